@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewAnalyzeCommand() *cobra.Command {
+	var namespaceFlag string
+	var allNamespacesFlag bool
+	var labelSelector string
+	var outputFormat string
+	var requiredLabelsPath string
+	var strict bool
+	var includePolicyKinds []string
+	var excludePolicyKinds []string
+
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Report configuration issues found in Gateways, HTTPRoutes and Backends",
+		Long: `Report configuration issues found in Gateways, HTTPRoutes and Backends.
+
+The command exits with code 2 if any finding has the Error severity. With
+--strict, it exits with code 3 if any finding has the Warning severity and none
+has the Error severity.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runAnalyze(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, analyze resources from all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", `Output format. Must be one of (yaml, json, sarif)`)
+	cmd.Flags().StringVar(&requiredLabelsPath, "required-labels", "", "Path to a YAML file mapping kinds of resources (e.g. Gateway) to the keys of the labels which resources of the kind must have.")
+	cmd.Flags().BoolVar(&strict, "strict", false, "If present, exit with a non-zero code if there are findings with the Warning severity.")
+	cmd.Flags().StringSliceVar(&includePolicyKinds, "include-policy-kind", nil, "Only report findings about policies of these kinds (e.g. TimeoutPolicy.foo.com). Each kind must be a known policy CRD. Findings which are not about policies are always reported.")
+	cmd.Flags().StringSliceVar(&excludePolicyKinds, "exclude-policy-kind", nil, "Suppress findings about policies of these kinds (e.g. TimeoutPolicy.foo.com). Each kind must be a known policy CRD. Takes precedence over --include-policy-kind.")
+
+	return cmd
+}
+
+func runAnalyze(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"selector\": %v\n", err)
+		os.Exit(1)
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"output\": %v\n", err)
+		os.Exit(1)
+	}
+	outputFormat, err := utils.ValidateAndReturnOutputFormat(output, utils.OutputFormatSARIF)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	strict, err := cmd.Flags().GetBool("strict")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"strict\": %v\n", err)
+		os.Exit(1)
+	}
+
+	var policyKindFilter analyzer.PolicyKindFilter
+	includePolicyKinds, err := cmd.Flags().GetStringSlice("include-policy-kind")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"include-policy-kind\": %v\n", err)
+		os.Exit(1)
+	}
+	for _, kind := range includePolicyKinds {
+		policyKindFilter.Include = append(policyKindFilter.Include, policymanager.PolicyCrdID(kind))
+	}
+	excludePolicyKinds, err := cmd.Flags().GetStringSlice("exclude-policy-kind")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"exclude-policy-kind\": %v\n", err)
+		os.Exit(1)
+	}
+	for _, kind := range excludePolicyKinds {
+		policyKindFilter.Exclude = append(policyKindFilter.Exclude, policymanager.PolicyCrdID(kind))
+	}
+	var knownPolicyKinds []policymanager.PolicyCrdID
+	for _, policyCRD := range params.PolicyManager.GetCRDs() {
+		knownPolicyKinds = append(knownPolicyKinds, policyCRD.ID())
+	}
+	if err := policyKindFilter.Validate(knownPolicyKinds); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --include-policy-kind or --exclude-policy-kind: %v\n", err)
+		os.Exit(1)
+	}
+
+	requiredLabelsPath, err := cmd.Flags().GetString("required-labels")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"required-labels\": %v\n", err)
+		os.Exit(1)
+	}
+	if requiredLabelsPath != "" {
+		requiredLabels, err := analyzer.LoadRequiredLabels(requiredLabelsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load required labels: %v\n", err)
+			os.Exit(1)
+		}
+		analyzer.RegisterAnalyzer(analyzer.NewRequiredLabelsAnalyzer(requiredLabels))
+	}
+
+	if allNs {
+		ns = ""
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	filter := resourcediscovery.Filter{Namespace: ns, Labels: selector}
+	httpRoutesResourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover HTTPRoute resources: %v\n", err)
+		os.Exit(1)
+	}
+	backendsResourceModel, err := discoverer.DiscoverResourcesForBackend(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover backend resources: %v\n", err)
+		os.Exit(1)
+	}
+	// Gateways are discovered separately since those without HTTPRoutes are
+	// not reachable from the other resourceModels.
+	gatewaysResourceModel, err := discoverer.DiscoverResourcesForGateway(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover Gateway resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	// ReferenceGrants are discovered separately since those for Secrets, or for
+	// resources which are not referenced, are not reachable from the other
+	// resourceModels.
+	referenceGrantsResourceModel, err := discoverer.DiscoverResourcesForReferenceGrant(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover ReferenceGrant resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	findingsPrinter := &printer.FindingsPrinter{Writer: params.Out, Color: newColorizer(params)}
+	findings, suppressed := policyKindFilter.Apply(analyzer.Analyze(httpRoutesResourceModel, backendsResourceModel, gatewaysResourceModel, referenceGrantsResourceModel))
+	findingsPrinter.PrintFindings(findings, outputFormat)
+	if suppressed != 0 {
+		// The count goes to stderr such that structured output stays parsable.
+		fmt.Fprintf(os.Stderr, "%d findings about filtered policy kinds were suppressed\n", suppressed)
+	}
+	if exitCode := analyzer.ExitCodeForFindings(findings, strict); exitCode != utils.ExitCodeSuccess {
+		os.Exit(int(exitCode))
+	}
+}
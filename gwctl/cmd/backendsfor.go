@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewBackendsForCommand() *cobra.Command {
+	var hostFlag string
+
+	cmd := &cobra.Command{
+		Use:   "backends-for --host HOST",
+		Short: "Show which backends can serve requests for a host, grouped by Gateway",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runBackendsFor(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVar(&hostFlag, "host", "", "Host of the requests.")
+	_ = cmd.MarkFlagRequired("host")
+
+	return cmd
+}
+
+func runBackendsFor(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	host, err := cmd.Flags().GetString("host")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"host\": %v\n", err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(cmd.Context(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	requestsPrinter := &printer.RequestsPrinter{Writer: params.Out}
+	requestsPrinter.PrintHostBackends(host, resourceModel.BackendsForHost(host))
+}
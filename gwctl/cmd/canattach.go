@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewCanAttachCommand() *cobra.Command {
+	var gatewayFlag string
+	var fromFlag string
+
+	cmd := &cobra.Command{
+		Use:   "can-attach",
+		Short: "Show whether routes from a namespace may attach to a Gateway",
+		Long: `Show whether routes from a namespace may attach to a Gateway.
+
+Routes may attach if the allowedRoutes of at least one listener of the Gateway
+permit the namespace. The permitting listeners are listed. The command exits
+with a non-zero code if no listener permits the namespace.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runCanAttach(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVar(&gatewayFlag, "gateway", "", "Gateway to attach to, as NAMESPACE/NAME. The namespace defaults to \"default\".")
+	cmd.Flags().StringVar(&fromFlag, "from", "", "Namespace of the routes.")
+	_ = cmd.MarkFlagRequired("gateway")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func runCanAttach(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	gateway, err := cmd.Flags().GetString("gateway")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"gateway\": %v\n", err)
+		os.Exit(1)
+	}
+	from, err := cmd.Flags().GetString("from")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"from\": %v\n", err)
+		os.Exit(1)
+	}
+	ns, name, ok := strings.Cut(gateway, "/")
+	if !ok {
+		ns, name = "default", gateway
+	}
+
+	discoverer := newDiscoverer(params)
+	filter := resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+	gatewayNode, ok := resourceModel.Gateways[resourcediscovery.GatewayID(ns, name)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "failed to find Gateway %v/%v\n", ns, name)
+		os.Exit(1)
+	}
+
+	canAttach, listeners := gatewayNode.NamespaceCanAttach(from, resourceModel.NamespaceLabels)
+	if !canAttach {
+		fmt.Fprintf(params.Out, "Routes from namespace %v can not attach to Gateway %v/%v: no listener permits the namespace\n", from, ns, name)
+		os.Exit(1)
+	}
+	fmt.Fprintf(params.Out, "Routes from namespace %v can attach to Gateway %v/%v through listeners: %v\n", from, ns, name, strings.Join(listeners, ", "))
+}
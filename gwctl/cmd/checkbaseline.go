@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewCheckBaselineCommand() *cobra.Command {
+	var namespaceFlag string
+	var allNamespacesFlag bool
+	var labelSelector string
+	var baselinePath string
+	var outputFormat string
+	var warnOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "check-baseline --baseline FILE",
+		Short: "Report where effective policies deviate from a desired baseline",
+		Long: `Compare the effective policies of Gateways, HTTPRoutes and Backends with the
+desired effective policies declared in a baseline file, e.g.
+
+  - kind: Gateway
+    namespace: default
+    name: foo-gateway
+    effectivePolicies:
+      TimeoutPolicy.bar.com:
+        timeout: 30s
+
+Each deviating field is reported as a finding with the Warning severity. The
+command exits with code 3 if any effective policy deviates, unless --warn-only
+is set.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runCheckBaseline(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, check resources from all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter HTTPRoutes on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	cmd.Flags().StringVar(&baselinePath, "baseline", "", "Path to a YAML file declaring the desired effective policies of resources.")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", `Output format. Must be one of (yaml, json)`)
+	cmd.Flags().BoolVar(&warnOnly, "warn-only", false, "If present, report deviations from the baseline without exiting with a non-zero code.")
+	_ = cmd.MarkFlagRequired("baseline")
+
+	return cmd
+}
+
+func runCheckBaseline(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"selector\": %v\n", err)
+		os.Exit(1)
+	}
+	baselinePath, err := cmd.Flags().GetString("baseline")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"baseline\": %v\n", err)
+		os.Exit(1)
+	}
+	warnOnly, err := cmd.Flags().GetBool("warn-only")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"warn-only\": %v\n", err)
+		os.Exit(1)
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"output\": %v\n", err)
+		os.Exit(1)
+	}
+	outputFormat, err := utils.ValidateAndReturnOutputFormat(output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if allNs {
+		ns = ""
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+		os.Exit(1)
+	}
+
+	baseline, err := resourcediscovery.LoadBaseline(baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load baseline: %v\n", err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(cmd.Context(), resourcediscovery.Filter{Namespace: ns, Labels: selector})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	findings, err := analyzer.CheckBaseline(resourceModel, baseline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to check baseline: %v\n", err)
+		os.Exit(1)
+	}
+	findingsPrinter := &printer.FindingsPrinter{Writer: params.Out, Color: newColorizer(params)}
+	findingsPrinter.PrintFindings(findings, outputFormat)
+	if exitCode := analyzer.ExitCodeForFindings(findings, !warnOnly); exitCode != utils.ExitCodeSuccess {
+		os.Exit(int(exitCode))
+	}
+}
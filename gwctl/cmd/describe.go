@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -36,9 +37,15 @@ func NewDescribeCommand() *cobra.Command {
 	var namespaceFlag string
 	var allNamespacesFlag bool
 	var labelSelector string
+	var groupBy string
+	var tree bool
+	var verbose bool
+	var dedupBackends bool
+	var latency bool
+	var inheritedPolicies bool
 
 	cmd := &cobra.Command{
-		Use:   "describe {policies|httproutes|gateways|gatewayclasses|backends|namespace|policycrd} RESOURCE_NAME",
+		Use:   "describe {policies|httproutes|gateways|gatewayclasses|backends|namespace|policycrd|referencegrants} RESOURCE_NAME",
 		Short: "Show details of a specific resource or group of resources",
 		Args:  cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -49,6 +56,12 @@ func NewDescribeCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
 	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, list requested resources from all namespaces.")
 	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", `Organize the output into sections. Must be one of (namespace). Only supported for gateways.`)
+	cmd.Flags().BoolVar(&tree, "tree", false, "If present, print each resource as a tree of its listeners, attached routes and backends, annotated with the number of effective policies. Only supported for gateways.")
+	cmd.Flags().BoolVar(&dedupBackends, "dedup-backends", false, "If present, print backends shared by several routes of a gateway once in the tree, along with the routes using them, instead of under each route. Only supported with --tree.")
+	cmd.Flags().BoolVar(&latency, "latency", false, "If present, summarize the configuration affecting the latency of requests: the timeouts of each rule, and the timeout, retry and connection related fields of the effective policies of the backends. Only supported for httproutes.")
+	cmd.Flags().BoolVar(&inheritedPolicies, "policies", false, "If present, list the inheritable policies which flow into each namespace from the GatewayClasses and Gateways of its resources, grouped by the resource they are attached to. Only supported for namespaces.")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "If present, annotate each field of the spec with its description from the schema of the CRD. Only supported for policies.")
 
 	return cmd
 }
@@ -74,18 +87,86 @@ func runDescribe(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		os.Exit(1)
 	}
 
+	groupBy, err := cmd.Flags().GetString("group-by")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"group-by\": %v\n", err)
+		os.Exit(1)
+	}
+	if groupBy != "" && groupBy != "namespace" {
+		fmt.Fprintf(os.Stderr, "unsupported value %q for flag \"group-by\"; must be one of (namespace)\n", groupBy)
+		os.Exit(1)
+	}
+	if groupBy != "" && kind != "gateway" && kind != "gateways" {
+		fmt.Fprintf(os.Stderr, "flag \"group-by\" is only supported for gateways\n")
+		os.Exit(1)
+	}
+
+	tree, err := cmd.Flags().GetBool("tree")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"tree\": %v\n", err)
+		os.Exit(1)
+	}
+	if tree && kind != "gateway" && kind != "gateways" {
+		fmt.Fprintf(os.Stderr, "flag \"tree\" is only supported for gateways\n")
+		os.Exit(1)
+	}
+	if tree && groupBy != "" {
+		fmt.Fprintf(os.Stderr, "flags \"tree\" and \"group-by\" can not be used together\n")
+		os.Exit(1)
+	}
+	dedupBackends, err := cmd.Flags().GetBool("dedup-backends")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"dedup-backends\": %v\n", err)
+		os.Exit(1)
+	}
+	if dedupBackends && !tree {
+		fmt.Fprintf(os.Stderr, "flag \"dedup-backends\" is only supported with \"tree\"\n")
+		os.Exit(1)
+	}
+
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"verbose\": %v\n", err)
+		os.Exit(1)
+	}
+	if verbose && kind != "policy" && kind != "policies" {
+		fmt.Fprintf(os.Stderr, "flag \"verbose\" is only supported for policies\n")
+		os.Exit(1)
+	}
+
+	latency, err := cmd.Flags().GetBool("latency")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"latency\": %v\n", err)
+		os.Exit(1)
+	}
+	if latency && kind != "httproute" && kind != "httproutes" {
+		fmt.Fprintf(os.Stderr, "flag \"latency\" is only supported for httproutes\n")
+		os.Exit(1)
+	}
+
+	inheritedPolicies, err := cmd.Flags().GetBool("policies")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"policies\": %v\n", err)
+		os.Exit(1)
+	}
+	if inheritedPolicies && kind != "namespace" && kind != "namespaces" && kind != "ns" {
+		fmt.Fprintf(os.Stderr, "flag \"policies\" is only supported for namespaces\n")
+		os.Exit(1)
+	}
+
 	if allNs {
 		ns = metav1.NamespaceAll
 	}
 
-	discoverer := resourcediscovery.NewDiscoverer(params.K8sClients, params.PolicyManager)
+	discoverer := newDiscoverer(params)
 
-	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out, Clock: clock.RealClock{}}
-	httpRoutesPrinter := &printer.HTTPRoutesPrinter{Writer: params.Out, Clock: clock.RealClock{}}
-	gwPrinter := &printer.GatewaysPrinter{Writer: params.Out, Clock: clock.RealClock{}}
+	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out, Clock: clock.RealClock{}, Verbose: verbose}
+	httpRoutesPrinter := &printer.HTTPRoutesPrinter{Writer: params.Out, Clock: clock.RealClock{}, Latency: latency}
+	gwPrinter := &printer.GatewaysPrinter{Writer: params.Out, Clock: clock.RealClock{}, DedupBackends: dedupBackends}
 	gwcPrinter := &printer.GatewayClassesPrinter{Writer: params.Out, Clock: clock.RealClock{}}
 	backendsPrinter := &printer.BackendsPrinter{Writer: params.Out}
-	namespacesPrinter := &printer.NamespacesPrinter{Writer: params.Out, Clock: clock.RealClock{}}
+	namespacesPrinter := &printer.NamespacesPrinter{Writer: params.Out, Clock: clock.RealClock{}, Policies: inheritedPolicies}
+	grantsPrinter := &printer.GrantsPrinter{Writer: params.Out}
 
 	switch kind {
 	case "policy", "policies":
@@ -132,7 +213,13 @@ func runDescribe(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(filter)
+		discover := discoverer.DiscoverResourcesForHTTPRoute
+		if latency {
+			// The latency profile includes the effective policies of the
+			// backends, which are only discovered along with the Services.
+			discover = discoverer.DiscoverResourcesForRequests
+		}
+		resourceModel, err := discover(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover HTTPRoute resources: %v\n", err)
 			os.Exit(1)
@@ -152,12 +239,24 @@ func runDescribe(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err := discoverer.DiscoverResourcesForGateway(filter)
+		discover := discoverer.DiscoverResourcesForGateway
+		if tree {
+			// The tree also includes the Backends of the attached routes.
+			discover = discoverer.DiscoverResourcesForTopology
+		}
+		resourceModel, err := discover(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover Gateway resources: %v\n", err)
 			os.Exit(1)
 		}
-		gwPrinter.PrintDescribeView(resourceModel)
+		switch {
+		case tree:
+			gwPrinter.PrintDescribeTree(resourceModel)
+		case groupBy == "namespace":
+			gwPrinter.PrintDescribeViewGroupedByNamespace(resourceModel)
+		default:
+			gwPrinter.PrintDescribeView(resourceModel)
+		}
 
 	case "gatewayclass", "gatewayclasses":
 		selector, err := labels.Parse(labelSelector)
@@ -171,7 +270,7 @@ func runDescribe(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(filter)
+		resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover GatewayClass resources: %v\n", err)
 			os.Exit(1)
@@ -191,13 +290,37 @@ func runDescribe(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err := discoverer.DiscoverResourcesForBackend(filter)
+		resourceModel, err := discoverer.DiscoverResourcesForBackend(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover resources related to Backend: %v\n", err)
 			os.Exit(1)
 		}
 		backendsPrinter.PrintDescribeView(resourceModel)
 
+	case "referencegrant", "referencegrants":
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+			os.Exit(1)
+		}
+		filter := resourcediscovery.Filter{
+			Namespace: ns,
+			Labels:    selector,
+		}
+		if len(args) > 1 {
+			// The ReferenceGrant can also be given as NAMESPACE/NAME.
+			filter.Name = args[1]
+			if namespace, name, ok := strings.Cut(args[1], "/"); ok {
+				filter.Namespace, filter.Name = namespace, name
+			}
+		}
+		resourceModel, err := discoverer.DiscoverResourcesForReferenceGrant(cmd.Context(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to discover ReferenceGrant resources: %v\n", err)
+			os.Exit(1)
+		}
+		grantsPrinter.PrintDescribeView(resourceModel)
+
 	case "namespace", "namespaces", "ns":
 		selector, err := labels.Parse(labelSelector)
 		if err != nil {
@@ -211,7 +334,7 @@ func runDescribe(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 			filter.Name = args[1]
 		}
 
-		resourceModel, err := discoverer.DiscoverResourcesForNamespace(filter)
+		resourceModel, err := discoverer.DiscoverResourcesForNamespaceContents(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover Namespace resources: %v\n", err)
 			os.Exit(1)
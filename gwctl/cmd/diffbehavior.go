@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewDiffBehaviorCommand() *cobra.Command {
+	var namespaceFlag string
+	var allNamespacesFlag bool
+	var labelSelector string
+	var rules []string
+
+	var knownRules []string
+	for _, rule := range policymanager.KnownBehaviorRules() {
+		knownRules = append(knownRules, fmt.Sprintf("%v (%v)", rule, rule.Description()))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff-behavior --rule RULE",
+		Short: "Show which effective policies would change under a different merging behavior",
+		Long:  "Calculates the effective policies of Gateways, HTTPRoutes and Backends a second time with the given behavior rules enabled, e.g. to validate an upgrade of the Gateway API or of a policy, and reports the effective policies which would change.\n\nKnown rules:\n  " + strings.Join(knownRules, "\n  "),
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runDiffBehavior(cmd, params)
+		},
+	}
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, compare the resources from all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter HTTPRoutes on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	cmd.Flags().StringSliceVar(&rules, "rule", nil, "Comma separated list of behavior rules to enable.")
+	_ = cmd.MarkFlagRequired("rule")
+
+	return cmd
+}
+
+func runDiffBehavior(cmd *cobra.Command, params *utils.CmdParams) {
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"selector\": %v\n", err)
+		os.Exit(1)
+	}
+
+	ruleNames, err := cmd.Flags().GetStringSlice("rule")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"rule\": %v\n", err)
+		os.Exit(1)
+	}
+	var rules []policymanager.BehaviorRule
+	for _, ruleName := range ruleNames {
+		rules = append(rules, policymanager.BehaviorRule(ruleName))
+	}
+
+	if allNs {
+		ns = ""
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	filter := resourcediscovery.Filter{Namespace: ns, Labels: selector}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	changes, err := resourceModel.DiffBehavior(rules...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to compare behaviors: %v\n", err)
+		os.Exit(1)
+	}
+
+	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out, Clock: clock.RealClock{}}
+	policiesPrinter.PrintBehaviorChanges(changes)
+}
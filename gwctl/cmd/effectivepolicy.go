@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewEffectivePolicyCommand() *cobra.Command {
+	var kindFlag string
+	var namespaceFlag string
+	var allFlag bool
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "effective-policy (--kind POLICY_KIND | -o rego-input) [--all]",
+		Short: "Show the effective policy of a kind on every resource it affects",
+		Long:  "Shows, in one table, the effective policy of a kind on every Gateway, HTTPRoute (and each of its named rules) and Backend it affects, which helps verifying that a kind of policy is applied consistently. POLICY_KIND is either the name of the policy CRD (e.g. healthcheckpolicies.foo.com) or its kind and group (e.g. HealthCheckPolicy.foo.com). With -o rego-input, the effective policies are printed as a JSON document for use as the input of OPA Rego policies, listing them under the top-level key effectivePolicies as entries of resource, gateway, rule, policyKind and spec. The kind is then optional, and all kinds are included if it is omitted.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runEffectivePolicy(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVar(&kindFlag, "kind", "", "Kind of the policy, either the name of the policy CRD or its kind and group. Optional with -o rego-input.")
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVar(&allFlag, "all", false, "If present, include the resources from all namespaces.")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", `Output format. Must be one of (rego-input)`)
+
+	return cmd
+}
+
+func runEffectivePolicy(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	kind, err := cmd.Flags().GetString("kind")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"kind\": %v\n", err)
+		os.Exit(1)
+	}
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all\": %v\n", err)
+		os.Exit(1)
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"output\": %v\n", err)
+		os.Exit(1)
+	}
+	outputFormat, err := utils.ValidateAndReturnOutputFormat(output, utils.OutputFormatRegoInput)
+	if err != nil || (outputFormat != utils.OutputFormatTable && outputFormat != utils.OutputFormatRegoInput) {
+		fmt.Fprintf(os.Stderr, "Unrecognized output format %q, must be one of (rego-input)\n", output)
+		os.Exit(1)
+	}
+	if all {
+		ns = ""
+	}
+
+	// Only the rego-input output covers all kinds of policies at once.
+	var policyCrdIDs []policymanager.PolicyCrdID
+	switch {
+	case kind != "":
+		policyCrdID, ok := findPolicyCrdID(params.PolicyManager, kind)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "failed to find policy CRD %q, or it is excluded by --policy-kind\n", kind)
+			os.Exit(1)
+		}
+		policyCrdIDs = append(policyCrdIDs, policyCrdID)
+	case outputFormat == utils.OutputFormatRegoInput:
+		for _, policyCrd := range params.PolicyManager.GetCRDs() {
+			policyCrdIDs = append(policyCrdIDs, policyCrd.ID())
+		}
+		slices.Sort(policyCrdIDs)
+	default:
+		fmt.Fprintf(os.Stderr, "required flag \"kind\" not set\n")
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(cmd.Context(), resourcediscovery.Filter{Namespace: ns, Labels: labels.Everything()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out}
+	if outputFormat == utils.OutputFormatRegoInput {
+		var effectivePolicies []resourcediscovery.ResourceEffectivePolicy
+		for _, policyCrdID := range policyCrdIDs {
+			effectivePolicies = append(effectivePolicies, resourceModel.EffectivePoliciesOfKind(policyCrdID)...)
+		}
+		policiesPrinter.PrintRegoInput(effectivePolicies)
+		return
+	}
+	policiesPrinter.PrintEffectivePoliciesOfKind(policyCrdIDs[0], resourceModel.EffectivePoliciesOfKind(policyCrdIDs[0]))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewExplainPolicyCommand() *cobra.Command {
+	var namespaceFlag string
+	var gatewayFlag string
+
+	cmd := &cobra.Command{
+		Use:   "explain-policy {gateway|httproute} RESOURCE_NAME",
+		Short: "Show what each hierarchy level contributes to the effective policies of a resource",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runExplainPolicy(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().StringVar(&gatewayFlag, "gateway", "", "Only explain the effective policies of the HTTPRoute in the context of this Gateway, specified as NAMESPACE/NAME. By default, all parent Gateways of the HTTPRoute are explained.")
+
+	return cmd
+}
+
+func runExplainPolicy(cmd *cobra.Command, args []string, params *utils.CmdParams) {
+	kind, name := args[0], args[1]
+
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+
+	gateway, err := cmd.Flags().GetString("gateway")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"gateway\": %v\n", err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out}
+	filter := resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()}
+
+	switch kind {
+	case "gateway", "gateways":
+		resourceModel, err := discoverer.DiscoverResourcesForGateway(cmd.Context(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to discover Gateway resources: %v\n", err)
+			os.Exit(1)
+		}
+		layers, err := resourceModel.PolicyLayersForGateway(resourcediscovery.GatewayID(ns, name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		policiesPrinter.PrintPolicyLayers(layers)
+
+	case "httproute", "httproutes":
+		resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(cmd.Context(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to discover HTTPRoute resources: %v\n", err)
+			os.Exit(1)
+		}
+		httpRouteNode, ok := resourceModel.HTTPRoutes[resourcediscovery.HTTPRouteID(ns, name)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "failed to find HTTPRoute %v/%v\n", ns, name)
+			os.Exit(1)
+		}
+
+		var gatewayNodes []*resourcediscovery.GatewayNode
+		for _, gatewayNode := range httpRouteNode.Gateways {
+			gatewayNN := fmt.Sprintf("%v/%v", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName())
+			if gateway == "" || gateway == gatewayNN {
+				gatewayNodes = append(gatewayNodes, gatewayNode)
+			}
+		}
+		if len(gatewayNodes) == 0 {
+			fmt.Fprintf(os.Stderr, "HTTPRoute %v/%v is not attached to any matching Gateway\n", ns, name)
+			os.Exit(1)
+		}
+		sort.Slice(gatewayNodes, func(i, j int) bool {
+			return gatewayNodes[i].Gateway.GetNamespace()+"/"+gatewayNodes[i].Gateway.GetName() <
+				gatewayNodes[j].Gateway.GetNamespace()+"/"+gatewayNodes[j].Gateway.GetName()
+		})
+
+		for i, gatewayNode := range gatewayNodes {
+			layers, err := resourceModel.PolicyLayersForHTTPRoute(httpRouteNode.ID(), gatewayNode.ID())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(params.Out, "### Gateway: %v/%v ###\n", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName())
+			policiesPrinter.PrintPolicyLayers(layers)
+			if i+1 != len(gatewayNodes) {
+				fmt.Fprintf(params.Out, "\n\n")
+			}
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unrecognized RESOURCE_TYPE\n")
+		os.Exit(1)
+	}
+}
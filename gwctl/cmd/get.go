@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -35,9 +36,10 @@ func NewGetCommand() *cobra.Command {
 	var allNamespacesFlag bool
 	var labelSelector string
 	var outputFormat string
+	var since time.Duration
 
 	cmd := &cobra.Command{
-		Use:   "get {namespaces|gateways|gatewayclasses|policies|policycrds|httproutes} RESOURCE_NAME",
+		Use:   "get {namespaces|gateways|gatewayclasses|policies|policycrds|policy-kinds|httproutes} RESOURCE_NAME",
 		Short: "Display one or many resources",
 		Args:  cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -48,7 +50,8 @@ func NewGetCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
 	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, list requested resources from all namespaces.")
 	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", `Output format. Must be one of (yaml, json)`)
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", `Output format. Must be one of (yaml, json, status, go-template=TEMPLATE). The status format is only supported for gateways and httproutes, and exits with a non-zero code if any resource is not OK. The go-template format renders the template with the data model documented by printer.TemplateData, e.g. -o go-template='{{range .Gateways}}{{.Name}} {{len .HTTPRoutes}}{{"\n"}}{{end}}'.`)
+	cmd.Flags().DurationVar(&since, "since", 0, "If present, only show resources created, or whose status conditions transitioned, within this duration (e.g. 1h). Not supported for policies, policycrds and policy-kinds.")
 
 	return cmd
 }
@@ -77,24 +80,31 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		fmt.Fprintf(os.Stderr, "failed to read flag \"output\": %v\n", err)
 		os.Exit(1)
 	}
-	outputFormat, err := utils.ValidateAndReturnOutputFormat(output)
+	outputFormat, err := utils.ValidateAndReturnOutputFormat(output, utils.OutputFormatStatus, utils.OutputFormatGoTemplate)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 
+	since, err := cmd.Flags().GetDuration("since")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"since\": %v\n", err)
+		os.Exit(1)
+	}
+
 	if allNs {
 		ns = ""
 	}
 
-	discoverer := resourcediscovery.NewDiscoverer(params.K8sClients, params.PolicyManager)
+	discoverer := newDiscoverer(params)
 	realClock := clock.RealClock{}
+	color := newColorizer(params)
 
 	nsPrinter := &printer.NamespacesPrinter{Writer: params.Out, Clock: realClock}
-	gwPrinter := &printer.GatewaysPrinter{Writer: params.Out, Clock: realClock}
+	gwPrinter := &printer.GatewaysPrinter{Writer: params.Out, Clock: realClock, Color: color}
 	gwcPrinter := &printer.GatewayClassesPrinter{Writer: params.Out, Clock: realClock}
-	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out, Clock: realClock}
-	httpRoutesPrinter := &printer.HTTPRoutesPrinter{Writer: params.Out, Clock: realClock}
+	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out, Clock: realClock, Color: color}
+	httpRoutesPrinter := &printer.HTTPRoutesPrinter{Writer: params.Out, Clock: realClock, Color: color}
 	backendsPrinter := &printer.BackendsPrinter{Writer: params.Out, Clock: realClock}
 
 	var resourceModel *resourcediscovery.ResourceModel
@@ -107,7 +117,7 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 			fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
 			os.Exit(1)
 		}
-		resourceModel, err = discoverer.DiscoverResourcesForNamespace(resourcediscovery.Filter{Labels: selector})
+		resourceModel, err = discoverer.DiscoverResourcesForNamespace(cmd.Context(), resourcediscovery.Filter{Labels: selector})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover Namespace resources: %v\n", err)
 			os.Exit(1)
@@ -124,7 +134,7 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err = discoverer.DiscoverResourcesForGateway(filter)
+		resourceModel, err = discoverer.DiscoverResourcesForGateway(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover Gateway resources: %v\n", err)
 			os.Exit(1)
@@ -141,7 +151,7 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err = discoverer.DiscoverResourcesForGatewayClass(filter)
+		resourceModel, err = discoverer.DiscoverResourcesForGatewayClass(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover GatewayClass resources: %v\n", err)
 			os.Exit(1)
@@ -158,6 +168,10 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		policiesPrinter.PrintCRDs(list, outputFormat)
 		return
 
+	case "policy-kind", "policy-kinds", "policykind", "policykinds":
+		policiesPrinter.PrintPolicyKinds(params.PolicyManager.GetCRDs(), params.PolicyManager.GetPolicies(), outputFormat)
+		return
+
 	case "httproute", "httproutes":
 		selector, err := labels.Parse(labelSelector)
 		if err != nil {
@@ -168,7 +182,7 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err = discoverer.DiscoverResourcesForHTTPRoute(filter)
+		resourceModel, err = discoverer.DiscoverResourcesForHTTPRoute(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover HTTPRoute resources: %v\n", err)
 			os.Exit(1)
@@ -185,11 +199,18 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		if len(args) > 1 {
 			filter.Name = args[1]
 		}
-		resourceModel, err = discoverer.DiscoverResourcesForBackend(filter)
+		resourceModel, err = discoverer.DiscoverResourcesForBackend(cmd.Context(), filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to discover backend resources: %v\n", err)
 			os.Exit(1)
 		}
+		if since > 0 {
+			resourceModel.FilterChangedSince(realClock.Now().Add(-since))
+		}
+		if outputFormat == utils.OutputFormatGoTemplate {
+			printGoTemplate(params, resourceModel, output)
+			return
+		}
 		backendsPrinter.Print(resourceModel)
 		return
 
@@ -197,5 +218,27 @@ func runGet(cmd *cobra.Command, args []string, params *utils.CmdParams) {
 		fmt.Fprintf(os.Stderr, "Unrecognized RESOURCE_TYPE\n")
 		os.Exit(1)
 	}
+	if since > 0 {
+		resourceModel.FilterChangedSince(realClock.Now().Add(-since))
+	}
+	if outputFormat == utils.OutputFormatStatus {
+		if ok := printer.PrintStatus(printerImpl, resourceModel); !ok {
+			os.Exit(1)
+		}
+		return
+	}
+	if outputFormat == utils.OutputFormatGoTemplate {
+		printGoTemplate(params, resourceModel, output)
+		return
+	}
 	printer.Print(printerImpl, resourceModel, outputFormat)
 }
+
+// printGoTemplate renders the resourceModel with the template of the output
+// flag.
+func printGoTemplate(params *utils.CmdParams, resourceModel *resourcediscovery.ResourceModel, output string) {
+	if err := printer.PrintTemplate(params.Out, resourceModel, utils.TemplateFromOutputFormat(output)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewGraphCommand() *cobra.Command {
+	var namespaceFlag string
+	var allNamespacesFlag bool
+	var labelSelector string
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "graph {gateways|httproutes|backends} RESOURCE_NAME",
+		Short: "Render the resources and their relationships as a diagram",
+		Args:  cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runGraph(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, graph requested resources from all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "mermaid", `Output format. Must be one of (mermaid, jsonl)`)
+
+	return cmd
+}
+
+func runGraph(cmd *cobra.Command, args []string, params *utils.CmdParams) {
+	kind := args[0]
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"selector\": %v\n", err)
+		os.Exit(1)
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"output\": %v\n", err)
+		os.Exit(1)
+	}
+	if output != "mermaid" && output != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Unrecognized output format %q, must be one of (mermaid, jsonl)\n", output)
+		os.Exit(1)
+	}
+
+	if allNs {
+		ns = ""
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	filter := resourcediscovery.Filter{Namespace: ns, Labels: selector}
+	if len(args) > 1 {
+		filter.Name = args[1]
+	}
+
+	var resourceModel *resourcediscovery.ResourceModel
+	switch kind {
+	case "gateway", "gateways":
+		resourceModel, err = discoverer.DiscoverResourcesForGateway(cmd.Context(), filter)
+	case "httproute", "httproutes":
+		resourceModel, err = discoverer.DiscoverResourcesForHTTPRoute(cmd.Context(), filter)
+	case "backend", "backends":
+		resourceModel, err = discoverer.DiscoverResourcesForBackend(cmd.Context(), filter)
+	default:
+		fmt.Fprintf(os.Stderr, "Unrecognized RESOURCE_TYPE\n")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output == "jsonl" {
+		err = resourceModel.StreamJSONL(params.Out)
+	} else {
+		err = resourceModel.ToMermaid(params.Out)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write graph: %v\n", err)
+		os.Exit(1)
+	}
+}
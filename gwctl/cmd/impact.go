@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewImpactCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "impact gateway NAMESPACE/NAME",
+		Short: "Show which routes would be affected by deleting a resource",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runImpact(cmd, args, params)
+		},
+	}
+	return cmd
+}
+
+func runImpact(cmd *cobra.Command, args []string, params *utils.CmdParams) {
+	kind := args[0]
+
+	switch kind {
+	case "gateway", "gateways":
+		ns, name, ok := strings.Cut(args[1], "/")
+		if !ok {
+			ns, name = "default", args[1]
+		}
+
+		discoverer := newDiscoverer(params)
+		filter := resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()}
+		resourceModel, err := discoverer.DiscoverResourcesForGatewayImpact(cmd.Context(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to discover Gateway resources: %v\n", err)
+			os.Exit(1)
+		}
+		gatewayNode, ok := resourceModel.Gateways[resourcediscovery.GatewayID(ns, name)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "failed to find Gateway %v/%v\n", ns, name)
+			os.Exit(1)
+		}
+
+		gwPrinter := &printer.GatewaysPrinter{Writer: params.Out}
+		gwPrinter.PrintImpact(gatewayNode)
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unrecognized RESOURCE_TYPE\n")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewMatchTestCommand() *cobra.Command {
+	var gatewayFlag string
+	var requestsFlag string
+	var headerFlags []string
+	var queryFlags []string
+
+	cmd := &cobra.Command{
+		Use:   "match-test --gateway NAMESPACE/NAME --requests FILE [--header HEADER]... [--query PARAM]...",
+		Short: "Show which route each of a list of sample requests would match through a Gateway",
+		Long: `Show which route each of a list of sample requests would match through a Gateway.
+
+The requests file holds a YAML list of requests with a host, and optionally a
+path (defaults to /) and a method (defaults to GET):
+
+  - host: api.foo.com
+    path: /v1/users
+  - host: api.foo.com
+    path: /upload
+    method: POST
+
+The headers and query parameters given with --header and --query are sent
+with every request.
+
+The command exits with code 2 if any request matches no route.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runMatchTest(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVar(&gatewayFlag, "gateway", "", "Gateway routing the requests, as NAMESPACE/NAME. The namespace defaults to \"default\".")
+	cmd.Flags().StringVar(&requestsFlag, "requests", "", "Path to a YAML file listing the requests.")
+	cmd.Flags().StringArrayVarP(&headerFlags, "header", "H", nil, "Header sent with every request in the form \"Name: value\". May be repeated.")
+	cmd.Flags().StringArrayVar(&queryFlags, "query", nil, "Query parameter sent with every request in the form \"name=value\". May be repeated.")
+	_ = cmd.MarkFlagRequired("gateway")
+	_ = cmd.MarkFlagRequired("requests")
+
+	return cmd
+}
+
+func runMatchTest(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	gateway, err := cmd.Flags().GetString("gateway")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"gateway\": %v\n", err)
+		os.Exit(1)
+	}
+	requestsFile, err := cmd.Flags().GetString("requests")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"requests\": %v\n", err)
+		os.Exit(1)
+	}
+	headerFlags, err := cmd.Flags().GetStringArray("header")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"header\": %v\n", err)
+		os.Exit(1)
+	}
+	queryFlags, err := cmd.Flags().GetStringArray("query")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"query\": %v\n", err)
+		os.Exit(1)
+	}
+	headers, err := parseHeaderFlags(headerFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	queryParams, err := parseQueryFlags(queryFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	ns, name, ok := strings.Cut(gateway, "/")
+	if !ok {
+		ns, name = "default", gateway
+	}
+
+	content, err := os.ReadFile(requestsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read requests: %v\n", err)
+		os.Exit(1)
+	}
+	var requests []resourcediscovery.TestRequest
+	if err := yaml.UnmarshalStrict(content, &requests); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse requests from %v: %v\n", requestsFile, err)
+		os.Exit(1)
+	}
+	for i, request := range requests {
+		if request.Host == "" {
+			fmt.Fprintf(os.Stderr, "request %d in %v has no host\n", i, requestsFile)
+			os.Exit(1)
+		}
+	}
+
+	// Only the selected Gateway is discovered, such that requests are routed
+	// through its listeners alone.
+	discoverer := newDiscoverer(params)
+	filter := resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(cmd.Context(), filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+	if _, ok := resourceModel.Gateways[resourcediscovery.GatewayID(ns, name)]; !ok {
+		fmt.Fprintf(os.Stderr, "failed to find Gateway %v/%v\n", ns, name)
+		os.Exit(1)
+	}
+
+	matches := resourceModel.MatchRequests(requests, headers, queryParams)
+	requestsPrinter := &printer.RequestsPrinter{Writer: params.Out}
+	requestsPrinter.PrintRequestMatches(matches)
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: name, Namespace: ns}
+	if exitCode := analyzer.ExitCodeForFindings(analyzer.RequestMatchFindings(gatewayRef, matches), false); exitCode != utils.ExitCodeSuccess {
+		os.Exit(int(exitCode))
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewPolicyTreeCommand() *cobra.Command {
+	var namespaceFlag string
+	var allNamespacesFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "policy-tree POLICY_KIND [{gatewayclass|gateway|httproute} RESOURCE_NAME]",
+		Short: "Show how policies of a kind are inherited and overridden through the hierarchy",
+		Long:  "Shows the inheritance tree of a policy kind, from GatewayClasses through Gateways to HTTPRoutes, along with the fields each level adds, overrides or removes. POLICY_KIND is either the name of the policy CRD (e.g. healthcheckpolicies.foo.com) or its kind and group (e.g. HealthCheckPolicy.foo.com). When a resource is given, only the branches of the tree leading through it are shown.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 && len(args) != 3 {
+				return fmt.Errorf("accepts 1 or 3 args, received %d", len(args))
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runPolicyTree(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, include Gateways and HTTPRoutes from all namespaces.")
+
+	return cmd
+}
+
+func runPolicyTree(cmd *cobra.Command, args []string, params *utils.CmdParams) {
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+	if allNs {
+		ns = ""
+	}
+
+	policyCrdID, ok := findPolicyCrdID(params.PolicyManager, args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "failed to find policy CRD %q\n", args[0])
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	var resourceModel *resourcediscovery.ResourceModel
+	var selectedGatewayClass string
+	if len(args) == 1 {
+		resourceModel, err = discoverer.DiscoverResourcesForGateway(cmd.Context(), resourcediscovery.Filter{Namespace: ns, Labels: labels.Everything()})
+	} else {
+		kind, name := args[1], args[2]
+		switch kind {
+		case "gatewayclass", "gatewayclasses":
+			// The tree of a GatewayClass spans the Gateways from all namespaces.
+			selectedGatewayClass = name
+			resourceModel, err = discoverer.DiscoverResourcesForGateway(cmd.Context(), resourcediscovery.Filter{Labels: labels.Everything()})
+		case "gateway", "gateways":
+			resourceModel, err = discoverer.DiscoverResourcesForGateway(cmd.Context(), resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()})
+		case "httproute", "httproutes":
+			resourceModel, err = discoverer.DiscoverResourcesForHTTPRoute(cmd.Context(), resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()})
+		default:
+			fmt.Fprintf(os.Stderr, "Unrecognized RESOURCE_TYPE\n")
+			os.Exit(1)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	roots, err := resourceModel.PolicyTree(policyCrdID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build policy tree: %v\n", err)
+		os.Exit(1)
+	}
+	if selectedGatewayClass != "" {
+		var selected []*resourcediscovery.PolicyTreeNode
+		for _, root := range roots {
+			if root.Resource.Kind == "GatewayClass" && root.Resource.Name == selectedGatewayClass {
+				selected = append(selected, root)
+			}
+		}
+		roots = selected
+	}
+
+	policiesPrinter := &printer.PoliciesPrinter{Writer: params.Out}
+	policiesPrinter.PrintPolicyTree(policyCrdID, roots)
+}
+
+// findPolicyCrdID returns the ID of the policy CRD which is either named name,
+// or whose ID is name.
+func findPolicyCrdID(policyManager *policymanager.PolicyManager, name string) (policymanager.PolicyCrdID, bool) {
+	if policyCrd, ok := policyManager.GetCRD(name); ok {
+		return policyCrd.ID(), true
+	}
+	for _, policyCrd := range policyManager.GetCRDs() {
+		if string(policyCrd.ID()) == name {
+			return policyCrd.ID(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewResolveCommand() *cobra.Command {
+	var hostFlag string
+	var pathFlag string
+	var methodFlag string
+	var headerFlags []string
+
+	cmd := &cobra.Command{
+		Use:   "resolve --host HOST [--path PATH] [--method METHOD] [--header HEADER]...",
+		Short: "Show how a request would be routed and which policies apply to it",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runResolve(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVar(&hostFlag, "host", "", "Host of the request.")
+	cmd.Flags().StringVar(&pathFlag, "path", "/", "Path of the request, optionally followed by a query string (e.g. /api?version=beta).")
+	cmd.Flags().StringVar(&methodFlag, "method", "GET", "Method of the request.")
+	cmd.Flags().StringArrayVarP(&headerFlags, "header", "H", nil, "Header of the request in the form \"Name: value\". May be repeated.")
+	_ = cmd.MarkFlagRequired("host")
+
+	return cmd
+}
+
+func runResolve(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	host, err := cmd.Flags().GetString("host")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"host\": %v\n", err)
+		os.Exit(1)
+	}
+	path, err := cmd.Flags().GetString("path")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"path\": %v\n", err)
+		os.Exit(1)
+	}
+	method, err := cmd.Flags().GetString("method")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"method\": %v\n", err)
+		os.Exit(1)
+	}
+	headerFlags, err := cmd.Flags().GetStringArray("header")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"header\": %v\n", err)
+		os.Exit(1)
+	}
+
+	headers, err := parseHeaderFlags(headerFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	path, rawQuery, _ := strings.Cut(path, "?")
+	queryParams, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid query string %q: %v\n", rawQuery, err)
+		os.Exit(1)
+	}
+
+	discoverer := newDiscoverer(params)
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(cmd.Context(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	trace, err := resourceModel.TraceRequest(host, path, method, headers, queryParams)
+	requestsPrinter := &printer.RequestsPrinter{Writer: params.Out}
+	requestsPrinter.PrintTrace(trace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseHeaderFlags parses the values of a --header flag, each in the form
+// "Name: value".
+func parseHeaderFlags(headerFlags []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, headerFlag := range headerFlags {
+		name, value, ok := strings.Cut(headerFlag, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q; must be in the form \"Name: value\"", headerFlag)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// parseQueryFlags parses the values of a --query flag, each in the form
+// "name=value".
+func parseQueryFlags(queryFlags []string) (url.Values, error) {
+	queryParams := make(url.Values)
+	for _, queryFlag := range queryFlags {
+		name, value, ok := strings.Cut(queryFlag, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid query parameter %q; must be in the form \"name=value\"", queryFlag)
+		}
+		queryParams.Add(name, value)
+	}
+	return queryParams, nil
+}
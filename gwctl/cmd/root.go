@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path"
 
 	"github.com/spf13/cobra"
@@ -28,10 +29,25 @@ import (
 
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
 	cmdutils "sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
-var kubeConfigPath string
+var (
+	kubeConfigPath         string
+	showProgress           bool
+	targetSelectorPolicies []string
+	excludeNamespaces      []string
+	validateMergedPolicies bool
+	noColor                bool
+	redactPatterns         []string
+	requireParentGrants    bool
+	skipForbidden          bool
+	skipDeletingPolicies   bool
+	policyKinds            []string
+	policyRulesPath        string
+)
 
 func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -41,6 +57,17 @@ func newRootCmd() *cobra.Command {
 	}
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&kubeConfigPath, "kubeconfig", "", "path to kubeconfig file (default is the KUBECONFIG environment variable and if it isn't set, falls back to $HOME/.kube/config)")
+	rootCmd.PersistentFlags().StringSliceVar(&targetSelectorPolicies, "target-selector-policies", nil, "Comma separated list of policy kinds (e.g. TimeoutPolicy.bar.com) which attach to resources through spec.targetSelector, instead of spec.targetRef.")
+	rootCmd.PersistentFlags().StringVar(&policyRulesPath, "policy-rules", "", "Path to a YAML file customizing, per policy kind (e.g. TimeoutPolicy.bar.com), how policies are inherited and merged: fields of spec.default treated as overrides (overrideFields) and vice versa (defaultFields), the tiebreakers ordering conflicting policies (tiebreakers), whether policies are inherited across namespaces (crossNamespace), and the behavior rules enabled for the kind (behaviors).")
+	rootCmd.PersistentFlags().BoolVar(&validateMergedPolicies, "validate-merged-policies", false, "If present, validate effective policies, which result from merging policies from multiple levels of the hierarchy, against the schema of their CRD. Violations are reported by the analyze command.")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "If present, never color the output. Output is only colored when writing to a terminal, and the NO_COLOR environment variable is also honored.")
+	rootCmd.PersistentFlags().BoolVar(&showProgress, "progress", false, "If present, report progress to stderr while fetching resources.")
+	rootCmd.PersistentFlags().StringSliceVar(&redactPatterns, "redact", cmdutils.DefaultRedactionPatterns, "Comma separated list of JSON path patterns (e.g. spec.auth.token or **.*secret) whose values are replaced with "+cmdutils.RedactedValue+" in the json, yaml and describe output. A * segment matches any single field and a ** segment matches any number of fields. Set to an empty string to disable redaction.")
+	rootCmd.PersistentFlags().BoolVar(&requireParentGrants, "require-parent-reference-grants", false, "If present, HTTPRoutes only attach to Gateways in other namespaces when a ReferenceGrant in the namespace of the Gateway permits it. Attachments which are not permitted are reported as errors of the HTTPRoute.")
+	rootCmd.PersistentFlags().BoolVar(&skipForbidden, "skip-forbidden", false, "If present, kinds of resources (including kinds of policies) which can not be fetched due to missing permissions are skipped with a warning, instead of failing. The output is then based on a partial view of the cluster.")
+	rootCmd.PersistentFlags().BoolVar(&skipDeletingPolicies, "skip-deleting-policies", false, "If present, policies which are being deleted (which have a deletionTimestamp but still exist due to finalizers) do not contribute to effective policies, showing the effective policies once their deletion completes.")
+	rootCmd.PersistentFlags().StringSliceVar(&policyKinds, "policy-kind", nil, "Comma separated list of policy kinds (e.g. TimeoutPolicy.bar.com) to include. Only policies of these kinds are fetched, shown and merged into effective policies by every command, while other kinds are skipped. Each kind must be a known policy CRD. Analyze flags like --include-policy-kind further narrow the findings within these kinds. By default, all kinds are included.")
+	rootCmd.PersistentFlags().StringSliceVar(&excludeNamespaces, "exclude-namespace", resourcediscovery.DefaultNamespaceIgnoreList, "Comma separated list of namespace patterns (e.g. kube-*) whose resources are ignored when listing across all namespaces. Resources in these namespaces are still shown when referenced by other resources. Set to an empty string to include all namespaces.")
 
 	// initialize logging flags in a new flag set
 	// otherwise it conflicts with cobra's flags
@@ -51,13 +78,35 @@ func newRootCmd() *cobra.Command {
 
 	rootCmd.AddCommand(NewGetCommand())
 	rootCmd.AddCommand(NewDescribeCommand())
+	rootCmd.AddCommand(NewAnalyzeCommand())
+	rootCmd.AddCommand(NewExplainPolicyCommand())
+	rootCmd.AddCommand(NewImpactCommand())
+	rootCmd.AddCommand(NewGraphCommand())
+	rootCmd.AddCommand(NewResolveCommand())
+	rootCmd.AddCommand(NewBackendsForCommand())
+	rootCmd.AddCommand(NewMatchTestCommand())
+	rootCmd.AddCommand(NewDiffBehaviorCommand())
+	rootCmd.AddCommand(NewPolicyTreeCommand())
+	rootCmd.AddCommand(NewTUICommand())
+	rootCmd.AddCommand(NewVerifyGrantsCommand())
+	rootCmd.AddCommand(NewServeMetricsCommand())
+	rootCmd.AddCommand(NewCheckBaselineCommand())
+	rootCmd.AddCommand(NewEffectivePolicyCommand())
+	rootCmd.AddCommand(NewCanAttachCommand())
+	rootCmd.AddCommand(NewWatchCommand())
+	rootCmd.AddCommand(NewTraceCommand())
 
 	return rootCmd
 }
 
 func Execute() {
+	// Cancel the context on Ctrl-C so that long running discoveries stop
+	// promptly.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	rootCmd := newRootCmd()
-	err := rootCmd.Execute()
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to execute command: %v\n", err)
 		os.Exit(1)
@@ -73,6 +122,34 @@ func initConfig() {
 	}
 }
 
+// newDiscoverer returns a Discoverer which ignores the excluded namespaces,
+// warns about skipped kinds and optionally reports progress to stderr.
+func newDiscoverer(params *cmdutils.CmdParams) resourcediscovery.Discoverer {
+	discoverer := resourcediscovery.NewDiscoverer(params.K8sClients, params.PolicyManager)
+	discoverer.IgnoredNamespaces = excludeNamespaces
+	discoverer.RequireParentReferenceGrants = requireParentGrants
+	discoverer.SkipForbidden = skipForbidden
+	discoverer.SkipDeletingPolicies = skipDeletingPolicies
+	discoverer.Warn = func(kind string, err error) {
+		fmt.Fprintf(os.Stderr, "warning: skipped %v, the output may be incomplete: %v\n", kind, err)
+	}
+	if showProgress {
+		discoverer.Progress = func(kind string, fetched, total int) {
+			if total < 0 {
+				fmt.Fprintf(os.Stderr, "fetched %d %v\n", fetched, kind)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "fetched %d/%d %v\n", fetched, total, kind)
+		}
+	}
+	return discoverer
+}
+
+// newColorizer returns the Colorizer for output written to params.Out.
+func newColorizer(params *cmdutils.CmdParams) printer.Colorizer {
+	return printer.NewColorizer(params.Out, noColor)
+}
+
 func getParams(path string) *cmdutils.CmdParams {
 	k8sClients, err := common.NewK8sClients(path)
 	if err != nil {
@@ -80,7 +157,36 @@ func getParams(path string) *cmdutils.CmdParams {
 		os.Exit(1)
 	}
 
+	if err := cmdutils.SetRedactionPatterns(redactPatterns); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure redaction: %v\n", err)
+		os.Exit(1)
+	}
+
 	policyManager := policymanager.New(k8sClients.DC)
+	for _, policyCrdID := range targetSelectorPolicies {
+		policyManager.EnableTargetSelector(policymanager.PolicyCrdID(policyCrdID))
+	}
+	if policyRulesPath != "" {
+		policyRules, err := policymanager.LoadPolicyRules(policyRulesPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load policy rules: %v\n", err)
+			os.Exit(1)
+		}
+		policyManager.SetPolicyRules(policyRules)
+	}
+	if len(policyKinds) > 0 {
+		var policyCrdIDs []policymanager.PolicyCrdID
+		for _, policyCrdID := range policyKinds {
+			policyCrdIDs = append(policyCrdIDs, policymanager.PolicyCrdID(policyCrdID))
+		}
+		policyManager.SetPolicyKinds(policyCrdIDs...)
+	}
+	if validateMergedPolicies {
+		policyManager.EnableMergedPolicyValidation()
+	}
+	if skipForbidden {
+		policyManager.EnableSkipForbidden()
+	}
 	if err := policyManager.Init(context.Background()); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to initialize policy manager: %v\n", err)
 		os.Exit(1)
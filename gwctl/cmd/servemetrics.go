@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+const (
+	// metricsReadHeaderTimeout and metricsReadTimeout bound the time a client
+	// may take to send a request, so that slow clients can't hold connections
+	// open indefinitely. No write timeout is set since collecting the metrics
+	// requires discovering the resources, which may take a while in large
+	// clusters.
+	metricsReadHeaderTimeout = 10 * time.Second
+	metricsReadTimeout       = 30 * time.Second
+)
+
+func NewServeMetricsCommand() *cobra.Command {
+	var addressFlag string
+	var namespaceFlag string
+	var allNamespacesFlag bool
+	var labelSelector string
+
+	cmd := &cobra.Command{
+		Use:   "serve-metrics",
+		Short: "Serve metrics about Gateways, HTTPRoutes and the findings of analyze in the Prometheus format",
+		Long: `Serve metrics about Gateways, HTTPRoutes and the findings of analyze in the Prometheus format.
+
+The resources are discovered anew on each request to /metrics. The following
+gauges are served:
+
+  gwctl_gateways_total
+  gwctl_httproutes_total
+  gwctl_orphaned_routes_total
+  gwctl_cross_namespace_refs_total{permitted="true|false"}
+  gwctl_findings_total{severity="error|warning|info"}`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runServeMetrics(cmd, args, params)
+		},
+	}
+	cmd.Flags().StringVar(&addressFlag, "address", ":9090", "Address to serve the metrics on.")
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, collect metrics about resources from all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+
+	return cmd
+}
+
+func runServeMetrics(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	address, err := cmd.Flags().GetString("address")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"address\": %v\n", err)
+		os.Exit(1)
+	}
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"selector\": %v\n", err)
+		os.Exit(1)
+	}
+	if allNs {
+		ns = ""
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+		os.Exit(1)
+	}
+	filter := resourcediscovery.Filter{Namespace: ns, Labels: selector}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		metrics, err := collectMetrics(r.Context(), params, filter)
+		if err != nil {
+			klog.V(0).ErrorS(err, "Failed to collect metrics")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		metricsPrinter := &printer.MetricsPrinter{Writer: w}
+		metricsPrinter.PrintMetrics(metrics)
+	})
+
+	fmt.Fprintf(params.Out, "Serving metrics on %v/metrics\n", address)
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: metricsReadHeaderTimeout,
+		ReadTimeout:       metricsReadTimeout,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to serve metrics: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// collectMetrics discovers the resources matching the filter and returns the
+// metrics about them, including the findings of the analyzers.
+func collectMetrics(ctx context.Context, params *utils.CmdParams, filter resourcediscovery.Filter) ([]resourcediscovery.Metric, error) {
+	discoverer := newDiscoverer(params)
+	// HTTPRoutes are discovered along with their Gateways and backends, which
+	// includes HTTPRoutes not attached to any Gateway.
+	httpRoutesResourceModel, err := discoverer.DiscoverResourcesForRequests(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover HTTPRoute resources: %w", err)
+	}
+	// Gateways are discovered separately since those without HTTPRoutes are
+	// not reachable from the HTTPRoutes.
+	gatewaysResourceModel, err := discoverer.DiscoverResourcesForGateway(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover Gateway resources: %w", err)
+	}
+
+	metrics := resourcediscovery.CollectMetrics(httpRoutesResourceModel, gatewaysResourceModel)
+	return append(metrics, analyzer.FindingMetrics(analyzer.Analyze(httpRoutesResourceModel, gatewaysResourceModel))...), nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewTraceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace httproute NAMESPACE/NAME",
+		Short: "Show the complete path from the GatewayClass down to the backends of a route",
+		Long:  "Shows the GatewayClass, the Gateway and the listeners the route attaches to, the matches and filters of the route and its backends, along with the policies attached to and the effective policies of each of them. A route attached to multiple Gateways is traced once per Gateway.",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runTrace(cmd, args, params)
+		},
+	}
+	return cmd
+}
+
+func runTrace(cmd *cobra.Command, args []string, params *utils.CmdParams) {
+	kind := args[0]
+
+	switch kind {
+	case "httproute", "httproutes":
+		ns, name, ok := strings.Cut(args[1], "/")
+		if !ok {
+			ns, name = "default", args[1]
+		}
+
+		discoverer := newDiscoverer(params)
+		filter := resourcediscovery.Filter{Namespace: ns, Name: name, Labels: labels.Everything()}
+		resourceModel, err := discoverer.DiscoverResourcesForRequests(cmd.Context(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to discover HTTPRoute resources: %v\n", err)
+			os.Exit(1)
+		}
+		traces, err := resourceModel.RouteTraces(resourcediscovery.HTTPRouteID(ns, name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to trace HTTPRoute: %v\n", err)
+			os.Exit(1)
+		}
+		if len(traces) == 0 {
+			fmt.Fprintf(os.Stderr, "HTTPRoute %v/%v is not attached to any Gateway\n", ns, name)
+			os.Exit(1)
+		}
+
+		httpRoutesPrinter := &printer.HTTPRoutesPrinter{Writer: params.Out}
+		httpRoutesPrinter.PrintTraces(traces)
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unrecognized RESOURCE_TYPE\n")
+		os.Exit(1)
+	}
+}
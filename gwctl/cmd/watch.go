@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/printer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func NewWatchCommand() *cobra.Command {
+	var intervalFlag time.Duration
+	var namespaceFlag string
+	var allNamespacesFlag bool
+	var labelSelector string
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print the tree of Gateways, their HTTPRoutes and backends, and print it again whenever it changes",
+		Long: `Print the tree of Gateways, their HTTPRoutes and backends, and print it again whenever it changes.
+
+The resources are discovered anew at each interval, so only the list verb is
+required on them, not the watch verb. The tree is only printed again if the
+content of the resources changed, as determined by hashing their labels,
+annotations and spec, the relations between them and the effective policies.
+Changes to the status of resources alone do not cause the tree to be printed.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			params := getParams(kubeConfigPath)
+			runWatch(cmd, args, params)
+		},
+	}
+	cmd.Flags().DurationVar(&intervalFlag, "interval", 30*time.Second, "Interval between discoveries of the resources.")
+	cmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "default", "")
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false, "If present, watch Gateways from all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter Gateways on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+
+	return cmd
+}
+
+func runWatch(cmd *cobra.Command, _ []string, params *utils.CmdParams) {
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"interval\": %v\n", err)
+		os.Exit(1)
+	}
+	if interval <= 0 {
+		fmt.Fprintf(os.Stderr, "--interval must be positive, got %v\n", interval)
+		os.Exit(1)
+	}
+	ns, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"namespace\": %v\n", err)
+		os.Exit(1)
+	}
+	allNs, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"all-namespaces\": %v\n", err)
+		os.Exit(1)
+	}
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read flag \"selector\": %v\n", err)
+		os.Exit(1)
+	}
+	if allNs {
+		ns = ""
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse label selector %q: %v\n", labelSelector, err)
+		os.Exit(1)
+	}
+	filter := resourcediscovery.Filter{Namespace: ns, Labels: selector}
+
+	discoverer := newDiscoverer(params)
+	poller := &resourcediscovery.Poller{
+		Interval: interval,
+		Discover: func(ctx context.Context) (*resourcediscovery.ResourceModel, error) {
+			return discoverer.DiscoverResourcesForTopology(ctx, filter)
+		},
+	}
+	gwPrinter := &printer.GatewaysPrinter{Writer: params.Out, Clock: clock.RealClock{}}
+
+	// Run only returns once the context is cancelled on Ctrl-C.
+	_ = poller.Run(cmd.Context(), func(resourceModel *resourcediscovery.ResourceModel) {
+		fmt.Fprintf(params.Out, "--- %v ---\n", time.Now().Format(time.RFC3339))
+		gwPrinter.PrintDescribeTree(resourceModel)
+		fmt.Fprintln(params.Out)
+	}, func(err error) {
+		fmt.Fprintf(os.Stderr, "failed to discover resources: %v\n", err)
+	})
+}
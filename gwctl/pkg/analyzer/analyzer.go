@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analyzer inspects a ResourceModel and reports Findings about
+// configurations which are likely to be invalid or to behave differently than
+// intended.
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+func init() {
+	RegisterAnalyzer(NewAnalyzer("gatewayclasses", analyzeGatewayClasses))
+	RegisterAnalyzer(NewAnalyzer("namespaces", analyzeNamespaces))
+	RegisterAnalyzer(NewAnalyzer("gateways", analyzeGateways))
+	RegisterAnalyzer(NewAnalyzer("httproutes", analyzeHTTPRoutes))
+	RegisterAnalyzer(NewAnalyzer("referencegrants", analyzeReferenceGrants))
+	RegisterAnalyzer(NewAnalyzer("policies", analyzePolicies))
+	RegisterAnalyzer(NewAnalyzer("backends", analyzeBackends))
+}
+
+// Analyze runs all registered Analyzers against the resourceModels and
+// returns the Findings sorted by resource. Identical Findings reported through
+// multiple resourceModels are only returned once. Findings for resources
+// within the namespaces ignored by a resourceModel are dropped.
+func Analyze(resourceModels ...*resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	seen := make(map[Finding]bool)
+	analyzers := Analyzers()
+	for _, resourceModel := range resourceModels {
+		for _, analyzer := range analyzers {
+			for _, finding := range analyzer.Analyze(resourceModel) {
+				if resourceModel.IgnoredNamespaces.Ignores(finding.ResourceRef.Namespace) {
+					continue
+				}
+				if !seen[finding] {
+					seen[finding] = true
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func analyzeGatewayClasses(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, gatewayClassNode := range resourceModel.GatewayClasses {
+		findings = append(findings, analyzeAPIVersion(gatewayClassNode.GatewayClass, gatewayClassNode.GatewayClass.TypeMeta)...)
+		findings = append(findings, analyzeGatewayClassParametersRef(gatewayClassNode)...)
+		findings = append(findings, analyzeDuplicatePolicies(common.ObjRef{
+			Kind: "GatewayClass",
+			Name: gatewayClassNode.GatewayClass.GetName(),
+		}, common.MapToValues(gatewayClassNode.Policies))...)
+	}
+	return findings
+}
+
+func analyzeNamespaces(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, namespaceNode := range resourceModel.Namespaces {
+		findings = append(findings, analyzeDuplicatePolicies(common.ObjRef{
+			Kind: "Namespace",
+			Name: namespaceNode.Namespace.GetName(),
+		}, common.MapToValues(namespaceNode.Policies))...)
+	}
+	return findings
+}
+
+func analyzeGateways(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, gatewayNode := range resourceModel.Gateways {
+		gatewayRef := common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}
+		findings = append(findings, analyzeAPIVersion(gatewayNode.Gateway, gatewayNode.Gateway.TypeMeta)...)
+		findings = append(findings, analyzeUnusedGateway(gatewayNode)...)
+		findings = append(findings, analyzeGatewayMissingDefaultBackends(gatewayNode)...)
+		findings = append(findings, analyzeGatewayListenerStatus(gatewayNode)...)
+		findings = append(findings, analyzeUnservedListeners(gatewayNode)...)
+		findings = append(findings, analyzeListenerTLSConflicts(gatewayNode)...)
+		findings = append(findings, analyzeGatewayCertificateRefs(gatewayNode)...)
+		findings = append(findings, analyzeStaleGeneration(gatewayRef, gatewayNode.Generations())...)
+		findings = append(findings, analyzeEffectivePolicies(gatewayRef, gatewayNode.Errors)...)
+		findings = append(findings, analyzeDuplicatePolicies(gatewayRef, common.MapToValues(gatewayNode.Policies))...)
+	}
+	findings = append(findings, analyzeGatewayAddressConflicts(resourceModel)...)
+	return findings
+}
+
+func analyzeHTTPRoutes(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, httpRouteNode := range resourceModel.HTTPRoutes {
+		httpRouteRef := common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}
+		findings = append(findings, analyzeHTTPRouteMatches(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteFilters(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteMissingServices(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteMissingSecrets(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteBackendPorts(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteAmbiguousWeights(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteListenerTLSMode(httpRouteNode)...)
+		findings = append(findings, analyzeHTTPRouteParentReferences(httpRouteNode)...)
+		findings = append(findings, analyzeStaleGeneration(httpRouteRef, httpRouteNode.Generations())...)
+		findings = append(findings, analyzeAPIVersion(httpRouteNode.HTTPRoute, httpRouteNode.HTTPRoute.TypeMeta)...)
+		findings = append(findings, analyzeEffectivePolicies(httpRouteRef, httpRouteNode.Errors)...)
+		findings = append(findings, analyzeDuplicatePolicies(httpRouteRef, common.MapToValues(httpRouteNode.Policies))...)
+		findings = append(findings, analyzeUnmatchedPolicySections(httpRouteRef, httpRouteNode)...)
+	}
+	return findings
+}
+
+func analyzeReferenceGrants(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, referenceGrantNode := range resourceModel.ReferenceGrants {
+		findings = append(findings, analyzeAPIVersion(referenceGrantNode.ReferenceGrant, referenceGrantNode.ReferenceGrant.TypeMeta)...)
+	}
+	findings = append(findings, analyzeDuplicateReferenceGrants(resourceModel)...)
+	return findings
+}
+
+func analyzePolicies(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, policyNode := range resourceModel.Policies {
+		policy := policyNode.Policy.Unstructured()
+		findings = append(findings, analyzePolicyAncestorStatus(policyNode)...)
+		findings = append(findings, analyzeShadowedPolicy(policyNode)...)
+		findings = append(findings, analyzePolicyTargetKind(policyNode)...)
+		findings = append(findings, analyzeStaleGeneration(common.ObjRef{
+			Group:     policy.GroupVersionKind().Group,
+			Kind:      policy.GetKind(),
+			Name:      policy.GetName(),
+			Namespace: policy.GetNamespace(),
+		}, policyNode.Generations())...)
+	}
+	return findings
+}
+
+func analyzeBackends(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, backendNode := range resourceModel.Backends {
+		backendRef := common.ObjRef{
+			Group:     backendNode.Backend.GroupVersionKind().Group,
+			Kind:      backendNode.Backend.GetKind(),
+			Name:      backendNode.Backend.GetName(),
+			Namespace: backendNode.Backend.GetNamespace(),
+		}
+		findings = append(findings, analyzeBackendTrafficDistribution(backendNode)...)
+		findings = append(findings, analyzeBackendEndpoints(backendNode)...)
+		findings = append(findings, analyzeBackendZeroWeight(backendNode)...)
+		findings = append(findings, analyzeEffectivePolicies(backendRef, backendNode.Errors)...)
+		findings = append(findings, analyzeDuplicatePolicies(backendRef, common.MapToValues(backendNode.Policies))...)
+	}
+	return findings
+}
+
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a := fmt.Sprintf("%v/%v/%v/%v", findings[i].ResourceRef.Kind, findings[i].ResourceRef.Namespace, findings[i].ResourceRef.Name, findings[i].Message)
+		b := fmt.Sprintf("%v/%v/%v/%v", findings[j].ResourceRef.Kind, findings[j].ResourceRef.Namespace, findings[j].ResourceRef.Name, findings[j].Message)
+		return a < b
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyze_IgnoredNamespaces(t *testing.T) {
+	shadowedRules := []gatewayv1.HTTPRouteRule{
+		{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/")}}}},
+		{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/")}}}},
+	}
+	gateway := func(namespace, name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		}
+	}
+	httpRoute := func(namespace, name string, parentRefs ...gatewayv1.ParentReference) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Rules:           shadowedRules,
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("kube-system"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("default", "foo-gateway"),
+		gateway("kube-system", "kube-gateway"),
+		// HTTPRoute in a regular namespace referencing a Gateway in an ignored
+		// namespace.
+		httpRoute("default", "foo-httproute",
+			gatewayv1.ParentReference{Name: "foo-gateway"},
+			gatewayv1.ParentReference{Name: "kube-gateway", Namespace: common.PtrTo(gatewayv1.Namespace("kube-system"))},
+		),
+		// HTTPRoute in an ignored namespace attached to a Gateway in a regular
+		// namespace.
+		httpRoute("kube-system", "kube-httproute",
+			gatewayv1.ParentReference{Name: "foo-gateway", Namespace: common.PtrTo(gatewayv1.Namespace("default"))},
+		),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:        params.K8sClients,
+		PolicyManager:     params.PolicyManager,
+		IgnoredNamespaces: resourcediscovery.DefaultNamespaceIgnoreList,
+	}
+	filter := resourcediscovery.Filter{Labels: labels.Everything()}
+
+	httpRoutesResourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	gatewaysResourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	// HTTPRoutes from ignored namespaces are not discovered directly...
+	if _, ok := httpRoutesResourceModel.HTTPRoutes[resourcediscovery.HTTPRouteID("kube-system", "kube-httproute")]; ok {
+		t.Errorf("HTTPRoute kube-system/kube-httproute should not be discovered from an ignored namespace")
+	}
+	// ...but resources in ignored namespaces still resolve as reference targets.
+	fooHTTPRouteNode, ok := httpRoutesResourceModel.HTTPRoutes[resourcediscovery.HTTPRouteID("default", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-httproute missing from resourceModel")
+	}
+	if _, ok := fooHTTPRouteNode.Gateways[resourcediscovery.GatewayID("kube-system", "kube-gateway")]; !ok {
+		t.Errorf("HTTPRoute default/foo-httproute should be attached to Gateway kube-system/kube-gateway")
+	}
+	if len(fooHTTPRouteNode.Errors) != 0 {
+		t.Errorf("HTTPRoute default/foo-httproute should not have any errors; got %v", fooHTTPRouteNode.Errors)
+	}
+	if _, ok := gatewaysResourceModel.HTTPRoutes[resourcediscovery.HTTPRouteID("kube-system", "kube-httproute")]; !ok {
+		t.Errorf("HTTPRoute kube-system/kube-httproute should be discovered through Gateway default/foo-gateway")
+	}
+
+	// Only the HTTPRoute outside of the ignored namespaces produces findings.
+	gotFindings := Analyze(httpRoutesResourceModel, gatewaysResourceModel)
+	wantFindings := []Finding{
+		{
+			Code:        CodeShadowedMatch,
+			Severity:    SeverityWarning,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			Message:     "Match 0 of rule 1 (PathPrefix /) is shadowed by match 0 of rule 0 (PathPrefix /) which matches the same requests with equal or higher precedence",
+		},
+	}
+	if diff := cmp.Diff(wantFindings, gotFindings, cmpopts.EquateEmpty(), ignoreCodeDetails); diff != "" {
+		t.Errorf("Unexpected diff in Analyze(); got=%v, want=%v;\ndiff (-want +got)=\n%v", gotFindings, wantFindings, diff)
+	}
+}
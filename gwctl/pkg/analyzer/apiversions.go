@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeAPIVersion reports resources which were last written as a deprecated
+// API version, along with the version they should be upgraded to. The version
+// is taken from the managedFields of the resource, since the API server serves
+// resources as whichever version gwctl requests.
+func analyzeAPIVersion(object metav1.Object, typeMeta metav1.TypeMeta) []Finding {
+	apiVersion := resourcediscovery.WrittenAPIVersion(object)
+	replacement, deprecated := resourcediscovery.DeprecatedAPIVersion(typeMeta.Kind, apiVersion)
+	if !deprecated {
+		return nil
+	}
+	return []Finding{newFinding(CodeDeprecatedAPIVersion, common.ObjRef{
+		Kind:      typeMeta.Kind,
+		Name:      object.GetName(),
+		Namespace: object.GetNamespace(),
+	}, fmt.Sprintf("%v uses the deprecated API version %v; upgrade to %v", typeMeta.Kind, apiVersion, replacement))}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeAPIVersion(t *testing.T) {
+	managedFields := func(apiVersion string) []metav1.ManagedFieldsEntry {
+		return []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply, APIVersion: apiVersion},
+			// Status is written by the implementation, which may use another
+			// version.
+			{Manager: "controller", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "gateway.networking.k8s.io/v1", Subresource: "status"},
+		}
+	}
+	httpRoute := func(name, apiVersion string) *gatewayv1beta1.HTTPRoute {
+		return &gatewayv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          name,
+				Namespace:     "default",
+				ManagedFields: managedFields(apiVersion),
+			},
+			Spec: gatewayv1beta1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayv1beta1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1beta1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1beta1.HTTPBackendRef{{
+						BackendRef: gatewayv1beta1.BackendRef{
+							BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+								Kind:      common.PtrTo(gatewayv1.Kind("Service")),
+								Name:      "bar-svc",
+								Namespace: common.PtrTo(gatewayv1.Namespace("bar")),
+								Port:      common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("bar"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// Both HTTPRoutes are read as v1beta1, but only foo-httproute was
+		// written as v1beta1, even though v1 is available.
+		httpRoute("foo-httproute", "gateway.networking.k8s.io/v1beta1"),
+		httpRoute("bar-httproute", "gateway.networking.k8s.io/v1"),
+		// v1beta1 is the latest version of ReferenceGrant.
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          "bar-referencegrant",
+				Namespace:     "bar",
+				ManagedFields: managedFields("gateway.networking.k8s.io/v1beta1"),
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default"}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+			},
+		},
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-svc",
+				Namespace: "bar",
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-svc-abc",
+				Namespace: "bar",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "bar-svc"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:                     params.K8sClients,
+		PolicyManager:                  params.PolicyManager,
+		PreferredHTTPRouteGroupVersion: metav1.GroupVersion(gatewayv1beta1.GroupVersion),
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	if len(resourceModel.GatewayClasses) != 1 || len(resourceModel.Gateways) != 1 || len(resourceModel.HTTPRoutes) != 2 || len(resourceModel.ReferenceGrants) != 1 {
+		t.Fatalf("resourceModel is missing resources; got %d GatewayClasses, %d Gateways, %d HTTPRoutes and %d ReferenceGrants; want 1, 1, 2 and 1",
+			len(resourceModel.GatewayClasses), len(resourceModel.Gateways), len(resourceModel.HTTPRoutes), len(resourceModel.ReferenceGrants))
+	}
+
+	want := []Finding{{
+		Code:        CodeDeprecatedAPIVersion,
+		Severity:    SeverityInfo,
+		ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+		Message:     "HTTPRoute uses the deprecated API version gateway.networking.k8s.io/v1beta1; upgrade to gateway.networking.k8s.io/v1",
+	}}
+	if diff := cmp.Diff(want, Analyze(resourceModel), ignoreCodeDetails); diff != "" {
+		t.Errorf("Unexpected diff in Findings (-want +got):\n%v", diff)
+	}
+}
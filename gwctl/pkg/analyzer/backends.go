@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeBackendTrafficDistribution reports Backends which prefer topologically
+// close endpoints while all their endpoints are within a single zone. In such
+// cases, the trafficDistribution setting provides no benefit.
+func analyzeBackendTrafficDistribution(backendNode *resourcediscovery.BackendNode) []Finding {
+	if backendNode.TrafficDistribution != corev1.ServiceTrafficDistributionPreferClose {
+		return nil
+	}
+	zones := backendNode.Zones()
+	if len(zones) != 1 {
+		return nil
+	}
+
+	return []Finding{newFinding(CodeTrafficDistributionSingleZone, common.ObjRef{
+		Group:     backendNode.Backend.GroupVersionKind().Group,
+		Kind:      backendNode.Backend.GetKind(),
+		Name:      backendNode.Backend.GetName(),
+		Namespace: backendNode.Backend.GetNamespace(),
+	}, fmt.Sprintf("trafficDistribution is %v but all endpoints are in a single zone (%v), so the setting has no effect", corev1.ServiceTrafficDistributionPreferClose, zones[0]))}
+}
+
+// analyzeBackendEndpoints reports Backends without any ready endpoints which are
+// referenced by HTTPRoutes attached to a Gateway or a parent Service. Requests
+// routed to such Backends fail, typically with a 503 status code.
+func analyzeBackendEndpoints(backendNode *resourcediscovery.BackendNode) []Finding {
+	if !backendNode.EndpointsDiscovered || backendNode.ReadyEndpoints != 0 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, httpRouteNode := range backendNode.HTTPRoutes {
+		if len(httpRouteNode.Gateways) == 0 && !httpRouteNode.IsMeshRoute() {
+			continue
+		}
+		findings = append(findings, newFinding(CodeBackendWithoutReadyEndpoints, common.ObjRef{
+			Group:     backendNode.Backend.GroupVersionKind().Group,
+			Kind:      backendNode.Backend.GetKind(),
+			Name:      backendNode.Backend.GetName(),
+			Namespace: backendNode.Backend.GetNamespace(),
+		}, fmt.Sprintf("%v has no ready endpoints but is referenced by HTTPRoute %v/%v, so requests routed to it fail",
+			backendNode.Backend.GetKind(), httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName())))
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Message < findings[j].Message })
+	return findings
+}
+
+// analyzeBackendZeroWeight reports Backends with effective policies which do
+// not receive any traffic, since all backendRefs referencing them have a weight
+// of 0. Their policies are effectively inert, which may be confusing when
+// checking why a policy does not seem to work.
+func analyzeBackendZeroWeight(backendNode *resourcediscovery.BackendNode) []Finding {
+	var policyCrdIDs []string
+	seen := make(map[string]bool)
+	for _, policies := range backendNode.EffectivePolicies {
+		for policyCrdID := range policies {
+			if !seen[string(policyCrdID)] {
+				seen[string(policyCrdID)] = true
+				policyCrdIDs = append(policyCrdIDs, string(policyCrdID))
+			}
+		}
+	}
+	if len(policyCrdIDs) == 0 || !backendNode.ReceivesZeroWeight() {
+		return nil
+	}
+	sort.Strings(policyCrdIDs)
+
+	return []Finding{newFinding(CodeInertBackendPolicies, common.ObjRef{
+		Group:     backendNode.Backend.GroupVersionKind().Group,
+		Kind:      backendNode.Backend.GetKind(),
+		Name:      backendNode.Backend.GetName(),
+		Namespace: backendNode.Backend.GetNamespace(),
+	}, fmt.Sprintf("effective policies %v apply to the %v, but all backendRefs referencing it have a weight of 0, so the policies are inert",
+		strings.Join(policyCrdIDs, ", "), backendNode.Backend.GetKind()))}
+}
+
+// analyzeHTTPRouteMissingServices reports Services referenced by the HTTPRoute,
+// either as a backend or as a parent, which do not exist.
+func analyzeHTTPRouteMissingServices(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, err := range httpRouteNode.Errors {
+		var nonExistentErr resourcediscovery.ReferenceToNonExistentResourceError
+		if !errors.As(err, &nonExistentErr) || nonExistentErr.ReferredObject.Kind != "Service" {
+			continue
+		}
+		findings = append(findings, newFinding(CodeMissingService, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, nonExistentErr.Error()))
+	}
+	return findings
+}
+
+// analyzeHTTPRouteBackendPorts reports backendRefs of the HTTPRoute which
+// reference a port by a name which does not exist on the Service.
+func analyzeHTTPRouteBackendPorts(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, err := range httpRouteNode.Errors {
+		var unknownPortErr resourcediscovery.UnknownBackendPortError
+		if !errors.As(err, &unknownPortErr) {
+			continue
+		}
+		findings = append(findings, newFinding(CodeUnknownBackendPort, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, unknownPortErr.Error()))
+	}
+	return findings
+}
+
+// analyzeHTTPRouteAmbiguousWeights reports rules of the HTTPRoute which specify
+// a weight on some backendRefs but not on others, along with the share of the
+// traffic which the backendRefs without a weight receive through defaulting.
+func analyzeHTTPRouteAmbiguousWeights(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	rules := httpRouteNode.RulesWithAmbiguousWeights()
+	if len(rules) == 0 {
+		return nil
+	}
+	omitted := make(map[int][]string)
+	for _, weight := range httpRouteNode.BackendWeights() {
+		if weight.WeightOmitted {
+			omitted[weight.RuleIndex] = append(omitted[weight.RuleIndex],
+				fmt.Sprintf("%v (%.1f%%)", resourcediscovery.BackendRefString(weight.BackendRef), weight.Percentage))
+		}
+	}
+
+	var findings []Finding
+	for _, ruleIndex := range rules {
+		findings = append(findings, newFinding(CodeAmbiguousBackendWeights, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, fmt.Sprintf("rule %d specifies a weight on some backendRefs but not on others, which default to a weight of 1: %v",
+			ruleIndex, strings.Join(omitted[ruleIndex], ", "))))
+	}
+	return findings
+}
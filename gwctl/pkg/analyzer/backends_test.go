@@ -0,0 +1,499 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeBackendTrafficDistribution(t *testing.T) {
+	serviceForTest := func(trafficDistribution *string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+			Spec: corev1.ServiceSpec{
+				TrafficDistribution: trafficDistribution,
+			},
+		}
+	}
+	endpointSliceForTest := func(zones ...string) *discoveryv1.EndpointSlice {
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc-abc",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo-svc"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+		for _, zone := range zones {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+				Addresses: []string{"10.0.0.1"},
+				Zone:      common.PtrTo(zone),
+			})
+		}
+		return endpointSlice
+	}
+
+	testcases := []struct {
+		name         string
+		objects      []runtime.Object
+		wantFindings []Finding
+	}{
+		{
+			name: "PreferClose with endpoints in a single zone",
+			objects: []runtime.Object{
+				serviceForTest(common.PtrTo(corev1.ServiceTrafficDistributionPreferClose)),
+				endpointSliceForTest("zone-a", "zone-a"),
+			},
+			wantFindings: []Finding{
+				{
+					Code:        CodeTrafficDistributionSingleZone,
+					Severity:    SeverityInfo,
+					ResourceRef: common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "default"},
+					Message:     "trafficDistribution is PreferClose but all endpoints are in a single zone (zone-a), so the setting has no effect",
+				},
+			},
+		},
+		{
+			name: "PreferClose with endpoints in multiple zones",
+			objects: []runtime.Object{
+				serviceForTest(common.PtrTo(corev1.ServiceTrafficDistributionPreferClose)),
+				endpointSliceForTest("zone-a", "zone-b"),
+			},
+			wantFindings: nil,
+		},
+		{
+			name: "no trafficDistribution with endpoints in a single zone",
+			objects: []runtime.Object{
+				serviceForTest(nil),
+				endpointSliceForTest("zone-a"),
+			},
+			wantFindings: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := append([]runtime.Object{common.NamespaceForTest("default")}, tc.objects...)
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			got := Analyze(resourceModel)
+			if diff := cmp.Diff(tc.wantFindings, got, cmpopts.EquateEmpty(), ignoreCodeDetails); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.wantFindings, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBackendEndpoints(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "foo-gatewayclass",
+		},
+	}
+	httpRoute := func(parentGateway string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(parentGateway)}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-svc",
+			Namespace: "default",
+		},
+	}
+	endpointSliceForTest := func(ready ...bool) *discoveryv1.EndpointSlice {
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc-abc",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo-svc"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+		for _, ready := range ready {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+				Addresses:  []string{"10.0.0.1"},
+				Conditions: discoveryv1.EndpointConditions{Ready: common.PtrTo(ready)},
+			})
+		}
+		return endpointSlice
+	}
+	noReadyEndpointsFinding := Finding{
+		Code:        CodeBackendWithoutReadyEndpoints,
+		Severity:    SeverityWarning,
+		ResourceRef: common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "default"},
+		Message:     "Service has no ready endpoints but is referenced by HTTPRoute default/foo-httproute, so requests routed to it fail",
+	}
+
+	testcases := []struct {
+		name         string
+		objects      []runtime.Object
+		wantFindings []Finding
+	}{
+		{
+			name:         "Service with ready endpoints",
+			objects:      []runtime.Object{gateway, httpRoute("foo-gateway"), service, endpointSliceForTest(false, true)},
+			wantFindings: nil,
+		},
+		{
+			name:         "Service with only endpoints which are not ready",
+			objects:      []runtime.Object{gateway, httpRoute("foo-gateway"), service, endpointSliceForTest(false)},
+			wantFindings: []Finding{noReadyEndpointsFinding},
+		},
+		{
+			name:         "Service without any EndpointSlices",
+			objects:      []runtime.Object{gateway, httpRoute("foo-gateway"), service},
+			wantFindings: []Finding{noReadyEndpointsFinding},
+		},
+		{
+			name:         "Service without endpoints referenced by an HTTPRoute which is not attached",
+			objects:      []runtime.Object{httpRoute("non-existent-gateway"), service},
+			wantFindings: nil,
+		},
+		{
+			name:    "Service missing entirely",
+			objects: []runtime.Object{gateway, httpRoute("foo-gateway")},
+			wantFindings: []Finding{
+				{
+					Code:        CodeMissingService,
+					Severity:    SeverityError,
+					ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+					Message:     `HTTPRoute "default/foo-httproute" references a non-existent Service "default/foo-svc"`,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := append([]runtime.Object{common.NamespaceForTest("default")}, tc.objects...)
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			filter := resourcediscovery.Filter{Labels: labels.Everything()}
+			httpRoutesResourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), filter)
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+			backendsResourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), filter)
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			got := Analyze(httpRoutesResourceModel, backendsResourceModel)
+			if diff := cmp.Diff(tc.wantFindings, got, cmpopts.EquateEmpty(), ignoreCodeDetails); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.wantFindings, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBackendZeroWeight(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "foo-gatewayclass",
+		},
+	}
+	httpRoute := func(weights ...int32) *gatewayv1.HTTPRoute {
+		var backendRefs []gatewayv1.HTTPBackendRef
+		for _, weight := range weights {
+			backendRefs = append(backendRefs, gatewayv1.HTTPBackendRef{
+				BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{
+						Kind: common.PtrTo(gatewayv1.Kind("Service")),
+						Name: "foo-svc",
+						Port: common.PtrTo(gatewayv1.PortNumber(80)),
+					},
+					Weight: common.PtrTo(weight),
+				},
+			})
+		}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+	}
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-svc",
+			Namespace: "default",
+		},
+	}
+	timeoutPolicyCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "timeoutpolicies.bar.com",
+			Labels: map[string]string{
+				gatewayv1alpha2.PolicyLabelKey: "direct",
+			},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Scope:    apiextensionsv1.NamespaceScoped,
+			Group:    "bar.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: "timeoutpolicies",
+				Kind:   "TimeoutPolicy",
+			},
+		},
+	}
+	timeoutPolicy := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "bar.com/v1",
+			"kind":       "TimeoutPolicy",
+			"metadata": map[string]interface{}{
+				"name":      "timeout-policy",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"seconds": int64(30),
+				"targetRef": map[string]interface{}{
+					"kind": "Service",
+					"name": "foo-svc",
+				},
+			},
+		},
+	}
+	// mirroredHTTPRoute forwards requests to bar-svc, and mirrors them to the
+	// zero weight foo-svc.
+	mirroredHTTPRoute := httpRoute(0)
+	mirroredHTTPRoute.Spec.Rules[0].BackendRefs = append(mirroredHTTPRoute.Spec.Rules[0].BackendRefs, gatewayv1.HTTPBackendRef{
+		BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: "bar-svc",
+				Port: common.PtrTo(gatewayv1.PortNumber(80)),
+			},
+		},
+	})
+	mirroredHTTPRoute.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+			BackendRef: gatewayv1.BackendObjectReference{
+				Name: "foo-svc",
+				Port: common.PtrTo(gatewayv1.PortNumber(80)),
+			},
+		},
+	}}
+	inertFinding := newFinding(CodeInertBackendPolicies, common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "default"},
+		"effective policies TimeoutPolicy.bar.com apply to the Service, but all backendRefs referencing it have a weight of 0, so the policies are inert")
+
+	testcases := []struct {
+		name         string
+		objects      []runtime.Object
+		wantFindings []Finding
+	}{
+		{
+			name:         "zero weight backend with a policy",
+			objects:      []runtime.Object{gateway, httpRoute(0), service, timeoutPolicyCRD, timeoutPolicy},
+			wantFindings: []Finding{inertFinding},
+		},
+		{
+			name:         "zero weight backend referenced twice with a policy",
+			objects:      []runtime.Object{gateway, httpRoute(0, 0), service, timeoutPolicyCRD, timeoutPolicy},
+			wantFindings: []Finding{inertFinding},
+		},
+		{
+			name:         "zero weight backend without policies",
+			objects:      []runtime.Object{gateway, httpRoute(0), service},
+			wantFindings: nil,
+		},
+		{
+			name:         "backend with a policy receiving traffic",
+			objects:      []runtime.Object{gateway, httpRoute(1), service, timeoutPolicyCRD, timeoutPolicy},
+			wantFindings: nil,
+		},
+		{
+			name:         "backend with a policy receiving traffic from one of its backendRefs",
+			objects:      []runtime.Object{gateway, httpRoute(0, 2), service, timeoutPolicyCRD, timeoutPolicy},
+			wantFindings: nil,
+		},
+		{
+			name:         "zero weight backend with a policy receiving mirrored traffic",
+			objects:      []runtime.Object{gateway, mirroredHTTPRoute, service, timeoutPolicyCRD, timeoutPolicy},
+			wantFindings: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := append([]runtime.Object{common.NamespaceForTest("default")}, tc.objects...)
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			var got []Finding
+			for _, backendNode := range resourceModel.Backends {
+				got = append(got, analyzeBackendZeroWeight(backendNode)...)
+			}
+			if diff := cmp.Diff(tc.wantFindings, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.wantFindings, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeHTTPRouteBackendPorts(t *testing.T) {
+	httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-httproute",
+			Namespace: "default",
+		},
+	})
+	httpRouteNode.Errors = append(httpRouteNode.Errors, resourcediscovery.UnknownBackendPortError{
+		ReferenceFromTo: resourcediscovery.ReferenceFromTo{
+			ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			ReferredObject:  common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "default"},
+		},
+		RuleIndex: 1,
+		Port:      "grpc",
+	})
+
+	want := []Finding{
+		newFinding(CodeUnknownBackendPort, common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}, `HTTPRoute "default/foo-httproute" references port "grpc" in rule 1 which does not exist on Service "default/foo-svc"`),
+	}
+	if diff := cmp.Diff(want, analyzeHTTPRouteBackendPorts(httpRouteNode)); diff != "" {
+		t.Errorf("analyzeHTTPRouteBackendPorts() diff (-want +got):\n%v", diff)
+	}
+}
+
+func TestAnalyzeHTTPRouteAmbiguousWeights(t *testing.T) {
+	backendRef := func(name string, weight *int32) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+				Weight: weight,
+			},
+		}
+	}
+	httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-httproute",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc", common.PtrTo(int32(90))),
+					backendRef("bar-svc", common.PtrTo(int32(10))),
+				}},
+				// Mixes explicit and omitted weights.
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc", common.PtrTo(int32(3))),
+					backendRef("bar-svc", nil),
+				}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc", nil),
+					backendRef("bar-svc", nil),
+				}},
+			},
+		},
+	})
+
+	want := []Finding{
+		newFinding(CodeAmbiguousBackendWeights, common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			"rule 1 specifies a weight on some backendRefs but not on others, which default to a weight of 1: Service default/bar-svc (25.0%)"),
+	}
+	got := analyzeHTTPRouteAmbiguousWeights(httpRouteNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// CheckBaseline compares the effective policies of the ResourceModel with the
+// Baseline and reports every deviating field as a Finding, sorted by
+// resource.
+func CheckBaseline(resourceModel *resourcediscovery.ResourceModel, baseline resourcediscovery.Baseline) ([]Finding, error) {
+	deviations, err := resourceModel.CheckBaseline(baseline)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, deviation := range deviations {
+		subject := fmt.Sprintf("Effective %v", deviation.PolicyCrdID)
+		if deviation.Gateway.Name != "" {
+			subject += fmt.Sprintf(" through Gateway %v/%v", deviation.Gateway.Namespace, deviation.Gateway.Name)
+		}
+		for _, change := range deviation.Changes {
+			var message string
+			switch {
+			case change.IsAdded():
+				message = fmt.Sprintf("%v sets %v to %v, which is not part of the baseline", subject, change.Path, change.Value)
+			case change.IsRemoved():
+				message = fmt.Sprintf("%v does not set %v, want %v", subject, change.Path, change.PreviousValue)
+			default:
+				message = fmt.Sprintf("%v sets %v to %v, want %v", subject, change.Path, change.Value, change.PreviousValue)
+			}
+			findings = append(findings, newPolicyFinding(CodeBaselineDeviation, deviation.PolicyCrdID, deviation.Resource, message))
+		}
+	}
+	sortFindings(findings)
+	return findings, nil
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// Code is a stable identifier for the kind of issue a Finding reports. Codes
+// are never reused or renumbered, so they can be used to filter or suppress
+// Findings programmatically, e.g. in CI.
+type Code string
+
+// Category groups related Codes.
+type Category string
+
+const (
+	CategoryAPIVersion Category = "APIVersion"
+	CategoryBackend    Category = "Backend"
+	CategoryConfig     Category = "Config"
+	CategoryFilter     Category = "Filter"
+	CategoryMetadata   Category = "Metadata"
+	CategoryPolicy     Category = "Policy"
+	CategoryRouting    Category = "Routing"
+)
+
+const (
+	CodeDeprecatedAPIVersion          Code = "GWCTL001"
+	CodeTrafficDistributionSingleZone Code = "GWCTL002"
+	CodeBackendWithoutReadyEndpoints  Code = "GWCTL003"
+	CodeMissingService                Code = "GWCTL004"
+	CodeShadowedMatch                 Code = "GWCTL005"
+	CodeCORSWildcardWithCredentials   Code = "GWCTL006"
+	CodeContradictoryResponseHeaders  Code = "GWCTL007"
+	CodeConflictingBackendFilter      Code = "GWCTL008"
+	CodeUnresolvedExtensionRef        Code = "GWCTL009"
+	CodeTLSPassthroughListener        Code = "GWCTL010"
+	CodeInvalidEffectivePolicy        Code = "GWCTL011"
+	CodePolicyNotAccepted             Code = "GWCTL012"
+	CodePolicyConflicted              Code = "GWCTL013"
+	CodeUnknownBackendPort            Code = "GWCTL014"
+	CodeUnusedGateway                 Code = "GWCTL015"
+	CodeParentReferenceNotPermitted   Code = "GWCTL016"
+	CodeDuplicatePolicies             Code = "GWCTL017"
+	CodeInertBackendPolicies          Code = "GWCTL018"
+	CodeListenerMissingFromStatus     Code = "GWCTL019"
+	CodeStaleObservedGeneration       Code = "GWCTL020"
+	CodeShadowedPolicy                Code = "GWCTL021"
+	CodeMissingRequiredLabel          Code = "GWCTL022"
+	CodeBaselineDeviation             Code = "GWCTL023"
+	CodeUnservedListener              Code = "GWCTL024"
+	CodeAmbiguousBackendWeights       Code = "GWCTL025"
+	CodeUnresolvedParametersRef       Code = "GWCTL026"
+	CodePolicyTargetKindNotAllowed    Code = "GWCTL027"
+	CodeMissingSecret                 Code = "GWCTL028"
+	CodeConflictingListenerTLS        Code = "GWCTL029"
+	CodeCertificateRefNotPermitted    Code = "GWCTL030"
+	CodeConflictingGatewayAddress     Code = "GWCTL031"
+	CodeDuplicateReferenceGrant       Code = "GWCTL032"
+	CodeBackendReferenceNotPermitted  Code = "GWCTL033"
+	CodeUnmatchedRequest              Code = "GWCTL034"
+	CodeUnmatchedPolicySection        Code = "GWCTL035"
+)
+
+// CodeInfo documents a Code.
+type CodeInfo struct {
+	Code     Code
+	Category Category
+	Severity Severity
+	// Summary is a short description of the issue.
+	Summary string
+	// Remediation describes how the issue is typically resolved.
+	Remediation string
+}
+
+// codeRegistry documents all Codes, in the order of their numbers.
+var codeRegistry = []CodeInfo{
+	{
+		Code:        CodeDeprecatedAPIVersion,
+		Category:    CategoryAPIVersion,
+		Severity:    SeverityInfo,
+		Summary:     "The resource was last written as a deprecated API version.",
+		Remediation: "Update the manifests of the resource to the replacement API version.",
+	},
+	{
+		Code:        CodeTrafficDistributionSingleZone,
+		Category:    CategoryBackend,
+		Severity:    SeverityInfo,
+		Summary:     "The Service prefers close endpoints, but all its endpoints are in a single zone.",
+		Remediation: "Spread the endpoints across zones, or remove the trafficDistribution setting.",
+	},
+	{
+		Code:        CodeBackendWithoutReadyEndpoints,
+		Category:    CategoryBackend,
+		Severity:    SeverityWarning,
+		Summary:     "The Service is routed to, but has no ready endpoints.",
+		Remediation: "Check that the Pods selected by the Service are running and ready.",
+	},
+	{
+		Code:        CodeMissingService,
+		Category:    CategoryBackend,
+		Severity:    SeverityError,
+		Summary:     "The HTTPRoute or Gateway references a Service which does not exist.",
+		Remediation: "Create the Service, or fix the backendRef or parentRef which references it.",
+	},
+	{
+		Code:        CodeShadowedMatch,
+		Category:    CategoryRouting,
+		Severity:    SeverityWarning,
+		Summary:     "A match of the HTTPRoute never applies since another match takes precedence for the same requests.",
+		Remediation: "Remove the shadowed match, or make it more specific than the match shadowing it.",
+	},
+	{
+		Code:        CodeCORSWildcardWithCredentials,
+		Category:    CategoryFilter,
+		Severity:    SeverityWarning,
+		Summary:     "A CORS filter allows all origins together with credentials, which browsers reject.",
+		Remediation: "List the allowed origins explicitly, or stop allowing credentials.",
+	},
+	{
+		Code:        CodeContradictoryResponseHeaders,
+		Category:    CategoryFilter,
+		Severity:    SeverityWarning,
+		Summary:     "A ResponseHeaderModifier filter both sets and removes the same header.",
+		Remediation: "Either set or remove the header, but not both.",
+	},
+	{
+		Code:        CodeConflictingBackendFilter,
+		Category:    CategoryFilter,
+		Severity:    SeverityWarning,
+		Summary:     "A filter of a backendRef modifies the same headers as a filter of its rule.",
+		Remediation: "Modify each header either in the rule or in the backendRef.",
+	},
+	{
+		Code:        CodeUnresolvedExtensionRef,
+		Category:    CategoryFilter,
+		Severity:    SeverityError,
+		Summary:     "An ExtensionRef filter references an object which could not be resolved.",
+		Remediation: "Create the referenced object, or fix the ExtensionRef.",
+	},
+	{
+		Code:        CodeTLSPassthroughListener,
+		Category:    CategoryRouting,
+		Severity:    SeverityError,
+		Summary:     "The HTTPRoute only attaches to listeners in TLS Passthrough mode, which only accept TLSRoutes.",
+		Remediation: "Attach the HTTPRoute to an HTTP or HTTPS listener, or use a TLSRoute.",
+	},
+	{
+		Code:        CodeInvalidEffectivePolicy,
+		Category:    CategoryPolicy,
+		Severity:    SeverityError,
+		Summary:     "The effective policy, merged from multiple levels of the hierarchy, does not conform to the schema of its CRD.",
+		Remediation: "Change the policies at one of the levels such that merging them results in a valid spec.",
+	},
+	{
+		Code:        CodePolicyNotAccepted,
+		Category:    CategoryPolicy,
+		Severity:    SeverityError,
+		Summary:     "An implementation did not accept the policy for one of its ancestors.",
+		Remediation: "Check the reason and message of the Accepted condition in the status of the policy.",
+	},
+	{
+		Code:        CodePolicyConflicted,
+		Category:    CategoryPolicy,
+		Severity:    SeverityError,
+		Summary:     "An implementation reported that the policy conflicts with another policy.",
+		Remediation: "Check which policy takes precedence, and remove or retarget the conflicting one.",
+	},
+	{
+		Code:        CodeUnknownBackendPort,
+		Category:    CategoryBackend,
+		Severity:    SeverityError,
+		Summary:     "A backendRef references a port by a name which does not exist on the Service.",
+		Remediation: "Use the name or number of one of the ports listed in the spec of the Service.",
+	},
+	{
+		Code:        CodeUnusedGateway,
+		Category:    CategoryRouting,
+		Severity:    SeverityInfo,
+		Summary:     "The Gateway has no attached HTTPRoutes and no default backends, so it does not serve any traffic.",
+		Remediation: "Attach routes or a default backend to the Gateway, or delete it if it is no longer needed.",
+	},
+	{
+		Code:        CodeParentReferenceNotPermitted,
+		Category:    CategoryRouting,
+		Severity:    SeverityError,
+		Summary:     "The HTTPRoute references a Gateway in another namespace, but no ReferenceGrant permits it to attach.",
+		Remediation: "Create a ReferenceGrant in the namespace of the Gateway which permits HTTPRoutes from the namespace of the HTTPRoute.",
+	},
+	{
+		Code:        CodeDuplicatePolicies,
+		Category:    CategoryPolicy,
+		Severity:    SeverityWarning,
+		Summary:     "Multiple policies of the same kind are directly attached to the same resource.",
+		Remediation: "Combine the policies into a single one, or remove the policies which do not take precedence.",
+	},
+	{
+		Code:        CodeInertBackendPolicies,
+		Category:    CategoryPolicy,
+		Severity:    SeverityInfo,
+		Summary:     "Policies apply to the backend, but all backendRefs referencing it have a weight of 0, so no traffic reaches it.",
+		Remediation: "Give the backendRefs a non-zero weight if the backend should receive traffic, or remove the policies.",
+	},
+	{
+		Code:        CodeListenerMissingFromStatus,
+		Category:    CategoryRouting,
+		Severity:    SeverityWarning,
+		Summary:     "A listener is declared in the spec of the Gateway, but the Gateway does not report a status for it, so it was likely not programmed.",
+		Remediation: "Check the conditions of the Gateway and the logs of the implementation for why the listener was not programmed.",
+	},
+	{
+		Code:        CodeStaleObservedGeneration,
+		Category:    CategoryRouting,
+		Severity:    SeverityWarning,
+		Summary:     "The status of the Gateway, HTTPRoute or policy reflects an older generation than the current one, so the latest change has not been reconciled yet or the reconcile is stuck.",
+		Remediation: "Wait for the controller to reconcile the resource; if the gap persists, check the logs of the implementation for errors reconciling it.",
+	},
+	{
+		Code:        CodeShadowedPolicy,
+		Category:    CategoryPolicy,
+		Severity:    SeverityInfo,
+		Summary:     "An inheritable policy is overridden on every resource inheriting it, so none of its fields are in effect.",
+		Remediation: "Remove the policy, or remove the overrides from the policies attached to the descendant resources.",
+	},
+	{
+		Code:        CodeMissingRequiredLabel,
+		Category:    CategoryMetadata,
+		Severity:    SeverityWarning,
+		Summary:     "The resource is missing labels which are required for its kind, only reported with `--required-labels`.",
+		Remediation: "Add the missing labels to the resource.",
+	},
+	{
+		Code:        CodeBaselineDeviation,
+		Category:    CategoryPolicy,
+		Severity:    SeverityWarning,
+		Summary:     "The effective policy of the resource deviates from the baseline, only reported by `gwctl check-baseline`.",
+		Remediation: "Change the policies contributing to the effective policy, or update the baseline if the change is intended.",
+	},
+	{
+		Code:        CodeUnservedListener,
+		Category:    CategoryRouting,
+		Severity:    SeverityWarning,
+		Summary:     "A listener with a hostname serves nothing, since no HTTPRoute is attached to it or none of the hostnames of the attached HTTPRoutes intersect with its hostname.",
+		Remediation: "Align the hostname of the listener with the hostnames of the HTTPRoutes meant to attach to it, or remove the listener.",
+	},
+	{
+		Code:        CodeAmbiguousBackendWeights,
+		Category:    CategoryBackend,
+		Severity:    SeverityWarning,
+		Summary:     "A rule of an HTTPRoute specifies a weight on some of its backendRefs but not on others, whose weight then defaults to 1.",
+		Remediation: "Specify a weight on every backendRef of the rule, or on none of them.",
+	},
+	{
+		Code:        CodeUnresolvedParametersRef,
+		Category:    CategoryConfig,
+		Severity:    SeverityError,
+		Summary:     "The parametersRef of a GatewayClass references an object which could not be resolved.",
+		Remediation: "Create the referenced configuration object, or fix the parametersRef.",
+	},
+	{
+		Code:        CodePolicyTargetKindNotAllowed,
+		Category:    CategoryPolicy,
+		Severity:    SeverityError,
+		Summary:     "The targetRef of a policy references a kind which the CRD of the policy does not allow it to target.",
+		Remediation: "Target a kind allowed by the CRD of the policy, or use a policy kind which supports the targeted kind.",
+	},
+	{
+		Code:        CodeMissingSecret,
+		Category:    CategoryConfig,
+		Severity:    SeverityError,
+		Summary:     "An HTTPRoute references a Secret through its filters, directly or through an ExtensionRef, which does not exist.",
+		Remediation: "Create the Secret, or fix the reference in the filter or in the object referenced by the ExtensionRef filter.",
+	},
+	{
+		Code:        CodeConflictingListenerTLS,
+		Category:    CategoryConfig,
+		Severity:    SeverityWarning,
+		Summary:     "Two HTTPS listeners of a Gateway share a port and a hostname, but reference different certificates, so which certificate clients are presented depends on the implementation.",
+		Remediation: "Merge the listeners, give them different hostnames, or have them reference the same certificates.",
+	},
+	{
+		Code:        CodeCertificateRefNotPermitted,
+		Category:    CategoryConfig,
+		Severity:    SeverityError,
+		Summary:     "A listener of the Gateway references a certificate in another namespace, but no ReferenceGrant permits the reference, so the listener is not programmed.",
+		Remediation: "Create a ReferenceGrant in the namespace of the certificate which permits Gateways from the namespace of the Gateway.",
+	},
+	{
+		Code:        CodeConflictingGatewayAddress,
+		Category:    CategoryConfig,
+		Severity:    SeverityError,
+		Summary:     "Multiple Gateways request the same address in their spec. Only compared across the analyzed namespaces.",
+		Remediation: "Request distinct addresses for the Gateways, or remove the address from the spec of all but one of them.",
+	},
+	{
+		Code:        CodeDuplicateReferenceGrant,
+		Category:    CategoryConfig,
+		Severity:    SeverityInfo,
+		Summary:     "Multiple ReferenceGrants permit the same references, either exactly or because one permits all names of the kind, which makes it harder to tell which of them is needed.",
+		Remediation: "Consolidate the ReferenceGrants, keeping a single one which permits the references.",
+	},
+	{
+		Code:        CodeBackendReferenceNotPermitted,
+		Category:    CategoryBackend,
+		Severity:    SeverityError,
+		Summary:     "An HTTPRoute references a backend in another namespace, but no ReferenceGrant permits the reference, only reported by `gwctl verify-grants`.",
+		Remediation: "Create a ReferenceGrant in the namespace of the backend which permits HTTPRoutes from the namespace of the HTTPRoute.",
+	},
+	{
+		Code:        CodeUnmatchedRequest,
+		Category:    CategoryRouting,
+		Severity:    SeverityError,
+		Summary:     "A test request is not matched by any HTTPRoute attached to the Gateway, only reported by `gwctl match-test`.",
+		Remediation: "Add an HTTPRoute matching the request, or fix the hostnames and matches of the HTTPRoute meant to match it.",
+	},
+	{
+		Code:        CodeUnmatchedPolicySection,
+		Category:    CategoryPolicy,
+		Severity:    SeverityWarning,
+		Summary:     "A policy targets a section of an HTTPRoute, but no rule of the HTTPRoute has that name, so the policy does not apply to any of its rules.",
+		Remediation: "Set the sectionName of the policy to the name of a rule, or name the rule the policy is meant for.",
+	},
+}
+
+// Codes returns the documentation of all Codes, sorted by Code.
+func Codes() []CodeInfo {
+	return append([]CodeInfo(nil), codeRegistry...)
+}
+
+// LookupCode returns the documentation of the Code.
+func LookupCode(code Code) (CodeInfo, bool) {
+	for _, info := range codeRegistry {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return CodeInfo{}, false
+}
+
+// newFinding returns a Finding for the Code, with the Severity, Category and
+// Remediation documented for it.
+// newPolicyFinding returns a Finding about policies of the given kind.
+func newPolicyFinding(code Code, policyCrdID policymanager.PolicyCrdID, resourceRef common.ObjRef, message string) Finding {
+	finding := newFinding(code, resourceRef, message)
+	finding.PolicyKind = policyCrdID
+	return finding
+}
+
+func newFinding(code Code, resourceRef common.ObjRef, message string) Finding {
+	info, _ := LookupCode(code)
+	return Finding{
+		Code:        code,
+		Severity:    info.Severity,
+		Category:    info.Category,
+		ResourceRef: resourceRef,
+		Message:     message,
+		Remediation: info.Remediation,
+	}
+}
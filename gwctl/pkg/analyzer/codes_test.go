@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// ignoreCodeDetails ignores the fields of a Finding which are derived from its
+// Code, so tests only need to spell out the Code itself.
+var ignoreCodeDetails = cmpopts.IgnoreFields(Finding{}, "Category", "Remediation")
+
+func TestCodes(t *testing.T) {
+	seen := map[Code]bool{}
+	for i, info := range Codes() {
+		if seen[info.Code] {
+			t.Errorf("Code %v is registered more than once", info.Code)
+		}
+		seen[info.Code] = true
+		// Codes are numbered sequentially and never reused.
+		if want := Code(fmt.Sprintf("GWCTL%03d", i+1)); info.Code != want {
+			t.Errorf("Codes()[%d].Code = %v; want %v", i, info.Code, want)
+		}
+		if info.Category == "" || info.Severity == "" || info.Summary == "" || info.Remediation == "" {
+			t.Errorf("Code %v is not fully documented: %+v", info.Code, info)
+		}
+	}
+
+	// Each declared Code must be registered.
+	for name, code := range declaredCodes(t) {
+		if !seen[code] {
+			t.Errorf("%v (%v) is declared but not registered", name, code)
+		}
+	}
+}
+
+// TestCodes_Emitted checks that every Finding created by the analyzers uses a
+// registered Code.
+func TestCodes_Emitted(t *testing.T) {
+	codes := declaredCodes(t)
+	registered := func(name string) bool {
+		code, ok := codes[name]
+		if !ok {
+			return false
+		}
+		_, ok = LookupCode(code)
+		return ok
+	}
+
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || file == "codes.go" {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CompositeLit:
+				if ident, ok := n.Type.(*ast.Ident); ok && ident.Name == "Finding" {
+					t.Errorf("%v: Finding must be created through newFinding or newPolicyFinding", fset.Position(n.Pos()))
+				}
+			case *ast.FuncDecl:
+				if n.Body == nil {
+					return false
+				}
+				// Codes may be chosen through a local variable, in which case
+				// every value assigned to it must be registered.
+				assigned := map[string][]ast.Expr{}
+				ast.Inspect(n.Body, func(n ast.Node) bool {
+					if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+						for i, lhs := range assign.Lhs {
+							if ident, ok := lhs.(*ast.Ident); ok {
+								assigned[ident.Name] = append(assigned[ident.Name], assign.Rhs[i])
+							}
+						}
+					}
+					return true
+				})
+				ast.Inspect(n.Body, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok || len(call.Args) == 0 {
+						return true
+					}
+					if fun, ok := call.Fun.(*ast.Ident); !ok || (fun.Name != "newFinding" && fun.Name != "newPolicyFinding") {
+						return true
+					}
+					calls++
+					arg, ok := call.Args[0].(*ast.Ident)
+					if !ok {
+						t.Errorf("%v: code must be a Code constant or a variable holding one", fset.Position(call.Pos()))
+						return true
+					}
+					values := []ast.Expr{arg}
+					if _, ok := codes[arg.Name]; !ok {
+						values = assigned[arg.Name]
+					}
+					if len(values) == 0 {
+						t.Errorf("%v: %v is not a Code constant", fset.Position(call.Pos()), arg.Name)
+					}
+					for _, value := range values {
+						if ident, ok := value.(*ast.Ident); !ok || !registered(ident.Name) {
+							t.Errorf("%v: Finding uses an unregistered code %v", fset.Position(value.Pos()), value)
+						}
+					}
+					return true
+				})
+				return false
+			}
+			return true
+		})
+	}
+	if calls == 0 {
+		t.Errorf("Found no calls to newFinding or newPolicyFinding")
+	}
+}
+
+// TestCodes_README checks that the table of codes in the README is in sync
+// with the registered Codes.
+func TestCodes_README(t *testing.T) {
+	readme, err := os.ReadFile("../../README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []CodeInfo
+	row := regexp.MustCompile(`(?m)^\| (GWCTL\d+) \| (\w+) +\| (\w+) +\| (.+) \| (.+) \|$`)
+	for _, match := range row.FindAllStringSubmatch(string(readme), -1) {
+		got = append(got, CodeInfo{
+			Code:        Code(match[1]),
+			Category:    Category(match[2]),
+			Severity:    Severity(match[3]),
+			Summary:     match[4],
+			Remediation: match[5],
+		})
+	}
+	if diff := cmp.Diff(Codes(), got); diff != "" {
+		t.Errorf("The code table in README.md is out of sync with Codes(); diff (-want +got)=\n%v", diff)
+	}
+}
+
+// declaredCodes returns the Code constants declared in codes.go by name.
+func declaredCodes(t *testing.T) map[string]Code {
+	f, err := parser.ParseFile(token.NewFileSet(), "codes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := map[string]Code{}
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if ident, ok := valueSpec.Type.(*ast.Ident); !ok || ident.Name != "Code" {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				lit := valueSpec.Values[i].(*ast.BasicLit)
+				codes[name.Name] = Code(strings.Trim(lit.Value, `"`))
+			}
+		}
+	}
+	if len(codes) == 0 {
+		t.Fatal("Found no Code constants in codes.go")
+	}
+	return codes
+}
+
+func TestNewFinding(t *testing.T) {
+	resourceRef := common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}
+	got := newFinding(CodeShadowedMatch, resourceRef, "message")
+	want := Finding{
+		Code:        CodeShadowedMatch,
+		Severity:    SeverityWarning,
+		Category:    CategoryRouting,
+		ResourceRef: resourceRef,
+		Message:     "message",
+		Remediation: "Remove the shadowed match, or make it more specific than the match shadowing it.",
+	}
+	if got != want {
+		t.Errorf("newFinding() = %+v; want %+v", got, want)
+	}
+}
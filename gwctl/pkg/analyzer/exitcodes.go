@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+// ExitCodeForFindings returns the code with which a command reporting the
+// findings exits: ExitCodeErrorFindings if any finding has the Error severity,
+// ExitCodeWarningFindings if any has the Warning severity and strict is true,
+// and ExitCodeSuccess otherwise.
+func ExitCodeForFindings(findings []Finding, strict bool) utils.ExitCode {
+	hasWarnings := false
+	for _, finding := range findings {
+		switch finding.Severity {
+		case SeverityError:
+			return utils.ExitCodeErrorFindings
+		case SeverityWarning:
+			hasWarnings = true
+		}
+	}
+	if strict && hasWarnings {
+		return utils.ExitCodeWarningFindings
+	}
+	return utils.ExitCodeSuccess
+}
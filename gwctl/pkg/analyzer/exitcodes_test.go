@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"testing"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestExitCodeForFindings(t *testing.T) {
+	resourceRef := common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}
+	info := newFinding(CodeShadowedPolicy, resourceRef, "info")
+	warning := newFinding(CodeShadowedMatch, resourceRef, "warning")
+	err := newFinding(CodeMissingService, resourceRef, "error")
+
+	testcases := []struct {
+		name     string
+		findings []Finding
+		strict   bool
+		want     utils.ExitCode
+	}{
+		{
+			name: "no findings",
+			want: utils.ExitCodeSuccess,
+		},
+		{
+			name:   "no findings in strict mode",
+			strict: true,
+			want:   utils.ExitCodeSuccess,
+		},
+		{
+			name:     "info findings in strict mode",
+			findings: []Finding{info},
+			strict:   true,
+			want:     utils.ExitCodeSuccess,
+		},
+		{
+			name:     "warning findings",
+			findings: []Finding{info, warning},
+			want:     utils.ExitCodeSuccess,
+		},
+		{
+			name:     "warning findings in strict mode",
+			findings: []Finding{info, warning},
+			strict:   true,
+			want:     utils.ExitCodeWarningFindings,
+		},
+		{
+			name:     "error findings",
+			findings: []Finding{warning, err},
+			want:     utils.ExitCodeErrorFindings,
+		},
+		{
+			name:     "error findings in strict mode",
+			findings: []Finding{warning, err, info},
+			strict:   true,
+			want:     utils.ExitCodeErrorFindings,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ExitCodeForFindings(tc.findings, tc.strict); got != tc.want {
+				t.Errorf("ExitCodeForFindings(strict=%v) = %v, want %v", tc.strict, got, tc.want)
+			}
+		})
+	}
+}
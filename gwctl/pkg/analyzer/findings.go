@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// Severity indicates how serious a Finding is.
+type Severity string
+
+const (
+	// SeverityInfo is used for observations which are not necessarily a
+	// problem, but may be worth knowing about.
+	SeverityInfo Severity = "Info"
+	// SeverityWarning is used for configurations which are valid but likely
+	// behave differently than intended.
+	SeverityWarning Severity = "Warning"
+	// SeverityError is used for configurations which are invalid or broken.
+	SeverityError Severity = "Error"
+)
+
+// Finding describes a single issue detected while analyzing the ResourceModel.
+type Finding struct {
+	// Code identifies the kind of issue. See Codes for all known Codes.
+	Code     Code     `json:"code"`
+	Severity Severity `json:"severity"`
+	Category Category `json:"category"`
+	// ResourceRef references the resource which the Finding is about.
+	ResourceRef common.ObjRef `json:"resourceRef"`
+	// Message is a human readable description of the Finding.
+	Message string `json:"message"`
+	// Remediation describes how the issue is typically resolved.
+	Remediation string `json:"remediation,omitempty"`
+	// PolicyKind is the kind of policy the Finding is about. It is only set
+	// for Findings about policies of a single kind.
+	PolicyKind policymanager.PolicyCrdID `json:"policyKind,omitempty"`
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeGatewayClassParametersRef reports GatewayClasses whose parametersRef
+// references an object which could not be resolved.
+func analyzeGatewayClassParametersRef(gatewayClassNode *resourcediscovery.GatewayClassNode) []Finding {
+	var findings []Finding
+	for _, err := range gatewayClassNode.Errors {
+		var unresolvedErr resourcediscovery.UnresolvedParametersRefError
+		if !errors.As(err, &unresolvedErr) {
+			continue
+		}
+		findings = append(findings, newFinding(CodeUnresolvedParametersRef, common.ObjRef{
+			Kind: "GatewayClass",
+			Name: gatewayClassNode.GatewayClass.GetName(),
+		}, unresolvedErr.Error()))
+	}
+	return findings
+}
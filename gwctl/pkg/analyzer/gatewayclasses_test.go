@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+func TestAnalyzeGatewayClassParametersRef(t *testing.T) {
+	gatewayClassNode := resourcediscovery.NewGatewayClassNode(&gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo-gatewayclass",
+		},
+	})
+	unresolvedErr := resourcediscovery.UnresolvedParametersRefError{
+		ParametersRef: common.ObjRef{Group: "example.com", Kind: "ProxyConfig", Name: "missing-config", Namespace: "default"},
+		Reason:        `proxyconfigs.example.com "missing-config" not found`,
+	}
+	gatewayClassNode.Errors = append(gatewayClassNode.Errors, unresolvedErr)
+
+	want := []Finding{
+		newFinding(CodeUnresolvedParametersRef, common.ObjRef{Kind: "GatewayClass", Name: "foo-gatewayclass"},
+			`parametersRef references ProxyConfig.example.com "default/missing-config" which could not be resolved: proxyconfigs.example.com "missing-config" not found`),
+	}
+	got := analyzeGatewayClassParametersRef(gatewayClassNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeUnusedGateway reports Gateways which neither have HTTPRoutes attached
+// nor default backends, and hence do not serve any traffic. Gateways without
+// listeners are not reported, since no route could attach to them.
+func analyzeUnusedGateway(gatewayNode *resourcediscovery.GatewayNode) []Finding {
+	if len(gatewayNode.Gateway.Spec.Listeners) == 0 {
+		return nil
+	}
+	if len(gatewayNode.HTTPRoutes) != 0 || len(gatewayNode.DefaultBackends) != 0 {
+		return nil
+	}
+	gatewayRef := common.ObjRef{
+		Kind:      "Gateway",
+		Name:      gatewayNode.Gateway.GetName(),
+		Namespace: gatewayNode.Gateway.GetNamespace(),
+	}
+	message := "Gateway has no attached HTTPRoutes and no default backends"
+	if len(gatewayNode.DefaultBackendRefs) != 0 {
+		message = fmt.Sprintf("%v; none of its %d default backends could be resolved", message, len(gatewayNode.DefaultBackendRefs))
+	}
+	return []Finding{newFinding(CodeUnusedGateway, gatewayRef, message)}
+}
+
+// analyzeGatewayMissingDefaultBackends reports default backends of the Gateway
+// which reference Services that do not exist.
+func analyzeGatewayMissingDefaultBackends(gatewayNode *resourcediscovery.GatewayNode) []Finding {
+	var findings []Finding
+	for _, err := range gatewayNode.Errors {
+		var nonExistentErr resourcediscovery.ReferenceToNonExistentResourceError
+		if !errors.As(err, &nonExistentErr) || nonExistentErr.ReferredObject.Kind != "Service" {
+			continue
+		}
+		findings = append(findings, newFinding(CodeMissingService, common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}, nonExistentErr.Error()))
+	}
+	return findings
+}
+
+// analyzeGatewayListenerStatus reports listeners declared in the spec of the
+// Gateway for which the Gateway does not report a status.
+func analyzeGatewayListenerStatus(gatewayNode *resourcediscovery.GatewayNode) []Finding {
+	var findings []Finding
+	for _, sectionName := range gatewayNode.ListenersMissingFromStatus() {
+		findings = append(findings, newFinding(CodeListenerMissingFromStatus, common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}, fmt.Sprintf("listener %v is declared in the spec, but the Gateway does not report a status for it", sectionName)))
+	}
+	return findings
+}
+
+// analyzeUnservedListeners reports listeners with a hostname through which no
+// HTTPRoute serves any hostname.
+func analyzeUnservedListeners(gatewayNode *resourcediscovery.GatewayNode) []Finding {
+	var findings []Finding
+	for _, listener := range gatewayNode.UnservedListeners() {
+		message := fmt.Sprintf("listener %v has hostname %v, but no HTTPRoutes are attached to it", listener.Listener, listener.Hostname)
+		if listener.AttachedHTTPRoutes != 0 {
+			message = fmt.Sprintf("listener %v has hostname %v, but the hostnames of the HTTPRoutes attached to it do not intersect with it", listener.Listener, listener.Hostname)
+		}
+		findings = append(findings, newFinding(CodeUnservedListener, common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}, message))
+	}
+	return findings
+}
+
+// analyzeListenerTLSConflicts reports HTTPS listeners sharing a port and a
+// hostname but referencing different certificates.
+func analyzeListenerTLSConflicts(gatewayNode *resourcediscovery.GatewayNode) []Finding {
+	var findings []Finding
+	for _, conflict := range gatewayNode.ListenerTLSConflicts() {
+		findings = append(findings, newFinding(CodeConflictingListenerTLS, common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}, fmt.Sprintf("listeners %v and %v share port %d and hostname %v, but reference different certificates", conflict.Listener, conflict.OtherListener, conflict.Port, conflict.Hostname)))
+	}
+	return findings
+}
+
+// analyzeGatewayCertificateRefs reports certificateRefs of the listeners of the
+// Gateway which reference objects in other namespaces without a permitting
+// ReferenceGrant.
+func analyzeGatewayCertificateRefs(gatewayNode *resourcediscovery.GatewayNode) []Finding {
+	var findings []Finding
+	for _, err := range gatewayNode.Errors {
+		var notPermittedErr resourcediscovery.ReferenceNotPermittedError
+		if !errors.As(err, &notPermittedErr) || notPermittedErr.ReferringObject.Kind != "Gateway" {
+			continue
+		}
+		findings = append(findings, newFinding(CodeCertificateRefNotPermitted, common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}, notPermittedErr.Error()))
+	}
+	return findings
+}
+
+// analyzeGatewayAddressConflicts reports each Gateway which requests an address
+// in its spec that other Gateways request as well.
+func analyzeGatewayAddressConflicts(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, conflict := range resourceModel.GatewayAddressConflicts() {
+		for i, gateway := range conflict.Gateways {
+			var others []string
+			for j, other := range conflict.Gateways {
+				if i != j {
+					others = append(others, fmt.Sprintf("%v/%v", other.Namespace, other.Name))
+				}
+			}
+			kind := "Gateway"
+			if len(others) > 1 {
+				kind = "Gateways"
+			}
+			findings = append(findings, newFinding(CodeConflictingGatewayAddress, common.ObjRef{
+				Kind:      "Gateway",
+				Name:      gateway.Name,
+				Namespace: gateway.Namespace,
+			}, fmt.Sprintf("address %v of type %v is also requested by %v %v", conflict.Address.Value, conflict.Address.Type, kind, strings.Join(others, ", "))))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,520 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeUnusedGateway(t *testing.T) {
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	defaultBackendRef := resourcediscovery.DefaultBackendRef{
+		Listener:   "https",
+		BackendRef: common.ObjRef{Kind: "Service", Name: "fallback-svc", Namespace: "default"},
+	}
+	newGatewayNode := func() *resourcediscovery.GatewayNode {
+		return resourcediscovery.NewGatewayNode(&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{
+				Listeners: []gatewayv1.Listener{{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443}},
+			},
+		})
+	}
+
+	testcases := []struct {
+		name   string
+		modify func(*resourcediscovery.GatewayNode)
+		want   []Finding
+	}{
+		{
+			name:   "without routes or default backends",
+			modify: func(*resourcediscovery.GatewayNode) {},
+			want: []Finding{
+				newFinding(CodeUnusedGateway, gatewayRef, "Gateway has no attached HTTPRoutes and no default backends"),
+			},
+		},
+		{
+			name: "without listeners",
+			modify: func(gatewayNode *resourcediscovery.GatewayNode) {
+				gatewayNode.Gateway.Spec.Listeners = nil
+			},
+		},
+		{
+			name: "with attached HTTPRoute",
+			modify: func(gatewayNode *resourcediscovery.GatewayNode) {
+				httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+				})
+				gatewayNode.HTTPRoutes[httpRouteNode.ID()] = httpRouteNode
+			},
+		},
+		{
+			name: "with default backend",
+			modify: func(gatewayNode *resourcediscovery.GatewayNode) {
+				backendNode := resourcediscovery.NewBackendNode(&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "Service",
+						"metadata":   map[string]interface{}{"name": "fallback-svc", "namespace": "default"},
+					},
+				})
+				gatewayNode.DefaultBackendRefs = []resourcediscovery.DefaultBackendRef{defaultBackendRef}
+				gatewayNode.DefaultBackends[backendNode.ID()] = backendNode
+			},
+		},
+		{
+			name: "with unresolved default backend",
+			modify: func(gatewayNode *resourcediscovery.GatewayNode) {
+				gatewayNode.DefaultBackendRefs = []resourcediscovery.DefaultBackendRef{defaultBackendRef}
+			},
+			want: []Finding{
+				newFinding(CodeUnusedGateway, gatewayRef, "Gateway has no attached HTTPRoutes and no default backends; none of its 1 default backends could be resolved"),
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayNode := newGatewayNode()
+			tc.modify(gatewayNode)
+			if diff := cmp.Diff(tc.want, analyzeUnusedGateway(gatewayNode)); diff != "" {
+				t.Errorf("analyzeUnusedGateway() diff (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeGatewayMissingDefaultBackends(t *testing.T) {
+	gatewayNode := resourcediscovery.NewGatewayNode(&gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+	})
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	gatewayNode.Errors = append(gatewayNode.Errors, resourcediscovery.ReferenceToNonExistentResourceError{
+		ReferenceFromTo: resourcediscovery.ReferenceFromTo{
+			ReferringObject: gatewayRef,
+			ReferredObject:  common.ObjRef{Kind: "Service", Name: "missing-svc", Namespace: "default"},
+		},
+	})
+
+	want := []Finding{
+		newFinding(CodeMissingService, gatewayRef, `Gateway "default/foo-gateway" references a non-existent Service "default/missing-svc"`),
+	}
+	if diff := cmp.Diff(want, analyzeGatewayMissingDefaultBackends(gatewayNode)); diff != "" {
+		t.Errorf("analyzeGatewayMissingDefaultBackends() diff (-want +got):\n%v", diff)
+	}
+}
+
+func TestAnalyzeGatewayListenerStatus(t *testing.T) {
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	newGatewayNode := func(status gatewayv1.GatewayStatus) *resourcediscovery.GatewayNode {
+		return resourcediscovery.NewGatewayNode(&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default", Generation: 2},
+			Spec: gatewayv1.GatewaySpec{
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				},
+			},
+			Status: status,
+		})
+	}
+	programmed := []metav1.Condition{{
+		Type:               string(gatewayv1.GatewayConditionProgrammed),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 2,
+	}}
+
+	testcases := []struct {
+		name   string
+		status gatewayv1.GatewayStatus
+		want   []Finding
+	}{
+		{
+			name: "listener missing from status",
+			status: gatewayv1.GatewayStatus{
+				Conditions: programmed,
+				Listeners:  []gatewayv1.ListenerStatus{{Name: "http", Conditions: programmed}},
+			},
+			want: []Finding{
+				newFinding(CodeListenerMissingFromStatus, gatewayRef, "listener https is declared in the spec, but the Gateway does not report a status for it"),
+			},
+		},
+		{
+			name: "all listeners in status",
+			status: gatewayv1.GatewayStatus{
+				Conditions: programmed,
+				Listeners: []gatewayv1.ListenerStatus{
+					{Name: "https", Conditions: programmed},
+					{Name: "http", Conditions: programmed},
+				},
+			},
+		},
+		{
+			name:   "Gateway without status",
+			status: gatewayv1.GatewayStatus{},
+		},
+		{
+			name: "Gateway with defaulted status",
+			status: gatewayv1.GatewayStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    string(gatewayv1.GatewayConditionAccepted),
+						Status:  metav1.ConditionUnknown,
+						Reason:  string(gatewayv1.GatewayReasonPending),
+						Message: "Waiting for controller",
+					},
+					{
+						Type:    string(gatewayv1.GatewayConditionProgrammed),
+						Status:  metav1.ConditionUnknown,
+						Reason:  string(gatewayv1.GatewayReasonPending),
+						Message: "Waiting for controller",
+					},
+				},
+			},
+		},
+		{
+			name: "status of an older generation",
+			status: gatewayv1.GatewayStatus{
+				Conditions: []metav1.Condition{{
+					Type:               string(gatewayv1.GatewayConditionProgrammed),
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+				}},
+				Listeners: []gatewayv1.ListenerStatus{{Name: "http", Conditions: programmed}},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, analyzeGatewayListenerStatus(newGatewayNode(tc.status))); diff != "" {
+				t.Errorf("analyzeGatewayListenerStatus() diff (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeUnservedListeners(t *testing.T) {
+	hostname := func(h string) *gatewayv1.Hostname {
+		return common.PtrTo(gatewayv1.Hostname(h))
+	}
+	httpRoute := func(name, sectionName string, hostnames ...gatewayv1.Hostname) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Name:        "foo-gateway",
+						SectionName: common.PtrTo(gatewayv1.SectionName(sectionName)),
+					}},
+				},
+				Hostnames: hostnames,
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "api", Protocol: gatewayv1.HTTPProtocolType, Port: 80, Hostname: hostname("api.example.com")},
+					// The hostname of the listener does not match the hostname of
+					// the HTTPRoute attached to it.
+					{Name: "web", Protocol: gatewayv1.HTTPProtocolType, Port: 8080, Hostname: hostname("web.example.com")},
+					{Name: "idle", Protocol: gatewayv1.HTTPProtocolType, Port: 8081, Hostname: hostname("idle.example.com")},
+					// Listeners without a hostname are never reported.
+					{Name: "any", Protocol: gatewayv1.HTTPProtocolType, Port: 8082},
+				},
+			},
+		},
+		httpRoute("api-httproute", "api", "*.example.com"),
+		httpRoute("web-httproute", "web", "www.example.com"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	want := []Finding{
+		newFinding(CodeUnservedListener, gatewayRef, "listener web has hostname web.example.com, but the hostnames of the HTTPRoutes attached to it do not intersect with it"),
+		newFinding(CodeUnservedListener, gatewayRef, "listener idle has hostname idle.example.com, but no HTTPRoutes are attached to it"),
+	}
+	got := analyzeUnservedListeners(resourceModel.Gateways[resourcediscovery.GatewayID("default", "foo-gateway")])
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzeListenerTLSConflicts(t *testing.T) {
+	hostname := func(h string) *gatewayv1.Hostname {
+		return common.PtrTo(gatewayv1.Hostname(h))
+	}
+	https := func(name string, port gatewayv1.PortNumber, h *gatewayv1.Hostname, secrets ...string) gatewayv1.Listener {
+		listener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(name),
+			Protocol: gatewayv1.HTTPSProtocolType,
+			Port:     port,
+			Hostname: h,
+			TLS:      &gatewayv1.GatewayTLSConfig{},
+		}
+		for _, secret := range secrets {
+			listener.TLS.CertificateRefs = append(listener.TLS.CertificateRefs, gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(secret)})
+		}
+		return listener
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					// The api and api-v2 listeners share a hostname, but present
+					// different certificates.
+					https("api", 443, hostname("api.example.com"), "api-cert"),
+					https("api-v2", 443, hostname("api.example.com"), "api-v2-cert"),
+					// A wildcard listener next to a more specific listener does
+					// not conflict, since the more specific listener takes
+					// precedence for its hostname.
+					https("wildcard", 443, hostname("*.example.com"), "wildcard-cert"),
+					// Listeners referencing the same certificates do not conflict.
+					https("www", 443, hostname("www.example.com"), "www-cert"),
+					https("www-alt", 443, hostname("www.example.com"), "www-cert"),
+					// Listeners on other ports or with disjoint hostnames do not
+					// conflict.
+					https("admin", 8443, hostname("admin.example.com"), "admin-cert"),
+					https("other", 443, hostname("foo.com"), "foo-cert"),
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	want := []Finding{
+		newFinding(CodeConflictingListenerTLS, gatewayRef, "listeners api and api-v2 share port 443 and hostname api.example.com, but reference different certificates"),
+	}
+	got := analyzeListenerTLSConflicts(resourceModel.Gateways[resourcediscovery.GatewayID("default", "foo-gateway")])
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzeGatewayCertificateRefs(t *testing.T) {
+	baseObjects := func() []runtime.Object {
+		return []runtime.Object{
+			common.NamespaceForTest("infra"),
+			common.NamespaceForTest("certs"),
+			&gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-gateway",
+					Namespace: "infra",
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "foo-gatewayclass",
+					Listeners: []gatewayv1.Listener{
+						{
+							Name:     "https",
+							Protocol: gatewayv1.HTTPSProtocolType,
+							Port:     443,
+							TLS: &gatewayv1.GatewayTLSConfig{
+								CertificateRefs: []gatewayv1.SecretObjectReference{
+									{Name: "foo-cert", Namespace: common.PtrTo(gatewayv1.Namespace("certs"))},
+									// References within the namespace of the Gateway need no ReferenceGrant.
+									{Name: "local-cert"},
+								},
+							},
+						},
+						{
+							Name:     "https-alt",
+							Protocol: gatewayv1.HTTPSProtocolType,
+							Port:     8443,
+							TLS: &gatewayv1.GatewayTLSConfig{
+								CertificateRefs: []gatewayv1.SecretObjectReference{
+									// The same reference is reported once per Gateway.
+									{Name: "foo-cert", Namespace: common.PtrTo(gatewayv1.Namespace("certs"))},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	referenceGrant := func(fromNamespace string) *gatewayv1beta1.ReferenceGrant {
+		return &gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-gateways",
+				Namespace: "certs",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "Gateway",
+					Namespace: gatewayv1.Namespace(fromNamespace),
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{
+					Kind: "Secret",
+				}},
+			},
+		}
+	}
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "infra"}
+
+	testcases := []struct {
+		name    string
+		objects []runtime.Object
+		want    []Finding
+	}{
+		{
+			name: "without ReferenceGrant",
+			want: []Finding{
+				newFinding(CodeCertificateRefNotPermitted, gatewayRef, `Gateway "infra/foo-gateway" is not permitted to reference Secret "certs/foo-cert"`),
+			},
+		},
+		{
+			name:    "with ReferenceGrant from another namespace",
+			objects: []runtime.Object{referenceGrant("other")},
+			want: []Finding{
+				newFinding(CodeCertificateRefNotPermitted, gatewayRef, `Gateway "infra/foo-gateway" is not permitted to reference Secret "certs/foo-cert"`),
+			},
+		},
+		{
+			name:    "with permitting ReferenceGrant",
+			objects: []runtime.Object{referenceGrant("infra")},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, append(baseObjects(), tc.objects...)...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+			gatewayNode := resourceModel.Gateways[resourcediscovery.GatewayID("infra", "foo-gateway")]
+
+			got := analyzeGatewayCertificateRefs(gatewayNode)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeGatewayAddressConflicts(t *testing.T) {
+	gateway := func(name, address string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Addresses:        []gatewayv1.GatewayAddress{{Value: address}},
+			},
+		}
+	}
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("foo-gateway", "192.0.2.1"),
+		gateway("bar-gateway", "192.0.2.1"),
+		gateway("baz-gateway", "192.0.2.2"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	want := []Finding{
+		newFinding(CodeConflictingGatewayAddress, common.ObjRef{Kind: "Gateway", Name: "bar-gateway", Namespace: "default"}, "address 192.0.2.1 of type IPAddress is also requested by Gateway default/foo-gateway"),
+		newFinding(CodeConflictingGatewayAddress, common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}, "address 192.0.2.1 of type IPAddress is also requested by Gateway default/bar-gateway"),
+	}
+	got := analyzeGatewayAddressConflicts(resourceModel)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeStaleGeneration reports resources whose status reflects an older
+// generation than the current one, which indicates a pending or stuck
+// reconcile.
+func analyzeStaleGeneration(resourceRef common.ObjRef, generations resourcediscovery.Generations) []Finding {
+	if !generations.Stale() {
+		return nil
+	}
+	message := fmt.Sprintf("%v is at generation %d, but its status reflects generation %d (%d behind); the latest change has not been reconciled",
+		resourceRef.Kind, generations.Generation, generations.ObservedGeneration, generations.Gap())
+	return []Finding{newFinding(CodeStaleObservedGeneration, resourceRef, message)}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeStaleGeneration(t *testing.T) {
+	accepted := func(observedGeneration int64) []metav1.Condition {
+		return []metav1.Condition{{
+			Type:               "Accepted",
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: observedGeneration,
+		}}
+	}
+	gateway := func(name string, generation, observedGeneration int64) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "default",
+				Generation: generation,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+			Status: gatewayv1.GatewayStatus{
+				Conditions: accepted(observedGeneration),
+			},
+		}
+	}
+	parentStatus := func(gatewayName string, observedGeneration int64) gatewayv1.RouteParentStatus {
+		return gatewayv1.RouteParentStatus{
+			ParentRef:      gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayName)},
+			ControllerName: "foo.com/gateway-controller",
+			Conditions:     accepted(observedGeneration),
+		}
+	}
+	timeoutPolicy := func(name, gatewayName string, generation, observedGeneration int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":       name,
+					"namespace":  "default",
+					"generation": generation,
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  gatewayName,
+					},
+				},
+				"status": map[string]interface{}{
+					"ancestors": []interface{}{
+						map[string]interface{}{
+							"ancestorRef":    map[string]interface{}{"name": gatewayName},
+							"controllerName": "foo.com/gateway-controller",
+							"conditions": []interface{}{
+								map[string]interface{}{
+									"type":               "Accepted",
+									"status":             "True",
+									"reason":             "Accepted",
+									"message":            "",
+									"lastTransitionTime": "2024-01-01T00:00:00Z",
+									"observedGeneration": observedGeneration,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.bar.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "direct"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		// The latest change to the stale Gateway has not been reconciled.
+		gateway("stale-gateway", 3, 1),
+		gateway("current-gateway", 2, 2),
+		// Only one of the parents of the HTTPRoute reconciled its latest change.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "foo-httproute",
+				Namespace:  "default",
+				Generation: 5,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "stale-gateway"},
+						{Name: "current-gateway"},
+					},
+				},
+			},
+			Status: gatewayv1.HTTPRouteStatus{
+				RouteStatus: gatewayv1.RouteStatus{
+					Parents: []gatewayv1.RouteParentStatus{
+						parentStatus("stale-gateway", 4),
+						parentStatus("current-gateway", 5),
+					},
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "bar-httproute",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "current-gateway"}},
+				},
+			},
+			Status: gatewayv1.HTTPRouteStatus{
+				RouteStatus: gatewayv1.RouteStatus{
+					Parents: []gatewayv1.RouteParentStatus{parentStatus("current-gateway", 1)},
+				},
+			},
+		},
+		timeoutPolicy("stale-timeout-policy", "current-gateway", 2, 1),
+		timeoutPolicy("current-timeout-policy", "stale-gateway", 4, 4),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	var got []Finding
+	for _, finding := range Analyze(resourceModel) {
+		if finding.Code == CodeStaleObservedGeneration {
+			got = append(got, finding)
+		}
+	}
+	want := []Finding{
+		newFinding(CodeStaleObservedGeneration, common.ObjRef{Kind: "Gateway", Name: "stale-gateway", Namespace: "default"},
+			"Gateway is at generation 3, but its status reflects generation 1 (2 behind); the latest change has not been reconciled"),
+		newFinding(CodeStaleObservedGeneration, common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			"HTTPRoute is at generation 5, but its status reflects generation 4 (1 behind); the latest change has not been reconciled"),
+		newFinding(CodeStaleObservedGeneration, common.ObjRef{Group: "bar.com", Kind: "TimeoutPolicy", Name: "stale-timeout-policy", Namespace: "default"},
+			"TimeoutPolicy is at generation 2, but its status reflects generation 1 (1 behind); the latest change has not been reconciled"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Analyze() diff (-want +got):\n%v", diff)
+	}
+}
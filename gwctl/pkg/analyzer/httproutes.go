@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeHTTPRouteMatches reports matches within an HTTPRoute which can never
+// be selected because a match from a different rule matches exactly the same
+// requests and takes precedence over it.
+//
+// Note that a less specific match can never shadow a more specific one (for
+// example, PathPrefix "/" does not shadow PathPrefix "/api") since precedence
+// is decided by specificity before the order of rules is considered.
+func analyzeHTTPRouteMatches(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	matchesByRequestSet := make(map[string][]resourcediscovery.HTTPRouteMatch)
+	var keys []string
+	for _, match := range resourcediscovery.HTTPRouteMatches(httpRouteNode.HTTPRoute) {
+		key := match.RequestSetKey()
+		if _, ok := matchesByRequestSet[key]; !ok {
+			keys = append(keys, key)
+		}
+		matchesByRequestSet[key] = append(matchesByRequestSet[key], match)
+	}
+
+	var findings []Finding
+	for _, key := range keys {
+		matches := matchesByRequestSet[key]
+		winner := matches[0]
+		for _, match := range matches[1:] {
+			if match.HasHigherPrecedenceThan(winner) {
+				winner = match
+			}
+		}
+		for _, match := range matches {
+			// Identical matches within the same rule route to the same place, so
+			// they are harmless.
+			if match.RuleIndex == winner.RuleIndex {
+				continue
+			}
+			findings = append(findings, newFinding(CodeShadowedMatch, common.ObjRef{
+				Kind:      "HTTPRoute",
+				Name:      httpRouteNode.HTTPRoute.GetName(),
+				Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+			}, fmt.Sprintf("Match %d of rule %d (%v) is shadowed by match %d of rule %d (%v) which matches the same requests with equal or higher precedence",
+				match.MatchIndex, match.RuleIndex, match, winner.MatchIndex, winner.RuleIndex, winner)))
+		}
+	}
+	return findings
+}
+
+// analyzeHTTPRouteFilters reports filters of the HTTPRoute with invalid
+// configurations. Currently, this reports:
+//   - CORS filters which allow all origins while also allowing credentials.
+//     Browsers reject such responses, so the filter does not behave as
+//     intended.
+//   - ResponseHeaderModifier filters which both set (or add) and remove the
+//     same header, which is contradictory.
+//   - ExtensionRef filters referencing objects which could not be resolved.
+//     Implementations typically reject the rule in this case.
+//   - Header modifier filters of a backendRef which modify the same headers
+//     as a filter of the same type of its rule. Both filters apply to traffic
+//     to the backend, so the outcome depends on the order the implementation
+//     applies them in.
+func analyzeHTTPRouteFilters(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, filter := range httpRouteNode.Filters {
+		if filter.CORS == nil || !filter.CORS.AllowCredentials || !filter.CORS.AllowsAllOrigins() {
+			continue
+		}
+		findings = append(findings, newFinding(CodeCORSWildcardWithCredentials, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, fmt.Sprintf("CORS filter in rule %d allows all origins (*) together with credentials, which browsers reject", filter.RuleIndex)))
+	}
+	for _, filter := range httpRouteNode.ResponseHeaderModifiers() {
+		headers := resourcediscovery.ContradictoryHeaders(filter.ResponseHeaderModifier)
+		if len(headers) == 0 {
+			continue
+		}
+		findings = append(findings, newFinding(CodeContradictoryResponseHeaders, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, fmt.Sprintf("ResponseHeaderModifier filter in rule %d both sets and removes header(s) %v", filter.RuleIndex, strings.Join(headers, ", "))))
+	}
+	for _, backendFilter := range httpRouteNode.Filters {
+		if backendFilter.BackendRef == nil {
+			continue
+		}
+		for _, ruleFilter := range httpRouteNode.Filters {
+			if ruleFilter.BackendRef != nil || ruleFilter.RuleIndex != backendFilter.RuleIndex || ruleFilter.Type != backendFilter.Type {
+				continue
+			}
+			headers := resourcediscovery.ConflictingHeaders(ruleFilter.RequestHeaderModifier, backendFilter.RequestHeaderModifier)
+			headers = append(headers, resourcediscovery.ConflictingHeaders(ruleFilter.ResponseHeaderModifier, backendFilter.ResponseHeaderModifier)...)
+			if len(headers) == 0 {
+				continue
+			}
+			findings = append(findings, newFinding(CodeConflictingBackendFilter, common.ObjRef{
+				Kind:      "HTTPRoute",
+				Name:      httpRouteNode.HTTPRoute.GetName(),
+				Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+			}, fmt.Sprintf("%v filter of backendRef %d of rule %d (%v) modifies header(s) %v which are also modified by the filter of the rule", backendFilter.Type, backendFilter.BackendRefIndex, backendFilter.RuleIndex, resourcediscovery.BackendRefString(*backendFilter.BackendRef), strings.Join(headers, ", "))))
+		}
+	}
+	for _, err := range httpRouteNode.Errors {
+		var unresolvedErr resourcediscovery.UnresolvedExtensionRefError
+		if !errors.As(err, &unresolvedErr) {
+			continue
+		}
+		findings = append(findings, newFinding(CodeUnresolvedExtensionRef, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, unresolvedErr.Error()))
+	}
+	return findings
+}
+
+// analyzeHTTPRouteListenerTLSMode reports HTTPRoutes attached to Gateway
+// listeners which operate in TLS Passthrough mode. Passthrough listeners
+// forward the TLS stream without terminating it, so they only accept
+// TLSRoutes; an HTTPRoute can only attach to HTTP listeners or to HTTPS
+// listeners which terminate TLS.
+//
+// A parentRef which does not select a specific listener is only reported if
+// every listener it could attach to is in Passthrough mode.
+func analyzeHTTPRouteListenerTLSMode(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, parentRef := range httpRouteNode.HTTPRoute.Spec.ParentRefs {
+		if parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName {
+			continue
+		}
+		if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+			continue
+		}
+		namespace := httpRouteNode.HTTPRoute.GetNamespace()
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		gatewayNode, ok := httpRouteNode.Gateways[resourcediscovery.GatewayID(namespace, string(parentRef.Name))]
+		if !ok {
+			continue
+		}
+
+		var candidates, passthrough []string
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if parentRef.SectionName != nil && listener.Name != *parentRef.SectionName {
+				continue
+			}
+			if parentRef.Port != nil && listener.Port != *parentRef.Port {
+				continue
+			}
+			candidates = append(candidates, string(listener.Name))
+			if listener.TLS != nil && listener.TLS.Mode != nil && *listener.TLS.Mode == gatewayv1.TLSModePassthrough {
+				passthrough = append(passthrough, string(listener.Name))
+			}
+		}
+		if len(passthrough) == 0 || len(passthrough) != len(candidates) {
+			continue
+		}
+		findings = append(findings, newFinding(CodeTLSPassthroughListener, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, fmt.Sprintf("HTTPRoute attaches to listener(s) %v of Gateway %v/%v which use TLS mode Passthrough and only accept TLSRoutes",
+			strings.Join(passthrough, ", "), namespace, parentRef.Name)))
+	}
+	return findings
+}
+
+// analyzeHTTPRouteParentReferences reports Gateways in other namespaces which
+// the HTTPRoute could not attach to for lack of a permitting ReferenceGrant.
+// These are only recorded when discovery requires ReferenceGrants for such
+// parentRefs.
+func analyzeHTTPRouteParentReferences(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, err := range httpRouteNode.Errors {
+		var notPermittedErr resourcediscovery.ReferenceNotPermittedError
+		if !errors.As(err, &notPermittedErr) || notPermittedErr.ReferredObject.Kind != "Gateway" {
+			continue
+		}
+		findings = append(findings, newFinding(CodeParentReferenceNotPermitted, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, notPermittedErr.Error()))
+	}
+	return findings
+}
+
+// analyzeHTTPRouteMissingSecrets reports Secrets referenced through the filters
+// of the HTTPRoute, directly or through the objects referenced by its
+// ExtensionRef filters, which do not exist.
+func analyzeHTTPRouteMissingSecrets(httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, err := range httpRouteNode.Errors {
+		var nonExistentErr resourcediscovery.ReferenceToNonExistentResourceError
+		if !errors.As(err, &nonExistentErr) || nonExistentErr.ReferredObject.Kind != "Secret" {
+			continue
+		}
+		findings = append(findings, newFinding(CodeMissingSecret, common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, nonExistentErr.Error()))
+	}
+	return findings
+}
@@ -0,0 +1,524 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeHTTPRouteMatches(t *testing.T) {
+	pathMatch := func(pathType gatewayv1.PathMatchType, value string) gatewayv1.HTTPRouteMatch {
+		return gatewayv1.HTTPRouteMatch{
+			Path: &gatewayv1.HTTPPathMatch{
+				Type:  common.PtrTo(pathType),
+				Value: common.PtrTo(value),
+			},
+		}
+	}
+	httpRoute := func(rules ...gatewayv1.HTTPRouteRule) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: rules,
+			},
+		}
+	}
+	httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}
+
+	testcases := []struct {
+		name         string
+		httpRoute    *gatewayv1.HTTPRoute
+		wantFindings []Finding
+	}{
+		{
+			name: "exact duplicate matches in different rules",
+			httpRoute: httpRoute(
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{
+					{
+						Path:    &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/api")},
+						Method:  common.PtrTo(gatewayv1.HTTPMethodGet),
+						Headers: []gatewayv1.HTTPHeaderMatch{{Name: "Version", Value: "v2"}},
+					},
+				}},
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{
+					{
+						Path:    &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/api")},
+						Method:  common.PtrTo(gatewayv1.HTTPMethodGet),
+						Headers: []gatewayv1.HTTPHeaderMatch{{Name: "version", Value: "v2"}}, // Header names are case-insensitive.
+					},
+				}},
+			),
+			wantFindings: []Finding{
+				{
+					Code:        CodeShadowedMatch,
+					Severity:    SeverityWarning,
+					ResourceRef: httpRouteRef,
+					Message:     "Match 0 of rule 1 (PathPrefix /api method=GET headers=[Exact:version=v2]) is shadowed by match 0 of rule 0 (PathPrefix /api method=GET headers=[Exact:version=v2]) which matches the same requests with equal or higher precedence",
+				},
+			},
+		},
+		{
+			name: "longer equivalent prefix shadows shorter prefix regardless of rule order",
+			httpRoute: httpRoute(
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/api")}},
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/api/")}},
+			),
+			wantFindings: []Finding{
+				{
+					Code:        CodeShadowedMatch,
+					Severity:    SeverityWarning,
+					ResourceRef: httpRouteRef,
+					Message:     "Match 0 of rule 0 (PathPrefix /api) is shadowed by match 0 of rule 1 (PathPrefix /api/) which matches the same requests with equal or higher precedence",
+				},
+			},
+		},
+		{
+			name: "rule without matches is shadowed by an earlier catch-all rule",
+			httpRoute: httpRoute(
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/")}},
+				gatewayv1.HTTPRouteRule{},
+			),
+			wantFindings: []Finding{
+				{
+					Code:        CodeShadowedMatch,
+					Severity:    SeverityWarning,
+					ResourceRef: httpRouteRef,
+					Message:     "Match 0 of rule 1 (PathPrefix /) is shadowed by match 0 of rule 0 (PathPrefix /) which matches the same requests with equal or higher precedence",
+				},
+			},
+		},
+		{
+			name: "less specific prefix before more specific prefix does not shadow",
+			httpRoute: httpRoute(
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/")}},
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/api")}},
+			),
+			wantFindings: nil,
+		},
+		{
+			name: "duplicate matches within the same rule are harmless",
+			httpRoute: httpRoute(
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{
+					pathMatch(gatewayv1.PathMatchExact, "/login"),
+					pathMatch(gatewayv1.PathMatchExact, "/login"),
+				}},
+			),
+			wantFindings: nil,
+		},
+		{
+			name: "matches differing in method do not shadow",
+			httpRoute: httpRoute(
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{{
+					Path:   &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchExact), Value: common.PtrTo("/login")},
+					Method: common.PtrTo(gatewayv1.HTTPMethodGet),
+				}}},
+				gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{{
+					Path:   &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchExact), Value: common.PtrTo("/login")},
+					Method: common.PtrTo(gatewayv1.HTTPMethodPost),
+				}}},
+			),
+			wantFindings: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := []runtime.Object{
+				common.NamespaceForTest("default"),
+				tc.httpRoute,
+			}
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			got := Analyze(resourceModel)
+			if diff := cmp.Diff(tc.wantFindings, got, cmpopts.EquateEmpty(), ignoreCodeDetails); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.wantFindings, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeHTTPRouteFilters(t *testing.T) {
+	httpRoute := func(filter map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "gateway.networking.k8s.io/v1",
+				"kind":       "HTTPRoute",
+				"metadata": map[string]interface{}{
+					"name":      "foo-httproute",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"rules": []interface{}{
+						map[string]interface{}{
+							"filters": []interface{}{filter},
+						},
+					},
+				},
+			},
+		}
+	}
+	httpRouteWithBackendFilter := func(ruleFilter, backendFilter map[string]interface{}) *unstructured.Unstructured {
+		route := httpRoute(ruleFilter)
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		rules[0].(map[string]interface{})["backendRefs"] = []interface{}{
+			map[string]interface{}{
+				"name":    "foo-svc",
+				"port":    int64(8080),
+				"filters": []interface{}{backendFilter},
+			},
+		}
+		_ = unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+		return route
+	}
+	requestHeaderModifierFilter := func(requestHeaderModifier map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "RequestHeaderModifier", "requestHeaderModifier": requestHeaderModifier}
+	}
+	corsFilter := func(cors map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "CORS", "cors": cors}
+	}
+	responseHeaderModifierFilter := func(responseHeaderModifier map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "ResponseHeaderModifier", "responseHeaderModifier": responseHeaderModifier}
+	}
+
+	testcases := []struct {
+		name         string
+		httpRoute    *unstructured.Unstructured
+		wantFindings []Finding
+	}{
+		{
+			name: "wildcard origin with credentials is invalid",
+			httpRoute: httpRoute(corsFilter(map[string]interface{}{
+				"allowOrigins":     []interface{}{"https://foo.example.com", "*"},
+				"allowCredentials": true,
+			})),
+			wantFindings: []Finding{
+				{
+					Code:        CodeCORSWildcardWithCredentials,
+					Severity:    SeverityWarning,
+					ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+					Message:     "CORS filter in rule 0 allows all origins (*) together with credentials, which browsers reject",
+				},
+			},
+		},
+		{
+			name: "wildcard origin without credentials is valid",
+			httpRoute: httpRoute(corsFilter(map[string]interface{}{
+				"allowOrigins": []interface{}{"*"},
+			})),
+			wantFindings: nil,
+		},
+		{
+			name: "explicit origins with credentials are valid",
+			httpRoute: httpRoute(corsFilter(map[string]interface{}{
+				"allowOrigins":     []interface{}{"https://foo.example.com"},
+				"allowCredentials": true,
+			})),
+			wantFindings: nil,
+		},
+		{
+			name: "response header both set and removed is contradictory",
+			httpRoute: httpRoute(responseHeaderModifierFilter(map[string]interface{}{
+				"set":    []interface{}{map[string]interface{}{"name": "X-Cache", "value": "HIT"}},
+				"add":    []interface{}{map[string]interface{}{"name": "X-Request-Id", "value": "abc"}},
+				"remove": []interface{}{"x-cache", "X-Powered-By"},
+			})),
+			wantFindings: []Finding{
+				{
+					Code:        CodeContradictoryResponseHeaders,
+					Severity:    SeverityWarning,
+					ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+					Message:     "ResponseHeaderModifier filter in rule 0 both sets and removes header(s) x-cache",
+				},
+			},
+		},
+		{
+			name: "response headers set and removed independently are valid",
+			httpRoute: httpRoute(responseHeaderModifierFilter(map[string]interface{}{
+				"set":    []interface{}{map[string]interface{}{"name": "X-Cache", "value": "HIT"}},
+				"remove": []interface{}{"X-Powered-By"},
+			})),
+			wantFindings: nil,
+		},
+		{
+			name: "backend filter setting a header modified by the rule conflicts",
+			httpRoute: httpRouteWithBackendFilter(
+				requestHeaderModifierFilter(map[string]interface{}{
+					"set":    []interface{}{map[string]interface{}{"name": "X-Env", "value": "prod"}},
+					"remove": []interface{}{"X-Debug"},
+				}),
+				requestHeaderModifierFilter(map[string]interface{}{
+					"set": []interface{}{
+						map[string]interface{}{"name": "x-env", "value": "canary"},
+						map[string]interface{}{"name": "X-Canary", "value": "true"},
+					},
+				}),
+			),
+			wantFindings: []Finding{
+				{
+					Code:        CodeConflictingBackendFilter,
+					Severity:    SeverityWarning,
+					ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+					Message:     "RequestHeaderModifier filter of backendRef 0 of rule 0 (Service default/foo-svc) modifies header(s) x-env which are also modified by the filter of the rule",
+				},
+			},
+		},
+		{
+			name: "backend filter modifying other headers than the rule is valid",
+			httpRoute: httpRouteWithBackendFilter(
+				requestHeaderModifierFilter(map[string]interface{}{
+					"set": []interface{}{map[string]interface{}{"name": "X-Env", "value": "prod"}},
+				}),
+				requestHeaderModifierFilter(map[string]interface{}{
+					"set": []interface{}{map[string]interface{}{"name": "X-Canary", "value": "true"}},
+				}),
+			),
+			wantFindings: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The structured HTTPRoute type does not know about CORS filters, so
+			// the node is constructed directly from the unstructured HTTPRoute
+			// rather than through the (fake) API server.
+			filters, err := resourcediscovery.SummarizeHTTPRouteFilters(tc.httpRoute)
+			if err != nil {
+				t.Fatalf("SummarizeHTTPRouteFilters() failed: %v", err)
+			}
+			httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.httpRoute.GetName(),
+					Namespace: tc.httpRoute.GetNamespace(),
+				},
+			})
+			httpRouteNode.Filters = filters
+
+			got := analyzeHTTPRouteFilters(httpRouteNode)
+			if diff := cmp.Diff(tc.wantFindings, got, cmpopts.EquateEmpty(), ignoreCodeDetails); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.wantFindings, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeHTTPRouteFilters_UnresolvedExtensionRef(t *testing.T) {
+	httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-httproute",
+			Namespace: "default",
+		},
+	})
+	httpRouteNode.Errors = []error{
+		resourcediscovery.UnresolvedExtensionRefError{
+			RuleIndex:    1,
+			ExtensionRef: common.ObjRef{Group: "example.com", Kind: "WAFConfig", Name: "missing-waf", Namespace: "default"},
+			Reason:       `wafconfigs.example.com "missing-waf" not found`,
+		},
+	}
+
+	want := []Finding{
+		{
+			Code:        CodeUnresolvedExtensionRef,
+			Severity:    SeverityError,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			Message:     `ExtensionRef filter in rule 1 references WAFConfig.example.com "missing-waf" which could not be resolved: wafconfigs.example.com "missing-waf" not found`,
+		},
+	}
+	got := analyzeHTTPRouteFilters(httpRouteNode)
+	if diff := cmp.Diff(want, got, ignoreCodeDetails); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzeHTTPRouteListenerTLSMode(t *testing.T) {
+	gatewayNode := resourcediscovery.NewGatewayNode(&gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "tls-passthrough",
+					Protocol: gatewayv1.TLSProtocolType,
+					Port:     8443,
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModePassthrough)},
+				},
+				{
+					Name:     "https",
+					Protocol: gatewayv1.HTTPSProtocolType,
+					Port:     443,
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: common.PtrTo(gatewayv1.TLSModeTerminate)},
+				},
+			},
+		},
+	})
+
+	testcases := []struct {
+		name      string
+		parentRef gatewayv1.ParentReference
+		want      []Finding
+	}{
+		{
+			name:      "attached to passthrough listener through sectionName",
+			parentRef: gatewayv1.ParentReference{Name: "foo-gateway", SectionName: common.PtrTo(gatewayv1.SectionName("tls-passthrough"))},
+			want: []Finding{
+				{
+					Code:        CodeTLSPassthroughListener,
+					Severity:    SeverityError,
+					ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+					Message:     "HTTPRoute attaches to listener(s) tls-passthrough of Gateway default/foo-gateway which use TLS mode Passthrough and only accept TLSRoutes",
+				},
+			},
+		},
+		{
+			name:      "attached to passthrough listener through port",
+			parentRef: gatewayv1.ParentReference{Name: "foo-gateway", Port: common.PtrTo(gatewayv1.PortNumber(8443))},
+			want: []Finding{
+				{
+					Code:        CodeTLSPassthroughListener,
+					Severity:    SeverityError,
+					ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+					Message:     "HTTPRoute attaches to listener(s) tls-passthrough of Gateway default/foo-gateway which use TLS mode Passthrough and only accept TLSRoutes",
+				},
+			},
+		},
+		{
+			name:      "attached to terminating listener",
+			parentRef: gatewayv1.ParentReference{Name: "foo-gateway", SectionName: common.PtrTo(gatewayv1.SectionName("https"))},
+		},
+		{
+			name:      "attached to whole Gateway with a terminating listener",
+			parentRef: gatewayv1.ParentReference{Name: "foo-gateway"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-httproute",
+					Namespace: "default",
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{tc.parentRef},
+					},
+				},
+			})
+			httpRouteNode.Gateways[gatewayNode.ID()] = gatewayNode
+
+			got := analyzeHTTPRouteListenerTLSMode(httpRouteNode)
+			if diff := cmp.Diff(tc.want, got, ignoreCodeDetails); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeHTTPRouteParentReferences(t *testing.T) {
+	httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-httproute",
+			Namespace: "team-a",
+		},
+	})
+	httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "team-a"}
+	httpRouteNode.Errors = []error{
+		resourcediscovery.ReferenceNotPermittedError{ReferenceFromTo: resourcediscovery.ReferenceFromTo{
+			ReferringObject: httpRouteRef,
+			ReferredObject:  common.ObjRef{Kind: "Gateway", Name: "shared-gateway", Namespace: "infra"},
+		}},
+		// References to backends are reported on the backends instead.
+		resourcediscovery.ReferenceNotPermittedError{ReferenceFromTo: resourcediscovery.ReferenceFromTo{
+			ReferringObject: httpRouteRef,
+			ReferredObject:  common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "infra"},
+		}},
+	}
+
+	want := []Finding{
+		newFinding(CodeParentReferenceNotPermitted, httpRouteRef, `HTTPRoute "team-a/foo-httproute" is not permitted to reference Gateway "infra/shared-gateway"`),
+	}
+	got := analyzeHTTPRouteParentReferences(httpRouteNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzeHTTPRouteMissingSecrets(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+						ExtensionRef: &gatewayv1.LocalObjectReference{Kind: "Secret", Name: "basic-auth"},
+					}},
+				}},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	httpRouteNode := resourceModel.HTTPRoutes[resourcediscovery.HTTPRouteID("default", "foo-httproute")]
+
+	want := []Finding{
+		newFinding(CodeMissingSecret, common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}, `HTTPRoute "default/foo-httproute" references a non-existent Secret "default/basic-auth"`),
+	}
+	// The Secret is not reported as an unresolved ExtensionRef.
+	got := append(analyzeHTTPRouteFilters(httpRouteNode), analyzeHTTPRouteMissingSecrets(httpRouteNode)...)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
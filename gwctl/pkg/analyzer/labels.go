@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// RequiredLabels maps kinds of resources (e.g. Gateway, HTTPRoute, Service or
+// the kind of a policy) to the keys of the labels which resources of the kind
+// must have.
+type RequiredLabels map[string][]string
+
+// LoadRequiredLabels reads RequiredLabels from the YAML file at path, e.g.
+//
+//	Gateway: [team, cost-center]
+//	HTTPRoute: [team]
+func LoadRequiredLabels(path string) (RequiredLabels, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read required labels: %w", err)
+	}
+	return ParseRequiredLabels(data)
+}
+
+// ParseRequiredLabels parses RequiredLabels from YAML. See LoadRequiredLabels.
+func ParseRequiredLabels(data []byte) (RequiredLabels, error) {
+	result := make(RequiredLabels)
+	if err := yaml.UnmarshalStrict(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse required labels: %w", err)
+	}
+	for kind, keys := range result {
+		for _, key := range keys {
+			if key == "" {
+				return nil, fmt.Errorf("invalid required labels for %v: label keys must not be empty", kind)
+			}
+		}
+	}
+	return result, nil
+}
+
+// NewRequiredLabelsAnalyzer returns an Analyzer which reports resources
+// missing any of the labels required for their kind.
+func NewRequiredLabelsAnalyzer(requiredLabels RequiredLabels) Analyzer {
+	return NewAnalyzer("requiredlabels", func(resourceModel *resourcediscovery.ResourceModel) []Finding {
+		return analyzeRequiredLabels(resourceModel, requiredLabels)
+	})
+}
+
+func analyzeRequiredLabels(resourceModel *resourcediscovery.ResourceModel, requiredLabels RequiredLabels) []Finding {
+	var findings []Finding
+	check := func(resourceRef common.ObjRef, labels map[string]string) {
+		var missing []string
+		for _, key := range requiredLabels[resourceRef.Kind] {
+			if _, ok := labels[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, newFinding(CodeMissingRequiredLabel, resourceRef,
+				fmt.Sprintf("%v is missing the required labels: %v", resourceRef.Kind, strings.Join(missing, ", "))))
+		}
+	}
+
+	for _, gatewayClassNode := range resourceModel.GatewayClasses {
+		check(common.ObjRef{Kind: "GatewayClass", Name: gatewayClassNode.GatewayClass.GetName()}, gatewayClassNode.GatewayClass.GetLabels())
+	}
+	for _, namespaceNode := range resourceModel.Namespaces {
+		check(common.ObjRef{Kind: "Namespace", Name: namespaceNode.Namespace.GetName()}, namespaceNode.Labels)
+	}
+	for _, gatewayNode := range resourceModel.Gateways {
+		check(common.ObjRef{
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}, gatewayNode.Gateway.GetLabels())
+	}
+	for _, httpRouteNode := range resourceModel.HTTPRoutes {
+		check(common.ObjRef{
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}, httpRouteNode.HTTPRoute.GetLabels())
+	}
+	for _, backendNode := range resourceModel.Backends {
+		check(common.ObjRef{
+			Group:     backendNode.Backend.GroupVersionKind().Group,
+			Kind:      backendNode.Backend.GetKind(),
+			Name:      backendNode.Backend.GetName(),
+			Namespace: backendNode.Backend.GetNamespace(),
+		}, backendNode.Backend.GetLabels())
+	}
+	for _, policyNode := range resourceModel.Policies {
+		policy := policyNode.Policy.Unstructured()
+		check(common.ObjRef{
+			Group:     policy.GroupVersionKind().Group,
+			Kind:      policy.GetKind(),
+			Name:      policy.GetName(),
+			Namespace: policy.GetNamespace(),
+		}, policy.GetLabels())
+	}
+	return findings
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// FindingMetrics returns the gwctl_findings_total Metric, counting the
+// Findings by severity. Each severity is included, even without Findings.
+func FindingMetrics(findings []Finding) []resourcediscovery.Metric {
+	counts := make(map[Severity]int)
+	for _, finding := range findings {
+		counts[finding.Severity]++
+	}
+
+	var result []resourcediscovery.Metric
+	for _, severity := range []Severity{SeverityError, SeverityWarning, SeverityInfo} {
+		result = append(result, resourcediscovery.Metric{
+			Name:   "gwctl_findings_total",
+			Help:   "Number of findings reported by the analyzers.",
+			Labels: map[string]string{"severity": strings.ToLower(string(severity))},
+			Value:  float64(counts[severity]),
+		})
+	}
+	return result
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeEffectivePolicies reports effective policies of a resource which do
+// not conform to the schema of their CRD. These are recorded as errors on the
+// resource during discovery, if validation of merged policies is enabled.
+func analyzeEffectivePolicies(resourceRef common.ObjRef, nodeErrors []error) []Finding {
+	var findings []Finding
+	for _, err := range nodeErrors {
+		var invalidEffectivePolicyErr resourcediscovery.InvalidEffectivePolicyError
+		if !errors.As(err, &invalidEffectivePolicyErr) {
+			continue
+		}
+		findings = append(findings, newPolicyFinding(CodeInvalidEffectivePolicy, invalidEffectivePolicyErr.PolicyCrdID, resourceRef, invalidEffectivePolicyErr.Error()))
+	}
+	return findings
+}
+
+// analyzeDuplicatePolicies reports policies of the same kind which are directly
+// attached to the same resource, or to the same section of it. Such conflicts
+// are resolved deterministically, but are rarely intended. The finding lists
+// the policies in order of precedence, along with the policy which wins.
+func analyzeDuplicatePolicies(resourceRef common.ObjRef, policyNodes []*resourcediscovery.PolicyNode) []Finding {
+	type policyGroup struct {
+		policyCrdID policymanager.PolicyCrdID
+		sectionName string
+	}
+	policiesByGroup := make(map[policyGroup][]policymanager.Policy)
+	for _, policyNode := range policyNodes {
+		policy := *policyNode.Policy
+		group := policyGroup{policyCrdID: policy.PolicyCrdID(), sectionName: policy.SectionName()}
+		policiesByGroup[group] = append(policiesByGroup[group], policy)
+	}
+
+	var findings []Finding
+	for group, policies := range policiesByGroup {
+		if len(policies) < 2 {
+			continue
+		}
+		merged, err := policymanager.MergePoliciesOfSimilarKind(policies)
+		if err != nil {
+			continue
+		}
+		resolution := merged[group.policyCrdID].ConflictResolution()
+		if resolution == nil {
+			continue
+		}
+
+		var ordered []string
+		for _, policyRef := range resolution.Ordered {
+			ordered = append(ordered, policyRefName(policyRef))
+		}
+		attachedTo := "the resource"
+		if group.sectionName != "" {
+			attachedTo = fmt.Sprintf("section %v of the resource", group.sectionName)
+		}
+		findings = append(findings, newPolicyFinding(CodeDuplicatePolicies, group.policyCrdID, resourceRef,
+			fmt.Sprintf("%d %v policies are directly attached to %v: %v (in order of precedence); %v takes precedence",
+				len(policies), group.policyCrdID, attachedTo, strings.Join(ordered, ", "), policyRefName(resolution.Chosen))))
+	}
+	return findings
+}
+
+// analyzeUnmatchedPolicySections reports policies which target a section of
+// the HTTPRoute which matches the name of none of its rules. Such policies are
+// not applied to the HTTPRoute at all.
+func analyzeUnmatchedPolicySections(httpRouteRef common.ObjRef, httpRouteNode *resourcediscovery.HTTPRouteNode) []Finding {
+	var findings []Finding
+	for _, policyNode := range httpRouteNode.Policies {
+		policy := *policyNode.Policy
+		sectionName := policy.SectionName()
+		if sectionName == "" || slices.Contains(httpRouteNode.RuleNames, sectionName) {
+			continue
+		}
+		policyRef := policymanager.ObjRef{Namespace: policy.Unstructured().GetNamespace(), Name: policy.Unstructured().GetName()}
+		findings = append(findings, newPolicyFinding(CodeUnmatchedPolicySection, policy.PolicyCrdID(), httpRouteRef,
+			fmt.Sprintf("%v policy %v targets section %q, but no rule of the HTTPRoute has that name", policy.PolicyCrdID(), policyRefName(policyRef), sectionName)))
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Message < findings[j].Message })
+	return findings
+}
+
+// policyRefName returns namespace/name for namespaced policies and name for
+// cluster scoped ones.
+func policyRefName(policyRef policymanager.ObjRef) string {
+	if policyRef.Namespace == "" {
+		return policyRef.Name
+	}
+	return policyRef.Namespace + "/" + policyRef.Name
+}
+
+// analyzePolicyAncestorStatus reports ancestors for which an implementation
+// rejected the policy through an Accepted condition with status False. Policies
+// rejected with the reason Conflicted are reported separately, since another
+// policy takes precedence over them.
+func analyzePolicyAncestorStatus(policyNode *resourcediscovery.PolicyNode) []Finding {
+	policy := policyNode.Policy.Unstructured()
+	resourceRef := common.ObjRef{
+		Group:     policy.GroupVersionKind().Group,
+		Kind:      policy.GetKind(),
+		Name:      policy.GetName(),
+		Namespace: policy.GetNamespace(),
+	}
+
+	var findings []Finding
+	for _, ancestor := range policyNode.Policy.AncestorStatuses() {
+		ancestorRef := policymanager.AncestorRefString(ancestor.AncestorRef, policy.GetNamespace())
+		for _, condition := range ancestor.Conditions {
+			if condition.Type != string(gatewayv1alpha2.PolicyConditionAccepted) || condition.Status != metav1.ConditionFalse {
+				continue
+			}
+			code := CodePolicyNotAccepted
+			message := fmt.Sprintf("Policy is not accepted for %v by %v", ancestorRef, ancestor.ControllerName)
+			if condition.Reason == string(gatewayv1alpha2.PolicyReasonConflicted) {
+				code = CodePolicyConflicted
+				message = fmt.Sprintf("Policy conflicts with another policy for %v according to %v", ancestorRef, ancestor.ControllerName)
+			} else if condition.Reason != "" {
+				message += fmt.Sprintf(" (reason %v)", condition.Reason)
+			}
+			if condition.Message != "" {
+				message += ": " + condition.Message
+			}
+			findings = append(findings, newPolicyFinding(code, policyNode.Policy.PolicyCrdID(), resourceRef, message))
+		}
+	}
+	return findings
+}
+
+// analyzeShadowedPolicy reports an inherited policy when none of its fields
+// are reflected in the effective policies of the resources inheriting it,
+// i.e. when every one of them is overridden by other policies.
+func analyzeShadowedPolicy(policyNode *resourcediscovery.PolicyNode) []Finding {
+	if !policyNode.Policy.IsInherited() {
+		return nil
+	}
+	effectivePolicies := policyNode.InheritingEffectivePolicies()
+	if len(effectivePolicies) == 0 {
+		return nil
+	}
+	for _, effectivePolicy := range effectivePolicies {
+		reflected, inherited, err := policyNode.Policy.ReflectedFields(effectivePolicy)
+		if err != nil || inherited == 0 || len(reflected) > 0 {
+			return nil
+		}
+	}
+
+	policy := policyNode.Policy.Unstructured()
+	resourceRef := common.ObjRef{
+		Group:     policy.GroupVersionKind().Group,
+		Kind:      policy.GetKind(),
+		Name:      policy.GetName(),
+		Namespace: policy.GetNamespace(),
+	}
+	return []Finding{newPolicyFinding(CodeShadowedPolicy, policyNode.Policy.PolicyCrdID(), resourceRef, fmt.Sprintf(
+		"%v has no effect: all of its fields are overridden by other policies on each of the %d inheriting resources",
+		policy.GetKind(), len(effectivePolicies)))}
+}
+
+// analyzePolicyTargetKind reports a policy whose targetRef references a kind
+// which is not among the kinds its CRD declares it may target. Such a policy is
+// rejected by the implementation. Policies whose CRD does not declare the kinds
+// it may target are not checked.
+func analyzePolicyTargetKind(policyNode *resourcediscovery.PolicyNode) []Finding {
+	allowedKinds := policyNode.Policy.AllowedTargetKinds()
+	targetKind := policyNode.Policy.TargetRef().Kind
+	if len(allowedKinds) == 0 || targetKind == "" || slices.Contains(allowedKinds, targetKind) {
+		return nil
+	}
+
+	policy := policyNode.Policy.Unstructured()
+	resourceRef := common.ObjRef{
+		Group:     policy.GroupVersionKind().Group,
+		Kind:      policy.GetKind(),
+		Name:      policy.GetName(),
+		Namespace: policy.GetNamespace(),
+	}
+	return []Finding{newPolicyFinding(CodePolicyTargetKindNotAllowed, policyNode.Policy.PolicyCrdID(), resourceRef, fmt.Sprintf(
+		"%v targets kind %v, but its CRD only allows targeting %v",
+		policy.GetKind(), targetKind, strings.Join(allowedKinds, ", ")))}
+}
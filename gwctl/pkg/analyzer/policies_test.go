@@ -0,0 +1,679 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeEffectivePolicies(t *testing.T) {
+	healthCheckPolicy := func(name string, targetRef, defaults map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope: apiextensionsv1.ClusterScoped,
+				Group: "foo.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										// interval and intervalMilliseconds are mutually exclusive.
+										"default": {
+											Type: "object",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"interval":             {Type: "integer"},
+												"intervalMilliseconds": {Type: "integer"},
+											},
+											Not: &apiextensionsv1.JSONSchemaProps{
+												Required: []string{"interval", "intervalMilliseconds"},
+											},
+										},
+										"targetRef": {Type: "object", XPreserveUnknownFields: common.PtrTo(true)},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+				}},
+			},
+		},
+		healthCheckPolicy("health-check-gatewayclass",
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "GatewayClass", "name": "foo-gatewayclass"},
+			map[string]interface{}{"interval": int64(10)},
+		),
+	}
+
+	testcases := []struct {
+		name      string
+		targetRef map[string]interface{}
+		want      string
+	}{
+		{
+			name:      "policy of the Gateway",
+			targetRef: map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "foo-gateway", "namespace": "default"},
+			want:      `effective HealthCheckPolicy.foo.com does not conform to the CRD schema: "spec.default" must not validate the schema (not)`,
+		},
+		{
+			name:      "policy of a listener of the Gateway",
+			targetRef: map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "foo-gateway", "namespace": "default", "sectionName": "http"},
+			want:      `effective HealthCheckPolicy.foo.com of section "http" does not conform to the CRD schema: "spec.default" must not validate the schema (not)`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := append(objects, healthCheckPolicy("health-check-gateway", tc.targetRef, map[string]interface{}{"intervalMilliseconds": int64(500)}))
+
+			for _, validate := range []bool{false, true} {
+				params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+				if validate {
+					params.PolicyManager.EnableMergedPolicyValidation()
+				}
+				discoverer := resourcediscovery.Discoverer{
+					K8sClients:    params.K8sClients,
+					PolicyManager: params.PolicyManager,
+				}
+				resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+				if err != nil {
+					t.Fatalf("Failed to construct resourceModel: %v", err)
+				}
+
+				var wantFindings []Finding
+				if validate {
+					wantFindings = []Finding{newPolicyFinding(CodeInvalidEffectivePolicy, "HealthCheckPolicy.foo.com", common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}, tc.want)}
+				}
+				// The listener of the Gateway has no attached HTTPRoutes, which is
+				// reported as well but is not relevant here.
+				var got []Finding
+				for _, finding := range Analyze(resourceModel) {
+					if finding.Code == CodeInvalidEffectivePolicy {
+						got = append(got, finding)
+					}
+				}
+				if diff := cmp.Diff(wantFindings, got); diff != "" {
+					t.Errorf("Unexpected diff in Findings with validation enabled=%v (-want +got):\n%v", validate, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzePolicyAncestorStatus(t *testing.T) {
+	ancestor := func(name, acceptedStatus, reason, message string) map[string]interface{} {
+		return map[string]interface{}{
+			"ancestorRef": map[string]interface{}{
+				"group":     "gateway.networking.k8s.io",
+				"kind":      "Gateway",
+				"name":      name,
+				"namespace": "default",
+			},
+			"controllerName": "example.com/gateway-controller",
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":               "Accepted",
+					"status":             acceptedStatus,
+					"reason":             reason,
+					"message":            message,
+					"lastTransitionTime": "2024-01-01T00:00:00Z",
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-gateway",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group":     "gateway.networking.k8s.io",
+						"kind":      "Gateway",
+						"name":      "foo-gateway",
+						"namespace": "default",
+					},
+				},
+				"status": map[string]interface{}{
+					"ancestors": []interface{}{
+						ancestor("foo-gateway", "False", "Conflicted", "health-check-other takes precedence"),
+						ancestor("bar-gateway", "True", "Accepted", ""),
+						ancestor("baz-gateway", "False", "Invalid", "unsupported interval"),
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	policyNode, ok := resourceModel.Policies[resourcediscovery.PolicyID("foo.com", "HealthCheckPolicy", "", "health-check-gateway")]
+	if !ok {
+		t.Fatalf("HealthCheckPolicy health-check-gateway missing from resourceModel")
+	}
+
+	want := []Finding{
+		newPolicyFinding(CodePolicyConflicted, "HealthCheckPolicy.foo.com", common.ObjRef{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gateway"}, "Policy conflicts with another policy for Gateway default/foo-gateway according to example.com/gateway-controller: health-check-other takes precedence"),
+		newPolicyFinding(CodePolicyNotAccepted, "HealthCheckPolicy.foo.com", common.ObjRef{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gateway"}, "Policy is not accepted for Gateway default/baz-gateway by example.com/gateway-controller (reason Invalid): unsupported interval"),
+	}
+	got := analyzePolicyAncestorStatus(policyNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzeDuplicatePolicies(t *testing.T) {
+	rateLimitPolicy := func(name, creationTimestamp string, requestsPerSecond int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "RateLimitPolicy",
+				"metadata": map[string]interface{}{
+					"name":              name,
+					"namespace":         "default",
+					"creationTimestamp": creationTimestamp,
+				},
+				"spec": map[string]interface{}{
+					"requestsPerSecond": requestsPerSecond,
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ratelimitpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "ratelimitpolicies",
+					Kind:   "RateLimitPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// rate-limit-b is older, so it takes precedence despite its name.
+		rateLimitPolicy("rate-limit-a", "2024-02-01T00:00:00Z", 100),
+		rateLimitPolicy("rate-limit-b", "2024-01-01T00:00:00Z", 50),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	want := []Finding{
+		newPolicyFinding(CodeDuplicatePolicies, "RateLimitPolicy.foo.com", gatewayRef, "2 RateLimitPolicy.foo.com policies are directly attached to the resource: default/rate-limit-b, default/rate-limit-a (in order of precedence); default/rate-limit-b takes precedence"),
+	}
+	got := analyzeDuplicatePolicies(gatewayRef, common.MapToValues(resourceModel.Gateways[resourcediscovery.GatewayID("default", "foo-gateway")].Policies))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+
+	// A single policy of a kind is not reported.
+	if got := analyzeDuplicatePolicies(gatewayRef, common.MapToValues(resourceModel.Gateways[resourcediscovery.GatewayID("default", "foo-gateway")].Policies)[:1]); len(got) != 0 {
+		t.Errorf("analyzeDuplicatePolicies() with a single policy = %v; want none", got)
+	}
+}
+
+func TestAnalyzeUnmatchedPolicySections(t *testing.T) {
+	timeoutPolicy := func(name, sectionName string) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "HTTPRoute",
+			"name":  "foo-httproute",
+		}
+		if sectionName != "" {
+			targetRef["sectionName"] = sectionName
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+			},
+		},
+		timeoutPolicy("timeout-policy-httproute", ""),
+		timeoutPolicy("timeout-policy-read", "read"),
+		timeoutPolicy("timeout-policy-typo", "raed"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	httpRouteNode := resourceModel.HTTPRoutes[resourcediscovery.HTTPRouteID("default", "foo-httproute")]
+	// Rule names are not part of the structured HTTPRouteRule type, which the
+	// fake clients convert HTTPRoutes to, so they are set directly.
+	httpRouteNode.RuleNames = []string{"read", ""}
+
+	httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}
+	want := []Finding{
+		newPolicyFinding(CodeUnmatchedPolicySection, "TimeoutPolicy.bar.com", httpRouteRef, `TimeoutPolicy.bar.com policy default/timeout-policy-typo targets section "raed", but no rule of the HTTPRoute has that name`),
+	}
+	got := analyzeUnmatchedPolicySections(httpRouteRef, httpRouteNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzeShadowedPolicy(t *testing.T) {
+	healthCheckPolicy := func(name string, targetRef, defaults map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// The default of the GatewayClass is overridden on its only Gateway.
+		healthCheckPolicy("health-check-gatewayclass",
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "GatewayClass", "name": "foo-gatewayclass"},
+			map[string]interface{}{"interval": int64(10)},
+		),
+		healthCheckPolicy("health-check-gateway",
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "foo-gateway", "namespace": "default"},
+			map[string]interface{}{"interval": int64(20)},
+		),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	var got []Finding
+	for _, policyNode := range resourceModel.Policies {
+		got = append(got, analyzeShadowedPolicy(policyNode)...)
+	}
+	want := []Finding{
+		newPolicyFinding(CodeShadowedPolicy, "HealthCheckPolicy.foo.com", common.ObjRef{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gatewayclass"},
+			"HealthCheckPolicy has no effect: all of its fields are overridden by other policies on each of the 1 inheriting resources"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestAnalyzePolicyTargetKind(t *testing.T) {
+	timeoutPolicy := func(name string, targetRef map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"timeout":   int64(30),
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope: apiextensionsv1.ClusterScoped,
+				Group: "bar.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"timeout": {Type: "integer"},
+										"targetRef": {
+											Type: "object",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"group": {Type: "string"},
+												"kind": {
+													Type: "string",
+													Enum: []apiextensionsv1.JSON{{Raw: []byte(`"Gateway"`)}, {Raw: []byte(`"HTTPRoute"`)}},
+												},
+												"name":      {Type: "string"},
+												"namespace": {Type: "string"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// GatewayClass is not among the kinds allowed by the CRD.
+		timeoutPolicy("timeout-gatewayclass",
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "GatewayClass", "name": "foo-gatewayclass"},
+		),
+		timeoutPolicy("timeout-gateway",
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "foo-gateway", "namespace": "default"},
+		),
+		// The CRD of HealthCheckPolicy does not declare the kinds it may target,
+		// so its policies are not checked.
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-gatewayclass",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "GatewayClass",
+						"name":  "foo-gatewayclass",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	if len(resourceModel.Policies) != 3 {
+		t.Fatalf("Discovered %d policies; want 3", len(resourceModel.Policies))
+	}
+
+	var got []Finding
+	for _, policyNode := range resourceModel.Policies {
+		got = append(got, analyzePolicyTargetKind(policyNode)...)
+	}
+	want := []Finding{
+		newPolicyFinding(CodePolicyTargetKindNotAllowed, "TimeoutPolicy.bar.com", common.ObjRef{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-gatewayclass"},
+			"TimeoutPolicy targets kind GatewayClass, but its CRD only allows targeting Gateway, HTTPRoute"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// PolicyKindFilter scopes the Findings about policies to some kinds of
+// policies, e.g. to silence experimental kinds. Findings which are not about a
+// single kind of policy are never filtered.
+type PolicyKindFilter struct {
+	// Include lists the kinds of policies to report Findings for. Findings
+	// for all kinds are reported if it is empty.
+	Include []policymanager.PolicyCrdID
+	// Exclude lists the kinds of policies to suppress Findings for. It takes
+	// precedence over Include.
+	Exclude []policymanager.PolicyCrdID
+}
+
+// Validate returns an error if the filter lists a kind of policy which is not
+// one of the known kinds, since it would never match, which is likely a typo.
+func (f PolicyKindFilter) Validate(known []policymanager.PolicyCrdID) error {
+	for _, policyCrdID := range append(slices.Clone(f.Include), f.Exclude...) {
+		if slices.Contains(known, policyCrdID) {
+			continue
+		}
+		var knownKinds []string
+		for _, id := range known {
+			knownKinds = append(knownKinds, string(id))
+		}
+		sort.Strings(knownKinds)
+		return fmt.Errorf("unknown policy kind %q, known kinds are: %v", policyCrdID, strings.Join(knownKinds, ", "))
+	}
+	return nil
+}
+
+// Selects returns true if Findings about the kind of policy are reported.
+func (f PolicyKindFilter) Selects(policyCrdID policymanager.PolicyCrdID) bool {
+	if slices.Contains(f.Exclude, policyCrdID) {
+		return false
+	}
+	return len(f.Include) == 0 || slices.Contains(f.Include, policyCrdID)
+}
+
+// Apply returns the Findings selected by the filter, in their original order,
+// along with the number of suppressed Findings.
+func (f PolicyKindFilter) Apply(findings []Finding) ([]Finding, int) {
+	var result []Finding
+	suppressed := 0
+	for _, finding := range findings {
+		if finding.PolicyKind != "" && !f.Selects(finding.PolicyKind) {
+			suppressed++
+			continue
+		}
+		result = append(result, finding)
+	}
+	return result, suppressed
+}
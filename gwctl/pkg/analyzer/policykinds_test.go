@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+func TestPolicyKindFilter_Apply(t *testing.T) {
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	timeoutFinding := newPolicyFinding(CodeDuplicatePolicies, "TimeoutPolicy.foo.com", gatewayRef, "timeout")
+	experimentalFinding := newPolicyFinding(CodeShadowedPolicy, "ExperimentalPolicy.foo.com",
+		common.ObjRef{Group: "foo.com", Kind: "ExperimentalPolicy", Name: "experimental", Namespace: "default"}, "experimental")
+	// Findings which are not about a single kind of policy are never filtered.
+	unusedFinding := newFinding(CodeUnusedGateway, gatewayRef, "unused")
+	inertFinding := newFinding(CodeInertBackendPolicies, common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "default"}, "inert")
+	findings := []Finding{timeoutFinding, unusedFinding, experimentalFinding, inertFinding}
+
+	testcases := []struct {
+		name           string
+		filter         PolicyKindFilter
+		want           []Finding
+		wantSuppressed int
+	}{
+		{
+			name: "empty filter",
+			want: findings,
+		},
+		{
+			name:           "exclude",
+			filter:         PolicyKindFilter{Exclude: []policymanager.PolicyCrdID{"ExperimentalPolicy.foo.com"}},
+			want:           []Finding{timeoutFinding, unusedFinding, inertFinding},
+			wantSuppressed: 1,
+		},
+		{
+			name:           "include",
+			filter:         PolicyKindFilter{Include: []policymanager.PolicyCrdID{"ExperimentalPolicy.foo.com"}},
+			want:           []Finding{unusedFinding, experimentalFinding, inertFinding},
+			wantSuppressed: 1,
+		},
+		{
+			name: "exclude takes precedence over include",
+			filter: PolicyKindFilter{
+				Include: []policymanager.PolicyCrdID{"TimeoutPolicy.foo.com", "ExperimentalPolicy.foo.com"},
+				Exclude: []policymanager.PolicyCrdID{"TimeoutPolicy.foo.com"},
+			},
+			want:           []Finding{unusedFinding, experimentalFinding, inertFinding},
+			wantSuppressed: 1,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, gotSuppressed := tc.filter.Apply(findings)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+			if gotSuppressed != tc.wantSuppressed {
+				t.Errorf("Apply() suppressed %d Findings; want %d", gotSuppressed, tc.wantSuppressed)
+			}
+		})
+	}
+}
+
+func TestPolicyKindFilter_Validate(t *testing.T) {
+	known := []policymanager.PolicyCrdID{"TimeoutPolicy.foo.com", "ExperimentalPolicy.foo.com"}
+
+	testcases := []struct {
+		name    string
+		filter  PolicyKindFilter
+		wantErr bool
+	}{
+		{
+			name: "empty filter",
+		},
+		{
+			name: "known kinds",
+			filter: PolicyKindFilter{
+				Include: []policymanager.PolicyCrdID{"TimeoutPolicy.foo.com"},
+				Exclude: []policymanager.PolicyCrdID{"ExperimentalPolicy.foo.com"},
+			},
+		},
+		{
+			name:    "unknown included kind",
+			filter:  PolicyKindFilter{Include: []policymanager.PolicyCrdID{"TimeoutPolicy.bar.com"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown excluded kind",
+			filter:  PolicyKindFilter{Exclude: []policymanager.PolicyCrdID{"timeoutpolicies.foo.com"}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.filter.Validate(known)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Validate() returned err=%v; want error=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// analyzeDuplicateReferenceGrants reports each ReferenceGrant which permits
+// the same references as other ReferenceGrants, once per duplicated
+// permission.
+func analyzeDuplicateReferenceGrants(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, duplicate := range resourceModel.DuplicateReferenceGrants() {
+		for i, referenceGrant := range duplicate.ReferenceGrants {
+			var others []string
+			for j, other := range duplicate.ReferenceGrants {
+				if i != j {
+					others = append(others, fmt.Sprintf("%v/%v", other.Namespace, other.Name))
+				}
+			}
+			kind := "ReferenceGrant"
+			if len(others) > 1 {
+				kind = "ReferenceGrants"
+			}
+			findings = append(findings, newFinding(CodeDuplicateReferenceGrant, referenceGrant, fmt.Sprintf("references %v are also permitted by %v %v; consider consolidating them", duplicate.Permission, kind, strings.Join(others, ", "))))
+		}
+	}
+	return findings
+}
+
+// GrantCheckFindings reports each cross namespace reference of the checks of
+// verify-grants which is not permitted, or whose backend is not found.
+func GrantCheckFindings(checks []resourcediscovery.GrantCheck) []Finding {
+	var findings []Finding
+	for _, check := range checks {
+		if check.Passed() {
+			continue
+		}
+		if check.BackendNotFound {
+			findings = append(findings, newFinding(CodeMissingService, check.HTTPRoute, fmt.Sprintf(
+				"referenced backend %v does not exist", resourcediscovery.BackendRefString(check.Backend))))
+			continue
+		}
+		findings = append(findings, newFinding(CodeBackendReferenceNotPermitted, check.HTTPRoute, fmt.Sprintf(
+			"reference to backend %v is not permitted: %v", resourcediscovery.BackendRefString(check.Backend), check.Reason)))
+	}
+	return findings
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestAnalyzeDuplicateReferenceGrants(t *testing.T) {
+	referenceGrant := func(name string, toName *gatewayv1.ObjectName) *gatewayv1beta1.ReferenceGrant {
+		return &gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "bar"},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "foo"}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service", Name: toName}},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name    string
+		objects []runtime.Object
+		want    []Finding
+	}{
+		{
+			name: "identical ReferenceGrants are duplicates",
+			objects: []runtime.Object{
+				referenceGrant("grant-a", nil),
+				referenceGrant("grant-b", nil),
+			},
+			want: []Finding{
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-a", Namespace: "bar"}, "references from HTTPRoute in namespace foo to any Service in namespace bar are also permitted by ReferenceGrant bar/grant-b; consider consolidating them"),
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-b", Namespace: "bar"}, "references from HTTPRoute in namespace foo to any Service in namespace bar are also permitted by ReferenceGrant bar/grant-a; consider consolidating them"),
+			},
+		},
+		{
+			name: "ReferenceGrants restricted to different names are not duplicates",
+			objects: []runtime.Object{
+				referenceGrant("grant-a", common.PtrTo(gatewayv1.ObjectName("svc-1"))),
+				referenceGrant("grant-b", common.PtrTo(gatewayv1.ObjectName("svc-2"))),
+			},
+		},
+		{
+			name: "ReferenceGrant for all names overlaps those restricted to a name",
+			objects: []runtime.Object{
+				referenceGrant("grant-a", common.PtrTo(gatewayv1.ObjectName("svc-1"))),
+				referenceGrant("grant-b", common.PtrTo(gatewayv1.ObjectName("svc-2"))),
+				referenceGrant("grant-c", nil),
+			},
+			want: []Finding{
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-a", Namespace: "bar"}, "references from HTTPRoute in namespace foo to Service bar/svc-1 are also permitted by ReferenceGrant bar/grant-c; consider consolidating them"),
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-c", Namespace: "bar"}, "references from HTTPRoute in namespace foo to Service bar/svc-1 are also permitted by ReferenceGrant bar/grant-a; consider consolidating them"),
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-b", Namespace: "bar"}, "references from HTTPRoute in namespace foo to Service bar/svc-2 are also permitted by ReferenceGrant bar/grant-c; consider consolidating them"),
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-c", Namespace: "bar"}, "references from HTTPRoute in namespace foo to Service bar/svc-2 are also permitted by ReferenceGrant bar/grant-b; consider consolidating them"),
+			},
+		},
+		{
+			name: "ReferenceGrants for Secrets are duplicates",
+			objects: []runtime.Object{
+				&gatewayv1beta1.ReferenceGrant{
+					ObjectMeta: metav1.ObjectMeta{Name: "grant-a", Namespace: "bar"},
+					Spec: gatewayv1beta1.ReferenceGrantSpec{
+						From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "foo"}},
+						To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Secret"}},
+					},
+				},
+				&gatewayv1beta1.ReferenceGrant{
+					ObjectMeta: metav1.ObjectMeta{Name: "grant-b", Namespace: "bar"},
+					Spec: gatewayv1beta1.ReferenceGrantSpec{
+						From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "foo"}},
+						To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Secret", Name: common.PtrTo(gatewayv1.ObjectName("cert"))}},
+					},
+				},
+			},
+			want: []Finding{
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-a", Namespace: "bar"}, "references from Gateway in namespace foo to Secret bar/cert are also permitted by ReferenceGrant bar/grant-b; consider consolidating them"),
+				newFinding(CodeDuplicateReferenceGrant, common.ObjRef{Kind: "ReferenceGrant", Name: "grant-b", Namespace: "bar"}, "references from Gateway in namespace foo to Secret bar/cert are also permitted by ReferenceGrant bar/grant-a; consider consolidating them"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, tc.objects...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForReferenceGrant(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			got := analyzeDuplicateReferenceGrants(resourceModel)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestGrantCheckFindings(t *testing.T) {
+	httpRouteRef := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "foo"}
+	checks := []resourcediscovery.GrantCheck{
+		{
+			HTTPRoute:      httpRouteRef,
+			Backend:        common.ObjRef{Kind: "Service", Name: "bar-svc", Namespace: "bar"},
+			ReferenceGrant: &common.ObjRef{Kind: "ReferenceGrant", Name: "bar-grant", Namespace: "bar"},
+		},
+		{
+			HTTPRoute: httpRouteRef,
+			Backend:   common.ObjRef{Kind: "Service", Name: "baz-svc", Namespace: "baz"},
+			Reason:    "no ReferenceGrant permits the reference",
+		},
+	}
+
+	want := []Finding{
+		newFinding(CodeBackendReferenceNotPermitted, httpRouteRef, "reference to backend Service baz/baz-svc is not permitted: no ReferenceGrant permits the reference"),
+	}
+	got := GrantCheckFindings(checks)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+	if exitCode := ExitCodeForFindings(got, false); exitCode != utils.ExitCodeErrorFindings {
+		t.Errorf("ExitCodeForFindings() = %v, want %v", exitCode, utils.ExitCodeErrorFindings)
+	}
+	if exitCode := ExitCodeForFindings(GrantCheckFindings(checks[:1]), false); exitCode != utils.ExitCodeSuccess {
+		t.Errorf("ExitCodeForFindings() for permitted references = %v, want %v", exitCode, utils.ExitCodeSuccess)
+	}
+}
+
+func TestGrantCheckFindings_BackendNotFound(t *testing.T) {
+	httpRouteRef := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "foo"}
+	checks := []resourcediscovery.GrantCheck{
+		{
+			HTTPRoute:       httpRouteRef,
+			Backend:         common.ObjRef{Kind: "Service", Name: "missing-svc", Namespace: "bar"},
+			BackendNotFound: true,
+		},
+	}
+
+	want := []Finding{
+		newFinding(CodeMissingService, httpRouteRef, "referenced backend Service bar/missing-svc does not exist"),
+	}
+	got := GrantCheckFindings(checks)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+	if exitCode := ExitCodeForFindings(got, false); exitCode != utils.ExitCodeErrorFindings {
+		t.Errorf("ExitCodeForFindings() = %v, want %v", exitCode, utils.ExitCodeErrorFindings)
+	}
+}
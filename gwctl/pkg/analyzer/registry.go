@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// Analyzer inspects a ResourceModel and reports Findings. Besides the built-in
+// Analyzers, consumers of this package can register their own through
+// RegisterAnalyzer, e.g. to enforce rules specific to their organization.
+// Findings of custom Analyzers should use Codes which do not collide with the
+// built-in "GWCTL" Codes.
+type Analyzer interface {
+	// Name identifies the Analyzer. Names must be unique.
+	Name() string
+	// Analyze returns the Findings for the ResourceModel.
+	Analyze(*resourcediscovery.ResourceModel) []Finding
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Analyzer
+)
+
+// RegisterAnalyzer adds the Analyzer to the Analyzers run by Analyze. It is
+// meant to be called from init functions, or otherwise before running any
+// analysis. It panics if an Analyzer with the same name is already
+// registered.
+func RegisterAnalyzer(a Analyzer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, registered := range registry {
+		if registered.Name() == a.Name() {
+			panic(fmt.Sprintf("analyzer %q is already registered", a.Name()))
+		}
+	}
+	registry = append(registry, a)
+}
+
+// Analyzers returns all registered Analyzers, in the order of their
+// registration.
+func Analyzers() []Analyzer {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]Analyzer(nil), registry...)
+}
+
+// NewAnalyzer returns an Analyzer with the name which reports the Findings
+// returned by analyze.
+func NewAnalyzer(name string, analyze func(*resourcediscovery.ResourceModel) []Finding) Analyzer {
+	return funcAnalyzer{name: name, analyze: analyze}
+}
+
+type funcAnalyzer struct {
+	name    string
+	analyze func(*resourcediscovery.ResourceModel) []Finding
+}
+
+func (f funcAnalyzer) Name() string {
+	return f.name
+}
+
+func (f funcAnalyzer) Analyze(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	return f.analyze(resourceModel)
+}
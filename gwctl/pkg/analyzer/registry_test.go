@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+// edgeNamespaceAnalyzer is an organization specific Analyzer requiring all
+// Gateways to be in the edge namespace.
+type edgeNamespaceAnalyzer struct{}
+
+func (edgeNamespaceAnalyzer) Name() string {
+	return "example.com/edge-namespace"
+}
+
+func (edgeNamespaceAnalyzer) Analyze(resourceModel *resourcediscovery.ResourceModel) []Finding {
+	var findings []Finding
+	for _, gatewayNode := range resourceModel.Gateways {
+		if gatewayNode.Gateway.GetNamespace() == "edge" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Code:     "EXAMPLE001",
+			Severity: SeverityError,
+			Category: CategoryRouting,
+			ResourceRef: common.ObjRef{
+				Kind:      "Gateway",
+				Name:      gatewayNode.Gateway.GetName(),
+				Namespace: gatewayNode.Gateway.GetNamespace(),
+			},
+			Message: "Gateways must be in the edge namespace",
+		})
+	}
+	return findings
+}
+
+// withRegistry restores the registered Analyzers when the test completes.
+func withRegistry(t *testing.T) {
+	registryMu.Lock()
+	original := append([]Analyzer(nil), registry...)
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = original
+		registryMu.Unlock()
+	})
+}
+
+func TestRegisterAnalyzer(t *testing.T) {
+	withRegistry(t)
+	RegisterAnalyzer(edgeNamespaceAnalyzer{})
+
+	gateway := func(namespace string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: namespace,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80}},
+			},
+		}
+	}
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("edge"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("default"),
+		gateway("edge"),
+	}
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gatewayRef := func(namespace string) common.ObjRef {
+		return common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: namespace}
+	}
+	// Both Gateways lack HTTPRoutes, which the built-in Analyzers report
+	// alongside the Finding of the custom Analyzer.
+	want := []Finding{
+		newFinding(CodeUnusedGateway, gatewayRef("default"), "Gateway has no attached HTTPRoutes and no default backends"),
+		{
+			Code:        "EXAMPLE001",
+			Severity:    SeverityError,
+			Category:    CategoryRouting,
+			ResourceRef: gatewayRef("default"),
+			Message:     "Gateways must be in the edge namespace",
+		},
+		newFinding(CodeUnusedGateway, gatewayRef("edge"), "Gateway has no attached HTTPRoutes and no default backends"),
+	}
+	if diff := cmp.Diff(want, Analyze(resourceModel)); diff != "" {
+		t.Errorf("Analyze() diff (-want +got):\n%v", diff)
+	}
+}
+
+func TestRegisterAnalyzer_DuplicateName(t *testing.T) {
+	withRegistry(t)
+	RegisterAnalyzer(edgeNamespaceAnalyzer{})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterAnalyzer() did not panic for a duplicate name")
+		}
+	}()
+	RegisterAnalyzer(NewAnalyzer("example.com/edge-namespace", func(*resourcediscovery.ResourceModel) []Finding { return nil }))
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// RequestMatchFindings reports each request of the matches of match-test which
+// no HTTPRoute attached to the Gateway matches.
+func RequestMatchFindings(gatewayRef common.ObjRef, matches []resourcediscovery.RequestMatch) []Finding {
+	var findings []Finding
+	for _, match := range matches {
+		if match.Matched() {
+			continue
+		}
+		message := fmt.Sprintf("request %v %v%v matches no HTTPRoute", match.Request.Method, match.Request.Host, match.Request.Path)
+		if match.Err != nil {
+			message += ": " + match.Err.Error()
+		}
+		findings = append(findings, newFinding(CodeUnmatchedRequest, gatewayRef, message))
+	}
+	return findings
+}
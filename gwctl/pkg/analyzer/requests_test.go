@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestRequestMatchFindings(t *testing.T) {
+	gatewayRef := common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"}
+	matched := resourcediscovery.RequestMatch{
+		Request: resourcediscovery.TestRequest{Host: "foo.example.com", Path: "/", Method: "GET"},
+		Trace: &resourcediscovery.RequestTrace{
+			HTTPRoute: resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{}),
+		},
+	}
+	unmatched := resourcediscovery.RequestMatch{
+		Request: resourcediscovery.TestRequest{Host: "bar.example.com", Path: "/api", Method: "POST"},
+		Trace:   &resourcediscovery.RequestTrace{},
+	}
+	failed := resourcediscovery.RequestMatch{
+		Request: resourcediscovery.TestRequest{Host: "baz.example.com", Path: "/", Method: "GET"},
+		Err:     errors.New("no listener matches host baz.example.com"),
+	}
+
+	want := []Finding{
+		newFinding(CodeUnmatchedRequest, gatewayRef, "request POST bar.example.com/api matches no HTTPRoute"),
+		newFinding(CodeUnmatchedRequest, gatewayRef, "request GET baz.example.com/ matches no HTTPRoute: no listener matches host baz.example.com"),
+	}
+	got := RequestMatchFindings(gatewayRef, []resourcediscovery.RequestMatch{matched, unmatched, failed})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Findings; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+	if exitCode := ExitCodeForFindings(got, false); exitCode != utils.ExitCodeErrorFindings {
+		t.Errorf("ExitCodeForFindings() = %v, want %v", exitCode, utils.ExitCodeErrorFindings)
+	}
+	if exitCode := ExitCodeForFindings(RequestMatchFindings(gatewayRef, []resourcediscovery.RequestMatch{matched}), false); exitCode != utils.ExitCodeSuccess {
+		t.Errorf("ExitCodeForFindings() for matched requests = %v, want %v", exitCode, utils.ExitCodeSuccess)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policymanager
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// BehaviorRule names an alternative interpretation of the rules used to merge
+// policies, e.g. one introduced by a newer version of the Gateway API or of a
+// policy. Enabling a rule shows how effective policies would change when
+// upgrading to it.
+type BehaviorRule string
+
+const (
+	// BehaviorRuleNewRetrySemantics treats "retry" fields as atomic: a retry
+	// object set by a policy with a higher precedence replaces the one from a
+	// lower precedence as a whole, instead of being merged field by field.
+	BehaviorRuleNewRetrySemantics BehaviorRule = "new-retry-semantics"
+)
+
+// behaviorRuleDescriptions describes all known BehaviorRules.
+var behaviorRuleDescriptions = map[BehaviorRule]string{
+	BehaviorRuleNewRetrySemantics: "retry objects are replaced as a whole instead of being merged field by field",
+}
+
+// KnownBehaviorRules returns the names of all known BehaviorRules, sorted.
+func KnownBehaviorRules() []BehaviorRule {
+	var result []BehaviorRule
+	for rule := range behaviorRuleDescriptions {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// Description returns a short description of the behavior enabled by the rule.
+func (b BehaviorRule) Description() string {
+	return behaviorRuleDescriptions[b]
+}
+
+// MergeRules customize how policies are inherited and merged. They apply
+// either to all kinds of policies, e.g. the BehaviorRules compared by
+// diff-behavior, or to a single kind, when loaded from PolicyRules. The zero
+// value merges policies as defined by the Gateway Specification.
+type MergeRules struct {
+	// Behaviors are the BehaviorRules enabled while merging policies.
+	Behaviors []BehaviorRule `json:"behaviors,omitempty"`
+	// OverrideFields are paths of fields, relative to spec.default (e.g.
+	// "retry.attempts"), which are treated as if they were set in
+	// spec.override.
+	OverrideFields []string `json:"overrideFields,omitempty"`
+	// DefaultFields are paths of fields, relative to spec.override, which are
+	// treated as if they were set in spec.default.
+	DefaultFields []string `json:"defaultFields,omitempty"`
+	// Tiebreakers order conflicting policies attached at the same level of the
+	// hierarchy, from the first to the last criterion. Policies are ordered by
+	// their name if all criteria are equal. Defaults to CreationTimestamp and
+	// Name.
+	Tiebreakers []Tiebreaker `json:"tiebreakers,omitempty"`
+	// CrossNamespace indicates whether policies are inherited by resources in
+	// another namespace than the resource they apply to, e.g. by HTTPRoutes
+	// attached to a Gateway in another namespace. Defaults to true.
+	CrossNamespace *bool `json:"crossNamespace,omitempty"`
+}
+
+// NewMergeRules returns MergeRules with the given rules enabled. It returns an
+// error if any of the rules is unknown.
+func NewMergeRules(rules ...BehaviorRule) (MergeRules, error) {
+	result := MergeRules{Behaviors: rules}
+	if err := result.validateBehaviors(); err != nil {
+		return MergeRules{}, err
+	}
+	return result, nil
+}
+
+func (r MergeRules) validateBehaviors() error {
+	for _, rule := range r.Behaviors {
+		if _, ok := behaviorRuleDescriptions[rule]; !ok {
+			return fmt.Errorf("unknown behavior rule %q; must be one of %v", rule, KnownBehaviorRules())
+		}
+	}
+	return nil
+}
+
+// Enabled returns true if the BehaviorRule is enabled.
+func (r MergeRules) Enabled(rule BehaviorRule) bool {
+	return slices.Contains(r.Behaviors, rule)
+}
+
+// WithBehaviors returns a copy of the rules with the given BehaviorRules
+// enabled as well. r is not modified.
+func (r MergeRules) WithBehaviors(rules ...BehaviorRule) MergeRules {
+	result := r
+	result.Behaviors = append(append([]BehaviorRule{}, r.Behaviors...), rules...)
+	return result
+}
+
+// MergePoliciesOfSimilarKind is like the package level function of the same
+// name, with the rules enabled.
+func (r MergeRules) MergePoliciesOfSimilarKind(policies []Policy) (map[PolicyCrdID]Policy, error) {
+	return mergePoliciesOfSimilarKind(policies, r)
+}
+
+// MergePoliciesOfSameHierarchy is like the package level function of the same
+// name, with the rules enabled.
+func (r MergeRules) MergePoliciesOfSameHierarchy(policies1, policies2 map[PolicyCrdID]Policy) (map[PolicyCrdID]Policy, error) {
+	return mergePolicies(policies1, policies2, orderPolicyByPrecedence, r)
+}
+
+// MergePoliciesOfDifferentHierarchy is like the package level function of the
+// same name, with the rules enabled.
+func (r MergeRules) MergePoliciesOfDifferentHierarchy(parentPolicies, childPolicies map[PolicyCrdID]Policy) (map[PolicyCrdID]Policy, error) {
+	return mergePolicies(parentPolicies, childPolicies, func(a, b Policy) (Policy, Policy) { return a, b }, r)
+}
+
+// atomicFields returns the names of the fields which are replaced as a whole
+// instead of being merged.
+func (r MergeRules) atomicFields() map[string]bool {
+	result := make(map[string]bool)
+	if r.Enabled(BehaviorRuleNewRetrySemantics) {
+		result["retry"] = true
+	}
+	return result
+}
+
+// dropAtomicFields returns a copy of parent without the atomic fields which are
+// also set in patch, such that merging the patch replaces them as a whole.
+// parent is not modified.
+func dropAtomicFields(parent, patch map[string]interface{}, atomicFields map[string]bool) map[string]interface{} {
+	if len(atomicFields) == 0 {
+		return parent
+	}
+	result := make(map[string]interface{}, len(parent))
+	for key, value := range parent {
+		patchValue, ok := patch[key]
+		if !ok {
+			result[key] = value
+			continue
+		}
+		if atomicFields[key] {
+			continue
+		}
+		parentObj, parentIsObj := value.(map[string]interface{})
+		patchObj, patchIsObj := patchValue.(map[string]interface{})
+		if parentIsObj && patchIsObj {
+			result[key] = dropAtomicFields(parentObj, patchObj, atomicFields)
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
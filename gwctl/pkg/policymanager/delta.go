@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policymanager
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// FieldChange describes how a single field of the effective spec of a policy
+// changed as a result of merging policies from a hierarchy level.
+type FieldChange struct {
+	// Path is the dot separated path of the field within the effective spec,
+	// like "timeout.seconds". Lists are treated as a single value since they
+	// are always replaced as a whole when merging.
+	Path string
+	// Value is the value of the field after merging the level. It is nil if
+	// the level removed the field.
+	Value interface{}
+	// PreviousValue is the value of the field before merging the level. It is
+	// nil if the level added the field.
+	PreviousValue interface{}
+}
+
+// IsAdded returns true if the field was not set prior to the level.
+func (f FieldChange) IsAdded() bool { return f.PreviousValue == nil }
+
+// IsRemoved returns true if the field was unset by the level.
+func (f FieldChange) IsRemoved() bool { return f.Value == nil }
+
+// LevelDelta describes what a single hierarchy level contributed to the
+// effective policy of some kind.
+type LevelDelta struct {
+	// Level names the hierarchy level, e.g. "GatewayClass foo" or "Gateway
+	// default/bar".
+	Level string
+	// Policies references the policies of the kind which are attached at this
+	// level.
+	Policies []ObjRef
+	// Changes lists the fields of the effective spec which were added,
+	// overridden or removed by this level, sorted by their path.
+	Changes []FieldChange
+}
+
+// ComputeSpecDelta returns the fields which differ between the effective spec
+// of the before and after policies. A nil before policy means the after policy
+// is the first one of its kind, so all its fields are reported as added.
+func ComputeSpecDelta(before *Policy, after Policy) ([]FieldChange, error) {
+	beforeFields := make(map[string]interface{})
+	if before != nil {
+		beforeSpec, err := normalizedEffectiveSpec(*before)
+		if err != nil {
+			return nil, err
+		}
+		flattenFields("", beforeSpec, beforeFields)
+	}
+	afterSpec, err := normalizedEffectiveSpec(after)
+	if err != nil {
+		return nil, err
+	}
+	afterFields := make(map[string]interface{})
+	flattenFields("", afterSpec, afterFields)
+
+	var result []FieldChange
+	for path, value := range afterFields {
+		previousValue, ok := beforeFields[path]
+		if ok && reflect.DeepEqual(previousValue, value) {
+			continue
+		}
+		result = append(result, FieldChange{Path: path, Value: value, PreviousValue: previousValue})
+	}
+	for path, previousValue := range beforeFields {
+		if _, ok := afterFields[path]; !ok {
+			result = append(result, FieldChange{Path: path, PreviousValue: previousValue})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// ComputeSpecDeviation returns the fields which differ between the expected
+// effective spec and the effective spec of the actual policy, with
+// PreviousValue being the expected value and Value the actual one. A nil
+// actual policy means no policy of the kind applies, so all expected fields
+// are reported as missing.
+func ComputeSpecDeviation(expected map[string]interface{}, actual *Policy) ([]FieldChange, error) {
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		return nil, err
+	}
+	expectedSpec := make(map[string]interface{})
+	if err := json.Unmarshal(expectedJSON, &expectedSpec); err != nil {
+		return nil, err
+	}
+	expectedFields := make(map[string]interface{})
+	flattenFields("", expectedSpec, expectedFields)
+	actualFields := make(map[string]interface{})
+	if actual != nil {
+		actualSpec, err := normalizedEffectiveSpec(*actual)
+		if err != nil {
+			return nil, err
+		}
+		flattenFields("", actualSpec, actualFields)
+	}
+
+	var result []FieldChange
+	for path, value := range actualFields {
+		expectedValue, ok := expectedFields[path]
+		if ok && reflect.DeepEqual(expectedValue, value) {
+			continue
+		}
+		result = append(result, FieldChange{Path: path, Value: value, PreviousValue: expectedValue})
+	}
+	for path, expectedValue := range expectedFields {
+		if _, ok := actualFields[path]; !ok {
+			result = append(result, FieldChange{Path: path, PreviousValue: expectedValue})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// FlattenFields returns the leaf fields of the spec, mapped by their dot
+// separated path, like "timeout.seconds". Lists are treated as a single value.
+func FlattenFields(spec map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	flattenFields("", spec, result)
+	return result
+}
+
+// flattenFields collects all leaf fields of obj into result, keyed by their
+// dot separated path.
+func flattenFields(prefix string, obj map[string]interface{}, result map[string]interface{}) {
+	for key, value := range obj {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) != 0 {
+			flattenFields(path, nested, result)
+			continue
+		}
+		result[path] = value
+	}
+}
+
+// normalizedEffectiveSpec returns the effective spec of the policy after a
+// JSON round trip. Merged policies have already gone through such a round trip
+// (which for example converts integers into floats), so this is needed to
+// compare the effective specs of merged and unmerged policies.
+func normalizedEffectiveSpec(policy Policy) (map[string]interface{}, error) {
+	spec, err := policy.EffectiveSpec()
+	if err != nil {
+		return nil, err
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(specJSON, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReflectedFields returns the paths of the fields inherited from the policy
+// which have the same value in the effective spec of the effective policy,
+// i.e. which were not overridden by any other policy, along with the number
+// of fields inherited from the policy. Lists are compared as a whole.
+func (p Policy) ReflectedFields(effective Policy) ([]string, int, error) {
+	inheritedSpec, err := normalizedEffectiveSpec(p.InheritablePart())
+	if err != nil {
+		return nil, 0, err
+	}
+	inheritedFields := make(map[string]interface{})
+	flattenFields("", inheritedSpec, inheritedFields)
+	effectiveSpec, err := normalizedEffectiveSpec(effective)
+	if err != nil {
+		return nil, 0, err
+	}
+	effectiveFields := make(map[string]interface{})
+	flattenFields("", effectiveSpec, effectiveFields)
+
+	var result []string
+	for path, value := range inheritedFields {
+		if effectiveValue, ok := effectiveFields[path]; ok && reflect.DeepEqual(value, effectiveValue) {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result, len(inheritedFields), nil
+}
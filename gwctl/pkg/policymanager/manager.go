@@ -20,13 +20,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -41,31 +45,132 @@ type PolicyManager struct {
 	policyCRDs map[PolicyCrdID]PolicyCRD
 	// policies maps a policy name to the policy object.
 	policies map[string]Policy
+	// targetSelectorCRDs contains the kinds of policies for which attachment
+	// through spec.targetSelector is enabled.
+	targetSelectorCRDs map[PolicyCrdID]bool
+	// policyRules customize how policies of each kind are inherited and
+	// merged.
+	policyRules PolicyRules
+	// validateMergedPolicies indicates whether merged policies should be
+	// validated against the schema of their CRD.
+	validateMergedPolicies bool
+	// skipForbidden indicates whether Init skips the kinds which may not be
+	// listed, instead of failing.
+	skipForbidden bool
+	// skippedKinds maps the kinds skipped by Init to the error returned when
+	// listing them.
+	skippedKinds map[string]error
+	// policyKinds, if set, restricts the kinds of policies known to the
+	// PolicyManager.
+	policyKinds []PolicyCrdID
 }
 
 func New(dc dynamic.Interface) *PolicyManager {
 	return &PolicyManager{
-		dc:         dc,
-		policyCRDs: make(map[PolicyCrdID]PolicyCRD),
-		policies:   make(map[string]Policy),
+		dc:                 dc,
+		policyCRDs:         make(map[PolicyCrdID]PolicyCRD),
+		policies:           make(map[string]Policy),
+		targetSelectorCRDs: make(map[PolicyCrdID]bool),
+		skippedKinds:       make(map[string]error),
 	}
 }
 
+// EnableTargetSelector opts the given kinds of policies into attachment through
+// spec.targetSelector, which attaches the policy to all resources matching the
+// selector instead of the single resource referenced by spec.targetRef. This
+// must be called before Init.
+func (p *PolicyManager) EnableTargetSelector(policyCrdIDs ...PolicyCrdID) {
+	for _, policyCrdID := range policyCrdIDs {
+		p.targetSelectorCRDs[policyCrdID] = true
+	}
+}
+
+// SetPolicyRules customizes how policies of the kinds in rules are inherited
+// and merged. Kinds without rules are merged as defined by the Gateway
+// Specification. This must be called before Init.
+func (p *PolicyManager) SetPolicyRules(rules PolicyRules) {
+	p.policyRules = rules
+}
+
+// SetPolicyKinds restricts the PolicyManager to policies of the given kinds.
+// Policies of other kinds are neither fetched nor returned, and their CRDs are
+// unknown. Init fails if one of the kinds is not a known policy CRD. This must
+// be called before Init.
+func (p *PolicyManager) SetPolicyKinds(policyCrdIDs ...PolicyCrdID) {
+	p.policyKinds = policyCrdIDs
+}
+
+// EnableMergedPolicyValidation enables the validation of merged policies
+// against the OpenAPI schema of their CRD through ValidateMergedPolicy.
+func (p *PolicyManager) EnableMergedPolicyValidation() {
+	p.validateMergedPolicies = true
+}
+
+// EnableSkipForbidden makes Init skip the kinds of policies which may not be
+// listed due to missing permissions, instead of failing. If the CRDs may not
+// be listed, no policies are known at all. The skipped kinds are reported by
+// SkippedKinds. This must be called before Init.
+func (p *PolicyManager) EnableSkipForbidden() {
+	p.skipForbidden = true
+}
+
+// SkippedKinds returns the kinds which Init skipped due to missing
+// permissions, mapped to the error returned when listing them. Kinds of
+// policies are identified by their PolicyCrdID.
+func (p *PolicyManager) SkippedKinds() map[string]error {
+	return p.skippedKinds
+}
+
+// skip returns true if listing the kind failed with an error which is to be
+// skipped, in which case the kind is recorded as skipped.
+func (p *PolicyManager) skip(kind string, err error) bool {
+	if !p.skipForbidden || !apierrors.IsForbidden(err) {
+		return false
+	}
+	p.skippedKinds[kind] = err
+	return true
+}
+
+// ValidateMergedPolicy validates the spec of a policy, which is the result of
+// merging multiple policies, against the OpenAPI schema of its CRD. Merging can
+// produce a spec which none of the individual policies would have, e.g. one
+// where two mutually exclusive fields are both set by different levels of the
+// hierarchy. It returns nil if validation of merged policies is not enabled, or
+// if the CRD of the policy is unknown.
+func (p *PolicyManager) ValidateMergedPolicy(policy Policy) error {
+	if !p.validateMergedPolicies {
+		return nil
+	}
+	policyCRD, ok := p.policyCRDs[policy.PolicyCrdID()]
+	if !ok {
+		return nil
+	}
+	return policyCRD.ValidateSpec(policy)
+}
+
 // Init will construct a local cache of all Policy CRDs and Policy Resources.
 func (p *PolicyManager) Init(ctx context.Context) error {
 	allCRDs, err := fetchCRDs(ctx, p.dc)
 	if err != nil {
+		if p.skip("CustomResourceDefinitions", err) {
+			return nil
+		}
 		return err
 	}
 	for _, crd := range allCRDs {
-		policyCRD := PolicyCRD{crd}
+		policyCRD := PolicyCRD{crd: crd}
+		policyCRD.targetSelectorEnabled = p.targetSelectorCRDs[policyCRD.ID()]
+		policyCRD.rules = p.policyRules[policyCRD.ID()]
 		// Check if the CRD is a Gateway Policy CRD
 		if policyCRD.IsValid() {
 			p.policyCRDs[policyCRD.ID()] = policyCRD
 		}
 	}
+	if err := p.restrictPolicyKinds(); err != nil {
+		return err
+	}
 
-	allPolicies, err := fetchPolicies(ctx, p.dc, p.policyCRDs)
+	allPolicies, err := fetchPolicies(ctx, p.dc, p.policyCRDs, p.skip)
 	if err != nil {
 		return err
 	}
@@ -78,6 +183,31 @@ func (p *PolicyManager) Init(ctx context.Context) error {
 	return nil
 }
 
+// restrictPolicyKinds removes the CRDs whose kind is not listed in
+// p.policyKinds, if set. It returns an error if a listed kind is not a known
+// policy CRD.
+func (p *PolicyManager) restrictPolicyKinds() error {
+	if len(p.policyKinds) == 0 {
+		return nil
+	}
+	for _, policyCrdID := range p.policyKinds {
+		if _, ok := p.policyCRDs[policyCrdID]; !ok {
+			var known []string
+			for id := range p.policyCRDs {
+				known = append(known, string(id))
+			}
+			sort.Strings(known)
+			return fmt.Errorf("unknown policy kind %q, known kinds are: %v", policyCrdID, strings.Join(known, ", "))
+		}
+	}
+	for id := range p.policyCRDs {
+		if !slices.Contains(p.policyKinds, id) {
+			delete(p.policyCRDs, id)
+		}
+	}
+	return nil
+}
+
 func (p *PolicyManager) PoliciesAttachedTo(objRef ObjRef) []Policy {
 	var result []Policy
 	for _, policy := range p.policies {
@@ -133,7 +263,7 @@ func fetchCRDs(ctx context.Context, dc dynamic.Interface) ([]apiextensionsv1.Cus
 	gvr := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
 	unstructuredCRDs, err := dc.Resource(gvr).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return []apiextensionsv1.CustomResourceDefinition{}, fmt.Errorf("failed to list CRDs: %v", err)
+		return []apiextensionsv1.CustomResourceDefinition{}, fmt.Errorf("failed to list CRDs: %w", err)
 	}
 
 	crds := &apiextensionsv1.CustomResourceDefinitionList{}
@@ -145,8 +275,9 @@ func fetchCRDs(ctx context.Context, dc dynamic.Interface) ([]apiextensionsv1.Cus
 }
 
 // fetchPolicies will fetch all policy resources corresponding to the CRDs
-// present in policyCRDs.
-func fetchPolicies(ctx context.Context, dc dynamic.Interface, policyCRDs map[PolicyCrdID]PolicyCRD) ([]unstructured.Unstructured, error) {
+// present in policyCRDs. Kinds for which skip returns true on failure are
+// left out.
+func fetchPolicies(ctx context.Context, dc dynamic.Interface, policyCRDs map[PolicyCrdID]PolicyCRD, skip func(kind string, err error) bool) ([]unstructured.Unstructured, error) {
 	var result []unstructured.Unstructured
 
 	for _, policyCRD := range policyCRDs {
@@ -166,6 +297,9 @@ func fetchPolicies(ctx context.Context, dc dynamic.Interface, policyCRDs map[Pol
 			policies, err = dc.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{})
 		}
 		if err != nil {
+			if skip(string(policyCRD.ID()), err) {
+				continue
+			}
 			return result, err
 		}
 
@@ -180,6 +314,11 @@ type PolicyCrdID string
 
 type PolicyCRD struct {
 	crd apiextensionsv1.CustomResourceDefinition
+	// targetSelectorEnabled indicates whether policies of this kind can attach
+	// through spec.targetSelector.
+	targetSelectorEnabled bool
+	// rules customize how policies of this kind are inherited and merged.
+	rules MergeRules
 }
 
 func (p PolicyCRD) ClientObject() client.Object { return p.CRD() }
@@ -203,6 +342,18 @@ func (p PolicyCRD) IsDirect() bool {
 	return strings.ToLower(p.crd.GetLabels()[gatewayv1alpha2.PolicyLabelKey]) == "direct"
 }
 
+// MergeRules returns the rules customizing how policies of this kind are
+// inherited and merged.
+func (p PolicyCRD) MergeRules() MergeRules {
+	return p.rules
+}
+
+// IsTargetSelectorEnabled returns true if policies of this kind can attach
+// through spec.targetSelector.
+func (p PolicyCRD) IsTargetSelectorEnabled() bool {
+	return p.targetSelectorEnabled
+}
+
 func (p PolicyCRD) CRD() *apiextensionsv1.CustomResourceDefinition {
 	return p.crd.DeepCopy()
 }
@@ -219,9 +370,46 @@ type Policy struct {
 	// only makes sense in case of a directly-attached-policy, or an
 	// unmerged-inherited-policy.
 	targetRef ObjRef
+	// sectionName is the name of the section within the target object, e.g. a
+	// rule of an HTTPRoute, which the policy is scoped to. It is empty if the
+	// policy applies to the whole target object.
+	sectionName string
+	// targetSelector selects the target objects this policy is attached to by
+	// their labels. It is only set for policies of a kind for which attachment
+	// through spec.targetSelector has been enabled.
+	targetSelector *TargetSelector
 	// Indicates whether the policy is supposed to be "inherited" (as opposed to
 	// "direct").
 	inherited bool
+	// conflictResolution records how conflicts were resolved when this policy
+	// is the result of merging multiple policies of the same kind attached at
+	// the same level.
+	conflictResolution *ConflictResolution
+	// ancestors is the status reported by implementations for each ancestor of
+	// the policy, e.g. the Gateways through which an HTTPRoute targeted by the
+	// policy is reached.
+	ancestors []gatewayv1alpha2.PolicyAncestorStatus
+	// rules customize how policies of this kind are inherited and merged. They
+	// are never modified, so they are shared between copies of the policy.
+	rules MergeRules
+	// allowedTargetKinds are the kinds which the CRD of the policy allows it to
+	// target. It is empty if the CRD does not restrict the kind.
+	allowedTargetKinds []string
+	// specSchema is the OpenAPI schema of the spec of the policy, as declared by
+	// its CRD. It is nil if the CRD does not declare one. It is never modified,
+	// so it is shared between copies of the policy.
+	specSchema *apiextensionsv1.JSONSchemaProps
+}
+
+// ConflictResolution describes how multiple conflicting policies of the same
+// kind, attached at the same level of the hierarchy, were merged.
+type ConflictResolution struct {
+	// Chosen is the policy with the highest precedence, whose values win in
+	// case of a conflict.
+	Chosen ObjRef
+	// Ordered contains all the conflicting policies, ordered from the highest
+	// to the lowest precedence.
+	Ordered []ObjRef
 }
 
 func (p Policy) ClientObject() client.Object { return p.Unstructured() }
@@ -233,6 +421,24 @@ type ObjRef struct {
 	Namespace string `json:",omitempty"`
 }
 
+// TargetSelector selects all objects of a Group and Kind whose labels match
+// MatchLabels. For namespaced kinds, only objects within the namespace of the
+// policy are selected.
+type TargetSelector struct {
+	Group       string            `json:"group"`
+	Kind        string            `json:"kind"`
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// Matches returns true if an object of the given group and kind, and with the
+// given labels, is selected.
+func (t TargetSelector) Matches(group, kind string, objLabels map[string]string) bool {
+	if t.Group != group || t.Kind != kind {
+		return false
+	}
+	return labels.SelectorFromSet(t.MatchLabels).Matches(labels.Set(objLabels))
+}
+
 func PolicyFromUnstructured(u unstructured.Unstructured, policyCRDs map[PolicyCrdID]PolicyCRD) (Policy, error) {
 	result := Policy{u: u}
 
@@ -241,8 +447,10 @@ func PolicyFromUnstructured(u unstructured.Unstructured, policyCRDs map[PolicyCr
 		metav1.TypeMeta   `json:",inline"`
 		metav1.ObjectMeta `json:"metadata,omitempty"`
 		Spec              struct {
-			TargetRef gatewayv1alpha2.NamespacedPolicyTargetReference
+			TargetRef      gatewayv1alpha2.NamespacedPolicyTargetReference
+			TargetSelector *TargetSelector
 		}
+		Status gatewayv1alpha2.PolicyStatus
 	}
 	structuredPolicy := &genericPolicy{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), structuredPolicy); err != nil {
@@ -260,6 +468,10 @@ func PolicyFromUnstructured(u unstructured.Unstructured, policyCRDs map[PolicyCr
 	if structuredPolicy.Spec.TargetRef.Namespace != nil {
 		result.targetRef.Namespace = string(*structuredPolicy.Spec.TargetRef.Namespace)
 	}
+	// NamespacedPolicyTargetReference does not have a sectionName, so it is
+	// read from the unstructured policy.
+	result.sectionName, _, _ = unstructured.NestedString(u.Object, "spec", "targetRef", "sectionName")
+	result.ancestors = structuredPolicy.Status.Ancestors
 
 	// Get the CRD corresponding to this policy object.
 	policyCRD, ok := policyCRDs[result.PolicyCrdID()]
@@ -267,6 +479,18 @@ func PolicyFromUnstructured(u unstructured.Unstructured, policyCRDs map[PolicyCr
 		return Policy{}, fmt.Errorf("unable to find CRD corresponding to policy object")
 	}
 	result.inherited = policyCRD.IsInherited()
+	result.rules = policyCRD.rules
+	result.allowedTargetKinds = policyCRD.AllowedTargetKinds(u.GroupVersionKind().Version)
+	if specSchema, ok := policyCRD.specSchema(u.GroupVersionKind().Version); ok {
+		result.specSchema = specSchema
+	}
+
+	if policyCRD.IsTargetSelectorEnabled() && structuredPolicy.Spec.TargetSelector != nil {
+		result.targetSelector = structuredPolicy.Spec.TargetSelector
+		// The targetSelector replaces the targetRef.
+		result.targetRef = ObjRef{}
+		result.sectionName = ""
+	}
 
 	return result, nil
 }
@@ -280,10 +504,60 @@ func (p Policy) PolicyCrdID() PolicyCrdID {
 	return PolicyCrdID(p.u.GetObjectKind().GroupVersionKind().Kind + "." + p.u.GetObjectKind().GroupVersionKind().Group)
 }
 
+// MergeRules returns the rules customizing how policies of this kind are
+// inherited and merged.
+func (p Policy) MergeRules() MergeRules {
+	return p.rules
+}
+
 func (p Policy) TargetRef() ObjRef {
 	return p.targetRef
 }
 
+// AllowedTargetKinds returns the kinds which the CRD of the policy allows it
+// to target, as captured when the policy was discovered. It is empty if the
+// CRD does not restrict the kind.
+func (p Policy) AllowedTargetKinds() []string {
+	return p.allowedTargetKinds
+}
+
+// SectionName returns the name of the section within the target object, e.g.
+// a rule of an HTTPRoute, which the policy is scoped to. It is empty if the
+// policy applies to the whole target object.
+func (p Policy) SectionName() string {
+	return p.sectionName
+}
+
+// AncestorStatuses returns the status reported by implementations for each
+// ancestor of the policy.
+func (p Policy) AncestorStatuses() []gatewayv1alpha2.PolicyAncestorStatus {
+	return p.ancestors
+}
+
+// AncestorRefString returns a human readable representation of the ancestor
+// reference of a policy, e.g. "Gateway default/foo-gateway". The Kind defaults
+// to Gateway, and the namespace to that of the policy.
+func AncestorRefString(ancestorRef gatewayv1alpha2.ParentReference, policyNamespace string) string {
+	kind := "Gateway"
+	if ancestorRef.Kind != nil {
+		kind = string(*ancestorRef.Kind)
+	}
+	namespace := policyNamespace
+	if ancestorRef.Namespace != nil {
+		namespace = string(*ancestorRef.Namespace)
+	}
+	if namespace == "" {
+		return fmt.Sprintf("%v %v", kind, ancestorRef.Name)
+	}
+	return fmt.Sprintf("%v %v/%v", kind, namespace, ancestorRef.Name)
+}
+
+// TargetSelector returns the selector used to attach the policy to multiple
+// objects, or nil if the policy attaches through its targetRef.
+func (p Policy) TargetSelector() *TargetSelector {
+	return p.targetSelector
+}
+
 func (p Policy) IsInherited() bool {
 	return p.inherited
 }
@@ -292,6 +566,13 @@ func (p Policy) IsDirect() bool {
 	return !p.inherited
 }
 
+// ConflictResolution returns how conflicts were resolved if this policy is
+// the result of merging multiple policies of the same kind at the same level.
+// It returns nil otherwise.
+func (p Policy) ConflictResolution() *ConflictResolution {
+	return p.conflictResolution
+}
+
 func (p Policy) IsAttachedTo(objRef ObjRef) bool {
 	if p.targetRef.Kind == "Namespace" && p.targetRef.Name == "" {
 		p.targetRef.Name = "default"
@@ -314,9 +595,29 @@ func (p Policy) Unstructured() *unstructured.Unstructured {
 
 func (p Policy) DeepCopy() Policy {
 	clone := Policy{
-		u:         *p.u.DeepCopy(),
-		targetRef: p.targetRef,
-		inherited: p.inherited,
+		u:                  *p.u.DeepCopy(),
+		targetRef:          p.targetRef,
+		sectionName:        p.sectionName,
+		inherited:          p.inherited,
+		rules:              p.rules,
+		allowedTargetKinds: append([]string(nil), p.allowedTargetKinds...),
+		specSchema:         p.specSchema,
+	}
+	if p.targetSelector != nil {
+		targetSelector := *p.targetSelector
+		targetSelector.MatchLabels = make(map[string]string)
+		for k, v := range p.targetSelector.MatchLabels {
+			targetSelector.MatchLabels[k] = v
+		}
+		clone.targetSelector = &targetSelector
+	}
+	for _, ancestor := range p.ancestors {
+		clone.ancestors = append(clone.ancestors, *ancestor.DeepCopy())
+	}
+	if p.conflictResolution != nil {
+		conflictResolution := *p.conflictResolution
+		conflictResolution.Ordered = append([]ObjRef(nil), p.conflictResolution.Ordered...)
+		clone.conflictResolution = &conflictResolution
 	}
 	return clone
 }
@@ -339,6 +640,7 @@ func (p Policy) EffectiveSpec() (map[string]interface{}, error) {
 		// No merging is required in case of Direct policies.
 		result := p.Spec()
 		delete(result, "targetRef")
+		delete(result, "targetSelector")
 		return result, nil
 	}
 
@@ -363,7 +665,13 @@ func (p Policy) EffectiveSpec() (map[string]interface{}, error) {
 		return nil, fmt.Errorf("spec.default and spec.override must be non-scalar")
 	}
 
-	result, err := mergeUnstructured(defaultSpecNonScalar, overrideSpecNonScalar)
+	// Fields outside of spec.default and spec.override only apply to the target
+	// itself. They take precedence over the defaults, but not the overrides.
+	result, err := mergeUnstructured(defaultSpecNonScalar, p.directSpec())
+	if err != nil {
+		return nil, err
+	}
+	result, err = mergeUnstructured(result, overrideSpecNonScalar)
 	if err != nil {
 		return nil, err
 	}
@@ -371,6 +679,45 @@ func (p Policy) EffectiveSpec() (map[string]interface{}, error) {
 	return result, nil
 }
 
+// inheritableSpecFields are the fields in the spec of an Inherited policy which
+// are inherited by the descendants of its target.
+var inheritableSpecFields = []string{"default", "override"}
+
+// targetingSpecFields are the fields in the spec of a policy which select its
+// target rather than configure it.
+var targetingSpecFields = []string{"targetRef", "targetRefs", "targetSelector"}
+
+// directSpec returns the fields in the spec of an Inherited policy which are
+// not inherited and only apply to the target of the policy. It's empty for
+// policies which only declare spec.default and spec.override.
+func (p Policy) directSpec() map[string]interface{} {
+	result := p.Spec()
+	if result == nil {
+		return map[string]interface{}{}
+	}
+	for _, fields := range [][]string{inheritableSpecFields, targetingSpecFields} {
+		for _, field := range fields {
+			delete(result, field)
+		}
+	}
+	return result
+}
+
+// InheritablePart returns the part of the policy which is inherited by the
+// descendants of its target. For Inherited policies, this drops the fields of
+// the spec outside of spec.default and spec.override, which only apply to the
+// target itself. Direct policies are returned unchanged.
+func (p Policy) InheritablePart() Policy {
+	if p.IsDirect() {
+		return p
+	}
+	result := p.DeepCopy()
+	for field := range p.directSpec() {
+		unstructured.RemoveNestedField(result.u.Object, "spec", field)
+	}
+	return result
+}
+
 func (p Policy) MarshalJSON() ([]byte, error) {
 	effectiveSpec, err := p.EffectiveSpec()
 	if err != nil {
@@ -19,6 +19,7 @@ package policymanager
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -27,53 +28,75 @@ import (
 // MergePoliciesOfSimilarKind will convert a slice a policies to a map of
 // policies by merging policies of similar kind. The returned map will have the
 // policy kind as the key.
+//
+// Conflicting policies of the same kind are merged in the order defined by the
+// [Gateway Specification]: the oldest policy wins, and if the creation
+// timestamps are equal, the policy appearing first in alphabetical order of
+// namespace/name wins. The outcome is recorded in the ConflictResolution of
+// the merged policy.
+//
+// [Gateway Specification]: https://gateway-api.sigs.k8s.io/geps/gep-713/#conflict-resolution
 func MergePoliciesOfSimilarKind(policies []Policy) (map[PolicyCrdID]Policy, error) {
-	result := make(map[PolicyCrdID]Policy)
+	return mergePoliciesOfSimilarKind(policies, MergeRules{})
+}
+
+func mergePoliciesOfSimilarKind(policies []Policy, rules MergeRules) (map[PolicyCrdID]Policy, error) {
+	policiesByKind := make(map[PolicyCrdID][]Policy)
 	for _, policy := range policies {
 		policyCrdID := policy.PolicyCrdID()
+		policiesByKind[policyCrdID] = append(policiesByKind[policyCrdID], policy)
+	}
 
-		if _, ok := result[policyCrdID]; !ok {
-			// Policy of kind policyCrdID doesn't already exist so simply insert it
-			// into the resulting map.
-			result[policyCrdID] = policy
+	result := make(map[PolicyCrdID]Policy)
+	for policyCrdID, conflictingPolicies := range policiesByKind {
+		if len(conflictingPolicies) == 1 {
+			result[policyCrdID] = conflictingPolicies[0]
 			continue
 		}
 
-		// At this point, we know that a policy of kind policyCrdID already exists
-		// so we need to merge the new policy with the existing one.
-
-		// Merge existing policy with new policy. Reuse existing function to merge
-		// policies of similar hierarchy.
-		mergedPolicies, err := MergePoliciesOfSameHierarchy(
-			map[PolicyCrdID]Policy{
-				policyCrdID: result[policyCrdID], // Existing policy.
-			},
-			map[PolicyCrdID]Policy{
-				policyCrdID: policy, // New policy.
-			},
-		)
-		if err != nil {
-			return nil, err
+		// Sort policies from the highest to the lowest precedence.
+		sorted := make([]Policy, len(conflictingPolicies))
+		copy(sorted, conflictingPolicies)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return hasHigherPrecedence(sorted[i], sorted[j])
+		})
+
+		// Merge policies starting from the lowest precedence, such that a policy
+		// with a higher precedence is always merged on top of the ones with a
+		// lower precedence.
+		merged := sorted[len(sorted)-1]
+		for i := len(sorted) - 2; i >= 0; i-- {
+			var err error
+			merged, err = mergePolicy(merged, sorted[i], rules)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		result[policyCrdID] = mergedPolicies[policyCrdID]
+		resolution := &ConflictResolution{Chosen: objRefOfPolicy(sorted[0])}
+		for _, policy := range sorted {
+			resolution.Ordered = append(resolution.Ordered, objRefOfPolicy(policy))
+		}
+		merged.conflictResolution = resolution
+		result[policyCrdID] = merged
 	}
 	return result, nil
 }
 
 func MergePoliciesOfSameHierarchy(policies1, policies2 map[PolicyCrdID]Policy) (map[PolicyCrdID]Policy, error) {
-	return mergePolicies(policies1, policies2, orderPolicyByPrecedence)
+	return mergePolicies(policies1, policies2, orderPolicyByPrecedence, MergeRules{})
 }
 
 func MergePoliciesOfDifferentHierarchy(parentPolicies, childPolicies map[PolicyCrdID]Policy) (map[PolicyCrdID]Policy, error) {
-	return mergePolicies(parentPolicies, childPolicies, func(a, b Policy) (Policy, Policy) { return a, b })
+	return mergePolicies(parentPolicies, childPolicies, func(a, b Policy) (Policy, Policy) { return a, b }, MergeRules{})
 }
 
 // mergePolicies will merge policies which are partitioned by their Kind.
 //
 // precedence function will order two policies such that the second policy
-// returned will have a higher precedence.
-func mergePolicies(policies1, policies2 map[PolicyCrdID]Policy, precedence func(a, b Policy) (Policy, Policy)) (map[PolicyCrdID]Policy, error) {
+// returned will have a higher precedence. rules select alternative merging
+// behaviors.
+func mergePolicies(policies1, policies2 map[PolicyCrdID]Policy, precedence func(a, b Policy) (Policy, Policy), rules MergeRules) (map[PolicyCrdID]Policy, error) {
 	result := make(map[PolicyCrdID]Policy)
 
 	// Copy policies1 into result.
@@ -95,7 +118,7 @@ func mergePolicies(policies1, policies2 map[PolicyCrdID]Policy, precedence func(
 
 		lowerPolicy, higherPolicy := precedence(existingPolicy, policy)
 
-		res, err := mergePolicy(lowerPolicy, higherPolicy)
+		res, err := mergePolicy(lowerPolicy, higherPolicy, rules)
 		if err != nil {
 			return nil, err
 		}
@@ -109,13 +132,33 @@ func mergePolicies(policies1, policies2 map[PolicyCrdID]Policy, precedence func(
 //     child.
 //   - defaults from child will take precedence over the defaults from the
 //     parent.
-func mergePolicy(parent, child Policy) (Policy, error) {
+//   - fields made atomic by the rules are replaced as a whole instead of being
+//     merged.
+func mergePolicy(parent, child Policy, rules MergeRules) (Policy, error) {
 	// Only policies of similar kind can be merged.
 	if parent.PolicyCrdID() != child.PolicyCrdID() {
 		return Policy{}, fmt.Errorf("cannot merge policies of different kind; kind1=%v, kind2=%v", parent.PolicyCrdID(), child.PolicyCrdID())
 	}
 
-	resultUnstructured, err := mergeUnstructured(parent.u.UnstructuredContent(), child.u.UnstructuredContent())
+	// The rules apply in addition to the MergeRules of the kind. Fields which
+	// these move between spec.default and spec.override are moved before
+	// merging.
+	rules = child.rules.WithBehaviors(rules.Behaviors...)
+	parentContent, childContent := parent.u.UnstructuredContent(), child.u.UnstructuredContent()
+	var err error
+	if parent.IsInherited() {
+		if parentContent, err = rules.applyFieldRules(parentContent); err != nil {
+			return Policy{}, err
+		}
+	}
+	if child.IsInherited() {
+		if childContent, err = rules.applyFieldRules(childContent); err != nil {
+			return Policy{}, err
+		}
+	}
+
+	atomicFields := rules.atomicFields()
+	resultUnstructured, err := mergeUnstructured(dropAtomicFields(parentContent, childContent, atomicFields), childContent)
 	if err != nil {
 		return Policy{}, err
 	}
@@ -124,7 +167,7 @@ func mergePolicy(parent, child Policy) (Policy, error) {
 		// In case of an Inherited policy, the "spec.override" field of the parent
 		// should take precedence over the child. So we patch the override field
 		// from the parent into the result.
-		override, ok, err := unstructured.NestedFieldCopy(parent.u.UnstructuredContent(), "spec", "override")
+		override, ok, err := unstructured.NestedFieldCopy(parentContent, "spec", "override")
 		if err != nil {
 			return Policy{}, err
 		}
@@ -132,11 +175,12 @@ func mergePolicy(parent, child Policy) (Policy, error) {
 		// nothing to do in that case. On the other hand, ok=true means
 		// "spec.override" field exists so we override the value of the parent.
 		if ok {
-			resultUnstructured, err = mergeUnstructured(resultUnstructured, map[string]interface{}{
+			overridePatch := map[string]interface{}{
 				"spec": map[string]interface{}{
 					"override": override,
 				},
-			})
+			}
+			resultUnstructured, err = mergeUnstructured(dropAtomicFields(resultUnstructured, overridePatch, atomicFields), overridePatch)
 			if err != nil {
 				return Policy{}, err
 			}
@@ -146,8 +190,11 @@ func mergePolicy(parent, child Policy) (Policy, error) {
 	result := child.DeepCopy()
 	result.u.SetUnstructuredContent(resultUnstructured)
 	// Merging two policies means the targetRef no longer makes any sense since
-	// since they can be conflicting. So we unset the targetRef.
+	// since they can be conflicting. So we unset the targetRef. The same applies
+	// to the targetSelector.
 	result.targetRef = ObjRef{}
+	result.targetSelector = nil
+	result.conflictResolution = nil
 	return result, nil
 }
 
@@ -184,19 +231,44 @@ func orderPolicyByPrecedence(a, b Policy) (Policy, Policy) {
 	lowerPolicy := a.DeepCopy()  // lowerPolicy will have lower precedence.
 	higherPolicy := b.DeepCopy() // higherPolicy will have higher precedence.
 
-	if lowerPolicy.u.GetCreationTimestamp() == higherPolicy.u.GetCreationTimestamp() {
-		// Policies have the same creation time, so precedence is decided based
-		// on alphabetical ordering.
-		higherNN := fmt.Sprintf("%v/%v", higherPolicy.u.GetNamespace(), higherPolicy.u.GetName())
-		lowerNN := fmt.Sprintf("%v/%v", lowerPolicy.u.GetNamespace(), lowerPolicy.u.GetName())
-		if higherNN > lowerNN {
-			higherPolicy, lowerPolicy = lowerPolicy, higherPolicy
-		}
-	} else if higherPolicy.u.GetCreationTimestamp().Time.After(lowerPolicy.u.GetCreationTimestamp().Time) {
-		// Policies have difference creation time, so this will decide the precedence
+	if hasHigherPrecedence(lowerPolicy, higherPolicy) {
 		higherPolicy, lowerPolicy = lowerPolicy, higherPolicy
 	}
 
 	// At this point, higherPolicy will have precedence over lowerPolicy.
 	return lowerPolicy, higherPolicy
 }
+
+// hasHigherPrecedence returns true if policy a takes precedence over policy b.
+// By default, the older policy has a higher precedence. If both policies have
+// the same creation time, precedence is decided based on alphabetical ordering
+// of their namespace/name. The MergeRules of the kind may reorder these
+// tiebreakers.
+func hasHigherPrecedence(a, b Policy) bool {
+	for _, tiebreaker := range a.rules.tiebreakers() {
+		switch tiebreaker {
+		case TiebreakerCreationTimestamp:
+			aTime, bTime := a.u.GetCreationTimestamp().Time, b.u.GetCreationTimestamp().Time
+			if !aTime.Equal(bTime) {
+				return aTime.Before(bTime)
+			}
+		case TiebreakerName:
+			aNN := fmt.Sprintf("%v/%v", a.u.GetNamespace(), a.u.GetName())
+			bNN := fmt.Sprintf("%v/%v", b.u.GetNamespace(), b.u.GetName())
+			if aNN != bNN {
+				return aNN < bNN
+			}
+		}
+	}
+	return false
+}
+
+func objRefOfPolicy(policy Policy) ObjRef {
+	gvk := policy.u.GroupVersionKind()
+	return ObjRef{
+		Group:     gvk.Group,
+		Kind:      gvk.Kind,
+		Name:      policy.u.GetName(),
+		Namespace: policy.u.GetNamespace(),
+	}
+}
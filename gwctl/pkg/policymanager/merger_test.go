@@ -140,6 +140,13 @@ func TestMergePoliciesOfSimilarKind(t *testing.T) {
 				},
 			},
 			inherited: true,
+			conflictResolution: &ConflictResolution{
+				Chosen: ObjRef{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-1"},
+				Ordered: []ObjRef{
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-1"},
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-2"},
+				},
+			},
 		},
 		PolicyCrdID("TimeoutPolicy.bar.com"): {
 			u: unstructured.Unstructured{
@@ -159,6 +166,13 @@ func TestMergePoliciesOfSimilarKind(t *testing.T) {
 					},
 				},
 			},
+			conflictResolution: &ConflictResolution{
+				Chosen: ObjRef{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-1"},
+				Ordered: []ObjRef{
+					{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-1"},
+					{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-2"},
+				},
+			},
 		},
 	}
 
@@ -174,6 +188,65 @@ func TestMergePoliciesOfSimilarKind(t *testing.T) {
 	}
 }
 
+func TestMergePoliciesOfSimilarKind_OldestWins(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	newer := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+
+	newTimeoutPolicy := func(name, creationTimestamp string, seconds float64) Policy {
+		return Policy{
+			u: unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "bar.com/v1",
+					"kind":       "TimeoutPolicy",
+					"metadata": map[string]interface{}{
+						"name":              name,
+						"namespace":         "default",
+						"creationTimestamp": creationTimestamp,
+					},
+					"spec": map[string]interface{}{
+						"seconds": seconds,
+					},
+				},
+			},
+		}
+	}
+
+	// The newer policy is alphabetically first, so the older policy only wins
+	// if the creation timestamp is considered first.
+	policies := []Policy{
+		newTimeoutPolicy("timeout-policy-b", older, 30),
+		newTimeoutPolicy("timeout-policy-a", newer, 60),
+	}
+
+	// The result must not depend on the order in which the policies are listed.
+	for _, ordering := range [][]Policy{policies, {policies[1], policies[0]}} {
+		got, err := MergePoliciesOfSimilarKind(ordering)
+		if err != nil {
+			t.Fatalf("MergePoliciesOfSimilarKind returned err=%v; want no error", err)
+		}
+
+		merged := got[PolicyCrdID("TimeoutPolicy.bar.com")]
+		if gotName := merged.Unstructured().GetName(); gotName != "timeout-policy-b" {
+			t.Errorf("MergePoliciesOfSimilarKind returned policy with name=%q; want %q", gotName, "timeout-policy-b")
+		}
+		gotSeconds, _, _ := unstructured.NestedFloat64(merged.Spec(), "seconds")
+		if gotSeconds != 30 {
+			t.Errorf("MergePoliciesOfSimilarKind returned policy with seconds=%v; want 30", gotSeconds)
+		}
+
+		wantConflictResolution := &ConflictResolution{
+			Chosen: ObjRef{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-b", Namespace: "default"},
+			Ordered: []ObjRef{
+				{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-b", Namespace: "default"},
+				{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-a", Namespace: "default"},
+			},
+		}
+		if diff := cmp.Diff(wantConflictResolution, merged.ConflictResolution()); diff != "" {
+			t.Errorf("ConflictResolution() returned unexpected diff (-want, +got):\n%v", diff)
+		}
+	}
+}
+
 func TestMergePoliciesOfDifferentHierarchy(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policymanager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// Tiebreaker names a criterion used to order conflicting policies of the same
+// kind attached at the same level of the hierarchy.
+type Tiebreaker string
+
+const (
+	// TiebreakerCreationTimestamp gives the older policy a higher precedence.
+	TiebreakerCreationTimestamp Tiebreaker = "CreationTimestamp"
+	// TiebreakerName gives the policy appearing first in alphabetical order of
+	// namespace/name a higher precedence.
+	TiebreakerName Tiebreaker = "Name"
+)
+
+// defaultTiebreakers are the tiebreakers defined by the [Gateway
+// Specification].
+//
+// [Gateway Specification]: https://gateway-api.sigs.k8s.io/geps/gep-713/#conflict-resolution
+var defaultTiebreakers = []Tiebreaker{TiebreakerCreationTimestamp, TiebreakerName}
+
+// PolicyRules maps kinds of policies to the MergeRules applying to them. Kinds
+// without rules are merged as defined by the Gateway Specification.
+type PolicyRules map[PolicyCrdID]MergeRules
+
+// LoadPolicyRules reads PolicyRules from the YAML file at path, e.g.
+//
+//	TimeoutPolicy.bar.com:
+//	  overrideFields: [timeout]
+//	  tiebreakers: [Name]
+//	  crossNamespace: false
+//	  behaviors: [new-retry-semantics]
+func LoadPolicyRules(path string) (PolicyRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy rules: %w", err)
+	}
+	return ParsePolicyRules(data)
+}
+
+// ParsePolicyRules parses PolicyRules from YAML. See LoadPolicyRules.
+func ParsePolicyRules(data []byte) (PolicyRules, error) {
+	result := make(PolicyRules)
+	if err := yaml.UnmarshalStrict(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse policy rules: %w", err)
+	}
+	for policyCrdID, rules := range result {
+		if err := rules.validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy rules for %v: %w", policyCrdID, err)
+		}
+	}
+	return result, nil
+}
+
+func (r MergeRules) validate() error {
+	if err := r.validateBehaviors(); err != nil {
+		return err
+	}
+	for _, tiebreaker := range r.Tiebreakers {
+		if tiebreaker != TiebreakerCreationTimestamp && tiebreaker != TiebreakerName {
+			return fmt.Errorf("unknown tiebreaker %q; must be one of %v", tiebreaker, defaultTiebreakers)
+		}
+	}
+	overrideFields := make(map[string]bool)
+	for _, field := range r.OverrideFields {
+		overrideFields[field] = true
+	}
+	for _, field := range r.DefaultFields {
+		if overrideFields[field] {
+			return fmt.Errorf("field %q can not be both an override and a default field", field)
+		}
+	}
+	return nil
+}
+
+// PropagatesAcrossNamespaces returns true if policies are inherited by
+// resources in another namespace than the resource they apply to.
+func (r MergeRules) PropagatesAcrossNamespaces() bool {
+	return r.CrossNamespace == nil || *r.CrossNamespace
+}
+
+func (r MergeRules) tiebreakers() []Tiebreaker {
+	if len(r.Tiebreakers) == 0 {
+		return defaultTiebreakers
+	}
+	return append(append([]Tiebreaker{}, r.Tiebreakers...), TiebreakerName)
+}
+
+// applyFieldRules returns a copy of the content of an Inherited policy, with
+// the fields listed in OverrideFields moved from spec.default to
+// spec.override, and the fields listed in DefaultFields moved the other way. A
+// field set in both sections keeps the value from spec.override, which takes
+// precedence within a policy. content is not modified.
+func (r MergeRules) applyFieldRules(content map[string]interface{}) (map[string]interface{}, error) {
+	if len(r.OverrideFields) == 0 && len(r.DefaultFields) == 0 {
+		return content, nil
+	}
+	result := runtime.DeepCopyJSON(content)
+	for _, field := range r.OverrideFields {
+		if err := moveField(result, "default", "override", field, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range r.DefaultFields {
+		if err := moveField(result, "override", "default", field, true); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// moveField moves the field at the dotted path from spec.<from> to spec.<to>.
+// If the field is set in both sections, the value from spec.<from> replaces
+// the existing one if replace is true, and is dropped otherwise.
+func moveField(content map[string]interface{}, from, to, path string, replace bool) error {
+	fields := strings.Split(path, ".")
+	fromPath := append([]string{"spec", from}, fields...)
+	toPath := append([]string{"spec", to}, fields...)
+
+	value, ok, err := unstructured.NestedFieldNoCopy(content, fromPath...)
+	if err != nil || !ok {
+		return err
+	}
+	unstructured.RemoveNestedField(content, fromPath...)
+	if _, exists, _ := unstructured.NestedFieldNoCopy(content, toPath...); exists && !replace {
+		return nil
+	}
+	return unstructured.SetNestedField(content, value, toPath...)
+}
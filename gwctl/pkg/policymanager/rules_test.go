@@ -0,0 +1,294 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policymanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestPolicyManager_SetPolicyRules(t *testing.T) {
+	healthCheckPolicyCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "healthcheckpolicies.foo.com",
+			Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Scope:    apiextensionsv1.NamespaceScoped,
+			Group:    "foo.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: "healthcheckpolicies",
+				Kind:   "HealthCheckPolicy",
+			},
+		},
+	}
+	healthCheckPolicy := func(name, targetKind string, interval int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  "foo",
+					},
+					"default": map[string]interface{}{
+						"interval": interval,
+						"timeout":  int64(5),
+					},
+				},
+			},
+		}
+	}
+
+	// Merged policies are round-tripped through JSON, so numbers are float64.
+	testcases := []struct {
+		name        string
+		policyRules string
+		want        map[string]interface{}
+	}{
+		{
+			name: "no rules",
+			// The defaults of the HTTPRoute take precedence over those of the
+			// Gateway.
+			want: map[string]interface{}{"interval": float64(20), "timeout": float64(5)},
+		},
+		{
+			name: "interval is an override",
+			policyRules: `
+HealthCheckPolicy.foo.com:
+  overrideFields: [interval]
+`,
+			// The interval of the Gateway now overrides the one of the HTTPRoute.
+			want: map[string]interface{}{"interval": float64(10), "timeout": float64(5)},
+		},
+		{
+			name: "rules of another kind",
+			policyRules: `
+TimeoutPolicy.bar.com:
+  overrideFields: [interval]
+`,
+			want: map[string]interface{}{"interval": float64(20), "timeout": float64(5)},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClients := common.MustClientsForTest(t,
+				healthCheckPolicyCRD,
+				healthCheckPolicy("health-check-gateway", "Gateway", 10),
+				healthCheckPolicy("health-check-httproute", "HTTPRoute", 20),
+			)
+			policyManager := New(k8sClients.DC)
+			if tc.policyRules != "" {
+				path := filepath.Join(t.TempDir(), "policy-rules.yaml")
+				if err := os.WriteFile(path, []byte(tc.policyRules), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				policyRules, err := LoadPolicyRules(path)
+				if err != nil {
+					t.Fatalf("LoadPolicyRules returned err=%v; want no error", err)
+				}
+				policyManager.SetPolicyRules(policyRules)
+			}
+			if err := policyManager.Init(context.Background()); err != nil {
+				t.Fatalf("Init returned err=%v; want no error", err)
+			}
+
+			parent, ok := policyManager.GetPolicy("default/health-check-gateway")
+			if !ok {
+				t.Fatalf("GetPolicy(default/health-check-gateway) found no policy")
+			}
+			child, ok := policyManager.GetPolicy("default/health-check-httproute")
+			if !ok {
+				t.Fatalf("GetPolicy(default/health-check-httproute) found no policy")
+			}
+			merged, err := MergePoliciesOfDifferentHierarchy(
+				map[PolicyCrdID]Policy{parent.PolicyCrdID(): parent},
+				map[PolicyCrdID]Policy{child.PolicyCrdID(): child},
+			)
+			if err != nil {
+				t.Fatalf("MergePoliciesOfDifferentHierarchy returned err=%v; want no error", err)
+			}
+			got, err := merged[parent.PolicyCrdID()].EffectiveSpec()
+			if err != nil {
+				t.Fatalf("EffectiveSpec returned err=%v; want no error", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected effective spec; diff (-want +got)=\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestParsePolicyRules_Invalid(t *testing.T) {
+	testcases := []struct {
+		name        string
+		policyRules string
+	}{
+		{
+			name: "unknown tiebreaker",
+			policyRules: `
+HealthCheckPolicy.foo.com:
+  tiebreakers: [Age]
+`,
+		},
+		{
+			name: "field is both an override and a default",
+			policyRules: `
+HealthCheckPolicy.foo.com:
+  overrideFields: [interval]
+  defaultFields: [interval]
+`,
+		},
+		{
+			name: "unknown behavior rule",
+			policyRules: `
+HealthCheckPolicy.foo.com:
+  behaviors: [old-retry-semantics]
+`,
+		},
+		{
+			name: "unknown rule",
+			policyRules: `
+HealthCheckPolicy.foo.com:
+  mergeStrategy: atomic
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParsePolicyRules([]byte(tc.policyRules)); err == nil {
+				t.Errorf("ParsePolicyRules returned no error; want error")
+			}
+		})
+	}
+}
+
+func TestPolicyManager_SetPolicyRules_Behaviors(t *testing.T) {
+	retryPolicyCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "retrypolicies.foo.com",
+			Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Scope:    apiextensionsv1.NamespaceScoped,
+			Group:    "foo.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: "retrypolicies",
+				Kind:   "RetryPolicy",
+			},
+		},
+	}
+	retryPolicy := func(name, targetKind string, retry map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "RetryPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  "foo",
+					},
+					"default": map[string]interface{}{
+						"retry": retry,
+					},
+				},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name        string
+		policyRules PolicyRules
+		want        map[string]interface{}
+	}{
+		{
+			name: "no rules",
+			// The retry of the HTTPRoute is merged field by field with the one of
+			// the Gateway.
+			want: map[string]interface{}{"retry": map[string]interface{}{"attempts": float64(5), "backoff": "1s"}},
+		},
+		{
+			name:        "new retry semantics",
+			policyRules: PolicyRules{"RetryPolicy.foo.com": {Behaviors: []BehaviorRule{BehaviorRuleNewRetrySemantics}}},
+			// The retry of the HTTPRoute replaces the one of the Gateway.
+			want: map[string]interface{}{"retry": map[string]interface{}{"attempts": float64(5)}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClients := common.MustClientsForTest(t,
+				retryPolicyCRD,
+				retryPolicy("retry-gateway", "Gateway", map[string]interface{}{"attempts": int64(3), "backoff": "1s"}),
+				retryPolicy("retry-httproute", "HTTPRoute", map[string]interface{}{"attempts": int64(5)}),
+			)
+			policyManager := New(k8sClients.DC)
+			policyManager.SetPolicyRules(tc.policyRules)
+			if err := policyManager.Init(context.Background()); err != nil {
+				t.Fatalf("Init returned err=%v; want no error", err)
+			}
+
+			parent, ok := policyManager.GetPolicy("default/retry-gateway")
+			if !ok {
+				t.Fatalf("GetPolicy(default/retry-gateway) found no policy")
+			}
+			child, ok := policyManager.GetPolicy("default/retry-httproute")
+			if !ok {
+				t.Fatalf("GetPolicy(default/retry-httproute) found no policy")
+			}
+			merged, err := MergePoliciesOfDifferentHierarchy(
+				map[PolicyCrdID]Policy{parent.PolicyCrdID(): parent},
+				map[PolicyCrdID]Policy{child.PolicyCrdID(): child},
+			)
+			if err != nil {
+				t.Fatalf("MergePoliciesOfDifferentHierarchy returned err=%v; want no error", err)
+			}
+			got, err := merged[parent.PolicyCrdID()].EffectiveSpec()
+			if err != nil {
+				t.Fatalf("EffectiveSpec returned err=%v; want no error", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected effective spec; diff (-want +got)=\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policymanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// specSchema returns the OpenAPI schema of the spec field for the given version
+// of the CRD. If the version is not served by the CRD, the schema of the first
+// version is used. The returned bool is false if the CRD does not define a
+// schema for the spec.
+func (p PolicyCRD) specSchema(version string) (*apiextensionsv1.JSONSchemaProps, bool) {
+	if len(p.crd.Spec.Versions) == 0 {
+		return nil, false
+	}
+	crdVersion := p.crd.Spec.Versions[0]
+	for _, v := range p.crd.Spec.Versions {
+		if v.Name == version {
+			crdVersion = v
+			break
+		}
+	}
+	if crdVersion.Schema == nil || crdVersion.Schema.OpenAPIV3Schema == nil {
+		return nil, false
+	}
+	specSchema, ok := crdVersion.Schema.OpenAPIV3Schema.Properties["spec"]
+	if !ok {
+		return nil, false
+	}
+	return &specSchema, true
+}
+
+// ValidateSpec validates the spec of the policy against the OpenAPI schema of
+// the CRD. It returns nil if the CRD does not define a schema for the spec.
+func (p PolicyCRD) ValidateSpec(policy Policy) error {
+	specSchema, ok := p.specSchema(policy.u.GroupVersionKind().Version)
+	if !ok {
+		return nil
+	}
+
+	// The JSON representation of the CRD schema is a valid OpenAPI schema, so
+	// converting through JSON avoids depending on the apiextensions-apiserver
+	// internals.
+	b, err := json.Marshal(specSchema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema of %v: %v", p.ID(), err)
+	}
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(b, schema); err != nil {
+		return fmt.Errorf("failed to unmarshal schema of %v: %v", p.ID(), err)
+	}
+
+	validator := validate.NewSchemaValidator(schema, nil, "spec", strfmt.Default)
+	result := validator.Validate(policy.Spec())
+	if result.IsValid() {
+		return nil
+	}
+	var messages []string
+	for _, err := range result.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Errorf("%v", strings.Join(messages, "; "))
+}
+
+// AllowedTargetKinds returns the kinds which policies of this CRD may target,
+// as declared by the enum of spec.targetRef.kind in the schema of the given
+// version of the CRD. It returns nil if the CRD does not restrict the kind.
+func (p PolicyCRD) AllowedTargetKinds(version string) []string {
+	specSchema, ok := p.specSchema(version)
+	if !ok {
+		return nil
+	}
+	targetRefSchema, ok := specSchema.Properties["targetRef"]
+	if !ok {
+		return nil
+	}
+	kindSchema, ok := targetRefSchema.Properties["kind"]
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, value := range kindSchema.Enum {
+		var kind string
+		if err := json.Unmarshal(value.Raw, &kind); err != nil {
+			continue
+		}
+		result = append(result, kind)
+	}
+	return result
+}
+
+// SpecField is a field within the spec of a policy, paired with its
+// description from the schema of the CRD.
+type SpecField struct {
+	// Path is the path of the field within the spec, e.g. "default.timeout"
+	// or "rules[0].name".
+	Path string
+	// Value is the value of the field. It is nil for objects and lists of
+	// objects, whose fields are listed separately.
+	Value interface{}
+	// Description is the description of the field in the schema of the CRD. It
+	// is empty if the schema does not describe the field.
+	Description string
+}
+
+// SpecFields returns the fields set within the spec of the policy, depth first
+// and in the order of their names, each along with its description from the
+// schema of the CRD captured when the policy was discovered. Descriptions are
+// empty if the CRD does not declare a schema.
+func (p Policy) SpecFields() []SpecField {
+	var result []SpecField
+	walkSpecFields(p.Spec(), p.specSchema, "", &result)
+	return result
+}
+
+// walkSpecFields appends the fields of value, whose schema is schema (or nil
+// if unknown), to result. path is the path of value within the spec.
+func walkSpecFields(value interface{}, schema *apiextensionsv1.JSONSchemaProps, path string, result *[]SpecField) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			fieldSchema := propertySchema(schema, key)
+			field := SpecField{Path: fieldPath, Description: description(fieldSchema)}
+			if !isNested(v[key]) {
+				field.Value = v[key]
+			}
+			*result = append(*result, field)
+			walkSpecFields(v[key], fieldSchema, fieldPath, result)
+		}
+	case []interface{}:
+		var itemSchema *apiextensionsv1.JSONSchemaProps
+		if schema != nil && schema.Items != nil {
+			itemSchema = schema.Items.Schema
+		}
+		for i, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				walkSpecFields(item, itemSchema, fmt.Sprintf("%v[%d]", path, i), result)
+			}
+		}
+	}
+}
+
+// propertySchema returns the schema of the property of an object with the
+// given schema, or nil if it is unknown.
+func propertySchema(schema *apiextensionsv1.JSONSchemaProps, property string) *apiextensionsv1.JSONSchemaProps {
+	if schema == nil {
+		return nil
+	}
+	if propertySchema, ok := schema.Properties[property]; ok {
+		return &propertySchema
+	}
+	if schema.AdditionalProperties != nil {
+		return schema.AdditionalProperties.Schema
+	}
+	return nil
+}
+
+func description(schema *apiextensionsv1.JSONSchemaProps) string {
+	if schema == nil {
+		return ""
+	}
+	return schema.Description
+}
+
+// isNested returns true if value is an object or a list containing objects,
+// whose fields are walked separately.
+func isNested(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
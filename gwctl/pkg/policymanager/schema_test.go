@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policymanager
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestPolicyManager_ValidateMergedPolicy(t *testing.T) {
+	// The schema of HealthCheckPolicy forbids setting both interval and
+	// intervalMilliseconds.
+	policyCRD := PolicyCRD{
+		crd: apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "healthcheckpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "foo.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"default": {
+											Type: "object",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"interval":             {Type: "integer"},
+												"intervalMilliseconds": {Type: "integer"},
+											},
+											Not: &apiextensionsv1.JSONSchemaProps{
+												Required: []string{"interval", "intervalMilliseconds"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	healthCheckPolicy := func(name string, defaults map[string]interface{}) Policy {
+		return Policy{
+			inherited: true,
+			u: unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "foo.com/v1",
+					"kind":       "HealthCheckPolicy",
+					"metadata": map[string]interface{}{
+						"name": name,
+					},
+					"spec": map[string]interface{}{
+						"default": defaults,
+					},
+				},
+			},
+		}
+	}
+
+	// Each policy is valid on its own...
+	parent := healthCheckPolicy("health-check-gatewayclass", map[string]interface{}{"interval": int64(10)})
+	child := healthCheckPolicy("health-check-gateway", map[string]interface{}{"intervalMilliseconds": int64(500)})
+	// ...but merging them sets both mutually exclusive fields.
+	merged, err := MergePoliciesOfDifferentHierarchy(
+		map[PolicyCrdID]Policy{parent.PolicyCrdID(): parent},
+		map[PolicyCrdID]Policy{child.PolicyCrdID(): child},
+	)
+	if err != nil {
+		t.Fatalf("MergePoliciesOfDifferentHierarchy returned err=%v; want no error", err)
+	}
+
+	policyManager := New(nil)
+	policyManager.policyCRDs[policyCRD.ID()] = policyCRD
+
+	// Validation is disabled by default.
+	if err := policyManager.ValidateMergedPolicy(merged[policyCRD.ID()]); err != nil {
+		t.Errorf("ValidateMergedPolicy returned err=%v with validation disabled; want no error", err)
+	}
+
+	policyManager.EnableMergedPolicyValidation()
+	for _, policy := range []Policy{parent, child} {
+		if err := policyManager.ValidateMergedPolicy(policy); err != nil {
+			t.Errorf("ValidateMergedPolicy(%v) returned err=%v; want no error", policy.Name(), err)
+		}
+	}
+	if err := policyManager.ValidateMergedPolicy(merged[policyCRD.ID()]); err == nil {
+		t.Errorf("ValidateMergedPolicy returned no error for merged policy setting both interval and intervalMilliseconds; want error")
+	}
+}
+
+func TestPolicy_SpecFields(t *testing.T) {
+	policyCRD := PolicyCRD{
+		crd: apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "direct"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "foo.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"targetRef": {Type: "object", Description: "TargetRef identifies the target."},
+										"rules": {
+											Type:        "array",
+											Description: "Rules lists the timeouts.",
+											Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+												Schema: &apiextensionsv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]apiextensionsv1.JSONSchemaProps{
+														"timeout": {Type: "integer", Description: "Timeout in seconds."},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	u := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "foo.com/v1",
+			"kind":       "TimeoutPolicy",
+			"metadata": map[string]interface{}{
+				"name":      "timeout-policy",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{
+					"group": "gateway.networking.k8s.io",
+					"kind":  "Gateway",
+					"name":  "foo-gateway",
+				},
+				"rules": []interface{}{
+					map[string]interface{}{"timeout": int64(30)},
+				},
+				"undocumented": "bar",
+			},
+		},
+	}
+	policy, err := PolicyFromUnstructured(u, map[PolicyCrdID]PolicyCRD{policyCRD.ID(): policyCRD})
+	if err != nil {
+		t.Fatalf("PolicyFromUnstructured returned err=%v; want no error", err)
+	}
+
+	want := []SpecField{
+		{Path: "rules", Description: "Rules lists the timeouts."},
+		{Path: "rules[0].timeout", Value: int64(30), Description: "Timeout in seconds."},
+		{Path: "targetRef", Description: "TargetRef identifies the target."},
+		{Path: "targetRef.group", Value: "gateway.networking.k8s.io"},
+		{Path: "targetRef.kind", Value: "Gateway"},
+		{Path: "targetRef.name", Value: "foo-gateway"},
+		{Path: "undocumented", Value: "bar"},
+	}
+	if diff := cmp.Diff(want, policy.SpecFields()); diff != "" {
+		t.Errorf("SpecFields() returned unexpected diff (-want, +got):\n%v", diff)
+	}
+}
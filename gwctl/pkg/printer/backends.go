@@ -31,8 +31,7 @@ import (
 
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
-
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
 type BackendsPrinter struct {
@@ -129,6 +128,9 @@ type backendDescribeView struct {
 	Kind                     string                 `json:",omitempty"`
 	Name                     string                 `json:",omitempty"`
 	Namespace                string                 `json:",omitempty"`
+	TrafficDistribution      string                 `json:",omitempty"`
+	EndpointZones            map[string]int         `json:",omitempty"`
+	HTTPRouteFilters         []string               `json:",omitempty"`
 	DirectlyAttachedPolicies []policymanager.ObjRef `json:",omitempty"`
 	EffectivePolicies        any                    `json:",omitempty"`
 }
@@ -146,6 +148,21 @@ func (bp *BackendsPrinter) PrintDescribeView(resourceModel *resourcediscovery.Re
 				Namespace: backendNode.Backend.GetNamespace(),
 			},
 		}
+		if backendNode.TrafficDistribution != "" {
+			views = append(views, backendDescribeView{
+				TrafficDistribution: backendNode.TrafficDistribution,
+			})
+		}
+		if len(backendNode.EndpointZones) != 0 {
+			views = append(views, backendDescribeView{
+				EndpointZones: backendNode.EndpointZones,
+			})
+		}
+		if httpRouteFilters := backendHTTPRouteFilters(backendNode); len(httpRouteFilters) != 0 {
+			views = append(views, backendDescribeView{
+				HTTPRouteFilters: httpRouteFilters,
+			})
+		}
 		if policyRefs := resourcediscovery.ConvertPoliciesMapToPolicyRefs(backendNode.Policies); len(policyRefs) != 0 {
 			views = append(views, backendDescribeView{
 				DirectlyAttachedPolicies: policyRefs,
@@ -158,7 +175,7 @@ func (bp *BackendsPrinter) PrintDescribeView(resourceModel *resourcediscovery.Re
 		}
 
 		for _, view := range views {
-			b, err := yaml.Marshal(view)
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 				os.Exit(1)
@@ -171,3 +188,20 @@ func (bp *BackendsPrinter) PrintDescribeView(resourceModel *resourcediscovery.Re
 		}
 	}
 }
+
+// backendHTTPRouteFilters returns the filters which backendRefs of HTTPRoutes
+// apply to traffic forwarded to the Backend, prefixed with the HTTPRoute.
+func backendHTTPRouteFilters(backendNode *resourcediscovery.BackendNode) []string {
+	var result []string
+	for _, httpRouteNode := range backendNode.HTTPRoutes {
+		for _, filter := range httpRouteNode.BackendFilters(backendNode.ID()) {
+			summary := fmt.Sprintf("HTTPRoute %v/%v rule %d: %v", httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName(), filter.RuleIndex, filter.Type)
+			if filter.Details != "" {
+				summary = fmt.Sprintf("%v (%v)", summary, filter.Details)
+			}
+			result = append(result, summary)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
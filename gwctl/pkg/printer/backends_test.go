@@ -18,11 +18,13 @@ package printer
 
 import (
 	"bytes"
+	"context"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -184,7 +186,7 @@ func TestBackendsPrinter_Print(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForBackend(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel %v: %v", resourceModel, err)
 	}
@@ -206,3 +208,97 @@ ns1        foo-svc-2  Service  ns1/foo-httproute-2,ns1/foo-httproute-3 + 2 more
 		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
 	}
 }
+
+func TestBackendsPrinter_PrintDescribeView(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("ns1"),
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "ns1",
+			},
+			Spec: corev1.ServiceSpec{
+				TrafficDistribution: ptr.To(corev1.ServiceTrafficDistributionPreferClose),
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc-abc",
+				Namespace: "ns1",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo-svc"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Zone: ptr.To("zone-a")},
+				{Addresses: []string{"10.0.0.2"}, Zone: ptr.To("zone-a")},
+				{Addresses: []string{"10.0.0.3"}, Zone: ptr.To("zone-b")},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "ns1",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{
+					{
+						BackendRefs: []gatewayv1.HTTPBackendRef{
+							{
+								BackendRef: gatewayv1.BackendRef{
+									BackendObjectReference: gatewayv1.BackendObjectReference{
+										Kind: ptr.To(gatewayv1.Kind("Service")),
+										Name: "foo-svc",
+										Port: ptr.To(gatewayv1.PortNumber(8080)),
+									},
+								},
+								Filters: []gatewayv1.HTTPRouteFilter{
+									{
+										Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+										RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+											Set: []gatewayv1.HTTPHeader{{Name: "X-Canary", Value: "true"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), resourcediscovery.Filter{})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel %v: %v", resourceModel, err)
+	}
+
+	bp := &BackendsPrinter{
+		Writer: params.Out,
+	}
+	bp.PrintDescribeView(resourceModel)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `
+Kind: Service
+Name: foo-svc
+Namespace: ns1
+TrafficDistribution: PreferClose
+EndpointZones:
+  zone-a: 2
+  zone-b: 1
+HTTPRouteFilters:
+- 'HTTPRoute ns1/foo-httproute rule 0: RequestHeaderModifier (set=[X-Canary:true])'
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
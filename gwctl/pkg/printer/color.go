@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// Color is an ANSI Select Graphic Rendition code.
+type Color string
+
+const (
+	ColorRed    Color = "31"
+	ColorGreen  Color = "32"
+	ColorYellow Color = "33"
+	ColorDim    Color = "2"
+)
+
+// Colorizer wraps text in ANSI escape sequences. The zero value does not color
+// any text.
+type Colorizer struct {
+	Enabled bool
+}
+
+// NewColorizer returns a Colorizer for output written to w. Coloring is enabled
+// only if w is a terminal, noColor is false and the NO_COLOR environment
+// variable (https://no-color.org) is not set.
+func NewColorizer(w io.Writer, noColor bool) Colorizer {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return Colorizer{}
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return Colorizer{}
+	}
+	return Colorizer{Enabled: term.IsTerminal(int(f.Fd()))}
+}
+
+// Colorize returns s wrapped in the escape sequences for color.
+func (c Colorizer) Colorize(s string, color Color) string {
+	if !c.Enabled || s == "" || color == "" {
+		return s
+	}
+	return "\x1b[" + string(color) + "m" + s + "\x1b[0m"
+}
+
+// SeverityColor returns the color used for findings of the given severity.
+// Informational findings are not colored.
+func SeverityColor(severity analyzer.Severity) Color {
+	switch severity {
+	case analyzer.SeverityError:
+		return ColorRed
+	case analyzer.SeverityWarning:
+		return ColorYellow
+	default:
+		return ""
+	}
+}
+
+// Status returns the colored string representation of status.
+func (c Colorizer) Status(status resourcediscovery.StatusSummary) string {
+	if status.OK() {
+		return c.Colorize(status.String(), ColorGreen)
+	}
+	return c.Colorize(status.String(), ColorRed)
+}
+
+// colorizeLinePrefix colors the prefix of line, if line starts with prefix.
+// Tables are colored after they have been aligned by the tabwriter, since
+// escape sequences would otherwise be counted towards the width of the cells.
+func (c Colorizer) colorizeLinePrefix(line, prefix string, color Color) string {
+	if !strings.HasPrefix(line, prefix) {
+		return line
+	}
+	return c.Colorize(prefix, color) + line[len(prefix):]
+}
+
+// colorizeLine colors line, excluding its trailing newline.
+func (c Colorizer) colorizeLine(line string, color Color) string {
+	trimmed := strings.TrimSuffix(line, "\n")
+	return c.Colorize(trimmed, color) + line[len(trimmed):]
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestNewColorizer(t *testing.T) {
+	// A regular file is not a terminal.
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	if NewColorizer(&bytes.Buffer{}, false).Enabled {
+		t.Errorf("NewColorizer(buffer) is enabled; want disabled for output which is not a terminal")
+	}
+	if NewColorizer(file, false).Enabled {
+		t.Errorf("NewColorizer(file) is enabled; want disabled for output which is not a terminal")
+	}
+	if NewColorizer(os.Stdout, true).Enabled {
+		t.Errorf("NewColorizer(stdout, noColor=true) is enabled; want disabled")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if NewColorizer(os.Stdout, false).Enabled {
+		t.Errorf("NewColorizer(stdout) with NO_COLOR set is enabled; want disabled")
+	}
+}
+
+func TestFindingsPrinter_Color(t *testing.T) {
+	findings := []analyzer.Finding{
+		{
+			Code:        analyzer.CodeMissingService,
+			Severity:    analyzer.SeverityError,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			Message:     "broken",
+		},
+		{
+			Code:        analyzer.CodeShadowedMatch,
+			Severity:    analyzer.SeverityWarning,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "bar-httproute", Namespace: "default"},
+			Message:     "suspicious",
+		},
+		{
+			Code:        analyzer.CodeDeprecatedAPIVersion,
+			Severity:    analyzer.SeverityInfo,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "baz-httproute", Namespace: "default"},
+			Message:     "noteworthy",
+		},
+	}
+
+	t.Run("not a terminal", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		fp := &FindingsPrinter{Writer: out, Color: NewColorizer(out, false)}
+		fp.PrintFindings(findings, utils.OutputFormatTable)
+
+		if got := out.String(); strings.Contains(got, "\x1b[") {
+			t.Errorf("Output contains ANSI escape sequences:\n%q", got)
+		}
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		fp := &FindingsPrinter{Writer: out, Color: Colorizer{Enabled: true}}
+		fp.PrintFindings(findings, utils.OutputFormatTable)
+
+		// Only the severity is colored, and the columns stay aligned.
+		want := "SEVERITY  CODE      KIND       RESOURCE               MESSAGE\n" +
+			"\x1b[31mError\x1b[0m     GWCTL004  HTTPRoute  default/foo-httproute  broken\n" +
+			"\x1b[33mWarning\x1b[0m   GWCTL005  HTTPRoute  default/bar-httproute  suspicious\n" +
+			"Info      GWCTL001  HTTPRoute  default/baz-httproute  noteworthy\n"
+		if diff := cmp.Diff(want, out.String()); diff != "" {
+			t.Errorf("Unexpected diff\ngot=\n%q\nwant=\n%q\ndiff (-want +got)=\n%v", out.String(), want, diff)
+		}
+	})
+}
@@ -28,7 +28,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
 // DescriberKV stores key-value pairs that are used with Describing a resource.
@@ -59,7 +61,7 @@ func Describe(w io.Writer, pairs []*DescriberKV) {
 
 		// If Value is NOT a Table, it can be handled through the yaml Marshaller.
 		data := map[string]any{pair.Key: pair.Value}
-		b, err := yaml.Marshal(data)
+		b, err := utils.MarshalWithFormat(data, utils.OutputFormatYAML)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 			os.Exit(1)
@@ -151,6 +153,23 @@ func convertEventsSliceToTable(events []corev1.Event, clock clock.Clock) *Table
 	return table
 }
 
+// policyRefsToTable converts the policyRefs into a Table with the Type and Name
+// of each Policy.
+func policyRefsToTable(policyRefs []policymanager.ObjRef) *Table {
+	table := &Table{
+		ColumnNames:  []string{"Type", "Name"},
+		UseSeparator: true,
+	}
+	for _, policyRef := range policyRefs {
+		row := []string{
+			fmt.Sprintf("%v.%v", policyRef.Kind, policyRef.Group),     // Type
+			fmt.Sprintf("%v/%v", policyRef.Namespace, policyRef.Name), // Name
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
 type NodeResource interface {
 	ClientObject() client.Object
 }
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+type FindingsPrinter struct {
+	io.Writer
+	Color Colorizer
+}
+
+func (fp *FindingsPrinter) printFindingsTable(findings []analyzer.Finding) {
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	row := []string{"SEVERITY", "CODE", "KIND", "RESOURCE", "MESSAGE"}
+	_, err := tw.Write([]byte(strings.Join(row, "\t") + "\n"))
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for _, finding := range findings {
+		resource := finding.ResourceRef.Name
+		if finding.ResourceRef.Namespace != "" {
+			resource = fmt.Sprintf("%v/%v", finding.ResourceRef.Namespace, finding.ResourceRef.Name)
+		}
+		row := []string{
+			string(finding.Severity),
+			string(finding.Code),
+			finding.ResourceRef.Kind,
+			resource,
+			finding.Message,
+		}
+		_, err := tw.Write([]byte(strings.Join(row, "\t") + "\n"))
+		if err != nil {
+			fmt.Fprint(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	tw.Flush()
+
+	// The first line is the header, followed by one line for each finding.
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if i > 0 && i <= len(findings) {
+			severity := findings[i-1].Severity
+			line = fp.Color.colorizeLinePrefix(line, string(severity), SeverityColor(severity))
+		}
+		fmt.Fprint(fp, line)
+	}
+}
+
+func (fp *FindingsPrinter) PrintFindings(findings []analyzer.Finding, format utils.OutputFormat) {
+	switch format {
+	case utils.OutputFormatJSON, utils.OutputFormatYAML:
+		if findings == nil {
+			findings = []analyzer.Finding{}
+		}
+		output, err := utils.MarshalWithFormat(findings, format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal the object %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprint(fp, string(output))
+	case utils.OutputFormatSARIF:
+		output, err := utils.MarshalWithFormat(newSARIFLog(findings), utils.OutputFormatJSON)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal the object %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(fp, string(output))
+	case utils.OutputFormatTable:
+		if len(findings) == 0 {
+			fmt.Fprintln(fp, "No issues found.")
+			return
+		}
+		fp.printFindingsTable(findings)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown output format '%s' found\n", format)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestFindingsPrinter_PrintFindings(t *testing.T) {
+	findings := []analyzer.Finding{
+		{
+			Code:        analyzer.CodeShadowedMatch,
+			Severity:    analyzer.SeverityWarning,
+			Category:    analyzer.CategoryRouting,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			Message:     "Match 0 of rule 1 (PathPrefix /api) is shadowed by match 0 of rule 0 (PathPrefix /api) which matches the same requests with equal or higher precedence",
+			Remediation: "Remove the shadowed match.",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		findings []analyzer.Finding
+		format   utils.OutputFormat
+		want     string
+	}{
+		{
+			name:     "table",
+			findings: findings,
+			format:   utils.OutputFormatTable,
+			want: `
+SEVERITY  CODE      KIND       RESOURCE               MESSAGE
+Warning   GWCTL005  HTTPRoute  default/foo-httproute  Match 0 of rule 1 (PathPrefix /api) is shadowed by match 0 of rule 0 (PathPrefix /api) which matches the same requests with equal or higher precedence
+`,
+		},
+		{
+			name:     "table with no findings",
+			findings: nil,
+			format:   utils.OutputFormatTable,
+			want: `
+No issues found.
+`,
+		},
+		{
+			name:     "yaml",
+			findings: findings,
+			format:   utils.OutputFormatYAML,
+			want: `
+- category: Routing
+  code: GWCTL005
+  message: Match 0 of rule 1 (PathPrefix /api) is shadowed by match 0 of rule 0 (PathPrefix
+    /api) which matches the same requests with equal or higher precedence
+  remediation: Remove the shadowed match.
+  resourceRef:
+    Kind: HTTPRoute
+    Name: foo-httproute
+    Namespace: default
+  severity: Warning
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			fp := &FindingsPrinter{Writer: out}
+			fp.PrintFindings(tc.findings, tc.format)
+
+			got := out.String()
+			if diff := cmp.Diff(common.YamlString(tc.want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+				t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestFindingsPrinter_PrintFindings_SARIF(t *testing.T) {
+	findings := []analyzer.Finding{
+		{
+			Code:        analyzer.CodeShadowedMatch,
+			Severity:    analyzer.SeverityWarning,
+			Category:    analyzer.CategoryRouting,
+			ResourceRef: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			Message:     "Match 0 of rule 1 is shadowed",
+			Remediation: "Remove the shadowed match.",
+		},
+		{
+			Code:        analyzer.CodeDeprecatedAPIVersion,
+			Severity:    analyzer.SeverityInfo,
+			Category:    analyzer.CategoryAPIVersion,
+			ResourceRef: common.ObjRef{Kind: "GatewayClass", Name: "foo-gatewayclass"},
+			Message:     "GatewayClass is served as a deprecated API version",
+		},
+	}
+
+	out := &bytes.Buffer{}
+	fp := &FindingsPrinter{Writer: out}
+	fp.PrintFindings(findings, utils.OutputFormatSARIF)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%v", err, out.String())
+	}
+	if err := validateSARIF(t, got); err != nil {
+		t.Fatalf("Output does not conform to the SARIF schema: %v\n%v", err, out.String())
+	}
+
+	log := sarifLog{}
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("Failed to unmarshal output: %v", err)
+	}
+	if got, want := len(log.Runs[0].Tool.Driver.Rules), len(analyzer.Codes()); got != want {
+		t.Errorf("len(rules)=%v; want %v", got, want)
+	}
+	wantResults := []sarifResult{
+		{
+			RuleID:    "GWCTL005",
+			RuleIndex: 4,
+			Level:     "warning",
+			Message:   sarifMessage{Text: "Match 0 of rule 1 is shadowed"},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: "HTTPRoute/default/foo-httproute"},
+					Region:           sarifRegion{StartLine: 1},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               "default/foo-httproute",
+					FullyQualifiedName: "HTTPRoute/default/foo-httproute",
+					Kind:               "resource",
+				}},
+			}},
+			Properties: map[string]interface{}{"remediation": "Remove the shadowed match."},
+		},
+		{
+			RuleID:    "GWCTL001",
+			RuleIndex: 0,
+			Level:     "note",
+			Message:   sarifMessage{Text: "GatewayClass is served as a deprecated API version"},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: "GatewayClass/foo-gatewayclass"},
+					Region:           sarifRegion{StartLine: 1},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               "foo-gatewayclass",
+					FullyQualifiedName: "GatewayClass/foo-gatewayclass",
+					Kind:               "resource",
+				}},
+			}},
+		},
+	}
+	if diff := cmp.Diff(wantResults, log.Runs[0].Results); diff != "" {
+		t.Errorf("Unexpected diff in results (-want +got):\n%v", diff)
+	}
+
+	// Ensure the schema actually rejects invalid logs.
+	got["version"] = "2.0.0"
+	if err := validateSARIF(t, got); err == nil {
+		t.Errorf("validateSARIF() accepted a log with an unsupported version")
+	}
+}
+
+// validateSARIF validates the SARIF log against the subset of the SARIF 2.1.0
+// schema in testdata.
+func validateSARIF(t *testing.T, sarif map[string]interface{}) error {
+	b, err := os.ReadFile("testdata/sarif-schema-2.1.0-subset.json")
+	if err != nil {
+		t.Fatalf("Failed to read SARIF schema: %v", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("Failed to unmarshal SARIF schema: %v", err)
+	}
+	// The validator does not support references, so they are inlined first.
+	definitions, _ := raw["definitions"].(map[string]interface{})
+	delete(raw, "definitions")
+	b, err = json.Marshal(inlineSchemaRefs(raw, definitions))
+	if err != nil {
+		t.Fatalf("Failed to marshal SARIF schema: %v", err)
+	}
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(b, schema); err != nil {
+		t.Fatalf("Failed to unmarshal SARIF schema: %v", err)
+	}
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(sarif)
+	if result.IsValid() {
+		return nil
+	}
+	return result.AsError()
+}
+
+// inlineSchemaRefs replaces each "#/definitions/NAME" reference in the schema
+// with the referenced definition. The definitions must not be recursive.
+func inlineSchemaRefs(node interface{}, definitions map[string]interface{}) interface{} {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok {
+			return inlineSchemaRefs(definitions[strings.TrimPrefix(ref, "#/definitions/")], definitions)
+		}
+		result := make(map[string]interface{})
+		for key, value := range node {
+			result[key] = inlineSchemaRefs(value, definitions)
+		}
+		return result
+	case []interface{}:
+		var result []interface{}
+		for _, value := range node {
+			result = append(result, inlineSchemaRefs(value, definitions))
+		}
+		return result
+	default:
+		return node
+	}
+}
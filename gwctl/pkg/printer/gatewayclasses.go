@@ -17,6 +17,7 @@ limitations under the License.
 package printer
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -32,8 +33,7 @@ import (
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
-
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
 var _ Printer = (*GatewayClassesPrinter)(nil)
@@ -54,12 +54,50 @@ type gatewayClassDescribeView struct {
 	Name           string `json:",omitempty"`
 	ControllerName string `json:",omitempty"`
 	// GatewayClass description
-	Description *string `json:",omitempty"`
+	Description   *string            `json:",omitempty"`
+	ParametersRef *parametersRefView `json:",omitempty"`
 
 	Status                   *gatewayv1.GatewayClassStatus `json:",omitempty"`
 	DirectlyAttachedPolicies []policymanager.ObjRef        `json:",omitempty"`
 }
 
+// parametersRefView describes the configuration object referenced by the
+// parametersRef of the GatewayClass, along with its configuration if it was
+// resolved: the spec of custom resources, or the data of ConfigMaps.
+type parametersRefView struct {
+	Kind   string
+	Name   string
+	Config interface{} `json:",omitempty"`
+	Error  string      `json:",omitempty"`
+}
+
+func newParametersRefView(gatewayClassNode *resourcediscovery.GatewayClassNode) *parametersRefView {
+	ref := gatewayClassNode.GatewayClass.Spec.ParametersRef
+	if ref == nil {
+		return nil
+	}
+	view := &parametersRefView{Kind: string(ref.Kind), Name: ref.Name}
+	if ref.Group != "" {
+		view.Kind = fmt.Sprintf("%v.%v", ref.Kind, ref.Group)
+	}
+	if ref.Namespace != nil {
+		view.Name = fmt.Sprintf("%v/%v", *ref.Namespace, ref.Name)
+	}
+	if gatewayClassNode.Parameters != nil {
+		view.Config = gatewayClassNode.Parameters.Object["spec"]
+		if view.Config == nil {
+			view.Config = gatewayClassNode.Parameters.Object["data"]
+		}
+	}
+	for _, err := range gatewayClassNode.Errors {
+		var unresolvedErr resourcediscovery.UnresolvedParametersRefError
+		if errors.As(err, &unresolvedErr) {
+			view.Error = unresolvedErr.Reason
+		}
+	}
+	return view
+}
+
 func (gcp *GatewayClassesPrinter) GetPrintableNodes(resourceModel *resourcediscovery.ResourceModel) []NodeResource {
 	return NodeResources(common.MapToValues(resourceModel.GatewayClasses))
 }
@@ -140,6 +178,11 @@ func (gcp *GatewayClassesPrinter) PrintDescribeView(resourceModel *resourcedisco
 				Description: gatewayClassNode.GatewayClass.Spec.Description,
 			})
 		}
+		if parametersRef := newParametersRefView(gatewayClassNode); parametersRef != nil {
+			views = append(views, gatewayClassDescribeView{
+				ParametersRef: parametersRef,
+			})
+		}
 		views = append(views, gatewayClassDescribeView{
 			Status: &gatewayClassNode.GatewayClass.Status,
 		})
@@ -151,7 +194,7 @@ func (gcp *GatewayClassesPrinter) PrintDescribeView(resourceModel *resourcedisco
 		}
 
 		for _, view := range views {
-			b, err := yaml.Marshal(view)
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 				os.Exit(1)
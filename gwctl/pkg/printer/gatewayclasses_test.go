@@ -18,6 +18,7 @@ package printer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -105,7 +106,7 @@ func TestGatewayClassesPrinter_PrintTable(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -185,6 +186,8 @@ func TestGatewayClassesPrinter_PrintDescribeView(t *testing.T) {
 Name: foo-gatewayclass
 Labels: null
 Annotations: null
+APIVersion: gateway.networking.k8s.io/v1
+Kind: GatewayClass
 Metadata:
   creationTimestamp: null
   resourceVersion: "999"
@@ -195,6 +198,69 @@ DirectlyAttachedPolicies:
 - Group: foo.com
   Kind: HealthCheckPolicy
   Name: policy-name
+`,
+		},
+		{
+			name: "GatewayClass with parametersRef",
+			objects: []runtime.Object{
+				&gatewayv1.GatewayClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo-gatewayclass",
+					},
+					Spec: gatewayv1.GatewayClassSpec{
+						ControllerName: "example.net/gateway-controller",
+						ParametersRef: &gatewayv1.ParametersReference{
+							Group:     "example.com",
+							Kind:      "ProxyConfig",
+							Name:      "foo-config",
+							Namespace: common.PtrTo(gatewayv1.Namespace("infra")),
+						},
+					},
+				},
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "proxyconfigs.example.com",
+					},
+					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+						Scope:    apiextensionsv1.NamespaceScoped,
+						Group:    "example.com",
+						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+						Names: apiextensionsv1.CustomResourceDefinitionNames{
+							Plural: "proxyconfigs",
+							Kind:   "ProxyConfig",
+						},
+					},
+				},
+				&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "example.com/v1",
+						"kind":       "ProxyConfig",
+						"metadata": map[string]interface{}{
+							"name":      "foo-config",
+							"namespace": "infra",
+						},
+						"spec": map[string]interface{}{
+							"replicas": int64(3),
+						},
+					},
+				},
+			},
+			want: `
+Name: foo-gatewayclass
+Labels: null
+Annotations: null
+APIVersion: gateway.networking.k8s.io/v1
+Kind: GatewayClass
+Metadata:
+  creationTimestamp: null
+  resourceVersion: "999"
+ControllerName: example.net/gateway-controller
+ParametersRef:
+  Config:
+    replicas: 3
+  Kind: ProxyConfig.example.com
+  Name: infra/foo-config
+Status: {}
 `,
 		},
 		{
@@ -217,6 +283,8 @@ Name: foo-gatewayclass
 Labels:
   foo: bar
 Annotations: null
+APIVersion: gateway.networking.k8s.io/v1
+Kind: GatewayClass
 Metadata:
   creationTimestamp: null
   resourceVersion: "999"
@@ -234,7 +302,7 @@ Status: {}
 				K8sClients:    params.K8sClients,
 				PolicyManager: params.PolicyManager,
 			}
-			resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(resourcediscovery.Filter{})
+			resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(context.Background(), resourcediscovery.Filter{})
 			if err != nil {
 				t.Fatalf("Failed to construct resourceModel: %v", err)
 			}
@@ -293,7 +361,7 @@ func TestGatewayClassesPrinter_PrintJsonYaml(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
 	}
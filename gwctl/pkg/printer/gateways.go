@@ -29,6 +29,7 @@ import (
 	"k8s.io/utils/clock"
 
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 )
 
 var _ Printer = (*GatewaysPrinter)(nil)
@@ -36,6 +37,11 @@ var _ Printer = (*GatewaysPrinter)(nil)
 type GatewaysPrinter struct {
 	io.Writer
 	Clock clock.Clock
+	Color Colorizer
+	// DedupBackends prints Backends referenced by more than one HTTPRoute
+	// attached to a Gateway once in the tree, along with the HTTPRoutes
+	// referencing them, instead of under each of those HTTPRoutes.
+	DedupBackends bool
 }
 
 func (gp *GatewaysPrinter) GetPrintableNodes(resourceModel *resourcediscovery.ResourceModel) []NodeResource {
@@ -44,7 +50,7 @@ func (gp *GatewaysPrinter) GetPrintableNodes(resourceModel *resourcediscovery.Re
 
 func (gp *GatewaysPrinter) PrintTable(resourceModel *resourcediscovery.ResourceModel) {
 	tw := tabwriter.NewWriter(gp, 0, 0, 2, ' ', 0)
-	row := []string{"NAME", "CLASS", "ADDRESSES", "PORTS", "PROGRAMMED", "AGE"}
+	row := []string{"NAME", "CLASS", "IMPLEMENTATION", "ADDRESSES", "PORTS", "PROGRAMMED", "AGE"}
 	_, err := tw.Write([]byte(strings.Join(row, "\t") + "\n"))
 	if err != nil {
 		fmt.Fprint(os.Stderr, err)
@@ -77,11 +83,17 @@ func (gp *GatewaysPrinter) PrintTable(resourceModel *resourcediscovery.ResourceM
 			}
 		}
 
+		implementation := string(gatewayNode.ControllerName())
+		if implementation == "" {
+			implementation = "unknown"
+		}
+
 		age := duration.HumanDuration(gp.Clock.Since(gatewayNode.Gateway.GetCreationTimestamp().Time))
 
 		row := []string{
 			gatewayNode.Gateway.GetName(),
 			string(gatewayNode.Gateway.Spec.GatewayClassName),
+			implementation,
 			addressesOutput,
 			portsOutput,
 			programmedStatus,
@@ -97,8 +109,39 @@ func (gp *GatewaysPrinter) PrintTable(resourceModel *resourcediscovery.ResourceM
 }
 
 func (gp *GatewaysPrinter) PrintDescribeView(resourceModel *resourcediscovery.ResourceModel) {
+	gp.describeGateways(common.MapToValues(resourceModel.Gateways))
+}
+
+// PrintDescribeViewGroupedByNamespace prints the describe view of Gateways
+// organized into per-namespace sections. Each section begins with a header
+// which lists the Policies attached to the Namespace. Namespaces without any
+// Gateways are not printed.
+func (gp *GatewaysPrinter) PrintDescribeViewGroupedByNamespace(resourceModel *resourcediscovery.ResourceModel) {
+	var namespaceNodes []*resourcediscovery.NamespaceNode
+	for _, namespaceNode := range resourceModel.Namespaces {
+		if len(namespaceNode.Gateways) != 0 {
+			namespaceNodes = append(namespaceNodes, namespaceNode)
+		}
+	}
+
+	for i, namespaceNode := range SortByString(namespaceNodes) {
+		fmt.Fprintf(gp, "### Namespace: %v ###\n", namespaceNode.Namespace.Name)
+		Describe(gp, []*DescriberKV{
+			{Key: "NamespacePolicies", Value: policyRefsToTable(resourcediscovery.ConvertPoliciesMapToPolicyRefs(namespaceNode.Policies))},
+		})
+		fmt.Fprintf(gp, "\n")
+
+		gp.describeGateways(SortByString(common.MapToValues(namespaceNode.Gateways)))
+
+		if i+1 < len(namespaceNodes) {
+			fmt.Fprintf(gp, "\n\n")
+		}
+	}
+}
+
+func (gp *GatewaysPrinter) describeGateways(gatewayNodes []*resourcediscovery.GatewayNode) {
 	index := 0
-	for _, gatewayNode := range resourceModel.Gateways {
+	for _, gatewayNode := range gatewayNodes {
 		index++
 
 		metadata := gatewayNode.Gateway.ObjectMeta.DeepCopy()
@@ -133,20 +176,18 @@ func (gp *GatewaysPrinter) PrintDescribeView(resourceModel *resourcediscovery.Re
 		}
 		pairs = append(pairs, &DescriberKV{Key: "AttachedRoutes", Value: attachedRoutes})
 
+		// DefaultBackends
+		if len(gatewayNode.DefaultBackendRefs) != 0 {
+			var defaultBackends []string
+			for _, defaultBackendRef := range gatewayNode.DefaultBackendRefs {
+				defaultBackends = append(defaultBackends, defaultBackendRef.String())
+			}
+			pairs = append(pairs, &DescriberKV{Key: "DefaultBackends", Value: defaultBackends})
+		}
+
 		// DirectlyAttachedPolicies
 		if policyRefs := resourcediscovery.ConvertPoliciesMapToPolicyRefs(gatewayNode.Policies); len(policyRefs) != 0 {
-			directlyAttachedPolicies := &Table{
-				ColumnNames:  []string{"Type", "Name"},
-				UseSeparator: true,
-			}
-			for _, policyRef := range policyRefs {
-				row := []string{
-					fmt.Sprintf("%v.%v", policyRef.Kind, policyRef.Group),     // Type
-					fmt.Sprintf("%v/%v", policyRef.Namespace, policyRef.Name), // Name
-				}
-				directlyAttachedPolicies.Rows = append(directlyAttachedPolicies.Rows, row)
-			}
-			pairs = append(pairs, &DescriberKV{Key: "DirectlyAttachedPolicies", Value: directlyAttachedPolicies})
+			pairs = append(pairs, &DescriberKV{Key: "DirectlyAttachedPolicies", Value: policyRefsToTable(policyRefs)})
 		}
 
 		// EffectivePolicies
@@ -159,8 +200,183 @@ func (gp *GatewaysPrinter) PrintDescribeView(resourceModel *resourcediscovery.Re
 
 		Describe(gp, pairs)
 
-		if index+1 <= len(resourceModel.Gateways) {
+		if index+1 <= len(gatewayNodes) {
 			fmt.Fprintf(gp, "\n\n")
 		}
 	}
 }
+
+// PrintDescribeTree prints each Gateway as the root of a tree of its
+// listeners, the HTTPRoutes attached to each listener and the Backends of those
+// HTTPRoutes. Each resource is annotated with the number of effective policies
+// applying to it through the Gateway.
+func (gp *GatewaysPrinter) PrintDescribeTree(resourceModel *resourcediscovery.ResourceModel) {
+	gatewayNodes := SortByString(common.MapToValues(resourceModel.Gateways))
+	for i, gatewayNode := range gatewayNodes {
+		fmt.Fprintf(gp, "Gateway %v/%v %v\n", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName(), effectivePolicyCount(gatewayNode.EffectivePolicies))
+		printDescribeTreeNodes(gp, gatewayDescribeTree(gatewayNode, gp.DedupBackends), "")
+		if i+1 < len(gatewayNodes) {
+			fmt.Fprintf(gp, "\n")
+		}
+	}
+}
+
+// describeTreeNode is a line of the tree printed by PrintDescribeTree.
+type describeTreeNode struct {
+	line     string
+	children []describeTreeNode
+}
+
+// gatewayDescribeTree returns the listeners of the Gateway, along with the
+// HTTPRoutes attached to them and their Backends. If dedupBackends is set,
+// Backends shared by several of the HTTPRoutes are omitted from the HTTPRoutes
+// and returned once in a trailing node instead, each with the HTTPRoutes
+// referencing it.
+func gatewayDescribeTree(gatewayNode *resourcediscovery.GatewayNode, dedupBackends bool) []describeTreeNode {
+	gatewayID := gatewayNode.ID()
+	httpRouteNodes := SortByString(common.MapToValues(gatewayNode.HTTPRoutes))
+	backendLine := func(backendNode *resourcediscovery.BackendNode) string {
+		return fmt.Sprintf("%v %v/%v %v", backendNode.Backend.GetKind(), backendNode.Backend.GetNamespace(), backendNode.Backend.GetName(), effectivePolicyCount(backendNode.EffectivePolicies[gatewayID]))
+	}
+
+	// sharedBackends maps the Backends shared by several HTTPRoutes attached to
+	// the Gateway to those HTTPRoutes.
+	sharedBackends := make(map[*resourcediscovery.BackendNode][]*resourcediscovery.HTTPRouteNode)
+	if dedupBackends {
+		for _, httpRouteNode := range httpRouteNodes {
+			for _, backendNode := range httpRouteNode.Backends {
+				if _, ok := sharedBackends[backendNode]; ok {
+					continue
+				}
+				var httpRoutes []*resourcediscovery.HTTPRouteNode
+				for _, backendHTTPRouteNode := range backendNode.HTTPRoutes {
+					if _, ok := gatewayNode.HTTPRoutes[backendHTTPRouteNode.ID()]; ok {
+						httpRoutes = append(httpRoutes, backendHTTPRouteNode)
+					}
+				}
+				if len(httpRoutes) > 1 {
+					sharedBackends[backendNode] = SortByString(httpRoutes)
+				}
+			}
+		}
+	}
+
+	var result []describeTreeNode
+	for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+		listenerPolicies, ok := gatewayNode.ListenerEffectivePolicies[listener.Name]
+		if !ok {
+			listenerPolicies = gatewayNode.EffectivePolicies
+		}
+		listenerNode := describeTreeNode{
+			line: fmt.Sprintf("Listener %v (%v/%v) %v", listener.Name, listener.Protocol, listener.Port, effectivePolicyCount(listenerPolicies)),
+		}
+		for _, httpRouteNode := range httpRouteNodes {
+			if !httpRouteNode.AttachesToListener(gatewayID, listener.Name) {
+				continue
+			}
+			httpRoutePolicies, ok := httpRouteNode.ListenerEffectivePolicies[gatewayID][listener.Name]
+			if !ok {
+				httpRoutePolicies = httpRouteNode.EffectivePolicies[gatewayID]
+			}
+			httpRouteTreeNode := describeTreeNode{
+				line: fmt.Sprintf("HTTPRoute %v/%v %v", httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName(), effectivePolicyCount(httpRoutePolicies)),
+			}
+			for _, backendNode := range SortByString(common.MapToValues(httpRouteNode.Backends)) {
+				if _, ok := sharedBackends[backendNode]; ok {
+					continue
+				}
+				httpRouteTreeNode.children = append(httpRouteTreeNode.children, describeTreeNode{line: backendLine(backendNode)})
+			}
+			listenerNode.children = append(listenerNode.children, httpRouteTreeNode)
+		}
+		result = append(result, listenerNode)
+	}
+
+	if len(sharedBackends) != 0 {
+		sharedNode := describeTreeNode{line: "Shared backends"}
+		var backendNodes []*resourcediscovery.BackendNode
+		for backendNode := range sharedBackends {
+			backendNodes = append(backendNodes, backendNode)
+		}
+		for _, backendNode := range SortByString(backendNodes) {
+			backendTreeNode := describeTreeNode{line: backendLine(backendNode)}
+			for _, httpRouteNode := range sharedBackends[backendNode] {
+				backendTreeNode.children = append(backendTreeNode.children, describeTreeNode{
+					line: fmt.Sprintf("used by HTTPRoute %v/%v", httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName()),
+				})
+			}
+			sharedNode.children = append(sharedNode.children, backendTreeNode)
+		}
+		result = append(result, sharedNode)
+	}
+	return result
+}
+
+func printDescribeTreeNodes(w io.Writer, nodes []describeTreeNode, prefix string) {
+	for i, node := range nodes {
+		branch, childPrefix := "├── ", prefix+"│   "
+		if i+1 == len(nodes) {
+			branch, childPrefix = "└── ", prefix+"    "
+		}
+		fmt.Fprintf(w, "%v%v%v\n", prefix, branch, node.line)
+		printDescribeTreeNodes(w, node.children, childPrefix)
+	}
+}
+
+// effectivePolicyCount formats the number of effective policies.
+func effectivePolicyCount(policies map[policymanager.PolicyCrdID]policymanager.Policy) string {
+	if len(policies) == 1 {
+		return "(1 effective policy)"
+	}
+	return fmt.Sprintf("(%d effective policies)", len(policies))
+}
+
+// PrintImpact prints the routes which would be affected by deleting the
+// Gateway, split into the routes which would be orphaned and the routes which
+// would survive since they have other parents.
+func (gp *GatewaysPrinter) PrintImpact(gatewayNode *resourcediscovery.GatewayNode) {
+	orphanedRoutes := &Table{
+		ColumnNames:  []string{"Kind", "Name"},
+		UseSeparator: true,
+	}
+	for _, httpRouteNode := range gatewayNode.DependentRoutes() {
+		row := []string{
+			"HTTPRoute", // Kind
+			fmt.Sprintf("%v/%v", httpRouteNode.HTTPRoute.Namespace, httpRouteNode.HTTPRoute.Name), // Name
+		}
+		orphanedRoutes.Rows = append(orphanedRoutes.Rows, row)
+	}
+
+	survivingRoutes := &Table{
+		ColumnNames:  []string{"Kind", "Name", "OtherParents"},
+		UseSeparator: true,
+	}
+	for _, httpRouteNode := range gatewayNode.SharedRoutes() {
+		var otherParents []string
+		for _, parentRef := range httpRouteNode.OtherParents(gatewayNode.ID()) {
+			otherParents = append(otherParents, fmt.Sprintf("%v %v/%v", parentRef.Kind, parentRef.Namespace, parentRef.Name))
+		}
+		row := []string{
+			"HTTPRoute", // Kind
+			fmt.Sprintf("%v/%v", httpRouteNode.HTTPRoute.Namespace, httpRouteNode.HTTPRoute.Name), // Name
+			strings.Join(otherParents, ", "), // OtherParents
+		}
+		survivingRoutes.Rows = append(survivingRoutes.Rows, row)
+	}
+
+	Describe(gp, []*DescriberKV{
+		{Key: "Gateway", Value: fmt.Sprintf("%v/%v", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName())},
+		{Key: "OrphanedRoutes", Value: orphanedRoutes},
+		{Key: "SurvivingRoutes", Value: survivingRoutes},
+	})
+}
+
+// PrintStatus prints the status rollup of each Gateway on a single line and
+// returns true if all Gateways are OK.
+func (gp *GatewaysPrinter) PrintStatus(resourceModel *resourcediscovery.ResourceModel) bool {
+	statuses := make(map[string]resourcediscovery.StatusSummary)
+	for _, gatewayNode := range resourceModel.Gateways {
+		statuses[fmt.Sprintf("%v/%v", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName())] = gatewayNode.StatusSummary()
+	}
+	return printStatusLines(gp, gp.Color, statuses)
+}
@@ -18,6 +18,7 @@ package printer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -56,7 +57,7 @@ func TestGatewaysPrinter_PrintTable(t *testing.T) {
 				Name: "external-class",
 			},
 			Spec: gatewayv1.GatewayClassSpec{
-				ControllerName: "example.net/gateway-controller",
+				ControllerName: "example.com/other-controller",
 				Description:    common.PtrTo("random"),
 			},
 		},
@@ -173,6 +174,26 @@ func TestGatewaysPrinter_PrintTable(t *testing.T) {
 				},
 			},
 		},
+		// The GatewayClass of this Gateway does not exist, so the
+		// implementation managing it is unknown.
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "zzz-gateway",
+				CreationTimestamp: metav1.Time{
+					Time: fakeClock.Now().Add(-1 * time.Hour),
+				},
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "missing-class",
+				Listeners: []gatewayv1.Listener{
+					{
+						Name:     gatewayv1.SectionName("http-80"),
+						Protocol: gatewayv1.HTTPProtocolType,
+						Port:     gatewayv1.PortNumber(80),
+					},
+				},
+			},
+		},
 	}
 
 	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
@@ -180,7 +201,7 @@ func TestGatewaysPrinter_PrintTable(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGateway(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -193,10 +214,11 @@ func TestGatewaysPrinter_PrintTable(t *testing.T) {
 
 	got := params.Out.(*bytes.Buffer).String()
 	want := `
-NAME               CLASS                    ADDRESSES                   PORTS     PROGRAMMED  AGE
-abc-gateway-12345  internal-class           192.168.100.5               443,8080  False       20d
-demo-gateway-2     external-class           10.0.0.1,10.0.0.2 + 1 more  80        True        5d
-random-gateway     regional-internal-class  10.11.12.13                 8443      Unknown     3s
+NAME               CLASS                    IMPLEMENTATION                  ADDRESSES                   PORTS     PROGRAMMED  AGE
+abc-gateway-12345  internal-class           example.net/gateway-controller  192.168.100.5               443,8080  False       20d
+demo-gateway-2     external-class           example.com/other-controller    10.0.0.1,10.0.0.2 + 1 more  80        True        5d
+random-gateway     regional-internal-class  example.net/gateway-controller  10.11.12.13                 8443      Unknown     3s
+zzz-gateway        missing-class            unknown                                                     80        Unknown     60m
 `
 
 	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
@@ -370,7 +392,7 @@ func TestGatewaysPrinter_PrintDescribeView(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGateway(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -387,8 +409,8 @@ Name: foo-gateway
 Namespace: ""
 Labels: null
 Annotations: null
-APIVersion: ""
-Kind: ""
+APIVersion: gateway.networking.k8s.io/v1
+Kind: Gateway
 Metadata:
   creationTimestamp: null
   resourceVersion: "999"
@@ -425,6 +447,195 @@ Events:
 	}
 }
 
+func TestGatewaysPrinter_PrintDescribeViewGroupedByNamespace(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	objects := []runtime.Object{
+		common.NamespaceForTest("ns1"),
+		common.NamespaceForTest("ns2"),
+		common.NamespaceForTest("ns3"),
+
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+			Spec: gatewayv1.GatewayClassSpec{
+				ControllerName: "example.net/gateway-controller",
+			},
+		},
+
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway-1",
+				Namespace: "ns1",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway-2",
+				Namespace: "ns2",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway-3",
+				Namespace: "ns2",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+
+		// HTTPRoute in ns3 results in ns3 being part of the resourceModel, but
+		// since ns3 has no Gateways, it should not be printed.
+		&gatewayv1.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "HTTPRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "ns3",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Kind:      common.PtrTo(gatewayv1.Kind("Gateway")),
+						Group:     common.PtrTo(gatewayv1.Group("gateway.networking.k8s.io")),
+						Name:      "gateway-1",
+						Namespace: common.PtrTo(gatewayv1.Namespace("ns1")),
+					}},
+				},
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name": "timeout-policy-namespace",
+				},
+				"spec": map[string]interface{}{
+					"seconds": int64(30),
+					"targetRef": map[string]interface{}{
+						"kind": "Namespace",
+						"name": "ns1",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gp := &GatewaysPrinter{
+		Writer: params.Out,
+		Clock:  fakeClock,
+	}
+	gp.PrintDescribeViewGroupedByNamespace(resourceModel)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `
+### Namespace: ns1 ###
+NamespacePolicies:
+  Type                   Name
+  ----                   ----
+  TimeoutPolicy.bar.com  /timeout-policy-namespace
+
+Name: gateway-1
+Namespace: ns1
+Labels: null
+Annotations: null
+APIVersion: gateway.networking.k8s.io/v1
+Kind: Gateway
+Metadata:
+  creationTimestamp: null
+  resourceVersion: "999"
+Spec:
+  gatewayClassName: foo-gatewayclass
+  listeners: null
+Status: {}
+AttachedRoutes:
+  Kind       Name
+  ----       ----
+  HTTPRoute  ns3/foo-httproute
+EffectivePolicies:
+  TimeoutPolicy.bar.com:
+    seconds: 30
+Events: <none>
+
+
+### Namespace: ns2 ###
+NamespacePolicies: <none>
+
+Name: gateway-2
+Namespace: ns2
+Labels: null
+Annotations: null
+APIVersion: gateway.networking.k8s.io/v1
+Kind: Gateway
+Metadata:
+  creationTimestamp: null
+  resourceVersion: "999"
+Spec:
+  gatewayClassName: foo-gatewayclass
+  listeners: null
+Status: {}
+AttachedRoutes: <none>
+Events: <none>
+
+
+Name: gateway-3
+Namespace: ns2
+Labels: null
+Annotations: null
+APIVersion: gateway.networking.k8s.io/v1
+Kind: Gateway
+Metadata:
+  creationTimestamp: null
+  resourceVersion: "999"
+Spec:
+  gatewayClassName: foo-gatewayclass
+  listeners: null
+Status: {}
+AttachedRoutes: <none>
+Events: <none>
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
 // TestGatewaysPrinter_PrintJsonYaml tests the -o json/yaml output of the `get` subcommand
 func TestGatewaysPrinter_PrintJsonYaml(t *testing.T) {
 	fakeClock := testingclock.NewFakeClock(time.Now())
@@ -487,7 +698,7 @@ func TestGatewaysPrinter_PrintJsonYaml(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGateway(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
 	}
@@ -653,7 +864,7 @@ func TestGatewaysPrinter_PrintYaml(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGateway(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
 	}
@@ -697,3 +908,378 @@ kind: List`, creationTime.Format(time.RFC3339)))
 		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
 	}
 }
+
+func TestGatewaysPrinter_PrintImpact(t *testing.T) {
+	objects := []runtime.Object{
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}, {Name: "bar-gateway"}},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{Namespace: "default", Name: "foo-gateway"})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gp := &GatewaysPrinter{
+		Writer: params.Out,
+	}
+	gp.PrintImpact(resourceModel.Gateways[resourcediscovery.GatewayID("default", "foo-gateway")])
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `
+Gateway: default/foo-gateway
+OrphanedRoutes:
+  Kind       Name
+  ----       ----
+  HTTPRoute  default/foo-httproute
+SurvivingRoutes:
+  Kind       Name                      OtherParents
+  ----       ----                      ------------
+  HTTPRoute  default/shared-httproute  Gateway default/bar-gateway
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestGatewaysPrinter_PrintDescribeTree(t *testing.T) {
+	healthCheckPolicy := func(name, targetKind, targetName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+					"default": map[string]interface{}{
+						"interval": "10s",
+					},
+				},
+			},
+		}
+	}
+	timeoutPolicy := func(name, targetKind, targetName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+					"default": map[string]interface{}{
+						"timeout": "30s",
+					},
+				},
+			},
+		}
+	}
+	policyCRD := func(group, plural, kind string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   plural + "." + group,
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    group,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	httpRoute := func(name string, sectionName *gatewayv1.SectionName, backendName string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway", SectionName: sectionName}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: gatewayv1.ObjectName(backendName),
+								Port: common.PtrTo(gatewayv1.PortNumber(8080)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				},
+			},
+		},
+		// foo-httproute attaches to all listeners, bar-httproute only to https.
+		httpRoute("foo-httproute", nil, "foo-svc"),
+		httpRoute("bar-httproute", common.PtrTo(gatewayv1.SectionName("https")), "bar-svc"),
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "default"}},
+
+		policyCRD("foo.com", "healthcheckpolicies", "HealthCheckPolicy"),
+		policyCRD("bar.com", "timeoutpolicies", "TimeoutPolicy"),
+		healthCheckPolicy("health-check-gateway", "Gateway", "foo-gateway"),
+		timeoutPolicy("timeout-httproute", "HTTPRoute", "foo-httproute"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), resourcediscovery.Filter{Namespace: "default", Name: "foo-gateway"})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gp := &GatewaysPrinter{
+		Writer: params.Out,
+	}
+	gp.PrintDescribeTree(resourceModel)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `Gateway default/foo-gateway (1 effective policy)
+├── Listener http (HTTP/80) (1 effective policy)
+│   └── HTTPRoute default/foo-httproute (2 effective policies)
+│       └── Service default/foo-svc (2 effective policies)
+└── Listener https (HTTPS/443) (1 effective policy)
+    ├── HTTPRoute default/bar-httproute (1 effective policy)
+    │   └── Service default/bar-svc (1 effective policy)
+    └── HTTPRoute default/foo-httproute (2 effective policies)
+        └── Service default/foo-svc (2 effective policies)
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestGatewaysPrinter_PrintDescribeTree_DedupBackends(t *testing.T) {
+	httpRoute := func(name string, backendNames ...string) *gatewayv1.HTTPRoute {
+		httpRoute := &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+			},
+		}
+		for _, backendName := range backendNames {
+			httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, gatewayv1.HTTPRouteRule{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: gatewayv1.ObjectName(backendName),
+							Port: common.PtrTo(gatewayv1.PortNumber(8080)),
+						},
+					},
+				}},
+			})
+		}
+		return httpRoute
+	}
+
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+				},
+			},
+		},
+		// shared-svc is used by all three HTTPRoutes, while foo-svc is only used
+		// by foo-httproute.
+		httpRoute("bar-httproute", "shared-svc"),
+		httpRoute("baz-httproute", "shared-svc"),
+		httpRoute("foo-httproute", "shared-svc", "foo-svc"),
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "shared-svc", Namespace: "default"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"}},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), resourcediscovery.Filter{Namespace: "default", Name: "foo-gateway"})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gp := &GatewaysPrinter{
+		Writer:        params.Out,
+		DedupBackends: true,
+	}
+	gp.PrintDescribeTree(resourceModel)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `Gateway default/foo-gateway (0 effective policies)
+├── Listener http (HTTP/80) (0 effective policies)
+│   ├── HTTPRoute default/bar-httproute (0 effective policies)
+│   ├── HTTPRoute default/baz-httproute (0 effective policies)
+│   └── HTTPRoute default/foo-httproute (0 effective policies)
+│       └── Service default/foo-svc (0 effective policies)
+└── Shared backends
+    └── Service default/shared-svc (0 effective policies)
+        ├── used by HTTPRoute default/bar-httproute
+        ├── used by HTTPRoute default/baz-httproute
+        └── used by HTTPRoute default/foo-httproute
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestGatewaysPrinter_PrintStatus(t *testing.T) {
+	gateway := func(name string, resolvedRefs metav1.ConditionStatus) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+			Status: gatewayv1.GatewayStatus{
+				Listeners: []gatewayv1.ListenerStatus{{
+					Name:       "web",
+					Conditions: []metav1.Condition{{Type: "ResolvedRefs", Status: resolvedRefs}},
+				}},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name     string
+		gateways []runtime.Object
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:     "all Gateways OK",
+			gateways: []runtime.Object{gateway("foo-gateway", metav1.ConditionTrue)},
+			want:     "default/foo-gateway OK\n",
+			wantOK:   true,
+		},
+		{
+			name: "one Gateway degraded",
+			gateways: []runtime.Object{
+				gateway("foo-gateway", metav1.ConditionTrue),
+				gateway("bar-gateway", metav1.ConditionFalse),
+			},
+			want:   "default/bar-gateway DEGRADED: listener web ResolvedRefs=False\ndefault/foo-gateway OK\n",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, tc.gateways...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), resourcediscovery.Filter{})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			gp := &GatewaysPrinter{Writer: params.Out}
+			gotOK := PrintStatus(gp, resourceModel)
+
+			got := params.Out.(*bytes.Buffer).String()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff (-want +got):\n%v", diff)
+			}
+			if gotOK != tc.wantOK {
+				t.Errorf("PrintStatus() returned %v; want %v", gotOK, tc.wantOK)
+			}
+		})
+	}
+}
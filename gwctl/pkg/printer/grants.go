@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+type GrantsPrinter struct {
+	io.Writer
+}
+
+// PrintGrantChecks prints whether each cross namespace reference from an
+// HTTPRoute to a backend is permitted by a ReferenceGrant, followed by a
+// summary. References to backends which do not exist are reported separately.
+// It returns false if any reference is not permitted or its backend is not
+// found.
+func (gp *GrantsPrinter) PrintGrantChecks(checks []resourcediscovery.GrantCheck) bool {
+	if len(checks) == 0 {
+		fmt.Fprintf(gp, "No cross namespace references from HTTPRoutes to backends found\n")
+		return true
+	}
+
+	table := &Table{ColumnNames: []string{"HTTPROUTE", "BACKEND", "RESULT", "DETAILS"}}
+	var failed, notFound int
+	for _, check := range checks {
+		result, details := "PASS", ""
+		switch {
+		case check.Passed():
+			details = fmt.Sprintf("ReferenceGrant %v/%v", check.ReferenceGrant.Namespace, check.ReferenceGrant.Name)
+		case check.BackendNotFound:
+			notFound++
+			result, details = "NOT FOUND", "backend not found"
+		default:
+			failed++
+			result, details = "FAIL", check.Reason
+		}
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprintf("%v/%v", check.HTTPRoute.Namespace, check.HTTPRoute.Name),
+			resourcediscovery.BackendRefString(check.Backend),
+			result,
+			details,
+		})
+	}
+	table.writeTable(gp, 0)
+
+	fmt.Fprintf(gp, "\n%d of %d cross namespace references are permitted", len(checks)-failed-notFound, len(checks))
+	if failed != 0 {
+		fmt.Fprintf(gp, "; %d are not", failed)
+	}
+	if notFound != 0 {
+		fmt.Fprintf(gp, "; %d reference backends which are not found", notFound)
+	}
+	fmt.Fprintf(gp, "\n")
+	return failed == 0 && notFound == 0
+}
+
+type referenceGrantDescribeView struct {
+	Name      string                              `json:",omitempty"`
+	Namespace string                              `json:",omitempty"`
+	From      []gatewayv1beta1.ReferenceGrantFrom `json:",omitempty"`
+	To        []gatewayv1beta1.ReferenceGrantTo   `json:",omitempty"`
+	// ExposedBackends lists the Backends in the model which the ReferenceGrant
+	// exposes.
+	ExposedBackends []string `json:",omitempty"`
+	// EnabledReferences lists the cross namespace references in the model
+	// which rely on the ReferenceGrant.
+	EnabledReferences []string `json:",omitempty"`
+}
+
+// PrintDescribeView prints the spec of each ReferenceGrant, along with the
+// Backends it exposes and the cross namespace references it permits.
+func (gp *GrantsPrinter) PrintDescribeView(resourceModel *resourcediscovery.ResourceModel) {
+	referenceGrantNodes := SortByString(common.MapToValues(resourceModel.ReferenceGrants))
+	for index, referenceGrantNode := range referenceGrantNodes {
+		referenceGrant := referenceGrantNode.ReferenceGrant
+		views := []referenceGrantDescribeView{
+			{
+				Name:      referenceGrant.GetName(),
+				Namespace: referenceGrant.GetNamespace(),
+			},
+			{
+				From: referenceGrant.Spec.From,
+			},
+			{
+				To: referenceGrant.Spec.To,
+			},
+		}
+
+		var exposedBackends []string
+		for _, backendNode := range SortByString(common.MapToValues(referenceGrantNode.Backends)) {
+			exposedBackends = append(exposedBackends, resourcediscovery.BackendRefString(common.ObjRef{
+				Group:     backendNode.Backend.GroupVersionKind().Group,
+				Kind:      backendNode.Backend.GetKind(),
+				Name:      backendNode.Backend.GetName(),
+				Namespace: backendNode.Backend.GetNamespace(),
+			}))
+		}
+		if len(exposedBackends) != 0 {
+			views = append(views, referenceGrantDescribeView{
+				ExposedBackends: exposedBackends,
+			})
+		}
+
+		var enabledReferences []string
+		for _, ref := range referenceGrantNode.EnabledReferences() {
+			enabledReferences = append(enabledReferences, fmt.Sprintf("%v %v/%v -> %v %v/%v",
+				ref.From.Kind, ref.From.Namespace, ref.From.Name, ref.To.Kind, ref.To.Namespace, ref.To.Name))
+		}
+		if len(enabledReferences) != 0 {
+			views = append(views, referenceGrantDescribeView{
+				EnabledReferences: enabledReferences,
+			})
+		}
+
+		for _, view := range views {
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprint(gp, string(b))
+		}
+
+		if index+1 != len(referenceGrantNodes) {
+			fmt.Fprintf(gp, "\n\n")
+		}
+	}
+}
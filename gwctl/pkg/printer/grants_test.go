@@ -0,0 +1,358 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestGrantsPrinter_PrintGrantChecks(t *testing.T) {
+	backendRef := func(namespace, name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Kind:      common.PtrTo(gatewayv1.Kind("Service")),
+					Name:      gatewayv1.ObjectName(name),
+					Namespace: common.PtrTo(gatewayv1.Namespace(namespace)),
+					Port:      common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+			},
+		}
+	}
+	httpRoute := func(backendRefs ...gatewayv1.HTTPBackendRef) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+	}
+	service := func(namespace, name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+	}
+	referenceGrant := func(namespace, fromNamespace string) *gatewayv1beta1.ReferenceGrant {
+		return &gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-" + fromNamespace,
+				Namespace: namespace,
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "HTTPRoute",
+					Namespace: gatewayv1.Namespace(fromNamespace),
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{
+					Kind: "Service",
+				}},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name    string
+		objects []runtime.Object
+		wantOK  bool
+		want    string
+	}{
+		{
+			name: "permitted and unpermitted references",
+			objects: []runtime.Object{
+				httpRoute(
+					backendRef("default", "local-svc"),
+					backendRef("bar", "bar-svc"),
+					backendRef("baz", "baz-svc"),
+					backendRef("baz", "missing-svc"),
+				),
+				service("default", "local-svc"),
+				service("bar", "bar-svc"),
+				service("baz", "baz-svc"),
+				referenceGrant("bar", "default"),
+				// Permits references from another namespace only.
+				referenceGrant("baz", "other"),
+			},
+			wantOK: false,
+			want: `
+HTTPROUTE              BACKEND                  RESULT     DETAILS
+default/foo-httproute  Service bar/bar-svc      PASS       ReferenceGrant bar/allow-default
+default/foo-httproute  Service baz/baz-svc      FAIL       no ReferenceGrant permits the reference
+default/foo-httproute  Service baz/missing-svc  NOT FOUND  backend not found
+
+1 of 3 cross namespace references are permitted; 1 are not; 1 reference backends which are not found
+`,
+		},
+		{
+			name: "only permitted references",
+			objects: []runtime.Object{
+				httpRoute(
+					backendRef("default", "local-svc"),
+					backendRef("bar", "bar-svc"),
+				),
+				service("default", "local-svc"),
+				service("bar", "bar-svc"),
+				referenceGrant("bar", "default"),
+			},
+			wantOK: true,
+			want: `
+HTTPROUTE              BACKEND              RESULT  DETAILS
+default/foo-httproute  Service bar/bar-svc  PASS    ReferenceGrant bar/allow-default
+
+1 of 1 cross namespace references are permitted
+`,
+		},
+		{
+			name: "no cross namespace references",
+			objects: []runtime.Object{
+				httpRoute(backendRef("default", "local-svc")),
+				service("default", "local-svc"),
+			},
+			wantOK: true,
+			want: `
+No cross namespace references from HTTPRoutes to backends found
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := append([]runtime.Object{
+				common.NamespaceForTest("default"),
+				common.NamespaceForTest("bar"),
+				common.NamespaceForTest("baz"),
+			}, tc.objects...)
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := resourcediscovery.Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			out := &bytes.Buffer{}
+			gp := &GrantsPrinter{Writer: out}
+			gotOK := gp.PrintGrantChecks(resourceModel.VerifyGrants())
+
+			if gotOK != tc.wantOK {
+				t.Errorf("PrintGrantChecks() = %v, want %v", gotOK, tc.wantOK)
+			}
+			got := out.String()
+			if diff := cmp.Diff(common.YamlString(tc.want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+				t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestGrantsPrinter_PrintDescribeView(t *testing.T) {
+	httpRoute := func(namespace, name string, backendRefs ...gatewayv1.HTTPBackendRef) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+	}
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name:      gatewayv1.ObjectName(name),
+					Namespace: common.PtrTo(gatewayv1.Namespace("bar")),
+					Port:      common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("bar"),
+		common.NamespaceForTest("other"),
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-default",
+				Namespace: "bar",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "HTTPRoute",
+					Namespace: "default",
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{
+					Kind: "Service",
+					Name: common.PtrTo(gatewayv1.ObjectName("bar-svc")),
+				}},
+			},
+		},
+		&corev1.Service{TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "bar"}},
+		// Not exposed by the ReferenceGrant.
+		&corev1.Service{TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: "private-svc", Namespace: "bar"}},
+		httpRoute("default", "foo-httproute", backendRef("bar-svc"), backendRef("private-svc")),
+		// Not permitted by the ReferenceGrant, since it is in another namespace.
+		httpRoute("other", "other-httproute", backendRef("bar-svc")),
+		// Does not rely on the ReferenceGrant, since it is in the same namespace.
+		httpRoute("bar", "bar-httproute", backendRef("bar-svc")),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForReferenceGrant(context.Background(), resourcediscovery.Filter{Namespace: "bar", Name: "allow-default", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	gp := &GrantsPrinter{Writer: out}
+	gp.PrintDescribeView(resourceModel)
+
+	got := out.String()
+	want := `
+Name: allow-default
+Namespace: bar
+From:
+- group: gateway.networking.k8s.io
+  kind: HTTPRoute
+  namespace: default
+To:
+- group: ""
+  kind: Service
+  name: bar-svc
+ExposedBackends:
+- Service bar/bar-svc
+EnabledReferences:
+- HTTPRoute default/foo-httproute -> Service bar/bar-svc
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+// TestGrantsPrinter_PrintDescribeView_Gateways tests that the HTTPRoutes
+// attaching to the Gateways exposed by a ReferenceGrant are listed as enabled
+// references.
+func TestGrantsPrinter_PrintDescribeView_Gateways(t *testing.T) {
+	httpRoute := func(namespace, name string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Name:      "bar-gateway",
+						Namespace: common.PtrTo(gatewayv1.Namespace("bar")),
+					}},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("bar"),
+		common.NamespaceForTest("other"),
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-default",
+				Namespace: "bar",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "HTTPRoute",
+					Namespace: "default",
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{
+					Group: gatewayv1.GroupName,
+					Kind:  "Gateway",
+				}},
+			},
+		},
+		&gatewayv1.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"}},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-gateway", Namespace: "bar"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		httpRoute("default", "foo-httproute"),
+		// Not permitted by the ReferenceGrant, since it is in another namespace.
+		httpRoute("other", "other-httproute"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForReferenceGrant(context.Background(), resourcediscovery.Filter{Namespace: "bar", Name: "allow-default", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	gp := &GrantsPrinter{Writer: out}
+	gp.PrintDescribeView(resourceModel)
+
+	got := out.String()
+	want := `
+Name: allow-default
+Namespace: bar
+From:
+- group: gateway.networking.k8s.io
+  kind: HTTPRoute
+  namespace: default
+To:
+- group: gateway.networking.k8s.io
+  kind: Gateway
+EnabledReferences:
+- HTTPRoute default/foo-httproute -> Gateway bar/bar-gateway
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
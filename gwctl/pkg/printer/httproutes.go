@@ -20,17 +20,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/utils/clock"
-	"sigs.k8s.io/yaml"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
 var _ Printer = (*HTTPRoutesPrinter)(nil)
@@ -38,6 +39,10 @@ var _ Printer = (*HTTPRoutesPrinter)(nil)
 type HTTPRoutesPrinter struct {
 	io.Writer
 	Clock clock.Clock
+	Color Colorizer
+	// Latency includes the configuration of each HTTPRoute which affects the
+	// latency of its requests in the describe view.
+	Latency bool
 }
 
 func (hp *HTTPRoutesPrinter) GetPrintableNodes(resourceModel *resourcediscovery.ResourceModel) []NodeResource {
@@ -46,7 +51,7 @@ func (hp *HTTPRoutesPrinter) GetPrintableNodes(resourceModel *resourcediscovery.
 
 func (hp *HTTPRoutesPrinter) PrintTable(resourceModel *resourcediscovery.ResourceModel) {
 	tw := tabwriter.NewWriter(hp, 0, 0, 2, ' ', 0)
-	row := []string{"NAMESPACE", "NAME", "HOSTNAMES", "PARENT REFS", "AGE"}
+	row := []string{"NAMESPACE", "NAME", "HOSTNAMES", "PARENT REFS", "TYPE", "AGE"}
 	_, err := tw.Write([]byte(strings.Join(row, "\t") + "\n"))
 	if err != nil {
 		fmt.Fprint(os.Stderr, err)
@@ -78,6 +83,7 @@ func (hp *HTTPRoutesPrinter) PrintTable(resourceModel *resourcediscovery.Resourc
 			httpRouteNode.HTTPRoute.GetName(),
 			hostNamesOutput,
 			parentRefsCount,
+			httpRouteType(httpRouteNode),
 			age,
 		}
 		_, err := tw.Write([]byte(strings.Join(row, "\t") + "\n"))
@@ -89,13 +95,182 @@ func (hp *HTTPRoutesPrinter) PrintTable(resourceModel *resourcediscovery.Resourc
 	tw.Flush()
 }
 
+// httpRouteType returns whether the HTTPRoute configures ingress traffic
+// (attached to a Gateway), mesh traffic (attached to a Service), or both.
+func httpRouteType(httpRouteNode *resourcediscovery.HTTPRouteNode) string {
+	var types []string
+	if httpRouteNode.IsIngressRoute() {
+		types = append(types, "Ingress")
+	}
+	if httpRouteNode.IsMeshRoute() {
+		types = append(types, "Mesh")
+	}
+	if len(types) == 0 {
+		return "None"
+	}
+	return strings.Join(types, ",")
+}
+
 type httpRouteDescribeView struct {
 	Name                     string                      `json:",omitempty"`
 	Namespace                string                      `json:",omitempty"`
+	Type                     string                      `json:",omitempty"`
 	Hostnames                []gatewayv1.Hostname        `json:",omitempty"`
 	ParentRefs               []gatewayv1.ParentReference `json:",omitempty"`
+	EffectiveHostnames       map[string][]string         `json:",omitempty"`
+	AccessURLs               map[string][]string         `json:",omitempty"`
+	Filters                  []string                    `json:",omitempty"`
+	NamedBackendPorts        []string                    `json:",omitempty"`
+	BackendWeights           []backendWeightView         `json:",omitempty"`
+	RequestHeaders           []headersView               `json:",omitempty"`
+	ResponseHeaders          []headersView               `json:",omitempty"`
+	ExtensionRefs            []extensionRefView          `json:",omitempty"`
+	Secrets                  []string                    `json:",omitempty"`
 	DirectlyAttachedPolicies []policymanager.ObjRef      `json:",omitempty"`
 	EffectivePolicies        any                         `json:",omitempty"`
+	RuleEffectivePolicies    any                         `json:",omitempty"`
+	MeshEffectivePolicies    any                         `json:",omitempty"`
+	LatencyProfile           *latencyProfileView         `json:",omitempty"`
+}
+
+// latencyProfileView describes the configuration of the HTTPRoute which affects
+// the latency of its requests.
+type latencyProfileView struct {
+	RuleTimeouts    []resourcediscovery.RuleTimeouts `json:",omitempty"`
+	BackendSettings []latencySettingView             `json:",omitempty"`
+}
+
+// latencySettingView describes a field of the effective policy of a backend of
+// the HTTPRoute which affects latency.
+type latencySettingView struct {
+	Backend string
+	Gateway string `json:",omitempty"`
+	Policy  policymanager.PolicyCrdID
+	Kind    resourcediscovery.LatencySettingKind
+	Field   string
+	Value   interface{}
+}
+
+// newLatencyProfileView returns the view of the LatencyProfile of the
+// HTTPRoute.
+func newLatencyProfileView(httpRouteNode *resourcediscovery.HTTPRouteNode) *latencyProfileView {
+	profile := httpRouteNode.LatencyProfile()
+	view := &latencyProfileView{RuleTimeouts: profile.RuleTimeouts}
+	for _, setting := range profile.BackendSettings {
+		settingView := latencySettingView{
+			Backend: resourcediscovery.BackendRefString(setting.Backend),
+			Policy:  setting.PolicyKind,
+			Kind:    setting.Kind,
+			Field:   setting.Path,
+			Value:   setting.Value,
+		}
+		if setting.Gateway.Name != "" {
+			settingView.Gateway = fmt.Sprintf("%v/%v", setting.Gateway.Namespace, setting.Gateway.Name)
+		}
+		view.BackendSettings = append(view.BackendSettings, settingView)
+	}
+	return view
+}
+
+// headersView describes how a RequestHeaderModifier or ResponseHeaderModifier
+// filter in a rule of the HTTPRoute manipulates the headers of requests or
+// responses.
+type headersView struct {
+	Rule   int
+	Set    []gatewayv1.HTTPHeader `json:",omitempty"`
+	Add    []gatewayv1.HTTPHeader `json:",omitempty"`
+	Remove []string               `json:",omitempty"`
+}
+
+// headersViews returns the views of the header filters of the HTTPRoute which
+// headerFilter returns for its filters, skipping the filters for which it
+// returns nil.
+func headersViews(httpRouteNode *resourcediscovery.HTTPRouteNode, headerFilter func(resourcediscovery.FilterSummary) *gatewayv1.HTTPHeaderFilter) []headersView {
+	var result []headersView
+	for _, filter := range httpRouteNode.Filters {
+		if modifier := headerFilter(filter); modifier != nil {
+			result = append(result, headersView{
+				Rule:   filter.RuleIndex,
+				Set:    modifier.Set,
+				Add:    modifier.Add,
+				Remove: modifier.Remove,
+			})
+		}
+	}
+	return result
+}
+
+// backendWeightView describes the share of the traffic matched by a rule of
+// the HTTPRoute which a backendRef of the rule receives.
+type backendWeightView struct {
+	Rule       int
+	Backend    string
+	Weight     string
+	Percentage string
+}
+
+// backendWeightViews returns the weights of the backendRefs of the rules of
+// the HTTPRoute which split traffic across several backendRefs.
+func backendWeightViews(httpRouteNode *resourcediscovery.HTTPRouteNode) []backendWeightView {
+	var result []backendWeightView
+	for _, weight := range httpRouteNode.BackendWeights() {
+		if len(httpRouteNode.HTTPRoute.Spec.Rules[weight.RuleIndex].BackendRefs) < 2 {
+			continue
+		}
+		view := backendWeightView{
+			Rule:       weight.RuleIndex,
+			Backend:    resourcediscovery.BackendRefString(weight.BackendRef),
+			Weight:     fmt.Sprintf("%d", weight.Weight),
+			Percentage: fmt.Sprintf("%.1f%%", weight.Percentage),
+		}
+		if weight.WeightOmitted {
+			view.Weight += " (default)"
+		}
+		result = append(result, view)
+	}
+	return result
+}
+
+// extensionRefView describes an object referenced by an ExtensionRef filter of
+// the HTTPRoute, along with the summary of its configuration.
+type extensionRefView struct {
+	Kind    string
+	Name    string
+	Summary string `json:",omitempty"`
+}
+
+func extensionRefViews(httpRouteNode *resourcediscovery.HTTPRouteNode) []extensionRefView {
+	var result []extensionRefView
+	for _, extensionRefNode := range httpRouteNode.ExtensionRefs {
+		gvk := extensionRefNode.Object.GroupVersionKind()
+		result = append(result, extensionRefView{
+			Kind:    fmt.Sprintf("%v.%v", gvk.Kind, gvk.Group),
+			Name:    extensionRefNode.Object.GetName(),
+			Summary: extensionRefNode.Summary(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// secretNames returns the namespace/name of the Secrets referenced through the
+// filters of the HTTPRoute, sorted, with missing Secrets marked as such.
+func secretNames(httpRouteNode *resourcediscovery.HTTPRouteNode) []string {
+	var result []string
+	for _, secretNode := range httpRouteNode.Secrets {
+		name := fmt.Sprintf("%v/%v", secretNode.Secret.GetNamespace(), secretNode.Secret.GetName())
+		if secretNode.Missing {
+			name += " (missing)"
+		}
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
 }
 
 func (hp *HTTPRoutesPrinter) PrintDescribeView(resourceModel *resourcediscovery.ResourceModel) {
@@ -107,12 +282,78 @@ func (hp *HTTPRoutesPrinter) PrintDescribeView(resourceModel *resourcediscovery.
 			{
 				Name:      httpRouteNode.HTTPRoute.GetName(),
 				Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+				Type:      httpRouteType(httpRouteNode),
 			},
 			{
 				Hostnames:  httpRouteNode.HTTPRoute.Spec.Hostnames,
 				ParentRefs: httpRouteNode.HTTPRoute.Spec.ParentRefs,
 			},
 		}
+		// EffectiveHostnames are the hostnames served through each Gateway,
+		// which may differ from the declared Hostnames.
+		if effectiveHostnames := httpRouteNode.EffectiveHostnames(); len(effectiveHostnames) != 0 {
+			view := httpRouteDescribeView{EffectiveHostnames: make(map[string][]string)}
+			for gatewayID, hostnames := range effectiveHostnames {
+				view.EffectiveHostnames[fmt.Sprintf("%v/%v", gatewayID.Namespace, gatewayID.Name)] = hostnames
+			}
+			views = append(views, view)
+		}
+		// AccessURLs are the URLs through which the HTTPRoute is reached at the
+		// addresses of each Gateway.
+		if accessURLs := httpRouteNode.AccessURLs(); len(accessURLs) != 0 {
+			view := httpRouteDescribeView{AccessURLs: make(map[string][]string)}
+			for gatewayID, urls := range accessURLs {
+				view.AccessURLs[fmt.Sprintf("%v/%v", gatewayID.Namespace, gatewayID.Name)] = urls
+			}
+			views = append(views, view)
+		}
+		if len(httpRouteNode.Filters) != 0 {
+			var filters []string
+			for _, filter := range httpRouteNode.Filters {
+				filters = append(filters, filter.String())
+			}
+			views = append(views, httpRouteDescribeView{
+				Filters: filters,
+			})
+		}
+		if len(httpRouteNode.NamedBackendPorts) != 0 {
+			var namedBackendPorts []string
+			for _, namedPort := range httpRouteNode.NamedBackendPorts {
+				namedBackendPorts = append(namedBackendPorts, namedPort.String())
+			}
+			views = append(views, httpRouteDescribeView{
+				NamedBackendPorts: namedBackendPorts,
+			})
+		}
+		if backendWeights := backendWeightViews(httpRouteNode); len(backendWeights) != 0 {
+			views = append(views, httpRouteDescribeView{
+				BackendWeights: backendWeights,
+			})
+		}
+		if requestHeaders := headersViews(httpRouteNode, func(filter resourcediscovery.FilterSummary) *gatewayv1.HTTPHeaderFilter {
+			return filter.RequestHeaderModifier
+		}); len(requestHeaders) != 0 {
+			views = append(views, httpRouteDescribeView{
+				RequestHeaders: requestHeaders,
+			})
+		}
+		if responseHeaders := headersViews(httpRouteNode, func(filter resourcediscovery.FilterSummary) *gatewayv1.HTTPHeaderFilter {
+			return filter.ResponseHeaderModifier
+		}); len(responseHeaders) != 0 {
+			views = append(views, httpRouteDescribeView{
+				ResponseHeaders: responseHeaders,
+			})
+		}
+		if extensionRefs := extensionRefViews(httpRouteNode); len(extensionRefs) != 0 {
+			views = append(views, httpRouteDescribeView{
+				ExtensionRefs: extensionRefs,
+			})
+		}
+		if secrets := secretNames(httpRouteNode); len(secrets) != 0 {
+			views = append(views, httpRouteDescribeView{
+				Secrets: secrets,
+			})
+		}
 		if policyRefs := resourcediscovery.ConvertPoliciesMapToPolicyRefs(httpRouteNode.Policies); len(policyRefs) != 0 {
 			views = append(views, httpRouteDescribeView{
 				DirectlyAttachedPolicies: policyRefs,
@@ -123,9 +364,35 @@ func (hp *HTTPRoutesPrinter) PrintDescribeView(resourceModel *resourcediscovery.
 				EffectivePolicies: httpRouteNode.EffectivePolicies,
 			})
 		}
+		// Effective policies of rules are only shown for rules with rule-scoped
+		// policies, since all other rules share the effective policies of the
+		// HTTPRoute.
+		if rules := httpRouteNode.RulesWithScopedPolicies(); len(rules) != 0 && len(httpRouteNode.RuleEffectivePolicies) != 0 {
+			ruleEffectivePolicies := make(map[string]map[string]map[policymanager.PolicyCrdID]policymanager.Policy)
+			for gatewayID, policiesByRule := range httpRouteNode.RuleEffectivePolicies {
+				gatewayRef := fmt.Sprintf("%v/%v", gatewayID.Namespace, gatewayID.Name)
+				ruleEffectivePolicies[gatewayRef] = make(map[string]map[policymanager.PolicyCrdID]policymanager.Policy)
+				for _, rule := range rules {
+					ruleEffectivePolicies[gatewayRef][rule] = policiesByRule[rule]
+				}
+			}
+			views = append(views, httpRouteDescribeView{
+				RuleEffectivePolicies: ruleEffectivePolicies,
+			})
+		}
+		if len(httpRouteNode.MeshEffectivePolicies) != 0 {
+			views = append(views, httpRouteDescribeView{
+				MeshEffectivePolicies: httpRouteNode.MeshEffectivePolicies,
+			})
+		}
+		if hp.Latency {
+			views = append(views, httpRouteDescribeView{
+				LatencyProfile: newLatencyProfileView(httpRouteNode),
+			})
+		}
 
 		for _, view := range views {
-			b, err := yaml.Marshal(view)
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 				os.Exit(1)
@@ -138,3 +405,159 @@ func (hp *HTTPRoutesPrinter) PrintDescribeView(resourceModel *resourcediscovery.
 		}
 	}
 }
+
+// PrintStatus prints the status rollup of each HTTPRoute on a single line and
+// returns true if all HTTPRoutes are OK.
+func (hp *HTTPRoutesPrinter) PrintStatus(resourceModel *resourcediscovery.ResourceModel) bool {
+	statuses := make(map[string]resourcediscovery.StatusSummary)
+	for _, httpRouteNode := range resourceModel.HTTPRoutes {
+		statuses[fmt.Sprintf("%v/%v", httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName())] = httpRouteNode.StatusSummary()
+	}
+	return printStatusLines(hp, hp.Color, statuses)
+}
+
+// routeTraceView describes a level of the path of an HTTPRoute through one
+// Gateway. Levels are printed as separate views, from the GatewayClass down to
+// the Backends, since the keys of a single view would be printed sorted.
+type routeTraceView struct {
+	GatewayClass *traceLevelView  `json:",omitempty"`
+	Gateway      *traceLevelView  `json:",omitempty"`
+	HTTPRoute    *traceLevelView  `json:",omitempty"`
+	Backends     []traceLevelView `json:",omitempty"`
+}
+
+// traceLevelView describes a single level of a routeTraceView.
+type traceLevelView struct {
+	Kind                      string                 `json:",omitempty"`
+	Name                      string                 `json:",omitempty"`
+	Namespace                 string                 `json:",omitempty"`
+	ControllerName            string                 `json:",omitempty"`
+	Listeners                 []string               `json:",omitempty"`
+	Hostnames                 []gatewayv1.Hostname   `json:",omitempty"`
+	Matches                   []string               `json:",omitempty"`
+	Filters                   []string               `json:",omitempty"`
+	ReadyEndpoints            *int                   `json:",omitempty"`
+	DirectlyAttachedPolicies  []policymanager.ObjRef `json:",omitempty"`
+	EffectivePolicies         any                    `json:",omitempty"`
+	ListenerEffectivePolicies any                    `json:",omitempty"`
+}
+
+// PrintTraces prints each RouteTrace of an HTTPRoute, preceded by a header
+// naming the Gateway it goes through.
+func (hp *HTTPRoutesPrinter) PrintTraces(traces []resourcediscovery.RouteTrace) {
+	for i, trace := range traces {
+		fmt.Fprintf(hp, "### Gateway: %v/%v ###\n", trace.Gateway.Gateway.GetNamespace(), trace.Gateway.Gateway.GetName())
+
+		for _, view := range newRouteTraceViews(trace) {
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprint(hp, string(b))
+		}
+
+		if i+1 < len(traces) {
+			fmt.Fprintf(hp, "\n\n")
+		}
+	}
+}
+
+// newRouteTraceViews returns the views of the levels of the RouteTrace, in
+// order.
+func newRouteTraceViews(trace resourcediscovery.RouteTrace) []routeTraceView {
+	gatewayClassView := &traceLevelView{
+		Name: string(trace.Gateway.Gateway.Spec.GatewayClassName),
+	}
+	gatewayView := &traceLevelView{
+		Name:                     trace.Gateway.Gateway.GetName(),
+		Namespace:                trace.Gateway.Gateway.GetNamespace(),
+		DirectlyAttachedPolicies: resourcediscovery.ConvertPoliciesMapToPolicyRefs(trace.Gateway.Policies),
+	}
+	httpRouteView := &traceLevelView{
+		Name:                     trace.HTTPRoute.HTTPRoute.GetName(),
+		Namespace:                trace.HTTPRoute.HTTPRoute.GetNamespace(),
+		Hostnames:                trace.HTTPRoute.HTTPRoute.Spec.Hostnames,
+		DirectlyAttachedPolicies: resourcediscovery.ConvertPoliciesMapToPolicyRefs(trace.HTTPRoute.Policies),
+	}
+	var backendViews []traceLevelView
+
+	if trace.GatewayClass == nil {
+		gatewayClassView.Name += " (not found)"
+	} else {
+		gatewayClassView.ControllerName = string(trace.GatewayClass.GatewayClass.Spec.ControllerName)
+		gatewayClassView.DirectlyAttachedPolicies = resourcediscovery.ConvertPoliciesMapToPolicyRefs(trace.GatewayClass.Policies)
+	}
+	if len(trace.GatewayClassEffectivePolicies) != 0 {
+		gatewayClassView.EffectivePolicies = trace.GatewayClassEffectivePolicies
+	}
+
+	for _, listener := range trace.Listeners {
+		gatewayView.Listeners = append(gatewayView.Listeners, fmt.Sprintf("%v (%v/%d)", listener.Name, listener.Protocol, listener.Port))
+	}
+	if len(trace.Gateway.EffectivePolicies) != 0 {
+		gatewayView.EffectivePolicies = trace.Gateway.EffectivePolicies
+	}
+	if len(trace.ListenerEffectivePolicies) != 0 {
+		gatewayView.ListenerEffectivePolicies = trace.ListenerEffectivePolicies
+	}
+
+	for _, match := range resourcediscovery.HTTPRouteMatches(trace.HTTPRoute.HTTPRoute) {
+		httpRouteView.Matches = append(httpRouteView.Matches, traceMatchString(match))
+	}
+	for _, filter := range trace.HTTPRoute.Filters {
+		httpRouteView.Filters = append(httpRouteView.Filters, filter.String())
+	}
+	if len(trace.HTTPRouteEffectivePolicies) != 0 {
+		httpRouteView.EffectivePolicies = trace.HTTPRouteEffectivePolicies
+	}
+	if len(trace.HTTPRouteListenerEffectivePolicies) != 0 {
+		httpRouteView.ListenerEffectivePolicies = trace.HTTPRouteListenerEffectivePolicies
+	}
+
+	for _, backendTrace := range trace.Backends {
+		backendNode := backendTrace.Backend
+		backendView := traceLevelView{
+			Kind:                     backendNode.Backend.GetKind(),
+			Name:                     backendNode.Backend.GetName(),
+			Namespace:                backendNode.Backend.GetNamespace(),
+			DirectlyAttachedPolicies: resourcediscovery.ConvertPoliciesMapToPolicyRefs(backendNode.Policies),
+		}
+		if backendNode.EndpointsDiscovered {
+			backendView.ReadyEndpoints = common.PtrTo(backendNode.ReadyEndpoints)
+		}
+		if len(backendTrace.EffectivePolicies) != 0 {
+			backendView.EffectivePolicies = backendTrace.EffectivePolicies
+		}
+		if len(backendTrace.ListenerEffectivePolicies) != 0 {
+			backendView.ListenerEffectivePolicies = backendTrace.ListenerEffectivePolicies
+		}
+		backendViews = append(backendViews, backendView)
+	}
+
+	views := []routeTraceView{
+		{GatewayClass: gatewayClassView},
+		{Gateway: gatewayView},
+		{HTTPRoute: httpRouteView},
+	}
+	if len(backendViews) != 0 {
+		views = append(views, routeTraceView{Backends: backendViews})
+	}
+	return views
+}
+
+// traceMatchString returns a single line summary of the match, e.g.
+// "Rule 0: PathPrefix /foo GET".
+func traceMatchString(match resourcediscovery.HTTPRouteMatch) string {
+	parts := []string{fmt.Sprintf("Rule %d:", match.RuleIndex), string(match.PathType), match.PathValue}
+	if match.Method != "" {
+		parts = append(parts, match.Method)
+	}
+	if len(match.Headers) != 0 {
+		parts = append(parts, "headers="+strings.Join(match.Headers, ","))
+	}
+	if len(match.QueryParams) != 0 {
+		parts = append(parts, "queryParams="+strings.Join(match.QueryParams, ","))
+	}
+	return strings.Join(parts, " ")
+}
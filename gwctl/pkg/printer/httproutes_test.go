@@ -18,6 +18,7 @@ package printer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -210,7 +211,7 @@ func TestHTTPRoutesPrinter_PrintTable(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -224,11 +225,11 @@ func TestHTTPRoutesPrinter_PrintTable(t *testing.T) {
 
 	got := params.Out.(*bytes.Buffer).String()
 	want := `
-NAMESPACE  NAME                 HOSTNAMES                          PARENT REFS  AGE
-default    foo-httproute-1      example.com,example2.com + 1 more  1            24h
-default    qmn-httproute-100    example.com                        2            11h
-ns1        bar-route-21         foo.com,bar.com + 5 more           1            9h
-ns2        bax-httproute-18777  None                               1            5m
+NAMESPACE  NAME                 HOSTNAMES                          PARENT REFS  TYPE     AGE
+default    foo-httproute-1      example.com,example2.com + 1 more  1            Ingress  24h
+default    qmn-httproute-100    example.com                        2            Ingress  11h
+ns1        bar-route-21         foo.com,bar.com + 5 more           1            Ingress  9h
+ns2        bax-httproute-18777  None                               1            Ingress  5m
 `
 	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
 		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
@@ -280,6 +281,12 @@ func TestHTTPRoutesPrinter_PrintDescribeView(t *testing.T) {
 			},
 			Spec: gatewayv1.GatewaySpec{
 				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+					Hostname: common.PtrTo(gatewayv1.Hostname("*.example.com")),
+				}},
 			},
 		},
 		&unstructured.Unstructured{
@@ -309,9 +316,13 @@ func TestHTTPRoutesPrinter_PrintDescribeView(t *testing.T) {
 
 		&gatewayv1.HTTPRoute{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "foo-httproute",
+				Name:      "foo-httproute",
+				Namespace: "default",
 			},
 			Spec: gatewayv1.HTTPRouteSpec{
+				// Only foo.example.com is served through the listener of
+				// foo-gateway.
+				Hostnames: []gatewayv1.Hostname{"foo.example.com", "bar.com"},
 				CommonRouteSpec: gatewayv1.CommonRouteSpec{
 					ParentRefs: []gatewayv1.ParentReference{{
 						Kind:  common.PtrTo(gatewayv1.Kind("Gateway")),
@@ -319,6 +330,26 @@ func TestHTTPRoutesPrinter_PrintDescribeView(t *testing.T) {
 						Name:  "foo-gateway",
 					}},
 				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+						ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+							Set:    []gatewayv1.HTTPHeader{{Name: "Cache-Control", Value: "no-store"}},
+							Remove: []string{"X-Powered-By"},
+						},
+					}},
+				}, {
+					// The omitted weight of bar-svc defaults to 1.
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{Name: "foo-svc", Port: common.PtrTo(gatewayv1.PortNumber(80))},
+							Weight:                 common.PtrTo(int32(3)),
+						}},
+						{BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{Name: "bar-svc", Port: common.PtrTo(gatewayv1.PortNumber(80))},
+						}},
+					},
+				}},
 			},
 		},
 		&unstructured.Unstructured{
@@ -399,7 +430,7 @@ func TestHTTPRoutesPrinter_PrintDescribeView(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -413,10 +444,39 @@ func TestHTTPRoutesPrinter_PrintDescribeView(t *testing.T) {
 	got := params.Out.(*bytes.Buffer).String()
 	want := `
 Name: foo-httproute
+Namespace: default
+Type: Ingress
+Hostnames:
+- foo.example.com
+- bar.com
 ParentRefs:
 - group: gateway.networking.k8s.io
   kind: Gateway
   name: foo-gateway
+EffectiveHostnames:
+  default/foo-gateway:
+  - foo.example.com
+AccessURLs:
+  default/foo-gateway:
+  - http://foo.example.com
+Filters:
+- 'Rule 0: ResponseHeaderModifier (set=[Cache-Control:no-store] remove=[X-Powered-By])'
+BackendWeights:
+- Backend: Service default/foo-svc
+  Percentage: 75.0%
+  Rule: 1
+  Weight: "3"
+- Backend: Service default/bar-svc
+  Percentage: 25.0%
+  Rule: 1
+  Weight: 1 (default)
+ResponseHeaders:
+- Remove:
+  - X-Powered-By
+  Rule: 0
+  Set:
+  - name: Cache-Control
+    value: no-store
 DirectlyAttachedPolicies:
 - Group: bar.com
   Kind: TimeoutPolicy
@@ -439,6 +499,44 @@ EffectivePolicies:
 }
 
 // TestHTTPRoutesPrinter_PrintJsonYaml tests the correctness of JSON/YAML output associated with -o json/yaml of `get` subcommand
+func TestHeadersViews(t *testing.T) {
+	httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+	})
+	httpRouteNode.Filters = []resourcediscovery.FilterSummary{
+		{
+			RuleIndex: 0,
+			Type:      gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Add: []gatewayv1.HTTPHeader{{Name: "X-Env", Value: "prod"}},
+			},
+		},
+		{
+			RuleIndex: 1,
+			Type:      gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Remove: []string{"X-Powered-By"},
+			},
+		},
+	}
+
+	gotRequestHeaders := headersViews(httpRouteNode, func(filter resourcediscovery.FilterSummary) *gatewayv1.HTTPHeaderFilter {
+		return filter.RequestHeaderModifier
+	})
+	wantRequestHeaders := []headersView{{Rule: 0, Add: []gatewayv1.HTTPHeader{{Name: "X-Env", Value: "prod"}}}}
+	if diff := cmp.Diff(wantRequestHeaders, gotRequestHeaders); diff != "" {
+		t.Errorf("Unexpected diff in request headers (-want +got):\n%v", diff)
+	}
+
+	gotResponseHeaders := headersViews(httpRouteNode, func(filter resourcediscovery.FilterSummary) *gatewayv1.HTTPHeaderFilter {
+		return filter.ResponseHeaderModifier
+	})
+	wantResponseHeaders := []headersView{{Rule: 1, Remove: []string{"X-Powered-By"}}}
+	if diff := cmp.Diff(wantResponseHeaders, gotResponseHeaders); diff != "" {
+		t.Errorf("Unexpected diff in response headers (-want +got):\n%v", diff)
+	}
+}
+
 func TestHTTPRoutesPrinter_PrintJsonYaml(t *testing.T) {
 	fakeClock := testingclock.NewFakeClock(time.Now())
 	creationTime := fakeClock.Now().Add(-24 * time.Hour).UTC() // UTC being necessary for consistently handling the time while marshaling/unmarshaling its JSON
@@ -498,7 +596,7 @@ func TestHTTPRoutesPrinter_PrintJsonYaml(t *testing.T) {
 		PolicyManager: params.PolicyManager,
 	}
 
-	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to discover resources: %v", err)
 	}
@@ -582,3 +680,138 @@ kind: List`, creationTime.Format(time.RFC3339)))
 		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", gotYaml, wantYaml, diff)
 	}
 }
+
+func TestHTTPRoutesPrinter_PrintTraces(t *testing.T) {
+	objects := []runtime.Object{
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.net/gateway-controller"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Hostnames: []gatewayv1.Hostname{"example.com"},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Path:   &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/foo")},
+						Method: common.PtrTo(gatewayv1.HTTPMethodGet),
+					}},
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata":   map[string]interface{}{"name": "timeout-gateway", "namespace": "default"},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{"idle": int64(300)},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), resourcediscovery.Filter{})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	traces, err := resourceModel.RouteTraces(resourcediscovery.HTTPRouteID("default", "foo-httproute"))
+	if err != nil {
+		t.Fatalf("RouteTraces() returned err=%v", err)
+	}
+
+	hp := &HTTPRoutesPrinter{Writer: params.Out}
+	hp.PrintTraces(traces)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `
+### Gateway: default/foo-gateway ###
+GatewayClass:
+  ControllerName: example.net/gateway-controller
+  Name: foo-gatewayclass
+Gateway:
+  DirectlyAttachedPolicies:
+  - Group: foo.com
+    Kind: TimeoutPolicy
+    Name: timeout-gateway
+    Namespace: default
+  EffectivePolicies:
+    TimeoutPolicy.foo.com:
+      idle: 300
+  Listeners:
+  - http (HTTP/80)
+  Name: foo-gateway
+  Namespace: default
+HTTPRoute:
+  EffectivePolicies:
+    TimeoutPolicy.foo.com:
+      idle: 300
+  Hostnames:
+  - example.com
+  Matches:
+  - 'Rule 0: PathPrefix /foo GET'
+  Name: foo-httproute
+  Namespace: default
+Backends:
+- EffectivePolicies:
+    TimeoutPolicy.foo.com:
+      idle: 300
+  Kind: Service
+  Name: foo-svc
+  Namespace: default
+`
+
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
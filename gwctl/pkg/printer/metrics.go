@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+type MetricsPrinter struct {
+	io.Writer
+}
+
+// labelValueEscaper escapes label values as required by the Prometheus text
+// exposition format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// PrintMetrics prints the metrics as gauges in the Prometheus text exposition
+// format. Metrics with the same name are grouped under a single HELP and TYPE
+// line, in the order in which the names first appear.
+func (mp *MetricsPrinter) PrintMetrics(metrics []resourcediscovery.Metric) {
+	var names []string
+	byName := make(map[string][]resourcediscovery.Metric)
+	for _, metric := range metrics {
+		if _, ok := byName[metric.Name]; !ok {
+			names = append(names, metric.Name)
+		}
+		byName[metric.Name] = append(byName[metric.Name], metric)
+	}
+
+	for _, name := range names {
+		fmt.Fprintf(mp, "# HELP %v %v\n", name, byName[name][0].Help)
+		fmt.Fprintf(mp, "# TYPE %v gauge\n", name)
+		for _, metric := range byName[name] {
+			fmt.Fprintf(mp, "%v%v %v\n", name, formatLabels(metric.Labels), strconv.FormatFloat(metric.Value, 'g', -1, 64))
+		}
+	}
+}
+
+// formatLabels returns the labels sorted by name, e.g. `{a="1",b="2"}`, or an
+// empty string if there are no labels.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var names []string
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%v=\"%v\"", name, labelValueEscaper.Replace(labels[name])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
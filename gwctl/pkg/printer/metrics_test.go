@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+func TestMetricsPrinter_PrintMetrics(t *testing.T) {
+	metrics := []resourcediscovery.Metric{
+		{Name: "gwctl_gateways_total", Help: "Number of Gateways.", Value: 2},
+		{Name: "gwctl_cross_namespace_refs_total", Help: "Number of cross namespace references.", Labels: map[string]string{"permitted": "true"}, Value: 3},
+		{Name: "gwctl_cross_namespace_refs_total", Help: "Number of cross namespace references.", Labels: map[string]string{"permitted": "false"}, Value: 0},
+	}
+	metrics = append(metrics, analyzer.FindingMetrics([]analyzer.Finding{
+		{Severity: analyzer.SeverityWarning},
+		{Severity: analyzer.SeverityWarning},
+		{Severity: analyzer.SeverityInfo},
+	})...)
+
+	out := &bytes.Buffer{}
+	mp := &MetricsPrinter{Writer: out}
+	mp.PrintMetrics(metrics)
+
+	want := `# HELP gwctl_gateways_total Number of Gateways.
+# TYPE gwctl_gateways_total gauge
+gwctl_gateways_total 2
+# HELP gwctl_cross_namespace_refs_total Number of cross namespace references.
+# TYPE gwctl_cross_namespace_refs_total gauge
+gwctl_cross_namespace_refs_total{permitted="true"} 3
+gwctl_cross_namespace_refs_total{permitted="false"} 0
+# HELP gwctl_findings_total Number of findings reported by the analyzers.
+# TYPE gwctl_findings_total gauge
+gwctl_findings_total{severity="error"} 0
+gwctl_findings_total{severity="warning"} 2
+gwctl_findings_total{severity="info"} 1
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", out.String(), want, diff)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
@@ -29,8 +30,7 @@ import (
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
-
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
 var _ Printer = (*NamespacesPrinter)(nil)
@@ -38,14 +38,27 @@ var _ Printer = (*NamespacesPrinter)(nil)
 type NamespacesPrinter struct {
 	io.Writer
 	Clock clock.Clock
+	// Policies includes the inheritable policies which flow into each
+	// Namespace from the resources higher in the hierarchy in the describe
+	// view.
+	Policies bool
 }
 
 type namespaceDescribeView struct {
-	Name                     string                 `json:",omitempty"`
-	Labels                   map[string]string      `json:",omitempty"`
-	Annotations              map[string]string      `json:",omitempty"`
-	Status                   string                 `json:",omitempty"`
-	DirectlyAttachedPolicies []policymanager.ObjRef `json:",omitempty"`
+	Name                     string                      `json:",omitempty"`
+	Labels                   map[string]string           `json:",omitempty"`
+	Annotations              map[string]string           `json:",omitempty"`
+	Status                   string                      `json:",omitempty"`
+	Gateways                 []string                    `json:",omitempty"`
+	HTTPRoutes               []string                    `json:",omitempty"`
+	Backends                 []string                    `json:",omitempty"`
+	DirectlyAttachedPolicies []policymanager.ObjRef      `json:",omitempty"`
+	InheritedPolicies        []inheritedPolicySourceView `json:",omitempty"`
+}
+
+type inheritedPolicySourceView struct {
+	Source   common.ObjRef
+	Policies []policymanager.ObjRef
 }
 
 func (nsp *NamespacesPrinter) GetPrintableNodes(resourceModel *resourcediscovery.ResourceModel) []NodeResource {
@@ -97,14 +110,38 @@ func (nsp *NamespacesPrinter) PrintDescribeView(resourceModel *resourcediscovery
 			},
 		}
 
+		contents := namespaceDescribeView{
+			Gateways:   namespacedResourceNames(common.MapToValues(namespaceNode.Gateways)),
+			HTTPRoutes: namespacedResourceNames(common.MapToValues(namespaceNode.HTTPRoutes)),
+			Backends:   namespacedResourceNames(common.MapToValues(namespaceNode.Backends)),
+		}
+		if len(contents.Gateways)+len(contents.HTTPRoutes)+len(contents.Backends) != 0 {
+			views = append(views, contents)
+		}
+
 		if policyRefs := resourcediscovery.ConvertPoliciesMapToPolicyRefs(namespaceNode.Policies); len(policyRefs) != 0 {
 			views = append(views, namespaceDescribeView{
 				DirectlyAttachedPolicies: policyRefs,
 			})
 		}
 
+		if nsp.Policies {
+			var inheritedPolicies []inheritedPolicySourceView
+			for _, source := range namespaceNode.InheritedPolicySummary() {
+				inheritedPolicies = append(inheritedPolicies, inheritedPolicySourceView{
+					Source:   source.Source,
+					Policies: policymanager.ToPolicyRefs(source.Policies),
+				})
+			}
+			if len(inheritedPolicies) != 0 {
+				views = append(views, namespaceDescribeView{
+					InheritedPolicies: inheritedPolicies,
+				})
+			}
+		}
+
 		for _, view := range views {
-			b, err := yaml.Marshal(view)
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 				os.Exit(1)
@@ -117,3 +154,14 @@ func (nsp *NamespacesPrinter) PrintDescribeView(resourceModel *resourcediscovery
 		}
 	}
 }
+
+// namespacedResourceNames returns the sorted names of the resources within a
+// Namespace.
+func namespacedResourceNames[K NodeResource](nodes []K) []string {
+	var result []string
+	for _, object := range ClientObjects(nodes) {
+		result = append(result, object.GetName())
+	}
+	sort.Strings(result)
+	return result
+}
@@ -18,6 +18,7 @@ package printer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	testingclock "k8s.io/utils/clock/testing"
 
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
@@ -80,7 +82,7 @@ func TestNamespacePrinter_PrintTable(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForNamespace(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForNamespace(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -220,7 +222,7 @@ func TestNamespacePrinter_PrintDescribeView(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForNamespace(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForNamespace(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -259,6 +261,225 @@ DirectlyAttachedPolicies:
 	}
 }
 
+func TestNamespacePrinter_PrintDescribeView_ContainedResources(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	gateway := func(namespace, name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		}
+	}
+	objects := []runtime.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "foo",
+				Labels: map[string]string{"team": "foo"},
+			},
+			Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar"},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		gateway("foo", "foo-gateway-2"),
+		gateway("foo", "foo-gateway-1"),
+		// Resources in other namespaces are not listed.
+		gateway("bar", "bar-gateway"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "foo"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway-1"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "foo-svc"},
+					}}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "foo"},
+		},
+		// Services which no HTTPRoute references are not listed.
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "unused-svc", Namespace: "foo"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "bar"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "healthcheckpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-namespace",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"interval": "10s",
+					},
+					"targetRef": map[string]interface{}{
+						"kind": "Namespace",
+						"name": "foo",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForNamespaceContents(context.Background(), resourcediscovery.Filter{Name: "foo"})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	nsp := &NamespacesPrinter{
+		Writer: params.Out,
+		Clock:  fakeClock,
+	}
+	nsp.PrintDescribeView(resourceModel)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `
+Name: foo
+Labels:
+  team: foo
+Status: Active
+Backends:
+- foo-svc
+Gateways:
+- foo-gateway-1
+- foo-gateway-2
+HTTPRoutes:
+- foo-httproute
+DirectlyAttachedPolicies:
+- Group: foo.com
+  Kind: HealthCheckPolicy
+  Name: health-check-namespace
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestNamespacePrinter_PrintDescribeView_InheritedPolicies(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	objects := []runtime.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "foo",
+				Labels: map[string]string{"team": "foo"},
+			},
+			Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "foo"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "healthcheckpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-gatewayclass",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"interval": "10s",
+					},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "GatewayClass",
+						"name":  "foo-gatewayclass",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForNamespaceContents(context.Background(), resourcediscovery.Filter{Name: "foo"})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	nsp := &NamespacesPrinter{
+		Writer:   params.Out,
+		Clock:    fakeClock,
+		Policies: true,
+	}
+	nsp.PrintDescribeView(resourceModel)
+
+	got := params.Out.(*bytes.Buffer).String()
+	want := `
+Name: foo
+Labels:
+  team: foo
+Status: Active
+Gateways:
+- foo-gateway
+InheritedPolicies:
+- Policies:
+  - Group: foo.com
+    Kind: HealthCheckPolicy
+    Name: health-check-gatewayclass
+  Source:
+    Group: gateway.networking.k8s.io
+    Kind: GatewayClass
+    Name: foo-gatewayclass
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
 // TestNamespacesPrinter_PrintJsonYaml tests the correctness of JSON/YAML output associated with -o json/yaml of `get` subcommand
 func TestNamespacesPrinter_PrintJsonYaml(t *testing.T) {
 	fakeClock := testingclock.NewFakeClock(time.Now())
@@ -289,7 +510,7 @@ func TestNamespacesPrinter_PrintJsonYaml(t *testing.T) {
 		K8sClients:    params.K8sClients,
 		PolicyManager: params.PolicyManager,
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForNamespace(resourcediscovery.Filter{})
+	resourceModel, err := discoverer.DiscoverResourcesForNamespace(context.Background(), resourcediscovery.Filter{})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
 	}
@@ -17,6 +17,8 @@ limitations under the License.
 package printer
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -24,12 +26,12 @@ import (
 	"strings"
 	"text/tabwriter"
 
-	"sigs.k8s.io/yaml"
-
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/utils/clock"
 
@@ -41,6 +43,10 @@ import (
 type PoliciesPrinter struct {
 	io.Writer
 	Clock clock.Clock
+	Color Colorizer
+	// Verbose annotates each field of the spec of policies with its
+	// description from the schema of the CRD in the describe view.
+	Verbose bool
 }
 
 func (pp *PoliciesPrinter) printClientObjects(objects []client.Object, format utils.OutputFormat) {
@@ -58,7 +64,8 @@ func (pp *PoliciesPrinter) printClientObjects(objects []client.Object, format ut
 }
 
 func (pp *PoliciesPrinter) printPoliciesTable(sortedPoliciesList []policymanager.Policy) {
-	tw := tabwriter.NewWriter(pp, 0, 0, 2, ' ', 0)
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
 	row := []string{"NAME", "KIND", "TARGET NAME", "TARGET KIND", "POLICY TYPE", "AGE"}
 	_, err := tw.Write([]byte(strings.Join(row, "\t") + "\n"))
 	if err != nil {
@@ -76,11 +83,17 @@ func (pp *PoliciesPrinter) printPoliciesTable(sortedPoliciesList []policymanager
 
 		age := duration.HumanDuration(pp.Clock.Since(policy.Unstructured().GetCreationTimestamp().Time))
 
+		targetName, targetKind := policy.TargetRef().Name, policy.TargetRef().Kind
+		if targetSelector := policy.TargetSelector(); targetSelector != nil {
+			targetName = fmt.Sprintf("<selector: %v>", labels.SelectorFromSet(targetSelector.MatchLabels))
+			targetKind = targetSelector.Kind
+		}
+
 		row := []string{
 			policy.Unstructured().GetName(),
 			kind,
-			policy.TargetRef().Name,
-			policy.TargetRef().Kind,
+			targetName,
+			targetKind,
 			policyType,
 			age,
 		}
@@ -91,6 +104,16 @@ func (pp *PoliciesPrinter) printPoliciesTable(sortedPoliciesList []policymanager
 		}
 	}
 	tw.Flush()
+
+	// Inherited policies are dimmed. The first line is the header, followed by
+	// one line for each policy.
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if i > 0 && i <= len(sortedPoliciesList) && sortedPoliciesList[i-1].IsInherited() {
+			line = pp.Color.colorizeLine(line, ColorDim)
+		}
+		fmt.Fprint(pp, line)
+	}
 }
 
 func (pp *PoliciesPrinter) PrintPolicies(policies []policymanager.Policy, format utils.OutputFormat) {
@@ -155,6 +178,57 @@ func (pp *PoliciesPrinter) PrintCRDs(policyCRDs []policymanager.PolicyCRD, forma
 	}
 }
 
+// policyKindView summarizes a policy kind discovered in the cluster.
+type policyKindView struct {
+	Kind      policymanager.PolicyCrdID
+	Inherited bool
+	// Policies is the number of policies of the kind.
+	Policies int
+}
+
+// PrintPolicyKinds prints each policy kind discovered in the cluster, whether it
+// is inheritable and how many policies of the kind are attached to resources.
+func (pp *PoliciesPrinter) PrintPolicyKinds(policyCRDs []policymanager.PolicyCRD, policies []policymanager.Policy, format utils.OutputFormat) {
+	counts := make(map[policymanager.PolicyCrdID]int)
+	for _, policy := range policies {
+		counts[policy.PolicyCrdID()]++
+	}
+	var views []policyKindView
+	for _, policyCRD := range policyCRDs {
+		views = append(views, policyKindView{
+			Kind:      policyCRD.ID(),
+			Inherited: policyCRD.IsInherited(),
+			Policies:  counts[policyCRD.ID()],
+		})
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Kind < views[j].Kind })
+
+	switch format {
+	case utils.OutputFormatJSON, utils.OutputFormatYAML:
+		output, err := utils.MarshalWithFormat(views, format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal the policy kinds %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprint(pp, string(output))
+	case utils.OutputFormatTable:
+		table := &Table{
+			ColumnNames: []string{"POLICY KIND", "INHERITED", "POLICIES"},
+		}
+		for _, view := range views {
+			table.Rows = append(table.Rows, []string{
+				string(view.Kind),
+				fmt.Sprintf("%t", view.Inherited),
+				fmt.Sprintf("%d", view.Policies),
+			})
+		}
+		table.writeTable(pp, 0)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown output format '%s' found\n", format)
+		os.Exit(1)
+	}
+}
+
 type policyDescribeView struct {
 	Name      string                 `json:",omitempty"`
 	Namespace string                 `json:",omitempty"`
@@ -162,6 +236,48 @@ type policyDescribeView struct {
 	Kind      string                 `json:",omitempty"`
 	Inherited string                 `json:",omitempty"`
 	Spec      map[string]interface{} `json:",omitempty"`
+	// SpecFields lists each field of the spec along with its description. It
+	// is only shown in verbose mode.
+	SpecFields []specFieldView `json:",omitempty"`
+	// AncestorStatus lists the conditions reported for each ancestor of the
+	// policy.
+	AncestorStatus []policyAncestorStatusView `json:",omitempty"`
+}
+
+type specFieldView struct {
+	Field       string
+	Value       interface{} `json:",omitempty"`
+	Description string      `json:",omitempty"`
+}
+
+type policyAncestorStatusView struct {
+	Ancestor       string
+	ControllerName string
+	Conditions     []string `json:",omitempty"`
+}
+
+// policyAncestorStatusViews summarizes each condition of an ancestor on a
+// single line, e.g. "Accepted=False (Conflicted): message".
+func policyAncestorStatusViews(policy policymanager.Policy) []policyAncestorStatusView {
+	var result []policyAncestorStatusView
+	for _, ancestor := range policy.AncestorStatuses() {
+		view := policyAncestorStatusView{
+			Ancestor:       policymanager.AncestorRefString(ancestor.AncestorRef, policy.Unstructured().GetNamespace()),
+			ControllerName: string(ancestor.ControllerName),
+		}
+		for _, condition := range ancestor.Conditions {
+			summary := fmt.Sprintf("%v=%v", condition.Type, condition.Status)
+			if condition.Reason != "" {
+				summary += fmt.Sprintf(" (%v)", condition.Reason)
+			}
+			if condition.Message != "" {
+				summary += ": " + condition.Message
+			}
+			view.Conditions = append(view.Conditions, summary)
+		}
+		result = append(result, view)
+	}
+	return result
 }
 
 func (pp *PoliciesPrinter) PrintPoliciesDescribeView(policies []policymanager.Policy) {
@@ -182,9 +298,21 @@ func (pp *PoliciesPrinter) PrintPoliciesDescribeView(policies []policymanager.Po
 				Spec: policy.Spec(),
 			},
 		}
+		if pp.Verbose {
+			var specFields []specFieldView
+			for _, field := range policy.SpecFields() {
+				specFields = append(specFields, specFieldView{Field: field.Path, Value: field.Value, Description: field.Description})
+			}
+			if len(specFields) != 0 {
+				views = append(views, policyDescribeView{SpecFields: specFields})
+			}
+		}
+		if ancestorStatus := policyAncestorStatusViews(policy); len(ancestorStatus) != 0 {
+			views = append(views, policyDescribeView{AncestorStatus: ancestorStatus})
+		}
 
 		for _, view := range views {
-			b, err := yaml.Marshal(view)
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 				os.Exit(1)
@@ -251,7 +379,7 @@ func (pp *PoliciesPrinter) PrintPolicyCRDsDescribeView(policyCrds []policymanage
 		}
 
 		for _, view := range views {
-			b, err := yaml.Marshal(view)
+			b, err := utils.MarshalWithFormat(view, utils.OutputFormatYAML)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to marshal to yaml: %v\n", err)
 				os.Exit(1)
@@ -264,3 +392,213 @@ func (pp *PoliciesPrinter) PrintPolicyCRDsDescribeView(policyCrds []policymanage
 		}
 	}
 }
+
+// PrintPolicyLayers prints, for each policy kind, the hierarchy levels which
+// contribute to the effective policy. Levels are stacked from the lowest to the
+// highest precedence, each listing the fields it added, overrode or removed.
+func (pp *PoliciesPrinter) PrintPolicyLayers(layers map[policymanager.PolicyCrdID][]policymanager.LevelDelta) {
+	if len(layers) == 0 {
+		fmt.Fprintln(pp, "No policies apply.")
+		return
+	}
+
+	var policyCrdIDs []policymanager.PolicyCrdID
+	for policyCrdID := range layers {
+		policyCrdIDs = append(policyCrdIDs, policyCrdID)
+	}
+	sort.Slice(policyCrdIDs, func(i, j int) bool { return policyCrdIDs[i] < policyCrdIDs[j] })
+
+	for i, policyCrdID := range policyCrdIDs {
+		fmt.Fprintf(pp, "%v:\n", policyCrdID)
+		for j, levelDelta := range layers[policyCrdID] {
+			fmt.Fprintf(pp, "  Layer %d: %v\n", j+1, levelDelta.Level)
+			fmt.Fprintf(pp, "    Policies: %v\n", strings.Join(policyRefNames(levelDelta.Policies), ", "))
+
+			if len(levelDelta.Changes) == 0 {
+				fmt.Fprintf(pp, "    Changes: <none>\n")
+				continue
+			}
+			table := &Table{
+				ColumnNames: []string{"CHANGE", "FIELD", "PREVIOUS", "VALUE"},
+			}
+			for _, change := range levelDelta.Changes {
+				changeType := "Overridden"
+				switch {
+				case change.IsAdded():
+					changeType = "Added"
+				case change.IsRemoved():
+					changeType = "Removed"
+				}
+				table.Rows = append(table.Rows, []string{
+					changeType,
+					change.Path,
+					formatFieldValue(change.PreviousValue),
+					formatFieldValue(change.Value),
+				})
+			}
+			fmt.Fprintf(pp, "    Changes:\n")
+			table.writeTable(pp, 6)
+		}
+
+		if i+1 != len(policyCrdIDs) {
+			fmt.Fprintf(pp, "\n\n")
+		}
+	}
+}
+
+// policyRefNames returns the names of the policies, prefixed with their
+// namespace for namespaced policies.
+func policyRefNames(policyRefs []policymanager.ObjRef) []string {
+	var result []string
+	for _, policyRef := range policyRefs {
+		if policyRef.Namespace != "" {
+			result = append(result, fmt.Sprintf("%v/%v", policyRef.Namespace, policyRef.Name))
+		} else {
+			result = append(result, policyRef.Name)
+		}
+	}
+	return result
+}
+
+// PrintPolicyTree prints the inheritance tree of the policy kind. Each resource
+// lists the policies of the kind attached to it, followed by the fields they
+// add (+), override (~) or remove (-) relative to the policies inherited from
+// its ancestors. Resources without policies of their own are marked as
+// inherited.
+func (pp *PoliciesPrinter) PrintPolicyTree(policyCrdID policymanager.PolicyCrdID, roots []*resourcediscovery.PolicyTreeNode) {
+	if len(roots) == 0 {
+		fmt.Fprintf(pp, "No resources are affected by %v.\n", policyCrdID)
+		return
+	}
+	fmt.Fprintf(pp, "%v\n", policyCrdID)
+	pp.printPolicyTreeNodes(roots, "")
+}
+
+func (pp *PoliciesPrinter) printPolicyTreeNodes(nodes []*resourcediscovery.PolicyTreeNode, prefix string) {
+	for i, node := range nodes {
+		branch, childPrefix := "├── ", prefix+"│   "
+		if i+1 == len(nodes) {
+			branch, childPrefix = "└── ", prefix+"    "
+		}
+
+		line := node.Level
+		switch {
+		case len(node.Policies) != 0:
+			line = fmt.Sprintf("%v [%v]", line, strings.Join(policyRefNames(node.Policies), ", "))
+		case node.Inherited:
+			line = fmt.Sprintf("%v (inherited)", line)
+		}
+		fmt.Fprintf(pp, "%v%v%v\n", prefix, branch, line)
+
+		changePrefix := childPrefix + "    "
+		if len(node.Children) != 0 {
+			changePrefix = childPrefix + "│   "
+		}
+		for _, change := range node.Changes {
+			switch {
+			case change.IsAdded():
+				fmt.Fprintf(pp, "%v+ %v: %v\n", changePrefix, change.Path, formatFieldValue(change.Value))
+			case change.IsRemoved():
+				fmt.Fprintf(pp, "%v- %v: %v\n", changePrefix, change.Path, formatFieldValue(change.PreviousValue))
+			default:
+				fmt.Fprintf(pp, "%v~ %v: %v -> %v\n", changePrefix, change.Path, formatFieldValue(change.PreviousValue), formatFieldValue(change.Value))
+			}
+		}
+
+		pp.printPolicyTreeNodes(node.Children, childPrefix)
+	}
+}
+
+// PrintEffectivePoliciesOfKind prints, in one table, the effective policy of
+// the kind of every resource it applies to.
+func (pp *PoliciesPrinter) PrintEffectivePoliciesOfKind(policyCrdID policymanager.PolicyCrdID, effectivePolicies []resourcediscovery.ResourceEffectivePolicy) {
+	if len(effectivePolicies) == 0 {
+		fmt.Fprintf(pp, "No resources are affected by %v.\n", policyCrdID)
+		return
+	}
+
+	table := &Table{
+		ColumnNames: []string{"RESOURCE", "GATEWAY", "LISTENER", "RULE", "EFFECTIVE SPEC"},
+	}
+	for _, effectivePolicy := range effectivePolicies {
+		resource := fmt.Sprintf("%v/%v", effectivePolicy.Resource.Kind, effectivePolicy.Resource.Name)
+		if effectivePolicy.Resource.Namespace != "" {
+			resource = fmt.Sprintf("%v/%v/%v", effectivePolicy.Resource.Kind, effectivePolicy.Resource.Namespace, effectivePolicy.Resource.Name)
+		}
+		gateway := "-"
+		if effectivePolicy.Gateway.Name != "" {
+			gateway = fmt.Sprintf("%v/%v", effectivePolicy.Gateway.Namespace, effectivePolicy.Gateway.Name)
+		}
+		listener := "-"
+		if effectivePolicy.Listener != "" {
+			listener = string(effectivePolicy.Listener)
+		}
+		rule := "-"
+		if effectivePolicy.RuleName != "" {
+			rule = effectivePolicy.RuleName
+		}
+		table.Rows = append(table.Rows, []string{resource, gateway, listener, rule, formatFieldValue(effectivePolicy.Policy)})
+	}
+	table.writeTable(pp, 0)
+}
+
+// PrintBehaviorChanges prints, for each resource, the effective policies which
+// would change when enabling the behavior rules, along with the fields which
+// change.
+func (pp *PoliciesPrinter) PrintBehaviorChanges(changes []resourcediscovery.BehaviorChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(pp, "No effective policies would change.")
+		return
+	}
+
+	var lastHeader string
+	for _, change := range changes {
+		header := fmt.Sprintf("%v %v", change.Resource.Kind, change.Resource.Name)
+		if change.Resource.Namespace != "" {
+			header = fmt.Sprintf("%v %v/%v", change.Resource.Kind, change.Resource.Namespace, change.Resource.Name)
+		}
+		var qualifiers []string
+		if change.Gateway.Name != "" {
+			qualifiers = append(qualifiers, fmt.Sprintf("Gateway %v/%v", change.Gateway.Namespace, change.Gateway.Name))
+		}
+		if change.RuleName != "" {
+			qualifiers = append(qualifiers, fmt.Sprintf("rule %v", change.RuleName))
+		}
+		if len(qualifiers) != 0 {
+			header = fmt.Sprintf("%v (%v)", header, strings.Join(qualifiers, ", "))
+		}
+		if header != lastHeader {
+			if lastHeader != "" {
+				fmt.Fprintf(pp, "\n")
+			}
+			fmt.Fprintf(pp, "%v:\n", header)
+			lastHeader = header
+		}
+
+		fmt.Fprintf(pp, "  %v:\n", change.PolicyCrdID)
+		table := &Table{
+			ColumnNames: []string{"FIELD", "CURRENT", "WITH RULES"},
+		}
+		for _, fieldChange := range change.Changes {
+			table.Rows = append(table.Rows, []string{
+				fieldChange.Path,
+				formatFieldValue(fieldChange.PreviousValue),
+				formatFieldValue(fieldChange.Value),
+			})
+		}
+		table.writeTable(pp, 4)
+	}
+}
+
+// formatFieldValue returns the compact JSON representation of a field value,
+// or "-" if the field is not set.
+func formatFieldValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
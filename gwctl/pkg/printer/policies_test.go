@@ -32,6 +32,8 @@ import (
 
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
@@ -104,6 +106,25 @@ func TestPoliciesPrinter_Print_And_PrintDescribeView(t *testing.T) {
 						"namespace": "default",
 					},
 				},
+				"status": map[string]interface{}{
+					"ancestors": []interface{}{
+						map[string]interface{}{
+							"ancestorRef": map[string]interface{}{
+								"name": "foo-gateway",
+							},
+							"controllerName": "foo.com/gateway-controller",
+							"conditions": []interface{}{
+								map[string]interface{}{
+									"type":               "Accepted",
+									"status":             "False",
+									"reason":             "Conflicted",
+									"message":            "conflicts with another policy",
+									"lastTransitionTime": fakeClock.Now().Format(time.RFC3339),
+								},
+							},
+						},
+					},
+				},
 			},
 		},
 
@@ -202,6 +223,11 @@ Spec:
     kind: Gateway
     name: foo-gateway
     namespace: default
+AncestorStatus:
+- Ancestor: Gateway foo-gateway
+  Conditions:
+  - 'Accepted=False (Conflicted): conflicts with another policy'
+  ControllerName: foo.com/gateway-controller
 
 
 Name: health-check-gatewayclass
@@ -357,6 +383,73 @@ timeoutpolicies.bar.com      Direct       Namespaced  5m
 	}
 }
 
+func TestPoliciesPrinter_PrintPolicyKinds(t *testing.T) {
+	policyCRD := func(name, group, kind, policyType string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name + "." + group,
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: policyType,
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    group,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: name,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	policy := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		policyCRD("healthcheckpolicies", "foo.com", "HealthCheckPolicy", "inherited"),
+		policy("foo.com/v1", "HealthCheckPolicy", "health-check-1"),
+		policy("foo.com/v1", "HealthCheckPolicy", "health-check-2"),
+		policyCRD("timeoutpolicies", "bar.com", "TimeoutPolicy", "direct"),
+		policy("bar.com/v1", "TimeoutPolicy", "timeout"),
+		policyCRD("retrypolicies", "bar.com", "RetryPolicy", "direct"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	pp := &PoliciesPrinter{
+		Writer: &bytes.Buffer{},
+	}
+	pp.PrintPolicyKinds(params.PolicyManager.GetCRDs(), params.PolicyManager.GetPolicies(), utils.OutputFormatTable)
+
+	got := pp.Writer.(*bytes.Buffer).String()
+	want := `
+POLICY KIND                INHERITED  POLICIES
+HealthCheckPolicy.foo.com  true       2
+RetryPolicy.bar.com        false      0
+TimeoutPolicy.bar.com      false      1
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
 // TestPoliciesPrinter_PrintCRDs_JsonYaml tests the correctness of JSON/YAML output associated with -o json/yaml of `get` subcommand
 func TestPoliciesPrinter_PrintCRDs_JsonYaml(t *testing.T) {
 	fakeClock := testingclock.NewFakeClock(time.Now())
@@ -762,3 +855,135 @@ Status:
 		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
 	}
 }
+
+func TestPoliciesPrinter_PrintPolicyTree(t *testing.T) {
+	roots := []*resourcediscovery.PolicyTreeNode{
+		{
+			LevelDelta: policymanager.LevelDelta{
+				Level: "GatewayClass foo-gatewayclass",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gatewayclass"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "retries", Value: float64(2)},
+					{Path: "timeout", Value: float64(30)},
+				},
+			},
+			Children: []*resourcediscovery.PolicyTreeNode{
+				{
+					LevelDelta: policymanager.LevelDelta{
+						Level: "Gateway default/foo-gateway",
+						Policies: []policymanager.ObjRef{
+							{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gateway", Namespace: "default"},
+						},
+						Changes: []policymanager.FieldChange{
+							{Path: "timeout", Value: float64(60), PreviousValue: float64(30)},
+						},
+					},
+					Inherited: true,
+					Children: []*resourcediscovery.PolicyTreeNode{
+						{
+							LevelDelta: policymanager.LevelDelta{Level: "HTTPRoute default/bar-httproute"},
+							Inherited:  true,
+						},
+						{
+							LevelDelta: policymanager.LevelDelta{
+								Level: "HTTPRoute default/foo-httproute",
+								Policies: []policymanager.ObjRef{
+									{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-httproute", Namespace: "default"},
+								},
+								Changes: []policymanager.FieldChange{
+									{Path: "retries", PreviousValue: float64(2)},
+								},
+							},
+							Inherited: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pp := &PoliciesPrinter{Writer: &bytes.Buffer{}}
+	pp.PrintPolicyTree("HealthCheckPolicy.foo.com", roots)
+
+	got := pp.Writer.(*bytes.Buffer).String()
+	want := `
+HealthCheckPolicy.foo.com
+└── GatewayClass foo-gatewayclass [health-check-gatewayclass]
+    │   + retries: 2
+    │   + timeout: 30
+    └── Gateway default/foo-gateway [default/health-check-gateway]
+        │   ~ timeout: 30 -> 60
+        ├── HTTPRoute default/bar-httproute (inherited)
+        └── HTTPRoute default/foo-httproute [default/health-check-httproute]
+                - retries: 2
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestPoliciesPrinter_PrintPolicyLayers(t *testing.T) {
+	layers := map[policymanager.PolicyCrdID][]policymanager.LevelDelta{
+		"HealthCheckPolicy.foo.com": {
+			{
+				Level: "GatewayClass foo-gatewayclass",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gatewayclass"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "retries", Value: float64(2)},
+					{Path: "timeout", Value: float64(30)},
+				},
+			},
+			{
+				Level: "Gateway default/foo-gateway",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gateway", Namespace: "default"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "retries", PreviousValue: float64(2)},
+					{Path: "timeout", Value: float64(60), PreviousValue: float64(30)},
+				},
+			},
+		},
+		"TimeoutPolicy.bar.com": {
+			{
+				Level: "Namespace default",
+				Policies: []policymanager.ObjRef{
+					{Group: "bar.com", Kind: "TimeoutPolicy", Name: "timeout-policy-namespace"},
+				},
+			},
+		},
+	}
+
+	pp := &PoliciesPrinter{Writer: &bytes.Buffer{}}
+	pp.PrintPolicyLayers(layers)
+
+	got := pp.Writer.(*bytes.Buffer).String()
+	want := `
+HealthCheckPolicy.foo.com:
+  Layer 1: GatewayClass foo-gatewayclass
+    Policies: health-check-gatewayclass
+    Changes:
+      CHANGE  FIELD    PREVIOUS  VALUE
+      Added   retries  -         2
+      Added   timeout  -         30
+  Layer 2: Gateway default/foo-gateway
+    Policies: default/health-check-gateway
+    Changes:
+      CHANGE      FIELD    PREVIOUS  VALUE
+      Removed     retries  2         -
+      Overridden  timeout  30        60
+
+
+TimeoutPolicy.bar.com:
+  Layer 1: Namespace default
+    Policies: timeout-policy-namespace
+    Changes: <none>
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,6 +37,44 @@ type Printer interface {
 	PrintTable(resourceModel *resourcediscovery.ResourceModel)
 }
 
+// StatusPrinter is implemented by Printers which support the status output
+// format.
+type StatusPrinter interface {
+	Printer
+	// PrintStatus prints a single line with the status rollup of each resource
+	// and returns true if all resources are OK.
+	PrintStatus(resourceModel *resourcediscovery.ResourceModel) bool
+}
+
+// PrintStatus prints the status rollup of each resource through p, and returns
+// true if all resources are OK.
+func PrintStatus(p Printer, resourceModel *resourcediscovery.ResourceModel) bool {
+	statusPrinter, ok := p.(StatusPrinter)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "output format %s is not supported for this resource type\n", utils.OutputFormatStatus)
+		os.Exit(1)
+	}
+	return statusPrinter.PrintStatus(resourceModel)
+}
+
+// printStatusLines prints one "NAMESPACE/NAME STATUS" line for each resource,
+// sorted by namespace/name, and returns true if all resources are OK.
+func printStatusLines(w io.Writer, color Colorizer, statuses map[string]resourcediscovery.StatusSummary) bool {
+	var names []string
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	allOK := true
+	for _, name := range names {
+		status := statuses[name]
+		allOK = allOK && status.OK()
+		fmt.Fprintf(w, "%v %v\n", name, color.Status(status))
+	}
+	return allOK
+}
+
 func Print(p Printer, resourceModel *resourcediscovery.ResourceModel, format utils.OutputFormat) {
 	switch format {
 	case utils.OutputFormatTable:
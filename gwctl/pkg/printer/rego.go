@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+// The types below model the JSON document printed for the rego-input output
+// format, which is meant to be passed as input to OPA. The effective policies
+// are listed under the top-level key "effectivePolicies", one entry per
+// resource, Gateway, listener, rule and kind of policy, such that Rego rules can iterate
+// over input.effectivePolicies[_] and assert on resource, policyKind and spec.
+
+type regoInput struct {
+	EffectivePolicies []regoEffectivePolicy `json:"effectivePolicies"`
+}
+
+type regoEffectivePolicy struct {
+	// Resource is the resource the effective policy applies to.
+	Resource regoObjRef `json:"resource"`
+	// Gateway is the Gateway through which the effective policy applies. It is
+	// omitted for Gateways and for HTTPRoutes attached to Services.
+	Gateway *regoObjRef `json:"gateway,omitempty"`
+	// Listener is the name of the listener the effective policy applies to. It
+	// is omitted if the effective policy applies to all listeners.
+	Listener string `json:"listener,omitempty"`
+	// Rule is the name of the HTTPRoute rule the effective policy applies to.
+	Rule string `json:"rule,omitempty"`
+	// PolicyKind is the kind and group of the policy, e.g.
+	// HealthCheckPolicy.foo.com.
+	PolicyKind string `json:"policyKind"`
+	// Spec is the effective spec of the policy.
+	Spec map[string]interface{} `json:"spec"`
+}
+
+type regoObjRef struct {
+	Group     string `json:"group"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func newRegoObjRef(objRef common.ObjRef) regoObjRef {
+	return regoObjRef{Group: objRef.Group, Kind: objRef.Kind, Namespace: objRef.Namespace, Name: objRef.Name}
+}
+
+// PrintRegoInput prints the effective policies as a JSON document for use as
+// the input of OPA Rego policies. The effective policies are listed in the
+// given order.
+func (pp *PoliciesPrinter) PrintRegoInput(effectivePolicies []resourcediscovery.ResourceEffectivePolicy) {
+	input := regoInput{EffectivePolicies: []regoEffectivePolicy{}}
+	for _, effectivePolicy := range effectivePolicies {
+		spec, err := effectivePolicy.Policy.EffectiveSpec()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get the effective spec of %v: %v\n", effectivePolicy.Policy.Name(), err)
+			os.Exit(1)
+		}
+		entry := regoEffectivePolicy{
+			Resource:   newRegoObjRef(effectivePolicy.Resource),
+			Listener:   string(effectivePolicy.Listener),
+			Rule:       effectivePolicy.RuleName,
+			PolicyKind: string(effectivePolicy.Policy.PolicyCrdID()),
+			Spec:       spec,
+		}
+		if effectivePolicy.Gateway.Name != "" {
+			gateway := newRegoObjRef(effectivePolicy.Gateway)
+			entry.Gateway = &gateway
+		}
+		input.EffectivePolicies = append(input.EffectivePolicies, entry)
+	}
+
+	output, err := utils.MarshalWithFormat(input, utils.OutputFormatJSON)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal the object %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(pp, string(output))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+type RequestsPrinter struct {
+	io.Writer
+}
+
+// PrintTrace prints the decisions made while routing a request, followed by
+// the effective policies applied to it.
+func (rp *RequestsPrinter) PrintTrace(trace *resourcediscovery.RequestTrace) {
+	target := trace.Path
+	if len(trace.QueryParams) != 0 {
+		target += "?" + trace.QueryParams.Encode()
+	}
+	host := trace.Host
+	if trace.Port != 0 {
+		host = fmt.Sprintf("%v:%d", host, trace.Port)
+	}
+	fmt.Fprintf(rp, "Request: %v %v%v\n", trace.Method, host, target)
+	if len(trace.Headers) != 0 {
+		fmt.Fprintf(rp, "Headers:\n")
+		var names []string
+		for name := range trace.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(rp, "  %v: %v\n", name, strings.Join(trace.Headers[name], ","))
+		}
+	}
+	// Steps are printed as they are instead of through the yaml Marshaller,
+	// which would wrap long lines.
+	fmt.Fprintf(rp, "Trace:\n")
+	for i, step := range trace.Steps {
+		fmt.Fprintf(rp, "  %d. %v\n", i+1, step)
+	}
+	if len(trace.EffectivePolicies) != 0 {
+		Describe(rp, []*DescriberKV{{Key: "EffectivePolicies", Value: trace.EffectivePolicies}})
+	}
+}
+
+// PrintHostBackends prints, grouped by Gateway, the backends which can serve
+// requests for the host. Each backend is listed once per HTTPRoute rule
+// referencing it, along with the weight of the reference.
+func (rp *RequestsPrinter) PrintHostBackends(host string, hostBackends []resourcediscovery.HostBackends) {
+	if len(hostBackends) == 0 {
+		fmt.Fprintf(rp, "No HTTP or HTTPS listener of any Gateway accepts host %q\n", host)
+		return
+	}
+	for i, gatewayBackends := range hostBackends {
+		if i > 0 {
+			fmt.Fprintf(rp, "\n")
+		}
+		var listeners []string
+		for _, listener := range gatewayBackends.Listeners {
+			listeners = append(listeners, string(listener))
+		}
+		fmt.Fprintf(rp, "Gateway %v/%v (listeners: %v)\n",
+			gatewayBackends.Gateway.Gateway.GetNamespace(), gatewayBackends.Gateway.Gateway.GetName(), strings.Join(listeners, ", "))
+		if len(gatewayBackends.Backends) == 0 && len(gatewayBackends.RulesWithoutBackends) == 0 {
+			fmt.Fprintf(rp, "  No HTTPRoute matches host %q\n", host)
+			continue
+		}
+
+		table := &Table{ColumnNames: []string{"BACKEND", "ROUTE", "RULE", "WEIGHT"}}
+		for _, backend := range gatewayBackends.Backends {
+			name := resourcediscovery.BackendRefString(backend.BackendRef)
+			if backend.Backend == nil && backend.BackendRef.Kind == "Service" {
+				// Referenced Services are always discovered, so this one
+				// does not exist.
+				name += " (not found)"
+			}
+			for _, ref := range backend.Refs {
+				if ref.Default {
+					table.Rows = append(table.Rows, []string{name, "(default)", "-", "-"})
+					continue
+				}
+				table.Rows = append(table.Rows, []string{
+					name,
+					fmt.Sprintf("%v/%v", ref.HTTPRoute.Namespace, ref.HTTPRoute.Name),
+					fmt.Sprintf("%d", ref.RuleIndex),
+					fmt.Sprintf("%d", ref.Weight),
+				})
+			}
+		}
+		for _, rule := range gatewayBackends.RulesWithoutBackends {
+			name := "(no backend)"
+			if len(rule.Filters) != 0 {
+				var filters []string
+				for _, filter := range rule.Filters {
+					filters = append(filters, string(filter))
+				}
+				name = fmt.Sprintf("(no backend: %v)", strings.Join(filters, ", "))
+			}
+			table.Rows = append(table.Rows, []string{
+				name,
+				fmt.Sprintf("%v/%v", rule.HTTPRoute.Namespace, rule.HTTPRoute.Name),
+				fmt.Sprintf("%d", rule.RuleIndex),
+				"-",
+			})
+		}
+		table.writeTable(rp, 2)
+	}
+}
+
+// PrintRequestMatches prints which HTTPRoute each request matches, followed by
+// the number of requests matching no HTTPRoute.
+func (rp *RequestsPrinter) PrintRequestMatches(matches []resourcediscovery.RequestMatch) {
+	table := &Table{ColumnNames: []string{"HOST", "PATH", "METHOD", "ROUTE", "RULE", "BACKEND"}}
+	var unmatched int
+	for _, match := range matches {
+		row := []string{match.Request.Host, match.Request.Path, match.Request.Method}
+		if !match.Matched() {
+			unmatched++
+			table.Rows = append(table.Rows, append(row, "NO MATCH", "-", "-"))
+			continue
+		}
+		httpRoute := match.Trace.HTTPRoute.HTTPRoute
+		backend := "-"
+		if match.Trace.BackendRef != nil {
+			backend = resourcediscovery.BackendRefString(*match.Trace.BackendRef)
+		}
+		table.Rows = append(table.Rows, append(row,
+			fmt.Sprintf("%v/%v", httpRoute.GetNamespace(), httpRoute.GetName()),
+			fmt.Sprintf("%d", match.Trace.Match.RuleIndex),
+			backend,
+		))
+	}
+	table.writeTable(rp, 0)
+
+	fmt.Fprintf(rp, "\n%d of %d requests matched a route", len(matches)-unmatched, len(matches))
+	if unmatched != 0 {
+		fmt.Fprintf(rp, "; %d matched no route", unmatched)
+	}
+	fmt.Fprintf(rp, "\n")
+}
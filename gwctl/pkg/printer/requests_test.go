@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+func TestRequestsPrinter_PrintTrace(t *testing.T) {
+	trace := &resourcediscovery.RequestTrace{
+		Host:   "api.foo.com",
+		Path:   "/v1/users",
+		Method: "GET",
+		Steps: []string{
+			`Listener api of Gateway default/foo-gateway accepts host "api.foo.com" (hostname "api.foo.com")`,
+			"Match 0 of rule 1 (Exact /v1/users) of HTTPRoute default/users-httproute wins for GET /v1/users",
+			"Rule 1 forwards to Service default/users-svc",
+		},
+	}
+
+	out := &bytes.Buffer{}
+	rp := &RequestsPrinter{Writer: out}
+	rp.PrintTrace(trace)
+
+	got := out.String()
+	want := `
+Request: GET api.foo.com/v1/users
+Trace:
+  1. Listener api of Gateway default/foo-gateway accepts host "api.foo.com" (hostname "api.foo.com")
+  2. Match 0 of rule 1 (Exact /v1/users) of HTTPRoute default/users-httproute wins for GET /v1/users
+  3. Rule 1 forwards to Service default/users-svc
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestRequestsPrinter_PrintHostBackends(t *testing.T) {
+	gatewayNode := func(name string) *resourcediscovery.GatewayNode {
+		return resourcediscovery.NewGatewayNode(&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		})
+	}
+	serviceRef := func(name string) common.ObjRef {
+		return common.ObjRef{Kind: "Service", Name: name, Namespace: "default"}
+	}
+	httpRouteRef := func(name string) common.ObjRef {
+		return common.ObjRef{Kind: "HTTPRoute", Name: name, Namespace: "default"}
+	}
+	found := resourcediscovery.NewBackendNode(&unstructured.Unstructured{})
+
+	hostBackends := []resourcediscovery.HostBackends{
+		{
+			Gateway:   gatewayNode("external-gateway"),
+			Listeners: []gatewayv1.SectionName{"api", "api-https"},
+			Backends: []resourcediscovery.HostBackend{
+				{BackendRef: serviceRef("api-v1-svc"), Backend: found, Refs: []resourcediscovery.HostBackendRef{
+					{HTTPRoute: httpRouteRef("stable-httproute"), Weight: 90},
+				}},
+				{BackendRef: serviceRef("api-v2-svc"), Backend: found, Refs: []resourcediscovery.HostBackendRef{
+					{HTTPRoute: httpRouteRef("stable-httproute"), Weight: 10},
+					{HTTPRoute: httpRouteRef("canary-httproute"), RuleIndex: 1, Weight: 1},
+				}},
+				{BackendRef: serviceRef("fallback-svc"), Backend: found, Refs: []resourcediscovery.HostBackendRef{
+					{Default: true},
+				}},
+			},
+		},
+		{
+			Gateway:   gatewayNode("internal-gateway"),
+			Listeners: []gatewayv1.SectionName{"wildcard"},
+			Backends: []resourcediscovery.HostBackend{
+				{BackendRef: serviceRef("internal-api-svc"), Refs: []resourcediscovery.HostBackendRef{
+					{HTTPRoute: httpRouteRef("internal-httproute"), Weight: 1},
+				}},
+			},
+		},
+		{
+			Gateway:   gatewayNode("unused-gateway"),
+			Listeners: []gatewayv1.SectionName{"all"},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	rp := &RequestsPrinter{Writer: out}
+	rp.PrintHostBackends("api.foo.com", hostBackends)
+
+	got := out.String()
+	want := `
+Gateway default/external-gateway (listeners: api, api-https)
+  BACKEND                       ROUTE                     RULE  WEIGHT
+  Service default/api-v1-svc    default/stable-httproute  0     90
+  Service default/api-v2-svc    default/stable-httproute  0     10
+  Service default/api-v2-svc    default/canary-httproute  1     1
+  Service default/fallback-svc  (default)                 -     -
+
+Gateway default/internal-gateway (listeners: wildcard)
+  BACKEND                                       ROUTE                       RULE  WEIGHT
+  Service default/internal-api-svc (not found)  default/internal-httproute  0     1
+
+Gateway default/unused-gateway (listeners: all)
+  No HTTPRoute matches host "api.foo.com"
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+
+	out.Reset()
+	rp.PrintHostBackends("api.foo.com", nil)
+	if got, want := out.String(), "No HTTP or HTTPS listener of any Gateway accepts host \"api.foo.com\"\n"; got != want {
+		t.Errorf("PrintHostBackends() without Gateways = %q; want %q", got, want)
+	}
+}
+
+func TestRequestsPrinter_PrintHostBackends_RulesWithoutBackends(t *testing.T) {
+	httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: "redirect-httproute", Namespace: "default"}
+	hostBackends := []resourcediscovery.HostBackends{
+		{
+			Gateway: resourcediscovery.NewGatewayNode(&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			}),
+			Listeners: []gatewayv1.SectionName{"http"},
+			RulesWithoutBackends: []resourcediscovery.HostRule{
+				{HTTPRoute: httpRouteRef, Filters: []gatewayv1.HTTPRouteFilterType{gatewayv1.HTTPRouteFilterRequestRedirect}},
+				{HTTPRoute: httpRouteRef, RuleIndex: 1},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	rp := &RequestsPrinter{Writer: out}
+	rp.PrintHostBackends("foo.com", hostBackends)
+
+	got := out.String()
+	want := `
+Gateway default/foo-gateway (listeners: http)
+  BACKEND                        ROUTE                       RULE  WEIGHT
+  (no backend: RequestRedirect)  default/redirect-httproute  0     -
+  (no backend)                   default/redirect-httproute  1     -
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestRequestsPrinter_PrintRequestMatches(t *testing.T) {
+	httpRouteNode := resourcediscovery.NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-httproute", Namespace: "default"},
+	})
+	matches := []resourcediscovery.RequestMatch{
+		{
+			Request: resourcediscovery.TestRequest{Host: "api.foo.com", Path: "/v1/users", Method: "GET"},
+			Trace: &resourcediscovery.RequestTrace{
+				HTTPRoute:  httpRouteNode,
+				Match:      resourcediscovery.HTTPRouteMatch{RuleIndex: 1},
+				BackendRef: &common.ObjRef{Kind: "Service", Name: "users-svc", Namespace: "default"},
+			},
+		},
+		{
+			Request: resourcediscovery.TestRequest{Host: "api.foo.com", Path: "/v2", Method: "GET"},
+			Trace:   &resourcediscovery.RequestTrace{},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	rp := &RequestsPrinter{Writer: out}
+	rp.PrintRequestMatches(matches)
+
+	got := out.String()
+	want := `
+HOST         PATH       METHOD  ROUTE                  RULE  BACKEND
+api.foo.com  /v1/users  GET     default/api-httproute  1     Service default/users-svc
+api.foo.com  /v2        GET     NO MATCH               -     -
+
+1 of 2 requests matched a route; 1 matched no route
+`
+	if diff := cmp.Diff(common.YamlString(want), common.YamlString(got), common.YamlStringTransformer); diff != "" {
+		t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
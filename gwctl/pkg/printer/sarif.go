@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/analyzer"
+)
+
+// The types below model the subset of the Static Analysis Results Interchange
+// Format (SARIF) 2.1.0 which is needed to report Findings, e.g. to GitHub code
+// scanning.
+//
+// [SARIF 2.1.0]: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifToolComponent `json:"driver"`
+}
+
+type sarifToolComponent struct {
+	Name           string                     `json:"name"`
+	InformationURI string                     `json:"informationUri,omitempty"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string                      `json:"id"`
+	ShortDescription     sarifMessage                `json:"shortDescription"`
+	Help                 sarifMessage                `json:"help"`
+	DefaultConfiguration sarifReportingConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]interface{}      `json:"properties,omitempty"`
+}
+
+type sarifReportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel maps the Severity of a Finding to the level of a SARIF result.
+func sarifLevel(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "error"
+	case analyzer.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// newSARIFLog converts the findings to a SARIF log with a single run. All
+// documented Codes are listed as rules of the run, and each Finding becomes a
+// result of the rule of its Code. Since Findings refer to resources rather
+// than files, their location is a logical location named after the resource.
+// Consumers like GitHub code scanning require a physical location as well, so
+// the resource is also given as the relative URI KIND/NAMESPACE/NAME, which
+// matches a manifest stored at that path.
+func newSARIFLog(findings []analyzer.Finding) sarifLog {
+	driver := sarifToolComponent{
+		Name:           "gwctl",
+		InformationURI: "https://gateway-api.sigs.k8s.io/",
+		Rules:          []sarifReportingDescriptor{},
+	}
+	ruleIndex := make(map[analyzer.Code]int)
+	for i, info := range analyzer.Codes() {
+		ruleIndex[info.Code] = i
+		driver.Rules = append(driver.Rules, sarifReportingDescriptor{
+			ID:                   string(info.Code),
+			ShortDescription:     sarifMessage{Text: info.Summary},
+			Help:                 sarifMessage{Text: info.Remediation},
+			DefaultConfiguration: sarifReportingConfiguration{Level: sarifLevel(info.Severity)},
+			Properties:           map[string]interface{}{"category": string(info.Category)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, finding := range findings {
+		index, ok := ruleIndex[finding.Code]
+		if !ok {
+			// -1 is the SARIF value for results whose rule is not part of the
+			// run.
+			index = -1
+		}
+		name := finding.ResourceRef.Name
+		if finding.ResourceRef.Namespace != "" {
+			name = fmt.Sprintf("%v/%v", finding.ResourceRef.Namespace, finding.ResourceRef.Name)
+		}
+		fullyQualifiedName := fmt.Sprintf("%v/%v", finding.ResourceRef.Kind, name)
+		result := sarifResult{
+			RuleID:    string(finding.Code),
+			RuleIndex: index,
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: fullyQualifiedName},
+					Region:           sarifRegion{StartLine: 1},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               name,
+					FullyQualifiedName: fullyQualifiedName,
+					Kind:               "resource",
+				}},
+			}},
+		}
+		if finding.Remediation != "" {
+			result.Properties = map[string]interface{}{"remediation": finding.Remediation}
+		}
+		results = append(results, result)
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
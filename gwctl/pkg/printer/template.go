@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/template"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// TemplateData is the data model exposed to Go templates through
+// `-o go-template=...`. It is derived from the ResourceModel, but decoupled
+// from it such that templates keep working as the ResourceModel evolves: fields
+// may be added, but existing fields are neither renamed nor removed. All lists
+// are sorted, and resources are referenced by their "namespace/name", or by
+// their name for cluster scoped resources.
+type TemplateData struct {
+	GatewayClasses []TemplateGatewayClass
+	Gateways       []TemplateGateway
+	HTTPRoutes     []TemplateHTTPRoute
+	Backends       []TemplateBackend
+}
+
+// TemplateGatewayClass describes a GatewayClass.
+type TemplateGatewayClass struct {
+	Name           string
+	ControllerName string
+	// Gateways references the Gateways of the GatewayClass.
+	Gateways []string
+}
+
+// TemplateGateway describes a Gateway.
+type TemplateGateway struct {
+	Name         string
+	Namespace    string
+	GatewayClass string
+	Listeners    []TemplateListener
+	// HTTPRoutes references the HTTPRoutes attached to the Gateway.
+	HTTPRoutes []string
+	// Status summarizes the status of the Gateway, e.g. "OK".
+	Status string
+	// EffectivePolicies maps each policy kind to its effective spec.
+	EffectivePolicies map[string]interface{}
+}
+
+// TemplateListener describes a listener of a Gateway.
+type TemplateListener struct {
+	Name     string
+	Protocol string
+	Port     int32
+	// Hostname is empty if the listener accepts any host.
+	Hostname string
+}
+
+// TemplateHTTPRoute describes an HTTPRoute.
+type TemplateHTTPRoute struct {
+	Name      string
+	Namespace string
+	Hostnames []string
+	// Gateways references the Gateways the HTTPRoute is attached to.
+	Gateways []string
+	// Backends references the backends of the HTTPRoute, as
+	// "Kind/namespace/name".
+	Backends []string
+	// Status summarizes the status of the HTTPRoute, e.g. "OK".
+	Status string
+	// EffectivePolicies maps each Gateway the HTTPRoute is attached to, and
+	// then each policy kind, to the effective spec.
+	EffectivePolicies map[string]map[string]interface{}
+}
+
+// TemplateBackend describes a backend.
+type TemplateBackend struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// HTTPRoutes references the HTTPRoutes forwarding to the backend.
+	HTTPRoutes []string
+	// EffectivePolicies maps each Gateway through which the backend is reached,
+	// and then each policy kind, to the effective spec.
+	EffectivePolicies map[string]map[string]interface{}
+}
+
+// NewTemplateData derives the TemplateData from the ResourceModel.
+func NewTemplateData(resourceModel *resourcediscovery.ResourceModel) TemplateData {
+	data := TemplateData{
+		GatewayClasses: []TemplateGatewayClass{},
+		Gateways:       []TemplateGateway{},
+		HTTPRoutes:     []TemplateHTTPRoute{},
+		Backends:       []TemplateBackend{},
+	}
+
+	for _, gatewayClassNode := range resourceModel.GatewayClasses {
+		gatewayClass := TemplateGatewayClass{
+			Name:           gatewayClassNode.GatewayClass.GetName(),
+			ControllerName: string(gatewayClassNode.GatewayClass.Spec.ControllerName),
+			Gateways:       []string{},
+		}
+		for _, gatewayNode := range gatewayClassNode.Gateways {
+			gatewayClass.Gateways = append(gatewayClass.Gateways, templateRef(gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName()))
+		}
+		sort.Strings(gatewayClass.Gateways)
+		data.GatewayClasses = append(data.GatewayClasses, gatewayClass)
+	}
+	sort.Slice(data.GatewayClasses, func(i, j int) bool { return data.GatewayClasses[i].Name < data.GatewayClasses[j].Name })
+
+	for _, gatewayNode := range resourceModel.Gateways {
+		gateway := TemplateGateway{
+			Name:              gatewayNode.Gateway.GetName(),
+			Namespace:         gatewayNode.Gateway.GetNamespace(),
+			GatewayClass:      string(gatewayNode.Gateway.Spec.GatewayClassName),
+			Listeners:         []TemplateListener{},
+			HTTPRoutes:        []string{},
+			Status:            gatewayNode.StatusSummary().String(),
+			EffectivePolicies: templatePolicies(gatewayNode.EffectivePolicies),
+		}
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			templateListener := TemplateListener{
+				Name:     string(listener.Name),
+				Protocol: string(listener.Protocol),
+				Port:     int32(listener.Port),
+			}
+			if listener.Hostname != nil {
+				templateListener.Hostname = string(*listener.Hostname)
+			}
+			gateway.Listeners = append(gateway.Listeners, templateListener)
+		}
+		for _, httpRouteNode := range gatewayNode.HTTPRoutes {
+			gateway.HTTPRoutes = append(gateway.HTTPRoutes, templateRef(httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName()))
+		}
+		sort.Strings(gateway.HTTPRoutes)
+		data.Gateways = append(data.Gateways, gateway)
+	}
+	sort.Slice(data.Gateways, func(i, j int) bool {
+		return templateRef(data.Gateways[i].Namespace, data.Gateways[i].Name) < templateRef(data.Gateways[j].Namespace, data.Gateways[j].Name)
+	})
+
+	for _, httpRouteNode := range resourceModel.HTTPRoutes {
+		httpRoute := TemplateHTTPRoute{
+			Name:              httpRouteNode.HTTPRoute.GetName(),
+			Namespace:         httpRouteNode.HTTPRoute.GetNamespace(),
+			Hostnames:         []string{},
+			Gateways:          []string{},
+			Backends:          []string{},
+			Status:            httpRouteNode.StatusSummary().String(),
+			EffectivePolicies: make(map[string]map[string]interface{}),
+		}
+		for _, hostname := range httpRouteNode.HTTPRoute.Spec.Hostnames {
+			httpRoute.Hostnames = append(httpRoute.Hostnames, string(hostname))
+		}
+		for gatewayID, gatewayNode := range httpRouteNode.Gateways {
+			gatewayRef := templateRef(gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName())
+			httpRoute.Gateways = append(httpRoute.Gateways, gatewayRef)
+			httpRoute.EffectivePolicies[gatewayRef] = templatePolicies(httpRouteNode.EffectivePolicies[gatewayID])
+		}
+		sort.Strings(httpRoute.Gateways)
+		for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			httpRoute.Backends = append(httpRoute.Backends, fmt.Sprintf("%v/%v", backendRef.Kind, templateRef(backendRef.Namespace, backendRef.Name)))
+		}
+		sort.Strings(httpRoute.Backends)
+		data.HTTPRoutes = append(data.HTTPRoutes, httpRoute)
+	}
+	sort.Slice(data.HTTPRoutes, func(i, j int) bool {
+		return templateRef(data.HTTPRoutes[i].Namespace, data.HTTPRoutes[i].Name) < templateRef(data.HTTPRoutes[j].Namespace, data.HTTPRoutes[j].Name)
+	})
+
+	for _, backendNode := range resourceModel.Backends {
+		backend := TemplateBackend{
+			Kind:              backendNode.Backend.GetKind(),
+			Name:              backendNode.Backend.GetName(),
+			Namespace:         backendNode.Backend.GetNamespace(),
+			HTTPRoutes:        []string{},
+			EffectivePolicies: make(map[string]map[string]interface{}),
+		}
+		for _, httpRouteNode := range backendNode.HTTPRoutes {
+			backend.HTTPRoutes = append(backend.HTTPRoutes, templateRef(httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName()))
+		}
+		sort.Strings(backend.HTTPRoutes)
+		for gatewayID, policies := range backendNode.EffectivePolicies {
+			backend.EffectivePolicies[templateRef(gatewayID.Namespace, gatewayID.Name)] = templatePolicies(policies)
+		}
+		data.Backends = append(data.Backends, backend)
+	}
+	sort.Slice(data.Backends, func(i, j int) bool {
+		a := fmt.Sprintf("%v/%v", data.Backends[i].Kind, templateRef(data.Backends[i].Namespace, data.Backends[i].Name))
+		b := fmt.Sprintf("%v/%v", data.Backends[j].Kind, templateRef(data.Backends[j].Namespace, data.Backends[j].Name))
+		return a < b
+	})
+
+	return data
+}
+
+// PrintTemplate renders the TemplateData of the ResourceModel with the Go
+// template text.
+func PrintTemplate(w io.Writer, resourceModel *resourcediscovery.ResourceModel, text string) error {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse the go-template: %w", err)
+	}
+	if err := tmpl.Execute(w, NewTemplateData(resourceModel)); err != nil {
+		return fmt.Errorf("failed to execute the go-template: %w", err)
+	}
+	return nil
+}
+
+// templatePolicies maps each policy kind to its effective spec, falling back
+// to the spec of the policy if the effective spec can not be determined.
+func templatePolicies(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[string]interface{} {
+	result := make(map[string]interface{})
+	for policyCrdID, policy := range policies {
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			result[string(policyCrdID)] = policy.Spec()
+			continue
+		}
+		result[string(policyCrdID)] = spec
+	}
+	return result
+}
+
+func templateRef(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
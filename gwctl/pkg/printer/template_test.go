@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestPrintTemplate(t *testing.T) {
+	gateway := func(name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+				}},
+			},
+		}
+	}
+	httpRoute := func(name string, parents ...string) *gatewayv1.HTTPRoute {
+		var parentRefs []gatewayv1.ParentReference
+		for _, parent := range parents {
+			parentRefs = append(parentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(parent)})
+		}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(name + ".example.com")},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+			Spec: gatewayv1.GatewayClassSpec{
+				ControllerName: "example.net/gateway-controller",
+			},
+		},
+		gateway("foo-gateway"),
+		gateway("bar-gateway"),
+		httpRoute("foo-httproute", "foo-gateway"),
+		httpRoute("shared-httproute", "foo-gateway", "bar-gateway"),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      "timeout-policy",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"seconds": int64(30),
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), resourcediscovery.Filter{})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	testcases := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "gateways with the number of routes",
+			template: `{{range .Gateways}}{{.Name}} {{len .HTTPRoutes}}{{"\n"}}{{end}}`,
+			want: `bar-gateway 1
+foo-gateway 2
+`,
+		},
+		{
+			name:     "routes with their parents and backends",
+			template: `{{range .HTTPRoutes}}{{.Namespace}}/{{.Name}} {{range .Hostnames}}{{.}}{{end}} parents={{range $i, $v := .Gateways}}{{if $i}},{{end}}{{$v}}{{end}} backends={{range .Backends}}{{.}}{{end}}{{"\n"}}{{end}}`,
+			want: `default/foo-httproute foo-httproute.example.com parents=default/foo-gateway backends=Service/default/foo-svc
+default/shared-httproute shared-httproute.example.com parents=default/bar-gateway,default/foo-gateway backends=Service/default/foo-svc
+`,
+		},
+		{
+			name:     "effective policies and listeners",
+			template: `{{range .Gateways}}{{.Name}} class={{.GatewayClass}}{{range .Listeners}} {{.Name}}:{{.Protocol}}/{{.Port}}{{end}} timeout={{with index .EffectivePolicies "TimeoutPolicy.bar.com"}}{{.seconds}}{{else}}none{{end}}{{"\n"}}{{end}}`,
+			want: `bar-gateway class=foo-gatewayclass http:HTTP/80 timeout=none
+foo-gateway class=foo-gatewayclass http:HTTP/80 timeout=30
+`,
+		},
+		{
+			name:     "gateway classes and backends",
+			template: `{{range .GatewayClasses}}{{.Name}} {{.ControllerName}} {{len .Gateways}}{{"\n"}}{{end}}{{range .Backends}}{{.Kind}} {{.Name}} {{len .HTTPRoutes}}{{"\n"}}{{end}}`,
+			want: `foo-gatewayclass example.net/gateway-controller 2
+Service foo-svc 2
+`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := PrintTemplate(out, resourceModel, tc.template); err != nil {
+				t.Fatalf("PrintTemplate() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, out.String()); diff != "" {
+				t.Errorf("Unexpected diff\ngot=\n%v\nwant=\n%v\ndiff (-want +got)=\n%v", out.String(), tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestPrintTemplate_Errors(t *testing.T) {
+	resourceModel := &resourcediscovery.ResourceModel{}
+
+	testcases := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{
+			name:     "invalid syntax",
+			template: `{{range .Gateways}}{{.Name}}`,
+			wantErr:  "failed to parse the go-template",
+		},
+		{
+			name:     "unknown field",
+			template: `{{.Listeners}}`,
+			wantErr:  "failed to execute the go-template",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := PrintTemplate(&bytes.Buffer{}, resourceModel, tc.template)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("PrintTemplate() = %v; want an error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -31,29 +31,68 @@ import (
 // to.
 func FindGatewayRefsForHTTPRoute(httpRoute gatewayv1.HTTPRoute) []types.NamespacedName {
 	result := []types.NamespacedName{}
-	for _, gatewayRef := range httpRoute.Spec.ParentRefs {
-		namespace := httpRoute.GetNamespace()
-		if namespace == "" {
-			namespace = metav1.NamespaceDefault
-		}
-		if gatewayRef.Namespace != nil {
-			namespace = string(*gatewayRef.Namespace)
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if !isGatewayParentRef(parentRef) {
+			continue
 		}
+		result = append(result, parentRefNamespacedName(httpRoute, parentRef))
+	}
+	return result
+}
 
-		result = append(result, types.NamespacedName{
-			Namespace: namespace,
-			Name:      string(gatewayRef.Name),
-		})
+// FindServiceParentRefsForHTTPRoute returns Services which the HTTPRoute is
+// attached to. An HTTPRoute with a Service as its parent configures mesh
+// traffic (GAMMA) directed to that Service, instead of ingress traffic through a
+// Gateway.
+func FindServiceParentRefsForHTTPRoute(httpRoute gatewayv1.HTTPRoute) []types.NamespacedName {
+	result := []types.NamespacedName{}
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if !isServiceParentRef(parentRef) {
+			continue
+		}
+		result = append(result, parentRefNamespacedName(httpRoute, parentRef))
 	}
 	return result
 }
 
+// isGatewayParentRef returns true if the parentRef references a Gateway. Group
+// and Kind default to the Gateway when unspecified.
+func isGatewayParentRef(parentRef gatewayv1.ParentReference) bool {
+	if parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName {
+		return false
+	}
+	return parentRef.Kind == nil || *parentRef.Kind == "Gateway"
+}
+
+// isServiceParentRef returns true if the parentRef references a core Service.
+func isServiceParentRef(parentRef gatewayv1.ParentReference) bool {
+	return parentRef.Group != nil && *parentRef.Group == "" &&
+		parentRef.Kind != nil && *parentRef.Kind == "Service"
+}
+
+// parentRefNamespacedName returns the namespaced name of the parent, defaulting
+// the namespace to that of the HTTPRoute.
+func parentRefNamespacedName(httpRoute gatewayv1.HTTPRoute, parentRef gatewayv1.ParentReference) types.NamespacedName {
+	namespace := httpRoute.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      string(parentRef.Name),
+	}
+}
+
 // FindGatewayClassNameForGateway returns GatewayClass for the Gateway.
 func FindGatewayClassNameForGateway(gateway gatewayv1.Gateway) string {
 	return string(gateway.Spec.GatewayClassName)
 }
 
-// FindBackendRefsForHTTPRoute returns Backends which the HTTPRoute references.
+// FindBackendRefsForHTTPRoute returns Backends which the HTTPRoute references,
+// in the order in which they are declared in the rules of the HTTPRoute.
 func FindBackendRefsForHTTPRoute(httpRoute gatewayv1.HTTPRoute) []common.ObjRef {
 	// Aggregate all BackendRefs
 	var backendRefs []gatewayv1.BackendObjectReference
@@ -74,30 +113,15 @@ func FindBackendRefsForHTTPRoute(httpRoute gatewayv1.HTTPRoute) []common.ObjRef
 
 	// Convert each BackendRef to ObjRef. ObjRef does not use pointers and thus is
 	// easily comparable.
-	resultSet := make(map[common.ObjRef]bool)
+	seen := make(map[common.ObjRef]bool)
+	var result []common.ObjRef
 	for _, backendRef := range backendRefs {
-		objRef := common.ObjRef{
-			Name: string(backendRef.Name),
-			// Assume namespace is unspecified in the backendRef and check later to
-			// override the default value.
-			Namespace: httpRoute.GetNamespace(),
+		objRef := BackendObjRef(httpRoute.GetNamespace(), backendRef)
+		// Return unique objRefs, in the order of their first reference.
+		if !seen[objRef] {
+			seen[objRef] = true
+			result = append(result, objRef)
 		}
-		if backendRef.Group != nil {
-			objRef.Group = string(*backendRef.Group)
-		}
-		if backendRef.Kind != nil {
-			objRef.Kind = string(*backendRef.Kind)
-		}
-		if backendRef.Namespace != nil {
-			objRef.Namespace = string(*backendRef.Namespace)
-		}
-		resultSet[objRef] = true
-	}
-
-	// Return unique objRefs
-	var result []common.ObjRef
-	for objRef := range resultSet {
-		result = append(result, objRef)
 	}
 	return result
 }
@@ -140,3 +164,25 @@ func ReferenceGrantAccepts(referenceGrant gatewayv1beta1.ReferenceGrant, resourc
 	}
 	return false
 }
+
+// BackendObjRef converts the backendRef of a route in namespace
+// routeNamespace to an ObjRef, applying the defaults of the Gateway API: an
+// empty group is the core API group, an empty kind is Service, and an empty
+// namespace is the namespace of the route.
+func BackendObjRef(routeNamespace string, backendRef gatewayv1.BackendObjectReference) common.ObjRef {
+	objRef := common.ObjRef{
+		Kind:      "Service",
+		Name:      string(backendRef.Name),
+		Namespace: routeNamespace,
+	}
+	if backendRef.Group != nil {
+		objRef.Group = string(*backendRef.Group)
+	}
+	if backendRef.Kind != nil && *backendRef.Kind != "" {
+		objRef.Kind = string(*backendRef.Kind)
+	}
+	if backendRef.Namespace != nil && *backendRef.Namespace != "" {
+		objRef.Namespace = string(*backendRef.Namespace)
+	}
+	return objRef
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// PendingAddress stands in for the address of a Gateway in access URLs while
+// the Gateway does not report any address in its status.
+const PendingAddress = "<pending>"
+
+// AccessURLs returns, for each Gateway the HTTPRoute is attached to, the sorted
+// URLs through which the HTTPRoute can be reached: one for each hostname the
+// HTTPRoute serves through each listener it attaches to, e.g.
+// "https://foo.example.com:8443". Where the hostname is a wildcard, or any
+// hostname is served, there is one URL for each address of the Gateway
+// instead, e.g. "https://192.0.2.1:8443". Gateways without an address yield
+// URLs with PendingAddress instead. When Gateways share an address, each URL
+// with that address is only listed for the first of them in the order of
+// their namespace and name.
+func (h *HTTPRouteNode) AccessURLs() map[gatewayID][]string {
+	result := make(map[gatewayID][]string)
+	seen := make(map[string]bool)
+	for _, gatewayID := range sortedGatewayIDs(h.Gateways) {
+		gatewayNode := h.Gateways[gatewayID]
+		addresses := gatewayAddresses(gatewayNode.Gateway)
+
+		// addressURLs are the URLs of urls using an address of the Gateway.
+		urls, addressURLs := make(map[string]bool), make(map[string]bool)
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if !acceptsHTTPRoutes(listener) || !attachedToListener(h.HTTPRoute, gatewayID, listener.Name, listener.Port) {
+				continue
+			}
+			for _, hostname := range servedHostnames(h.HTTPRoute, listener) {
+				if hostname != "" && !strings.HasPrefix(hostname, "*") {
+					urls[accessURL(listener, hostname)] = true
+					continue
+				}
+				for _, address := range addresses {
+					url := accessURL(listener, address)
+					if address != PendingAddress && seen[url] {
+						continue
+					}
+					urls[url] = true
+					addressURLs[url] = true
+				}
+			}
+		}
+
+		result[gatewayID] = []string{}
+		for url := range urls {
+			result[gatewayID] = append(result[gatewayID], url)
+		}
+		for url := range addressURLs {
+			seen[url] = true
+		}
+		sort.Strings(result[gatewayID])
+	}
+	return result
+}
+
+// gatewayAddresses returns the distinct addresses reported in the status of
+// the Gateway, or PendingAddress if there are none.
+func gatewayAddresses(gateway *gatewayv1.Gateway) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, address := range gateway.Status.Addresses {
+		if address.Value == "" || seen[address.Value] {
+			continue
+		}
+		seen[address.Value] = true
+		result = append(result, address.Value)
+	}
+	if len(result) == 0 {
+		return []string{PendingAddress}
+	}
+	return result
+}
+
+// accessURL returns the URL of the listener at the address, which is either an
+// IP address or a hostname. The port is omitted if it is the default port of
+// the scheme.
+func accessURL(listener gatewayv1.Listener, address string) string {
+	scheme, defaultPort := "http", gatewayv1.PortNumber(80)
+	if listener.Protocol == gatewayv1.HTTPSProtocolType {
+		scheme, defaultPort = "https", 443
+	}
+	if listener.Port == defaultPort {
+		if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+			address = "[" + address + "]"
+		}
+		return fmt.Sprintf("%v://%v", scheme, address)
+	}
+	return fmt.Sprintf("%v://%v", scheme, net.JoinHostPort(address, fmt.Sprint(listener.Port)))
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestHTTPRouteNode_AccessURLs(t *testing.T) {
+	gateway := func(name string, listeners []gatewayv1.Listener, addresses ...string) *GatewayNode {
+		gateway := &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        listeners,
+			},
+		}
+		for _, address := range addresses {
+			gateway.Status.Addresses = append(gateway.Status.Addresses, gatewayv1.GatewayStatusAddress{
+				Type:  common.PtrTo(gatewayv1.IPAddressType),
+				Value: address,
+			})
+		}
+		return NewGatewayNode(gateway)
+	}
+	httpListener := gatewayv1.Listener{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80}
+
+	testcases := []struct {
+		name      string
+		gateways  []*GatewayNode
+		hostnames []gatewayv1.Hostname
+		want      map[gatewayID][]string
+	}{
+		{
+			name: "one Gateway with an address and one pending",
+			gateways: []*GatewayNode{
+				gateway("bar-gateway", []gatewayv1.Listener{
+					httpListener,
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443},
+					{Name: "tcp", Protocol: gatewayv1.TCPProtocolType, Port: 9000},
+				}, "192.0.2.1", "2001:db8::1"),
+				gateway("foo-gateway", []gatewayv1.Listener{httpListener}),
+			},
+			want: map[gatewayID][]string{
+				GatewayID("default", "bar-gateway"): {
+					"http://192.0.2.1",
+					"http://[2001:db8::1]",
+					"https://192.0.2.1:8443",
+					"https://[2001:db8::1]:8443",
+				},
+				GatewayID("default", "foo-gateway"): {"http://<pending>"},
+			},
+		},
+		{
+			name: "Gateways sharing an address",
+			gateways: []*GatewayNode{
+				gateway("bar-gateway", []gatewayv1.Listener{httpListener}, "192.0.2.1"),
+				gateway("foo-gateway", []gatewayv1.Listener{
+					httpListener,
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				}, "192.0.2.1", "192.0.2.1"),
+			},
+			want: map[gatewayID][]string{
+				GatewayID("default", "bar-gateway"): {"http://192.0.2.1"},
+				GatewayID("default", "foo-gateway"): {"https://192.0.2.1"},
+			},
+		},
+		{
+			name: "all Gateways pending",
+			gateways: []*GatewayNode{
+				gateway("bar-gateway", []gatewayv1.Listener{httpListener}),
+				gateway("foo-gateway", []gatewayv1.Listener{httpListener}),
+			},
+			want: map[gatewayID][]string{
+				GatewayID("default", "bar-gateway"): {"http://<pending>"},
+				GatewayID("default", "foo-gateway"): {"http://<pending>"},
+			},
+		},
+		{
+			name: "hostnames of the listeners and the HTTPRoute",
+			gateways: []*GatewayNode{
+				gateway("foo-gateway", []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80, Hostname: common.PtrTo(gatewayv1.Hostname("*.example.com"))},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443, Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com"))},
+					{Name: "other", Protocol: gatewayv1.HTTPProtocolType, Port: 8080, Hostname: common.PtrTo(gatewayv1.Hostname("other.com"))},
+				}, "192.0.2.1"),
+			},
+			hostnames: []gatewayv1.Hostname{"foo.example.com", "*.bar.example.com"},
+			// The wildcard hostname yields a URL with the address of the Gateway,
+			// and the listener whose hostname matches none of the HTTPRoute yields
+			// no URL.
+			want: map[gatewayID][]string{
+				GatewayID("default", "foo-gateway"): {
+					"http://192.0.2.1",
+					"http://foo.example.com",
+					"https://foo.example.com:8443",
+				},
+			},
+		},
+		{
+			name: "hostname of the listener while the address is pending",
+			gateways: []*GatewayNode{
+				gateway("foo-gateway", []gatewayv1.Listener{
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443, Hostname: common.PtrTo(gatewayv1.Hostname("foo.example.com"))},
+				}),
+			},
+			want: map[gatewayID][]string{
+				GatewayID("default", "foo-gateway"): {"https://foo.example.com"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var parentRefs []gatewayv1.ParentReference
+			for _, gatewayNode := range tc.gateways {
+				parentRefs = append(parentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayNode.Gateway.Name)})
+			}
+			httpRouteNode := NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-httproute",
+					Namespace: "default",
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+					Hostnames:       tc.hostnames,
+				},
+			})
+			for _, gatewayNode := range tc.gateways {
+				httpRouteNode.Gateways[gatewayNode.ID()] = gatewayNode
+			}
+
+			if diff := cmp.Diff(tc.want, httpRouteNode.AccessURLs()); diff != "" {
+				t.Errorf("AccessURLs() diff (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// RequestedAddress is an address which a Gateway explicitly requests in its
+// spec, with the default type applied.
+type RequestedAddress struct {
+	Type  gatewayv1.AddressType
+	Value string
+}
+
+// RequestedAddresses returns the distinct addresses requested in the spec of the
+// Gateway, in the order of the spec. Addresses without a value, which leave
+// the choice of the address to the implementation, are omitted, as are all
+// addresses of Gateways which do not request any and are assigned addresses
+// dynamically.
+func (g *GatewayNode) RequestedAddresses() []RequestedAddress {
+	var result []RequestedAddress
+	seen := make(map[RequestedAddress]bool)
+	for _, address := range g.Gateway.Spec.Addresses {
+		if address.Value == "" {
+			continue
+		}
+		requested := RequestedAddress{Type: gatewayv1.IPAddressType, Value: address.Value}
+		if address.Type != nil {
+			requested.Type = *address.Type
+		}
+		if !seen[requested] {
+			seen[requested] = true
+			result = append(result, requested)
+		}
+	}
+	return result
+}
+
+// AddressConflict is an address which multiple Gateways request in their spec.
+// At most one of them can be assigned the address.
+type AddressConflict struct {
+	Address RequestedAddress
+	// Gateways references the Gateways requesting the address, sorted by
+	// namespace and name.
+	Gateways []common.ObjRef
+}
+
+// GatewayAddressConflicts returns the addresses which are requested by more
+// than one Gateway of the ResourceModel, sorted by type and value. Only
+// explicitly requested addresses are compared, since dynamically assigned
+// addresses are chosen by the implementation.
+func (rm *ResourceModel) GatewayAddressConflicts() []AddressConflict {
+	gatewaysByAddress := make(map[RequestedAddress][]common.ObjRef)
+	for _, gatewayID := range sortedGatewayIDs(rm.Gateways) {
+		for _, address := range rm.Gateways[gatewayID].RequestedAddresses() {
+			gatewaysByAddress[address] = append(gatewaysByAddress[address], gatewayObjRef(gatewayID))
+		}
+	}
+
+	var result []AddressConflict
+	for address, gateways := range gatewaysByAddress {
+		if len(gateways) > 1 {
+			result = append(result, AddressConflict{Address: address, Gateways: gateways})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Address, result[j].Address
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Value < b.Value
+	})
+	return result
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_GatewayAddressConflicts(t *testing.T) {
+	gateway := func(namespace, name string, addresses ...gatewayv1.GatewayAddress) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Addresses:        addresses,
+			},
+		}
+	}
+	ipAddress := func(value string) gatewayv1.GatewayAddress {
+		return gatewayv1.GatewayAddress{Value: value}
+	}
+	hostname := func(value string) gatewayv1.GatewayAddress {
+		return gatewayv1.GatewayAddress{Type: common.PtrTo(gatewayv1.HostnameAddressType), Value: value}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("team-a"),
+		common.NamespaceForTest("team-b"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		// The type of addresses defaults to IPAddress.
+		gateway("team-a", "foo-gateway", ipAddress("192.0.2.1"), hostname("lb.example.com")),
+		gateway("team-b", "bar-gateway", gatewayv1.GatewayAddress{Type: common.PtrTo(gatewayv1.IPAddressType), Value: "192.0.2.1"}),
+		gateway("team-b", "baz-gateway", ipAddress("192.0.2.1"), hostname("other.example.com")),
+		// Hostnames and IP addresses with the same value do not conflict.
+		gateway("team-b", "qux-gateway", gatewayv1.GatewayAddress{Type: common.PtrTo(gatewayv1.HostnameAddressType), Value: "192.0.2.2"}, ipAddress("lb.example.com")),
+		// Addresses without a value and Gateways without addresses are assigned
+		// addresses dynamically, which never conflict.
+		gateway("team-a", "dynamic-gateway", gatewayv1.GatewayAddress{Type: common.PtrTo(gatewayv1.IPAddressType)}),
+		gateway("team-b", "dynamic-gateway"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	want := []AddressConflict{
+		{
+			Address: RequestedAddress{Type: gatewayv1.IPAddressType, Value: "192.0.2.1"},
+			Gateways: []common.ObjRef{
+				{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "team-a", Name: "foo-gateway"},
+				{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "team-b", Name: "bar-gateway"},
+				{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "team-b", Name: "baz-gateway"},
+			},
+		},
+	}
+	got := resourceModel.GatewayAddressConflicts()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in GatewayAddressConflicts(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
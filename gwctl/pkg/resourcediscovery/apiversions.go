@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// latestAPIVersions maps the kinds of Gateway API resources to the latest
+// version known to gwctl. Older versions of a kind are considered deprecated.
+var latestAPIVersions = map[string]schema.GroupVersion{
+	"GatewayClass": gatewayv1.SchemeGroupVersion,
+	"Gateway":      gatewayv1.SchemeGroupVersion,
+	"HTTPRoute":    gatewayv1.SchemeGroupVersion,
+	// ReferenceGrant has not graduated to v1 yet, so v1beta1 is the latest
+	// version and is not deprecated.
+	"ReferenceGrant": gatewayv1beta1.SchemeGroupVersion,
+}
+
+// DeprecatedAPIVersion returns the API version which should be used instead of
+// apiVersion for resources of the given kind, and true if apiVersion is
+// deprecated, i.e. it is older than the latest version known to gwctl.
+func DeprecatedAPIVersion(kind, apiVersion string) (string, bool) {
+	latest, ok := latestAPIVersions[kind]
+	if !ok {
+		return "", false
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || gv.Group != latest.Group {
+		return "", false
+	}
+	if version.CompareKubeAwareVersionStrings(latest.Version, gv.Version) <= 0 {
+		return "", false
+	}
+	return latest.String(), true
+}
+
+// recordAPIVersion records the API version which the object was read as, unless
+// it was already populated from the response of the API server. The API server
+// converts objects to the version which is requested, so this is not
+// necessarily the version the object was written as; see WrittenAPIVersion.
+func recordAPIVersion(typeMeta *metav1.TypeMeta, gvr schema.GroupVersionResource, kind string) {
+	if typeMeta.APIVersion == "" {
+		typeMeta.APIVersion = gvr.GroupVersion().String()
+	}
+	if typeMeta.Kind == "" {
+		typeMeta.Kind = kind
+	}
+}
+
+// WrittenAPIVersion returns the API version which the object was last written
+// as, according to the most recent entry of its managedFields which does not
+// belong to a subresource. It returns an empty string if the object has no such
+// entry.
+func WrittenAPIVersion(object metav1.Object) string {
+	var latest *metav1.ManagedFieldsEntry
+	for i, entry := range object.GetManagedFields() {
+		if entry.Subresource != "" || entry.APIVersion == "" {
+			continue
+		}
+		if latest == nil || latest.Time == nil || (entry.Time != nil && !entry.Time.Before(latest.Time)) {
+			latest = &object.GetManagedFields()[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.APIVersion
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeprecatedAPIVersion(t *testing.T) {
+	testcases := []struct {
+		kind            string
+		apiVersion      string
+		wantReplacement string
+		wantDeprecated  bool
+	}{
+		{kind: "HTTPRoute", apiVersion: "gateway.networking.k8s.io/v1"},
+		{kind: "HTTPRoute", apiVersion: "gateway.networking.k8s.io/v1beta1", wantReplacement: "gateway.networking.k8s.io/v1", wantDeprecated: true},
+		{kind: "Gateway", apiVersion: "gateway.networking.k8s.io/v1alpha2", wantReplacement: "gateway.networking.k8s.io/v1", wantDeprecated: true},
+		{kind: "ReferenceGrant", apiVersion: "gateway.networking.k8s.io/v1beta1"},
+		{kind: "ReferenceGrant", apiVersion: "gateway.networking.k8s.io/v1alpha2", wantReplacement: "gateway.networking.k8s.io/v1beta1", wantDeprecated: true},
+		// Versions newer than the latest known version are not deprecated.
+		{kind: "ReferenceGrant", apiVersion: "gateway.networking.k8s.io/v1"},
+		// Kinds and groups unknown to gwctl are never deprecated.
+		{kind: "Service", apiVersion: "v1"},
+		{kind: "HTTPRoute", apiVersion: "example.com/v1alpha1"},
+	}
+	for _, tc := range testcases {
+		gotReplacement, gotDeprecated := DeprecatedAPIVersion(tc.kind, tc.apiVersion)
+		if gotReplacement != tc.wantReplacement || gotDeprecated != tc.wantDeprecated {
+			t.Errorf("DeprecatedAPIVersion(%q, %q) = (%q, %v); want (%q, %v)", tc.kind, tc.apiVersion, gotReplacement, gotDeprecated, tc.wantReplacement, tc.wantDeprecated)
+		}
+	}
+}
+
+func TestWrittenAPIVersion(t *testing.T) {
+	at := func(minutes int) *metav1.Time {
+		return &metav1.Time{Time: time.Date(2024, 1, 1, 0, minutes, 0, 0, time.UTC)}
+	}
+	testcases := []struct {
+		name          string
+		managedFields []metav1.ManagedFieldsEntry
+		want          string
+	}{
+		{
+			name: "no managedFields",
+		},
+		{
+			name: "most recent write wins",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", APIVersion: "gateway.networking.k8s.io/v1", Time: at(2)},
+				{Manager: "helm", APIVersion: "gateway.networking.k8s.io/v1beta1", Time: at(1)},
+			},
+			want: "gateway.networking.k8s.io/v1",
+		},
+		{
+			name: "writes to subresources are ignored",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", APIVersion: "gateway.networking.k8s.io/v1beta1", Time: at(1)},
+				{Manager: "controller", APIVersion: "gateway.networking.k8s.io/v1", Time: at(2), Subresource: "status"},
+			},
+			want: "gateway.networking.k8s.io/v1beta1",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			object := &metav1.ObjectMeta{ManagedFields: tc.managedFields}
+			if got := WrittenAPIVersion(object); got != tc.want {
+				t.Errorf("WrittenAPIVersion() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
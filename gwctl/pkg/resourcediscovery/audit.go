@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// AuditAction is the kind of decision recorded by an AuditEvent.
+type AuditAction string
+
+const (
+	// AuditActionAdd records a node being added to the ResourceModel.
+	AuditActionAdd AuditAction = "add"
+	// AuditActionConnect records an edge being established between two nodes.
+	AuditActionConnect AuditAction = "connect"
+	// AuditActionSkip records a node or an edge which was not added, e.g. since
+	// the other end of the edge is not part of the ResourceModel.
+	AuditActionSkip AuditAction = "skip"
+	// AuditActionResolve records the effective policies calculated for a
+	// resource.
+	AuditActionResolve AuditAction = "resolve"
+)
+
+// AuditEvent records a single decision taken while building a ResourceModel.
+type AuditEvent struct {
+	// Action is the kind of decision.
+	Action AuditAction
+	// Subject describes the resource the decision is about, e.g.
+	// `Policy "default/timeout-policy"`.
+	Subject string
+	// Object describes the other resource involved in the decision, e.g. the
+	// target of a policy. It is empty if no other resource is involved.
+	Object string
+	// Outcome describes the result of the decision, e.g. "connected" or
+	// "policy skipped: target not found".
+	Outcome string
+}
+
+// AuditLog returns the decisions taken while building the ResourceModel, in
+// the order in which they were taken. It is only recorded if the
+// ResourceModel was discovered with Discoverer.Audit set.
+func (rm *ResourceModel) AuditLog() []AuditEvent {
+	return append([]AuditEvent(nil), rm.auditLog...)
+}
+
+// audit records an AuditEvent if auditing is enabled. subject and object are
+// node IDs; object may be nil.
+func (rm *ResourceModel) audit(action AuditAction, subject, object interface{}, outcome string) {
+	if !rm.auditEnabled {
+		return
+	}
+	event := AuditEvent{Action: action, Subject: describeID(subject), Outcome: outcome}
+	if object != nil {
+		event.Object = describeID(object)
+	}
+	rm.auditLog = append(rm.auditLog, event)
+}
+
+// effectivePoliciesOutcome describes the kinds of the effective policies
+// resolved for a resource.
+func effectivePoliciesOutcome(policies map[policymanager.PolicyCrdID]policymanager.Policy) string {
+	if len(policies) == 0 {
+		return "no effective policies"
+	}
+	var kinds []string
+	for policyCrdID := range policies {
+		kinds = append(kinds, string(policyCrdID))
+	}
+	sort.Strings(kinds)
+	return "effective policies: " + strings.Join(kinds, ", ")
+}
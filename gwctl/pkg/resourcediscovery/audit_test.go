@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_AuditLog(t *testing.T) {
+	timeoutPolicy := func(name, gatewayName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  gatewayName,
+					},
+				},
+			},
+		}
+	}
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.bar.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "direct"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		timeoutPolicy("timeout-policy", "foo-gateway"),
+		// The policy targets a Gateway which does not exist.
+		timeoutPolicy("dangling-timeout-policy", "missing-gateway"),
+	}
+
+	newResourceModel := func(audit bool) *ResourceModel {
+		params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+		discoverer := Discoverer{
+			K8sClients:    params.K8sClients,
+			PolicyManager: params.PolicyManager,
+			Audit:         audit,
+		}
+		resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Namespace: "default", Labels: labels.Everything()})
+		if err != nil {
+			t.Fatalf("Failed to construct resourceModel: %v", err)
+		}
+		return resourceModel
+	}
+
+	if got := newResourceModel(false).AuditLog(); len(got) != 0 {
+		t.Errorf("AuditLog() without auditing = %v; want none", got)
+	}
+
+	auditLog := newResourceModel(true).AuditLog()
+	for _, want := range []AuditEvent{
+		{Action: AuditActionAdd, Subject: `Gateway "default/foo-gateway"`, Outcome: "added"},
+		{Action: AuditActionConnect, Subject: `Gateway "default/foo-gateway"`, Object: `GatewayClass "foo-gatewayclass"`, Outcome: "connected"},
+		{Action: AuditActionConnect, Subject: `Policy "default/timeout-policy"`, Object: `Gateway "default/foo-gateway"`, Outcome: "policy attached"},
+		{Action: AuditActionSkip, Subject: `Policy "default/dangling-timeout-policy"`, Object: `Gateway "default/missing-gateway"`, Outcome: "policy skipped: target not found"},
+		{Action: AuditActionResolve, Subject: `Gateway "default/foo-gateway"`, Outcome: "effective policies: TimeoutPolicy.bar.com"},
+	} {
+		found := false
+		for _, event := range auditLog {
+			if event == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("AuditLog() is missing %+v; got %+v", want, auditLog)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// BaselineEntry declares the desired effective policies of a resource.
+type BaselineEntry struct {
+	// Kind of the resource, one of Gateway, HTTPRoute or the kind of a
+	// Backend (e.g. Service).
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// EffectivePolicies maps kinds of policies (e.g. TimeoutPolicy.bar.com)
+	// to their desired effective spec. Kinds which are not listed are not
+	// compared.
+	EffectivePolicies map[policymanager.PolicyCrdID]map[string]interface{} `json:"effectivePolicies"`
+}
+
+// Baseline declares the desired effective policies of a set of resources.
+type Baseline []BaselineEntry
+
+// LoadBaseline reads a Baseline from the YAML file at path, e.g.
+//
+//	# The desired effective policies of default/foo-gateway.
+//	- kind: Gateway
+//	  namespace: default
+//	  name: foo-gateway
+//	  effectivePolicies:
+//	    TimeoutPolicy.bar.com:
+//	      timeout: 30s
+func LoadBaseline(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	return ParseBaseline(data)
+}
+
+// ParseBaseline parses a Baseline from YAML. See LoadBaseline.
+func ParseBaseline(data []byte) (Baseline, error) {
+	var result Baseline
+	if err := yaml.UnmarshalStrict(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	for i, entry := range result {
+		if entry.Kind == "" || entry.Name == "" {
+			return nil, fmt.Errorf("invalid baseline entry %d: kind and name must be set", i)
+		}
+		if entry.Namespace == "" {
+			result[i].Namespace = metav1.NamespaceDefault
+		}
+	}
+	return result, nil
+}
+
+// BaselineDeviation describes how the effective policy of one kind of a
+// resource deviates from the Baseline.
+type BaselineDeviation struct {
+	// Resource is the resource whose effective policy deviates.
+	Resource common.ObjRef
+	// Gateway is the Gateway through which the effective policy applies. It is
+	// empty for Gateways.
+	Gateway common.ObjRef
+	// PolicyCrdID is the kind of the effective policy.
+	PolicyCrdID policymanager.PolicyCrdID
+	// Changes lists the fields of the effective spec which deviate, with
+	// PreviousValue being the value declared in the Baseline and Value the
+	// live one.
+	Changes []policymanager.FieldChange
+}
+
+// CheckBaseline compares the effective policies of the resources listed in
+// the Baseline with their desired effective specs, and returns the
+// deviations. HTTPRoutes and Backends are compared once for every Gateway
+// through which they receive effective policies. Resources which are not part
+// of the ResourceModel are skipped.
+func (rm *ResourceModel) CheckBaseline(baseline Baseline) ([]BaselineDeviation, error) {
+	var result []BaselineDeviation
+	compare := func(resource, gateway common.ObjRef, entry BaselineEntry, policies map[policymanager.PolicyCrdID]policymanager.Policy) error {
+		var policyCrdIDs []policymanager.PolicyCrdID
+		for policyCrdID := range entry.EffectivePolicies {
+			policyCrdIDs = append(policyCrdIDs, policyCrdID)
+		}
+		sort.Slice(policyCrdIDs, func(i, j int) bool { return policyCrdIDs[i] < policyCrdIDs[j] })
+
+		for _, policyCrdID := range policyCrdIDs {
+			var actual *policymanager.Policy
+			if policy, ok := policies[policyCrdID]; ok {
+				actual = &policy
+			}
+			changes, err := policymanager.ComputeSpecDeviation(entry.EffectivePolicies[policyCrdID], actual)
+			if err != nil {
+				return fmt.Errorf("failed to compare effective %v of %v %v/%v: %w", policyCrdID, entry.Kind, entry.Namespace, entry.Name, err)
+			}
+			if len(changes) != 0 {
+				result = append(result, BaselineDeviation{Resource: resource, Gateway: gateway, PolicyCrdID: policyCrdID, Changes: changes})
+			}
+		}
+		return nil
+	}
+
+	for _, entry := range baseline {
+		switch entry.Kind {
+		case "Gateway":
+			id := GatewayID(entry.Namespace, entry.Name)
+			gatewayNode, ok := rm.Gateways[id]
+			if !ok {
+				continue
+			}
+			if err := compare(gatewayObjRef(id), common.ObjRef{}, entry, gatewayNode.EffectivePolicies); err != nil {
+				return nil, err
+			}
+		case "HTTPRoute":
+			id := HTTPRouteID(entry.Namespace, entry.Name)
+			httpRouteNode, ok := rm.HTTPRoutes[id]
+			if !ok {
+				continue
+			}
+			resource := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: id.Namespace, Name: id.Name}
+			for _, gatewayID := range sortedGatewayIDs(httpRouteNode.EffectivePolicies) {
+				if err := compare(resource, gatewayObjRef(gatewayID), entry, httpRouteNode.EffectivePolicies[gatewayID]); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			for id, backendNode := range rm.Backends {
+				if backendNode.Backend.GetKind() != entry.Kind || id.Namespace != entry.Namespace || id.Name != entry.Name {
+					continue
+				}
+				resource := common.ObjRef{Group: id.Group, Kind: entry.Kind, Namespace: id.Namespace, Name: id.Name}
+				for _, gatewayID := range sortedGatewayIDs(backendNode.EffectivePolicies) {
+					if err := compare(resource, gatewayObjRef(gatewayID), entry, backendNode.EffectivePolicies[gatewayID]); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
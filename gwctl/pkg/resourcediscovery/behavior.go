@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// BehaviorChange describes how the effective policy of one kind changes for a
+// resource when behavior rules are enabled.
+type BehaviorChange struct {
+	// Resource is the resource whose effective policy changes.
+	Resource common.ObjRef
+	// Gateway is the Gateway through which the effective policy applies. It is
+	// empty for Gateways.
+	Gateway common.ObjRef
+	// RuleName is the name of the HTTPRoute rule whose effective policy changes.
+	// It is empty if the change applies to the HTTPRoute as a whole.
+	RuleName string
+	// PolicyCrdID is the kind of the effective policy.
+	PolicyCrdID policymanager.PolicyCrdID
+	// Changes lists the fields of the effective spec which change, with
+	// PreviousValue being the value under the current behavior.
+	Changes []policymanager.FieldChange
+}
+
+// DiffBehavior calculates the effective policies of all resources a second
+// time, with the given behavior rules enabled, and returns the effective
+// policies which would change. The ResourceModel itself is not modified.
+func (rm *ResourceModel) DiffBehavior(rules ...policymanager.BehaviorRule) ([]BehaviorChange, error) {
+	if _, err := policymanager.NewMergeRules(rules...); err != nil {
+		return nil, err
+	}
+
+	after := rm.Clone()
+	after.mergeRules = rm.mergeRules.WithBehaviors(rules...)
+	if err := after.calculateEffectivePolicies(); err != nil {
+		return nil, fmt.Errorf("failed to calculate effective policies with rules %v: %w", rules, err)
+	}
+
+	var result []BehaviorChange
+	add := func(resource, gateway common.ObjRef, ruleName string, before, after map[policymanager.PolicyCrdID]policymanager.Policy) error {
+		changes, err := diffEffectivePolicies(before, after)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			change.Resource, change.Gateway, change.RuleName = resource, gateway, ruleName
+			result = append(result, change)
+		}
+		return nil
+	}
+
+	for id, gatewayNode := range rm.Gateways {
+		resource := gatewayObjRef(id)
+		if err := add(resource, common.ObjRef{}, "", gatewayNode.EffectivePolicies, after.Gateways[id].EffectivePolicies); err != nil {
+			return nil, err
+		}
+	}
+	for id, httpRouteNode := range rm.HTTPRoutes {
+		resource := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: id.Namespace, Name: id.Name}
+		afterNode := after.HTTPRoutes[id]
+		for _, gatewayID := range sortedGatewayIDs(httpRouteNode.EffectivePolicies) {
+			if err := add(resource, gatewayObjRef(gatewayID), "", httpRouteNode.EffectivePolicies[gatewayID], afterNode.EffectivePolicies[gatewayID]); err != nil {
+				return nil, err
+			}
+			for ruleName, policies := range httpRouteNode.RuleEffectivePolicies[gatewayID] {
+				if err := add(resource, gatewayObjRef(gatewayID), ruleName, policies, afterNode.RuleEffectivePolicies[gatewayID][ruleName]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for id, backendNode := range rm.Backends {
+		resource := common.ObjRef{Group: id.Group, Kind: backendNode.Backend.GetKind(), Namespace: id.Namespace, Name: id.Name}
+		for _, gatewayID := range sortedGatewayIDs(backendNode.EffectivePolicies) {
+			if err := add(resource, gatewayObjRef(gatewayID), "", backendNode.EffectivePolicies[gatewayID], after.Backends[id].EffectivePolicies[gatewayID]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		aKey := fmt.Sprintf("%v/%v/%v/%v/%v/%v/%v", a.Resource.Kind, a.Resource.Namespace, a.Resource.Name, a.Gateway.Namespace, a.Gateway.Name, a.RuleName, a.PolicyCrdID)
+		bKey := fmt.Sprintf("%v/%v/%v/%v/%v/%v/%v", b.Resource.Kind, b.Resource.Namespace, b.Resource.Name, b.Gateway.Namespace, b.Gateway.Name, b.RuleName, b.PolicyCrdID)
+		return aKey < bKey
+	})
+	return result, nil
+}
+
+// diffEffectivePolicies returns a BehaviorChange, without the resource, for
+// each kind of policy whose effective spec differs between before and after.
+func diffEffectivePolicies(before, after map[policymanager.PolicyCrdID]policymanager.Policy) ([]BehaviorChange, error) {
+	var result []BehaviorChange
+	for policyCrdID, afterPolicy := range after {
+		var beforePolicy *policymanager.Policy
+		if policy, ok := before[policyCrdID]; ok {
+			beforePolicy = &policy
+		}
+		changes, err := policymanager.ComputeSpecDelta(beforePolicy, afterPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) != 0 {
+			result = append(result, BehaviorChange{PolicyCrdID: policyCrdID, Changes: changes})
+		}
+	}
+	for policyCrdID, beforePolicy := range before {
+		if _, ok := after[policyCrdID]; ok {
+			continue
+		}
+		// The policy no longer applies, so all its fields are removed.
+		changes, err := policymanager.ComputeSpecDelta(nil, beforePolicy)
+		if err != nil {
+			return nil, err
+		}
+		for i := range changes {
+			changes[i].PreviousValue, changes[i].Value = changes[i].Value, nil
+		}
+		result = append(result, BehaviorChange{PolicyCrdID: policyCrdID, Changes: changes})
+	}
+	return result, nil
+}
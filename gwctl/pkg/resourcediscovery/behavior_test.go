@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_DiffBehavior(t *testing.T) {
+	retryPolicy := func(name, targetKind, targetName string, retry map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "RetryPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"retry": retry,
+					},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+				},
+			},
+		}
+	}
+	httpRoute := func(name string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		httpRoute("foo-httproute"),
+		httpRoute("bar-httproute"),
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "retrypolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "retrypolicies",
+					Kind:   "RetryPolicy",
+				},
+			},
+		},
+		retryPolicy("retry-policy-gateway", "Gateway", "foo-gateway", map[string]interface{}{
+			"attempts": int64(3),
+			"codes":    []interface{}{int64(503)},
+		}),
+		retryPolicy("retry-policy-httproute", "HTTPRoute", "foo-httproute", map[string]interface{}{
+			"attempts": int64(5),
+		}),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	got, err := resourceModel.DiffBehavior(policymanager.BehaviorRuleNewRetrySemantics)
+	if err != nil {
+		t.Fatalf("DiffBehavior() failed: %v", err)
+	}
+
+	// Only foo-httproute sets its own retry object, which replaces the one of
+	// the Gateway instead of being merged with it.
+	want := []BehaviorChange{
+		{
+			Resource:    common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default", Name: "foo-httproute"},
+			Gateway:     common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "default", Name: "foo-gateway"},
+			PolicyCrdID: "RetryPolicy.foo.com",
+			Changes: []policymanager.FieldChange{
+				{Path: "retry.codes", PreviousValue: []interface{}{float64(503)}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in DiffBehavior(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+
+	// The effective policies of the original ResourceModel are unchanged.
+	httpRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	spec, err := httpRouteNode.EffectivePolicies[GatewayID("default", "foo-gateway")]["RetryPolicy.foo.com"].EffectiveSpec()
+	if err != nil {
+		t.Fatalf("Failed to get EffectiveSpec: %v", err)
+	}
+	if _, ok, _ := unstructured.NestedFieldNoCopy(spec, "retry", "codes"); !ok {
+		t.Errorf("DiffBehavior() modified the effective policies of the ResourceModel; retry.codes is missing from %v", spec)
+	}
+
+	if _, err := resourceModel.DiffBehavior("unknown-rule"); err == nil {
+		t.Errorf("DiffBehavior(\"unknown-rule\") returned no error; want error")
+	}
+}
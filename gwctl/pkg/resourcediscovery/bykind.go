@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// ResourceEffectivePolicy is the effective policy of one kind of a resource.
+type ResourceEffectivePolicy struct {
+	// Resource is the resource the effective policy applies to.
+	Resource common.ObjRef
+	// Gateway is the Gateway through which the effective policy applies. It is
+	// empty for Gateways and for HTTPRoutes attached to Services.
+	Gateway common.ObjRef
+	// Listener is the listener of the Gateway the effective policy applies to,
+	// if policies target the listener through their sectionName. It is empty
+	// if the effective policy applies to all listeners.
+	Listener gatewayv1.SectionName
+	// RuleName is the name of the HTTPRoute rule the effective policy applies
+	// to. It is empty if the effective policy applies to the resource as a
+	// whole.
+	RuleName string
+	// Policy is the effective policy.
+	Policy policymanager.Policy
+}
+
+// EffectivePoliciesOfKind returns the effective policy of the given kind of
+// every resource it applies to, i.e. a view of the effective policies
+// transposed by kind. Resources without an effective policy of the kind are
+// skipped. The result is sorted by resource, Gateway and rule.
+func (rm *ResourceModel) EffectivePoliciesOfKind(policyCrdID policymanager.PolicyCrdID) []ResourceEffectivePolicy {
+	return rm.EffectivePolicies().ForKind(policyCrdID).Collect()
+}
+
+// gatewayEffectivePolicies returns the effective policies of the Gateway, as a
+// whole and for each of its listeners targeted by policies.
+func gatewayEffectivePolicies(gatewayNode *GatewayNode) []ResourceEffectivePolicy {
+	resource := gatewayObjRef(gatewayNode.ID())
+	var result []ResourceEffectivePolicy
+	for _, policy := range gatewayNode.EffectivePolicies {
+		result = append(result, ResourceEffectivePolicy{Resource: resource, Policy: policy})
+	}
+	for sectionName, policies := range gatewayNode.ListenerEffectivePolicies {
+		for _, policy := range policies {
+			result = append(result, ResourceEffectivePolicy{Resource: resource, Listener: sectionName, Policy: policy})
+		}
+	}
+	return result
+}
+
+// httpRouteEffectivePolicies returns the effective policies of the HTTPRoute
+// as a mesh route, and through each Gateway, for the HTTPRoute as a whole, for
+// each of its named rules and for each listener targeted by policies.
+func httpRouteEffectivePolicies(httpRouteNode *HTTPRouteNode) []ResourceEffectivePolicy {
+	id := httpRouteNode.ID()
+	resource := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: id.Namespace, Name: id.Name}
+	var result []ResourceEffectivePolicy
+	for _, policy := range httpRouteNode.MeshEffectivePolicies {
+		result = append(result, ResourceEffectivePolicy{Resource: resource, Policy: policy})
+	}
+	for _, gatewayID := range sortedGatewayIDs(httpRouteNode.EffectivePolicies) {
+		for _, policy := range httpRouteNode.EffectivePolicies[gatewayID] {
+			result = append(result, ResourceEffectivePolicy{Resource: resource, Gateway: gatewayObjRef(gatewayID), Policy: policy})
+		}
+		for ruleName, policies := range httpRouteNode.RuleEffectivePolicies[gatewayID] {
+			for _, policy := range policies {
+				result = append(result, ResourceEffectivePolicy{Resource: resource, Gateway: gatewayObjRef(gatewayID), RuleName: ruleName, Policy: policy})
+			}
+		}
+		for sectionName, policies := range httpRouteNode.ListenerEffectivePolicies[gatewayID] {
+			for _, policy := range policies {
+				result = append(result, ResourceEffectivePolicy{Resource: resource, Gateway: gatewayObjRef(gatewayID), Listener: sectionName, Policy: policy})
+			}
+		}
+	}
+	return result
+}
+
+// backendEffectivePolicies returns the effective policies of the Backend
+// through each Gateway, and through each listener targeted by policies.
+func backendEffectivePolicies(backendNode *BackendNode) []ResourceEffectivePolicy {
+	id := backendNode.ID()
+	resource := common.ObjRef{Group: id.Group, Kind: backendNode.Backend.GetKind(), Namespace: id.Namespace, Name: id.Name}
+	var result []ResourceEffectivePolicy
+	for _, gatewayID := range sortedGatewayIDs(backendNode.EffectivePolicies) {
+		for _, policy := range backendNode.EffectivePolicies[gatewayID] {
+			result = append(result, ResourceEffectivePolicy{Resource: resource, Gateway: gatewayObjRef(gatewayID), Policy: policy})
+		}
+		for sectionName, policies := range backendNode.ListenerEffectivePolicies[gatewayID] {
+			for _, policy := range policies {
+				result = append(result, ResourceEffectivePolicy{Resource: resource, Gateway: gatewayObjRef(gatewayID), Listener: sectionName, Policy: policy})
+			}
+		}
+	}
+	return result
+}
+
+// sortResourceEffectivePolicies sorts the effective policies by resource,
+// Gateway, listener, rule and kind of policy.
+func sortResourceEffectivePolicies(effectivePolicies []ResourceEffectivePolicy) {
+	key := func(e ResourceEffectivePolicy) string {
+		return fmt.Sprintf("%v/%v/%v/%v/%v/%v/%v/%v", e.Resource.Kind, e.Resource.Namespace, e.Resource.Name, e.Gateway.Namespace, e.Gateway.Name, e.Listener, e.RuleName, e.Policy.PolicyCrdID())
+	}
+	sort.Slice(effectivePolicies, func(i, j int) bool {
+		return key(effectivePolicies[i]) < key(effectivePolicies[j])
+	})
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_EffectivePoliciesOfKind(t *testing.T) {
+	timeoutPolicy := func(name, targetKind, targetName string, timeout int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": timeout,
+					},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+				},
+			},
+		}
+	}
+	httpRoute := func(name, backend string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: gatewayv1.ObjectName(backend),
+							Port: common.PtrTo(gatewayv1.PortNumber(80)),
+						},
+					}}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		httpRoute("foo-httproute", "foo-svc"),
+		httpRoute("bar-httproute", "bar-svc"),
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "default"}},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		timeoutPolicy("timeout-policy-gateway", "Gateway", "foo-gateway", 30),
+		timeoutPolicy("timeout-policy-httproute", "HTTPRoute", "foo-httproute", 60),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	type row struct {
+		Resource common.ObjRef
+		Gateway  common.ObjRef
+		Timeout  interface{}
+	}
+	var got []row
+	for _, effectivePolicy := range resourceModel.EffectivePoliciesOfKind("TimeoutPolicy.foo.com") {
+		spec, err := effectivePolicy.Policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		got = append(got, row{Resource: effectivePolicy.Resource, Gateway: effectivePolicy.Gateway, Timeout: spec["timeout"]})
+	}
+
+	// All resources affected by the policies of the kind appear, each with the
+	// effective timeout it inherits or overrides.
+	gateway := common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "default", Name: "foo-gateway"}
+	want := []row{
+		{Resource: gateway, Timeout: float64(30)},
+		{Resource: common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default", Name: "bar-httproute"}, Gateway: gateway, Timeout: float64(30)},
+		{Resource: common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default", Name: "foo-httproute"}, Gateway: gateway, Timeout: float64(60)},
+		{Resource: common.ObjRef{Kind: "Service", Namespace: "default", Name: "bar-svc"}, Gateway: gateway, Timeout: float64(30)},
+		{Resource: common.ObjRef{Kind: "Service", Namespace: "default", Name: "foo-svc"}, Gateway: gateway, Timeout: float64(60)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in EffectivePoliciesOfKind(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+
+	if got := resourceModel.EffectivePoliciesOfKind("UnknownPolicy.foo.com"); len(got) != 0 {
+		t.Errorf("EffectivePoliciesOfKind(\"UnknownPolicy.foo.com\")=%v; want empty", got)
+	}
+}
+
+func TestResourceModel_EffectivePoliciesOfKind_Listeners(t *testing.T) {
+	timeoutPolicy := func(name, sectionName string, timeout int64) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "Gateway",
+			"name":  "foo-gateway",
+		}
+		if sectionName != "" {
+			targetRef["sectionName"] = sectionName
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": timeout,
+					},
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: "foo-svc",
+							Port: common.PtrTo(gatewayv1.PortNumber(80)),
+						},
+					}}},
+				}},
+			},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"}},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		timeoutPolicy("timeout-policy-gateway", "", 30),
+		timeoutPolicy("timeout-policy-https", "https", 10),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	type row struct {
+		Resource common.ObjRef
+		Gateway  common.ObjRef
+		Listener gatewayv1.SectionName
+		Timeout  interface{}
+	}
+	var got []row
+	for _, effectivePolicy := range resourceModel.EffectivePoliciesOfKind("TimeoutPolicy.foo.com") {
+		spec, err := effectivePolicy.Policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		got = append(got, row{Resource: effectivePolicy.Resource, Gateway: effectivePolicy.Gateway, Listener: effectivePolicy.Listener, Timeout: spec["timeout"]})
+	}
+
+	// The policy targeting the https listener results in rows of their own for
+	// the listener, on the Gateway and on the resources inheriting from it.
+	gateway := common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "default", Name: "foo-gateway"}
+	httpRoute := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default", Name: "foo-httproute"}
+	service := common.ObjRef{Kind: "Service", Namespace: "default", Name: "foo-svc"}
+	want := []row{
+		{Resource: gateway, Timeout: float64(30)},
+		{Resource: gateway, Listener: "https", Timeout: float64(10)},
+		{Resource: httpRoute, Gateway: gateway, Timeout: float64(30)},
+		{Resource: httpRoute, Gateway: gateway, Listener: "https", Timeout: float64(10)},
+		{Resource: service, Gateway: gateway, Timeout: float64(30)},
+		{Resource: service, Gateway: gateway, Listener: "https", Timeout: float64(10)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in EffectivePoliciesOfKind(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
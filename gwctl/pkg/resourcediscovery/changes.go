@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// latestChange returns the latest of the creation timestamp and of the
+// lastTransitionTime of the conditions.
+func latestChange(created metav1.Time, conditions ...[]metav1.Condition) time.Time {
+	result := created.Time
+	for _, list := range conditions {
+		for _, condition := range list {
+			if condition.LastTransitionTime.After(result) {
+				result = condition.LastTransitionTime.Time
+			}
+		}
+	}
+	return result
+}
+
+// LastChanged returns the time the GatewayClass was created or any of its
+// conditions last transitioned, whichever is later.
+func (g *GatewayClassNode) LastChanged() time.Time {
+	return latestChange(g.GatewayClass.GetCreationTimestamp(), g.GatewayClass.Status.Conditions)
+}
+
+// LastChanged returns the time the Gateway was created or any of its
+// conditions, including those of its listeners, last transitioned, whichever
+// is later.
+func (g *GatewayNode) LastChanged() time.Time {
+	conditions := [][]metav1.Condition{g.Gateway.Status.Conditions}
+	for _, listener := range g.Gateway.Status.Listeners {
+		conditions = append(conditions, listener.Conditions)
+	}
+	return latestChange(g.Gateway.GetCreationTimestamp(), conditions...)
+}
+
+// LastChanged returns the time the HTTPRoute was created or any of the
+// conditions of its parents last transitioned, whichever is later.
+func (h *HTTPRouteNode) LastChanged() time.Time {
+	var conditions [][]metav1.Condition
+	for _, parent := range h.HTTPRoute.Status.Parents {
+		conditions = append(conditions, parent.Conditions)
+	}
+	return latestChange(h.HTTPRoute.GetCreationTimestamp(), conditions...)
+}
+
+// LastChanged returns the time the backend was created or any of the
+// conditions in its status last transitioned, whichever is later. Backends
+// like Services commonly lack conditions, in which case this is the creation
+// time.
+func (b *BackendNode) LastChanged() time.Time {
+	result := b.Backend.GetCreationTimestamp().Time
+	conditions, _, _ := unstructured.NestedSlice(b.Backend.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, _, _ := unstructured.NestedString(c, "lastTransitionTime")
+		lastTransitionTime, err := time.Parse(time.RFC3339, value)
+		if err == nil && lastTransitionTime.After(result) {
+			result = lastTransitionTime
+		}
+	}
+	return result
+}
+
+// LastChanged returns the time the Namespace was created.
+func (n *NamespaceNode) LastChanged() time.Time {
+	if n.Namespace == nil {
+		return time.Time{}
+	}
+	return n.Namespace.GetCreationTimestamp().Time
+}
+
+// FilterChangedSince removes the GatewayClasses, Namespaces, Gateways,
+// HTTPRoutes and Backends which last changed before since from the
+// ResourceModel, such that only recently changed resources are reported.
+// Relations of the remaining nodes are kept, so references to resources which
+// changed earlier still resolve.
+func (rm *ResourceModel) FilterChangedSince(since time.Time) {
+	for id, node := range rm.GatewayClasses {
+		if node.LastChanged().Before(since) {
+			delete(rm.GatewayClasses, id)
+		}
+	}
+	for id, node := range rm.Namespaces {
+		if node.LastChanged().Before(since) {
+			delete(rm.Namespaces, id)
+		}
+	}
+	for id, node := range rm.Gateways {
+		if node.LastChanged().Before(since) {
+			delete(rm.Gateways, id)
+		}
+	}
+	for id, node := range rm.HTTPRoutes {
+		if node.LastChanged().Before(since) {
+			delete(rm.HTTPRoutes, id)
+		}
+	}
+	for id, node := range rm.Backends {
+		if node.LastChanged().Before(since) {
+			delete(rm.Backends, id)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_FilterChangedSince(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	daysAgo := metav1.NewTime(now.Add(-48 * time.Hour))
+	minutesAgo := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	httpRoute := func(name string, created metav1.Time) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: created,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "old-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: "old-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo-gatewayclass",
+				CreationTimestamp: daysAgo,
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "old-gateway",
+				Namespace:         "default",
+				CreationTimestamp: daysAgo,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+			Status: gatewayv1.GatewayStatus{
+				Conditions: []metav1.Condition{{
+					Type:               string(gatewayv1.GatewayConditionProgrammed),
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: daysAgo,
+				}},
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "transitioned-gateway",
+				Namespace:         "default",
+				CreationTimestamp: daysAgo,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+			Status: gatewayv1.GatewayStatus{
+				Listeners: []gatewayv1.ListenerStatus{{
+					Name: "http",
+					Conditions: []metav1.Condition{{
+						Type:               string(gatewayv1.ListenerConditionResolvedRefs),
+						Status:             metav1.ConditionFalse,
+						LastTransitionTime: minutesAgo,
+					}},
+				}},
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "new-gateway",
+				Namespace:         "default",
+				CreationTimestamp: minutesAgo,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		httpRoute("old-httproute", daysAgo),
+		httpRoute("new-httproute", minutesAgo),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "old-svc",
+				Namespace:         "default",
+				CreationTimestamp: daysAgo,
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	resourceModel.FilterChangedSince(now.Add(-time.Hour))
+
+	var gotNodes []string
+	for nodeID := range resourceModel.Nodes() {
+		gotNodes = append(gotNodes, nodeID)
+	}
+	sort.Strings(gotNodes)
+	wantNodes := []string{
+		"Gateway/default/new-gateway",
+		"Gateway/default/transitioned-gateway",
+		"HTTPRoute/default/new-httproute",
+	}
+	if diff := cmp.Diff(wantNodes, gotNodes); diff != "" {
+		t.Errorf("Unexpected diff in nodes after FilterChangedSince; diff (-want +got)=\n%v", diff)
+	}
+
+	// The recently created HTTPRoute still references the Gateway and Service
+	// which changed earlier.
+	httpRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "new-httproute")]
+	if _, ok := httpRouteNode.Gateways[GatewayID("default", "old-gateway")]; !ok {
+		t.Errorf("HTTPRoute default/new-httproute lost its reference to Gateway default/old-gateway")
+	}
+	if _, ok := httpRouteNode.Backends[BackendIDForService("default", "old-svc")]; !ok {
+		t.Errorf("HTTPRoute default/new-httproute lost its reference to Service default/old-svc")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// DefaultBackendRef is a backend which receives the requests to a Gateway
+// which are not matched by any of its routes. Newer drafts of the Gateway API
+// allow default backends for the whole Gateway through spec.backendRefs, and
+// for a single listener through spec.listeners[].backendRefs. These fields are
+// not part of the structured Gateway type, so they are extracted from the
+// unstructured Gateway, and only if present.
+type DefaultBackendRef struct {
+	// Listener is the name of the listener which declares the default
+	// backend. It is empty for default backends of the whole Gateway.
+	Listener string
+	// BackendRef is the referenced backend, with its namespace and kind
+	// defaulted.
+	BackendRef common.ObjRef
+}
+
+func (r DefaultBackendRef) String() string {
+	if r.Listener == "" {
+		return BackendRefString(r.BackendRef)
+	}
+	return fmt.Sprintf("%v (listener %v)", BackendRefString(r.BackendRef), r.Listener)
+}
+
+// gatewayDefaultBackendRefs returns the default backends declared by the
+// unstructured Gateway. It returns nil for Gateways of API versions without
+// default backends.
+func gatewayDefaultBackendRefs(gateway *unstructured.Unstructured) []DefaultBackendRef {
+	var result []DefaultBackendRef
+	backendRefs, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "backendRefs")
+	result = append(result, defaultBackendRefs("", backendRefs, gateway.GetNamespace())...)
+
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	for _, listener := range listeners {
+		listenerMap, ok := listener.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(listenerMap, "name")
+		backendRefs, _, _ := unstructured.NestedSlice(listenerMap, "backendRefs")
+		result = append(result, defaultBackendRefs(name, backendRefs, gateway.GetNamespace())...)
+	}
+	return result
+}
+
+func defaultBackendRefs(listener string, backendRefs []interface{}, gatewayNamespace string) []DefaultBackendRef {
+	var result []DefaultBackendRef
+	for _, backendRef := range backendRefs {
+		backendRefMap, ok := backendRef.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result = append(result, DefaultBackendRef{
+			Listener:   listener,
+			BackendRef: backendRefObjRef(backendRefMap, gatewayNamespace),
+		})
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+// gatewayWithDefaultBackends is a Gateway of a newer API version which
+// declares a default backend for its https listener and one for the whole
+// Gateway. The fake clients store Gateways with the structured type, which
+// drops these fields, so the Gateway is added to the resourceModel directly.
+func gatewayWithDefaultBackends() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "Gateway",
+			"metadata": map[string]interface{}{
+				"name":      "foo-gateway",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"gatewayClassName": "foo-gatewayclass",
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": "missing-svc", "port": int64(80)},
+				},
+				"listeners": []interface{}{
+					map[string]interface{}{
+						"name":     "http",
+						"port":     int64(80),
+						"protocol": "HTTP",
+					},
+					map[string]interface{}{
+						"name":     "https",
+						"port":     int64(443),
+						"protocol": "HTTPS",
+						"backendRefs": []interface{}{
+							map[string]interface{}{"name": "fallback-svc", "namespace": "fallback", "port": int64(8080)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewFetchedGateway_DefaultBackends(t *testing.T) {
+	gateway, err := newFetchedGateway(gatewayWithDefaultBackends())
+	if err != nil {
+		t.Fatalf("newFetchedGateway() failed: %v", err)
+	}
+	want := []DefaultBackendRef{
+		{BackendRef: common.ObjRef{Kind: "Service", Name: "missing-svc", Namespace: "default"}},
+		{Listener: "https", BackendRef: common.ObjRef{Kind: "Service", Name: "fallback-svc", Namespace: "fallback"}},
+	}
+	if diff := cmp.Diff(want, gateway.defaultBackendRefs); diff != "" {
+		t.Errorf("Unexpected diff in defaultBackendRefs; got=%v, want=%v;\ndiff (-want +got)=\n%v", gateway.defaultBackendRefs, want, diff)
+	}
+	for i, want := range []string{
+		"Service default/missing-svc",
+		"Service fallback/fallback-svc (listener https)",
+	} {
+		if got := gateway.defaultBackendRefs[i].String(); got != want {
+			t.Errorf("defaultBackendRefs[%d].String()=%q; want %q", i, got, want)
+		}
+	}
+
+	// Gateways of API versions without default backends have none.
+	withoutDefaultBackends := gatewayWithDefaultBackends()
+	unstructured.RemoveNestedField(withoutDefaultBackends.Object, "spec", "backendRefs")
+	_ = unstructured.SetNestedSlice(withoutDefaultBackends.Object, []interface{}{
+		map[string]interface{}{"name": "http", "port": int64(80), "protocol": "HTTP"},
+	}, "spec", "listeners")
+	gateway, err = newFetchedGateway(withoutDefaultBackends)
+	if err != nil {
+		t.Fatalf("newFetchedGateway() failed: %v", err)
+	}
+	if gateway.defaultBackendRefs != nil {
+		t.Errorf("defaultBackendRefs=%v; want nil", gateway.defaultBackendRefs)
+	}
+}
+
+func TestDiscoverDefaultBackendsFromGateways(t *testing.T) {
+	gateway, err := newFetchedGateway(gatewayWithDefaultBackends())
+	if err != nil {
+		t.Fatalf("newFetchedGateway() failed: %v", err)
+	}
+
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fallback"}},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fallback-svc",
+				Namespace: "fallback",
+			},
+		},
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-default-gateways",
+				Namespace: "fallback",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "Gateway",
+					Namespace: "default",
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+			},
+		},
+	}
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel := &ResourceModel{}
+	resourceModel.addGateways(gateway)
+	discoverer.discoverDefaultBackendsFromGateways(context.Background(), resourceModel)
+	discoverer.discoverNamespaces(context.Background(), resourceModel)
+
+	gatewayID := GatewayID("default", "foo-gateway")
+	backendID := BackendIDForService("fallback", "fallback-svc")
+	gatewayNode := resourceModel.Gateways[gatewayID]
+	if _, ok := gatewayNode.DefaultBackends[backendID]; !ok || len(gatewayNode.DefaultBackends) != 1 {
+		t.Errorf("DefaultBackends=%v; want only %v", gatewayNode.DefaultBackends, backendID)
+	}
+	if _, ok := resourceModel.Backends[backendID].DefaultBackendOf[gatewayID]; !ok {
+		t.Errorf("DefaultBackendOf of Backend %v does not contain %v", backendID, gatewayID)
+	}
+
+	wantErrors := []error{
+		ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+			ReferringObject: common.ObjRef{Kind: "Gateway", Name: "foo-gateway", Namespace: "default"},
+			ReferredObject:  common.ObjRef{Kind: "Service", Name: "missing-svc", Namespace: "default"},
+		}},
+	}
+	if diff := cmp.Diff(wantErrors, gatewayNode.Errors, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", gatewayNode.Errors, wantErrors, diff)
+	}
+
+	// Default backends are preserved by Clone.
+	clone := resourceModel.Clone()
+	if got := clone.Gateways[gatewayID].DefaultBackendRefs; !cmp.Equal(got, gatewayNode.DefaultBackendRefs) {
+		t.Errorf("DefaultBackendRefs of clone=%v; want %v", got, gatewayNode.DefaultBackendRefs)
+	}
+	if _, ok := clone.Gateways[gatewayID].DefaultBackends[backendID]; !ok {
+		t.Errorf("DefaultBackends of clone=%v; want %v", clone.Gateways[gatewayID].DefaultBackends, backendID)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// InheritingEffectivePolicies returns the effective policies, of the kind of
+// the policy, of all resources which can inherit the policy: the resources it
+// is attached to and all their descendants, e.g. the Gateways of a
+// GatewayClass, the HTTPRoutes attached to these Gateways and their Backends.
+// This includes the effective policies of each listener and named rule.
+// Resources without an effective policy of the kind are skipped.
+func (p *PolicyNode) InheritingEffectivePolicies() []policymanager.Policy {
+	policyCrdID := p.Policy.PolicyCrdID()
+	var result []policymanager.Policy
+	add := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) {
+		if policy, ok := policies[policyCrdID]; ok {
+			result = append(result, policy)
+		}
+	}
+
+	addHTTPRoute := func(httpRouteNode *HTTPRouteNode, gatewayID gatewayID) {
+		add(httpRouteNode.EffectivePolicies[gatewayID])
+		for _, policies := range httpRouteNode.RuleEffectivePolicies[gatewayID] {
+			add(policies)
+		}
+		for _, policies := range httpRouteNode.ListenerEffectivePolicies[gatewayID] {
+			add(policies)
+		}
+		for _, backendNode := range httpRouteNode.Backends {
+			add(backendNode.EffectivePolicies[gatewayID])
+			for _, policies := range backendNode.ListenerEffectivePolicies[gatewayID] {
+				add(policies)
+			}
+		}
+	}
+	addGateway := func(gatewayNode *GatewayNode) {
+		gatewayID := gatewayNode.ID()
+		add(gatewayNode.EffectivePolicies)
+		for _, policies := range gatewayNode.ListenerEffectivePolicies {
+			add(policies)
+		}
+		for _, httpRouteNode := range gatewayNode.HTTPRoutes {
+			addHTTPRoute(httpRouteNode, gatewayID)
+		}
+		for _, backendNode := range gatewayNode.DefaultBackends {
+			add(backendNode.EffectivePolicies[gatewayID])
+		}
+	}
+	addHTTPRouteForAllGateways := func(httpRouteNode *HTTPRouteNode) {
+		for gatewayID := range httpRouteNode.Gateways {
+			addHTTPRoute(httpRouteNode, gatewayID)
+		}
+		add(httpRouteNode.MeshEffectivePolicies)
+	}
+	addBackend := func(backendNode *BackendNode) {
+		for _, policies := range backendNode.EffectivePolicies {
+			add(policies)
+		}
+		for _, policiesByListener := range backendNode.ListenerEffectivePolicies {
+			for _, policies := range policiesByListener {
+				add(policies)
+			}
+		}
+	}
+	addGatewayClass := func(gatewayClassNode *GatewayClassNode) {
+		for _, gatewayNode := range gatewayClassNode.Gateways {
+			addGateway(gatewayNode)
+		}
+	}
+	addNamespace := func(namespaceNode *NamespaceNode) {
+		for _, gatewayNode := range namespaceNode.Gateways {
+			addGateway(gatewayNode)
+		}
+		for _, httpRouteNode := range namespaceNode.HTTPRoutes {
+			addHTTPRouteForAllGateways(httpRouteNode)
+		}
+		for _, backendNode := range namespaceNode.Backends {
+			addBackend(backendNode)
+		}
+	}
+
+	if p.GatewayClass != nil {
+		addGatewayClass(p.GatewayClass)
+	}
+	for _, gatewayClassNode := range p.SelectedGatewayClasses {
+		addGatewayClass(gatewayClassNode)
+	}
+	if p.Namespace != nil {
+		addNamespace(p.Namespace)
+	}
+	for _, namespaceNode := range p.SelectedNamespaces {
+		addNamespace(namespaceNode)
+	}
+	if p.Gateway != nil {
+		addGateway(p.Gateway)
+	}
+	for _, gatewayNode := range p.SelectedGateways {
+		addGateway(gatewayNode)
+	}
+	if p.HTTPRoute != nil {
+		addHTTPRouteForAllGateways(p.HTTPRoute)
+	}
+	for _, httpRouteNode := range p.SelectedHTTPRoutes {
+		addHTTPRouteForAllGateways(httpRouteNode)
+	}
+	if p.Backend != nil {
+		addBackend(p.Backend)
+	}
+	for _, backendNode := range p.SelectedBackends {
+		addBackend(backendNode)
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// UpdatePolicy replaces the content of a policy which is already part of the
+// ResourceModel, e.g. after it was changed in the cluster, and recalculates
+// the effective policies affected by it. Only the resources the policy targets
+// and the resources inheriting from them are recalculated: a Gateway, the
+// HTTPRoutes attached to it and their Backends for a Gateway policy, or an
+// HTTPRoute and its Backends for an HTTPRoute policy.
+//
+// The policy must target the same resources as before, since the edges of the
+// ResourceModel are not rebuilt. Errors recorded on the resources while
+// validating their effective policies are not updated.
+func (rm *ResourceModel) UpdatePolicy(policy policymanager.Policy) error {
+	id := PolicyID(policy.Unstructured().GroupVersionKind().Group, policy.Unstructured().GetKind(), policy.Unstructured().GetNamespace(), policy.Unstructured().GetName())
+	policyNode, ok := rm.Policies[id]
+	if !ok {
+		return fmt.Errorf("policy %v is not part of the ResourceModel", policy.Name())
+	}
+	if policy.TargetRef() != policyNode.Policy.TargetRef() || policy.SectionName() != policyNode.Policy.SectionName() || !reflect.DeepEqual(policy.TargetSelector(), policyNode.Policy.TargetSelector()) {
+		return fmt.Errorf("targets of policy %v changed, resources need to be discovered again", policy.Name())
+	}
+
+	updated := policy.DeepCopy()
+	*policyNode.Policy = updated
+
+	rm.markDirtyForPolicy(policyNode)
+	return rm.calculateEffectivePolicies()
+}
+
+// markDirtyForPolicy marks the resources whose effective policies depend on
+// the policy as dirty, so that the next calculateEffectivePolicies only
+// recalculates those.
+func (rm *ResourceModel) markDirtyForPolicy(policyNode *PolicyNode) {
+	if rm.dirty == nil {
+		rm.dirty = make(map[string]bool)
+	}
+
+	var gatewayClassNodes []*GatewayClassNode
+	if policyNode.GatewayClass != nil {
+		gatewayClassNodes = append(gatewayClassNodes, policyNode.GatewayClass)
+	}
+	for _, gatewayClassNode := range policyNode.SelectedGatewayClasses {
+		gatewayClassNodes = append(gatewayClassNodes, gatewayClassNode)
+	}
+	for _, gatewayClassNode := range gatewayClassNodes {
+		for _, gatewayNode := range gatewayClassNode.Gateways {
+			rm.markGatewayDirty(gatewayNode)
+		}
+	}
+
+	var namespaceNodes []*NamespaceNode
+	if policyNode.Namespace != nil {
+		namespaceNodes = append(namespaceNodes, policyNode.Namespace)
+	}
+	for _, namespaceNode := range policyNode.SelectedNamespaces {
+		namespaceNodes = append(namespaceNodes, namespaceNode)
+	}
+	for _, namespaceNode := range namespaceNodes {
+		for _, gatewayNode := range namespaceNode.Gateways {
+			rm.markGatewayDirty(gatewayNode)
+		}
+		for _, httpRouteNode := range namespaceNode.HTTPRoutes {
+			rm.markHTTPRouteDirty(httpRouteNode)
+		}
+		for _, backendNode := range namespaceNode.Backends {
+			rm.dirty[backendNode.NodeID()] = true
+		}
+	}
+
+	if policyNode.Gateway != nil {
+		rm.markGatewayDirty(policyNode.Gateway)
+	}
+	for _, gatewayNode := range policyNode.SelectedGateways {
+		rm.markGatewayDirty(gatewayNode)
+	}
+	if policyNode.HTTPRoute != nil {
+		rm.markHTTPRouteDirty(policyNode.HTTPRoute)
+	}
+	for _, httpRouteNode := range policyNode.SelectedHTTPRoutes {
+		rm.markHTTPRouteDirty(httpRouteNode)
+	}
+	if policyNode.Backend != nil {
+		rm.dirty[policyNode.Backend.NodeID()] = true
+	}
+	for _, backendNode := range policyNode.SelectedBackends {
+		rm.dirty[backendNode.NodeID()] = true
+	}
+}
+
+// markGatewayDirty marks the Gateway, the HTTPRoutes attached to it, their
+// Backends and the default backends of the Gateway as dirty.
+func (rm *ResourceModel) markGatewayDirty(gatewayNode *GatewayNode) {
+	rm.dirty[gatewayNode.NodeID()] = true
+	for _, httpRouteNode := range gatewayNode.HTTPRoutes {
+		rm.markHTTPRouteDirty(httpRouteNode)
+	}
+	for _, backendNode := range gatewayNode.DefaultBackends {
+		rm.dirty[backendNode.NodeID()] = true
+	}
+}
+
+// markHTTPRouteDirty marks the HTTPRoute and its Backends as dirty.
+func (rm *ResourceModel) markHTTPRouteDirty(httpRouteNode *HTTPRouteNode) {
+	rm.dirty[httpRouteNode.NodeID()] = true
+	for _, backendNode := range httpRouteNode.Backends {
+		rm.dirty[backendNode.NodeID()] = true
+	}
+}
+
+// needsRecalculation returns true if the effective policies of the node have
+// to be recalculated, i.e. if no node was marked dirty, or if this node was.
+func (rm *ResourceModel) needsRecalculation(node Node) bool {
+	return rm.dirty == nil || rm.dirty[node.NodeID()]
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_UpdatePolicy(t *testing.T) {
+	httpRoute := func(name, gatewayName, serviceName string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayName)}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: gatewayv1.ObjectName(serviceName),
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+	policy := func(name, targetKind, targetName string, seconds int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{"seconds": seconds},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		httpRoute("foo-httproute", "foo-gateway", "foo-svc"),
+		httpRoute("bar-httproute", "bar-gateway", "bar-svc"),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		policy("timeout-foo-httproute", "HTTPRoute", "foo-httproute", 30),
+		policy("timeout-bar-gateway", "Gateway", "bar-gateway", 60),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+		Audit:         true,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	policyNode, ok := resourceModel.Policies[PolicyID("foo.com", "TimeoutPolicy", "default", "timeout-foo-httproute")]
+	if !ok {
+		t.Fatalf("Policy default/timeout-foo-httproute not found in resourceModel")
+	}
+	updated := policyNode.Policy.DeepCopy()
+	if err := unstructured.SetNestedField(updated.Unstructured().Object, int64(10), "spec", "default", "seconds"); err != nil {
+		t.Fatalf("Failed to update policy: %v", err)
+	}
+
+	auditLogLength := len(resourceModel.AuditLog())
+	if err := resourceModel.UpdatePolicy(updated); err != nil {
+		t.Fatalf("UpdatePolicy() returned err=%v", err)
+	}
+
+	// Only the HTTPRoute targeted by the policy and its Backend are
+	// recalculated.
+	var gotResolved []string
+	for _, event := range resourceModel.AuditLog()[auditLogLength:] {
+		if event.Action == AuditActionResolve {
+			gotResolved = append(gotResolved, event.Subject)
+		}
+	}
+	sort.Strings(gotResolved)
+	wantResolved := []string{
+		describeID(BackendIDForService("default", "foo-svc")),
+		describeID(HTTPRouteID("default", "foo-httproute")),
+	}
+	if diff := cmp.Diff(wantResolved, gotResolved); diff != "" {
+		t.Errorf("Unexpected diff in recalculated resources; diff (-want +got)=\n%v", diff)
+	}
+
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	gatewayID := GatewayID("default", "foo-gateway")
+	backendPolicies := resourceModel.Backends[BackendIDForService("default", "foo-svc")].EffectivePolicies[gatewayID]
+	got, err := backendPolicies["TimeoutPolicy.foo.com"].EffectiveSpec()
+	if err != nil {
+		t.Fatalf("Failed to get EffectiveSpec: %v", err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"seconds": float64(10)}, got); diff != "" {
+		t.Errorf("Unexpected diff in effective policy of the Backend; diff (-want +got)=\n%v", diff)
+	}
+
+	// Changing the targets of the policy requires discovering the resources
+	// again.
+	u := updated.DeepCopy().Unstructured()
+	if err := unstructured.SetNestedField(u.Object, "bar-httproute", "spec", "targetRef", "name"); err != nil {
+		t.Fatalf("Failed to update policy: %v", err)
+	}
+	policyCRDs := make(map[policymanager.PolicyCrdID]policymanager.PolicyCRD)
+	for _, policyCRD := range params.PolicyManager.GetCRDs() {
+		policyCRDs[policyCRD.ID()] = policyCRD
+	}
+	retargeted, err := policymanager.PolicyFromUnstructured(*u, policyCRDs)
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if err := resourceModel.UpdatePolicy(retargeted); err == nil {
+		t.Errorf("UpdatePolicy() with changed targetRef returned err=nil; want error")
+	}
+}
@@ -19,7 +19,9 @@ package resourcediscovery
 import (
 	"context"
 	"fmt"
-	"os"
+	"path"
+	"slices"
+	"sort"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -30,6 +32,8 @@ import (
 	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -38,6 +42,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 )
 
@@ -45,8 +51,22 @@ const (
 	// Maximum number of events to be fetched for each resource when constructing
 	// the resourceModel.
 	maxEventsPerResource = 10
+
+	// Maximum number of resources fetched in a single List call. Larger result
+	// sets are fetched over multiple pages.
+	listPageSize = 500
 )
 
+// ProgressFunc is called by the Discoverer after each page of resources of a
+// particular kind has been fetched. fetched is the number of resources fetched
+// so far and total is the estimated total number of resources, or -1 if the
+// total is not known.
+type ProgressFunc func(kind string, fetched, total int)
+
+// WarnFunc is called by the Discoverer when resources of a particular kind are
+// skipped, because they could not be fetched due to missing permissions.
+type WarnFunc func(kind string, err error)
+
 var (
 	defaultGatewayClassGroupVersion   = gatewayv1.GroupVersion
 	defaultGatewayGroupVersion        = gatewayv1.GroupVersion
@@ -54,6 +74,34 @@ var (
 	defaultReferenceGrantGroupVersion = gatewayv1beta1.GroupVersion
 )
 
+// DefaultNamespaceIgnoreList contains the namespaces which are ignored by
+// default. These usually contain infrastructure components which clutter the
+// output and analysis.
+var DefaultNamespaceIgnoreList = NamespaceIgnoreList{"kube-*"}
+
+// NamespaceIgnoreList is a list of patterns matching names of namespaces to be
+// ignored. Patterns use the syntax supported by path.Match, e.g. "kube-*".
+//
+// Resources within ignored namespaces are not discovered as the primary
+// resources when discovering across all namespaces. They are however still
+// discovered when referenced by other resources, so that references can be
+// resolved correctly.
+type NamespaceIgnoreList []string
+
+// Ignores returns true if the namespace matches any pattern in the list. The
+// empty namespace of cluster scoped resources is never ignored.
+func (l NamespaceIgnoreList) Ignores(namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	for _, pattern := range l {
+		if matched, err := path.Match(pattern, namespace); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // Filter struct defines parameters for filtering resources
 type Filter struct {
 	Namespace string
@@ -80,6 +128,35 @@ type Discoverer struct {
 	PreferredGatewayGroupVersion        metav1.GroupVersion
 	PreferredHTTPRouteGroupVersion      metav1.GroupVersion
 	PreferredReferenceGrantGroupVersion metav1.GroupVersion
+
+	// Progress, if set, is used to report progress while fetching resources.
+	Progress ProgressFunc
+	// RateLimiter, if set, is used to limit the rate of List calls made while
+	// fetching resources.
+	RateLimiter flowcontrol.RateLimiter
+	// IgnoredNamespaces lists namespaces whose resources are skipped when
+	// discovering resources across all namespaces. Namespaces explicitly
+	// requested through the Filter are never ignored.
+	IgnoredNamespaces NamespaceIgnoreList
+	// RequireParentReferenceGrants, if set, only allows HTTPRoutes to attach to
+	// Gateways in other namespaces when a ReferenceGrant in the namespace of the
+	// Gateway permits it. Attachments which are not permitted are recorded as
+	// errors of the HTTPRoute.
+	RequireParentReferenceGrants bool
+	// SkipForbidden, if set, skips the kinds of resources which can not be
+	// fetched due to missing permissions, instead of failing. The resulting
+	// ResourceModel is partial, and records the skipped kinds in SkippedKinds.
+	SkipForbidden bool
+	// SkipDeletingPolicies, if set, excludes policies which are being deleted,
+	// i.e. which have a deletionTimestamp but still exist due to finalizers,
+	// from the ResourceModel, so that they do not contribute to effective
+	// policies. Skipped policies are recorded in DeletingPolicies.
+	SkipDeletingPolicies bool
+	// Audit, if set, records every decision taken while building the
+	// ResourceModel, like adding a node or skipping an edge, in its AuditLog.
+	Audit bool
+	// Warn, if set, is called once for every kind skipped within a discovery.
+	Warn WarnFunc
 }
 
 func NewDiscoverer(k8sClients *common.K8sClients, policyManager *policymanager.PolicyManager) Discoverer {
@@ -131,109 +208,302 @@ func (d *Discoverer) initPreferredResourceVersions() error {
 
 // DiscoverResourcesForGatewayClass discovers resources related to a
 // GatewayClass.
-func (d Discoverer) DiscoverResourcesForGatewayClass(filter Filter) (*ResourceModel, error) {
-	ctx := context.Background()
-	resourceModel := &ResourceModel{}
+func (d Discoverer) DiscoverResourcesForGatewayClass(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	resourceModel := &ResourceModel{auditEnabled: d.Audit}
 
 	gatewayClasses, err := d.fetchGatewayClasses(ctx, filter)
-	if err != nil {
+	if err != nil && !d.skipForbidden(resourceModel, "GatewayClasses", err) {
 		return resourceModel, err
 	}
 	resourceModel.addGatewayClasses(gatewayClasses...)
 
+	d.discoverParametersForGatewayClasses(ctx, resourceModel)
 	d.discoverPolicies(resourceModel)
 
-	return resourceModel, nil
+	return resourceModel, ctx.Err()
 }
 
 // DiscoverResourcesForGateway discovers resources related to a Gateway.
-func (d Discoverer) DiscoverResourcesForGateway(filter Filter) (*ResourceModel, error) {
-	ctx := context.Background()
-	resourceModel := &ResourceModel{}
+func (d Discoverer) DiscoverResourcesForGateway(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	return d.discoverResourcesForGateways(ctx, filter, false)
+}
 
-	gateways, err := d.fetchGateways(ctx, filter)
+// DiscoverResourcesForGatewayImpact discovers resources related to a Gateway,
+// like DiscoverResourcesForGateway, and additionally checks that the other
+// parents of its HTTPRoutes exist, so that HTTPRouteNode.OtherParents only
+// returns parents which would still serve the HTTPRoutes.
+func (d Discoverer) DiscoverResourcesForGatewayImpact(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	resourceModel, err := d.discoverResourcesForGateways(ctx, filter, false)
 	if err != nil {
 		return resourceModel, err
 	}
+	d.verifyOtherParentsOfHTTPRoutes(ctx, resourceModel)
+	return resourceModel, ctx.Err()
+}
+
+// DiscoverResourcesForTopology discovers the Gateways matching the filter along
+// with the HTTPRoutes attached to them and the Services these forward to,
+// including the default backends of the Gateways. Unlike
+// DiscoverResourcesForRequests, Gateways without any HTTPRoutes are included.
+// Unlike DiscoverResourcesForGateway, the Events of the Gateways and the
+// parameters of their GatewayClasses are not discovered.
+func (d Discoverer) DiscoverResourcesForTopology(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	return d.discoverResourcesForGateways(ctx, filter, true)
+}
+
+// discoverResourcesForGateways discovers the resources related to the Gateways
+// matching the filter. If withBackends is set, the Services the HTTPRoutes
+// forward to are discovered instead of the details only shown when describing
+// Gateways.
+func (d Discoverer) discoverResourcesForGateways(ctx context.Context, filter Filter, withBackends bool) (*ResourceModel, error) {
+	resourceModel := &ResourceModel{
+		IgnoredNamespaces:            d.ignoredNamespaces(filter),
+		requireParentReferenceGrants: d.RequireParentReferenceGrants,
+		auditEnabled:                 d.Audit,
+	}
+
+	gateways, err := d.fetchGateways(ctx, filter)
+	if err != nil && !d.skipForbidden(resourceModel, "Gateways", err) {
+		return resourceModel, err
+	}
+	gateways = excludeIgnoredNamespaces(gateways, resourceModel.IgnoredNamespaces)
 	resourceModel.addGateways(gateways...)
 
-	d.discoverEventsForGateways(ctx, resourceModel)
+	if !withBackends {
+		d.discoverEventsForGateways(ctx, resourceModel)
+	}
 
 	d.discoverHTTPRoutesFromGateways(ctx, resourceModel)
+	if withBackends {
+		if err := d.discoverBackendsFromHTTPRoutes(ctx, resourceModel); err != nil {
+			return resourceModel, err
+		}
+	}
+	if err := d.discoverDefaultBackendsFromGateways(ctx, resourceModel); err != nil {
+		return resourceModel, err
+	}
+	d.verifyCertificateRefGrantsForGateways(ctx, resourceModel)
+	if withBackends {
+		resourceModel.resolveNamedBackendPorts()
+	}
 	d.discoverGatewayClassesFromGateways(ctx, resourceModel)
-	d.discoverNamespaces(ctx, resourceModel)
+	if !withBackends {
+		d.discoverParametersForGatewayClasses(ctx, resourceModel)
+	}
+	if err := d.discoverNamespaces(ctx, resourceModel); err != nil {
+		return resourceModel, err
+	}
 	d.discoverPolicies(resourceModel)
 
+	if err := ctx.Err(); err != nil {
+		return resourceModel, err
+	}
 	if err := resourceModel.calculateEffectivePolicies(); err != nil {
 		return resourceModel, err
 	}
+	resourceModel.validateEffectivePolicies(d.PolicyManager)
 
 	return resourceModel, nil
 }
 
 // DiscoverResourcesForHTTPRoute discovers resources related to an HTTPRoute.
-func (d Discoverer) DiscoverResourcesForHTTPRoute(filter Filter) (*ResourceModel, error) {
-	ctx := context.Background()
-	resourceModel := &ResourceModel{}
+func (d Discoverer) DiscoverResourcesForHTTPRoute(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	return d.discoverResourcesForHTTPRoutes(ctx, filter, false)
+}
+
+// discoverResourcesForHTTPRoutes discovers the resources related to the
+// HTTPRoutes matching the filter. If withDefaultBackends is set, the default
+// backends of the Gateways are discovered as well, and the certificateRefs of
+// the Gateways verified.
+func (d Discoverer) discoverResourcesForHTTPRoutes(ctx context.Context, filter Filter, withDefaultBackends bool) (*ResourceModel, error) {
+	resourceModel := &ResourceModel{
+		IgnoredNamespaces:            d.ignoredNamespaces(filter),
+		requireParentReferenceGrants: d.RequireParentReferenceGrants,
+		auditEnabled:                 d.Audit,
+	}
 
 	httpRoutes, err := d.fetchHTTPRoutes(ctx, filter)
-	if err != nil {
+	if err != nil && !d.skipForbidden(resourceModel, "HTTPRoutes", err) {
 		return resourceModel, err
 	}
+	httpRoutes = excludeIgnoredNamespaces(httpRoutes, resourceModel.IgnoredNamespaces)
 	resourceModel.addHTTPRoutes(httpRoutes...)
 
 	d.discoverGatewaysFromHTTPRoutes(ctx, resourceModel)
+	d.discoverParentServicesFromHTTPRoutes(ctx, resourceModel)
+	if err := d.discoverBackendsFromHTTPRoutes(ctx, resourceModel); err != nil {
+		return resourceModel, err
+	}
+	if withDefaultBackends {
+		if err := d.discoverDefaultBackendsFromGateways(ctx, resourceModel); err != nil {
+			return resourceModel, err
+		}
+		d.verifyCertificateRefGrantsForGateways(ctx, resourceModel)
+	}
+	resourceModel.resolveNamedBackendPorts()
+	d.discoverExtensionRefsFromHTTPRoutes(ctx, resourceModel)
+	d.discoverSecretsFromHTTPRoutes(ctx, resourceModel)
 	d.discoverGatewayClassesFromGateways(ctx, resourceModel)
-	d.discoverNamespaces(ctx, resourceModel)
+	if err := d.discoverNamespaces(ctx, resourceModel); err != nil {
+		return resourceModel, err
+	}
 	d.discoverPolicies(resourceModel)
 
+	if err := ctx.Err(); err != nil {
+		return resourceModel, err
+	}
 	if err := resourceModel.calculateEffectivePolicies(); err != nil {
 		return resourceModel, err
 	}
+	resourceModel.validateEffectivePolicies(d.PolicyManager)
 
 	return resourceModel, nil
 }
 
 // DiscoverResourcesForBackend discovers resources related to a Backend.
-func (d Discoverer) DiscoverResourcesForBackend(filter Filter) (*ResourceModel, error) {
-	ctx := context.Background()
-	resourceModel := &ResourceModel{}
+func (d Discoverer) DiscoverResourcesForBackend(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	resourceModel := &ResourceModel{
+		IgnoredNamespaces:            d.ignoredNamespaces(filter),
+		requireParentReferenceGrants: d.RequireParentReferenceGrants,
+		auditEnabled:                 d.Audit,
+	}
 
 	backends, err := d.fetchBackends(ctx, filter)
-	if err != nil {
+	if err != nil && !d.skipForbidden(resourceModel, "Services", err) {
 		return resourceModel, err
 	}
+	backends = excludeIgnoredNamespaces(backends, resourceModel.IgnoredNamespaces)
 	resourceModel.addBackends(backends...)
 
-	d.discoverReferenceGrantsFromBackends(ctx, resourceModel)
+	d.discoverTopologyForBackends(ctx, resourceModel)
+	if err := d.discoverReferenceGrantsFromBackends(ctx, resourceModel); err != nil {
+		return resourceModel, err
+	}
 	d.discoverHTTPRoutesFromBackends(ctx, resourceModel)
+	resourceModel.resolveNamedBackendPorts()
 	d.discoverGatewaysFromHTTPRoutes(ctx, resourceModel)
 	d.discoverGatewayClassesFromGateways(ctx, resourceModel)
-	d.discoverNamespaces(ctx, resourceModel)
+	if err := d.discoverNamespaces(ctx, resourceModel); err != nil {
+		return resourceModel, err
+	}
 	d.discoverPolicies(resourceModel)
 
+	if err := ctx.Err(); err != nil {
+		return resourceModel, err
+	}
 	if err := resourceModel.calculateEffectivePolicies(); err != nil {
 		return resourceModel, err
 	}
+	resourceModel.validateEffectivePolicies(d.PolicyManager)
 
 	return resourceModel, nil
 }
 
+// DiscoverResourcesForRequests discovers the HTTPRoutes matching the filter
+// along with their Gateways and the Services they forward to. This is the
+// topology needed to simulate how requests are routed (see
+// ResourceModel.TraceRequest and ResourceModel.BackendsForHost).
+func (d Discoverer) DiscoverResourcesForRequests(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	return d.discoverResourcesForHTTPRoutes(ctx, filter, true)
+}
+
+// DiscoverResourcesForReferenceGrant discovers the ReferenceGrants matching the
+// filter along with the Services they expose and the HTTPRoutes referencing
+// these Services.
+func (d Discoverer) DiscoverResourcesForReferenceGrant(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	resourceModel := &ResourceModel{
+		IgnoredNamespaces:            d.ignoredNamespaces(filter),
+		requireParentReferenceGrants: d.RequireParentReferenceGrants,
+		auditEnabled:                 d.Audit,
+	}
+
+	referenceGrants, err := d.fetchReferenceGrants(ctx, filter)
+	if err != nil && !d.skipForbidden(resourceModel, "ReferenceGrants", err) {
+		return resourceModel, err
+	}
+	referenceGrants = excludeIgnoredNamespaces(referenceGrants, resourceModel.IgnoredNamespaces)
+	resourceModel.addReferenceGrants(referenceGrants...)
+
+	d.discoverBackendsFromReferenceGrants(ctx, resourceModel)
+	d.discoverGatewaysFromReferenceGrants(ctx, resourceModel)
+	d.discoverHTTPRoutesFromBackends(ctx, resourceModel)
+	resourceModel.resolveNamedBackendPorts()
+	if len(resourceModel.Gateways) != 0 {
+		d.discoverHTTPRoutesFromGateways(ctx, resourceModel)
+	}
+
+	return resourceModel, ctx.Err()
+}
+
 // DiscoverResourcesForNamespace discovers resources related to a Namespace.
-func (d Discoverer) DiscoverResourcesForNamespace(filter Filter) (*ResourceModel, error) {
-	ctx := context.Background()
-	resourceModel := &ResourceModel{}
+func (d Discoverer) DiscoverResourcesForNamespace(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	return d.discoverResourcesForNamespace(ctx, filter, false)
+}
+
+// DiscoverResourcesForNamespaceContents discovers the same resources as
+// DiscoverResourcesForNamespace, along with the Gateways, HTTPRoutes and
+// Backends within the Namespaces.
+func (d Discoverer) DiscoverResourcesForNamespaceContents(ctx context.Context, filter Filter) (*ResourceModel, error) {
+	return d.discoverResourcesForNamespace(ctx, filter, true)
+}
+
+func (d Discoverer) discoverResourcesForNamespace(ctx context.Context, filter Filter, contents bool) (*ResourceModel, error) {
+	resourceModel := &ResourceModel{
+		IgnoredNamespaces:            d.ignoredNamespaces(filter),
+		requireParentReferenceGrants: d.RequireParentReferenceGrants,
+		auditEnabled:                 d.Audit,
+	}
 
 	namespaces, err := d.fetchNamespace(ctx, filter)
-	if err != nil {
+	if err != nil && !d.skipForbidden(resourceModel, "Namespaces", err) {
 		return resourceModel, err
 	}
+	var filteredNamespaces []corev1.Namespace
+	for _, namespace := range namespaces {
+		if filter.Name != "" || !resourceModel.IgnoredNamespaces.Ignores(namespace.GetName()) {
+			filteredNamespaces = append(filteredNamespaces, namespace)
+		}
+	}
+	namespaces = filteredNamespaces
 
 	resourceModel.addNamespace(namespaces...)
 
+	if contents {
+		if err := d.discoverResourcesInNamespaces(ctx, resourceModel, filter); err != nil {
+			return resourceModel, err
+		}
+		d.discoverGatewayClassesFromGateways(ctx, resourceModel)
+	}
 	d.discoverPolicies(resourceModel)
 
-	return resourceModel, nil
+	return resourceModel, ctx.Err()
+}
+
+// ignoredNamespaces returns the namespaces to be ignored for the filter. If
+// the filter explicitly requests an ignored namespace, nothing is ignored.
+func (d Discoverer) ignoredNamespaces(filter Filter) NamespaceIgnoreList {
+	if filter.Namespace != "" && d.IgnoredNamespaces.Ignores(filter.Namespace) {
+		return nil
+	}
+	return d.IgnoredNamespaces
+}
+
+// excludeIgnoredNamespaces returns the objects which do not belong to an
+// ignored namespace.
+func excludeIgnoredNamespaces[T any, PT interface {
+	*T
+	metav1.Object
+}](objects []T, ignoredNamespaces NamespaceIgnoreList) []T {
+	if len(ignoredNamespaces) == 0 {
+		return objects
+	}
+	var result []T
+	for i := range objects {
+		if !ignoredNamespaces.Ignores(PT(&objects[i]).GetNamespace()) {
+			result = append(result, objects[i])
+		}
+	}
+	return result
 }
 
 // discoverGatewayClassesFromGateways will add GatewayClasses associated with
@@ -241,6 +511,10 @@ func (d Discoverer) DiscoverResourcesForNamespace(filter Filter) (*ResourceModel
 func (d Discoverer) discoverGatewayClassesFromGateways(ctx context.Context, resourceModel *ResourceModel) {
 	gatewayClasses, err := d.fetchGatewayClasses(ctx, Filter{ /* all GatewayClasses */ Labels: labels.Everything()})
 	if err != nil {
+		if d.skipForbidden(resourceModel, "GatewayClasses", err) {
+			// The GatewayClasses of the Gateways can not be verified to exist.
+			return
+		}
 		klog.V(1).ErrorS(err, "Failed to list all GatewayClasses")
 	}
 
@@ -269,6 +543,54 @@ func (d Discoverer) discoverGatewayClassesFromGateways(ctx context.Context, reso
 	}
 }
 
+// discoverParametersForGatewayClasses will resolve the parametersRef of
+// GatewayClasses in the resourceModel. References which can not be resolved
+// are recorded as errors of the GatewayClass.
+func (d Discoverer) discoverParametersForGatewayClasses(ctx context.Context, resourceModel *ResourceModel) {
+	// CRDs are only fetched once the first parametersRef is encountered, since
+	// many GatewayClasses don't have any.
+	var crds []apiextensionsv1.CustomResourceDefinition
+	var crdsErr error
+	crdsFetched := false
+
+	for _, gatewayClassNode := range resourceModel.GatewayClasses {
+		ref := gatewayClassNode.GatewayClass.Spec.ParametersRef
+		if ref == nil {
+			continue
+		}
+		var namespace string
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+
+		var object *unstructured.Unstructured
+		var err error
+		if ref.Group == "" && ref.Kind == "ConfigMap" {
+			gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+			object, err = d.K8sClients.DC.Resource(gvr).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		} else {
+			if !crdsFetched {
+				crds, crdsErr = d.fetchCRDs(ctx)
+				crdsFetched = true
+			}
+			err = crdsErr
+			if err == nil {
+				object, err = d.fetchCustomResource(ctx, crds, string(ref.Group), string(ref.Kind), namespace, ref.Name)
+			}
+		}
+		if err != nil {
+			err := UnresolvedParametersRefError{
+				ParametersRef: common.ObjRef{Group: string(ref.Group), Kind: string(ref.Kind), Name: ref.Name, Namespace: namespace},
+				Reason:        err.Error(),
+			}
+			gatewayClassNode.Errors = append(gatewayClassNode.Errors, err)
+			klog.V(1).Info(err)
+			continue
+		}
+		gatewayClassNode.Parameters = object
+	}
+}
+
 // discoverGatewaysFromHTTPRoutes will add Gateways associated with HTTPRoutes
 // in the resourceModel.
 func (d Discoverer) discoverGatewaysFromHTTPRoutes(ctx context.Context, resourceModel *ResourceModel) {
@@ -291,7 +613,7 @@ func (d Discoverer) discoverGatewaysFromHTTPRoutes(ctx context.Context, resource
 					}}
 					httpRouteNode.Errors = append(httpRouteNode.Errors, err)
 					klog.V(1).Info(err)
-				} else {
+				} else if !d.skipForbidden(resourceModel, "Gateways", err) {
 					klog.V(1).ErrorS(err, "Error while fetching Gateway for HTTPRoute",
 						"gateway", gatewayRef.String(),
 						"httproute", httpRouteNode.HTTPRoute.GetNamespace()+"/"+httpRouteNode.HTTPRoute.GetName(),
@@ -303,6 +625,8 @@ func (d Discoverer) discoverGatewaysFromHTTPRoutes(ctx context.Context, resource
 		}
 	}
 
+	d.discoverReferenceGrantsFromGateways(ctx, resourceModel)
+
 	// Connect gatewayd with httproutes.
 	for httpRouteID, httpRouteNode := range resourceModel.HTTPRoutes {
 		for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
@@ -311,11 +635,293 @@ func (d Discoverer) discoverGatewaysFromHTTPRoutes(ctx context.Context, resource
 	}
 }
 
+// verifyOtherParentsOfHTTPRoutes records an error on the HTTPRoutes in the
+// resourceModel for each of their parent Gateways and Services which does not
+// exist. Parents which could not be fetched for another reason are assumed to
+// exist.
+func (d Discoverer) verifyOtherParentsOfHTTPRoutes(ctx context.Context, resourceModel *ResourceModel) {
+	for _, httpRouteNode := range resourceModel.HTTPRoutes {
+		httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()}
+		var missingParents []common.ObjRef
+		for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if _, ok := resourceModel.Gateways[GatewayID(gatewayRef.Namespace, gatewayRef.Name)]; ok {
+				continue
+			}
+			_, err := d.fetchGateways(ctx, Filter{Namespace: gatewayRef.Namespace, Name: gatewayRef.Name, Labels: labels.Everything()})
+			if apierrors.IsNotFound(err) {
+				missingParents = append(missingParents, common.ObjRef{Kind: "Gateway", Name: gatewayRef.Name, Namespace: gatewayRef.Namespace})
+			} else if err != nil && !d.skipForbidden(resourceModel, "Gateways", err) {
+				klog.V(1).ErrorS(err, "Error while fetching Gateway for HTTPRoute", "gateway", gatewayRef.String(), "httproute", httpRouteRef.Namespace+"/"+httpRouteRef.Name)
+			}
+		}
+		for _, serviceRef := range relations.FindServiceParentRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if _, ok := resourceModel.Backends[BackendIDForService(serviceRef.Namespace, serviceRef.Name)]; ok {
+				continue
+			}
+			_, err := d.fetchBackends(ctx, Filter{Namespace: serviceRef.Namespace, Name: serviceRef.Name, Labels: labels.Everything()})
+			if apierrors.IsNotFound(err) {
+				missingParents = append(missingParents, common.ObjRef{Kind: "Service", Name: serviceRef.Name, Namespace: serviceRef.Namespace})
+			} else if err != nil && !d.skipForbidden(resourceModel, "Services", err) {
+				klog.V(1).ErrorS(err, "Error while fetching parent Service for HTTPRoute", "service", serviceRef.String(), "httproute", httpRouteRef.Namespace+"/"+httpRouteRef.Name)
+			}
+		}
+		for _, parentRef := range missingParents {
+			err := ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+				ReferringObject: httpRouteRef,
+				ReferredObject:  parentRef,
+			}}
+			httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+			klog.V(1).Info(err)
+		}
+	}
+}
+
+// discoverParentServicesFromHTTPRoutes will add Services which are the parents
+// of mesh HTTPRoutes in the resourceModel.
+func (d Discoverer) discoverParentServicesFromHTTPRoutes(ctx context.Context, resourceModel *ResourceModel) {
+	for httpRouteID, httpRouteNode := range resourceModel.HTTPRoutes {
+		for _, serviceRef := range relations.FindServiceParentRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			backendID := BackendIDForService(serviceRef.Namespace, serviceRef.Name)
+			if _, ok := resourceModel.Backends[backendID]; !ok {
+				services, err := d.fetchBackends(ctx, Filter{Namespace: serviceRef.Namespace, Name: serviceRef.Name, Labels: labels.Everything()})
+				if err != nil {
+					if apierrors.IsNotFound(err) {
+						err := ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+							ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()},
+							ReferredObject:  common.ObjRef{Kind: "Service", Name: serviceRef.Name, Namespace: serviceRef.Namespace},
+						}}
+						httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+						klog.V(1).Info(err)
+					} else if !d.skipForbidden(resourceModel, "Services", err) {
+						klog.V(1).ErrorS(err, "Error while fetching parent Service for HTTPRoute",
+							"service", serviceRef.String(),
+							"httproute", httpRouteNode.HTTPRoute.GetNamespace()+"/"+httpRouteNode.HTTPRoute.GetName(),
+						)
+					}
+					continue
+				}
+				resourceModel.addBackends(services[0])
+			}
+			resourceModel.connectHTTPRouteWithParentService(httpRouteID, backendID)
+		}
+	}
+}
+
+// discoverBackendsFromHTTPRoutes adds the Services referenced as backends by
+// HTTPRoutes in the resourceModel, along with the ReferenceGrants exposing them,
+// and connects each HTTPRoute with the Services it is permitted to reference.
+// References to Services which do not exist are recorded as errors of the
+// HTTPRoute.
+func (d Discoverer) discoverBackendsFromHTTPRoutes(ctx context.Context, resourceModel *ResourceModel) error {
+	// Services are listed once per namespace instead of being fetched one at a
+	// time, since HTTPRoutes typically reference several Services of the same
+	// namespace. A nil entry means the Services of the namespace could not be
+	// listed, in which case none of them is known to be missing.
+	servicesByNamespace := make(map[string]map[string]unstructured.Unstructured)
+	for _, httpRouteNode := range resourceModel.HTTPRoutes {
+		for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if backendRef.Group != corev1.GroupName || backendRef.Kind != "Service" {
+				continue
+			}
+			if _, ok := resourceModel.Backends[BackendIDForService(backendRef.Namespace, backendRef.Name)]; ok {
+				continue
+			}
+			services, ok := servicesByNamespace[backendRef.Namespace]
+			if !ok {
+				services = d.fetchServicesByName(ctx, resourceModel, backendRef.Namespace)
+				servicesByNamespace[backendRef.Namespace] = services
+			}
+			if services == nil {
+				continue
+			}
+			service, ok := services[backendRef.Name]
+			if !ok {
+				err := ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()},
+					ReferredObject:  common.ObjRef{Kind: "Service", Name: backendRef.Name, Namespace: backendRef.Namespace},
+				}}
+				httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+				klog.V(1).Info(err)
+				continue
+			}
+			resourceModel.addBackends(service)
+		}
+	}
+
+	if err := d.discoverReferenceGrantsFromBackends(ctx, resourceModel); err != nil {
+		return err
+	}
+
+	for httpRouteID, httpRouteNode := range resourceModel.HTTPRoutes {
+		for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			backendID := BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)
+			backendNode, ok := resourceModel.Backends[backendID]
+			if !ok {
+				continue
+			}
+			if !httpRouteReferenceAccepted(*httpRouteNode.HTTPRoute, backendRef, backendNode) {
+				err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()},
+					ReferredObject:  backendRef,
+				}}
+				backendNode.Errors = append(backendNode.Errors, err)
+				klog.V(1).Info(err)
+				continue
+			}
+			resourceModel.connectHTTPRouteWithBackend(httpRouteID, backendID)
+		}
+	}
+	return nil
+}
+
+// fetchServicesByName lists the Services in the namespace, keyed by name. It
+// returns nil if the Services can not be listed.
+func (d Discoverer) fetchServicesByName(ctx context.Context, resourceModel *ResourceModel, namespace string) map[string]unstructured.Unstructured {
+	services, err := d.fetchBackends(ctx, Filter{Namespace: namespace, Labels: labels.Everything()})
+	if err != nil {
+		if !d.skipForbidden(resourceModel, "Services", err) {
+			klog.V(1).ErrorS(err, "Error while fetching backend Services for HTTPRoutes", "namespace", namespace)
+		}
+		return nil
+	}
+	result := make(map[string]unstructured.Unstructured, len(services))
+	for _, service := range services {
+		result[service.GetName()] = service
+	}
+	return result
+}
+
+// discoverExtensionRefsFromHTTPRoutes will add the objects referenced by the
+// ExtensionRef filters of HTTPRoutes in the resourceModel. References which
+// can not be resolved are recorded as errors of the HTTPRoute.
+func (d Discoverer) discoverExtensionRefsFromHTTPRoutes(ctx context.Context, resourceModel *ResourceModel) {
+	// CRDs are only fetched once the first ExtensionRef is encountered, since
+	// most HTTPRoutes don't use any.
+	var crds []apiextensionsv1.CustomResourceDefinition
+	var crdsErr error
+	crdsFetched := false
+
+	for httpRouteID, httpRouteNode := range resourceModel.HTTPRoutes {
+		namespace := httpRouteNode.HTTPRoute.GetNamespace()
+		for _, filter := range httpRouteNode.Filters {
+			ref := filter.ExtensionRef
+			if ref == nil || isSecretReference(*ref) {
+				// Secrets are discovered by discoverSecretsFromHTTPRoutes.
+				continue
+			}
+			extensionRefID := ExtensionRefID(string(ref.Group), string(ref.Kind), namespace, string(ref.Name))
+			if _, ok := resourceModel.ExtensionRefs[extensionRefID]; !ok {
+				if !crdsFetched {
+					crds, crdsErr = d.fetchCRDs(ctx)
+					crdsFetched = true
+				}
+				err := crdsErr
+				var object *unstructured.Unstructured
+				if err == nil {
+					object, err = d.fetchExtensionRef(ctx, crds, namespace, *ref)
+				}
+				if err != nil {
+					err := UnresolvedExtensionRefError{
+						RuleIndex:    filter.RuleIndex,
+						ExtensionRef: common.ObjRef{Group: string(ref.Group), Kind: string(ref.Kind), Name: string(ref.Name), Namespace: namespace},
+						Reason:       err.Error(),
+					}
+					httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+					klog.V(1).Info(err)
+					continue
+				}
+				resourceModel.addExtensionRefs(*object)
+			}
+			resourceModel.connectHTTPRouteWithExtensionRef(httpRouteID, extensionRefID)
+		}
+	}
+}
+
+// discoverSecretsFromHTTPRoutes will add the Secrets referenced through the
+// filters of HTTPRoutes in the resourceModel, either directly or through the
+// objects referenced by ExtensionRef filters. Secrets which do not exist are
+// added as missing, and recorded as errors of the HTTPRoute. Only the metadata
+// of the Secrets is fetched. References to Secrets in another namespace are
+// only followed if a ReferenceGrant permits them, otherwise they are recorded
+// as errors of the HTTPRoute.
+func (d Discoverer) discoverSecretsFromHTTPRoutes(ctx context.Context, resourceModel *ResourceModel) {
+	referenceGrantsByNamespace := make(map[string][]gatewayv1beta1.ReferenceGrant)
+	fetched := make(map[string]bool)
+	permitted := func(ref secretRef) bool {
+		if ref.Namespace == ref.From.Namespace {
+			return true
+		}
+		if !fetched[ref.Namespace] {
+			fetched[ref.Namespace] = true
+			referenceGrants, err := d.fetchReferenceGrants(ctx, Filter{Namespace: ref.Namespace, Labels: labels.Everything()})
+			if err != nil && !d.skipForbidden(resourceModel, "ReferenceGrants", err) {
+				klog.V(1).ErrorS(err, "Failed to fetch ReferenceGrants for Secrets", "namespace", ref.Namespace)
+			}
+			referenceGrantsByNamespace[ref.Namespace] = referenceGrants
+		}
+		secretObjRef := common.ObjRef{Kind: "Secret", Name: ref.Name, Namespace: ref.Namespace}
+		for _, referenceGrant := range referenceGrantsByNamespace[ref.Namespace] {
+			if relations.ReferenceGrantExposes(referenceGrant, secretObjRef) && relations.ReferenceGrantAccepts(referenceGrant, ref.From) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for httpRouteID, httpRouteNode := range resourceModel.HTTPRoutes {
+		for _, ref := range secretRefsOfHTTPRoute(httpRouteNode) {
+			if !permitted(ref) {
+				err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: ref.From.Kind, Name: ref.From.Name, Namespace: ref.From.Namespace},
+					ReferredObject:  common.ObjRef{Kind: "Secret", Name: ref.Name, Namespace: ref.Namespace},
+				}}
+				httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+				klog.V(1).Info(err)
+				continue
+			}
+
+			secretID := SecretID(ref.Namespace, ref.Name)
+			if _, ok := resourceModel.Secrets[secretID]; !ok {
+				secretMetadata := &metav1.PartialObjectMetadata{}
+				secretMetadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+				err := d.K8sClients.Client.Get(ctx, apimachinerytypes.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secretMetadata)
+				switch {
+				case err == nil:
+					resourceModel.addSecrets(corev1.Secret{ObjectMeta: secretMetadata.ObjectMeta})
+				case apierrors.IsNotFound(err):
+					resourceModel.addMissingSecret(ref.Namespace, ref.Name)
+				default:
+					if !d.skipForbidden(resourceModel, "Secrets", err) {
+						klog.V(1).ErrorS(err, "Error while fetching Secret for HTTPRoute",
+							"secret", ref.Namespace+"/"+ref.Name,
+							"httproute", httpRouteNode.HTTPRoute.GetNamespace()+"/"+httpRouteNode.HTTPRoute.GetName(),
+						)
+					}
+					continue
+				}
+			}
+			if resourceModel.Secrets[secretID].Missing {
+				err := ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()},
+					ReferredObject:  common.ObjRef{Kind: "Secret", Name: ref.Name, Namespace: ref.Namespace},
+				}}
+				if !slices.ContainsFunc(httpRouteNode.Errors, func(e error) bool { return e == error(err) }) {
+					httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+					klog.V(1).Info(err)
+				}
+			}
+			resourceModel.connectHTTPRouteWithSecret(httpRouteID, secretID)
+		}
+	}
+}
+
 // discoverHTTPRoutesFromGateways will add HTTPRoutes that are attached to any
 // Gateway in the resourceModel.
 func (d Discoverer) discoverHTTPRoutesFromGateways(ctx context.Context, resourceModel *ResourceModel) {
+	d.discoverReferenceGrantsFromGateways(ctx, resourceModel)
+
 	httpRoutes, err := d.fetchHTTPRoutes(ctx, Filter{ /* all HTTPRoutes */ Labels: labels.Everything()})
-	if err != nil {
+	if err != nil && !d.skipForbidden(resourceModel, "HTTPRoutes", err) {
 		klog.V(1).ErrorS(err, "Failed to list all HTTPRoutes")
 	}
 
@@ -327,7 +933,7 @@ func (d Discoverer) discoverHTTPRoutesFromGateways(ctx context.Context, resource
 		)
 		var isHTTPRouteAttachedToValidGateway bool
 
-		for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(httpRoute) {
+		for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(httpRoute.HTTPRoute) {
 			// Check if Gateway exists in the resourceModel.
 			gatewayID := GatewayID(gatewayRef.Namespace, gatewayRef.Name)
 			_, ok := resourceModel.Gateways[gatewayID]
@@ -359,7 +965,7 @@ func (d Discoverer) discoverHTTPRoutesFromGateways(ctx context.Context, resource
 // present in resourceModel.
 func (d Discoverer) discoverHTTPRoutesFromBackends(ctx context.Context, resourceModel *ResourceModel) {
 	httpRoutes, err := d.fetchHTTPRoutes(ctx, Filter{ /* all HTTPRoutes */ Labels: labels.Everything()})
-	if err != nil {
+	if err != nil && !d.skipForbidden(resourceModel, "HTTPRoutes", err) {
 		klog.V(1).ErrorS(err, "Failed to list all HTTPRoutes")
 	}
 
@@ -368,7 +974,7 @@ func (d Discoverer) discoverHTTPRoutesFromBackends(ctx context.Context, resource
 		// Backend which already exists in the resourceModel.
 		var includeRouteInResourceModel bool
 
-		for _, backendRef := range relations.FindBackendRefsForHTTPRoute(httpRoute) {
+		for _, backendRef := range relations.FindBackendRefsForHTTPRoute(httpRoute.HTTPRoute) {
 			// Check if the referenced backend exists in the resourceModel.
 			backendID := BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)
 			backendNode, ok := resourceModel.Backends[backendID]
@@ -378,29 +984,14 @@ func (d Discoverer) discoverHTTPRoutesFromBackends(ctx context.Context, resource
 
 			// Ensure that if this is a cross namespace reference, then it is accepted
 			// through some ReferenceGrant.
-			if httpRoute.GetNamespace() != backendRef.Namespace {
-				httpRouteRef := common.ObjRef{
-					Group:     httpRoute.GroupVersionKind().Group,
-					Kind:      httpRoute.GroupVersionKind().Kind,
-					Name:      httpRoute.GetName(),
-					Namespace: httpRoute.GetNamespace(),
-				}
-				var referenceAccepted bool
-				for _, referenceGrantNode := range backendNode.ReferenceGrants {
-					if relations.ReferenceGrantAccepts(*referenceGrantNode.ReferenceGrant, httpRouteRef) {
-						referenceAccepted = true
-						break
-					}
-				}
-				if !referenceAccepted {
-					err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
-						ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRoute.GetName(), Namespace: httpRoute.GetNamespace()},
-						ReferredObject:  backendRef,
-					}}
-					backendNode.Errors = append(backendNode.Errors, err)
-					klog.V(1).Info(err)
-					continue
-				}
+			if !httpRouteReferenceAccepted(httpRoute.HTTPRoute, backendRef, backendNode) {
+				err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRoute.GetName(), Namespace: httpRoute.GetNamespace()},
+					ReferredObject:  backendRef,
+				}}
+				backendNode.Errors = append(backendNode.Errors, err)
+				klog.V(1).Info(err)
+				continue
 			}
 
 			// At this point, we know that:
@@ -421,35 +1012,284 @@ func (d Discoverer) discoverHTTPRoutesFromBackends(ctx context.Context, resource
 	}
 }
 
+// httpRouteReferenceAccepted returns true if the HTTPRoute is allowed to
+// reference the backend, which is the case for references within the same
+// namespace and for cross namespace references accepted by some ReferenceGrant
+// of the backend.
+func httpRouteReferenceAccepted(httpRoute gatewayv1.HTTPRoute, backendRef common.ObjRef, backendNode *BackendNode) bool {
+	return referenceAccepted(common.ObjRef{
+		Group:     httpRoute.GroupVersionKind().Group,
+		Kind:      httpRoute.GroupVersionKind().Kind,
+		Name:      httpRoute.GetName(),
+		Namespace: httpRoute.GetNamespace(),
+	}, backendRef, backendNode)
+}
+
+// referenceAccepted returns true if the referring object is allowed to
+// reference the backend.
+func referenceAccepted(from common.ObjRef, backendRef common.ObjRef, backendNode *BackendNode) bool {
+	if from.Namespace == backendRef.Namespace {
+		return true
+	}
+	for _, referenceGrantNode := range backendNode.ReferenceGrants {
+		if relations.ReferenceGrantAccepts(*referenceGrantNode.ReferenceGrant, from) {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverDefaultBackendsFromGateways adds the Services referenced as default
+// backends by Gateways in the resourceModel, and connects each Gateway with
+// the Services it is permitted to reference. References to Services which do
+// not exist are recorded as errors of the Gateway.
+func (d Discoverer) discoverDefaultBackendsFromGateways(ctx context.Context, resourceModel *ResourceModel) error {
+	for _, gatewayNode := range resourceModel.Gateways {
+		for _, defaultBackendRef := range gatewayNode.DefaultBackendRefs {
+			backendRef := defaultBackendRef.BackendRef
+			if backendRef.Group != corev1.GroupName || backendRef.Kind != "Service" {
+				continue
+			}
+			if _, ok := resourceModel.Backends[BackendIDForService(backendRef.Namespace, backendRef.Name)]; ok {
+				continue
+			}
+			services, err := d.fetchBackends(ctx, Filter{Namespace: backendRef.Namespace, Name: backendRef.Name, Labels: labels.Everything()})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					err := ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+						ReferringObject: common.ObjRef{Kind: "Gateway", Name: gatewayNode.Gateway.GetName(), Namespace: gatewayNode.Gateway.GetNamespace()},
+						ReferredObject:  common.ObjRef{Kind: "Service", Name: backendRef.Name, Namespace: backendRef.Namespace},
+					}}
+					gatewayNode.Errors = append(gatewayNode.Errors, err)
+					klog.V(1).Info(err)
+				} else if !d.skipForbidden(resourceModel, "Services", err) {
+					klog.V(1).ErrorS(err, "Error while fetching default backend Service for Gateway",
+						"service", backendRef.Namespace+"/"+backendRef.Name,
+						"gateway", gatewayNode.Gateway.GetNamespace()+"/"+gatewayNode.Gateway.GetName(),
+					)
+				}
+				continue
+			}
+			resourceModel.addBackends(services...)
+		}
+	}
+
+	if err := d.discoverReferenceGrantsFromBackends(ctx, resourceModel); err != nil {
+		return err
+	}
+
+	for gatewayID, gatewayNode := range resourceModel.Gateways {
+		gatewayRef := common.ObjRef{
+			Group:     gatewayv1.GroupName,
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}
+		for _, defaultBackendRef := range gatewayNode.DefaultBackendRefs {
+			backendRef := defaultBackendRef.BackendRef
+			backendID := BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)
+			backendNode, ok := resourceModel.Backends[backendID]
+			if !ok {
+				continue
+			}
+			if !referenceAccepted(gatewayRef, backendRef, backendNode) {
+				err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: gatewayRef,
+					ReferredObject:  backendRef,
+				}}
+				backendNode.Errors = append(backendNode.Errors, err)
+				klog.V(1).Info(err)
+				continue
+			}
+			resourceModel.connectGatewayWithDefaultBackend(gatewayID, backendID)
+		}
+	}
+	return nil
+}
+
 // discoverNamespaces adds Namespaces for resources that exist in the
 // resourceModel.
-func (d Discoverer) discoverNamespaces(ctx context.Context, resourceModel *ResourceModel) {
-	namespacesList := &corev1.NamespaceList{}
-	if err := d.K8sClients.Client.List(ctx, namespacesList, &client.ListOptions{}); err != nil {
-		fmt.Fprintf(os.Stderr, "failed to fetch list of namespaces: %v\n", err)
-		os.Exit(1)
+func (d Discoverer) discoverNamespaces(ctx context.Context, resourceModel *ResourceModel) error {
+	namespaces, err := d.listNamespaces(ctx, &client.ListOptions{})
+	skipped := false
+	if err != nil {
+		if ctx.Err() != nil {
+			// Discovery was cancelled, which is reported by the caller.
+			return nil
+		}
+		if !d.skipForbidden(resourceModel, "Namespaces", err) {
+			return fmt.Errorf("failed to fetch list of namespaces: %w", err)
+		}
+		skipped = true
 	}
 
+	if !skipped {
+		resourceModel.NamespaceLabels = newNamespaceLabelIndex(namespaces)
+	}
 	namespaceMap := make(map[string]corev1.Namespace)
-	for _, namespace := range namespacesList.Items {
+	for _, namespace := range namespaces {
 		namespaceMap[namespace.Name] = namespace
 	}
+	namespaceOf := func(name string) corev1.Namespace {
+		namespace, ok := namespaceMap[name]
+		if !ok && skipped {
+			// Namespaces which could not be fetched are still part of the
+			// resourceModel, such that policies can attach to them, but without
+			// any labels.
+			namespace.Name = name
+		}
+		return namespace
+	}
 
 	for gatewayID, gatewayNode := range resourceModel.Gateways {
-		resourceModel.addNamespace(namespaceMap[gatewayNode.Gateway.GetNamespace()])
+		resourceModel.addNamespace(namespaceOf(gatewayNode.Gateway.GetNamespace()))
 		resourceModel.connectGatewayWithNamespace(gatewayID, NamespaceID(gatewayNode.Gateway.GetNamespace()))
 	}
 	for httpRouteID, httpRouteNode := range resourceModel.HTTPRoutes {
-		resourceModel.addNamespace(namespaceMap[httpRouteNode.HTTPRoute.GetNamespace()])
+		resourceModel.addNamespace(namespaceOf(httpRouteNode.HTTPRoute.GetNamespace()))
 		resourceModel.connectHTTPRouteWithNamespace(httpRouteID, NamespaceID(httpRouteNode.HTTPRoute.GetNamespace()))
 	}
 	for backendID, backendNode := range resourceModel.Backends {
-		resourceModel.addNamespace(namespaceMap[backendNode.Backend.GetNamespace()])
+		resourceModel.addNamespace(namespaceOf(backendNode.Backend.GetNamespace()))
 		resourceModel.connectBackendWithNamespace(backendID, NamespaceID(backendNode.Backend.GetNamespace()))
 	}
+	return nil
 }
 
-func (d Discoverer) discoverReferenceGrantsFromBackends(ctx context.Context, resourceModel *ResourceModel) {
+// discoverResourcesInNamespaces adds the Gateways and HTTPRoutes residing
+// within the Namespaces of the resourceModel, along with the Services
+// referenced as backends by the HTTPRoutes, and connects those within the
+// Namespaces to their Namespace. Services which no HTTPRoute references are not
+// discovered. Gateways and HTTPRoutes are listed across all namespaces unless
+// the filter names a single Namespace.
+func (d Discoverer) discoverResourcesInNamespaces(ctx context.Context, resourceModel *ResourceModel, filter Filter) error {
+	if len(resourceModel.Namespaces) == 0 {
+		return nil
+	}
+	namespaceFilter := Filter{Namespace: metav1.NamespaceAll}
+	if filter.Name != "" {
+		namespaceFilter.Namespace = filter.Name
+	}
+	inModel := func(namespace string) bool {
+		_, ok := resourceModel.Namespaces[NamespaceID(namespace)]
+		return ok
+	}
+
+	gateways, err := d.fetchGateways(ctx, namespaceFilter)
+	if err != nil && !d.skipForbidden(resourceModel, "Gateways", err) {
+		klog.V(1).ErrorS(err, "Failed to list Gateways in Namespaces")
+	}
+	for _, gateway := range gateways {
+		if !inModel(gateway.GetNamespace()) {
+			continue
+		}
+		resourceModel.addGateways(gateway)
+		resourceModel.connectGatewayWithNamespace(GatewayID(gateway.GetNamespace(), gateway.GetName()), NamespaceID(gateway.GetNamespace()))
+	}
+
+	httpRoutes, err := d.fetchHTTPRoutes(ctx, namespaceFilter)
+	if err != nil && !d.skipForbidden(resourceModel, "HTTPRoutes", err) {
+		klog.V(1).ErrorS(err, "Failed to list HTTPRoutes in Namespaces")
+	}
+	for _, httpRoute := range httpRoutes {
+		if !inModel(httpRoute.GetNamespace()) {
+			continue
+		}
+		resourceModel.addHTTPRoutes(httpRoute)
+		resourceModel.connectHTTPRouteWithNamespace(HTTPRouteID(httpRoute.GetNamespace(), httpRoute.GetName()), NamespaceID(httpRoute.GetNamespace()))
+	}
+	// Connect the HTTPRoutes with their parent Gateways, which may be in
+	// other namespaces.
+	d.discoverGatewaysFromHTTPRoutes(ctx, resourceModel)
+
+	if err := d.discoverBackendsFromHTTPRoutes(ctx, resourceModel); err != nil {
+		return err
+	}
+	resourceModel.resolveNamedBackendPorts()
+	for backendID, backendNode := range resourceModel.Backends {
+		if namespace := backendNode.Backend.GetNamespace(); inModel(namespace) {
+			resourceModel.connectBackendWithNamespace(backendID, NamespaceID(namespace))
+		}
+	}
+	return nil
+}
+
+// discoverBackendsFromReferenceGrants adds the Services exposed by the
+// ReferenceGrants in the resourceModel, and connects them with the
+// ReferenceGrants. Only the ReferenceGrants already in the resourceModel are
+// connected, even if other ReferenceGrants expose the same Services.
+func (d Discoverer) discoverBackendsFromReferenceGrants(ctx context.Context, resourceModel *ResourceModel) {
+	fetched := make(map[string]bool)
+	for _, referenceGrantNode := range resourceModel.ReferenceGrants {
+		namespace := referenceGrantNode.ReferenceGrant.GetNamespace()
+		if fetched[namespace] {
+			continue
+		}
+		fetched[namespace] = true
+		services, err := d.fetchBackends(ctx, Filter{Namespace: namespace, Labels: labels.Everything()})
+		if err != nil {
+			if !d.skipForbidden(resourceModel, "Services", err) {
+				klog.V(1).ErrorS(err, "Error while fetching Services exposed by ReferenceGrants", "namespace", namespace)
+			}
+			continue
+		}
+		for _, service := range services {
+			for referenceGrantID, referenceGrantNode := range resourceModel.ReferenceGrants {
+				if !relations.ReferenceGrantExposes(*referenceGrantNode.ReferenceGrant, common.ObjRef{Kind: "Service", Name: service.GetName(), Namespace: service.GetNamespace()}) {
+					continue
+				}
+				resourceModel.addBackends(service)
+				resourceModel.connectReferenceGrantWithBackend(referenceGrantID, BackendIDForService(service.GetNamespace(), service.GetName()))
+			}
+		}
+	}
+}
+
+// discoverGatewaysFromReferenceGrants adds the Gateways exposed by the
+// ReferenceGrants in the resourceModel, which permit HTTPRoutes in the From
+// namespaces of the ReferenceGrants to attach to them, and connects them with
+// the ReferenceGrants. Gateways are only fetched in the namespaces of
+// ReferenceGrants which expose Gateways.
+func (d Discoverer) discoverGatewaysFromReferenceGrants(ctx context.Context, resourceModel *ResourceModel) {
+	fetched := make(map[string]bool)
+	for _, referenceGrantNode := range resourceModel.ReferenceGrants {
+		namespace := referenceGrantNode.ReferenceGrant.GetNamespace()
+		if fetched[namespace] || !exposesGateways(*referenceGrantNode.ReferenceGrant) {
+			continue
+		}
+		fetched[namespace] = true
+		gateways, err := d.fetchGateways(ctx, Filter{Namespace: namespace, Labels: labels.Everything()})
+		if err != nil {
+			if !d.skipForbidden(resourceModel, "Gateways", err) {
+				klog.V(1).ErrorS(err, "Error while fetching Gateways exposed by ReferenceGrants", "namespace", namespace)
+			}
+			continue
+		}
+		for _, gateway := range gateways {
+			gatewayID := GatewayID(gateway.GetNamespace(), gateway.GetName())
+			for referenceGrantID, referenceGrantNode := range resourceModel.ReferenceGrants {
+				if !relations.ReferenceGrantExposes(*referenceGrantNode.ReferenceGrant, gatewayObjRef(gatewayID)) {
+					continue
+				}
+				resourceModel.addGateways(gateway)
+				resourceModel.connectReferenceGrantWithGateway(referenceGrantID, gatewayID)
+			}
+		}
+	}
+}
+
+// exposesGateways returns true if the ReferenceGrant permits references to
+// Gateways.
+func exposesGateways(referenceGrant gatewayv1beta1.ReferenceGrant) bool {
+	for _, to := range referenceGrant.Spec.To {
+		if string(to.Group) == gatewayv1.GroupName && to.Kind == "Gateway" {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Discoverer) discoverReferenceGrantsFromBackends(ctx context.Context, resourceModel *ResourceModel) error {
 	referenceGrantsByNamespace := make(map[string][]gatewayv1beta1.ReferenceGrant)
 	for _, backendNode := range resourceModel.Backends {
 		backendNS := backendNode.Backend.GetNamespace()
@@ -459,8 +1299,16 @@ func (d Discoverer) discoverReferenceGrantsFromBackends(ctx context.Context, res
 			var err error
 			referenceGrants, err = d.fetchReferenceGrants(ctx, Filter{Namespace: backendNS, Labels: labels.Everything()})
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "failed to fetch list of ReferenceGrants: %v\n", err)
-				os.Exit(1)
+				if ctx.Err() != nil {
+					// Discovery was cancelled, which is reported by the caller.
+					return nil
+				}
+				if d.skipForbidden(resourceModel, "ReferenceGrants", err) {
+					// Cross namespace references to the Backend will be reported as
+					// not permitted.
+					continue
+				}
+				return fmt.Errorf("failed to fetch list of ReferenceGrants: %w", err)
 			}
 		}
 
@@ -481,10 +1329,134 @@ func (d Discoverer) discoverReferenceGrantsFromBackends(ctx context.Context, res
 			}
 		}
 	}
+	return nil
+}
+
+// discoverReferenceGrantsFromGateways adds the ReferenceGrants which expose
+// Gateways in the resourceModel. These are only needed, and hence only fetched,
+// when ReferenceGrants are required for HTTPRoutes attaching to Gateways in
+// other namespaces.
+func (d Discoverer) discoverReferenceGrantsFromGateways(ctx context.Context, resourceModel *ResourceModel) {
+	if !d.RequireParentReferenceGrants {
+		return
+	}
+
+	referenceGrantsByNamespace := make(map[string][]gatewayv1beta1.ReferenceGrant)
+	for gatewayID, gatewayNode := range resourceModel.Gateways {
+		gatewayNS := gatewayNode.Gateway.GetNamespace()
+
+		referenceGrants, ok := referenceGrantsByNamespace[gatewayNS]
+		if !ok {
+			var err error
+			referenceGrants, err = d.fetchReferenceGrants(ctx, Filter{Namespace: gatewayNS, Labels: labels.Everything()})
+			if err != nil {
+				if !d.skipForbidden(resourceModel, "ReferenceGrants", err) {
+					klog.V(1).ErrorS(err, "Failed to fetch ReferenceGrants for Gateway", "gateway", gatewayNS+"/"+gatewayNode.Gateway.GetName())
+				}
+				continue
+			}
+			referenceGrantsByNamespace[gatewayNS] = referenceGrants
+		}
+
+		gatewayRef := common.ObjRef{
+			Group:     gatewayv1.GroupName,
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNS,
+		}
+		for _, referenceGrant := range referenceGrants {
+			if relations.ReferenceGrantExposes(referenceGrant, gatewayRef) {
+				klog.V(1).InfoS("ReferenceGrant exposes Gateway",
+					"referenceGrant", referenceGrant.GetNamespace()+"/"+referenceGrant.GetName(),
+					"gateway", gatewayRef.Namespace+"/"+gatewayRef.Name,
+				)
+				resourceModel.addReferenceGrants(referenceGrant)
+				resourceModel.connectReferenceGrantWithGateway(ReferenceGrantID(referenceGrant.GetNamespace(), referenceGrant.GetName()), gatewayID)
+			}
+		}
+	}
+}
+
+// verifyCertificateRefGrantsForGateways records an error for each certificateRef
+// of the listeners of Gateways in the resourceModel which references an object
+// in another namespace, without a ReferenceGrant in that namespace permitting
+// the reference. Such listeners are not programmed by implementations.
+// References which cannot be verified because ReferenceGrants could not be
+// fetched are not reported.
+func (d Discoverer) verifyCertificateRefGrantsForGateways(ctx context.Context, resourceModel *ResourceModel) {
+	referenceGrantsByNamespace := make(map[string][]gatewayv1beta1.ReferenceGrant)
+	fetched := make(map[string]bool)
+	for _, gatewayNode := range resourceModel.Gateways {
+		gatewayRef := common.ObjRef{
+			Group:     gatewayv1.GroupName,
+			Kind:      "Gateway",
+			Name:      gatewayNode.Gateway.GetName(),
+			Namespace: gatewayNode.Gateway.GetNamespace(),
+		}
+		reported := make(map[common.ObjRef]bool)
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if listener.TLS == nil {
+				continue
+			}
+			for _, ref := range listener.TLS.CertificateRefs {
+				certificateRef := certificateObjRef(gatewayRef.Namespace, ref)
+				if certificateRef.Namespace == gatewayRef.Namespace || reported[certificateRef] {
+					continue
+				}
+
+				if !fetched[certificateRef.Namespace] {
+					fetched[certificateRef.Namespace] = true
+					referenceGrants, err := d.fetchReferenceGrants(ctx, Filter{Namespace: certificateRef.Namespace, Labels: labels.Everything()})
+					if err != nil {
+						if !d.skipForbidden(resourceModel, "ReferenceGrants", err) {
+							klog.V(1).ErrorS(err, "Failed to fetch ReferenceGrants for certificateRefs", "namespace", certificateRef.Namespace)
+						}
+						continue
+					}
+					referenceGrantsByNamespace[certificateRef.Namespace] = referenceGrants
+				}
+				referenceGrants, ok := referenceGrantsByNamespace[certificateRef.Namespace]
+				if !ok {
+					continue
+				}
+
+				permitted := false
+				for _, referenceGrant := range referenceGrants {
+					if relations.ReferenceGrantExposes(referenceGrant, certificateRef) && relations.ReferenceGrantAccepts(referenceGrant, gatewayRef) {
+						permitted = true
+						break
+					}
+				}
+				if permitted {
+					continue
+				}
+				reported[certificateRef] = true
+				err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: "Gateway", Name: gatewayRef.Name, Namespace: gatewayRef.Namespace},
+					ReferredObject:  certificateRef,
+				}}
+				gatewayNode.Errors = append(gatewayNode.Errors, err)
+				klog.V(1).Info(err)
+			}
+		}
+	}
 }
 
 // discoverPolicies adds Policies for resources that exist in the resourceModel.
+// Kinds of policies which the PolicyManager skipped are recorded as skipped in
+// the resourceModel as well.
 func (d Discoverer) discoverPolicies(resourceModel *ResourceModel) {
+	skippedKinds := d.PolicyManager.SkippedKinds()
+	var kinds []string
+	for kind := range skippedKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		d.recordSkippedKind(resourceModel, kind, skippedKinds[kind])
+	}
+
+	resourceModel.skipDeletingPolicies = d.SkipDeletingPolicies
 	resourceModel.addPolicyIfTargetExists(d.PolicyManager.GetPolicies()...)
 }
 
@@ -492,6 +1464,9 @@ func (d Discoverer) discoverPolicies(resourceModel *ResourceModel) {
 // the resourceModel.
 func (d Discoverer) discoverEventsForGateways(ctx context.Context, resourceModel *ResourceModel) {
 	for _, gatewayNode := range resourceModel.Gateways {
+		if ctx.Err() != nil {
+			return
+		}
 		eventList := &corev1.EventList{}
 		options := &client.ListOptions{
 			FieldSelector: fields.AndSelectors(
@@ -503,8 +1478,10 @@ func (d Discoverer) discoverEventsForGateways(ctx context.Context, resourceModel
 			Limit: maxEventsPerResource,
 		}
 		if err := d.K8sClients.Client.List(ctx, eventList, options); err != nil {
-			klog.V(1).ErrorS(err, "Failed to list events associated with Gateway",
-				"gateway", gatewayNode.Gateway.Namespace+"/"+gatewayNode.Gateway.Name)
+			if !d.skipForbidden(resourceModel, "Events", err) {
+				klog.V(1).ErrorS(err, "Failed to list events associated with Gateway",
+					"gateway", gatewayNode.Gateway.Namespace+"/"+gatewayNode.Gateway.Name)
+			}
 			continue
 		}
 
@@ -512,6 +1489,55 @@ func (d Discoverer) discoverEventsForGateways(ctx context.Context, resourceModel
 	}
 }
 
+// discoverTopologyForBackends will populate the availability zones of the
+// endpoints and the trafficDistribution setting for Backends which are
+// Services.
+func (d Discoverer) discoverTopologyForBackends(ctx context.Context, resourceModel *ResourceModel) {
+	for _, backendNode := range resourceModel.Backends {
+		if ctx.Err() != nil {
+			return
+		}
+		backend := backendNode.Backend
+		if backend.GroupVersionKind().Group != corev1.GroupName || backend.GetKind() != "Service" {
+			continue
+		}
+
+		trafficDistribution, _, err := unstructured.NestedString(backend.UnstructuredContent(), "spec", "trafficDistribution")
+		if err != nil {
+			klog.V(1).ErrorS(err, "Failed to read trafficDistribution of Service",
+				"service", backend.GetNamespace()+"/"+backend.GetName())
+		}
+		backendNode.TrafficDistribution = trafficDistribution
+
+		endpointSliceList := &discoveryv1.EndpointSliceList{}
+		options := []client.ListOption{
+			client.InNamespace(backend.GetNamespace()),
+			client.MatchingLabels{discoveryv1.LabelServiceName: backend.GetName()},
+		}
+		if err := d.K8sClients.Client.List(ctx, endpointSliceList, options...); err != nil {
+			if !d.skipForbidden(resourceModel, "EndpointSlices", err) {
+				klog.V(1).ErrorS(err, "Failed to list EndpointSlices associated with Service",
+					"service", backend.GetNamespace()+"/"+backend.GetName())
+			}
+			continue
+		}
+
+		backendNode.EndpointsDiscovered = true
+		for _, endpointSlice := range endpointSliceList.Items {
+			for _, endpoint := range endpointSlice.Endpoints {
+				// A nil ready condition is to be interpreted as ready.
+				if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+					backendNode.ReadyEndpoints++
+				}
+				if endpoint.Zone == nil || *endpoint.Zone == "" {
+					continue
+				}
+				backendNode.EndpointZones[*endpoint.Zone]++
+			}
+		}
+	}
+}
+
 // fetchGatewayClasses fetches GatewayClasses based on a filter.
 func (d Discoverer) fetchGatewayClasses(ctx context.Context, filter Filter) ([]gatewayv1.GatewayClass, error) {
 	gvr := schema.GroupVersionResource{
@@ -533,6 +1559,7 @@ func (d Discoverer) fetchGatewayClasses(ctx context.Context, filter Filter) ([]g
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(gatewayClassUnstructured.UnstructuredContent(), gatewayClass); err != nil {
 			return []gatewayv1.GatewayClass{}, fmt.Errorf("failed to convert unstructured GatewayClass to structured: %v", err)
 		}
+		recordAPIVersion(&gatewayClass.TypeMeta, gvr, "GatewayClass")
 		return []gatewayv1.GatewayClass{*gatewayClass}, nil
 	}
 
@@ -544,7 +1571,7 @@ func (d Discoverer) fetchGatewayClasses(ctx context.Context, filter Filter) ([]g
 	listOptions := metav1.ListOptions{
 		LabelSelector: labelSelector,
 	}
-	gatewayClassListUnstructured, err := d.K8sClients.DC.Resource(gvr).List(ctx, listOptions)
+	gatewayClassListUnstructured, err := d.listAll(ctx, d.K8sClients.DC.Resource(gvr), "GatewayClasses", listOptions)
 	if err != nil {
 		return []gatewayv1.GatewayClass{}, err
 	}
@@ -552,11 +1579,14 @@ func (d Discoverer) fetchGatewayClasses(ctx context.Context, filter Filter) ([]g
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(gatewayClassListUnstructured.UnstructuredContent(), gatewayClassList); err != nil {
 		return []gatewayv1.GatewayClass{}, fmt.Errorf("failed to convert unstructured GatewayClassList to structured: %v", err)
 	}
+	for i := range gatewayClassList.Items {
+		recordAPIVersion(&gatewayClassList.Items[i].TypeMeta, gvr, "GatewayClass")
+	}
 	return gatewayClassList.Items, nil
 }
 
 // fetchGateways fetches Gateways based on a filter.
-func (d Discoverer) fetchGateways(ctx context.Context, filter Filter) ([]gatewayv1.Gateway, error) {
+func (d Discoverer) fetchGateways(ctx context.Context, filter Filter) ([]fetchedGateway, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    defaultGatewayGroupVersion.Group,
 		Version:  defaultGatewayGroupVersion.Version,
@@ -570,13 +1600,14 @@ func (d Discoverer) fetchGateways(ctx context.Context, filter Filter) ([]gateway
 		// Use Get call.
 		gatewayUnstructured, err := d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace).Get(ctx, filter.Name, metav1.GetOptions{})
 		if err != nil {
-			return []gatewayv1.Gateway{}, err
+			return []fetchedGateway{}, err
 		}
-		gateway := &gatewayv1.Gateway{}
-		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(gatewayUnstructured.UnstructuredContent(), gateway); err != nil {
-			return []gatewayv1.Gateway{}, fmt.Errorf("failed to convert unstructured Gateway to structured: %v", err)
+		gateway, err := newFetchedGateway(gatewayUnstructured)
+		if err != nil {
+			return []fetchedGateway{}, err
 		}
-		return []gatewayv1.Gateway{*gateway}, nil
+		recordAPIVersion(&gateway.TypeMeta, gvr, "Gateway")
+		return []fetchedGateway{gateway}, nil
 	}
 
 	// Use List call.
@@ -587,19 +1618,43 @@ func (d Discoverer) fetchGateways(ctx context.Context, filter Filter) ([]gateway
 	listOptions := metav1.ListOptions{
 		LabelSelector: labelSelector,
 	}
-	gatewayListUnstructured, err := d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace).List(ctx, listOptions)
+	gatewayListUnstructured, err := d.listAll(ctx, d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace), "Gateways", listOptions)
 	if err != nil {
-		return []gatewayv1.Gateway{}, err
+		return []fetchedGateway{}, err
 	}
-	gatewayList := &gatewayv1.GatewayList{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(gatewayListUnstructured.UnstructuredContent(), gatewayList); err != nil {
-		return []gatewayv1.Gateway{}, fmt.Errorf("failed to convert unstructured GatewayList to structured: %v", err)
+	var gateways []fetchedGateway
+	for i := range gatewayListUnstructured.Items {
+		gateway, err := newFetchedGateway(&gatewayListUnstructured.Items[i])
+		if err != nil {
+			return []fetchedGateway{}, err
+		}
+		recordAPIVersion(&gateway.TypeMeta, gvr, "Gateway")
+		gateways = append(gateways, gateway)
 	}
-	return gatewayList.Items, nil
+	return gateways, nil
+}
+
+// fetchedGateway is a Gateway along with its default backends, which are
+// extracted from the unstructured Gateway since the structured type does not
+// have them.
+type fetchedGateway struct {
+	gatewayv1.Gateway
+	defaultBackendRefs []DefaultBackendRef
+}
+
+func newFetchedGateway(gatewayUnstructured *unstructured.Unstructured) (fetchedGateway, error) {
+	gateway := gatewayv1.Gateway{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(gatewayUnstructured.UnstructuredContent(), &gateway); err != nil {
+		return fetchedGateway{}, fmt.Errorf("failed to convert unstructured Gateway to structured: %v", err)
+	}
+	return fetchedGateway{
+		Gateway:            gateway,
+		defaultBackendRefs: gatewayDefaultBackendRefs(gatewayUnstructured),
+	}, nil
 }
 
 // fetchHTTPRoutes fetches HTTPRoutes based on a filter.
-func (d Discoverer) fetchHTTPRoutes(ctx context.Context, filter Filter) ([]gatewayv1.HTTPRoute, error) {
+func (d Discoverer) fetchHTTPRoutes(ctx context.Context, filter Filter) ([]fetchedHTTPRoute, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    defaultHTTPRouteGroupVersion.Group,
 		Version:  defaultHTTPRouteGroupVersion.Version,
@@ -613,13 +1668,14 @@ func (d Discoverer) fetchHTTPRoutes(ctx context.Context, filter Filter) ([]gatew
 		// Use Get call.
 		httpRouteUnstructured, err := d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace).Get(ctx, filter.Name, metav1.GetOptions{})
 		if err != nil {
-			return []gatewayv1.HTTPRoute{}, err
+			return []fetchedHTTPRoute{}, err
 		}
-		httpRoute := &gatewayv1.HTTPRoute{}
-		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(httpRouteUnstructured.UnstructuredContent(), httpRoute); err != nil {
-			return []gatewayv1.HTTPRoute{}, fmt.Errorf("failed to convert unstructured HTTPRoute to structured: %v", err)
+		httpRoute, err := newFetchedHTTPRoute(httpRouteUnstructured)
+		if err != nil {
+			return []fetchedHTTPRoute{}, err
 		}
-		return []gatewayv1.HTTPRoute{*httpRoute}, nil
+		recordAPIVersion(&httpRoute.TypeMeta, gvr, "HTTPRoute")
+		return []fetchedHTTPRoute{httpRoute}, nil
 	}
 
 	// Use List call.
@@ -630,15 +1686,67 @@ func (d Discoverer) fetchHTTPRoutes(ctx context.Context, filter Filter) ([]gatew
 	listOptions := metav1.ListOptions{
 		LabelSelector: labelSelector,
 	}
-	httpRouteListUnstructured, err := d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace).List(ctx, listOptions)
+	httpRouteListUnstructured, err := d.listAll(ctx, d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace), "HTTPRoutes", listOptions)
 	if err != nil {
-		return []gatewayv1.HTTPRoute{}, err
+		return []fetchedHTTPRoute{}, err
 	}
-	httpRouteList := &gatewayv1.HTTPRouteList{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(httpRouteListUnstructured.UnstructuredContent(), httpRouteList); err != nil {
-		return []gatewayv1.HTTPRoute{}, fmt.Errorf("failed to convert unstructured HTTPRouteList to structured: %v", err)
+	var httpRoutes []fetchedHTTPRoute
+	for i := range httpRouteListUnstructured.Items {
+		httpRoute, err := newFetchedHTTPRoute(&httpRouteListUnstructured.Items[i])
+		if err != nil {
+			return []fetchedHTTPRoute{}, err
+		}
+		recordAPIVersion(&httpRoute.TypeMeta, gvr, "HTTPRoute")
+		httpRoutes = append(httpRoutes, httpRoute)
+	}
+	return httpRoutes, nil
+}
+
+// fetchedHTTPRoute is an HTTPRoute along with the summaries of its filters.
+// Filters are summarized from the unstructured HTTPRoute since the structured
+// type drops filters introduced in newer API versions.
+type fetchedHTTPRoute struct {
+	gatewayv1.HTTPRoute
+	filters           []FilterSummary
+	ruleNames         []string
+	namedBackendPorts []NamedBackendPort
+}
+
+func newFetchedHTTPRoute(httpRouteUnstructured *unstructured.Unstructured) (fetchedHTTPRoute, error) {
+	// Named ports can not be converted to the structured type, so they are
+	// removed before the conversion.
+	namedBackendPorts, convertible := extractNamedBackendPorts(httpRouteUnstructured)
+	httpRoute := gatewayv1.HTTPRoute{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(convertible.UnstructuredContent(), &httpRoute); err != nil {
+		return fetchedHTTPRoute{}, fmt.Errorf("failed to convert unstructured HTTPRoute to structured: %v", err)
+	}
+	filters, err := SummarizeHTTPRouteFilters(httpRouteUnstructured)
+	if err != nil {
+		return fetchedHTTPRoute{}, fmt.Errorf("failed to summarize filters of HTTPRoute %v/%v: %v", httpRoute.GetNamespace(), httpRoute.GetName(), err)
+	}
+	return fetchedHTTPRoute{
+		HTTPRoute:         httpRoute,
+		filters:           filters,
+		ruleNames:         httpRouteRuleNames(httpRouteUnstructured),
+		namedBackendPorts: namedBackendPorts,
+	}, nil
+}
+
+// httpRouteRuleNames returns the names of the rules of the unstructured
+// HTTPRoute, with an empty string for rules without a name. Rule names were
+// introduced in a newer version of the Gateway API than the one gwctl is built
+// against, so they are not part of the structured HTTPRouteRule type.
+func httpRouteRuleNames(httpRoute *unstructured.Unstructured) []string {
+	rules, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+	result := make([]string, len(rules))
+	for i, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result[i], _, _ = unstructured.NestedString(ruleMap, "name")
 	}
-	return httpRouteList.Items, nil
+	return result
 }
 
 // fetchHTTPRoutes fetches HTTPRoutes based on a filter.
@@ -662,6 +1770,7 @@ func (d Discoverer) fetchReferenceGrants(ctx context.Context, filter Filter) ([]
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(referenceGrantUnstructured.UnstructuredContent(), referenceGrant); err != nil {
 			return []gatewayv1beta1.ReferenceGrant{}, fmt.Errorf("failed to convert unstructured ReferenceGrant to structured: %v", err)
 		}
+		recordAPIVersion(&referenceGrant.TypeMeta, gvr, "ReferenceGrant")
 		return []gatewayv1beta1.ReferenceGrant{*referenceGrant}, nil
 	}
 
@@ -669,7 +1778,7 @@ func (d Discoverer) fetchReferenceGrants(ctx context.Context, filter Filter) ([]
 	listOptions := metav1.ListOptions{
 		LabelSelector: filter.Labels.String(),
 	}
-	referenceGrantListUnstructured, err := d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace).List(ctx, listOptions)
+	referenceGrantListUnstructured, err := d.listAll(ctx, d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace), "ReferenceGrants", listOptions)
 	if err != nil {
 		return []gatewayv1beta1.ReferenceGrant{}, err
 	}
@@ -677,6 +1786,9 @@ func (d Discoverer) fetchReferenceGrants(ctx context.Context, filter Filter) ([]
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(referenceGrantListUnstructured.UnstructuredContent(), referenceGrantList); err != nil {
 		return []gatewayv1beta1.ReferenceGrant{}, fmt.Errorf("failed to convert unstructured ReferenceGrantList to structured: %v", err)
 	}
+	for i := range referenceGrantList.Items {
+		recordAPIVersion(&referenceGrantList.Items[i].TypeMeta, gvr, "ReferenceGrant")
+	}
 	return referenceGrantList.Items, nil
 }
 
@@ -710,14 +1822,72 @@ func (d Discoverer) fetchBackends(ctx context.Context, filter Filter) ([]unstruc
 		LabelSelector: labelSelector,
 	}
 	var backendsList *unstructured.UnstructuredList
-	backendsList, err := d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace).List(ctx, listOptions)
+	backendsList, err := d.listAll(ctx, d.K8sClients.DC.Resource(gvr).Namespace(filter.Namespace), "Backends", listOptions)
 	if err != nil {
 		return nil, err
 	}
+	// The items of lists of built-in types don't necessarily carry their kind,
+	// unlike the objects returned by Get calls.
+	for i := range backendsList.Items {
+		if backendsList.Items[i].GetKind() == "" {
+			backendsList.Items[i].SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+		}
+	}
 
 	return backendsList.Items, nil
 }
 
+// fetchCRDs fetches all CustomResourceDefinitions.
+func (d Discoverer) fetchCRDs(ctx context.Context) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	gvr := schema.GroupVersionResource{Group: apiextensionsv1.GroupName, Version: "v1", Resource: "customresourcedefinitions"}
+	unstructuredCRDs, err := d.K8sClients.DC.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %v", err)
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredCRDs.UnstructuredContent(), crds); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured CRDs to structured: %v", err)
+	}
+	return crds.Items, nil
+}
+
+// fetchExtensionRef fetches the object referenced by an ExtensionRef filter of
+// an HTTPRoute within namespace. The resource of the referenced kind is looked
+// up in crds, since ExtensionRefs always reference implementation specific
+// custom resources.
+func (d Discoverer) fetchExtensionRef(ctx context.Context, crds []apiextensionsv1.CustomResourceDefinition, namespace string, ref gatewayv1.LocalObjectReference) (*unstructured.Unstructured, error) {
+	return d.fetchCustomResource(ctx, crds, string(ref.Group), string(ref.Kind), namespace, string(ref.Name))
+}
+
+// fetchCustomResource fetches the custom resource of the given kind and name.
+// The resource of the kind is looked up in crds. The namespace is ignored for
+// cluster scoped kinds.
+func (d Discoverer) fetchCustomResource(ctx context.Context, crds []apiextensionsv1.CustomResourceDefinition, group, kind, namespace, name string) (*unstructured.Unstructured, error) {
+	for _, crd := range crds {
+		if crd.Spec.Group != group || crd.Spec.Names.Kind != kind || len(crd.Spec.Versions) == 0 {
+			continue
+		}
+		gvr := schema.GroupVersionResource{
+			Group:    crd.Spec.Group,
+			Version:  crd.Spec.Versions[0].Name,
+			Resource: crd.Spec.Names.Plural, // CRD Kinds directly map to the Resource.
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Storage {
+				gvr.Version = version.Name
+			}
+		}
+
+		var resourceInterface dynamic.ResourceInterface = d.K8sClients.DC.Resource(gvr)
+		if crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+			resourceInterface = d.K8sClients.DC.Resource(gvr).Namespace(namespace)
+		}
+		return resourceInterface.Get(ctx, name, metav1.GetOptions{})
+	}
+	return nil, fmt.Errorf("no CustomResourceDefinition found for %v.%v", kind, group)
+}
+
 // fetchNamespace fetches Namespaces based on a filter.
 func (d Discoverer) fetchNamespace(ctx context.Context, filter Filter) ([]corev1.Namespace, error) {
 	if filter.Name != "" {
@@ -736,10 +1906,116 @@ func (d Discoverer) fetchNamespace(ctx context.Context, filter Filter) ([]corev1
 		Namespace:     filter.Namespace,
 		LabelSelector: filter.Labels,
 	}
-	namespacesList := &corev1.NamespaceList{}
-	if err := d.K8sClients.Client.List(ctx, namespacesList, options); err != nil {
+	namespaces, err := d.listNamespaces(ctx, options)
+	if err != nil {
 		return []corev1.Namespace{}, err
 	}
 
-	return namespacesList.Items, nil
+	return namespaces, nil
+}
+
+// listAll fetches all resources matching the listOptions, one page at a time.
+// The context is checked between pages so that a cancelled discovery stops
+// promptly.
+func (d Discoverer) listAll(ctx context.Context, resourceInterface dynamic.ResourceInterface, kind string, listOptions metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	result := &unstructured.UnstructuredList{}
+	listOptions.Limit = listPageSize
+	for {
+		if err := d.waitForNextPage(ctx); err != nil {
+			return nil, err
+		}
+
+		page, err := resourceInterface.List(ctx, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Items) == 0 {
+			result.Object = page.Object
+		}
+		result.Items = append(result.Items, page.Items...)
+
+		d.reportProgress(kind, len(result.Items), page.GetContinue(), page.GetRemainingItemCount())
+
+		if page.GetContinue() == "" {
+			return result, nil
+		}
+		listOptions.Continue = page.GetContinue()
+	}
+}
+
+// listNamespaces fetches all Namespaces matching the options, one page at a
+// time.
+func (d Discoverer) listNamespaces(ctx context.Context, options *client.ListOptions) ([]corev1.Namespace, error) {
+	var result []corev1.Namespace
+	options.Limit = listPageSize
+	for {
+		if err := d.waitForNextPage(ctx); err != nil {
+			return nil, err
+		}
+
+		page := &corev1.NamespaceList{}
+		if err := d.K8sClients.Client.List(ctx, page, options); err != nil {
+			return nil, err
+		}
+		result = append(result, page.Items...)
+
+		d.reportProgress("Namespaces", len(result), page.GetContinue(), page.GetRemainingItemCount())
+
+		if page.GetContinue() == "" {
+			return result, nil
+		}
+		options.Continue = page.GetContinue()
+	}
+}
+
+// waitForNextPage returns an error if the context has been cancelled, and
+// otherwise blocks until the RateLimiter permits the next List call.
+func (d Discoverer) waitForNextPage(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d.RateLimiter != nil {
+		return d.RateLimiter.Wait(ctx)
+	}
+	return nil
+}
+
+// skipForbidden returns true if SkipForbidden is set and fetching resources of
+// the kind failed due to missing permissions, in which case the kind is
+// recorded as skipped in the resourceModel.
+func (d Discoverer) skipForbidden(resourceModel *ResourceModel, kind string, err error) bool {
+	if !d.SkipForbidden || !apierrors.IsForbidden(err) {
+		return false
+	}
+	d.recordSkippedKind(resourceModel, kind, err)
+	return true
+}
+
+// recordSkippedKind records the kind as skipped in the resourceModel, and
+// calls Warn unless the kind was skipped before.
+func (d Discoverer) recordSkippedKind(resourceModel *ResourceModel, kind string, err error) {
+	if _, ok := resourceModel.SkippedKinds[kind]; ok {
+		return
+	}
+	if resourceModel.SkippedKinds == nil {
+		resourceModel.SkippedKinds = make(map[string]error)
+	}
+	resourceModel.SkippedKinds[kind] = err
+	klog.V(1).ErrorS(err, "Skipping resources which can not be fetched", "kind", kind)
+	if d.Warn != nil {
+		d.Warn(kind, err)
+	}
+}
+
+func (d Discoverer) reportProgress(kind string, fetched int, continueToken string, remainingItemCount *int64) {
+	if d.Progress == nil {
+		return
+	}
+	total := -1
+	if continueToken == "" {
+		total = fetched
+	} else if remainingItemCount != nil {
+		total = fetched + int(*remainingItemCount)
+	}
+	d.Progress(kind, fetched, total)
 }
@@ -17,21 +17,36 @@ limitations under the License.
 package resourcediscovery
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	goruntime "runtime"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	fakedynamicclient "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
 	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
 )
 
@@ -109,7 +124,7 @@ func TestDiscoverResourcesForGateway(t *testing.T) {
 				PolicyManager: params.PolicyManager,
 			}
 
-			resourceModel, err := discoverer.DiscoverResourcesForGateway(tc.filter)
+			resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), tc.filter)
 			if err != nil {
 				t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
 			}
@@ -317,7 +332,7 @@ func TestDiscoverResourcesForBackend(t *testing.T) {
 				PolicyManager: params.PolicyManager,
 			}
 
-			resourceModel, err := discoverer.DiscoverResourcesForBackend(tc.filter)
+			resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), tc.filter)
 			if err != nil {
 				t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
 			}
@@ -339,6 +354,436 @@ func TestDiscoverResourcesForBackend(t *testing.T) {
 	}
 }
 
+func TestDiscoverResourcesForBackend_Topology(t *testing.T) {
+	endpointSliceForTest := func(name, serviceName string, zones ...string) *discoveryv1.EndpointSlice {
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: serviceName},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+		for _, zone := range zones {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+				Addresses: []string{"10.0.0.1"},
+				Zone:      common.PtrTo(zone),
+			})
+		}
+		return endpointSlice
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "multi-zone-svc",
+				Namespace: "default",
+			},
+			Spec: corev1.ServiceSpec{
+				TrafficDistribution: common.PtrTo(corev1.ServiceTrafficDistributionPreferClose),
+			},
+		},
+		endpointSliceForTest("multi-zone-svc-abc", "multi-zone-svc", "zone-a", "zone-b"),
+		endpointSliceForTest("multi-zone-svc-def", "multi-zone-svc", "zone-b"),
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "single-zone-svc",
+				Namespace: "default",
+			},
+		},
+		endpointSliceForTest("single-zone-svc-abc", "single-zone-svc", "zone-a"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
+	}
+
+	type topology struct {
+		EndpointZones       map[string]int
+		TrafficDistribution string
+	}
+	got := map[string]topology{}
+	for _, backendNode := range resourceModel.Backends {
+		got[backendNode.Backend.GetName()] = topology{
+			EndpointZones:       backendNode.EndpointZones,
+			TrafficDistribution: backendNode.TrafficDistribution,
+		}
+	}
+	want := map[string]topology{
+		"multi-zone-svc": {
+			EndpointZones:       map[string]int{"zone-a": 1, "zone-b": 2},
+			TrafficDistribution: corev1.ServiceTrafficDistributionPreferClose,
+		},
+		"single-zone-svc": {
+			EndpointZones: map[string]int{"zone-a": 1},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in Backend topology; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestDiscoverResourcesForHTTPRoute_MeshRoute(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-mesh-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Group: common.PtrTo(gatewayv1.Group("")),
+						Kind:  common.PtrTo(gatewayv1.Kind("Service")),
+						Name:  "foo-svc",
+					}},
+				},
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name": "timeout-policy-namespace",
+				},
+				"spec": map[string]interface{}{
+					"condition": "path=/abc",
+					"seconds":   int64(30),
+					"targetRef": map[string]interface{}{
+						"kind": "Namespace",
+						"name": "default",
+					},
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name": "timeout-policy-httproute",
+				},
+				"spec": map[string]interface{}{
+					"seconds": int64(60),
+					"targetRef": map[string]interface{}{
+						"group":     "gateway.networking.k8s.io",
+						"kind":      "HTTPRoute",
+						"name":      "foo-mesh-httproute",
+						"namespace": "default",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", resourceModel)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-mesh-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-mesh-httproute not found in resourceModel")
+	}
+	if !httpRouteNode.IsMeshRoute() || httpRouteNode.IsIngressRoute() {
+		t.Errorf("IsMeshRoute()=%v, IsIngressRoute()=%v; want IsMeshRoute()=true, IsIngressRoute()=false", httpRouteNode.IsMeshRoute(), httpRouteNode.IsIngressRoute())
+	}
+	if len(resourceModel.Gateways) != 0 {
+		t.Errorf("Unexpected Gateways in resourceModel: %v", resourceModel.Gateways)
+	}
+
+	serviceID := BackendIDForService("default", "foo-svc")
+	if _, ok := httpRouteNode.ParentServices[serviceID]; !ok {
+		t.Errorf("HTTPRoute is not connected to its parent Service; ParentServices=%v", httpRouteNode.ParentServices)
+	}
+	if _, ok := resourceModel.Backends[serviceID].MeshHTTPRoutes[httpRouteNode.ID()]; !ok {
+		t.Errorf("Service is not connected to the mesh HTTPRoute; MeshHTTPRoutes=%v", resourceModel.Backends[serviceID].MeshHTTPRoutes)
+	}
+
+	gotPolicy, ok := httpRouteNode.MeshEffectivePolicies["TimeoutPolicy.bar.com"]
+	if !ok {
+		t.Fatalf("TimeoutPolicy.bar.com not found in MeshEffectivePolicies=%v", httpRouteNode.MeshEffectivePolicies)
+	}
+	got, err := gotPolicy.EffectiveSpec()
+	if err != nil {
+		t.Fatalf("Failed to get EffectiveSpec: %v", err)
+	}
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	want := map[string]interface{}{
+		"condition": "path=/abc",
+		"seconds":   float64(60),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in MeshEffectivePolicies; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestDiscoverResourcesForHTTPRoute_Progress(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute-1",
+				Namespace: "default",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute-2",
+				Namespace: "default",
+			},
+		},
+	}
+
+	type progress struct {
+		Kind           string
+		Fetched, Total int
+	}
+	var got []progress
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+		Progress: func(kind string, fetched, total int) {
+			got = append(got, progress{Kind: kind, Fetched: fetched, Total: total})
+		},
+	}
+
+	if _, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()}); err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	want := []progress{
+		{Kind: "HTTPRoutes", Fetched: 2, Total: 2},
+		{Kind: "GatewayClasses", Fetched: 0, Total: 0},
+		{Kind: "Namespaces", Fetched: 1, Total: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in progress; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestDiscoverResourcesForHTTPRoute_Cancelled(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+		},
+	}
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+
+	goroutinesBefore := goruntime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var kinds []string
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+		Progress: func(kind string, _, _ int) {
+			kinds = append(kinds, kind)
+			// Cancel discovery as soon as the first resources are fetched.
+			cancel()
+		},
+	}
+
+	_, err := discoverer.DiscoverResourcesForHTTPRoute(ctx, Filter{Labels: labels.Everything()})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DiscoverResourcesForHTTPRoute() returned err=%v; want %v", err, context.Canceled)
+	}
+	// Nothing should be fetched after the cancellation.
+	if diff := cmp.Diff([]string{"HTTPRoutes"}, kinds); diff != "" {
+		t.Errorf("Unexpected resources fetched after cancellation; diff (-want +got)=\n%v", diff)
+	}
+
+	// Discovery does not start any goroutines, so none should be left behind.
+	if goroutinesAfter := goruntime.NumGoroutine(); goroutinesAfter > goroutinesBefore {
+		t.Errorf("Goroutines leaked by cancelled discovery; before=%v, after=%v", goroutinesBefore, goroutinesAfter)
+	}
+}
+
+func TestDiscoverResourcesForGateway_TargetSelectorPolicy(t *testing.T) {
+	gatewayForTest := func(name string, labels map[string]string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		}
+	}
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gatewayForTest("foo-gateway-1", map[string]string{"env": "prod"}),
+		gatewayForTest("foo-gateway-2", map[string]string{"env": "prod", "tier": "edge"}),
+		gatewayForTest("foo-gateway-3", map[string]string{"env": "dev"}),
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      "timeout-policy-prod",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"seconds": int64(30),
+					"targetSelector": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"matchLabels": map[string]interface{}{
+							"env": "prod",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name                 string
+		enableTargetSelector bool
+		wantGateways         []string
+	}{
+		{
+			name:                 "targetSelector enabled for policy kind",
+			enableTargetSelector: true,
+			wantGateways:         []string{"foo-gateway-1", "foo-gateway-2"},
+		},
+		{
+			name:                 "targetSelector not enabled for policy kind",
+			enableTargetSelector: false,
+			wantGateways:         nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClients := common.MustClientsForTest(t, objects...)
+			policyManager := policymanager.New(k8sClients.DC)
+			if tc.enableTargetSelector {
+				policyManager.EnableTargetSelector("TimeoutPolicy.bar.com")
+			}
+			if err := policyManager.Init(context.Background()); err != nil {
+				t.Fatalf("Failed to initialize PolicyManager: %v", err)
+			}
+			discoverer := Discoverer{
+				K8sClients:    k8sClients,
+				PolicyManager: policyManager,
+			}
+
+			resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+			if errs := resourceModel.Validate(); len(errs) != 0 {
+				t.Errorf("resourceModel.Validate() returned errors: %v", errs)
+			}
+
+			var gotGateways []string
+			for _, gatewayNode := range resourceModel.Gateways {
+				if _, ok := gatewayNode.Policies[PolicyID("bar.com", "TimeoutPolicy", "default", "timeout-policy-prod")]; !ok {
+					continue
+				}
+				gotGateways = append(gotGateways, gatewayNode.Gateway.GetName())
+
+				// The policy must be part of the effective policies of every
+				// Gateway it is attached to.
+				if _, ok := gatewayNode.EffectivePolicies["TimeoutPolicy.bar.com"]; !ok {
+					t.Errorf("TimeoutPolicy.bar.com missing from EffectivePolicies of Gateway %v", gatewayNode.Gateway.GetName())
+				}
+			}
+			sort.Strings(gotGateways)
+
+			if diff := cmp.Diff(tc.wantGateways, gotGateways); diff != "" {
+				t.Errorf("Unexpected diff in Gateways with policy attached; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotGateways, tc.wantGateways, diff)
+			}
+		})
+	}
+}
+
 // TestDiscoverResourcesForGatewayClass_LabelSelector Tests label selector filtering for GatewayClasses.
 func TestDiscoverResourcesForGatewayClass_LabelSelector(t *testing.T) {
 	fakeClock := testingclock.NewFakeClock(time.Now())
@@ -379,7 +824,7 @@ func TestDiscoverResourcesForGatewayClass_LabelSelector(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unable to find resources that match the label selector \"%s\": %v\n", labelSelector, err)
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(Filter{Labels: selector})
+	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(context.Background(), Filter{Labels: selector})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -456,7 +901,7 @@ func TestDiscoverResourcesForGateway_LabelSelector(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unable to find resources that match the label selector \"%s\": %v\n", labelSelector, err)
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForGateway(Filter{Labels: selector})
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: selector})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -533,7 +978,7 @@ func TestDiscoverResourcesForHTTPRoute_LabelSelector(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unable to find resources that match the label selector \"%s\": %v\n", labelSelector, err)
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(Filter{Labels: selector})
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: selector})
 	if err != nil {
 		t.Fatalf("Failed to discover resources: %v", err)
 	}
@@ -582,7 +1027,7 @@ func TestDiscoverResourcesForNamespace_LabelSelector(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unable to find resources that match the label selector \"%s\": %v\n", labelSelector, err)
 	}
-	resourceModel, err := discoverer.DiscoverResourcesForNamespace(Filter{Labels: selector})
+	resourceModel, err := discoverer.DiscoverResourcesForNamespace(context.Background(), Filter{Labels: selector})
 	if err != nil {
 		t.Fatalf("Failed to construct resourceModel: %v", err)
 	}
@@ -598,6 +1043,63 @@ func TestDiscoverResourcesForNamespace_LabelSelector(t *testing.T) {
 	}
 }
 
+func TestDiscoverResourcesForNamespace_Contents(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "foo-svc"},
+					}}},
+				}},
+			},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "unused-svc", Namespace: "default"}},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	// Listing Namespaces does not discover their contents.
+	resourceModel, err := discoverer.DiscoverResourcesForNamespace(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	if len(resourceModel.Gateways)+len(resourceModel.HTTPRoutes)+len(resourceModel.Backends) != 0 {
+		t.Errorf("DiscoverResourcesForNamespace() discovered the contents of the Namespaces; got %v Gateways, %v HTTPRoutes and %v Backends", len(resourceModel.Gateways), len(resourceModel.HTTPRoutes), len(resourceModel.Backends))
+	}
+
+	resourceModel, err = discoverer.DiscoverResourcesForNamespaceContents(context.Background(), Filter{Name: "default", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	namespaceNode := resourceModel.Namespaces[NamespaceID("default")]
+	if namespaceNode == nil {
+		t.Fatalf("Namespace default was not discovered")
+	}
+	var gotBackends []string
+	for _, backendNode := range namespaceNode.Backends {
+		gotBackends = append(gotBackends, backendNode.Backend.GetName())
+	}
+	// Only the Services referenced by HTTPRoutes are backends of the Namespace.
+	if diff := cmp.Diff([]string{"foo-svc"}, gotBackends); diff != "" {
+		t.Errorf("Unexpected diff in Backends of Namespace default; diff (-want +got)=\n%v", diff)
+	}
+	if len(namespaceNode.Gateways) != 1 || len(namespaceNode.HTTPRoutes) != 1 {
+		t.Errorf("Namespace default has %v Gateways and %v HTTPRoutes; want 1 of each", len(namespaceNode.Gateways), len(namespaceNode.HTTPRoutes))
+	}
+}
+
 func namespacedGatewaysFromResourceModel(r *ResourceModel) []apimachinerytypes.NamespacedName {
 	var gateways []apimachinerytypes.NamespacedName
 	for _, gatewayNode := range r.Gateways {
@@ -642,3 +1144,1014 @@ func gatewayErrorsFromResourceModel(r *ResourceModel) map[apimachinerytypes.Name
 	}
 	return result
 }
+
+func TestDiscoverResourcesForHTTPRoute_ExtensionRef(t *testing.T) {
+	extensionRefFilter := func(name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{
+				Group: "example.com",
+				Kind:  "WAFConfig",
+				Name:  gatewayv1.ObjectName(name),
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "wafconfigs.example.com",
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "example.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "wafconfigs",
+					Kind:   "WAFConfig",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "WAFConfig",
+				"metadata": map[string]interface{}{
+					"name":      "strict-waf",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"mode":    "Block",
+					"ruleSet": "owasp-crs",
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{
+					{Filters: []gatewayv1.HTTPRouteFilter{extensionRefFilter("strict-waf")}},
+					{Filters: []gatewayv1.HTTPRouteFilter{extensionRefFilter("missing-waf")}},
+				},
+			},
+		},
+	}
+
+	wafConfigGroupKind := schema.GroupKind{Group: "example.com", Kind: "WAFConfig"}
+	RegisterExtensionRefSummarizer(wafConfigGroupKind, func(object *unstructured.Unstructured) string {
+		mode, _, _ := unstructured.NestedString(object.Object, "spec", "mode")
+		ruleSet, _, _ := unstructured.NestedString(object.Object, "spec", "ruleSet")
+		return fmt.Sprintf("mode=%v ruleSet=%v", mode, ruleSet)
+	})
+	t.Cleanup(func() { delete(extensionRefSummarizers, wafConfigGroupKind) })
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-httproute not found in resourceModel")
+	}
+
+	extensionRefID := ExtensionRefID("example.com", "WAFConfig", "default", "strict-waf")
+	extensionRefNode, ok := httpRouteNode.ExtensionRefs[extensionRefID]
+	if !ok {
+		t.Fatalf("HTTPRoute is not connected to WAFConfig default/strict-waf; ExtensionRefs=%v", httpRouteNode.ExtensionRefs)
+	}
+	if _, ok := extensionRefNode.HTTPRoutes[httpRouteNode.ID()]; !ok {
+		t.Errorf("WAFConfig is not connected to the HTTPRoute; HTTPRoutes=%v", extensionRefNode.HTTPRoutes)
+	}
+	if got, want := extensionRefNode.Summary(), "mode=Block ruleSet=owasp-crs"; got != want {
+		t.Errorf("Summary()=%q; want %q", got, want)
+	}
+	if got, want := len(resourceModel.ExtensionRefs), 1; got != want {
+		t.Errorf("len(resourceModel.ExtensionRefs)=%v; want %v", got, want)
+	}
+
+	wantErrors := []error{
+		UnresolvedExtensionRefError{
+			RuleIndex:    1,
+			ExtensionRef: common.ObjRef{Group: "example.com", Kind: "WAFConfig", Name: "missing-waf", Namespace: "default"},
+			Reason:       `wafconfigs.example.com "missing-waf" not found`,
+		},
+	}
+	if diff := cmp.Diff(wantErrors, httpRouteNode.Errors, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", httpRouteNode.Errors, wantErrors, diff)
+	}
+}
+
+func TestDiscoverResourcesForHTTPRoute_Secrets(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "authfilters.example.com",
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "example.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "authfilters",
+					Kind:   "AuthFilter",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "AuthFilter",
+				"metadata": map[string]interface{}{
+					"name":      "oidc",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"oidc": map[string]interface{}{
+						"issuer":          "https://auth.example.com",
+						"clientSecretRef": map[string]interface{}{"name": "oidc-secret"},
+					},
+				},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "oidc-secret",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{"client-secret": []byte("hunter2")},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{
+						{
+							Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+							ExtensionRef: &gatewayv1.LocalObjectReference{Group: "example.com", Kind: "AuthFilter", Name: "oidc"},
+						},
+						// References a Secret directly, which does not exist.
+						{
+							Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+							ExtensionRef: &gatewayv1.LocalObjectReference{Kind: "Secret", Name: "missing-secret"},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	var gotEdges []string
+	for _, edge := range resourceModel.SortedEdges() {
+		if _, ok := edge.To.(*SecretNode); ok {
+			gotEdges = append(gotEdges, edge.From.NodeID()+" -> "+edge.To.NodeID())
+		}
+	}
+	wantEdges := []string{
+		"HTTPRoute/default/foo-httproute -> Secret/default/missing-secret",
+		"HTTPRoute/default/foo-httproute -> Secret/default/oidc-secret",
+	}
+	if diff := cmp.Diff(wantEdges, gotEdges); diff != "" {
+		t.Errorf("Unexpected diff in edges to Secrets; diff (-want +got)=\n%v", diff)
+	}
+
+	oidcSecretNode := resourceModel.Secrets[SecretID("default", "oidc-secret")]
+	if oidcSecretNode == nil || oidcSecretNode.Missing {
+		t.Fatalf("Secret default/oidc-secret is not resolved; Secrets=%v", resourceModel.Secrets)
+	}
+	if oidcSecretNode.Secret.Data != nil {
+		t.Errorf("Data of Secret default/oidc-secret is part of the ResourceModel")
+	}
+	if missingSecretNode := resourceModel.Secrets[SecretID("default", "missing-secret")]; missingSecretNode == nil || !missingSecretNode.Missing {
+		t.Errorf("Secret default/missing-secret is not marked as missing; Secrets=%v", resourceModel.Secrets)
+	}
+
+	httpRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	wantErrors := []error{
+		ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+			ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+			ReferredObject:  common.ObjRef{Kind: "Secret", Name: "missing-secret", Namespace: "default"},
+		}},
+	}
+	if diff := cmp.Diff(wantErrors, httpRouteNode.Errors, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", httpRouteNode.Errors, wantErrors, diff)
+	}
+}
+
+// TestDiscoverResourcesForHTTPRoute_SecretsCrossNamespace verifies that only
+// the metadata of Secrets is kept, and that Secrets in another namespace are
+// only followed if a ReferenceGrant permits the reference.
+func TestDiscoverResourcesForHTTPRoute_SecretsCrossNamespace(t *testing.T) {
+	secret := func(namespace, name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"tls.key":"aHVudGVyMg=="}}`,
+				},
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+			Data: map[string][]byte{"tls.key": []byte("hunter2")},
+		}
+	}
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("certs"),
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "authfilters.example.com",
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "example.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "authfilters",
+					Kind:   "AuthFilter",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "AuthFilter",
+				"metadata": map[string]interface{}{
+					"name":      "mtls",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"clientCertificateSecretRef": map[string]interface{}{"name": "granted-secret", "namespace": "certs"},
+					"caSecretRef":                map[string]interface{}{"name": "ungranted-secret", "namespace": "certs"},
+				},
+			},
+		},
+		secret("certs", "granted-secret"),
+		secret("certs", "ungranted-secret"),
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-authfilters", Namespace: "certs"},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: "example.com", Kind: "AuthFilter", Namespace: "default"}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Secret", Name: common.PtrTo(gatewayv1.ObjectName("granted-secret"))}},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+						ExtensionRef: &gatewayv1.LocalObjectReference{Group: "example.com", Kind: "AuthFilter", Name: "mtls"},
+					}},
+				}},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	if _, ok := resourceModel.Secrets[SecretID("certs", "ungranted-secret")]; ok {
+		t.Errorf("Secret certs/ungranted-secret is part of the ResourceModel without a ReferenceGrant")
+	}
+	grantedSecretNode := resourceModel.Secrets[SecretID("certs", "granted-secret")]
+	if grantedSecretNode == nil || grantedSecretNode.Missing {
+		t.Fatalf("Secret certs/granted-secret is not resolved; Secrets=%v", resourceModel.Secrets)
+	}
+	if grantedSecretNode.Secret.Data != nil || grantedSecretNode.Secret.Annotations != nil || grantedSecretNode.Secret.ManagedFields != nil {
+		t.Errorf("Secret certs/granted-secret kept more than its metadata; got %+v", grantedSecretNode.Secret)
+	}
+
+	httpRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	wantErrors := []error{
+		ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+			ReferringObject: common.ObjRef{Kind: "AuthFilter", Name: "mtls", Namespace: "default"},
+			ReferredObject:  common.ObjRef{Kind: "Secret", Name: "ungranted-secret", Namespace: "certs"},
+		}},
+	}
+	if diff := cmp.Diff(wantErrors, httpRouteNode.Errors, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", httpRouteNode.Errors, wantErrors, diff)
+	}
+}
+
+func TestDiscoverResourcesForGatewayClass_ParametersRef(t *testing.T) {
+	gatewayClass := func(name string, parametersRef *gatewayv1.ParametersReference) *gatewayv1.GatewayClass {
+		return &gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: gatewayv1.GatewayClassSpec{
+				ControllerName: "example.net/gateway-controller",
+				ParametersRef:  parametersRef,
+			},
+		}
+	}
+	proxyConfigRef := func(name string) *gatewayv1.ParametersReference {
+		return &gatewayv1.ParametersReference{
+			Group:     "example.com",
+			Kind:      "ProxyConfig",
+			Name:      name,
+			Namespace: common.PtrTo(gatewayv1.Namespace("infra")),
+		}
+	}
+
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "proxyconfigs.example.com",
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "example.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "proxyconfigs",
+					Kind:   "ProxyConfig",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "ProxyConfig",
+				"metadata": map[string]interface{}{
+					"name":      "foo-config",
+					"namespace": "infra",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+				},
+			},
+		},
+		gatewayClass("foo-gatewayclass", proxyConfigRef("foo-config")),
+		gatewayClass("bar-gatewayclass", proxyConfigRef("missing-config")),
+		gatewayClass("baz-gatewayclass", nil),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForGatewayClass(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	fooNode := resourceModel.GatewayClasses[GatewayClassID("foo-gatewayclass")]
+	if fooNode.Parameters == nil {
+		t.Fatalf("Parameters of foo-gatewayclass were not resolved; Errors=%v", fooNode.Errors)
+	}
+	if got, want := fooNode.Parameters.GetName(), "foo-config"; got != want {
+		t.Errorf("Parameters.GetName()=%q; want %q", got, want)
+	}
+	if len(fooNode.Errors) != 0 {
+		t.Errorf("Unexpected Errors of foo-gatewayclass: %v", fooNode.Errors)
+	}
+
+	barNode := resourceModel.GatewayClasses[GatewayClassID("bar-gatewayclass")]
+	if barNode.Parameters != nil {
+		t.Errorf("Parameters of bar-gatewayclass=%v; want nil", barNode.Parameters)
+	}
+	wantErrors := []error{
+		UnresolvedParametersRefError{
+			ParametersRef: common.ObjRef{Group: "example.com", Kind: "ProxyConfig", Name: "missing-config", Namespace: "infra"},
+			Reason:        `proxyconfigs.example.com "missing-config" not found`,
+		},
+	}
+	if diff := cmp.Diff(wantErrors, barNode.Errors, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", barNode.Errors, wantErrors, diff)
+	}
+
+	bazNode := resourceModel.GatewayClasses[GatewayClassID("baz-gatewayclass")]
+	if bazNode.Parameters != nil || len(bazNode.Errors) != 0 {
+		t.Errorf("baz-gatewayclass has no parametersRef; got Parameters=%v, Errors=%v", bazNode.Parameters, bazNode.Errors)
+	}
+}
+
+func TestDiscoverResourcesForGateway_NamespaceLabels(t *testing.T) {
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"env": "prod", "team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"env": "dev", "team": "b"}}},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway-1",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "gatewayclass-1",
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	namespaceNode, ok := resourceModel.Namespaces[NamespaceID("default")]
+	if !ok {
+		t.Fatalf("Namespace default is not part of the resourceModel")
+	}
+	if diff := cmp.Diff(map[string]string{"env": "prod"}, namespaceNode.Labels); diff != "" {
+		t.Errorf("Unexpected diff in Labels of Namespace default (-want +got):\n%v", diff)
+	}
+
+	// The index contains all Namespaces, including those without resources in
+	// the resourceModel.
+	selector, err := labels.Parse("env=prod")
+	if err != nil {
+		t.Fatalf("Failed to parse selector: %v", err)
+	}
+	if !resourceModel.NamespaceLabels.Matches("team-a", selector) {
+		t.Errorf("NamespaceLabels.Matches(team-a, %v)=false; want true", selector)
+	}
+	if resourceModel.NamespaceLabels.Matches("team-b", selector) {
+		t.Errorf("NamespaceLabels.Matches(team-b, %v)=true; want false", selector)
+	}
+	if resourceModel.NamespaceLabels.Matches("unknown", labels.Everything()) {
+		t.Errorf("NamespaceLabels.Matches(unknown, %v)=true; want false", labels.Everything())
+	}
+}
+
+func TestDiscoverResources_CrossNamespaceParentReferenceGrant(t *testing.T) {
+	baseObjects := func() []runtime.Object {
+		return []runtime.Object{
+			common.NamespaceForTest("infra"),
+			common.NamespaceForTest("team-a"),
+			&gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "shared-gateway",
+					Namespace: "infra",
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-httproute",
+					Namespace: "team-a",
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{
+							Name:      "shared-gateway",
+							Namespace: ptr.To[gatewayv1.Namespace]("infra"),
+						}},
+					},
+				},
+			},
+		}
+	}
+	referenceGrant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "allow-team-a-routes",
+			Namespace: "infra",
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayv1.GroupName,
+				Kind:      "HTTPRoute",
+				Namespace: "team-a",
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Group: gatewayv1.GroupName,
+				Kind:  "Gateway",
+			}},
+		},
+	}
+	notPermittedErr := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+		ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "team-a"},
+		ReferredObject:  common.ObjRef{Kind: "Gateway", Name: "shared-gateway", Namespace: "infra"},
+	}}
+
+	testcases := []struct {
+		name          string
+		requireGrants bool
+		withGrant     bool
+		wantAttached  bool
+		wantErrors    []error
+	}{
+		{
+			name:         "grants not required",
+			wantAttached: true,
+		},
+		{
+			name:          "grants required without ReferenceGrant",
+			requireGrants: true,
+			wantErrors:    []error{notPermittedErr},
+		},
+		{
+			name:          "grants required with permitting ReferenceGrant",
+			requireGrants: true,
+			withGrant:     true,
+			wantAttached:  true,
+		},
+	}
+
+	discoverFuncs := map[string]func(Discoverer, context.Context, Filter) (*ResourceModel, error){
+		"DiscoverResourcesForGateway":   Discoverer.DiscoverResourcesForGateway,
+		"DiscoverResourcesForHTTPRoute": Discoverer.DiscoverResourcesForHTTPRoute,
+	}
+
+	for _, tc := range testcases {
+		for discoverName, discover := range discoverFuncs {
+			t.Run(fmt.Sprintf("%v/%v", tc.name, discoverName), func(t *testing.T) {
+				objects := baseObjects()
+				if tc.withGrant {
+					objects = append(objects, referenceGrant)
+				}
+				params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+				discoverer := Discoverer{
+					K8sClients:                   params.K8sClients,
+					PolicyManager:                params.PolicyManager,
+					RequireParentReferenceGrants: tc.requireGrants,
+				}
+				resourceModel, err := discover(discoverer, context.Background(), Filter{Labels: labels.Everything()})
+				if err != nil {
+					t.Fatalf("Failed to construct resourceModel: %v", err)
+				}
+
+				gatewayID := GatewayID("infra", "shared-gateway")
+				httpRouteID := HTTPRouteID("team-a", "foo-httproute")
+				// Clones must keep the attachment, which depends on the ReferenceGrant.
+				for _, rm := range []*ResourceModel{resourceModel, resourceModel.Clone()} {
+					httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
+					if !ok {
+						if tc.wantAttached {
+							t.Fatalf("HTTPRoute team-a/foo-httproute is not part of the resourceModel")
+						}
+						continue
+					}
+					if _, gotAttached := httpRouteNode.Gateways[gatewayID]; gotAttached != tc.wantAttached {
+						t.Errorf("HTTPRoute attached to Gateway = %v; want %v", gotAttached, tc.wantAttached)
+					}
+					if diff := cmp.Diff(tc.wantErrors, httpRouteNode.Errors, cmpopts.EquateErrors(), cmpopts.EquateEmpty()); diff != "" {
+						t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", httpRouteNode.Errors, tc.wantErrors, diff)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDiscoverResourcesForHTTPRoute_ParentReferenceNotPermittedOnce(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("infra"),
+		common.NamespaceForTest("team-a"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared-gateway",
+				Namespace: "infra",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// The HTTPRoute attaches to two listeners of the same Gateway.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "team-a",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "shared-gateway", Namespace: ptr.To[gatewayv1.Namespace]("infra"), SectionName: ptr.To[gatewayv1.SectionName]("http")},
+						{Name: "shared-gateway", Namespace: ptr.To[gatewayv1.Namespace]("infra"), SectionName: ptr.To[gatewayv1.SectionName]("https")},
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:                   params.K8sClients,
+		PolicyManager:                params.PolicyManager,
+		RequireParentReferenceGrants: true,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	want := []error{ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+		ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "team-a"},
+		ReferredObject:  common.ObjRef{Kind: "Gateway", Name: "shared-gateway", Namespace: "infra"},
+	}}}
+	got := resourceModel.HTTPRoutes[HTTPRouteID("team-a", "foo-httproute")].Errors
+	if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestDiscoverResourcesForGateway_SkipForbidden(t *testing.T) {
+	policyCRD := func(kind, plural string, policyType string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: plural + ".foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: policyType,
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	gatewayPolicy := func(kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"seconds": int64(30),
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+			},
+		},
+		policyCRD("TimeoutPolicy", "timeoutpolicies", "direct"),
+		policyCRD("HealthCheckPolicy", "healthcheckpolicies", "direct"),
+		gatewayPolicy("TimeoutPolicy", "timeout-policy"),
+		gatewayPolicy("HealthCheckPolicy", "health-check-policy"),
+	}
+
+	// newClients returns clients which are not permitted to list
+	// HealthCheckPolicies and GatewayClasses.
+	newClients := func(t *testing.T) *common.K8sClients {
+		k8sClients := common.MustClientsForTest(t, objects...)
+		fakeDC := k8sClients.DC.(*fakedynamicclient.FakeDynamicClient)
+		for _, groupResource := range []schema.GroupResource{
+			{Group: "foo.com", Resource: "healthcheckpolicies"},
+			{Group: gatewayv1.GroupName, Resource: "gatewayclasses"},
+		} {
+			err := apierrors.NewForbidden(groupResource, "", errors.New("RBAC: access denied"))
+			fakeDC.PrependReactor("list", groupResource.Resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, err
+			})
+		}
+		return k8sClients
+	}
+
+	// Without skipping, the forbidden policies fail initialization.
+	if err := policymanager.New(newClients(t).DC).Init(context.Background()); !apierrors.IsForbidden(err) {
+		t.Fatalf("PolicyManager.Init() = %v; want a Forbidden error", err)
+	}
+
+	k8sClients := newClients(t)
+	policyManager := policymanager.New(k8sClients.DC)
+	policyManager.EnableSkipForbidden()
+	if err := policyManager.Init(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize PolicyManager: %v", err)
+	}
+	var warnings []string
+	discoverer := Discoverer{
+		K8sClients:    k8sClients,
+		PolicyManager: policyManager,
+		SkipForbidden: true,
+		Warn: func(kind string, err error) {
+			warnings = append(warnings, kind)
+		},
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	// Each skipped kind is reported once.
+	sort.Strings(warnings)
+	wantWarnings := []string{"GatewayClasses", "HealthCheckPolicy.foo.com"}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("Unexpected diff in warnings (-want +got):\n%v", diff)
+	}
+	var skippedKinds []string
+	for kind, err := range resourceModel.SkippedKinds {
+		if !apierrors.IsForbidden(err) {
+			t.Errorf("SkippedKinds[%v] = %v; want a Forbidden error", kind, err)
+		}
+		skippedKinds = append(skippedKinds, kind)
+	}
+	sort.Strings(skippedKinds)
+	if diff := cmp.Diff(wantWarnings, skippedKinds); diff != "" {
+		t.Errorf("Unexpected diff in SkippedKinds (-want +got):\n%v", diff)
+	}
+
+	// The partial model holds the resources which could be fetched.
+	gatewayNode, ok := resourceModel.Gateways[GatewayID("default", "foo-gateway")]
+	if !ok {
+		t.Fatalf("Gateway default/foo-gateway not found in resourceModel")
+	}
+	if _, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]; !ok {
+		t.Errorf("HTTPRoute default/foo-httproute not found in resourceModel")
+	}
+	if len(resourceModel.GatewayClasses) != 0 {
+		t.Errorf("GatewayClasses = %v; want none", resourceModel.GatewayClasses)
+	}
+	// The GatewayClass can not be verified to exist, so its absence is no error.
+	if len(gatewayNode.Errors) != 0 {
+		t.Errorf("Errors of Gateway = %v; want none", gatewayNode.Errors)
+	}
+
+	// Effective policies are calculated from the policies which could be
+	// fetched, even without the GatewayClass.
+	var effectivePolicies []policymanager.PolicyCrdID
+	for policyCrdID := range gatewayNode.EffectivePolicies {
+		effectivePolicies = append(effectivePolicies, policyCrdID)
+	}
+	if diff := cmp.Diff([]policymanager.PolicyCrdID{"TimeoutPolicy.foo.com"}, effectivePolicies); diff != "" {
+		t.Errorf("Unexpected diff in EffectivePolicies of Gateway (-want +got):\n%v", diff)
+	}
+}
+
+func TestDiscoverResourcesForRequests_BackendRefDefaulting(t *testing.T) {
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+	backendRef := func(group, kind *string, name string) gatewayv1.HTTPBackendRef {
+		ref := gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: ptr.To(gatewayv1.PortNumber(80)),
+				},
+			},
+		}
+		if group != nil {
+			ref.Group = ptr.To(gatewayv1.Group(*group))
+		}
+		if kind != nil {
+			ref.Kind = ptr.To(gatewayv1.Kind(*kind))
+		}
+		return ref
+	}
+
+	testcases := []struct {
+		name         string
+		backendRef   gatewayv1.HTTPBackendRef
+		wantBackends []backendID
+	}{
+		{
+			name:         "omitted group and kind",
+			backendRef:   backendRef(nil, nil, "foo-svc"),
+			wantBackends: []backendID{BackendIDForService("default", "foo-svc")},
+		},
+		{
+			name:         "empty group and kind",
+			backendRef:   backendRef(ptr.To(""), ptr.To(""), "foo-svc"),
+			wantBackends: []backendID{BackendIDForService("default", "foo-svc")},
+		},
+		{
+			name:         "explicit core group and Service kind",
+			backendRef:   backendRef(ptr.To(""), ptr.To("Service"), "foo-svc"),
+			wantBackends: []backendID{BackendIDForService("default", "foo-svc")},
+		},
+		{
+			// A backend of a custom group is not a Service, even if a Service
+			// with the same name exists.
+			name:       "custom group",
+			backendRef: backendRef(ptr.To("foo.com"), ptr.To("Bucket"), "foo-svc"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := []runtime.Object{
+				common.NamespaceForTest("default"),
+				service("foo-svc"),
+				&gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo-httproute",
+						Namespace: "default",
+					},
+					Spec: gatewayv1.HTTPRouteSpec{
+						Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{tc.backendRef}}},
+					},
+				},
+			}
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+			resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+
+			httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+			if !ok {
+				t.Fatalf("HTTPRoute default/foo-httproute not found in resourceModel")
+			}
+			var gotBackends []backendID
+			for id := range httpRouteNode.Backends {
+				gotBackends = append(gotBackends, id)
+			}
+			if diff := cmp.Diff(tc.wantBackends, gotBackends); diff != "" {
+				t.Errorf("Unexpected Backends of the HTTPRoute; diff (-want +got)=\n%v", diff)
+			}
+			if len(httpRouteNode.Errors) != 0 {
+				t.Errorf("Unexpected errors for the HTTPRoute: %v", httpRouteNode.Errors)
+			}
+
+			// The ID of the referenced backend is the same, whether it is derived
+			// from the backendRef or from the backend itself.
+			ref := httpBackendRefObjRef(httpRouteNode.HTTPRoute, tc.backendRef)
+			refID := BackendID(ref.Group, ref.Kind, ref.Namespace, ref.Name)
+			if _, ok := resourceModel.Backends[refID]; ok != (len(tc.wantBackends) != 0) {
+				t.Errorf("Backend %v in resourceModel = %v; want %v", refID, ok, len(tc.wantBackends) != 0)
+			}
+		})
+	}
+}
+
+func TestDiscoverResourcesForHTTPRoute_BackendsListedPerNamespace(t *testing.T) {
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: ptr.To(gatewayv1.PortNumber(80)),
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		service("foo-svc"),
+		service("bar-svc"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc"),
+					backendRef("bar-svc"),
+					backendRef("missing-svc"),
+				}}},
+			},
+		},
+	}
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	fakeDC := params.K8sClients.DC.(*fakedynamicclient.FakeDynamicClient)
+	fakeDC.ClearActions()
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	// The Services are listed once, rather than fetched one at a time.
+	var serviceActions []string
+	for _, action := range fakeDC.Actions() {
+		if action.GetResource().Resource == "services" {
+			serviceActions = append(serviceActions, action.GetVerb())
+		}
+	}
+	if diff := cmp.Diff([]string{"list"}, serviceActions); diff != "" {
+		t.Errorf("Unexpected diff in actions on Services; diff (-want +got)=\n%v", diff)
+	}
+
+	var gotBackends []string
+	for _, backendNode := range resourceModel.Backends {
+		if kind := backendNode.Backend.GetKind(); kind != "Service" {
+			t.Errorf("Backend %v has kind %q; want Service", backendNode.Backend.GetName(), kind)
+		}
+		gotBackends = append(gotBackends, backendNode.Backend.GetName())
+	}
+	sort.Strings(gotBackends)
+	if diff := cmp.Diff([]string{"bar-svc", "foo-svc"}, gotBackends); diff != "" {
+		t.Errorf("Unexpected diff in Backends; diff (-want +got)=\n%v", diff)
+	}
+
+	httpRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	wantErrors := []error{ReferenceToNonExistentResourceError{ReferenceFromTo: ReferenceFromTo{
+		ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+		ReferredObject:  common.ObjRef{Kind: "Service", Name: "missing-svc", Namespace: "default"},
+	}}}
+	if diff := cmp.Diff(wantErrors, httpRouteNode.Errors); diff != "" {
+		t.Errorf("Unexpected diff in HTTPRoute errors; diff (-want +got)=\n%v", diff)
+	}
+}
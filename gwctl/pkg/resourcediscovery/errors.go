@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
 )
 
 type ReferenceToNonExistentResourceError struct {
@@ -42,6 +43,71 @@ func (r ReferenceNotPermittedError) Error() string {
 		r.referredObjectKind(), r.referredObjectName())
 }
 
+// InvalidEffectivePolicyError indicates that an effective policy, which is the
+// result of merging policies from multiple levels of the hierarchy, does not
+// conform to the schema of its CRD.
+type InvalidEffectivePolicyError struct {
+	PolicyCrdID policymanager.PolicyCrdID
+	// Gateway is the Gateway in the context of which the effective policy was
+	// calculated. It is empty for the effective policies of a Gateway itself
+	// and for mesh effective policies.
+	Gateway common.ObjRef
+	// SectionName is the listener or the rule to which the effective policy
+	// applies. It is empty for the effective policy of the whole resource.
+	SectionName string
+	// Err describes why the effective policy is invalid.
+	Err error
+}
+
+func (e InvalidEffectivePolicyError) Error() string {
+	effectivePolicy := fmt.Sprintf("effective %v", e.PolicyCrdID)
+	if e.SectionName != "" {
+		effectivePolicy += fmt.Sprintf(" of section %q", e.SectionName)
+	}
+	if e.Gateway.Name != "" {
+		return fmt.Sprintf("%v in the context of Gateway %q does not conform to the CRD schema: %v",
+			effectivePolicy, fmt.Sprintf("%v/%v", e.Gateway.Namespace, e.Gateway.Name), e.Err)
+	}
+	return fmt.Sprintf("%v does not conform to the CRD schema: %v", effectivePolicy, e.Err)
+}
+
+// UnresolvedExtensionRefError indicates that the object referenced by an
+// ExtensionRef filter of an HTTPRoute could not be fetched.
+type UnresolvedExtensionRefError struct {
+	// RuleIndex is the index of the rule which contains the filter.
+	RuleIndex int
+	// ExtensionRef is the referenced object.
+	ExtensionRef common.ObjRef
+	// Reason describes why the reference could not be resolved.
+	Reason string
+}
+
+func (e UnresolvedExtensionRefError) Error() string {
+	return fmt.Sprintf("ExtensionRef filter in rule %d references %v %q which could not be resolved: %v",
+		e.RuleIndex, fmt.Sprintf("%v.%v", e.ExtensionRef.Kind, e.ExtensionRef.Group), e.ExtensionRef.Name, e.Reason)
+}
+
+// UnresolvedParametersRefError indicates that the object referenced by the
+// parametersRef of a GatewayClass could not be fetched.
+type UnresolvedParametersRefError struct {
+	// ParametersRef is the referenced object.
+	ParametersRef common.ObjRef
+	// Reason describes why the reference could not be resolved.
+	Reason string
+}
+
+func (e UnresolvedParametersRefError) Error() string {
+	kind := e.ParametersRef.Kind
+	if e.ParametersRef.Group != "" {
+		kind = fmt.Sprintf("%v.%v", e.ParametersRef.Kind, e.ParametersRef.Group)
+	}
+	name := e.ParametersRef.Name
+	if e.ParametersRef.Namespace != "" {
+		name = fmt.Sprintf("%v/%v", e.ParametersRef.Namespace, e.ParametersRef.Name)
+	}
+	return fmt.Sprintf("parametersRef references %v %q which could not be resolved: %v", kind, name, e.Reason)
+}
+
 type ReferenceFromTo struct {
 	// ReferringObject is the "from" object which is referring "to" some other
 	// object.
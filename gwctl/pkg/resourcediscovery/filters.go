@@ -0,0 +1,438 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HTTPRouteFilterCORS is the type of the standard CORS filter. It was
+// introduced in a newer version of the Gateway API than the one gwctl is built
+// against, so it is not part of the structured HTTPRouteFilter type.
+const HTTPRouteFilterCORS gatewayv1.HTTPRouteFilterType = "CORS"
+
+// FilterSummary describes a single filter of an HTTPRoute rule, or of one of
+// the backendRefs of the rule.
+type FilterSummary struct {
+	// RuleIndex is the index of the rule which contains the filter.
+	RuleIndex int
+	// BackendRef is the backendRef carrying the filter, with its namespace and
+	// kind defaulted. It is nil for filters of the rule, which apply to all its
+	// backends.
+	BackendRef *common.ObjRef
+	// BackendRefIndex is the index of the backendRef within the rule. It is
+	// only set if BackendRef is set.
+	BackendRefIndex int
+	// Type is the type of the filter.
+	Type gatewayv1.HTTPRouteFilterType
+	// Standard is true if the filter is one of the standard filters defined by
+	// the Gateway API, i.e. it is neither an ExtensionRef nor an unrecognized
+	// filter.
+	Standard bool
+	// Details is a human readable summary of the configuration of the filter.
+	Details string
+	// CORS holds the configuration of the filter if it is a CORS filter.
+	CORS *CORSFilter
+	// RequestHeaderModifier holds the configuration of the filter if it is a
+	// RequestHeaderModifier filter.
+	RequestHeaderModifier *gatewayv1.HTTPHeaderFilter
+	// ResponseHeaderModifier holds the configuration of the filter if it is a
+	// ResponseHeaderModifier filter.
+	ResponseHeaderModifier *gatewayv1.HTTPHeaderFilter
+	// ExtensionRef holds the referenced object if it is an ExtensionRef filter.
+	ExtensionRef *gatewayv1.LocalObjectReference
+}
+
+func (f FilterSummary) String() string {
+	location := fmt.Sprintf("Rule %d", f.RuleIndex)
+	if f.BackendRef != nil {
+		location = fmt.Sprintf("Rule %d, backend %v", f.RuleIndex, BackendRefString(*f.BackendRef))
+	}
+	if f.Details == "" {
+		return fmt.Sprintf("%v: %v", location, f.Type)
+	}
+	return fmt.Sprintf("%v: %v (%v)", location, f.Type, f.Details)
+}
+
+// BackendRefString returns the backendRef as "Kind namespace/name", with the
+// group prepended to the kind for backends which are not in the core group.
+func BackendRefString(backendRef common.ObjRef) string {
+	kind := backendRef.Kind
+	if backendRef.Group != "" {
+		kind = fmt.Sprintf("%v.%v", backendRef.Kind, backendRef.Group)
+	}
+	return fmt.Sprintf("%v %v/%v", kind, backendRef.Namespace, backendRef.Name)
+}
+
+// CORSFilter is the configuration of a CORS filter.
+type CORSFilter struct {
+	AllowOrigins     []string `json:"allowOrigins,omitempty"`
+	AllowMethods     []string `json:"allowMethods,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty"`
+	MaxAge           int64    `json:"maxAge,omitempty"`
+}
+
+// AllowsAllOrigins returns true if the filter allows any origin through the
+// "*" wildcard.
+func (c CORSFilter) AllowsAllOrigins() bool {
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSummarizer returns the details for a filter. filter is the structured
+// filter and config is the unstructured configuration of the filter, i.e. the
+// value of the field named after the filter type. The summary can be updated
+// with any additional information about the filter.
+type filterSummarizer func(summary *FilterSummary, filter gatewayv1.HTTPRouteFilter, config map[string]interface{}) (string, error)
+
+// standardFilters maps each standard filter type to the field holding its
+// configuration and the function summarizing it. Supporting a new standard
+// filter only requires adding it here.
+var standardFilters = map[gatewayv1.HTTPRouteFilterType]struct {
+	field     string
+	summarize filterSummarizer
+}{
+	gatewayv1.HTTPRouteFilterRequestHeaderModifier:  {"requestHeaderModifier", summarizeRequestHeaderModifier},
+	gatewayv1.HTTPRouteFilterResponseHeaderModifier: {"responseHeaderModifier", summarizeResponseHeaderModifier},
+	gatewayv1.HTTPRouteFilterRequestRedirect:        {"requestRedirect", summarizeRequestRedirect},
+	gatewayv1.HTTPRouteFilterURLRewrite:             {"urlRewrite", summarizeURLRewrite},
+	gatewayv1.HTTPRouteFilterRequestMirror:          {"requestMirror", summarizeRequestMirror},
+	HTTPRouteFilterCORS:                             {"cors", summarizeCORS},
+}
+
+// ExtensionRefSummarizer returns a human readable summary of the key fields of
+// an object referenced by an ExtensionRef filter.
+type ExtensionRefSummarizer func(object *unstructured.Unstructured) string
+
+// extensionRefSummarizers maps the kinds of objects referenced by ExtensionRef
+// filters to the function summarizing them.
+var extensionRefSummarizers = map[schema.GroupKind]ExtensionRefSummarizer{}
+
+// RegisterExtensionRefSummarizer registers the summarizer for objects of the
+// given kind which are referenced by ExtensionRef filters. Implementations can
+// use this to surface the configuration of their custom filters. Registering a
+// summarizer for the same kind again replaces the previous one. This is not
+// safe to call concurrently with discovery.
+func RegisterExtensionRefSummarizer(groupKind schema.GroupKind, summarizer ExtensionRefSummarizer) {
+	extensionRefSummarizers[groupKind] = summarizer
+}
+
+// SummarizeHTTPRouteFilters returns summaries for the filters of all rules of
+// the unstructured HTTPRoute, followed by the filters of the backendRefs of
+// each rule. The unstructured form is used since it retains filters which are
+// unknown to the structured HTTPRoute type.
+func SummarizeHTTPRouteFilters(httpRoute *unstructured.Unstructured) ([]FilterSummary, error) {
+	rules, _, err := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []FilterSummary
+	for ruleIndex, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filters, _, err := unstructured.NestedSlice(ruleMap, "filters")
+		if err != nil {
+			return nil, err
+		}
+		for _, filter := range filters {
+			filterMap, ok := filter.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			summary, err := summarizeFilter(ruleIndex, filterMap)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, summary)
+		}
+
+		backendRefs, _, err := unstructured.NestedSlice(ruleMap, "backendRefs")
+		if err != nil {
+			return nil, err
+		}
+		for backendRefIndex, backendRef := range backendRefs {
+			backendRefMap, ok := backendRef.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			filters, _, err := unstructured.NestedSlice(backendRefMap, "filters")
+			if err != nil {
+				return nil, err
+			}
+			objRef := backendRefObjRef(backendRefMap, httpRoute.GetNamespace())
+			for _, filter := range filters {
+				filterMap, ok := filter.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				summary, err := summarizeFilter(ruleIndex, filterMap)
+				if err != nil {
+					return nil, err
+				}
+				summary.BackendRef = &objRef
+				summary.BackendRefIndex = backendRefIndex
+				result = append(result, summary)
+			}
+		}
+	}
+	return result, nil
+}
+
+// backendRefObjRef returns the reference of an unstructured backendRef, with
+// the kind defaulting to Service and the namespace to the one of the route.
+func backendRefObjRef(backendRef map[string]interface{}, routeNamespace string) common.ObjRef {
+	objRef := common.ObjRef{Kind: "Service", Namespace: routeNamespace}
+	objRef.Group, _, _ = unstructured.NestedString(backendRef, "group")
+	objRef.Name, _, _ = unstructured.NestedString(backendRef, "name")
+	if kind, _, _ := unstructured.NestedString(backendRef, "kind"); kind != "" {
+		objRef.Kind = kind
+	}
+	if namespace, _, _ := unstructured.NestedString(backendRef, "namespace"); namespace != "" {
+		objRef.Namespace = namespace
+	}
+	return objRef
+}
+
+func summarizeFilter(ruleIndex int, filterMap map[string]interface{}) (FilterSummary, error) {
+	filter := gatewayv1.HTTPRouteFilter{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(filterMap, &filter); err != nil {
+		return FilterSummary{}, fmt.Errorf("failed to convert unstructured HTTPRouteFilter to structured: %v", err)
+	}
+	summary := FilterSummary{RuleIndex: ruleIndex, Type: filter.Type}
+
+	if filter.Type == gatewayv1.HTTPRouteFilterExtensionRef {
+		if filter.ExtensionRef != nil {
+			summary.ExtensionRef = filter.ExtensionRef
+			summary.Details = fmt.Sprintf("%v/%v %v", filter.ExtensionRef.Group, filter.ExtensionRef.Kind, filter.ExtensionRef.Name)
+		}
+		return summary, nil
+	}
+
+	standardFilter, ok := standardFilters[filter.Type]
+	if !ok {
+		summary.Details = "<unrecognized filter>"
+		return summary, nil
+	}
+	summary.Standard = true
+
+	config, _, err := unstructured.NestedMap(filterMap, standardFilter.field)
+	if err != nil {
+		return FilterSummary{}, err
+	}
+	summary.Details, err = standardFilter.summarize(&summary, filter, config)
+	if err != nil {
+		return FilterSummary{}, err
+	}
+	return summary, nil
+}
+
+func summarizeRequestHeaderModifier(summary *FilterSummary, filter gatewayv1.HTTPRouteFilter, _ map[string]interface{}) (string, error) {
+	summary.RequestHeaderModifier = filter.RequestHeaderModifier
+	return summarizeHeaderFilter(filter.RequestHeaderModifier), nil
+}
+
+func summarizeResponseHeaderModifier(summary *FilterSummary, filter gatewayv1.HTTPRouteFilter, _ map[string]interface{}) (string, error) {
+	summary.ResponseHeaderModifier = filter.ResponseHeaderModifier
+	return summarizeHeaderFilter(filter.ResponseHeaderModifier), nil
+}
+
+// ContradictoryHeaders returns the names of the headers which are both set (or
+// added) and removed by the header filter. Header names are compared case
+// insensitively, and the names are returned as they appear in the remove list.
+func ContradictoryHeaders(headerFilter *gatewayv1.HTTPHeaderFilter) []string {
+	if headerFilter == nil {
+		return nil
+	}
+	modified := make(map[string]bool)
+	for _, header := range append(append([]gatewayv1.HTTPHeader{}, headerFilter.Set...), headerFilter.Add...) {
+		modified[strings.ToLower(string(header.Name))] = true
+	}
+	var result []string
+	for _, name := range headerFilter.Remove {
+		if modified[strings.ToLower(name)] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// ConflictingHeaders returns the names of the headers which are modified, i.e.
+// set, added or removed, by both header filters. Header names are compared case
+// insensitively, and the names are returned as they appear in the second
+// filter.
+func ConflictingHeaders(a, b *gatewayv1.HTTPHeaderFilter) []string {
+	if a == nil || b == nil {
+		return nil
+	}
+	modified := make(map[string]bool)
+	for _, name := range modifiedHeaders(a) {
+		modified[strings.ToLower(name)] = true
+	}
+	seen := make(map[string]bool)
+	var result []string
+	for _, name := range modifiedHeaders(b) {
+		if modified[strings.ToLower(name)] && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func modifiedHeaders(headerFilter *gatewayv1.HTTPHeaderFilter) []string {
+	var result []string
+	for _, header := range append(append([]gatewayv1.HTTPHeader{}, headerFilter.Set...), headerFilter.Add...) {
+		result = append(result, string(header.Name))
+	}
+	return append(result, headerFilter.Remove...)
+}
+
+func summarizeHeaderFilter(headerFilter *gatewayv1.HTTPHeaderFilter) string {
+	if headerFilter == nil {
+		return ""
+	}
+	var parts []string
+	headers := func(headers []gatewayv1.HTTPHeader) string {
+		var result []string
+		for _, header := range headers {
+			result = append(result, fmt.Sprintf("%v:%v", header.Name, header.Value))
+		}
+		return strings.Join(result, ",")
+	}
+	if len(headerFilter.Set) != 0 {
+		parts = append(parts, fmt.Sprintf("set=[%v]", headers(headerFilter.Set)))
+	}
+	if len(headerFilter.Add) != 0 {
+		parts = append(parts, fmt.Sprintf("add=[%v]", headers(headerFilter.Add)))
+	}
+	if len(headerFilter.Remove) != 0 {
+		parts = append(parts, fmt.Sprintf("remove=[%v]", strings.Join(headerFilter.Remove, ",")))
+	}
+	return strings.Join(parts, " ")
+}
+
+func summarizeRequestRedirect(_ *FilterSummary, filter gatewayv1.HTTPRouteFilter, _ map[string]interface{}) (string, error) {
+	redirect := filter.RequestRedirect
+	if redirect == nil {
+		return "", nil
+	}
+	var parts []string
+	if redirect.Scheme != nil {
+		parts = append(parts, fmt.Sprintf("scheme=%v", *redirect.Scheme))
+	}
+	if redirect.Hostname != nil {
+		parts = append(parts, fmt.Sprintf("hostname=%v", *redirect.Hostname))
+	}
+	if redirect.Path != nil {
+		parts = append(parts, fmt.Sprintf("path=%v", summarizePathModifier(*redirect.Path)))
+	}
+	if redirect.Port != nil {
+		parts = append(parts, fmt.Sprintf("port=%v", *redirect.Port))
+	}
+	if redirect.StatusCode != nil {
+		parts = append(parts, fmt.Sprintf("statusCode=%v", *redirect.StatusCode))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func summarizeURLRewrite(_ *FilterSummary, filter gatewayv1.HTTPRouteFilter, _ map[string]interface{}) (string, error) {
+	rewrite := filter.URLRewrite
+	if rewrite == nil {
+		return "", nil
+	}
+	var parts []string
+	if rewrite.Hostname != nil {
+		parts = append(parts, fmt.Sprintf("hostname=%v", *rewrite.Hostname))
+	}
+	if rewrite.Path != nil {
+		parts = append(parts, fmt.Sprintf("path=%v", summarizePathModifier(*rewrite.Path)))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func summarizePathModifier(pathModifier gatewayv1.HTTPPathModifier) string {
+	switch pathModifier.Type {
+	case gatewayv1.FullPathHTTPPathModifier:
+		if pathModifier.ReplaceFullPath != nil {
+			return fmt.Sprintf("%v:%v", pathModifier.Type, *pathModifier.ReplaceFullPath)
+		}
+	case gatewayv1.PrefixMatchHTTPPathModifier:
+		if pathModifier.ReplacePrefixMatch != nil {
+			return fmt.Sprintf("%v:%v", pathModifier.Type, *pathModifier.ReplacePrefixMatch)
+		}
+	}
+	return string(pathModifier.Type)
+}
+
+func summarizeRequestMirror(_ *FilterSummary, filter gatewayv1.HTTPRouteFilter, _ map[string]interface{}) (string, error) {
+	mirror := filter.RequestMirror
+	if mirror == nil {
+		return "", nil
+	}
+	backend := string(mirror.BackendRef.Name)
+	if mirror.BackendRef.Namespace != nil {
+		backend = fmt.Sprintf("%v/%v", *mirror.BackendRef.Namespace, backend)
+	}
+	if mirror.BackendRef.Port != nil {
+		backend = fmt.Sprintf("%v:%v", backend, *mirror.BackendRef.Port)
+	}
+	return fmt.Sprintf("backend=%v", backend), nil
+}
+
+func summarizeCORS(summary *FilterSummary, _ gatewayv1.HTTPRouteFilter, config map[string]interface{}) (string, error) {
+	cors := &CORSFilter{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(config, cors); err != nil {
+		return "", fmt.Errorf("failed to convert unstructured CORS filter to structured: %v", err)
+	}
+	summary.CORS = cors
+
+	var parts []string
+	if len(cors.AllowOrigins) != 0 {
+		parts = append(parts, fmt.Sprintf("allowOrigins=[%v]", strings.Join(cors.AllowOrigins, ",")))
+	}
+	if len(cors.AllowMethods) != 0 {
+		parts = append(parts, fmt.Sprintf("allowMethods=[%v]", strings.Join(cors.AllowMethods, ",")))
+	}
+	if len(cors.AllowHeaders) != 0 {
+		parts = append(parts, fmt.Sprintf("allowHeaders=[%v]", strings.Join(cors.AllowHeaders, ",")))
+	}
+	if len(cors.ExposeHeaders) != 0 {
+		parts = append(parts, fmt.Sprintf("exposeHeaders=[%v]", strings.Join(cors.ExposeHeaders, ",")))
+	}
+	parts = append(parts, fmt.Sprintf("allowCredentials=%v", cors.AllowCredentials))
+	if cors.MaxAge != 0 {
+		parts = append(parts, fmt.Sprintf("maxAge=%d", cors.MaxAge))
+	}
+	return strings.Join(parts, " "), nil
+}
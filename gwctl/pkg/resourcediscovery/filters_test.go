@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestSummarizeHTTPRouteFilters(t *testing.T) {
+	httpRoute := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      "foo-httproute",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"filters": []interface{}{
+							map[string]interface{}{
+								"type": "RequestHeaderModifier",
+								"requestHeaderModifier": map[string]interface{}{
+									"set":    []interface{}{map[string]interface{}{"name": "X-Env", "value": "prod"}},
+									"remove": []interface{}{"X-Debug"},
+								},
+							},
+							map[string]interface{}{
+								"type": "URLRewrite",
+								"urlRewrite": map[string]interface{}{
+									"path": map[string]interface{}{
+										"type":               "ReplacePrefixMatch",
+										"replacePrefixMatch": "/v2",
+									},
+								},
+							},
+							map[string]interface{}{
+								"type": "ResponseHeaderModifier",
+								"responseHeaderModifier": map[string]interface{}{
+									"add":    []interface{}{map[string]interface{}{"name": "X-Cache", "value": "HIT"}},
+									"remove": []interface{}{"X-Powered-By"},
+								},
+							},
+						},
+						"backendRefs": []interface{}{
+							map[string]interface{}{
+								"name": "foo-svc",
+								"port": int64(8080),
+								"filters": []interface{}{
+									map[string]interface{}{
+										"type": "RequestHeaderModifier",
+										"requestHeaderModifier": map[string]interface{}{
+											"set": []interface{}{map[string]interface{}{"name": "X-Env", "value": "canary"}},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "bar-svc",
+								"port": int64(8080),
+							},
+						},
+					},
+					map[string]interface{}{
+						"filters": []interface{}{
+							map[string]interface{}{
+								"type": "CORS",
+								"cors": map[string]interface{}{
+									"allowOrigins":     []interface{}{"*"},
+									"allowMethods":     []interface{}{"GET", "POST"},
+									"exposeHeaders":    []interface{}{"X-Request-Id"},
+									"allowCredentials": true,
+									"maxAge":           int64(600),
+								},
+							},
+							map[string]interface{}{
+								"type": "ExtensionRef",
+								"extensionRef": map[string]interface{}{
+									"group": "example.com",
+									"kind":  "RateLimit",
+									"name":  "foo-ratelimit",
+								},
+							},
+							map[string]interface{}{
+								"type": "SomeFutureFilter",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := SummarizeHTTPRouteFilters(httpRoute)
+	if err != nil {
+		t.Fatalf("SummarizeHTTPRouteFilters() failed: %v", err)
+	}
+
+	want := []FilterSummary{
+		{
+			RuleIndex: 0,
+			Type:      "RequestHeaderModifier",
+			Standard:  true,
+			Details:   "set=[X-Env:prod] remove=[X-Debug]",
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set:    []gatewayv1.HTTPHeader{{Name: "X-Env", Value: "prod"}},
+				Remove: []string{"X-Debug"},
+			},
+		},
+		{RuleIndex: 0, Type: "URLRewrite", Standard: true, Details: "path=ReplacePrefixMatch:/v2"},
+		{
+			RuleIndex: 0,
+			Type:      "ResponseHeaderModifier",
+			Standard:  true,
+			Details:   "add=[X-Cache:HIT] remove=[X-Powered-By]",
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Add:    []gatewayv1.HTTPHeader{{Name: "X-Cache", Value: "HIT"}},
+				Remove: []string{"X-Powered-By"},
+			},
+		},
+		{
+			RuleIndex:  0,
+			BackendRef: &common.ObjRef{Kind: "Service", Namespace: "default", Name: "foo-svc"},
+			Type:       "RequestHeaderModifier",
+			Standard:   true,
+			Details:    "set=[X-Env:canary]",
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set: []gatewayv1.HTTPHeader{{Name: "X-Env", Value: "canary"}},
+			},
+		},
+		{
+			RuleIndex: 1,
+			Type:      HTTPRouteFilterCORS,
+			Standard:  true,
+			Details:   "allowOrigins=[*] allowMethods=[GET,POST] exposeHeaders=[X-Request-Id] allowCredentials=true maxAge=600",
+			CORS: &CORSFilter{
+				AllowOrigins:     []string{"*"},
+				AllowMethods:     []string{"GET", "POST"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				AllowCredentials: true,
+				MaxAge:           600,
+			},
+		},
+		{
+			RuleIndex:    1,
+			Type:         "ExtensionRef",
+			Details:      "example.com/RateLimit foo-ratelimit",
+			ExtensionRef: &gatewayv1.LocalObjectReference{Group: "example.com", Kind: "RateLimit", Name: "foo-ratelimit"},
+		},
+		{RuleIndex: 1, Type: "SomeFutureFilter", Details: "<unrecognized filter>"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in SummarizeHTTPRouteFilters(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+
+	var gotStrings []string
+	for _, summary := range got {
+		gotStrings = append(gotStrings, summary.String())
+	}
+	wantStrings := []string{
+		"Rule 0: RequestHeaderModifier (set=[X-Env:prod] remove=[X-Debug])",
+		"Rule 0: URLRewrite (path=ReplacePrefixMatch:/v2)",
+		"Rule 0: ResponseHeaderModifier (add=[X-Cache:HIT] remove=[X-Powered-By])",
+		"Rule 0, backend Service default/foo-svc: RequestHeaderModifier (set=[X-Env:canary])",
+		"Rule 1: CORS (allowOrigins=[*] allowMethods=[GET,POST] exposeHeaders=[X-Request-Id] allowCredentials=true maxAge=600)",
+		"Rule 1: ExtensionRef (example.com/RateLimit foo-ratelimit)",
+		"Rule 1: SomeFutureFilter (<unrecognized filter>)",
+	}
+	if diff := cmp.Diff(wantStrings, gotStrings); diff != "" {
+		t.Errorf("Unexpected diff in String() of the summaries (-want +got):\n%v", diff)
+	}
+}
+
+func TestSummarizeHTTPRouteFilters_BackendRefIndex(t *testing.T) {
+	httpRoute := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      "foo-httproute",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"backendRefs": []interface{}{
+							map[string]interface{}{"name": "foo-svc"},
+							map[string]interface{}{
+								"name": "bar-svc",
+								"filters": []interface{}{
+									map[string]interface{}{
+										"type": "RequestHeaderModifier",
+										"requestHeaderModifier": map[string]interface{}{
+											"remove": []interface{}{"X-Debug"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := SummarizeHTTPRouteFilters(httpRoute)
+	if err != nil {
+		t.Fatalf("SummarizeHTTPRouteFilters() failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("SummarizeHTTPRouteFilters() returned %d summaries; want 1", len(got))
+	}
+	if got[0].BackendRefIndex != 1 {
+		t.Errorf("BackendRefIndex = %d; want 1", got[0].BackendRefIndex)
+	}
+}
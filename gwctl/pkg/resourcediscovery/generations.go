@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Generations pairs the generation of a resource, which is incremented on
+// each change to its spec, with the generation reflected by its status.
+type Generations struct {
+	// Generation is the metadata.generation of the resource.
+	Generation int64
+	// ObservedGeneration is the generation last reconciled by the controllers
+	// reporting a status for the resource. If multiple controllers report a
+	// status, e.g. for different parents of an HTTPRoute, it is the generation
+	// of the one lagging the most. It is 0 if no controller reports an
+	// observedGeneration.
+	ObservedGeneration int64
+}
+
+// Stale returns true if the status of the resource reflects an older
+// generation than the current one, i.e. the latest change to the resource has
+// not been reconciled yet, or the reconcile is stuck.
+func (g Generations) Stale() bool {
+	return g.ObservedGeneration != 0 && g.ObservedGeneration < g.Generation
+}
+
+// Gap returns the number of generations the status lags behind.
+func (g Generations) Gap() int64 {
+	if !g.Stale() {
+		return 0
+	}
+	return g.Generation - g.ObservedGeneration
+}
+
+// observedGeneration returns the latest observedGeneration of the conditions,
+// which are all reported by the same controller.
+func observedGeneration(conditions ...[]metav1.Condition) int64 {
+	var result int64
+	for _, list := range conditions {
+		for _, condition := range list {
+			if condition.ObservedGeneration > result {
+				result = condition.ObservedGeneration
+			}
+		}
+	}
+	return result
+}
+
+// lowestObservedGeneration returns the lowest non-zero observedGeneration
+// reported for each status entry, e.g. each parent of an HTTPRoute.
+func lowestObservedGeneration(entries [][]metav1.Condition) int64 {
+	var result int64
+	for _, conditions := range entries {
+		generation := observedGeneration(conditions)
+		if generation != 0 && (result == 0 || generation < result) {
+			result = generation
+		}
+	}
+	return result
+}
+
+// Generations returns the generation of the Gateway and the generation
+// reflected by its conditions, including those of its listeners.
+func (g *GatewayNode) Generations() Generations {
+	conditions := [][]metav1.Condition{g.Gateway.Status.Conditions}
+	for _, listener := range g.Gateway.Status.Listeners {
+		conditions = append(conditions, listener.Conditions)
+	}
+	return Generations{
+		Generation:         g.Gateway.GetGeneration(),
+		ObservedGeneration: observedGeneration(conditions...),
+	}
+}
+
+// Generations returns the generation of the HTTPRoute and the generation
+// reflected by the status of its parents.
+func (h *HTTPRouteNode) Generations() Generations {
+	var entries [][]metav1.Condition
+	for _, parent := range h.HTTPRoute.Status.Parents {
+		entries = append(entries, parent.Conditions)
+	}
+	return Generations{
+		Generation:         h.HTTPRoute.GetGeneration(),
+		ObservedGeneration: lowestObservedGeneration(entries),
+	}
+}
+
+// Generations returns the generation of the policy and the generation
+// reflected by the status of its ancestors.
+func (p *PolicyNode) Generations() Generations {
+	var entries [][]metav1.Condition
+	for _, ancestor := range p.Policy.AncestorStatuses() {
+		entries = append(entries, ancestor.Conditions)
+	}
+	return Generations{
+		Generation:         p.Policy.Unstructured().GetGeneration(),
+		ObservedGeneration: lowestObservedGeneration(entries),
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
+)
+
+// GrantCheck is the result of verifying that a cross namespace reference from
+// an HTTPRoute to a backend is permitted by a ReferenceGrant.
+type GrantCheck struct {
+	HTTPRoute common.ObjRef
+	Backend   common.ObjRef
+	// ReferenceGrant references the ReferenceGrant permitting the reference.
+	// It is nil if the reference is not permitted.
+	ReferenceGrant *common.ObjRef
+	// Reason explains why the reference is not permitted.
+	Reason string
+	// BackendNotFound is true if the backend does not exist, in which case no
+	// ReferenceGrant can be verified for the reference.
+	BackendNotFound bool
+}
+
+// Passed returns true if the reference is permitted.
+func (c GrantCheck) Passed() bool {
+	return c.ReferenceGrant != nil
+}
+
+// VerifyGrants returns a GrantCheck for each cross namespace reference from
+// the backendRefs of the HTTPRoutes in the ResourceModel to a backend, sorted
+// by HTTPRoute and backend. References to backends which are not part of the
+// ResourceModel are reported as BackendNotFound, rather than as not permitted.
+func (rm *ResourceModel) VerifyGrants() []GrantCheck {
+	var result []GrantCheck
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		httpRouteRef := common.ObjRef{
+			Group:     gatewayv1.GroupName,
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}
+		for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if backendRef.Namespace == httpRouteRef.Namespace {
+				continue
+			}
+			check := GrantCheck{HTTPRoute: httpRouteRef, Backend: backendRef}
+
+			backendNode, ok := rm.Backends[BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)]
+			if !ok {
+				check.BackendNotFound = true
+				result = append(result, check)
+				continue
+			}
+			referenceGrantNodes := common.MapToValues(backendNode.ReferenceGrants)
+			sort.Slice(referenceGrantNodes, func(i, j int) bool {
+				return referenceGrantNodes[i].ReferenceGrant.GetName() < referenceGrantNodes[j].ReferenceGrant.GetName()
+			})
+			for _, referenceGrantNode := range referenceGrantNodes {
+				if relations.ReferenceGrantAccepts(*referenceGrantNode.ReferenceGrant, httpRouteRef) {
+					check.ReferenceGrant = &common.ObjRef{
+						Kind:      "ReferenceGrant",
+						Name:      referenceGrantNode.ReferenceGrant.GetName(),
+						Namespace: referenceGrantNode.ReferenceGrant.GetNamespace(),
+					}
+					break
+				}
+			}
+			if check.ReferenceGrant == nil {
+				check.Reason = "no ReferenceGrant permits the reference"
+			}
+			result = append(result, check)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.HTTPRoute.Namespace != b.HTTPRoute.Namespace {
+			return a.HTTPRoute.Namespace < b.HTTPRoute.Namespace
+		}
+		if a.HTTPRoute.Name != b.HTTPRoute.Name {
+			return a.HTTPRoute.Name < b.HTTPRoute.Name
+		}
+		return BackendRefString(a.Backend) < BackendRefString(b.Backend)
+	})
+	return result
+}
+
+// GrantedReference is a cross namespace reference which is permitted by a
+// ReferenceGrant.
+type GrantedReference struct {
+	// From is the referring object, e.g. an HTTPRoute.
+	From common.ObjRef
+	// To is the referenced object, e.g. a Service.
+	To common.ObjRef
+}
+
+// EnabledReferences returns the cross namespace references in the
+// ResourceModel which the ReferenceGrant permits: those from HTTPRoutes to the
+// Backends it exposes, and those from HTTPRoutes attaching to the Gateways it
+// exposes. The result is sorted by From and To.
+func (r *ReferenceGrantNode) EnabledReferences() []GrantedReference {
+	var result []GrantedReference
+	add := func(httpRouteNode *HTTPRouteNode, to common.ObjRef) {
+		from := common.ObjRef{
+			Group:     gatewayv1.GroupName,
+			Kind:      "HTTPRoute",
+			Name:      httpRouteNode.HTTPRoute.GetName(),
+			Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		}
+		if from.Namespace != to.Namespace && relations.ReferenceGrantAccepts(*r.ReferenceGrant, from) {
+			result = append(result, GrantedReference{From: from, To: to})
+		}
+	}
+
+	for _, backendNode := range r.Backends {
+		to := common.ObjRef{
+			Group:     backendNode.Backend.GroupVersionKind().Group,
+			Kind:      backendNode.Backend.GetKind(),
+			Name:      backendNode.Backend.GetName(),
+			Namespace: backendNode.Backend.GetNamespace(),
+		}
+		for _, httpRouteNode := range backendNode.HTTPRoutes {
+			add(httpRouteNode, to)
+		}
+	}
+	for gatewayID, gatewayNode := range r.Gateways {
+		for _, httpRouteNode := range gatewayNode.HTTPRoutes {
+			add(httpRouteNode, gatewayObjRef(gatewayID))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.From != b.From {
+			return a.From.Namespace+"/"+a.From.Name < b.From.Namespace+"/"+b.From.Name
+		}
+		return a.To.Kind+"/"+a.To.Namespace+"/"+a.To.Name < b.To.Kind+"/"+b.To.Namespace+"/"+b.To.Name
+	})
+	return result
+}
+
+// GrantPermission is a single permission of a ReferenceGrant: references from
+// objects of a kind in the From namespace to objects of a kind in the
+// namespace of the ReferenceGrant, optionally restricted to a single name.
+type GrantPermission struct {
+	FromGroup     string
+	FromKind      string
+	FromNamespace string
+	// Namespace is the namespace of the ReferenceGrant, which is the namespace
+	// of the referenced objects.
+	Namespace string
+	ToGroup   string
+	ToKind    string
+	// ToName is the name of the referenced object. It is empty if references
+	// to all objects of the kind are permitted.
+	ToName string
+}
+
+// String returns a human readable representation of the permission, e.g.
+// "from HTTPRoute in namespace foo to any Service in namespace bar".
+func (p GrantPermission) String() string {
+	to := fmt.Sprintf("any %v in namespace %v", p.ToKind, p.Namespace)
+	if p.ToName != "" {
+		to = fmt.Sprintf("%v %v/%v", p.ToKind, p.Namespace, p.ToName)
+	}
+	return fmt.Sprintf("from %v in namespace %v to %v", p.FromKind, p.FromNamespace, to)
+}
+
+// Permissions returns the distinct permissions of the ReferenceGrant, i.e.
+// each combination of its from and to entries, in the order of the spec.
+func (r *ReferenceGrantNode) Permissions() []GrantPermission {
+	var result []GrantPermission
+	seen := make(map[GrantPermission]bool)
+	for _, from := range r.ReferenceGrant.Spec.From {
+		for _, to := range r.ReferenceGrant.Spec.To {
+			permission := GrantPermission{
+				FromGroup:     string(from.Group),
+				FromKind:      string(from.Kind),
+				FromNamespace: string(from.Namespace),
+				Namespace:     r.ReferenceGrant.GetNamespace(),
+				ToGroup:       string(to.Group),
+				ToKind:        string(to.Kind),
+			}
+			if to.Name != nil {
+				permission.ToName = string(*to.Name)
+			}
+			if !seen[permission] {
+				seen[permission] = true
+				result = append(result, permission)
+			}
+		}
+	}
+	return result
+}
+
+// DuplicateGrant is a permission which is granted by more than one
+// ReferenceGrant, either exactly or through a permission for all objects of
+// the kind.
+type DuplicateGrant struct {
+	Permission GrantPermission
+	// ReferenceGrants references the ReferenceGrants granting the permission,
+	// sorted by name.
+	ReferenceGrants []common.ObjRef
+}
+
+// DuplicateReferenceGrants returns the permissions which are granted by more
+// than one ReferenceGrant of the ResourceModel, ordered by the namespace and
+// name of the first ReferenceGrant granting them. A permission for all objects
+// of a kind overlaps every permission restricted to a single name of the kind,
+// so the ReferenceGrants granting it are also reported for the latter.
+func (rm *ResourceModel) DuplicateReferenceGrants() []DuplicateGrant {
+	referenceGrantNodes := common.MapToValues(rm.ReferenceGrants)
+	sort.Slice(referenceGrantNodes, func(i, j int) bool {
+		a, b := referenceGrantNodes[i].ReferenceGrant, referenceGrantNodes[j].ReferenceGrant
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+
+	var permissions []GrantPermission
+	referenceGrantsByPermission := make(map[GrantPermission][]common.ObjRef)
+	for _, referenceGrantNode := range referenceGrantNodes {
+		for _, permission := range referenceGrantNode.Permissions() {
+			if _, ok := referenceGrantsByPermission[permission]; !ok {
+				permissions = append(permissions, permission)
+			}
+			referenceGrantsByPermission[permission] = append(referenceGrantsByPermission[permission], common.ObjRef{
+				Kind:      "ReferenceGrant",
+				Name:      referenceGrantNode.ReferenceGrant.GetName(),
+				Namespace: referenceGrantNode.ReferenceGrant.GetNamespace(),
+			})
+		}
+	}
+
+	var result []DuplicateGrant
+	for _, permission := range permissions {
+		referenceGrants := referenceGrantsByPermission[permission]
+		if permission.ToName != "" {
+			anyName := permission
+			anyName.ToName = ""
+			referenceGrants = mergeObjRefs(referenceGrants, referenceGrantsByPermission[anyName])
+		}
+		if len(referenceGrants) > 1 {
+			result = append(result, DuplicateGrant{Permission: permission, ReferenceGrants: referenceGrants})
+		}
+	}
+	return result
+}
+
+// mergeObjRefs returns the distinct references of a and b, sorted by
+// namespace and name.
+func mergeObjRefs(a, b []common.ObjRef) []common.ObjRef {
+	result := slices.Clone(a)
+	for _, ref := range b {
+		if !slices.Contains(result, ref) {
+			result = append(result, ref)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// modelContent is the canonical form of the ResourceModel which ContentHash
+// serializes. encoding/json sorts the keys of maps, and all slices are sorted,
+// so the serialization does not depend on the iteration order of maps.
+type modelContent struct {
+	// Nodes maps the NodeID of each node to its content.
+	Nodes map[string]nodeContent
+	// Edges lists the edges between nodes, as "from -> to" strings.
+	Edges []string
+	// EffectivePolicies maps the NodeID of each node to its effective policies
+	// in each context, e.g. the Gateway an HTTPRoute is reached through.
+	EffectivePolicies map[string]map[string]map[policymanager.PolicyCrdID]interface{}
+}
+
+// nodeContent is the part of a resource which is meaningful for its behavior.
+type nodeContent struct {
+	Labels      map[string]string `json:",omitempty"`
+	Annotations map[string]string `json:",omitempty"`
+	Spec        interface{}       `json:",omitempty"`
+}
+
+// ContentHash returns a hex encoded SHA-256 hash of the meaningful content of
+// the ResourceModel: the labels, annotations and spec of each node, the edges
+// between nodes and the effective policies. Equal models produce equal hashes,
+// which allows detecting whether anything changed between runs. Metadata
+// maintained by the API server, like the resourceVersion, and the status of
+// resources are excluded.
+func (rm *ResourceModel) ContentHash() string {
+	content := modelContent{
+		Nodes:             make(map[string]nodeContent),
+		EffectivePolicies: make(map[string]map[string]map[policymanager.PolicyCrdID]interface{}),
+	}
+
+	for nodeID, node := range rm.Nodes() {
+		object := node.ClientObject()
+		c := nodeContent{Labels: object.GetLabels(), Annotations: object.GetAnnotations()}
+		if u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object); err == nil {
+			c.Spec = u["spec"]
+		} else {
+			klog.V(1).ErrorS(err, "Failed to convert node to unstructured", "node", nodeID)
+		}
+		content.Nodes[nodeID] = c
+	}
+
+	for _, edge := range rm.SortedEdges() {
+		content.Edges = append(content.Edges, fmt.Sprintf("%v -> %v", edge.From.NodeID(), edge.To.NodeID()))
+	}
+	for _, gatewayNode := range rm.Gateways {
+		for _, backendNode := range gatewayNode.DefaultBackends {
+			content.Edges = append(content.Edges, fmt.Sprintf("%v -> %v (default)", gatewayNode.NodeID(), backendNode.NodeID()))
+		}
+	}
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		for gatewayID, sectionNames := range httpRouteNode.GatewaySections {
+			for _, sectionName := range sectionNames {
+				content.Edges = append(content.Edges, fmt.Sprintf("%v -> %v (section %v)", gatewayNodeID(gatewayID), httpRouteNode.NodeID(), sectionName))
+			}
+		}
+	}
+	sort.Strings(content.Edges)
+
+	addPolicies := func(node Node, context string, policies map[policymanager.PolicyCrdID]policymanager.Policy) {
+		if len(policies) == 0 {
+			return
+		}
+		if content.EffectivePolicies[node.NodeID()] == nil {
+			content.EffectivePolicies[node.NodeID()] = make(map[string]map[policymanager.PolicyCrdID]interface{})
+		}
+		specs := make(map[policymanager.PolicyCrdID]interface{})
+		for policyCrdID, policy := range policies {
+			spec, err := policy.EffectiveSpec()
+			if err != nil {
+				// The raw spec still reflects the content of the policy.
+				specs[policyCrdID] = policy.Spec()
+				continue
+			}
+			specs[policyCrdID] = spec
+		}
+		content.EffectivePolicies[node.NodeID()][context] = specs
+	}
+	for _, gatewayNode := range rm.Gateways {
+		addPolicies(gatewayNode, "", gatewayNode.EffectivePolicies)
+		for sectionName, policies := range gatewayNode.ListenerEffectivePolicies {
+			addPolicies(gatewayNode, "listener "+string(sectionName), policies)
+		}
+	}
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		addPolicies(httpRouteNode, "mesh", httpRouteNode.MeshEffectivePolicies)
+		for gatewayID, policies := range httpRouteNode.EffectivePolicies {
+			addPolicies(httpRouteNode, gatewayNodeID(gatewayID), policies)
+		}
+		for gatewayID, policiesByRule := range httpRouteNode.RuleEffectivePolicies {
+			for ruleName, policies := range policiesByRule {
+				addPolicies(httpRouteNode, fmt.Sprintf("%v rule %v", gatewayNodeID(gatewayID), ruleName), policies)
+			}
+		}
+		for gatewayID, policiesByListener := range httpRouteNode.ListenerEffectivePolicies {
+			for sectionName, policies := range policiesByListener {
+				addPolicies(httpRouteNode, fmt.Sprintf("%v listener %v", gatewayNodeID(gatewayID), sectionName), policies)
+			}
+		}
+	}
+	for _, backendNode := range rm.Backends {
+		for gatewayID, policies := range backendNode.EffectivePolicies {
+			addPolicies(backendNode, gatewayNodeID(gatewayID), policies)
+		}
+		for gatewayID, policiesByListener := range backendNode.ListenerEffectivePolicies {
+			for sectionName, policies := range policiesByListener {
+				addPolicies(backendNode, fmt.Sprintf("%v listener %v", gatewayNodeID(gatewayID), sectionName), policies)
+			}
+		}
+	}
+
+	b, err := json.Marshal(content)
+	if err != nil {
+		// The content only consists of JSON compatible values, so this is not
+		// expected to happen.
+		klog.V(0).ErrorS(err, "Failed to serialize the content of the ResourceModel")
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// gatewayNodeID returns the NodeID of the Gateway identified by gatewayID.
+func gatewayNodeID(gatewayID gatewayID) string {
+	return nodeID("Gateway", gatewayID.Namespace, gatewayID.Name)
+}
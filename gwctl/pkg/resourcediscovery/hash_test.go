@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_ContentHash(t *testing.T) {
+	// buildModel builds a ResourceModel with its own clients, such that models
+	// never share any state.
+	buildModel := func(t *testing.T, hostname string, timeout int64) *ResourceModel {
+		objects := []runtime.Object{
+			common.NamespaceForTest("default"),
+			common.NamespaceForTest("bar"),
+			&gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-gateway",
+					Namespace: "default",
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-gateway",
+					Namespace: "bar",
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-httproute",
+					Namespace: "default",
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{
+							{Name: "foo-gateway"},
+							{Name: "bar-gateway", Namespace: common.PtrTo[gatewayv1.Namespace]("bar")},
+						},
+					},
+					Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(hostname)},
+				},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "healthcheckpolicies.foo.com",
+					Labels: map[string]string{
+						gatewayv1alpha2.PolicyLabelKey: "inherited",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Scope:    apiextensionsv1.ClusterScoped,
+					Group:    "foo.com",
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural: "healthcheckpolicies",
+						Kind:   "HealthCheckPolicy",
+					},
+				},
+			},
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "foo.com/v1",
+					"kind":       "HealthCheckPolicy",
+					"metadata": map[string]interface{}{
+						"name": "health-check-gatewayclass",
+					},
+					"spec": map[string]interface{}{
+						"default": map[string]interface{}{
+							"timeout": timeout,
+						},
+						"targetRef": map[string]interface{}{
+							"group": "gateway.networking.k8s.io",
+							"kind":  "GatewayClass",
+							"name":  "foo-gatewayclass",
+						},
+					},
+				},
+			},
+		}
+
+		params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+		discoverer := Discoverer{
+			K8sClients:    params.K8sClients,
+			PolicyManager: params.PolicyManager,
+		}
+		resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+		if err != nil {
+			t.Fatalf("Failed to construct resourceModel: %v", err)
+		}
+		return resourceModel
+	}
+
+	want := buildModel(t, "foo.example.com", 30).ContentHash()
+	if want == "" {
+		t.Fatalf("ContentHash() returned an empty hash")
+	}
+
+	// Rebuilding the model iterates its maps in a different order.
+	for i := 0; i < 10; i++ {
+		if got := buildModel(t, "foo.example.com", 30).ContentHash(); got != want {
+			t.Fatalf("ContentHash() of identical model = %v; want %v", got, want)
+		}
+	}
+
+	// Metadata maintained by the API server does not change the hash.
+	resourceModel := buildModel(t, "foo.example.com", 30)
+	gatewayNode := resourceModel.Gateways[GatewayID("default", "foo-gateway")]
+	gatewayNode.Gateway.ResourceVersion = "42"
+	gatewayNode.Gateway.Generation = 7
+	if got := resourceModel.ContentHash(); got != want {
+		t.Errorf("ContentHash() after changing the resourceVersion = %v; want %v", got, want)
+	}
+
+	testcases := []struct {
+		name     string
+		hostname string
+		timeout  int64
+	}{
+		{name: "changed spec of a resource", hostname: "bar.example.com", timeout: 30},
+		{name: "changed effective policy", hostname: "foo.example.com", timeout: 60},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := buildModel(t, tc.hostname, tc.timeout).ContentHash(); got == want {
+				t.Errorf("ContentHash() of modified model = %v; want a different hash", got)
+			}
+		})
+	}
+
+	// Dropping an edge changes the hash.
+	resourceModel = buildModel(t, "foo.example.com", 30)
+	delete(resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")].Gateways, GatewayID("bar", "bar-gateway"))
+	if got := resourceModel.ContentHash(); got == want {
+		t.Errorf("ContentHash() after removing an edge = %v; want a different hash", got)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// HostBackends describes which backends can serve requests for a host through
+// a single Gateway, as determined by BackendsForHost.
+type HostBackends struct {
+	// Gateway is the Gateway accepting the host.
+	Gateway *GatewayNode
+	// Listeners are the listeners of the Gateway which accept the host.
+	Listeners []gatewayv1.SectionName
+	// HTTPRoutes are the HTTPRoutes attached to these listeners whose hostnames
+	// match the host, in order of precedence.
+	HTTPRoutes []*HTTPRouteNode
+	// Backends is the union of the backends referenced by the HTTPRoutes and
+	// the default backends of the listeners, in order of the precedence of the
+	// HTTPRoute first referencing them.
+	Backends []HostBackend
+	// RulesWithoutBackends lists the rules of the HTTPRoutes which have no
+	// backendRefs, in order of the precedence of their HTTPRoute. Requests
+	// matching them are answered by their filters, e.g. with a redirect, or
+	// rejected if they have none.
+	RulesWithoutBackends []HostRule
+}
+
+// HostRule is a rule of an HTTPRoute matching a host.
+type HostRule struct {
+	HTTPRoute common.ObjRef
+	RuleIndex int
+	// Filters lists the types of the filters of the rule.
+	Filters []gatewayv1.HTTPRouteFilterType
+}
+
+// HostBackend is a backend which can serve requests for a host.
+type HostBackend struct {
+	// BackendRef is the referenced backend, with its namespace and kind
+	// defaulted.
+	BackendRef common.ObjRef
+	// Backend is the node of the backend. It's nil if the backend is not part
+	// of the ResourceModel.
+	Backend *BackendNode
+	// Refs lists where the backend is referenced.
+	Refs []HostBackendRef
+}
+
+// HostBackendRef is a reference to a HostBackend.
+type HostBackendRef struct {
+	// HTTPRoute is the HTTPRoute referencing the backend in the rule with
+	// RuleIndex. It is empty for default backends.
+	HTTPRoute common.ObjRef
+	RuleIndex int
+	// Weight is the weight of the backendRef within its rule.
+	Weight int32
+	// Default is true if the backend is a default backend of the Gateway or of
+	// one of the listeners, which serves requests not matched by any route.
+	Default bool
+}
+
+// BackendsForHost returns, for each Gateway with a listener accepting the host,
+// the HTTPRoutes which match the host and the union of their backends. Within
+// a Gateway, only the listeners with the most specific hostname matching the
+// host are considered, like in TraceRequest. Gateways are sorted by namespace
+// and name.
+func (rm *ResourceModel) BackendsForHost(host string) []HostBackends {
+	host = strings.ToLower(host)
+	var result []HostBackends
+	for _, gatewayID := range sortedGatewayIDs(rm.Gateways) {
+		gatewayNode := rm.Gateways[gatewayID]
+
+		// Select the listeners with the most specific hostname.
+		var listeners []gatewayv1.Listener
+		var listenerHostname string
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if !acceptsHTTPRoutes(listener) {
+				continue
+			}
+			hostname := ""
+			if listener.Hostname != nil {
+				hostname = string(*listener.Hostname)
+			}
+			if !hostnameMatches(hostname, host) {
+				continue
+			}
+			switch c := compareHostnames(hostname, listenerHostname); {
+			case len(listeners) == 0 || c > 0:
+				listeners, listenerHostname = []gatewayv1.Listener{listener}, hostname
+			case c == 0:
+				listeners = append(listeners, listener)
+			}
+		}
+		if len(listeners) == 0 {
+			continue
+		}
+		hostBackends := HostBackends{Gateway: gatewayNode}
+		for _, listener := range listeners {
+			hostBackends.Listeners = append(hostBackends.Listeners, listener.Name)
+		}
+
+		// Select the HTTPRoutes matching the host, in order of precedence.
+		routeHostnames := make(map[*HTTPRouteNode]string)
+		for _, httpRouteNode := range gatewayNode.HTTPRoutes {
+			for _, listener := range listeners {
+				if !attachedToListener(httpRouteNode.HTTPRoute, gatewayID, listener.Name, listener.Port) {
+					continue
+				}
+				if hostname, ok := matchingRouteHostname(httpRouteNode.HTTPRoute, listenerHostname, host); ok {
+					routeHostnames[httpRouteNode] = hostname
+					hostBackends.HTTPRoutes = append(hostBackends.HTTPRoutes, httpRouteNode)
+					break
+				}
+			}
+		}
+		sort.Slice(hostBackends.HTTPRoutes, func(i, j int) bool {
+			a, b := hostBackends.HTTPRoutes[i], hostBackends.HTTPRoutes[j]
+			if c := compareHostnames(routeHostnames[a], routeHostnames[b]); c != 0 {
+				return c > 0
+			}
+			return httpRouteHasPrecedence(a, b)
+		})
+
+		// Collect the union of their backends.
+		backendIndex := make(map[common.ObjRef]int)
+		addBackend := func(backendRef common.ObjRef, ref HostBackendRef) {
+			i, ok := backendIndex[backendRef]
+			if !ok {
+				i = len(hostBackends.Backends)
+				backendIndex[backendRef] = i
+				hostBackends.Backends = append(hostBackends.Backends, HostBackend{
+					BackendRef: backendRef,
+					Backend:    rm.Backends[BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)],
+				})
+			}
+			hostBackends.Backends[i].Refs = append(hostBackends.Backends[i].Refs, ref)
+		}
+		for _, httpRouteNode := range hostBackends.HTTPRoutes {
+			httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()}
+			for ruleIndex, rule := range httpRouteNode.HTTPRoute.Spec.Rules {
+				if len(rule.BackendRefs) == 0 {
+					hostRule := HostRule{HTTPRoute: httpRouteRef, RuleIndex: ruleIndex}
+					for _, filter := range rule.Filters {
+						hostRule.Filters = append(hostRule.Filters, filter.Type)
+					}
+					hostBackends.RulesWithoutBackends = append(hostBackends.RulesWithoutBackends, hostRule)
+					continue
+				}
+				for _, backendRef := range rule.BackendRefs {
+					addBackend(httpBackendRefObjRef(httpRouteNode.HTTPRoute, backendRef), HostBackendRef{
+						HTTPRoute: httpRouteRef,
+						RuleIndex: ruleIndex,
+						Weight:    backendRefWeight(backendRef),
+					})
+				}
+			}
+		}
+		for _, defaultBackendRef := range gatewayNode.DefaultBackendRefs {
+			if defaultBackendRef.Listener != "" && !containsListener(hostBackends.Listeners, gatewayv1.SectionName(defaultBackendRef.Listener)) {
+				continue
+			}
+			addBackend(defaultBackendRef.BackendRef, HostBackendRef{Default: true})
+		}
+
+		result = append(result, hostBackends)
+	}
+	return result
+}
+
+func containsListener(listeners []gatewayv1.SectionName, name gatewayv1.SectionName) bool {
+	for _, listener := range listeners {
+		if listener == name {
+			return true
+		}
+	}
+	return false
+}
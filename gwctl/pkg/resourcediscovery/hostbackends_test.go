@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_BackendsForHost(t *testing.T) {
+	gateway := func(name string, listeners ...gatewayv1.Listener) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        listeners,
+			},
+		}
+	}
+	listener := func(name, hostname string) gatewayv1.Listener {
+		return gatewayv1.Listener{Name: gatewayv1.SectionName(name), Protocol: gatewayv1.HTTPProtocolType, Port: 80, Hostname: common.PtrTo(gatewayv1.Hostname(hostname))}
+	}
+	backendRef := func(name string, weight int32) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+				Weight: common.PtrTo(weight),
+			},
+		}
+	}
+	httpRoute := func(name, gateway string, created time.Time, hostnames []gatewayv1.Hostname, backendRefs ...gatewayv1.HTTPBackendRef) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}},
+				},
+				Hostnames: hostnames,
+				Rules:     []gatewayv1.HTTPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+	}
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		// external-gateway serves api.foo.com through its more specific api
+		// listener, so HTTPRoutes attached only to the wildcard listener are
+		// not considered.
+		gateway("external-gateway", listener("wildcard", "*.foo.com"), listener("api", "api.foo.com")),
+		gateway("internal-gateway", listener("wildcard", "*.foo.com")),
+		gateway("bar-gateway", listener("bar", "bar.com")),
+
+		// stable-httproute inherits the hostname of the listener, which is as
+		// specific as the hostname of canary-httproute, so the older
+		// stable-httproute takes precedence.
+		httpRoute("stable-httproute", "external-gateway", older, nil, backendRef("api-v1-svc", 90), backendRef("api-v2-svc", 10)),
+		httpRoute("canary-httproute", "external-gateway", newer, []gatewayv1.Hostname{"api.foo.com"}, backendRef("api-v2-svc", 1)),
+		httpRoute("internal-httproute", "internal-gateway", older, []gatewayv1.Hostname{"*.foo.com"}, backendRef("internal-api-svc", 1)),
+		httpRoute("other-httproute", "internal-gateway", older, []gatewayv1.Hostname{"www.foo.com"}, backendRef("www-svc", 1)),
+		httpRoute("bar-httproute", "bar-gateway", older, nil, backendRef("bar-svc", 1)),
+		service("api-v1-svc"),
+		service("api-v2-svc"),
+		service("www-svc"),
+		service("bar-svc"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	type backend struct {
+		BackendRef common.ObjRef
+		Found      bool
+		Refs       []HostBackendRef
+	}
+	type gatewayBackends struct {
+		Gateway    string
+		Listeners  []gatewayv1.SectionName
+		HTTPRoutes []string
+		Backends   []backend
+	}
+	var got []gatewayBackends
+	for _, hostBackends := range resourceModel.BackendsForHost("API.foo.com") {
+		g := gatewayBackends{
+			Gateway:   hostBackends.Gateway.Gateway.GetName(),
+			Listeners: hostBackends.Listeners,
+		}
+		for _, httpRouteNode := range hostBackends.HTTPRoutes {
+			g.HTTPRoutes = append(g.HTTPRoutes, httpRouteNode.HTTPRoute.GetName())
+		}
+		for _, hostBackend := range hostBackends.Backends {
+			g.Backends = append(g.Backends, backend{BackendRef: hostBackend.BackendRef, Found: hostBackend.Backend != nil, Refs: hostBackend.Refs})
+		}
+		got = append(got, g)
+	}
+
+	serviceRef := func(name string) common.ObjRef {
+		return common.ObjRef{Kind: "Service", Name: name, Namespace: "default"}
+	}
+	httpRouteRef := func(name string) common.ObjRef {
+		return common.ObjRef{Kind: "HTTPRoute", Name: name, Namespace: "default"}
+	}
+	want := []gatewayBackends{
+		{
+			Gateway:    "external-gateway",
+			Listeners:  []gatewayv1.SectionName{"api"},
+			HTTPRoutes: []string{"stable-httproute", "canary-httproute"},
+			Backends: []backend{
+				{BackendRef: serviceRef("api-v1-svc"), Found: true, Refs: []HostBackendRef{
+					{HTTPRoute: httpRouteRef("stable-httproute"), Weight: 90},
+				}},
+				{BackendRef: serviceRef("api-v2-svc"), Found: true, Refs: []HostBackendRef{
+					{HTTPRoute: httpRouteRef("stable-httproute"), Weight: 10},
+					{HTTPRoute: httpRouteRef("canary-httproute"), Weight: 1},
+				}},
+			},
+		},
+		{
+			Gateway:    "internal-gateway",
+			Listeners:  []gatewayv1.SectionName{"wildcard"},
+			HTTPRoutes: []string{"internal-httproute"},
+			Backends: []backend{
+				{BackendRef: serviceRef("internal-api-svc"), Refs: []HostBackendRef{
+					{HTTPRoute: httpRouteRef("internal-httproute"), Weight: 1},
+				}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in BackendsForHost(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestResourceModel_BackendsForHost_RulesWithoutBackends(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80}},
+			},
+		},
+		// The first rule only redirects requests and the second one has
+		// neither backendRefs nor filters.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{Filters: []gatewayv1.HTTPRouteFilter{{
+						Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+						RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: common.PtrTo("https")},
+					}}},
+					{},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	hostBackends := resourceModel.BackendsForHost("foo.com")
+	if len(hostBackends) != 1 {
+		t.Fatalf("BackendsForHost() returned %d Gateways; want 1", len(hostBackends))
+	}
+	httpRouteRef := common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"}
+	want := []HostRule{
+		{HTTPRoute: httpRouteRef, RuleIndex: 0, Filters: []gatewayv1.HTTPRouteFilterType{gatewayv1.HTTPRouteFilterRequestRedirect}},
+		{HTTPRoute: httpRouteRef, RuleIndex: 1},
+	}
+	if diff := cmp.Diff(want, hostBackends[0].RulesWithoutBackends); diff != "" {
+		t.Errorf("Unexpected diff in RulesWithoutBackends (-want +got):\n%v", diff)
+	}
+	if len(hostBackends[0].Backends) != 0 {
+		t.Errorf("Backends = %v; want none", hostBackends[0].Backends)
+	}
+}
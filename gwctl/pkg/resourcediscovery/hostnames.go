@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EffectiveHostnames returns, for each Gateway the HTTPRoute is attached to,
+// the sorted hostnames the HTTPRoute serves through the listeners it attaches
+// to. These are the intersection of the hostnames declared by the HTTPRoute
+// with the hostnames of the listeners, where "*" stands for any hostname. An
+// empty list means that the HTTPRoute serves none of its hostnames through
+// the Gateway.
+func (h *HTTPRouteNode) EffectiveHostnames() map[gatewayID][]string {
+	result := make(map[gatewayID][]string)
+	for gatewayID, gatewayNode := range h.Gateways {
+		hostnames := make(map[string]bool)
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if !acceptsHTTPRoutes(listener) || !attachedToListener(h.HTTPRoute, gatewayID, listener.Name, listener.Port) {
+				continue
+			}
+			for _, hostname := range servedHostnames(h.HTTPRoute, listener) {
+				hostnames[displayHostname(hostname)] = true
+			}
+		}
+
+		result[gatewayID] = []string{}
+		for hostname := range hostnames {
+			result[gatewayID] = append(result[gatewayID], hostname)
+		}
+		sort.Strings(result[gatewayID])
+	}
+	return result
+}
+
+// UnservedListener describes a listener of a Gateway through which no
+// HTTPRoute serves any hostname.
+type UnservedListener struct {
+	// Listener is the name of the listener.
+	Listener gatewayv1.SectionName
+	// Hostname is the hostname of the listener.
+	Hostname string
+	// AttachedHTTPRoutes is the number of HTTPRoutes attached to the listener,
+	// none of which declare a hostname intersecting with the hostname of the
+	// listener.
+	AttachedHTTPRoutes int
+}
+
+// UnservedListeners returns the listeners of the Gateway which accept
+// HTTPRoutes and have a hostname, but serve nothing: either no HTTPRoute is
+// attached to them, or none of the hostnames of the attached HTTPRoutes
+// intersect with the hostname of the listener. Listeners without a hostname
+// serve any hostname, so they are never returned.
+func (g *GatewayNode) UnservedListeners() []UnservedListener {
+	gatewayID := g.ID()
+	var result []UnservedListener
+	for _, listener := range g.Gateway.Spec.Listeners {
+		if !acceptsHTTPRoutes(listener) || listener.Hostname == nil || *listener.Hostname == "" {
+			continue
+		}
+		listenerHostname := string(*listener.Hostname)
+		attached, served := 0, false
+		for _, httpRouteNode := range g.HTTPRoutes {
+			if !attachedToListener(httpRouteNode.HTTPRoute, gatewayID, listener.Name, listener.Port) {
+				continue
+			}
+			attached++
+			if len(httpRouteNode.HTTPRoute.Spec.Hostnames) == 0 {
+				served = true
+			}
+			for _, routeHostname := range httpRouteNode.HTTPRoute.Spec.Hostnames {
+				if _, ok := intersectHostnames(listenerHostname, string(routeHostname)); ok {
+					served = true
+				}
+			}
+		}
+		if !served {
+			result = append(result, UnservedListener{Listener: listener.Name, Hostname: listenerHostname, AttachedHTTPRoutes: attached})
+		}
+	}
+	return result
+}
+
+// servedHostnames returns the hostnames the HTTPRoute serves through the
+// listener: the intersections of the hostnames of the HTTPRoute with the
+// hostname of the listener, or the hostname of the listener if the HTTPRoute
+// declares none. An empty hostname stands for any hostname.
+func servedHostnames(httpRoute *gatewayv1.HTTPRoute, listener gatewayv1.Listener) []string {
+	if len(httpRoute.Spec.Hostnames) == 0 {
+		return []string{listenerHostname(listener)}
+	}
+	var result []string
+	for _, routeHostname := range httpRoute.Spec.Hostnames {
+		if hostname, ok := intersectHostnames(listenerHostname(listener), string(routeHostname)); ok {
+			result = append(result, hostname)
+		}
+	}
+	return result
+}
+
+// intersectHostnames returns the more specific of the listener and route
+// hostnames if one of them matches the other, e.g. "foo.example.com" for
+// "*.example.com" and "foo.example.com". An empty listener hostname matches any
+// route hostname.
+func intersectHostnames(listenerHostname, routeHostname string) (string, bool) {
+	listenerHostname, routeHostname = strings.ToLower(listenerHostname), strings.ToLower(routeHostname)
+	if hostnameMatches(listenerHostname, routeHostname) {
+		return routeHostname, true
+	}
+	if hostnameMatches(routeHostname, listenerHostname) {
+		return listenerHostname, true
+	}
+	return "", false
+}
+
+func displayHostname(hostname string) string {
+	if hostname == "" {
+		return "*"
+	}
+	return hostname
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestHTTPRouteNode_EffectiveHostnames(t *testing.T) {
+	gatewayNode := NewGatewayNode(&gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "foo-gatewayclass",
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "wildcard",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+					Hostname: common.PtrTo(gatewayv1.Hostname("*.example.com")),
+				},
+				{
+					Name:     "api",
+					Protocol: gatewayv1.HTTPSProtocolType,
+					Port:     443,
+					Hostname: common.PtrTo(gatewayv1.Hostname("api.foo.com")),
+				},
+				{
+					// HTTPRoutes can not attach to TCP listeners.
+					Name:     "tcp",
+					Protocol: gatewayv1.TCPProtocolType,
+					Port:     9000,
+				},
+			},
+		},
+	})
+
+	testcases := []struct {
+		name       string
+		hostnames  []gatewayv1.Hostname
+		parentRefs []gatewayv1.ParentReference
+		want       []string
+	}{
+		{
+			name:       "declared hostnames partially intersect the listeners",
+			hostnames:  []gatewayv1.Hostname{"foo.example.com", "*.foo.com", "bar.com"},
+			parentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+			want:       []string{"api.foo.com", "foo.example.com"},
+		},
+		{
+			name:       "wildcard hostnames of the route and listener",
+			hostnames:  []gatewayv1.Hostname{"*.bar.example.com", "*.com"},
+			parentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+			want:       []string{"*.bar.example.com", "*.example.com", "api.foo.com"},
+		},
+		{
+			name:       "attached to a single listener",
+			hostnames:  []gatewayv1.Hostname{"foo.example.com", "api.foo.com"},
+			parentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway", SectionName: common.PtrTo(gatewayv1.SectionName("api"))}},
+			want:       []string{"api.foo.com"},
+		},
+		{
+			name:       "without hostnames",
+			parentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+			want:       []string{"*.example.com", "api.foo.com"},
+		},
+		{
+			name:       "no declared hostname is served",
+			hostnames:  []gatewayv1.Hostname{"bar.com"},
+			parentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+			want:       []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRouteNode := NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-httproute",
+					Namespace: "default",
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: tc.parentRefs},
+					Hostnames:       tc.hostnames,
+				},
+			})
+			httpRouteNode.Gateways[gatewayNode.ID()] = gatewayNode
+
+			want := map[gatewayID][]string{gatewayNode.ID(): tc.want}
+			if diff := cmp.Diff(want, httpRouteNode.EffectiveHostnames()); diff != "" {
+				t.Errorf("EffectiveHostnames() diff (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
+)
+
+// Clone returns a deep copy of the ResourceModel. The clone shares no nodes or
+// resources with the original, so it can be modified (for example through
+// AddHypothetical) without affecting the original.
+func (rm *ResourceModel) Clone() *ResourceModel {
+	clone := &ResourceModel{
+		IgnoredNamespaces: append(NamespaceIgnoreList(nil), rm.IgnoredNamespaces...),
+
+		requireParentReferenceGrants: rm.requireParentReferenceGrants,
+		skipDeletingPolicies:         rm.skipDeletingPolicies,
+	}
+	if rm.NamespaceLabels != nil {
+		clone.NamespaceLabels = make(NamespaceLabelIndex, len(rm.NamespaceLabels))
+		for namespace, namespaceLabels := range rm.NamespaceLabels {
+			clone.NamespaceLabels[namespace] = labels.Merge(nil, namespaceLabels)
+		}
+	}
+	for kind, err := range rm.SkippedKinds {
+		if clone.SkippedKinds == nil {
+			clone.SkippedKinds = make(map[string]error)
+		}
+		clone.SkippedKinds[kind] = err
+	}
+	for _, policy := range rm.DeletingPolicies {
+		clone.DeletingPolicies = append(clone.DeletingPolicies, policy.DeepCopy())
+	}
+	for id := range rm.hypothetical {
+		clone.markHypothetical(id)
+	}
+	clone.mergeRules = rm.mergeRules
+
+	for gatewayClassID, gatewayClassNode := range rm.GatewayClasses {
+		clone.addGatewayClasses(*gatewayClassNode.GatewayClass.DeepCopy())
+		if gatewayClassNode.Parameters != nil {
+			clone.GatewayClasses[gatewayClassID].Parameters = gatewayClassNode.Parameters.DeepCopy()
+		}
+		clone.GatewayClasses[gatewayClassID].Errors = append([]error{}, gatewayClassNode.Errors...)
+	}
+	for _, namespaceNode := range rm.Namespaces {
+		clone.addNamespace(*namespaceNode.Namespace.DeepCopy())
+	}
+	for gatewayID, gatewayNode := range rm.Gateways {
+		clone.addGateways(fetchedGateway{
+			Gateway:            *gatewayNode.Gateway.DeepCopy(),
+			defaultBackendRefs: append([]DefaultBackendRef(nil), gatewayNode.DefaultBackendRefs...),
+		})
+		clone.Gateways[gatewayID].Events = append([]corev1.Event{}, gatewayNode.Events...)
+		clone.Gateways[gatewayID].Errors = append([]error{}, gatewayNode.Errors...)
+	}
+	for httpRouteID, httpRouteNode := range rm.HTTPRoutes {
+		clone.addHTTPRoutes(fetchedHTTPRoute{
+			HTTPRoute:         *httpRouteNode.HTTPRoute.DeepCopy(),
+			filters:           append([]FilterSummary(nil), httpRouteNode.Filters...),
+			ruleNames:         append([]string(nil), httpRouteNode.RuleNames...),
+			namedBackendPorts: append([]NamedBackendPort(nil), httpRouteNode.NamedBackendPorts...),
+		})
+		clone.HTTPRoutes[httpRouteID].Errors = append([]error{}, httpRouteNode.Errors...)
+	}
+	for backendID, backendNode := range rm.Backends {
+		clone.addBackends(*backendNode.Backend.DeepCopy())
+		cloneNode := clone.Backends[backendID]
+		if backendNode.EndpointZones != nil {
+			cloneNode.EndpointZones = make(map[string]int)
+			for zone, count := range backendNode.EndpointZones {
+				cloneNode.EndpointZones[zone] = count
+			}
+		}
+		cloneNode.ReadyEndpoints = backendNode.ReadyEndpoints
+		cloneNode.EndpointsDiscovered = backendNode.EndpointsDiscovered
+		cloneNode.TrafficDistribution = backendNode.TrafficDistribution
+		cloneNode.Errors = append([]error{}, backendNode.Errors...)
+	}
+	for _, referenceGrantNode := range rm.ReferenceGrants {
+		clone.addReferenceGrants(*referenceGrantNode.ReferenceGrant.DeepCopy())
+	}
+	for _, extensionRefNode := range rm.ExtensionRefs {
+		clone.addExtensionRefs(*extensionRefNode.Object.DeepCopy())
+	}
+	for _, secretNode := range rm.Secrets {
+		if secretNode.Missing {
+			clone.addMissingSecret(secretNode.Secret.GetNamespace(), secretNode.Secret.GetName())
+			continue
+		}
+		clone.addSecrets(*secretNode.Secret.DeepCopy())
+	}
+
+	for gatewayID, gatewayNode := range rm.Gateways {
+		if gatewayNode.GatewayClass != nil {
+			clone.connectGatewayWithGatewayClass(gatewayID, gatewayNode.GatewayClass.ID())
+		}
+		if gatewayNode.Namespace != nil {
+			clone.connectGatewayWithNamespace(gatewayID, gatewayNode.Namespace.ID())
+		}
+		for backendID := range gatewayNode.DefaultBackends {
+			clone.connectGatewayWithDefaultBackend(gatewayID, backendID)
+		}
+		// ReferenceGrants are connected before HTTPRoutes, which may require them
+		// to attach to the Gateway.
+		for referenceGrantID := range gatewayNode.ReferenceGrants {
+			clone.connectReferenceGrantWithGateway(referenceGrantID, gatewayID)
+		}
+	}
+	for httpRouteID, httpRouteNode := range rm.HTTPRoutes {
+		for gatewayID := range httpRouteNode.Gateways {
+			clone.connectHTTPRouteWithGateway(httpRouteID, gatewayID)
+		}
+		for backendID := range httpRouteNode.ParentServices {
+			clone.connectHTTPRouteWithParentService(httpRouteID, backendID)
+		}
+		for backendID := range httpRouteNode.Backends {
+			clone.connectHTTPRouteWithBackend(httpRouteID, backendID)
+		}
+		for extensionRefID := range httpRouteNode.ExtensionRefs {
+			clone.connectHTTPRouteWithExtensionRef(httpRouteID, extensionRefID)
+		}
+		for secretID := range httpRouteNode.Secrets {
+			clone.connectHTTPRouteWithSecret(httpRouteID, secretID)
+		}
+		if httpRouteNode.Namespace != nil {
+			clone.connectHTTPRouteWithNamespace(httpRouteID, httpRouteNode.Namespace.ID())
+		}
+	}
+	for backendID, backendNode := range rm.Backends {
+		if backendNode.Namespace != nil {
+			clone.connectBackendWithNamespace(backendID, backendNode.Namespace.ID())
+		}
+	}
+	for referenceGrantID, referenceGrantNode := range rm.ReferenceGrants {
+		for backendID := range referenceGrantNode.Backends {
+			clone.connectReferenceGrantWithBackend(referenceGrantID, backendID)
+		}
+	}
+
+	for policyID, policyNode := range rm.Policies {
+		clone.clonePolicyNode(policyID, policyNode)
+	}
+
+	// Effective policies are derived from the nodes and edges copied above, so
+	// they are recalculated instead of copied.
+	if err := clone.calculateEffectivePolicies(); err != nil {
+		klog.V(1).ErrorS(err, "Failed to calculate effective policies for cloned ResourceModel")
+	}
+	return clone
+}
+
+// clonePolicyNode adds a copy of policyNode, which belongs to a different
+// ResourceModel, and connects it to the same targets within rm.
+func (rm *ResourceModel) clonePolicyNode(id policyID, policyNode *PolicyNode) {
+	if rm.Policies == nil {
+		rm.Policies = make(map[policyID]*PolicyNode)
+	}
+	policy := policyNode.Policy.DeepCopy()
+	cloneNode := NewPolicyNode(&policy)
+	rm.Policies[id] = cloneNode
+
+	if policyNode.GatewayClass != nil {
+		cloneNode.GatewayClass = rm.GatewayClasses[policyNode.GatewayClass.ID()]
+		cloneNode.GatewayClass.Policies[id] = cloneNode
+	}
+	if policyNode.Namespace != nil {
+		cloneNode.Namespace = rm.Namespaces[policyNode.Namespace.ID()]
+		cloneNode.Namespace.Policies[id] = cloneNode
+	}
+	if policyNode.Gateway != nil {
+		cloneNode.Gateway = rm.Gateways[policyNode.Gateway.ID()]
+		cloneNode.Gateway.Policies[id] = cloneNode
+	}
+	if policyNode.HTTPRoute != nil {
+		cloneNode.HTTPRoute = rm.HTTPRoutes[policyNode.HTTPRoute.ID()]
+		cloneNode.HTTPRoute.Policies[id] = cloneNode
+	}
+	if policyNode.Backend != nil {
+		cloneNode.Backend = rm.Backends[policyNode.Backend.ID()]
+		cloneNode.Backend.Policies[id] = cloneNode
+	}
+	for targetID := range policyNode.SelectedGatewayClasses {
+		cloneNode.SelectedGatewayClasses[targetID] = rm.GatewayClasses[targetID]
+		rm.GatewayClasses[targetID].Policies[id] = cloneNode
+	}
+	for targetID := range policyNode.SelectedNamespaces {
+		cloneNode.SelectedNamespaces[targetID] = rm.Namespaces[targetID]
+		rm.Namespaces[targetID].Policies[id] = cloneNode
+	}
+	for targetID := range policyNode.SelectedGateways {
+		cloneNode.SelectedGateways[targetID] = rm.Gateways[targetID]
+		rm.Gateways[targetID].Policies[id] = cloneNode
+	}
+	for targetID := range policyNode.SelectedHTTPRoutes {
+		cloneNode.SelectedHTTPRoutes[targetID] = rm.HTTPRoutes[targetID]
+		rm.HTTPRoutes[targetID].Policies[id] = cloneNode
+	}
+	for targetID := range policyNode.SelectedBackends {
+		cloneNode.SelectedBackends[targetID] = rm.Backends[targetID]
+		rm.Backends[targetID].Policies[id] = cloneNode
+	}
+}
+
+// AddHypothetical inserts hypothetical resources into the ResourceModel, to
+// preview how a proposed change would fit into the existing topology. Each
+// resource is added and connected to the existing nodes the same way the
+// Discoverer would, and its node is marked as hypothetical (see
+// IsHypothetical). Effective policies are recalculated afterwards.
+//
+// AddHypothetical modifies the ResourceModel, so it is typically called on a
+// Clone:
+//
+//	preview, err := resourceModel.Clone().AddHypothetical(httpRoute)
+//
+// Supported resources are Gateways, HTTPRoutes, Services and other Backends as
+// unstructured objects. Policies which exist in the ResourceModel are not
+// re-evaluated against hypothetical nodes.
+func (rm *ResourceModel) AddHypothetical(objects ...client.Object) (*ResourceModel, error) {
+	for _, object := range objects {
+		switch object := object.(type) {
+		case *gatewayv1.Gateway:
+			rm.addHypotheticalGateway(object.DeepCopy())
+		case *gatewayv1.HTTPRoute:
+			if err := rm.addHypotheticalHTTPRoute(object); err != nil {
+				return rm, err
+			}
+		case *corev1.Service:
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+			if err != nil {
+				return rm, fmt.Errorf("failed to convert Service %v/%v to unstructured: %v", object.GetNamespace(), object.GetName(), err)
+			}
+			backend := &unstructured.Unstructured{Object: content}
+			backend.SetAPIVersion("v1")
+			backend.SetKind("Service")
+			rm.addHypotheticalBackend(backend)
+		case *unstructured.Unstructured:
+			rm.addHypotheticalBackend(object.DeepCopy())
+		default:
+			return rm, fmt.Errorf("unsupported hypothetical resource of type %T", object)
+		}
+	}
+	return rm, rm.calculateEffectivePolicies()
+}
+
+// IsHypothetical returns true if the node was inserted through AddHypothetical
+// instead of being discovered.
+func (rm *ResourceModel) IsHypothetical(node Node) bool {
+	return rm.hypothetical[node.NodeID()]
+}
+
+func (rm *ResourceModel) markHypothetical(nodeID string) {
+	if rm.hypothetical == nil {
+		rm.hypothetical = make(map[string]bool)
+	}
+	rm.hypothetical[nodeID] = true
+}
+
+func (rm *ResourceModel) addHypotheticalGateway(gateway *gatewayv1.Gateway) {
+	rm.addGateways(fetchedGateway{Gateway: *gateway})
+	gatewayID := GatewayID(gateway.GetNamespace(), gateway.GetName())
+	rm.markHypothetical(rm.Gateways[gatewayID].NodeID())
+
+	gatewayClassID := GatewayClassID(relations.FindGatewayClassNameForGateway(*gateway))
+	if _, ok := rm.GatewayClasses[gatewayClassID]; ok {
+		rm.connectGatewayWithGatewayClass(gatewayID, gatewayClassID)
+	}
+	if _, ok := rm.Namespaces[NamespaceID(gateway.GetNamespace())]; ok {
+		rm.connectGatewayWithNamespace(gatewayID, NamespaceID(gateway.GetNamespace()))
+	}
+	gatewayRef := common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Name: gateway.GetName(), Namespace: gateway.GetNamespace()}
+	for referenceGrantID, referenceGrantNode := range rm.ReferenceGrants {
+		if relations.ReferenceGrantExposes(*referenceGrantNode.ReferenceGrant, gatewayRef) {
+			rm.connectReferenceGrantWithGateway(referenceGrantID, gatewayID)
+		}
+	}
+	for httpRouteID, httpRouteNode := range rm.HTTPRoutes {
+		for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if GatewayID(gatewayRef.Namespace, gatewayRef.Name) == gatewayID {
+				rm.connectHTTPRouteWithGateway(httpRouteID, gatewayID)
+			}
+		}
+	}
+}
+
+func (rm *ResourceModel) addHypotheticalHTTPRoute(httpRoute *gatewayv1.HTTPRoute) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(httpRoute)
+	if err != nil {
+		return fmt.Errorf("failed to convert HTTPRoute %v/%v to unstructured: %v", httpRoute.GetNamespace(), httpRoute.GetName(), err)
+	}
+	httpRouteUnstructured := &unstructured.Unstructured{Object: content}
+	if httpRouteUnstructured.GetKind() == "" {
+		httpRouteUnstructured.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	}
+	fetched, err := newFetchedHTTPRoute(httpRouteUnstructured)
+	if err != nil {
+		return err
+	}
+	rm.addHTTPRoutes(fetched)
+	httpRouteID := HTTPRouteID(httpRoute.GetNamespace(), httpRoute.GetName())
+	httpRouteNode := rm.HTTPRoutes[httpRouteID]
+	rm.markHypothetical(httpRouteNode.NodeID())
+
+	for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+		if _, ok := rm.Gateways[GatewayID(gatewayRef.Namespace, gatewayRef.Name)]; ok {
+			rm.connectHTTPRouteWithGateway(httpRouteID, GatewayID(gatewayRef.Namespace, gatewayRef.Name))
+		}
+	}
+	for _, serviceRef := range relations.FindServiceParentRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+		if _, ok := rm.Backends[BackendIDForService(serviceRef.Namespace, serviceRef.Name)]; ok {
+			rm.connectHTTPRouteWithParentService(httpRouteID, BackendIDForService(serviceRef.Namespace, serviceRef.Name))
+		}
+	}
+	for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+		backendID := BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)
+		if backendNode, ok := rm.Backends[backendID]; ok && httpRouteReferenceAccepted(*httpRouteNode.HTTPRoute, backendRef, backendNode) {
+			rm.connectHTTPRouteWithBackend(httpRouteID, backendID)
+		}
+	}
+	for _, filter := range httpRouteNode.Filters {
+		if filter.ExtensionRef == nil {
+			continue
+		}
+		extensionRefID := ExtensionRefID(string(filter.ExtensionRef.Group), string(filter.ExtensionRef.Kind), httpRoute.GetNamespace(), string(filter.ExtensionRef.Name))
+		if _, ok := rm.ExtensionRefs[extensionRefID]; ok {
+			rm.connectHTTPRouteWithExtensionRef(httpRouteID, extensionRefID)
+		}
+	}
+	for _, ref := range secretRefsOfHTTPRoute(httpRouteNode) {
+		if _, ok := rm.Secrets[SecretID(ref.Namespace, ref.Name)]; ok {
+			rm.connectHTTPRouteWithSecret(httpRouteID, SecretID(ref.Namespace, ref.Name))
+		}
+	}
+	if _, ok := rm.Namespaces[NamespaceID(httpRoute.GetNamespace())]; ok {
+		rm.connectHTTPRouteWithNamespace(httpRouteID, NamespaceID(httpRoute.GetNamespace()))
+	}
+	return nil
+}
+
+func (rm *ResourceModel) addHypotheticalBackend(backend *unstructured.Unstructured) {
+	rm.addBackends(*backend)
+	backendNode := rm.Backends[BackendID(backend.GroupVersionKind().Group, backend.GetKind(), backend.GetNamespace(), backend.GetName())]
+	backendID := backendNode.ID()
+	rm.markHypothetical(backendNode.NodeID())
+
+	if _, ok := rm.Namespaces[NamespaceID(backend.GetNamespace())]; ok {
+		rm.connectBackendWithNamespace(backendID, NamespaceID(backend.GetNamespace()))
+	}
+	backendRef := common.ObjRef{
+		Group:     backend.GroupVersionKind().Group,
+		Kind:      backend.GetKind(),
+		Name:      backend.GetName(),
+		Namespace: backend.GetNamespace(),
+	}
+	for referenceGrantID, referenceGrantNode := range rm.ReferenceGrants {
+		if relations.ReferenceGrantExposes(*referenceGrantNode.ReferenceGrant, backendRef) {
+			rm.connectReferenceGrantWithBackend(referenceGrantID, backendID)
+		}
+	}
+	for httpRouteID, httpRouteNode := range rm.HTTPRoutes {
+		for _, ref := range relations.FindBackendRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if BackendID(ref.Group, ref.Kind, ref.Namespace, ref.Name) == backendID && httpRouteReferenceAccepted(*httpRouteNode.HTTPRoute, ref, backendNode) {
+				rm.connectHTTPRouteWithBackend(httpRouteID, backendID)
+			}
+		}
+		for _, serviceRef := range relations.FindServiceParentRefsForHTTPRoute(*httpRouteNode.HTTPRoute) {
+			if BackendIDForService(serviceRef.Namespace, serviceRef.Name) == backendID {
+				rm.connectHTTPRouteWithParentService(httpRouteID, backendID)
+			}
+		}
+	}
+}
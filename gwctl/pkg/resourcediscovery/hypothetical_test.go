@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_AddHypothetical(t *testing.T) {
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+	httpRoute := func(name, backend string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{
+						BackendRefs: []gatewayv1.HTTPBackendRef{{
+							BackendRef: gatewayv1.BackendRef{
+								BackendObjectReference: gatewayv1.BackendObjectReference{
+									Kind: common.PtrTo(gatewayv1.Kind("Service")),
+									Name: gatewayv1.ObjectName(backend),
+									Port: common.PtrTo(gatewayv1.PortNumber(80)),
+								},
+							},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		httpRoute("foo-httproute", "foo-svc"),
+		service("foo-svc"),
+		service("bar-svc"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	clone, err := resourceModel.Clone().AddHypothetical(httpRoute("bar-httproute", "bar-svc"))
+	if err != nil {
+		t.Fatalf("AddHypothetical() failed: %v", err)
+	}
+
+	gatewayID := GatewayID("default", "foo-gateway")
+	barHTTPRouteID := HTTPRouteID("default", "bar-httproute")
+	barSvcID := BackendIDForService("default", "bar-svc")
+
+	// The original must be untouched.
+	if _, ok := resourceModel.HTTPRoutes[barHTTPRouteID]; ok {
+		t.Errorf("Original resourceModel contains hypothetical HTTPRoute %v", barHTTPRouteID)
+	}
+	if got := len(resourceModel.Gateways[gatewayID].HTTPRoutes); got != 1 {
+		t.Errorf("Original Gateway has %d HTTPRoutes, want 1", got)
+	}
+	if got := len(resourceModel.Backends[barSvcID].HTTPRoutes); got != 0 {
+		t.Errorf("Original Service bar-svc has %d HTTPRoutes, want 0", got)
+	}
+
+	// The clone must have the hypothetical HTTPRoute along with its edges.
+	barHTTPRouteNode, ok := clone.HTTPRoutes[barHTTPRouteID]
+	if !ok {
+		t.Fatalf("Clone does not contain hypothetical HTTPRoute %v", barHTTPRouteID)
+	}
+	if clone.Gateways[gatewayID] == resourceModel.Gateways[gatewayID] {
+		t.Errorf("Clone shares Gateway node with the original resourceModel")
+	}
+	if clone.Gateways[gatewayID].HTTPRoutes[barHTTPRouteID] != barHTTPRouteNode {
+		t.Errorf("Gateway in clone is not connected to hypothetical HTTPRoute")
+	}
+	if clone.Backends[barSvcID].HTTPRoutes[barHTTPRouteID] != barHTTPRouteNode {
+		t.Errorf("Service bar-svc in clone is not connected to hypothetical HTTPRoute")
+	}
+	if !clone.IsHypothetical(barHTTPRouteNode) {
+		t.Errorf("IsHypothetical(%v) = false, want true", barHTTPRouteNode.NodeID())
+	}
+	if clone.IsHypothetical(clone.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]) {
+		t.Errorf("IsHypothetical() = true for discovered HTTPRoute, want false")
+	}
+	if errs := clone.Validate(); len(errs) != 0 {
+		t.Errorf("Clone failed validation: %v", errs)
+	}
+
+	var buf bytes.Buffer
+	if err := clone.ToMermaid(&buf); err != nil {
+		t.Fatalf("ToMermaid() failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `["HTTPRoute/default/bar-httproute (hypothetical)"]`) {
+		t.Errorf("ToMermaid() output does not mark hypothetical HTTPRoute:\n%v", buf.String())
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// InheritedPolicySource lists the inheritable policies of a resource which
+// flow into the resources of a Namespace.
+type InheritedPolicySource struct {
+	// Source references the resource the policies are attached to.
+	Source common.ObjRef
+	// Policies are the inheritable parts of the policies, sorted by kind,
+	// namespace and name.
+	Policies []policymanager.Policy
+}
+
+// InheritedPolicySummary returns the inheritable policies which apply to the
+// resources in the Namespace through one of their ancestors, grouped by the
+// resource they are attached to. The Gateways in the Namespace and their
+// GatewayClasses are sources, as are the Gateways (and their GatewayClasses)
+// which the HTTPRoutes in the Namespace attach to. Policies of a Gateway in another
+// namespace are only included if their kind propagates across namespaces.
+// GatewayClasses are listed before Gateways, each sorted by name.
+func (n *NamespaceNode) InheritedPolicySummary() []InheritedPolicySource {
+	sources := make(map[common.ObjRef][]policymanager.Policy)
+	addSource := func(source common.ObjRef, policyNodes map[policyID]*PolicyNode) {
+		if _, ok := sources[source]; ok {
+			return
+		}
+		var result []policymanager.Policy
+		for _, policy := range convertPoliciesMapToSlice(policyNodes) {
+			if !policy.IsInherited() {
+				continue
+			}
+			byKind := map[policymanager.PolicyCrdID]policymanager.Policy{policy.PolicyCrdID(): policy}
+			if source.Namespace != "" {
+				byKind = filterCrossNamespacePolicies(byKind, source.Namespace, n.Namespace.GetName())
+			}
+			for _, inheritable := range filterInheritablePolicies(byKind) {
+				result = append(result, inheritable)
+			}
+		}
+		sources[source] = result
+	}
+	addGatewayClassSource := func(gatewayNode *GatewayNode) {
+		if gatewayNode.GatewayClass == nil {
+			return
+		}
+		addSource(common.ObjRef{
+			Group: gatewayv1.GroupName,
+			Kind:  "GatewayClass",
+			Name:  gatewayNode.GatewayClass.GatewayClass.GetName(),
+		}, gatewayNode.GatewayClass.Policies)
+	}
+
+	for gatewayID, gatewayNode := range n.Gateways {
+		addGatewayClassSource(gatewayNode)
+		addSource(gatewayObjRef(gatewayID), gatewayNode.Policies)
+	}
+	for _, httpRouteNode := range n.HTTPRoutes {
+		for gatewayID, gatewayNode := range httpRouteNode.Gateways {
+			addGatewayClassSource(gatewayNode)
+			addSource(gatewayObjRef(gatewayID), gatewayNode.Policies)
+		}
+	}
+
+	var result []InheritedPolicySource
+	for source, policies := range sources {
+		if len(policies) == 0 {
+			continue
+		}
+		result = append(result, InheritedPolicySource{Source: source, Policies: policies})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Source, result[j].Source
+		if a.Kind != b.Kind {
+			// GatewayClasses are higher in the hierarchy than Gateways.
+			return a.Kind == "GatewayClass"
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
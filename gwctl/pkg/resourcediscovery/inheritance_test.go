@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestNamespaceNode_InheritedPolicySummary(t *testing.T) {
+	policyCRD := func(kind, plural, policyType string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   plural + ".foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: policyType},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	gatewayClassPolicy := func(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+		spec["targetRef"] = map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "GatewayClass",
+			"name":  "foo-gatewayclass",
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       kind,
+				"metadata":   map[string]interface{}{"name": name},
+				"spec":       spec,
+			},
+		}
+	}
+
+	retryPolicyCRD := policyCRD("RetryPolicy", "retrypolicies", "inherited")
+	retryPolicyCRD.Spec.Scope = apiextensionsv1.NamespaceScoped
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("foo-ns"),
+		common.NamespaceForTest("bar-ns"),
+		common.NamespaceForTest("baz-ns"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.net/gateway-controller"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "foo-ns"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		// baz-route attaches to foo-gateway in another namespace.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "baz-route", Namespace: "baz-ns"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Name:      "foo-gateway",
+						Namespace: common.PtrTo(gatewayv1.Namespace("foo-ns")),
+					}},
+				},
+			},
+		},
+
+		policyCRD("TimeoutPolicy", "timeoutpolicies", "inherited"),
+		policyCRD("TLSPolicy", "tlspolicies", "direct"),
+		gatewayClassPolicy("TimeoutPolicy", "timeout-gatewayclass", map[string]interface{}{
+			"default": map[string]interface{}{"connect": int64(5)},
+		}),
+		// Direct policies are not inherited by the resources of a Namespace.
+		gatewayClassPolicy("TLSPolicy", "tls-gatewayclass", map[string]interface{}{
+			"minVersion": "1.2",
+		}),
+		retryPolicyCRD,
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "RetryPolicy",
+				"metadata":   map[string]interface{}{"name": "retry-gateway", "namespace": "foo-ns"},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{"attempts": int64(3)},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForNamespaceContents(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	type source struct {
+		Source   common.ObjRef
+		Policies []policymanager.ObjRef
+	}
+	summary := func(namespace string) []source {
+		namespaceNode, ok := resourceModel.Namespaces[NamespaceID(namespace)]
+		if !ok {
+			t.Fatalf("Namespace %v not found in resourceModel", namespace)
+		}
+		var result []source
+		for _, inherited := range namespaceNode.InheritedPolicySummary() {
+			result = append(result, source{
+				Source:   inherited.Source,
+				Policies: policymanager.ToPolicyRefs(inherited.Policies),
+			})
+		}
+		return result
+	}
+
+	wantFooNs := []source{
+		{
+			Source:   common.ObjRef{Group: gatewayv1.GroupName, Kind: "GatewayClass", Name: "foo-gatewayclass"},
+			Policies: []policymanager.ObjRef{{Group: "foo.com", Kind: "TimeoutPolicy", Name: "timeout-gatewayclass"}},
+		},
+		{
+			Source:   common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "foo-ns", Name: "foo-gateway"},
+			Policies: []policymanager.ObjRef{{Group: "foo.com", Kind: "RetryPolicy", Namespace: "foo-ns", Name: "retry-gateway"}},
+		},
+	}
+	if diff := cmp.Diff(wantFooNs, summary("foo-ns")); diff != "" {
+		t.Errorf("Unexpected diff in InheritedPolicySummary() of foo-ns; diff (-want +got)=\n%v", diff)
+	}
+
+	// baz-ns only hosts an HTTPRoute attaching to foo-gateway in foo-ns.
+	if diff := cmp.Diff(wantFooNs, summary("baz-ns")); diff != "" {
+		t.Errorf("Unexpected diff in InheritedPolicySummary() of baz-ns; diff (-want +got)=\n%v", diff)
+	}
+
+	// bar-ns hosts no Gateway of foo-gatewayclass.
+	if got := summary("bar-ns"); len(got) != 0 {
+		t.Errorf("InheritedPolicySummary() of bar-ns=%v; want empty", got)
+	}
+}
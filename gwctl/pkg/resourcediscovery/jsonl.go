@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+// JSONL record types, set in the "type" field of each record.
+const (
+	JSONLRecordNode = "node"
+	JSONLRecordEdge = "edge"
+)
+
+// jsonlNode is the record of a node written by StreamJSONL.
+type jsonlNode struct {
+	Type         string        `json:"type"`
+	ID           string        `json:"id"`
+	Kind         string        `json:"kind"`
+	Hypothetical bool          `json:"hypothetical,omitempty"`
+	Object       client.Object `json:"object"`
+}
+
+// jsonlEdge is the record of an edge written by StreamJSONL.
+type jsonlEdge struct {
+	Type   string `json:"type"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Policy bool   `json:"policy,omitempty"`
+}
+
+// StreamJSONL writes the ResourceModel to w in the JSON Lines format: one
+// record per line, first a "node" record for each node in the order of
+// SortedNodes, then an "edge" record for each edge in the order of
+// SortedEdges. Edges refer to nodes by their NodeID. Records are encoded and
+// written one at a time, so consumers can process them incrementally without
+// the whole model being serialized at once. Like all exported content, the
+// records are redacted by utils.Redact.
+func (rm *ResourceModel) StreamJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encode := func(record any) error {
+		redacted, err := utils.Redact(record)
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(redacted)
+	}
+	for _, node := range rm.SortedNodes() {
+		kind, _, _ := strings.Cut(node.NodeID(), "/")
+		record := jsonlNode{
+			Type:         JSONLRecordNode,
+			ID:           node.NodeID(),
+			Kind:         kind,
+			Hypothetical: rm.hypothetical[node.NodeID()],
+			Object:       node.ClientObject(),
+		}
+		if err := encode(record); err != nil {
+			return err
+		}
+	}
+	for _, edge := range rm.SortedEdges() {
+		record := jsonlEdge{
+			Type:   JSONLRecordEdge,
+			From:   edge.From.NodeID(),
+			To:     edge.To.NodeID(),
+			Policy: edge.Policy,
+		}
+		if err := encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
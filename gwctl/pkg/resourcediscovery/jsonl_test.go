@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_StreamJSONL(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "healthcheckpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata":   map[string]interface{}{"name": "health-check-gateway"},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group":     "gateway.networking.k8s.io",
+						"kind":      "Gateway",
+						"name":      "foo-gateway",
+						"namespace": "default",
+					},
+					// Redacted by the default redaction patterns.
+					"apiToken": "hunter2",
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := resourceModel.StreamJSONL(&buf); err != nil {
+		t.Fatalf("StreamJSONL() returned err=%v", err)
+	}
+
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), utils.RedactedValue) {
+		t.Errorf("Output of StreamJSONL() is not redacted:\n%v", buf.String())
+	}
+
+	// Each line must be valid JSON on its own.
+	var gotNodes, gotEdges []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		switch record["type"] {
+		case JSONLRecordNode:
+			if _, ok := record["object"].(map[string]interface{}); !ok {
+				t.Errorf("Node record %q has no object", scanner.Text())
+			}
+			gotNodes = append(gotNodes, record["id"].(string))
+		case JSONLRecordEdge:
+			gotEdges = append(gotEdges, record["from"].(string)+" -> "+record["to"].(string))
+		default:
+			t.Errorf("Unexpected record type in %q", scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	// Records follow the order of SortedNodes and SortedEdges.
+	var wantNodes, wantEdges []string
+	for _, node := range resourceModel.SortedNodes() {
+		wantNodes = append(wantNodes, node.NodeID())
+	}
+	for _, edge := range resourceModel.SortedEdges() {
+		wantEdges = append(wantEdges, edge.From.NodeID()+" -> "+edge.To.NodeID())
+	}
+	if len(wantNodes) == 0 || len(wantEdges) == 0 {
+		t.Fatalf("Expected the resourceModel to have nodes and edges; nodes=%v, edges=%v", wantNodes, wantEdges)
+	}
+	if diff := cmp.Diff(wantNodes, gotNodes); diff != "" {
+		t.Errorf("Unexpected diff in node records; diff (-want +got)=\n%v", diff)
+	}
+	if diff := cmp.Diff(wantEdges, gotEdges); diff != "" {
+		t.Errorf("Unexpected diff in edge records; diff (-want +got)=\n%v", diff)
+	}
+}
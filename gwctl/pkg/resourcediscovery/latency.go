@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// LatencySettingKind classifies a field of a policy which affects latency.
+type LatencySettingKind string
+
+const (
+	LatencySettingTimeout    LatencySettingKind = "Timeout"
+	LatencySettingRetry      LatencySettingKind = "Retry"
+	LatencySettingConnection LatencySettingKind = "Connection"
+)
+
+// LatencyProfile summarizes the configuration of an HTTPRoute which affects the
+// latency of its requests, as a starting point for performance reviews.
+type LatencyProfile struct {
+	// RuleTimeouts lists the timeouts of the rules of the HTTPRoute which set
+	// any, in the order of the rules.
+	RuleTimeouts []RuleTimeouts `json:",omitempty"`
+	// BackendSettings lists the fields of the effective policies of the
+	// Backends of the HTTPRoute which affect latency, sorted by Backend,
+	// Gateway, kind of policy and path.
+	BackendSettings []LatencySetting `json:",omitempty"`
+}
+
+// RuleTimeouts are the timeouts set by a rule of an HTTPRoute.
+type RuleTimeouts struct {
+	// Rule is the index of the rule.
+	Rule int
+	// Request is the timeout for the gateway to respond to a request.
+	Request string `json:",omitempty"`
+	// BackendRequest is the timeout for a single request from the gateway to
+	// a backend.
+	BackendRequest string `json:",omitempty"`
+}
+
+// LatencySetting is a field of the effective policy of a Backend which affects
+// latency.
+type LatencySetting struct {
+	Backend common.ObjRef
+	// Gateway is the Gateway through which the effective policy applies. It is
+	// empty for mesh traffic.
+	Gateway    common.ObjRef
+	PolicyKind policymanager.PolicyCrdID
+	Kind       LatencySettingKind
+	// Path is the dot separated path of the field within the effective spec,
+	// like "retry.attempts".
+	Path  string
+	Value interface{}
+}
+
+// latencyKeywords maps substrings of the names of policy fields to the kind of
+// setting such fields configure. Policies are implementation specific, so
+// fields are recognized by the names commonly used for them. Keywords are
+// checked in order, such that e.g. "retry.perTryTimeout" counts as a retry
+// setting.
+var latencyKeywords = []struct {
+	keyword string
+	kind    LatencySettingKind
+}{
+	{"retr", LatencySettingRetry},
+	{"attempt", LatencySettingRetry},
+	{"timeout", LatencySettingTimeout},
+	{"connect", LatencySettingConnection},
+	{"keepalive", LatencySettingConnection},
+	{"idle", LatencySettingConnection},
+	{"circuitbreak", LatencySettingConnection},
+}
+
+// latencySettingKind returns the kind of latency setting the field at path
+// configures, or false if it does not affect latency.
+func latencySettingKind(path string) (LatencySettingKind, bool) {
+	path = strings.ToLower(path)
+	for _, k := range latencyKeywords {
+		if strings.Contains(path, k.keyword) {
+			return k.kind, true
+		}
+	}
+	return "", false
+}
+
+// LatencyProfile returns the configuration of the HTTPRoute which affects the
+// latency of its requests: the timeouts of its rules, and the timeout, retry
+// and connection related fields of the effective policies of its Backends.
+// Effective policies of Backends are only included for the Gateways the
+// HTTPRoute is attached to, and for mesh traffic.
+func (h *HTTPRouteNode) LatencyProfile() LatencyProfile {
+	var result LatencyProfile
+	for i, rule := range h.HTTPRoute.Spec.Rules {
+		if rule.Timeouts == nil {
+			continue
+		}
+		timeouts := RuleTimeouts{Rule: i}
+		if rule.Timeouts.Request != nil {
+			timeouts.Request = string(*rule.Timeouts.Request)
+		}
+		if rule.Timeouts.BackendRequest != nil {
+			timeouts.BackendRequest = string(*rule.Timeouts.BackendRequest)
+		}
+		if timeouts.Request != "" || timeouts.BackendRequest != "" {
+			result.RuleTimeouts = append(result.RuleTimeouts, timeouts)
+		}
+	}
+
+	var effectivePolicies []ResourceEffectivePolicy
+	for _, backendNode := range h.Backends {
+		for _, effectivePolicy := range backendEffectivePolicies(backendNode) {
+			id := GatewayID(effectivePolicy.Gateway.Namespace, effectivePolicy.Gateway.Name)
+			if _, ok := h.Gateways[id]; ok || effectivePolicy.Gateway.Name == "" {
+				effectivePolicies = append(effectivePolicies, effectivePolicy)
+			}
+		}
+	}
+	sortResourceEffectivePolicies(effectivePolicies)
+	for _, effectivePolicy := range effectivePolicies {
+		spec, err := effectivePolicy.Policy.EffectiveSpec()
+		if err != nil {
+			klog.V(1).ErrorS(err, "Failed to get effective spec of policy", "policy", effectivePolicy.Policy.PolicyCrdID())
+			continue
+		}
+		fields := policymanager.FlattenFields(spec)
+		paths := make([]string, 0, len(fields))
+		for path := range fields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			kind, ok := latencySettingKind(path)
+			if !ok {
+				continue
+			}
+			setting := LatencySetting{
+				Backend:    effectivePolicy.Resource,
+				PolicyKind: effectivePolicy.Policy.PolicyCrdID(),
+				Kind:       kind,
+				Path:       path,
+				Value:      fields[path],
+			}
+			if effectivePolicy.Gateway.Name != "" {
+				setting.Gateway = effectivePolicy.Gateway
+			}
+			result.BackendSettings = append(result.BackendSettings, setting)
+		}
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestHTTPRouteNode_LatencyProfile(t *testing.T) {
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(name),
+				Port: common.PtrTo(gatewayv1.PortNumber(80)),
+			},
+		}}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{
+						BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("foo-svc")},
+						Timeouts: &gatewayv1.HTTPRouteTimeouts{
+							Request:        common.PtrTo(gatewayv1.Duration("10s")),
+							BackendRequest: common.PtrTo(gatewayv1.Duration("2s")),
+						},
+					},
+					// Rules without timeouts are omitted.
+					{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("bar-svc")}},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "backendpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "backendpolicies",
+					Kind:   "BackendPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "BackendPolicy",
+				"metadata": map[string]interface{}{
+					"name":      "foo-svc-policy",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"retry": map[string]interface{}{
+							"attempts":      int64(3),
+							"perTryTimeout": "500ms",
+						},
+						"connectionPool": map[string]interface{}{
+							"maxConnections": int64(100),
+						},
+						// Fields unrelated to latency are omitted.
+						"loadBalancer": "round-robin",
+					},
+					"targetRef": map[string]interface{}{
+						"kind": "Service",
+						"name": "foo-svc",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	httpRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+
+	fooSvc := common.ObjRef{Kind: "Service", Namespace: "default", Name: "foo-svc"}
+	fooGateway := common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "default", Name: "foo-gateway"}
+	want := LatencyProfile{
+		RuleTimeouts: []RuleTimeouts{
+			{Rule: 0, Request: "10s", BackendRequest: "2s"},
+		},
+		BackendSettings: []LatencySetting{
+			{Backend: fooSvc, Gateway: fooGateway, PolicyKind: "BackendPolicy.foo.com", Kind: LatencySettingConnection, Path: "connectionPool.maxConnections", Value: float64(100)},
+			{Backend: fooSvc, Gateway: fooGateway, PolicyKind: "BackendPolicy.foo.com", Kind: LatencySettingRetry, Path: "retry.attempts", Value: float64(3)},
+			{Backend: fooSvc, Gateway: fooGateway, PolicyKind: "BackendPolicy.foo.com", Kind: LatencySettingRetry, Path: "retry.perTryTimeout", Value: "500ms"},
+		},
+	}
+	t.Logf("backends=%v", len(resourceModel.Backends))
+	for id, b := range httpRouteNode.Backends {
+		t.Logf("%v policies=%v eff=%v", id, b.Policies, b.EffectivePolicies)
+	}
+	got := httpRouteNode.LatencyProfile()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in LatencyProfile(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
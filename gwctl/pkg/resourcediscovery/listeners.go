@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// EffectiveListener pairs a listener declared in the spec of a Gateway with
+// its status, which reflects what the implementation actually programmed.
+type EffectiveListener struct {
+	Spec gatewayv1.Listener
+	// Status is nil if the Gateway does not report a status for the listener.
+	Status *gatewayv1.ListenerStatus
+}
+
+// EffectiveListeners returns the listeners declared in the spec of the
+// Gateway, in order, along with their status. Implementations may apply
+// defaults or constraints, e.g. from the parameters of the GatewayClass, to
+// the listeners. These are implementation specific and not discoverable, so
+// the status is the source of truth for what was programmed.
+func (g *GatewayNode) EffectiveListeners() []EffectiveListener {
+	statuses := make(map[gatewayv1.SectionName]*gatewayv1.ListenerStatus)
+	for i := range g.Gateway.Status.Listeners {
+		statuses[g.Gateway.Status.Listeners[i].Name] = &g.Gateway.Status.Listeners[i]
+	}
+
+	var result []EffectiveListener
+	for _, listener := range g.Gateway.Spec.Listeners {
+		result = append(result, EffectiveListener{Spec: listener, Status: statuses[listener.Name]})
+	}
+	return result
+}
+
+// ListenersMissingFromStatus returns the names of the listeners declared in
+// the spec of the Gateway for which the Gateway does not report a status. Such
+// listeners were likely not programmed by the implementation. Nothing is
+// returned until a controller reports the status of the current generation of
+// the Gateway, see statusReported.
+func (g *GatewayNode) ListenersMissingFromStatus() []gatewayv1.SectionName {
+	if !g.statusReported() {
+		return nil
+	}
+	var result []gatewayv1.SectionName
+	for _, listener := range g.EffectiveListeners() {
+		if listener.Status == nil {
+			result = append(result, listener.Spec.Name)
+		}
+	}
+	return result
+}
+
+// statusReported returns true if a controller reported the status of the
+// current generation of the Gateway. The conditions defaulted by the API server
+// for new Gateways, which are Unknown with reason Pending, do not count as
+// reported, and neither do conditions observing an older generation.
+func (g *GatewayNode) statusReported() bool {
+	reported := len(g.Gateway.Status.Listeners) != 0
+	for _, condition := range g.Gateway.Status.Conditions {
+		if condition.ObservedGeneration < g.Gateway.GetGeneration() {
+			return false
+		}
+		if condition.Status != metav1.ConditionUnknown || condition.Reason != string(gatewayv1.GatewayReasonPending) {
+			reported = true
+		}
+	}
+	return reported
+}
+
+// AttachesToListener returns true if the HTTPRoute attaches to the listener of
+// the Gateway, either through a parentRef naming the listener or one
+// referencing the whole Gateway.
+func (h *HTTPRouteNode) AttachesToListener(gatewayID gatewayID, listener gatewayv1.SectionName) bool { //nolint:revive
+	sectionNames, ok := h.GatewaySections[gatewayID]
+	if !ok {
+		return true
+	}
+	for _, sectionName := range sectionNames {
+		if sectionName == listener {
+			return true
+		}
+	}
+	return false
+}
+
+// NamespaceCanAttach returns whether routes from the namespace may attach to
+// the Gateway, i.e. whether the allowedRoutes of at least one of its listeners
+// permit the namespace, along with the names of those listeners in order.
+// Listeners without allowedRoutes only permit routes from the namespace of the
+// Gateway. Namespace selectors are evaluated against namespaceLabels, and never
+// match namespaces missing from it. The kinds of routes allowed by listeners
+// are not considered.
+func (g *GatewayNode) NamespaceCanAttach(namespace string, namespaceLabels NamespaceLabelIndex) (bool, []string) {
+	var listeners []string
+	for _, listener := range g.Gateway.Spec.Listeners {
+		if listenerPermitsNamespace(g.Gateway.GetNamespace(), listener, namespace, namespaceLabels) {
+			listeners = append(listeners, string(listener.Name))
+		}
+	}
+	return len(listeners) > 0, listeners
+}
+
+// listenerPermitsNamespace returns true if the allowedRoutes of the listener of
+// a Gateway in gatewayNamespace permit routes from the namespace.
+func listenerPermitsNamespace(gatewayNamespace string, listener gatewayv1.Listener, namespace string, namespaceLabels NamespaceLabelIndex) bool {
+	from := gatewayv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes.Namespaces.From != nil {
+			from = *listener.AllowedRoutes.Namespaces.From
+		}
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true
+	case gatewayv1.NamespacesFromSame:
+		return namespaceOrDefault(namespace) == namespaceOrDefault(gatewayNamespace)
+	case gatewayv1.NamespacesFromSelector:
+		if selector == nil {
+			return false
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			klog.V(1).ErrorS(err, "Invalid namespace selector in allowedRoutes", "listener", listener.Name)
+			return false
+		}
+		return namespaceLabels.Matches(namespace, labelSelector)
+	default:
+		return false
+	}
+}
+
+// ListenerTLSConflict describes two HTTPS listeners of a Gateway which share a
+// port and a hostname, but reference different certificates. Which certificate
+// clients are presented is up to the implementation. Listeners with a wildcard
+// hostname next to a more specific one do not conflict, since the more specific
+// listener takes precedence for its hostname.
+type ListenerTLSConflict struct {
+	// Listener and OtherListener are the names of the conflicting listeners, in
+	// the order they are declared.
+	Listener      gatewayv1.SectionName
+	OtherListener gatewayv1.SectionName
+	// Port is the port shared by the listeners.
+	Port gatewayv1.PortNumber
+	// Hostname is the hostname shared by the listeners, or "*" if neither
+	// listener has a hostname.
+	Hostname string
+}
+
+// ListenerTLSConflicts returns the pairs of HTTPS listeners of the Gateway
+// which share a port and a hostname, but whose certificateRefs differ, in the
+// order the listeners are declared.
+func (g *GatewayNode) ListenerTLSConflicts() []ListenerTLSConflict {
+	var result []ListenerTLSConflict
+	listeners := g.Gateway.Spec.Listeners
+	for i, listener := range listeners {
+		if listener.Protocol != gatewayv1.HTTPSProtocolType {
+			continue
+		}
+		for _, other := range listeners[i+1:] {
+			if other.Protocol != gatewayv1.HTTPSProtocolType || other.Port != listener.Port {
+				continue
+			}
+			hostname := listenerHostname(listener)
+			if hostname != listenerHostname(other) {
+				continue
+			}
+			if certificatesKey(g.Gateway.GetNamespace(), listener) == certificatesKey(g.Gateway.GetNamespace(), other) {
+				continue
+			}
+			result = append(result, ListenerTLSConflict{
+				Listener:      listener.Name,
+				OtherListener: other.Name,
+				Port:          listener.Port,
+				Hostname:      displayHostname(hostname),
+			})
+		}
+	}
+	return result
+}
+
+func listenerHostname(listener gatewayv1.Listener) string {
+	if listener.Hostname == nil {
+		return ""
+	}
+	return string(*listener.Hostname)
+}
+
+// certificatesKey returns a key identifying the set of certificates referenced
+// by the listener of a Gateway in gatewayNamespace, with the defaults of the
+// references applied.
+func certificatesKey(gatewayNamespace string, listener gatewayv1.Listener) string {
+	if listener.TLS == nil {
+		return ""
+	}
+	var refs []string
+	for _, ref := range listener.TLS.CertificateRefs {
+		objRef := certificateObjRef(gatewayNamespace, ref)
+		refs = append(refs, fmt.Sprintf("%v/%v/%v/%v", objRef.Group, objRef.Kind, objRef.Namespace, objRef.Name))
+	}
+	sort.Strings(refs)
+	return strings.Join(refs, ",")
+}
+
+// certificateObjRef converts a certificateRef of a listener of a Gateway in
+// gatewayNamespace to an ObjRef, applying the defaults of the Gateway API: an
+// empty group is the core API group, an empty kind is Secret, and an empty
+// namespace is the namespace of the Gateway.
+func certificateObjRef(gatewayNamespace string, ref gatewayv1.SecretObjectReference) common.ObjRef {
+	objRef := common.ObjRef{Kind: "Secret", Name: string(ref.Name), Namespace: gatewayNamespace}
+	if ref.Group != nil {
+		objRef.Group = string(*ref.Group)
+	}
+	if ref.Kind != nil {
+		objRef.Kind = string(*ref.Kind)
+	}
+	if ref.Namespace != nil {
+		objRef.Namespace = string(*ref.Namespace)
+	}
+	return objRef
+}
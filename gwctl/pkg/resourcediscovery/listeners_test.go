@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestGatewayNode_NamespaceCanAttach(t *testing.T) {
+	listener := func(name string, namespaces *gatewayv1.RouteNamespaces) gatewayv1.Listener {
+		result := gatewayv1.Listener{Name: gatewayv1.SectionName(name), Port: 80, Protocol: gatewayv1.HTTPProtocolType}
+		if namespaces != nil {
+			result.AllowedRoutes = &gatewayv1.AllowedRoutes{Namespaces: namespaces}
+		}
+		return result
+	}
+	fromAll := &gatewayv1.RouteNamespaces{From: common.PtrTo(gatewayv1.NamespacesFromAll)}
+	fromSame := &gatewayv1.RouteNamespaces{From: common.PtrTo(gatewayv1.NamespacesFromSame)}
+	fromTeamFoo := &gatewayv1.RouteNamespaces{
+		From:     common.PtrTo(gatewayv1.NamespacesFromSelector),
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "foo"}},
+	}
+
+	namespaceLabels := NamespaceLabelIndex{
+		"gateway-ns": labels.Set{},
+		"foo-ns":     labels.Set{"team": "foo"},
+		"bar-ns":     labels.Set{"team": "bar"},
+	}
+
+	testcases := []struct {
+		name          string
+		listeners     []gatewayv1.Listener
+		namespace     string
+		wantCanAttach bool
+		wantListeners []string
+	}{
+		{
+			name: "allowed by one listener",
+			listeners: []gatewayv1.Listener{
+				listener("same", fromSame),
+				listener("team-foo", fromTeamFoo),
+			},
+			namespace:     "foo-ns",
+			wantCanAttach: true,
+			wantListeners: []string{"team-foo"},
+		},
+		{
+			name: "allowed by no listener",
+			listeners: []gatewayv1.Listener{
+				// Without allowedRoutes, only the namespace of the Gateway is
+				// permitted.
+				listener("default", nil),
+				listener("team-foo", fromTeamFoo),
+			},
+			namespace:     "bar-ns",
+			wantCanAttach: false,
+		},
+		{
+			name: "allowed by all listeners",
+			listeners: []gatewayv1.Listener{
+				listener("all", fromAll),
+				listener("same", fromSame),
+				listener("default", nil),
+			},
+			namespace:     "gateway-ns",
+			wantCanAttach: true,
+			wantListeners: []string{"all", "same", "default"},
+		},
+		{
+			name: "selector does not match unknown namespace",
+			listeners: []gatewayv1.Listener{
+				listener("team-foo", fromTeamFoo),
+			},
+			namespace:     "unknown-ns",
+			wantCanAttach: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayNode := NewGatewayNode(&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "gateway-ns"},
+				Spec:       gatewayv1.GatewaySpec{Listeners: tc.listeners},
+			})
+
+			gotCanAttach, gotListeners := gatewayNode.NamespaceCanAttach(tc.namespace, namespaceLabels)
+			if gotCanAttach != tc.wantCanAttach {
+				t.Errorf("NamespaceCanAttach(%q) = %v, want %v", tc.namespace, gotCanAttach, tc.wantCanAttach)
+			}
+			if diff := cmp.Diff(tc.wantListeners, gotListeners); diff != "" {
+				t.Errorf("Unexpected diff in listeners; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotListeners, tc.wantListeners, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HTTPRouteMatch is a normalized form of an HTTPRouteMatch, with all defaults
+// applied, which makes it possible to compare matches from different rules and
+// different HTTPRoutes.
+type HTTPRouteMatch struct {
+	// RuleIndex is the index of the rule within the HTTPRoute.
+	RuleIndex int
+	// MatchIndex is the index of the match within the rule.
+	MatchIndex int
+
+	PathType    gatewayv1.PathMatchType
+	PathValue   string
+	Method      string
+	Headers     []string
+	QueryParams []string
+
+	// HeaderMatches and QueryParamMatches are the criteria summarized by
+	// Headers and QueryParams, with their type defaulted, against which
+	// requests are evaluated. Only the first criterion for each name is kept,
+	// as entries with an equivalent name are ignored by implementations.
+	HeaderMatches     []gatewayv1.HTTPHeaderMatch
+	QueryParamMatches []gatewayv1.HTTPQueryParamMatch
+}
+
+func NewHTTPRouteMatch(ruleIndex, matchIndex int, match gatewayv1.HTTPRouteMatch) HTTPRouteMatch {
+	result := HTTPRouteMatch{
+		RuleIndex:  ruleIndex,
+		MatchIndex: matchIndex,
+		PathType:   gatewayv1.PathMatchPathPrefix,
+		PathValue:  "/",
+	}
+	if match.Path != nil {
+		if match.Path.Type != nil {
+			result.PathType = *match.Path.Type
+		}
+		if match.Path.Value != nil {
+			result.PathValue = *match.Path.Value
+		}
+	}
+	if match.Method != nil {
+		result.Method = string(*match.Method)
+	}
+	seenHeaders := make(map[string]bool)
+	for _, header := range match.Headers {
+		headerType := gatewayv1.HeaderMatchExact
+		if header.Type != nil {
+			headerType = *header.Type
+		}
+		// Header names are case-insensitive.
+		name := strings.ToLower(string(header.Name))
+		result.Headers = append(result.Headers, fmt.Sprintf("%v:%v=%v", headerType, name, header.Value))
+		if !seenHeaders[name] {
+			seenHeaders[name] = true
+			result.HeaderMatches = append(result.HeaderMatches, gatewayv1.HTTPHeaderMatch{Type: &headerType, Name: header.Name, Value: header.Value})
+		}
+	}
+	sort.Strings(result.Headers)
+	seenQueryParams := make(map[gatewayv1.HTTPHeaderName]bool)
+	for _, queryParam := range match.QueryParams {
+		queryParamType := gatewayv1.QueryParamMatchExact
+		if queryParam.Type != nil {
+			queryParamType = *queryParam.Type
+		}
+		result.QueryParams = append(result.QueryParams, fmt.Sprintf("%v:%v=%v", queryParamType, queryParam.Name, queryParam.Value))
+		if !seenQueryParams[queryParam.Name] {
+			seenQueryParams[queryParam.Name] = true
+			result.QueryParamMatches = append(result.QueryParamMatches, gatewayv1.HTTPQueryParamMatch{Type: &queryParamType, Name: queryParam.Name, Value: queryParam.Value})
+		}
+	}
+	sort.Strings(result.QueryParams)
+	return result
+}
+
+// HTTPRouteMatches returns the normalized matches of all rules within the
+// HTTPRoute. A rule without any matches gets the default PathPrefix "/" match.
+func HTTPRouteMatches(httpRoute *gatewayv1.HTTPRoute) []HTTPRouteMatch {
+	var result []HTTPRouteMatch
+	for i, rule := range httpRoute.Spec.Rules {
+		if len(rule.Matches) == 0 {
+			result = append(result, NewHTTPRouteMatch(i, 0, gatewayv1.HTTPRouteMatch{}))
+			continue
+		}
+		for j, match := range rule.Matches {
+			result = append(result, NewHTTPRouteMatch(i, j, match))
+		}
+	}
+	return result
+}
+
+// RequestSetKey returns a key which is identical for two matches if and only if
+// they match exactly the same set of requests.
+func (m HTTPRouteMatch) RequestSetKey() string {
+	pathValue := m.PathValue
+	if m.PathType == gatewayv1.PathMatchPathPrefix && pathValue != "/" {
+		// Prefix matching is done on path elements, so a trailing slash does not
+		// change the set of matched requests.
+		pathValue = strings.TrimSuffix(pathValue, "/")
+	}
+	return fmt.Sprintf("%v|%v|%v|%v|%v", m.PathType, pathValue, m.Method, strings.Join(m.Headers, ","), strings.Join(m.QueryParams, ","))
+}
+
+// compareSpecificity compares the specificity of m and other as per the
+// ordering rules of HTTPRouteRule.Matches, without considering where the
+// matches appear. It returns a positive number if m is more specific, a
+// negative number if other is more specific, and zero on a tie.
+func (m HTTPRouteMatch) compareSpecificity(other HTTPRouteMatch) int {
+	if (m.PathType == gatewayv1.PathMatchExact) != (other.PathType == gatewayv1.PathMatchExact) {
+		if m.PathType == gatewayv1.PathMatchExact {
+			return 1
+		}
+		return -1
+	}
+	if len(m.PathValue) != len(other.PathValue) {
+		return len(m.PathValue) - len(other.PathValue)
+	}
+	if (m.Method != "") != (other.Method != "") {
+		if m.Method != "" {
+			return 1
+		}
+		return -1
+	}
+	if len(m.Headers) != len(other.Headers) {
+		return len(m.Headers) - len(other.Headers)
+	}
+	return len(m.QueryParams) - len(other.QueryParams)
+}
+
+// HasHigherPrecedenceThan returns true if m takes precedence over other, both
+// being matches of the same HTTPRoute, as per the ordering rules of
+// HTTPRouteRule.Matches. Ties are broken by the order in which the matches
+// appear within the HTTPRoute.
+func (m HTTPRouteMatch) HasHigherPrecedenceThan(other HTTPRouteMatch) bool {
+	if c := m.compareSpecificity(other); c != 0 {
+		return c > 0
+	}
+	if m.RuleIndex != other.RuleIndex {
+		return m.RuleIndex < other.RuleIndex
+	}
+	return m.MatchIndex < other.MatchIndex
+}
+
+// MatchesRequest returns true if a request with the path, method, headers and
+// query parameters is matched. Both headers and queryParams may be nil.
+// RegularExpression paths, headers and query parameters are evaluated as Go
+// regular expressions against the whole value, although implementations may
+// support a different dialect. A header carried multiple times is matched
+// against its values joined by commas, and a query parameter against its first
+// value.
+func (m HTTPRouteMatch) MatchesRequest(path, method string, headers http.Header, queryParams url.Values) bool {
+	if m.Method != "" && !strings.EqualFold(m.Method, method) {
+		return false
+	}
+	for _, header := range m.HeaderMatches {
+		values := headers.Values(string(header.Name))
+		if len(values) == 0 || !valueMatches(*header.Type == gatewayv1.HeaderMatchRegularExpression, header.Value, strings.Join(values, ",")) {
+			return false
+		}
+	}
+	for _, queryParam := range m.QueryParamMatches {
+		values, ok := queryParams[string(queryParam.Name)]
+		if !ok || len(values) == 0 || !valueMatches(*queryParam.Type == gatewayv1.QueryParamMatchRegularExpression, queryParam.Value, values[0]) {
+			return false
+		}
+	}
+	return m.matchesPath(path)
+}
+
+// compiledRegexps caches the regular expressions of matches by their source,
+// such that each is only compiled once however many requests are evaluated.
+// Invalid regular expressions are cached as nil.
+var compiledRegexps sync.Map
+
+// valueMatches returns true if the value equals the expected one, or, if regex
+// is true, if the expected regular expression matches the whole value.
+func valueMatches(regex bool, expected, value string) bool {
+	if !regex {
+		return value == expected
+	}
+	cached, ok := compiledRegexps.Load(expected)
+	if !ok {
+		re, err := regexp.Compile("^(?:" + expected + ")$")
+		if err != nil {
+			re = nil
+		}
+		cached, _ = compiledRegexps.LoadOrStore(expected, re)
+	}
+	re := cached.(*regexp.Regexp)
+	return re != nil && re.MatchString(value)
+}
+
+func (m HTTPRouteMatch) matchesPath(path string) bool {
+	switch m.PathType {
+	case gatewayv1.PathMatchExact:
+		return path == m.PathValue
+	case gatewayv1.PathMatchPathPrefix:
+		prefix := strings.TrimSuffix(m.PathValue, "/")
+		if prefix == "" {
+			return true
+		}
+		// Prefix matching is done on path elements, so "/api" matches "/api" and
+		// "/api/users" but not "/apis".
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	case gatewayv1.PathMatchRegularExpression:
+		return valueMatches(true, m.PathValue, path)
+	}
+	return false
+}
+
+func (m HTTPRouteMatch) String() string {
+	result := fmt.Sprintf("%v %v", m.PathType, m.PathValue)
+	if m.Method != "" {
+		result += fmt.Sprintf(" method=%v", m.Method)
+	}
+	if len(m.Headers) != 0 {
+		result += fmt.Sprintf(" headers=[%v]", strings.Join(m.Headers, ","))
+	}
+	if len(m.QueryParams) != 0 {
+		result += fmt.Sprintf(" queryParams=[%v]", strings.Join(m.QueryParams, ","))
+	}
+	return result
+}
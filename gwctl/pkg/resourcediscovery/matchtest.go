@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// TestRequest is a sample request which is expected to be routed by some
+// HTTPRoute.
+type TestRequest struct {
+	Host string `json:"host"`
+	// Path defaults to "/".
+	Path string `json:"path,omitempty"`
+	// Method defaults to GET.
+	Method string `json:"method,omitempty"`
+}
+
+// RequestMatch is the outcome of routing a TestRequest, as determined by
+// MatchRequests.
+type RequestMatch struct {
+	Request TestRequest
+	// Trace records how the request was routed. Trace.HTTPRoute is nil if no
+	// HTTPRoute matches the request.
+	Trace *RequestTrace
+	// Err explains why routing the request failed.
+	Err error
+}
+
+// Matched returns true if some HTTPRoute matches the request.
+func (m RequestMatch) Matched() bool {
+	return m.Trace != nil && m.Trace.HTTPRoute != nil
+}
+
+// MatchRequests routes each of the requests through the ResourceModel like
+// TraceRequest, which allows checking that expected traffic is covered by the
+// HTTPRoutes. The headers and query parameters are sent with every request.
+// The results are in the order of the requests.
+func (rm *ResourceModel) MatchRequests(requests []TestRequest, headers http.Header, queryParams url.Values) []RequestMatch {
+	result := make([]RequestMatch, 0, len(requests))
+	for _, request := range requests {
+		if request.Method == "" {
+			request.Method = "GET"
+		}
+		if request.Path == "" {
+			request.Path = "/"
+		}
+		trace, err := rm.TraceRequest(request.Host, request.Path, request.Method, headers, queryParams)
+		result = append(result, RequestMatch{Request: request, Trace: trace, Err: err})
+	}
+	return result
+}
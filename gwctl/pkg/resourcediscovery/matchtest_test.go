@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_MatchRequests(t *testing.T) {
+	gateway := func(name, hostname string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80, Hostname: common.PtrTo(gatewayv1.Hostname(hostname))},
+				},
+			},
+		}
+	}
+	httpRoute := func(name, gateway, pathPrefix string, method *gatewayv1.HTTPMethod, backend string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Path:   &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo(pathPrefix)},
+						Method: method,
+					}},
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: gatewayv1.ObjectName(backend),
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("foo-gateway", "*.foo.com"),
+		gateway("bar-gateway", "*.bar.com"),
+		httpRoute("api-httproute", "foo-gateway", "/api", nil, "api-svc"),
+		httpRoute("upload-httproute", "foo-gateway", "/upload", common.PtrTo(gatewayv1.HTTPMethodPost), "upload-svc"),
+		httpRoute("bar-httproute", "bar-gateway", "/", nil, "bar-svc"),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-svc",
+				Namespace: "default",
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	// Only the requests routed through foo-gateway are considered.
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Namespace: "default", Name: "foo-gateway", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	requests := []TestRequest{
+		{Host: "www.foo.com", Path: "/api/users"},
+		{Host: "www.foo.com", Path: "/upload", Method: "POST"},
+		// Matches no route since upload-httproute only accepts POST.
+		{Host: "www.foo.com", Path: "/upload", Method: "GET"},
+		// Matches no route since no route matches the path.
+		{Host: "www.foo.com"},
+		// Matches no listener of foo-gateway.
+		{Host: "www.bar.com", Path: "/api"},
+	}
+	type result struct {
+		Request   TestRequest
+		Matched   bool
+		HTTPRoute string
+		Err       bool
+	}
+	var got []result
+	for _, match := range resourceModel.MatchRequests(requests, nil, nil) {
+		r := result{Request: match.Request, Matched: match.Matched(), Err: match.Err != nil}
+		if match.Matched() {
+			r.HTTPRoute = match.Trace.HTTPRoute.HTTPRoute.GetName()
+		}
+		got = append(got, r)
+	}
+	want := []result{
+		{Request: TestRequest{Host: "www.foo.com", Path: "/api/users", Method: "GET"}, Matched: true, HTTPRoute: "api-httproute"},
+		{Request: TestRequest{Host: "www.foo.com", Path: "/upload", Method: "POST"}, Matched: true, HTTPRoute: "upload-httproute"},
+		{Request: TestRequest{Host: "www.foo.com", Path: "/upload", Method: "GET"}, Err: true},
+		{Request: TestRequest{Host: "www.foo.com", Path: "/", Method: "GET"}, Err: true},
+		{Request: TestRequest{Host: "www.bar.com", Path: "/api", Method: "GET"}, Err: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in MatchRequests (-want +got):\n%v", diff)
+	}
+}
+
+func TestResourceModel_MatchRequests_HeadersAndQueryParams(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80}},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "canary-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Headers:     []gatewayv1.HTTPHeaderMatch{{Name: "X-Canary", Value: "true"}},
+						QueryParams: []gatewayv1.HTTPQueryParamMatch{{Name: "version", Value: "beta"}},
+					}},
+				}},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Namespace: "default", Name: "foo-gateway", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	requests := []TestRequest{{Host: "www.foo.com"}}
+	testcases := []struct {
+		name        string
+		headers     http.Header
+		queryParams url.Values
+		wantMatched bool
+	}{
+		{
+			name: "without headers and query parameters",
+		},
+		{
+			name:    "with headers only",
+			headers: http.Header{"X-Canary": []string{"true"}},
+		},
+		{
+			name:        "with headers and query parameters",
+			headers:     http.Header{"X-Canary": []string{"true"}},
+			queryParams: url.Values{"version": []string{"beta"}},
+			wantMatched: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			matches := resourceModel.MatchRequests(requests, tc.headers, tc.queryParams)
+			if got := matches[0].Matched(); got != tc.wantMatched {
+				t.Errorf("Matched() = %v, want %v", got, tc.wantMatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ToMermaid writes the ResourceModel as a Mermaid flowchart to w. Namespaced
+// resources are grouped within a subgraph per namespace, and policies are
+// linked to their targets through dotted edges.
+//
+// Hypothetical nodes (see AddHypothetical) are labeled as such and drawn with a
+// dashed border.
+//
+// Nodes and edges are written in the order of SortedNodes and SortedEdges, and
+// subgraphs are sorted, so the same ResourceModel always results in the same
+// output.
+func (rm *ResourceModel) ToMermaid(w io.Writer) error {
+	// Assign short, stable identifiers to nodes and namespaces, since NodeIDs
+	// contain characters which Mermaid does not allow within identifiers.
+	var nodes []Node
+	namespaces := make(map[string][]Node)
+	for _, node := range rm.SortedNodes() {
+		if _, ok := node.(*NamespaceNode); ok {
+			namespaces[node.ClientObject().GetName()] = namespaces[node.ClientObject().GetName()]
+			continue
+		}
+		nodes = append(nodes, node)
+		if ns := node.ClientObject().GetNamespace(); ns != "" {
+			namespaces[ns] = append(namespaces[ns], node)
+		}
+	}
+	mermaidIDs := make(map[string]string)
+	for i, node := range nodes {
+		mermaidIDs[node.NodeID()] = fmt.Sprintf("n%d", i)
+	}
+	var namespaceNames []string
+	for ns := range namespaces {
+		namespaceNames = append(namespaceNames, ns)
+	}
+	sort.Strings(namespaceNames)
+	for i, ns := range namespaceNames {
+		mermaidIDs[namespaceKey(ns)] = fmt.Sprintf("ns%d", i)
+	}
+
+	var hypothetical []string
+	label := func(id string) string {
+		if rm.hypothetical[id] {
+			hypothetical = append(hypothetical, mermaidIDs[id])
+			return mermaidLabel(id + " (hypothetical)")
+		}
+		return mermaidLabel(id)
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, node := range nodes {
+		if node.ClientObject().GetNamespace() == "" {
+			fmt.Fprintf(&b, "  %v[\"%v\"]\n", mermaidIDs[node.NodeID()], label(node.NodeID()))
+		}
+	}
+	for _, ns := range namespaceNames {
+		fmt.Fprintf(&b, "  subgraph %v[\"Namespace %v\"]\n", mermaidIDs[namespaceKey(ns)], mermaidLabel(ns))
+		for _, node := range namespaces[ns] {
+			fmt.Fprintf(&b, "    %v[\"%v\"]\n", mermaidIDs[node.NodeID()], label(node.NodeID()))
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, edge := range rm.SortedEdges() {
+		from, to := mermaidIDs[mermaidKey(edge.From)], mermaidIDs[mermaidKey(edge.To)]
+		if from == "" || to == "" {
+			continue
+		}
+		arrow := "-->"
+		if edge.Policy {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %v %v %v\n", from, arrow, to)
+	}
+	if len(hypothetical) != 0 {
+		b.WriteString("  classDef hypothetical stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&b, "  class %v hypothetical\n", strings.Join(hypothetical, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidKey returns the key of the node within the Mermaid identifiers. Edges
+// to a Namespace point to the namespace subgraph.
+func mermaidKey(node Node) string {
+	if _, ok := node.(*NamespaceNode); ok {
+		return namespaceKey(node.ClientObject().GetName())
+	}
+	return node.NodeID()
+}
+
+// namespaceKey returns the key identifying the subgraph of the namespace. It
+// can not collide with a NodeID since those always contain a slash.
+func namespaceKey(namespace string) string {
+	return "namespace:" + namespace
+}
+
+// mermaidLabel escapes double quotes, which would otherwise terminate the
+// label of a Mermaid node.
+func mermaidLabel(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
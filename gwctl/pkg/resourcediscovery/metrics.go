@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"strconv"
+)
+
+// Metric is a gauge describing the ResourceModel, e.g. the number of Gateways.
+// Metrics with the same Name differ in their Labels.
+type Metric struct {
+	Name   string
+	Help   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Metrics returns the Metrics of the ResourceModel. See CollectMetrics.
+func (rm *ResourceModel) Metrics() []Metric {
+	return CollectMetrics(rm)
+}
+
+// CollectMetrics returns the following Metrics, counting each resource once
+// even if it is part of multiple resourceModels:
+//   - gwctl_gateways_total: the number of Gateways.
+//   - gwctl_httproutes_total: the number of HTTPRoutes.
+//   - gwctl_orphaned_routes_total: the number of HTTPRoutes which are neither
+//     attached to a Gateway nor to a Service.
+//   - gwctl_cross_namespace_refs_total: the number of cross namespace
+//     references from HTTPRoutes to backends, labeled by whether they are
+//     permitted by a ReferenceGrant (see ResourceModel.VerifyGrants).
+func CollectMetrics(resourceModels ...*ResourceModel) []Metric {
+	gateways := make(map[gatewayID]bool)
+	httpRoutes := make(map[httpRouteID]bool)
+	attachedHTTPRoutes := make(map[httpRouteID]bool)
+	type crossNamespaceRef struct{ httpRoute, backend string }
+	crossNamespaceRefs := make(map[crossNamespaceRef]bool)
+
+	for _, resourceModel := range resourceModels {
+		for id := range resourceModel.Gateways {
+			gateways[id] = true
+		}
+		for id, httpRouteNode := range resourceModel.HTTPRoutes {
+			httpRoutes[id] = true
+			if len(httpRouteNode.Gateways) != 0 || len(httpRouteNode.ParentServices) != 0 {
+				attachedHTTPRoutes[id] = true
+			}
+		}
+		for _, check := range resourceModel.VerifyGrants() {
+			ref := crossNamespaceRef{
+				httpRoute: check.HTTPRoute.Namespace + "/" + check.HTTPRoute.Name,
+				backend:   BackendRefString(check.Backend),
+			}
+			// A reference is permitted if any resourceModel includes the
+			// ReferenceGrant permitting it.
+			crossNamespaceRefs[ref] = crossNamespaceRefs[ref] || check.Passed()
+		}
+	}
+
+	permitted := map[bool]int{}
+	for _, ok := range crossNamespaceRefs {
+		permitted[ok]++
+	}
+
+	const crossNamespaceRefsHelp = "Number of cross namespace references from HTTPRoutes to backends."
+	return []Metric{
+		{Name: "gwctl_gateways_total", Help: "Number of Gateways.", Value: float64(len(gateways))},
+		{Name: "gwctl_httproutes_total", Help: "Number of HTTPRoutes.", Value: float64(len(httpRoutes))},
+		{Name: "gwctl_orphaned_routes_total", Help: "Number of HTTPRoutes which are not attached to any Gateway or Service.", Value: float64(len(httpRoutes) - len(attachedHTTPRoutes))},
+		{Name: "gwctl_cross_namespace_refs_total", Help: crossNamespaceRefsHelp, Labels: map[string]string{"permitted": strconv.FormatBool(true)}, Value: float64(permitted[true])},
+		{Name: "gwctl_cross_namespace_refs_total", Help: crossNamespaceRefsHelp, Labels: map[string]string{"permitted": strconv.FormatBool(false)}, Value: float64(permitted[false])},
+	}
+}
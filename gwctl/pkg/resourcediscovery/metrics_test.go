@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestCollectMetrics(t *testing.T) {
+	gateway := func(name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		}
+	}
+	httpRoute := func(name, parent string, backendNamespaces ...string) *gatewayv1.HTTPRoute {
+		var backendRefs []gatewayv1.HTTPBackendRef
+		for _, namespace := range backendNamespaces {
+			backendRefs = append(backendRefs, gatewayv1.HTTPBackendRef{
+				BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{
+						Kind:      common.PtrTo(gatewayv1.Kind("Service")),
+						Name:      "foo-svc",
+						Namespace: common.PtrTo(gatewayv1.Namespace(namespace)),
+						Port:      common.PtrTo(gatewayv1.PortNumber(80)),
+					},
+				},
+			})
+		}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(parent)}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: backendRefs}},
+			},
+		}
+	}
+	service := func(namespace string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: namespace,
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("permitted"),
+		common.NamespaceForTest("denied"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("foo-gateway"),
+		// Gateway without HTTPRoutes.
+		gateway("bar-gateway"),
+		httpRoute("foo-httproute", "foo-gateway", "default", "permitted", "denied"),
+		// HTTPRoute referencing a Gateway which does not exist.
+		httpRoute("orphaned-httproute", "missing-gateway", "permitted"),
+		service("default"),
+		service("permitted"),
+		service("denied"),
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-default",
+				Namespace: "permitted",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "HTTPRoute",
+					Namespace: "default",
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{
+					Kind: "Service",
+				}},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	httpRoutesResourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	gatewaysResourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	got := make(map[string]float64)
+	for _, metric := range CollectMetrics(httpRoutesResourceModel, gatewaysResourceModel) {
+		name := metric.Name
+		if permitted, ok := metric.Labels["permitted"]; ok {
+			name += "{permitted=" + permitted + "}"
+		}
+		got[name] = metric.Value
+	}
+	want := map[string]float64{
+		"gwctl_gateways_total":                             2,
+		"gwctl_httproutes_total":                           2,
+		"gwctl_orphaned_routes_total":                      1,
+		"gwctl_cross_namespace_refs_total{permitted=true}": 2,
+		// The reference of foo-httproute to the denied namespace.
+		"gwctl_cross_namespace_refs_total{permitted=false}": 1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in metrics; diff (-want +got)=\n%v", diff)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NamespaceLabelIndex maps the names of the Namespaces in the cluster to their
+// labels. It is built once while discovering the ResourceModel, so that
+// namespace selectors (e.g. of allowedRoutes or of policies) can be evaluated
+// without fetching the Namespaces again.
+type NamespaceLabelIndex map[string]labels.Set
+
+func newNamespaceLabelIndex(namespaces []corev1.Namespace) NamespaceLabelIndex {
+	index := make(NamespaceLabelIndex, len(namespaces))
+	for _, namespace := range namespaces {
+		index[namespace.GetName()] = labels.Set(namespace.GetLabels())
+	}
+	return index
+}
+
+// Labels returns the labels of the namespace, and whether the namespace is part
+// of the index.
+func (i NamespaceLabelIndex) Labels(namespace string) (labels.Set, bool) {
+	namespaceLabels, ok := i[namespaceOrDefault(namespace)]
+	return namespaceLabels, ok
+}
+
+// Matches returns true if the namespace is part of the index and its labels
+// match the selector.
+func (i NamespaceLabelIndex) Matches(namespace string, selector labels.Selector) bool {
+	namespaceLabels, ok := i.Labels(namespace)
+	return ok && selector.Matches(namespaceLabels)
+}
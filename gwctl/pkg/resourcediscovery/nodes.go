@@ -17,14 +17,18 @@ limitations under the License.
 package resourcediscovery
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +36,24 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// Node is implemented by all nodes within the ResourceModel.
+type Node interface {
+	// NodeID returns an identifier for the node of the form KIND/NAME for
+	// cluster scoped resources and KIND/NAMESPACE/NAME for namespaced
+	// resources, e.g. "Gateway/default/foo-gateway".
+	NodeID() string
+	// ClientObject returns the underlying resource of the node.
+	ClientObject() client.Object
+}
+
+// nodeID returns the identifier used by Node.NodeID().
+func nodeID(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%v/%v", kind, name)
+	}
+	return fmt.Sprintf("%v/%v/%v", kind, namespace, name)
+}
+
 // resourceID defines a type to represent unique IDs for a resource.
 type resourceID struct {
 	Group     string
@@ -52,6 +74,8 @@ type (
 	backendID        resourceID
 	referenceGrantID resourceID
 	policyID         resourceID
+	extensionRefID   resourceID
+	secretID         resourceID
 )
 
 // GatewayClassID returns an ID for a GatewayClass.
@@ -83,8 +107,13 @@ func HTTPRouteID(namespace, name string) httpRouteID { //nolint:revive
 	return httpRouteID(resourceID{Namespace: namespace, Name: name})
 }
 
-// BackendID returns an ID for a Backend.
+// BackendID returns an ID for a Backend. An empty kind defaults to Service, as
+// for backendRefs, so a backendRef which omits its group and kind has the same
+// ID as the Service it references.
 func BackendID(group, kind, namespace, name string) backendID { //nolint:revive
+	if kind == "" {
+		kind = "Service"
+	}
 	return backendID(resourceID{
 		Group:     strings.ToLower(group),
 		Kind:      strings.ToLower(kind),
@@ -117,6 +146,25 @@ func ReferenceGrantID(namespace, name string) referenceGrantID { //nolint:revive
 	})
 }
 
+// SecretID returns an ID for a Secret.
+func SecretID(namespace, name string) secretID { //nolint:revive
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	return secretID(resourceID{Namespace: namespace, Name: name})
+}
+
+// ExtensionRefID returns an ID for an object referenced by an ExtensionRef
+// filter.
+func ExtensionRefID(group, kind, namespace, name string) extensionRefID { //nolint:revive
+	return extensionRefID(resourceID{
+		Group:     strings.ToLower(group),
+		Kind:      strings.ToLower(kind),
+		Namespace: namespace,
+		Name:      name,
+	})
+}
+
 // MarshalText is used to implement encoding.TextMarshaler interface for
 // gatewayID.
 func (g gatewayID) MarshalText() ([]byte, error) {
@@ -133,6 +181,13 @@ type GatewayClassNode struct {
 	Gateways map[gatewayID]*GatewayNode
 	// Policies stores Policies that directly apply to this GatewayClass.
 	Policies map[policyID]*PolicyNode
+	// Parameters is the implementation specific configuration object
+	// referenced by the parametersRef of the GatewayClass. It is nil if the
+	// GatewayClass has no parametersRef or if it could not be resolved.
+	Parameters *unstructured.Unstructured
+
+	// Errors contains any errorrs associated with this resource.
+	Errors []error
 }
 
 func NewGatewayClassNode(gatewayClass *gatewayv1.GatewayClass) *GatewayClassNode {
@@ -140,11 +195,16 @@ func NewGatewayClassNode(gatewayClass *gatewayv1.GatewayClass) *GatewayClassNode
 		GatewayClass: gatewayClass,
 		Gateways:     make(map[gatewayID]*GatewayNode),
 		Policies:     make(map[policyID]*PolicyNode),
+		Errors:       []error{},
 	}
 }
 
 func (g GatewayClassNode) ClientObject() client.Object { return g.GatewayClass }
 
+func (g *GatewayClassNode) NodeID() string {
+	return nodeID("GatewayClass", "", g.GatewayClass.GetName())
+}
+
 func (g *GatewayClassNode) ID() gatewayClassID { //nolint:revive
 	if g.GatewayClass == nil {
 		klog.V(0).ErrorS(nil, "returning empty ID since GatewayClass is nil")
@@ -164,11 +224,26 @@ type GatewayNode struct {
 	GatewayClass *GatewayClassNode
 	// HTTPRoutes stores HTTPRoutes attached to this Gateway.
 	HTTPRoutes map[httpRouteID]*HTTPRouteNode
+	// ReferenceGrants contains ReferenceGrants that expose this Gateway. They
+	// are only discovered when ReferenceGrants are required for HTTPRoutes
+	// attaching to the Gateway from other namespaces.
+	ReferenceGrants map[referenceGrantID]*ReferenceGrantNode
+	// DefaultBackendRefs lists the default backends declared by the Gateway or
+	// its listeners. It is empty for API versions without default backends.
+	DefaultBackendRefs []DefaultBackendRef
+	// DefaultBackends stores the Backends which the Gateway uses as default
+	// backends.
+	DefaultBackends map[backendID]*BackendNode
 	// Policies stores Policies directly applied to the Gateway.
 	Policies map[policyID]*PolicyNode
 	// EffectivePolicies reflects the effective policies applicable to this Gateway,
 	// considering inheritance and hierarchy.
 	EffectivePolicies map[policymanager.PolicyCrdID]policymanager.Policy
+	// ListenerEffectivePolicies reflects the effective policies applicable to
+	// each listener of this Gateway which has policies scoped to it, mapped by
+	// the name of the listener. Other listeners share the value from
+	// EffectivePolicies.
+	ListenerEffectivePolicies map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy
 	// Events contains the events associated with this Gateway.
 	Events []corev1.Event
 	// Errors contains any errorrs associated with this resource.
@@ -177,17 +252,24 @@ type GatewayNode struct {
 
 func NewGatewayNode(gateway *gatewayv1.Gateway) *GatewayNode {
 	return &GatewayNode{
-		Gateway:           gateway,
-		HTTPRoutes:        make(map[httpRouteID]*HTTPRouteNode),
-		Policies:          make(map[policyID]*PolicyNode),
-		EffectivePolicies: make(map[policymanager.PolicyCrdID]policymanager.Policy),
-		Events:            []corev1.Event{},
-		Errors:            []error{},
+		Gateway:                   gateway,
+		HTTPRoutes:                make(map[httpRouteID]*HTTPRouteNode),
+		ReferenceGrants:           make(map[referenceGrantID]*ReferenceGrantNode),
+		DefaultBackends:           make(map[backendID]*BackendNode),
+		Policies:                  make(map[policyID]*PolicyNode),
+		EffectivePolicies:         make(map[policymanager.PolicyCrdID]policymanager.Policy),
+		ListenerEffectivePolicies: make(map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy),
+		Events:                    []corev1.Event{},
+		Errors:                    []error{},
 	}
 }
 
 func (g GatewayNode) ClientObject() client.Object { return g.Gateway }
 
+func (g *GatewayNode) NodeID() string {
+	return nodeID("Gateway", g.Gateway.GetNamespace(), g.Gateway.GetName())
+}
+
 func (g *GatewayNode) ID() gatewayID { //nolint:revive
 	if g.Gateway == nil {
 		klog.V(0).ErrorS(nil, "returning empty ID since Gateway is nil")
@@ -196,6 +278,56 @@ func (g *GatewayNode) ID() gatewayID { //nolint:revive
 	return GatewayID(g.Gateway.GetNamespace(), g.Gateway.GetName())
 }
 
+// ControllerName returns the controllerName of the GatewayClass of this
+// Gateway, i.e. the implementation which manages the Gateway. It returns an
+// empty string if the GatewayClass was not found.
+func (g *GatewayNode) ControllerName() gatewayv1.GatewayController {
+	if g.GatewayClass == nil {
+		return ""
+	}
+	return g.GatewayClass.GatewayClass.Spec.ControllerName
+}
+
+// DependentRoutes returns the routes attached to this Gateway which have no
+// other parent, i.e. the routes which would be orphaned if the Gateway were
+// deleted. HTTPRoutes are currently the only kind of route tracked by the
+// ResourceModel. The routes are sorted by namespace/name.
+func (g *GatewayNode) DependentRoutes() []*HTTPRouteNode {
+	var result []*HTTPRouteNode
+	for _, httpRouteNode := range g.sortedHTTPRoutes() {
+		if len(httpRouteNode.OtherParents(g.ID())) == 0 {
+			result = append(result, httpRouteNode)
+		}
+	}
+	return result
+}
+
+// SharedRoutes returns the routes attached to this Gateway which also have
+// other parents, i.e. the routes which would survive the deletion of the
+// Gateway. The routes are sorted by namespace/name.
+func (g *GatewayNode) SharedRoutes() []*HTTPRouteNode {
+	var result []*HTTPRouteNode
+	for _, httpRouteNode := range g.sortedHTTPRoutes() {
+		if len(httpRouteNode.OtherParents(g.ID())) != 0 {
+			result = append(result, httpRouteNode)
+		}
+	}
+	return result
+}
+
+func (g *GatewayNode) sortedHTTPRoutes() []*HTTPRouteNode {
+	var result []*HTTPRouteNode
+	for _, httpRouteNode := range g.HTTPRoutes {
+		result = append(result, httpRouteNode)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a := fmt.Sprintf("%v/%v", result[i].HTTPRoute.GetNamespace(), result[i].HTTPRoute.GetName())
+		b := fmt.Sprintf("%v/%v", result[j].HTTPRoute.GetNamespace(), result[j].HTTPRoute.GetName())
+		return a < b
+	})
+	return result
+}
+
 // HTTPRouteNode models the relationships and dependencies of an HTTPRoute
 // resource.
 type HTTPRouteNode struct {
@@ -206,31 +338,200 @@ type HTTPRouteNode struct {
 	Namespace *NamespaceNode
 	// Gateways stores Gateways whhich this HTTPRoute is attached to.
 	Gateways map[gatewayID]*GatewayNode
+	// GatewaySections stores, for each Gateway in Gateways, the sorted names of
+	// the listeners which the parentRefs of this HTTPRoute attach to. A Gateway
+	// has no entry if any of its parentRefs attaches to the whole Gateway.
+	GatewaySections map[gatewayID][]gatewayv1.SectionName
+	// ParentServices stores Services which this HTTPRoute is attached to as a
+	// mesh (GAMMA) route.
+	ParentServices map[backendID]*BackendNode
 	// Backends lists Backends serving as target endpoints for traffic through
 	// this route.
 	Backends map[backendID]*BackendNode
+	// Filters summarizes the filters of all rules of the HTTPRoute.
+	Filters []FilterSummary
+	// RuleNames contains the name of each rule of the HTTPRoute, or an empty
+	// string for rules without a name.
+	RuleNames []string
+	// NamedBackendPorts lists the backendRefs of the HTTPRoute which reference
+	// the port of a Service by name.
+	NamedBackendPorts []NamedBackendPort
+	// ExtensionRefs stores the objects referenced by the ExtensionRef filters
+	// of the HTTPRoute.
+	ExtensionRefs map[extensionRefID]*ExtensionRefNode
+	// Secrets stores the Secrets which the HTTPRoute references through its
+	// filters, either directly or through the objects referenced by its
+	// ExtensionRef filters.
+	Secrets map[secretID]*SecretNode
 	// Policies stores Policies directly applied to the HTTPRoute.
 	Policies map[policyID]*PolicyNode
 	// EffectivePolicies reflects the effective policies applicable to this
 	// HTTPRoute, mapped per Gateway for context-specific enforcement.
 	EffectivePolicies map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy
+	// RuleEffectivePolicies reflects the effective policies applicable to each
+	// named rule of this HTTPRoute, mapped per Gateway and then per rule name.
+	// Rules without any rule-scoped policies share the value from
+	// EffectivePolicies.
+	RuleEffectivePolicies map[gatewayID]map[string]map[policymanager.PolicyCrdID]policymanager.Policy
+	// ListenerEffectivePolicies reflects the effective policies applicable to
+	// this HTTPRoute through each listener section it attaches to, mapped per
+	// Gateway and then per listener name. Only listeners with policies scoped
+	// to them have an entry, the others share the value from EffectivePolicies.
+	ListenerEffectivePolicies map[gatewayID]map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy
+	// MeshEffectivePolicies reflects the effective policies applicable to this
+	// HTTPRoute in the mesh context. Since mesh routes are not attached to a
+	// Gateway, only policies from the HTTPRoute-namespace and the HTTPRoute are
+	// considered.
+	MeshEffectivePolicies map[policymanager.PolicyCrdID]policymanager.Policy
 	// Errors contains any errorrs associated with this resource.
 	Errors []error
 }
 
 func NewHTTPRouteNode(httpRoute *gatewayv1.HTTPRoute) *HTTPRouteNode {
 	return &HTTPRouteNode{
-		HTTPRoute:         httpRoute,
-		Gateways:          make(map[gatewayID]*GatewayNode),
-		Backends:          make(map[backendID]*BackendNode),
-		Policies:          make(map[policyID]*PolicyNode),
-		EffectivePolicies: make(map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy),
-		Errors:            []error{},
+		HTTPRoute:                 httpRoute,
+		Gateways:                  make(map[gatewayID]*GatewayNode),
+		GatewaySections:           make(map[gatewayID][]gatewayv1.SectionName),
+		ParentServices:            make(map[backendID]*BackendNode),
+		Backends:                  make(map[backendID]*BackendNode),
+		ExtensionRefs:             make(map[extensionRefID]*ExtensionRefNode),
+		Secrets:                   make(map[secretID]*SecretNode),
+		Policies:                  make(map[policyID]*PolicyNode),
+		EffectivePolicies:         make(map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy),
+		RuleEffectivePolicies:     make(map[gatewayID]map[string]map[policymanager.PolicyCrdID]policymanager.Policy),
+		ListenerEffectivePolicies: make(map[gatewayID]map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy),
+		MeshEffectivePolicies:     make(map[policymanager.PolicyCrdID]policymanager.Policy),
+		Errors:                    []error{},
+	}
+}
+
+// RulesWithScopedPolicies returns the sorted names of the rules of the
+// HTTPRoute which have policies scoped to them, i.e. whose effective policies
+// may differ from those of the HTTPRoute as a whole.
+func (h *HTTPRouteNode) RulesWithScopedPolicies() []string {
+	ruleNames := make(map[string]bool)
+	for _, ruleName := range h.RuleNames {
+		if ruleName != "" {
+			ruleNames[ruleName] = true
+		}
+	}
+	var result []string
+	for _, policyNode := range h.Policies {
+		sectionName := policyNode.Policy.SectionName()
+		if ruleNames[sectionName] {
+			result = append(result, sectionName)
+			delete(ruleNames, sectionName)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// OtherParents returns the parents of the HTTPRoute, as per its parentRefs,
+// other than the given Gateway. Parents which are not part of the
+// ResourceModel are included as well, unless they are known not to exist.
+func (h *HTTPRouteNode) OtherParents(gatewayID gatewayID) []common.ObjRef {
+	var result []common.ObjRef
+	for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(*h.HTTPRoute) {
+		if GatewayID(gatewayRef.Namespace, gatewayRef.Name) == gatewayID || h.isMissingParent("Gateway", gatewayRef.Namespace, gatewayRef.Name) {
+			continue
+		}
+		result = append(result, common.ObjRef{
+			Group:     gatewayv1.GroupName,
+			Kind:      "Gateway",
+			Name:      gatewayRef.Name,
+			Namespace: gatewayRef.Namespace,
+		})
+	}
+	for _, serviceRef := range relations.FindServiceParentRefsForHTTPRoute(*h.HTTPRoute) {
+		if h.isMissingParent("Service", serviceRef.Namespace, serviceRef.Name) {
+			continue
+		}
+		result = append(result, common.ObjRef{
+			Kind:      "Service",
+			Name:      serviceRef.Name,
+			Namespace: serviceRef.Namespace,
+		})
 	}
+	return result
+}
+
+// isMissingParent returns true if a reference of the HTTPRoute to the parent
+// was recorded as an error because the parent does not exist.
+func (h *HTTPRouteNode) isMissingParent(kind, namespace, name string) bool {
+	for _, err := range h.Errors {
+		var nonExistentErr ReferenceToNonExistentResourceError
+		if !errors.As(err, &nonExistentErr) {
+			continue
+		}
+		referredObject := nonExistentErr.ReferredObject
+		if referredObject.Kind == kind && referredObject.Namespace == namespace && referredObject.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseHeaderModifiers returns the summaries of the ResponseHeaderModifier
+// filters of the HTTPRoute, which manipulate the headers of responses.
+func (h *HTTPRouteNode) ResponseHeaderModifiers() []FilterSummary {
+	var result []FilterSummary
+	for _, filter := range h.Filters {
+		if filter.ResponseHeaderModifier != nil {
+			result = append(result, filter)
+		}
+	}
+	return result
+}
+
+// BackendFilters returns the summaries of the filters carried by the
+// backendRefs of the HTTPRoute which reference the Backend, i.e. the filters
+// which only apply to traffic forwarded from the HTTPRoute to that Backend.
+func (h *HTTPRouteNode) BackendFilters(backendID backendID) []FilterSummary {
+	var result []FilterSummary
+	for _, filter := range h.Filters {
+		if filter.BackendRef == nil {
+			continue
+		}
+		ref := filter.BackendRef
+		if BackendID(ref.Group, ref.Kind, ref.Namespace, ref.Name) == backendID {
+			result = append(result, filter)
+		}
+	}
+	return result
+}
+
+// OrderedBackends returns the IDs of the Backends of the HTTPRoute in the
+// order in which the rules of the HTTPRoute first reference them, which the
+// Backends map does not preserve. Some implementations give this order a
+// meaning, e.g. for failover.
+func (h *HTTPRouteNode) OrderedBackends() []backendID {
+	var result []backendID
+	for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*h.HTTPRoute) {
+		backendID := BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)
+		if _, ok := h.Backends[backendID]; ok {
+			result = append(result, backendID)
+		}
+	}
+	return result
+}
+
+// IsMeshRoute returns true if the HTTPRoute has a Service as its parent.
+func (h *HTTPRouteNode) IsMeshRoute() bool {
+	return len(relations.FindServiceParentRefsForHTTPRoute(*h.HTTPRoute)) != 0
+}
+
+// IsIngressRoute returns true if the HTTPRoute has a Gateway as its parent.
+func (h *HTTPRouteNode) IsIngressRoute() bool {
+	return len(relations.FindGatewayRefsForHTTPRoute(*h.HTTPRoute)) != 0
 }
 
 func (h HTTPRouteNode) ClientObject() client.Object { return h.HTTPRoute }
 
+func (h *HTTPRouteNode) NodeID() string {
+	return nodeID("HTTPRoute", h.HTTPRoute.GetNamespace(), h.HTTPRoute.GetName())
+}
+
 func (h *HTTPRouteNode) ID() httpRouteID { //nolint:revive
 	if h.HTTPRoute == nil {
 		klog.V(0).ErrorS(nil, "returning empty ID since HTTPRoute is nil")
@@ -251,6 +552,12 @@ type BackendNode struct {
 	Namespace *NamespaceNode
 	// HTTPRoutes lists HTTPRoutes that reference this Backend as a target.
 	HTTPRoutes map[httpRouteID]*HTTPRouteNode
+	// MeshHTTPRoutes lists HTTPRoutes that are attached to this Backend as their
+	// parent. This is only applicable to Services.
+	MeshHTTPRoutes map[httpRouteID]*HTTPRouteNode
+	// DefaultBackendOf lists Gateways which use this Backend as a default
+	// backend.
+	DefaultBackendOf map[gatewayID]*GatewayNode
 	// Policies stores Policies directly applied to the Backend.
 	Policies map[policyID]*PolicyNode
 	// ReferenceGrants contains ReferenceGrants that expose this Backend.
@@ -258,23 +565,108 @@ type BackendNode struct {
 	// EffectivePolicies reflects the effective policies applicable to this
 	// Backend, mapped per Gateway for context-specific enforcement.
 	EffectivePolicies map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy
+	// ListenerEffectivePolicies reflects the effective policies applicable to
+	// this Backend through each listener of a Gateway, mapped per Gateway and
+	// then per listener name. Only listeners with policies scoped to them have
+	// an entry, the others share the value from EffectivePolicies.
+	ListenerEffectivePolicies map[gatewayID]map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy
+	// EndpointZones maps each availability zone to the number of endpoints of
+	// the Backend within that zone, as reported by the EndpointSlices of the
+	// Backend.
+	EndpointZones map[string]int
+	// ReadyEndpoints is the number of ready endpoints of the Backend, as
+	// reported by the EndpointSlices of the Backend. It is only meaningful if
+	// EndpointsDiscovered is true.
+	ReadyEndpoints int
+	// EndpointsDiscovered is true if the EndpointSlices of the Backend were
+	// successfully fetched. This is only done for Backends which are Services.
+	EndpointsDiscovered bool
+	// TrafficDistribution is the value of spec.trafficDistribution when the
+	// Backend is a Service.
+	TrafficDistribution string
 	// Errors contains any errorrs associated with this resource.
 	Errors []error
 }
 
 func NewBackendNode(backend *unstructured.Unstructured) *BackendNode {
 	return &BackendNode{
-		Backend:           backend,
-		HTTPRoutes:        make(map[httpRouteID]*HTTPRouteNode),
-		Policies:          make(map[policyID]*PolicyNode),
-		ReferenceGrants:   make(map[referenceGrantID]*ReferenceGrantNode),
-		EffectivePolicies: make(map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy),
-		Errors:            []error{},
+		Backend:                   backend,
+		HTTPRoutes:                make(map[httpRouteID]*HTTPRouteNode),
+		MeshHTTPRoutes:            make(map[httpRouteID]*HTTPRouteNode),
+		DefaultBackendOf:          make(map[gatewayID]*GatewayNode),
+		Policies:                  make(map[policyID]*PolicyNode),
+		ReferenceGrants:           make(map[referenceGrantID]*ReferenceGrantNode),
+		EffectivePolicies:         make(map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy),
+		ListenerEffectivePolicies: make(map[gatewayID]map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy),
+		EndpointZones:             make(map[string]int),
+		Errors:                    []error{},
 	}
 }
 
 func (b BackendNode) ClientObject() client.Object { return b.Backend }
 
+func (b *BackendNode) NodeID() string {
+	return nodeID(b.Backend.GetKind(), b.Backend.GetNamespace(), b.Backend.GetName())
+}
+
+// Zones returns the sorted list of availability zones in which the Backend has
+// endpoints.
+func (b *BackendNode) Zones() []string {
+	var zones []string
+	for zone := range b.EndpointZones {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// ReceivesZeroWeight returns true if the Backend is referenced by HTTPRoutes,
+// but each of their backendRefs referencing it has a weight of 0, so no
+// traffic is forwarded to it. Default backends of Gateways, and backends which
+// RequestMirror filters mirror requests to, always receive traffic.
+func (b *BackendNode) ReceivesZeroWeight() bool {
+	if len(b.HTTPRoutes) == 0 || len(b.DefaultBackendOf) != 0 {
+		return false
+	}
+	id := b.ID()
+	for _, httpRouteNode := range b.HTTPRoutes {
+		namespace := httpRouteNode.HTTPRoute.GetNamespace()
+		for _, rule := range httpRouteNode.HTTPRoute.Spec.Rules {
+			if mirrorsTo(namespace, rule.Filters, id) {
+				return false
+			}
+			for _, backendRef := range rule.BackendRefs {
+				if mirrorsTo(namespace, backendRef.Filters, id) {
+					return false
+				}
+				objRef := httpBackendRefObjRef(httpRouteNode.HTTPRoute, backendRef)
+				if BackendID(objRef.Group, objRef.Kind, objRef.Namespace, objRef.Name) != id {
+					continue
+				}
+				if backendRefWeight(backendRef) != 0 {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// mirrorsTo returns true if one of the filters of an HTTPRoute in namespace is
+// a RequestMirror filter mirroring requests to the backend.
+func mirrorsTo(namespace string, filters []gatewayv1.HTTPRouteFilter, id backendID) bool {
+	for _, filter := range filters {
+		if filter.Type != gatewayv1.HTTPRouteFilterRequestMirror || filter.RequestMirror == nil {
+			continue
+		}
+		objRef := relations.BackendObjRef(namespace, filter.RequestMirror.BackendRef)
+		if BackendID(objRef.Group, objRef.Kind, objRef.Namespace, objRef.Name) == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *BackendNode) ID() backendID { //nolint:revive
 	if b.Backend == nil {
 		klog.V(0).ErrorS(nil, "returning empty ID since Backend is empty")
@@ -288,10 +680,90 @@ func (b *BackendNode) ID() backendID { //nolint:revive
 	)
 }
 
+// ExtensionRefNode models an implementation specific object, e.g. the
+// configuration of a WAF, which is referenced by the ExtensionRef filters of
+// HTTPRoutes.
+type ExtensionRefNode struct {
+	// Object references the actual resource.
+	Object *unstructured.Unstructured
+
+	// HTTPRoutes lists HTTPRoutes that reference this object through an
+	// ExtensionRef filter.
+	HTTPRoutes map[httpRouteID]*HTTPRouteNode
+}
+
+func NewExtensionRefNode(object *unstructured.Unstructured) *ExtensionRefNode {
+	return &ExtensionRefNode{
+		Object:     object,
+		HTTPRoutes: make(map[httpRouteID]*HTTPRouteNode),
+	}
+}
+
+func (e ExtensionRefNode) ClientObject() client.Object { return e.Object }
+
+func (e *ExtensionRefNode) NodeID() string {
+	return nodeID(e.Object.GetKind(), e.Object.GetNamespace(), e.Object.GetName())
+}
+
+func (e *ExtensionRefNode) ID() extensionRefID { //nolint:revive
+	return ExtensionRefID(
+		e.Object.GroupVersionKind().Group,
+		e.Object.GroupVersionKind().Kind,
+		e.Object.GetNamespace(),
+		e.Object.GetName(),
+	)
+}
+
+// Summary returns a human readable summary of the key fields of the object,
+// as rendered by the summarizer registered for its kind through
+// RegisterExtensionRefSummarizer. It is empty if no summarizer is registered.
+func (e *ExtensionRefNode) Summary() string {
+	summarizer, ok := extensionRefSummarizers[e.Object.GroupVersionKind().GroupKind()]
+	if !ok {
+		return ""
+	}
+	return summarizer(e.Object)
+}
+
+// SecretNode models a Secret referenced by HTTPRoutes, e.g. for the credentials
+// of an authentication filter. Only the metadata of the Secret is kept, so that
+// its data never becomes part of the ResourceModel.
+type SecretNode struct {
+	// Secret references the Secret, without its data. For a missing Secret,
+	// only its name and namespace are set.
+	Secret *corev1.Secret
+	// Missing is true if the referenced Secret does not exist. The node is
+	// kept as a placeholder, so that the dangling reference remains visible.
+	Missing bool
+
+	// HTTPRoutes lists HTTPRoutes that reference this Secret.
+	HTTPRoutes map[httpRouteID]*HTTPRouteNode
+}
+
+func NewSecretNode(secret *corev1.Secret) *SecretNode {
+	return &SecretNode{
+		Secret:     secret,
+		HTTPRoutes: make(map[httpRouteID]*HTTPRouteNode),
+	}
+}
+
+func (s SecretNode) ClientObject() client.Object { return s.Secret }
+
+func (s *SecretNode) NodeID() string {
+	return nodeID("Secret", s.Secret.GetNamespace(), s.Secret.GetName())
+}
+
+func (s *SecretNode) ID() secretID { //nolint:revive
+	return SecretID(s.Secret.GetNamespace(), s.Secret.GetName())
+}
+
 // NamespaceNode models the relationships and dependencies of a Namespace.
 type NamespaceNode struct {
 	// NamespaceName identifies the Namespace.
 	Namespace *corev1.Namespace
+	// Labels are the labels of the Namespace, against which namespace
+	// selectors are evaluated.
+	Labels map[string]string
 
 	// Gateways lists Gateways deployed within the Namespace.
 	Gateways map[gatewayID]*GatewayNode
@@ -309,6 +781,7 @@ func NewNamespaceNode(namespace corev1.Namespace) *NamespaceNode {
 	}
 	return &NamespaceNode{
 		Namespace:  &namespace,
+		Labels:     namespace.GetLabels(),
 		Gateways:   make(map[gatewayID]*GatewayNode),
 		HTTPRoutes: make(map[httpRouteID]*HTTPRouteNode),
 		Backends:   make(map[backendID]*BackendNode),
@@ -318,6 +791,8 @@ func NewNamespaceNode(namespace corev1.Namespace) *NamespaceNode {
 
 func (n *NamespaceNode) ClientObject() client.Object { return n.Namespace }
 
+func (n *NamespaceNode) NodeID() string { return nodeID("Namespace", "", n.Namespace.GetName()) }
+
 func (n *NamespaceNode) ID() namespaceID { //nolint:revive
 	if n.Namespace.Name == "" {
 		klog.V(0).ErrorS(nil, "returning empty ID since Namespace is empty")
@@ -333,15 +808,24 @@ type ReferenceGrantNode struct {
 
 	// Backends lists Backends residing within the ReferenceGrant.
 	Backends map[backendID]*BackendNode
+	// Gateways lists Gateways residing within the ReferenceGrant.
+	Gateways map[gatewayID]*GatewayNode
 }
 
 func NewReferenceGrantNode(referenceGrant *gatewayv1beta1.ReferenceGrant) *ReferenceGrantNode {
 	return &ReferenceGrantNode{
 		ReferenceGrant: referenceGrant,
 		Backends:       make(map[backendID]*BackendNode),
+		Gateways:       make(map[gatewayID]*GatewayNode),
 	}
 }
 
+func (r *ReferenceGrantNode) ClientObject() client.Object { return r.ReferenceGrant }
+
+func (r *ReferenceGrantNode) NodeID() string {
+	return nodeID("ReferenceGrant", r.ReferenceGrant.GetNamespace(), r.ReferenceGrant.GetName())
+}
+
 func (r *ReferenceGrantNode) ID() referenceGrantID { //nolint:revive
 	if r.ReferenceGrant.Name == "" {
 		klog.V(0).ErrorS(nil, "returning empty ID since ReferenceGrant is empty")
@@ -373,16 +857,34 @@ type PolicyNode struct {
 	// Backend references the BackendNode to which the policy is directly
 	// attached. It's nil if the policy is not associated with a specific Backend.
 	Backend *BackendNode
+
+	// The following store the nodes to which the policy is attached through its
+	// targetSelector. A policy with a targetSelector can be attached to many
+	// nodes, all of the same kind.
+	SelectedGatewayClasses map[gatewayClassID]*GatewayClassNode
+	SelectedNamespaces     map[namespaceID]*NamespaceNode
+	SelectedGateways       map[gatewayID]*GatewayNode
+	SelectedHTTPRoutes     map[httpRouteID]*HTTPRouteNode
+	SelectedBackends       map[backendID]*BackendNode
 }
 
 func NewPolicyNode(policy *policymanager.Policy) *PolicyNode {
 	return &PolicyNode{
-		Policy: policy,
+		Policy:                 policy,
+		SelectedGatewayClasses: make(map[gatewayClassID]*GatewayClassNode),
+		SelectedNamespaces:     make(map[namespaceID]*NamespaceNode),
+		SelectedGateways:       make(map[gatewayID]*GatewayNode),
+		SelectedHTTPRoutes:     make(map[httpRouteID]*HTTPRouteNode),
+		SelectedBackends:       make(map[backendID]*BackendNode),
 	}
 }
 
 func (p PolicyNode) ClientObject() client.Object { return p.Policy.Unstructured() }
 
+func (p *PolicyNode) NodeID() string {
+	return nodeID(p.Policy.Unstructured().GetKind(), p.Policy.Unstructured().GetNamespace(), p.Policy.Unstructured().GetName())
+}
+
 func (p *PolicyNode) ID() policyID { //nolint:revive
 	if p.Policy == nil {
 		klog.V(0).ErrorS(nil, "returning empty ID since Policy is empty")
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestGatewayNode_DependentRoutes(t *testing.T) {
+	gateway := func(name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		}
+	}
+	httpRoute := func(name string, gatewayNames ...string) *gatewayv1.HTTPRoute {
+		var parentRefs []gatewayv1.ParentReference
+		for _, gatewayName := range gatewayNames {
+			parentRefs = append(parentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayName)})
+		}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("foo-gateway"),
+		gateway("bar-gateway"),
+		// foo-httproute is only attached to foo-gateway.
+		httpRoute("foo-httproute", "foo-gateway"),
+		// shared-httproute is attached to both Gateways.
+		httpRoute("shared-httproute", "foo-gateway", "bar-gateway"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Namespace: "default", Name: "foo-gateway", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	gatewayNode, ok := resourceModel.Gateways[GatewayID("default", "foo-gateway")]
+	if !ok {
+		t.Fatalf("Gateway default/foo-gateway missing from resourceModel")
+	}
+
+	routeNames := func(httpRouteNodes []*HTTPRouteNode) []string {
+		var result []string
+		for _, httpRouteNode := range httpRouteNodes {
+			result = append(result, httpRouteNode.HTTPRoute.GetName())
+		}
+		return result
+	}
+
+	if diff := cmp.Diff([]string{"foo-httproute"}, routeNames(gatewayNode.DependentRoutes())); diff != "" {
+		t.Errorf("Unexpected diff in DependentRoutes() (-want +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]string{"shared-httproute"}, routeNames(gatewayNode.SharedRoutes())); diff != "" {
+		t.Errorf("Unexpected diff in SharedRoutes() (-want +got):\n%v", diff)
+	}
+
+	// bar-gateway was not discovered, but it must still be reported as another
+	// parent of shared-httproute.
+	sharedHTTPRouteNode := resourceModel.HTTPRoutes[HTTPRouteID("default", "shared-httproute")]
+	wantOtherParents := []common.ObjRef{{Group: gatewayv1.GroupName, Kind: "Gateway", Name: "bar-gateway", Namespace: "default"}}
+	if diff := cmp.Diff(wantOtherParents, sharedHTTPRouteNode.OtherParents(gatewayNode.ID())); diff != "" {
+		t.Errorf("Unexpected diff in OtherParents() (-want +got):\n%v", diff)
+	}
+}
+
+func TestGatewayNode_DependentRoutes_MissingParents(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// foo-httproute is attached to foo-gateway, and references a Gateway
+		// and a Service which do not exist as parents.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+					{Name: "foo-gateway"},
+					{Name: "missing-gateway"},
+					{Group: common.PtrTo(gatewayv1.Group("")), Kind: common.PtrTo(gatewayv1.Kind("Service")), Name: "missing-service"},
+				}},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	filter := Filter{Namespace: "default", Name: "foo-gateway", Labels: labels.Everything()}
+
+	// Without verifying the other parents, they are assumed to exist.
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	if got := resourceModel.Gateways[GatewayID("default", "foo-gateway")].DependentRoutes(); len(got) != 0 {
+		t.Errorf("DependentRoutes() = %v; want none", got)
+	}
+
+	resourceModel, err = discoverer.DiscoverResourcesForGatewayImpact(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	gatewayNode := resourceModel.Gateways[GatewayID("default", "foo-gateway")]
+	if got := gatewayNode.DependentRoutes(); len(got) != 1 || got[0].HTTPRoute.GetName() != "foo-httproute" {
+		t.Errorf("DependentRoutes() = %v; want default/foo-httproute", got)
+	}
+	if got := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")].OtherParents(gatewayNode.ID()); len(got) != 0 {
+		t.Errorf("OtherParents() = %v; want none", got)
+	}
+}
+
+func TestHTTPRouteNode_OrderedBackends(t *testing.T) {
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(name),
+				Port: common.PtrTo(gatewayv1.PortNumber(8080)),
+			},
+		}}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					// primary-svc fails over to secondary-svc; the order is not
+					// alphabetical so that it can not be restored by sorting.
+					{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("primary-svc"), backendRef("secondary-svc")}},
+					// primary-svc is only listed at its first reference.
+					{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("primary-svc"), backendRef("archive-svc")}},
+				},
+			},
+		},
+		service("archive-svc"),
+		service("secondary-svc"),
+		service("primary-svc"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Namespace: "default", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-httproute missing from resourceModel")
+	}
+
+	want := []backendID{
+		BackendIDForService("default", "primary-svc"),
+		BackendIDForService("default", "secondary-svc"),
+		BackendIDForService("default", "archive-svc"),
+	}
+	if diff := cmp.Diff(want, httpRouteNode.OrderedBackends()); diff != "" {
+		t.Errorf("Unexpected diff in OrderedBackends() (-want +got):\n%v", diff)
+	}
+}
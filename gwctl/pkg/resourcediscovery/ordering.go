@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+	"strings"
+)
+
+// Edge is a directed edge between two nodes of the ResourceModel.
+type Edge struct {
+	From, To Node
+	// Policy is true for edges from a policy to its target.
+	Policy bool
+}
+
+// SortedNodes returns all nodes within the ResourceModel, sorted by kind, then
+// namespace, then name. Exporters should iterate over the nodes in this order
+// so that the same ResourceModel always results in the same output.
+func (rm *ResourceModel) SortedNodes() []Node {
+	var result []Node
+	for _, node := range rm.Nodes() {
+		result = append(result, node)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return nodeLess(result[i], result[j])
+	})
+	return result
+}
+
+// SortedEdges returns the edges between the nodes of the ResourceModel, sorted
+// by their source node and then by their destination node, each ordered like
+// in SortedNodes. An edge which is not from a policy sorts before a policy
+// edge between the same nodes.
+//
+// Edges are:
+//   - From a GatewayClass to its Gateways.
+//   - From a Gateway or a Service to the HTTPRoutes attached to it.
+//   - From an HTTPRoute to its Backends, ExtensionRefs and Secrets.
+//   - From a ReferenceGrant to the Backends it exposes.
+//   - From a policy to each resource it targets, including Namespaces.
+func (rm *ResourceModel) SortedEdges() []Edge {
+	var edges []Edge
+	for _, gatewayNode := range rm.Gateways {
+		if gatewayNode.GatewayClass != nil {
+			edges = append(edges, Edge{From: gatewayNode.GatewayClass, To: gatewayNode})
+		}
+	}
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		for _, gatewayNode := range httpRouteNode.Gateways {
+			edges = append(edges, Edge{From: gatewayNode, To: httpRouteNode})
+		}
+		for _, serviceNode := range httpRouteNode.ParentServices {
+			edges = append(edges, Edge{From: serviceNode, To: httpRouteNode})
+		}
+		for _, backendNode := range httpRouteNode.Backends {
+			edges = append(edges, Edge{From: httpRouteNode, To: backendNode})
+		}
+		for _, extensionRefNode := range httpRouteNode.ExtensionRefs {
+			edges = append(edges, Edge{From: httpRouteNode, To: extensionRefNode})
+		}
+		for _, secretNode := range httpRouteNode.Secrets {
+			edges = append(edges, Edge{From: httpRouteNode, To: secretNode})
+		}
+	}
+	for _, referenceGrantNode := range rm.ReferenceGrants {
+		for _, backendNode := range referenceGrantNode.Backends {
+			edges = append(edges, Edge{From: referenceGrantNode, To: backendNode})
+		}
+	}
+	for _, policyNode := range rm.Policies {
+		var targets []Node
+		if policyNode.Namespace != nil {
+			targets = append(targets, policyNode.Namespace)
+		}
+		if policyNode.GatewayClass != nil {
+			targets = append(targets, policyNode.GatewayClass)
+		}
+		if policyNode.Gateway != nil {
+			targets = append(targets, policyNode.Gateway)
+		}
+		if policyNode.HTTPRoute != nil {
+			targets = append(targets, policyNode.HTTPRoute)
+		}
+		if policyNode.Backend != nil {
+			targets = append(targets, policyNode.Backend)
+		}
+		for _, namespaceNode := range policyNode.SelectedNamespaces {
+			targets = append(targets, namespaceNode)
+		}
+		for _, gatewayClassNode := range policyNode.SelectedGatewayClasses {
+			targets = append(targets, gatewayClassNode)
+		}
+		for _, gatewayNode := range policyNode.SelectedGateways {
+			targets = append(targets, gatewayNode)
+		}
+		for _, httpRouteNode := range policyNode.SelectedHTTPRoutes {
+			targets = append(targets, httpRouteNode)
+		}
+		for _, backendNode := range policyNode.SelectedBackends {
+			targets = append(targets, backendNode)
+		}
+		for _, target := range targets {
+			edges = append(edges, Edge{From: policyNode, To: target, Policy: true})
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.From.NodeID() != b.From.NodeID() {
+			return nodeLess(a.From, b.From)
+		}
+		if a.To.NodeID() != b.To.NodeID() {
+			return nodeLess(a.To, b.To)
+		}
+		return !a.Policy && b.Policy
+	})
+	return edges
+}
+
+// nodeLess orders nodes by kind, then namespace, then name. The kind is taken
+// from the NodeID, since typed objects do not always have their kind set.
+func nodeLess(a, b Node) bool {
+	aKind, _, _ := strings.Cut(a.NodeID(), "/")
+	bKind, _, _ := strings.Cut(b.NodeID(), "/")
+	if aKind != bKind {
+		return aKind < bKind
+	}
+	aObject, bObject := a.ClientObject(), b.ClientObject()
+	if aObject.GetNamespace() != bObject.GetNamespace() {
+		return aObject.GetNamespace() < bObject.GetNamespace()
+	}
+	return aObject.GetName() < bObject.GetName()
+}
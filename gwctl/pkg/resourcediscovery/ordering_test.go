@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_SortedNodesAndEdges(t *testing.T) {
+	gateway := func(namespace, name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		}
+	}
+	httpRoute := func(namespace, name, gatewayName, serviceName string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayName)}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: gatewayv1.ObjectName(serviceName),
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+	service := func(namespace, name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		}
+	}
+
+	buildModel := func() *ResourceModel {
+		objects := []runtime.Object{
+			common.NamespaceForTest("default"),
+			common.NamespaceForTest("prod"),
+			&gatewayv1.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"}},
+			gateway("prod", "a-gateway"),
+			gateway("default", "b-gateway"),
+			httpRoute("prod", "a-httproute", "a-gateway", "a-svc"),
+			httpRoute("default", "b-httproute", "b-gateway", "b-svc"),
+			httpRoute("default", "a-httproute", "b-gateway", "a-svc"),
+			service("prod", "a-svc"),
+			service("default", "b-svc"),
+			service("default", "a-svc"),
+		}
+		params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+		discoverer := Discoverer{
+			K8sClients:    params.K8sClients,
+			PolicyManager: params.PolicyManager,
+		}
+		resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+		if err != nil {
+			t.Fatalf("Failed to construct resourceModel: %v", err)
+		}
+		return resourceModel
+	}
+	nodeIDs := func(rm *ResourceModel) []string {
+		var result []string
+		for _, node := range rm.SortedNodes() {
+			result = append(result, node.NodeID())
+		}
+		return result
+	}
+	edgeIDs := func(rm *ResourceModel) []string {
+		var result []string
+		for _, edge := range rm.SortedEdges() {
+			result = append(result, edge.From.NodeID()+" -> "+edge.To.NodeID())
+		}
+		return result
+	}
+
+	first, second := buildModel(), buildModel()
+
+	wantNodes := []string{
+		"Gateway/default/b-gateway",
+		"Gateway/prod/a-gateway",
+		"GatewayClass/foo-gatewayclass",
+		"HTTPRoute/default/a-httproute",
+		"HTTPRoute/default/b-httproute",
+		"HTTPRoute/prod/a-httproute",
+		"Namespace/default",
+		"Namespace/prod",
+		"Service/default/a-svc",
+		"Service/default/b-svc",
+		"Service/prod/a-svc",
+	}
+	if diff := cmp.Diff(wantNodes, nodeIDs(first)); diff != "" {
+		t.Errorf("Unexpected diff in SortedNodes(); diff (-want +got)=\n%v", diff)
+	}
+	if diff := cmp.Diff(nodeIDs(first), nodeIDs(second)); diff != "" {
+		t.Errorf("SortedNodes() differs between two builds of the same model; diff (-first +second)=\n%v", diff)
+	}
+
+	wantEdges := []string{
+		"Gateway/default/b-gateway -> HTTPRoute/default/a-httproute",
+		"Gateway/default/b-gateway -> HTTPRoute/default/b-httproute",
+		"Gateway/prod/a-gateway -> HTTPRoute/prod/a-httproute",
+		"GatewayClass/foo-gatewayclass -> Gateway/default/b-gateway",
+		"GatewayClass/foo-gatewayclass -> Gateway/prod/a-gateway",
+		"HTTPRoute/default/a-httproute -> Service/default/a-svc",
+		"HTTPRoute/default/b-httproute -> Service/default/b-svc",
+		"HTTPRoute/prod/a-httproute -> Service/prod/a-svc",
+	}
+	if diff := cmp.Diff(wantEdges, edgeIDs(first)); diff != "" {
+		t.Errorf("Unexpected diff in SortedEdges(); diff (-want +got)=\n%v", diff)
+	}
+	if diff := cmp.Diff(edgeIDs(first), edgeIDs(second)); diff != "" {
+		t.Errorf("SortedEdges() differs between two builds of the same model; diff (-first +second)=\n%v", diff)
+	}
+}
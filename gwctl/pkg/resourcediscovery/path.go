@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"sort"
+)
+
+// Nodes returns all nodes within the ResourceModel, keyed by their NodeID.
+func (rm *ResourceModel) Nodes() map[string]Node {
+	result := make(map[string]Node)
+	for _, node := range rm.GatewayClasses {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.Namespaces {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.Gateways {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.HTTPRoutes {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.Backends {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.ReferenceGrants {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.Policies {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.ExtensionRefs {
+		result[node.NodeID()] = node
+	}
+	for _, node := range rm.Secrets {
+		result[node.NodeID()] = node
+	}
+	return result
+}
+
+// Path returns the shortest sequence of nodes through which traffic flows from
+// the node identified by fromID to the node identified by toID, e.g. Gateway ->
+// HTTPRoute -> Backend. IDs are the ones returned by Node.NodeID().
+//
+// Only traffic carrying edges are followed:
+//   - From a Gateway to the HTTPRoutes attached to it.
+//   - From a Service to the mesh HTTPRoutes attached to it.
+//   - From an HTTPRoute to its Backends. Cross namespace references which are
+//     not permitted by a ReferenceGrant are not part of the ResourceModel, so
+//     they are never followed.
+//
+// The returned bool is false if either node does not exist, or if there is no
+// path between them.
+func (rm *ResourceModel) Path(fromID, toID string) ([]Node, bool) {
+	nodes := rm.Nodes()
+	from, ok := nodes[fromID]
+	if !ok {
+		return nil, false
+	}
+	if _, ok := nodes[toID]; !ok {
+		return nil, false
+	}
+
+	// Breadth-first search, recording the node through which each node was
+	// first reached.
+	previous := map[string]Node{fromID: nil}
+	queue := []Node{from}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current.NodeID() == toID {
+			var path []Node
+			for node := current; node != nil; node = previous[node.NodeID()] {
+				path = append([]Node{node}, path...)
+			}
+			return path, true
+		}
+
+		for _, next := range trafficEdges(current) {
+			if _, seen := previous[next.NodeID()]; seen {
+				continue
+			}
+			previous[next.NodeID()] = current
+			queue = append(queue, next)
+		}
+	}
+	return nil, false
+}
+
+// trafficEdges returns the nodes to which traffic flows from the node, sorted
+// by their NodeID.
+func trafficEdges(node Node) []Node {
+	var result []Node
+	switch node := node.(type) {
+	case *GatewayNode:
+		for _, httpRouteNode := range node.HTTPRoutes {
+			result = append(result, httpRouteNode)
+		}
+	case *HTTPRouteNode:
+		for _, backendNode := range node.Backends {
+			result = append(result, backendNode)
+		}
+	case *BackendNode:
+		for _, httpRouteNode := range node.MeshHTTPRoutes {
+			result = append(result, httpRouteNode)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].NodeID() < result[j].NodeID() })
+	return result
+}
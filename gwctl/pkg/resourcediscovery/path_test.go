@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_Path(t *testing.T) {
+	service := func(namespace, name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+	}
+	serviceBackendRef := func(namespace, name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Kind:      common.PtrTo(gatewayv1.Kind("Service")),
+					Name:      gatewayv1.ObjectName(name),
+					Namespace: common.PtrTo(gatewayv1.Namespace(namespace)),
+					Port:      common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("bar"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{BackendRefs: []gatewayv1.HTTPBackendRef{serviceBackendRef("default", "foo-svc")}},
+				},
+			},
+		},
+		// bar-httproute references a Service in its own namespace, and a Service
+		// in another namespace without any ReferenceGrant permitting it.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-httproute",
+				Namespace: "bar",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway", Namespace: common.PtrTo(gatewayv1.Namespace("default"))}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{BackendRefs: []gatewayv1.HTTPBackendRef{
+						serviceBackendRef("bar", "bar-svc"),
+						serviceBackendRef("default", "baz-svc"),
+					}},
+				},
+			},
+		},
+		service("default", "foo-svc"),
+		service("bar", "bar-svc"),
+		service("default", "baz-svc"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+	// baz-svc must be part of the model for the blocked path to be meaningful.
+	if _, ok := resourceModel.Nodes()["Service/default/baz-svc"]; !ok {
+		t.Fatalf("Service default/baz-svc missing from resourceModel")
+	}
+
+	testcases := []struct {
+		name     string
+		fromID   string
+		toID     string
+		wantPath []string
+		wantOK   bool
+	}{
+		{
+			name:     "reachable path from Gateway to Service",
+			fromID:   "Gateway/default/foo-gateway",
+			toID:     "Service/default/foo-svc",
+			wantPath: []string{"Gateway/default/foo-gateway", "HTTPRoute/default/foo-httproute", "Service/default/foo-svc"},
+			wantOK:   true,
+		},
+		{
+			name:     "reachable path through cross namespace HTTPRoute",
+			fromID:   "Gateway/default/foo-gateway",
+			toID:     "Service/bar/bar-svc",
+			wantPath: []string{"Gateway/default/foo-gateway", "HTTPRoute/bar/bar-httproute", "Service/bar/bar-svc"},
+			wantOK:   true,
+		},
+		{
+			name:   "traffic does not flow from Service to Gateway",
+			fromID: "Service/default/foo-svc",
+			toID:   "Gateway/default/foo-gateway",
+			wantOK: false,
+		},
+		{
+			name:   "path blocked by missing ReferenceGrant",
+			fromID: "Gateway/default/foo-gateway",
+			toID:   "Service/default/baz-svc",
+			wantOK: false,
+		},
+		{
+			name:   "non-existent node",
+			fromID: "Gateway/default/foo-gateway",
+			toID:   "Service/default/does-not-exist",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, ok := resourceModel.Path(tc.fromID, tc.toID)
+			if ok != tc.wantOK {
+				t.Fatalf("Path(%q, %q) returned ok=%v, want %v", tc.fromID, tc.toID, ok, tc.wantOK)
+			}
+
+			var gotPath []string
+			for _, node := range path {
+				gotPath = append(gotPath, node.NodeID())
+			}
+			if diff := cmp.Diff(tc.wantPath, gotPath); diff != "" {
+				t.Errorf("Unexpected diff in Path(%q, %q); got=%v, want=%v;\ndiff (-want +got)=\n%v", tc.fromID, tc.toID, gotPath, tc.wantPath, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// PolicyTreeNode is a resource in the inheritance tree of a policy kind. The
+// embedded LevelDelta describes what the policies attached to the resource
+// contribute to the effective policy inherited from its ancestors in the tree.
+type PolicyTreeNode struct {
+	policymanager.LevelDelta
+	// Resource is the resource at this level of the hierarchy.
+	Resource common.ObjRef
+	// Inherited is true if an ancestor in the tree has policies of the kind,
+	// i.e. the resource is affected by policies of the kind even if none are
+	// attached to it.
+	Inherited bool
+	// Children are the resources which inherit from this resource, sorted by
+	// their Level.
+	Children []*PolicyTreeNode
+}
+
+// PolicyTree returns the inheritance tree of the policy kind across the
+// resourceModel, rooted at GatewayClasses and descending through Gateways to
+// HTTPRoutes. Namespaces are only included as a level when they have policies
+// of the kind. Subtrees which are not affected by any policy of the kind are
+// omitted.
+func (rm *ResourceModel) PolicyTree(policyCrdID policymanager.PolicyCrdID) ([]*PolicyTreeNode, error) {
+	var roots []*PolicyTreeNode
+	for _, gatewayClassNode := range common.MapToValues(rm.GatewayClasses) {
+		node, effective, err := newPolicyTreeNode(policyCrdID, policyHierarchyLevel{
+			name:     fmt.Sprintf("GatewayClass %v", gatewayClassNode.GatewayClass.GetName()),
+			policies: gatewayClassNode.Policies,
+		}, common.ObjRef{Group: gatewayv1.GroupName, Kind: "GatewayClass", Name: gatewayClassNode.GatewayClass.GetName()}, nil)
+		if err != nil {
+			return nil, err
+		}
+		node.Children, err = namespacedPolicyTrees(policyCrdID, effective, common.MapToValues(gatewayClassNode.Gateways),
+			func(gatewayNode *GatewayNode) *NamespaceNode { return gatewayNode.Namespace },
+			rm.gatewayPolicyTree)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, node)
+	}
+	sortPolicyTrees(roots)
+
+	// Gateways whose GatewayClass is not part of the resourceModel become roots
+	// themselves.
+	var orphanedGateways []*GatewayNode
+	for _, gatewayNode := range rm.Gateways {
+		if gatewayNode.GatewayClass == nil {
+			orphanedGateways = append(orphanedGateways, gatewayNode)
+		}
+	}
+	orphanedRoots, err := namespacedPolicyTrees(policyCrdID, nil, orphanedGateways,
+		func(gatewayNode *GatewayNode) *NamespaceNode { return gatewayNode.Namespace },
+		rm.gatewayPolicyTree)
+	if err != nil {
+		return nil, err
+	}
+	roots = append(roots, orphanedRoots...)
+
+	return prunePolicyTrees(roots), nil
+}
+
+func (rm *ResourceModel) gatewayPolicyTree(policyCrdID policymanager.PolicyCrdID, gatewayNode *GatewayNode, inherited *policymanager.Policy) (*PolicyTreeNode, error) {
+	node, effective, err := newPolicyTreeNode(policyCrdID, policyHierarchyLevel{
+		name:     fmt.Sprintf("Gateway %v/%v", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName()),
+		policies: gatewayNode.Policies,
+	}, gatewayObjRef(gatewayNode.ID()), inherited)
+	if err != nil {
+		return nil, err
+	}
+
+	// HTTPRoutes in other namespaces than the Gateway do not inherit policies
+	// of kinds whose MergeRules disable cross namespace propagation.
+	httpRoutesByInherited := map[*policymanager.Policy][]*HTTPRouteNode{}
+	for _, httpRouteNode := range common.MapToValues(gatewayNode.HTTPRoutes) {
+		inherited := effective
+		if effective != nil && httpRouteNode.HTTPRoute.GetNamespace() != gatewayNode.Gateway.GetNamespace() && !effective.MergeRules().PropagatesAcrossNamespaces() {
+			inherited = nil
+		}
+		httpRoutesByInherited[inherited] = append(httpRoutesByInherited[inherited], httpRouteNode)
+	}
+	for inherited, httpRouteNodes := range httpRoutesByInherited {
+		children, err := namespacedPolicyTrees(policyCrdID, inherited, httpRouteNodes,
+			func(httpRouteNode *HTTPRouteNode) *NamespaceNode { return httpRouteNode.Namespace },
+			func(policyCrdID policymanager.PolicyCrdID, httpRouteNode *HTTPRouteNode, inherited *policymanager.Policy) (*PolicyTreeNode, error) {
+				node, _, err := newPolicyTreeNode(policyCrdID, policyHierarchyLevel{
+					name:     fmt.Sprintf("HTTPRoute %v/%v", httpRouteNode.HTTPRoute.GetNamespace(), httpRouteNode.HTTPRoute.GetName()),
+					policies: httpRouteNode.Policies,
+				}, common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()}, inherited)
+				return node, err
+			})
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, children...)
+	}
+	sortPolicyTrees(node.Children)
+	return node, nil
+}
+
+// namespacedPolicyTrees builds the trees of the children, which inherit the
+// given effective policy. Children are grouped under a node of their namespace
+// if the namespace has policies of the kind, since these apply in between the
+// parent and the children.
+func namespacedPolicyTrees[T any](
+	policyCrdID policymanager.PolicyCrdID,
+	inherited *policymanager.Policy,
+	children []T,
+	namespaceOf func(T) *NamespaceNode,
+	build func(policymanager.PolicyCrdID, T, *policymanager.Policy) (*PolicyTreeNode, error),
+) ([]*PolicyTreeNode, error) {
+	var result []*PolicyTreeNode
+	namespaceTrees := make(map[string]*PolicyTreeNode)
+	namespaceEffective := make(map[string]*policymanager.Policy)
+	for _, child := range children {
+		namespaceNode := namespaceOf(child)
+		if namespaceNode == nil || !hasPoliciesOfKind(namespaceNode.Policies, policyCrdID) {
+			node, err := build(policyCrdID, child, inherited)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, node)
+			continue
+		}
+
+		name := namespaceNode.Namespace.GetName()
+		namespaceTree, ok := namespaceTrees[name]
+		if !ok {
+			var effective *policymanager.Policy
+			var err error
+			namespaceTree, effective, err = newPolicyTreeNode(policyCrdID, policyHierarchyLevel{
+				name:     fmt.Sprintf("Namespace %v", name),
+				policies: namespaceNode.Policies,
+			}, common.ObjRef{Kind: "Namespace", Name: name}, inherited)
+			if err != nil {
+				return nil, err
+			}
+			namespaceTrees[name] = namespaceTree
+			namespaceEffective[name] = effective
+			result = append(result, namespaceTree)
+		}
+		node, err := build(policyCrdID, child, namespaceEffective[name])
+		if err != nil {
+			return nil, err
+		}
+		namespaceTree.Children = append(namespaceTree.Children, node)
+	}
+
+	for _, namespaceTree := range namespaceTrees {
+		sortPolicyTrees(namespaceTree.Children)
+	}
+	sortPolicyTrees(result)
+	return result, nil
+}
+
+// newPolicyTreeNode returns the node for the hierarchy level, along with the
+// part of the effective policy of the kind which is inherited by the next
+// level, after merging the policies of the level on top of the inherited one.
+// It is nil if no policies of the kind apply.
+func newPolicyTreeNode(policyCrdID policymanager.PolicyCrdID, level policyHierarchyLevel, resource common.ObjRef, inherited *policymanager.Policy) (*PolicyTreeNode, *policymanager.Policy, error) {
+	node := &PolicyTreeNode{
+		LevelDelta: policymanager.LevelDelta{Level: level.name},
+		Resource:   resource,
+		Inherited:  inherited != nil,
+	}
+
+	var policies []policymanager.Policy
+	for _, policy := range convertPoliciesMapToSlice(level.policies) {
+		if policy.PolicyCrdID() == policyCrdID {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return node, inherited, nil
+	}
+	node.Policies = policymanager.ToPolicyRefs(policies)
+
+	levelPolicies, err := policymanager.MergePoliciesOfSimilarKind(policies)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentPolicies := make(map[policymanager.PolicyCrdID]policymanager.Policy)
+	if inherited != nil {
+		parentPolicies[policyCrdID] = *inherited
+	}
+	merged, err := policymanager.MergePoliciesOfDifferentHierarchy(parentPolicies, levelPolicies)
+	if err != nil {
+		return nil, nil, err
+	}
+	effective := merged[policyCrdID]
+	node.Changes, err = policymanager.ComputeSpecDelta(inherited, effective)
+	if err != nil {
+		return nil, nil, err
+	}
+	inheritable := effective.InheritablePart()
+	return node, &inheritable, nil
+}
+
+func hasPoliciesOfKind(policies map[policyID]*PolicyNode, policyCrdID policymanager.PolicyCrdID) bool {
+	for _, policyNode := range policies {
+		if policyNode.Policy.PolicyCrdID() == policyCrdID {
+			return true
+		}
+	}
+	return false
+}
+
+// prunePolicyTrees drops the nodes which neither have nor inherit policies of
+// the kind, unless some of their descendants have.
+func prunePolicyTrees(nodes []*PolicyTreeNode) []*PolicyTreeNode {
+	var result []*PolicyTreeNode
+	for _, node := range nodes {
+		node.Children = prunePolicyTrees(node.Children)
+		if len(node.Policies) != 0 || node.Inherited || len(node.Children) != 0 {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+func sortPolicyTrees(nodes []*PolicyTreeNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Level < nodes[j].Level })
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_PolicyTree(t *testing.T) {
+	healthCheckPolicy := func(name string, defaults map[string]interface{}, targetRef map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+	httpRoute := func(name string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "bar-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		// bar-gateway is not affected by any HealthCheckPolicy, so it is omitted
+		// from the tree.
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "bar-gatewayclass",
+			},
+		},
+		httpRoute("foo-httproute"),
+		httpRoute("bar-httproute"),
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		healthCheckPolicy("health-check-gatewayclass",
+			map[string]interface{}{"timeout": int64(30), "retries": int64(2)},
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "GatewayClass", "name": "foo-gatewayclass"},
+		),
+		healthCheckPolicy("health-check-gateway",
+			map[string]interface{}{"timeout": int64(60)},
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "foo-gateway", "namespace": "default"},
+		),
+		healthCheckPolicy("health-check-httproute",
+			map[string]interface{}{"retries": int64(5)},
+			map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "foo-httproute", "namespace": "default"},
+		),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	got, err := resourceModel.PolicyTree("HealthCheckPolicy.foo.com")
+	if err != nil {
+		t.Fatalf("PolicyTree() failed: %v", err)
+	}
+
+	want := []*PolicyTreeNode{
+		{
+			LevelDelta: policymanager.LevelDelta{
+				Level: "GatewayClass foo-gatewayclass",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gatewayclass"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "retries", Value: float64(2)},
+					{Path: "timeout", Value: float64(30)},
+				},
+			},
+			Resource: common.ObjRef{Group: gatewayv1.GroupName, Kind: "GatewayClass", Name: "foo-gatewayclass"},
+			Children: []*PolicyTreeNode{
+				{
+					LevelDelta: policymanager.LevelDelta{
+						Level: "Gateway default/foo-gateway",
+						Policies: []policymanager.ObjRef{
+							{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gateway"},
+						},
+						Changes: []policymanager.FieldChange{
+							{Path: "timeout", Value: float64(60), PreviousValue: float64(30)},
+						},
+					},
+					Resource:  common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Name: "foo-gateway", Namespace: "default"},
+					Inherited: true,
+					Children: []*PolicyTreeNode{
+						{
+							LevelDelta: policymanager.LevelDelta{Level: "HTTPRoute default/bar-httproute"},
+							Resource:   common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Name: "bar-httproute", Namespace: "default"},
+							Inherited:  true,
+						},
+						{
+							LevelDelta: policymanager.LevelDelta{
+								Level: "HTTPRoute default/foo-httproute",
+								Policies: []policymanager.ObjRef{
+									{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-httproute"},
+								},
+								Changes: []policymanager.FieldChange{
+									{Path: "retries", Value: float64(5), PreviousValue: float64(2)},
+								},
+							},
+							Resource:  common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+							Inherited: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in PolicyTree(); diff (-want +got)=\n%v", diff)
+	}
+}
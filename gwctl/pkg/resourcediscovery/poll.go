@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"time"
+)
+
+// Poller runs discovery at a fixed interval and reports the ResourceModel only
+// when its ContentHash differs from that of the last reported one. It serves
+// as a fallback to watching resources where that is not permitted, e.g. when
+// RBAC grants the list verb but not the watch verb.
+type Poller struct {
+	// Interval is the time between the start of consecutive polls.
+	Interval time.Duration
+	// Discover discovers the ResourceModel, e.g. by calling
+	// Discoverer.DiscoverResourcesForTopology.
+	Discover func(ctx context.Context) (*ResourceModel, error)
+
+	lastHash string
+}
+
+// Poll runs discovery once. It returns the discovered ResourceModel if its
+// content changed since the last ResourceModel returned by Poll, and nil
+// otherwise. The first successful Poll always returns the ResourceModel.
+func (p *Poller) Poll(ctx context.Context) (*ResourceModel, error) {
+	resourceModel, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hash := resourceModel.ContentHash()
+	if hash == p.lastHash {
+		return nil, nil
+	}
+	p.lastHash = hash
+	return resourceModel, nil
+}
+
+// Run polls immediately, and then every Interval until ctx is done. onChange
+// is called with each ResourceModel returned by Poll. Errors of discovery are
+// passed to onError and do not stop polling, since they are often transient.
+// Run returns the error of ctx once it is done.
+func (p *Poller) Run(ctx context.Context, onChange func(*ResourceModel), onError func(error)) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		resourceModel, err := p.Poll(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			onError(err)
+		case resourceModel != nil:
+			onChange(resourceModel)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
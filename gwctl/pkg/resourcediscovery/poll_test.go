@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamicclient "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+// discoverFuncsForPollTest returns functions discovering the topology of a
+// cluster with a Gateway, before and after a label was added to the Gateway.
+func discoverFuncsForPollTest(t *testing.T) (before, after func(context.Context) (*ResourceModel, error)) {
+	objects := func(gatewayLabels map[string]string) []runtime.Object {
+		return []runtime.Object{
+			common.NamespaceForTest("default"),
+			&gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo-gatewayclass",
+				},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-gateway",
+					Namespace: "default",
+					Labels:    gatewayLabels,
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "foo-gatewayclass",
+				},
+			},
+		}
+	}
+	discoverFunc := func(objects []runtime.Object) func(context.Context) (*ResourceModel, error) {
+		params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+		discoverer := Discoverer{
+			K8sClients:    params.K8sClients,
+			PolicyManager: params.PolicyManager,
+		}
+		return func(ctx context.Context) (*ResourceModel, error) {
+			return discoverer.DiscoverResourcesForTopology(ctx, Filter{Labels: labels.Everything()})
+		}
+	}
+	return discoverFunc(objects(nil)), discoverFunc(objects(map[string]string{"env": "prod"}))
+}
+
+func TestPoller_Poll(t *testing.T) {
+	before, after := discoverFuncsForPollTest(t)
+	discover := before
+	poller := &Poller{
+		Interval: time.Minute,
+		Discover: func(ctx context.Context) (*ResourceModel, error) { return discover(ctx) },
+	}
+
+	testcases := []struct {
+		name       string
+		discover   func(context.Context) (*ResourceModel, error)
+		wantRedraw bool
+		wantErr    bool
+	}{
+		{name: "first poll", discover: before, wantRedraw: true},
+		{name: "unchanged model", discover: before},
+		{
+			name:     "failed discovery",
+			discover: func(context.Context) (*ResourceModel, error) { return nil, errors.New("forbidden") },
+			wantErr:  true,
+		},
+		// The failed discovery does not reset the last reported model.
+		{name: "unchanged model after failed discovery", discover: before},
+		{name: "changed model", discover: after, wantRedraw: true},
+		{name: "changed model polled again", discover: after},
+	}
+	// The polls run in order, since each depends on the previous ones.
+	for _, tc := range testcases {
+		discover = tc.discover
+		resourceModel, err := poller.Poll(context.Background())
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%v: Poll() err=%v; wantErr=%v", tc.name, err, tc.wantErr)
+		}
+		if gotRedraw := resourceModel != nil; gotRedraw != tc.wantRedraw {
+			t.Errorf("%v: Poll() returned ResourceModel=%v; want %v", tc.name, gotRedraw, tc.wantRedraw)
+		}
+	}
+}
+
+func TestPoller_Run(t *testing.T) {
+	before, after := discoverFuncsForPollTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The first two polls see the same model, the following ones the changed
+	// model. Polling stops after the fourth poll.
+	polls := 0
+	poller := &Poller{
+		Interval: time.Millisecond,
+		Discover: func(ctx context.Context) (*ResourceModel, error) {
+			polls++
+			if polls == 4 {
+				cancel()
+			}
+			if polls <= 2 {
+				return before(ctx)
+			}
+			return after(ctx)
+		},
+	}
+
+	var redrawn []string
+	err := poller.Run(ctx, func(resourceModel *ResourceModel) {
+		gatewayNode := resourceModel.Gateways[GatewayID("default", "foo-gateway")]
+		redrawn = append(redrawn, gatewayNode.Gateway.GetLabels()["env"])
+	}, func(err error) {
+		t.Errorf("Unexpected error: %v", err)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() err=%v; want %v", err, context.Canceled)
+	}
+	if polls != 4 {
+		t.Errorf("Run() polled %d times; want 4", polls)
+	}
+	// The model is redrawn on the first poll and when it changed on the third.
+	if want := []string{"", "prod"}; !slices.Equal(redrawn, want) {
+		t.Errorf("Run() redrew models with env labels %q; want %q", redrawn, want)
+	}
+}
+
+func TestPoller_Run_DiscoveryErrors(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "foo-svc"},
+					}}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+	}
+
+	testcases := []struct {
+		name string
+		// breakClients makes listing some kind fail.
+		breakClients func(k8sClients *common.K8sClients)
+		wantErr      string
+	}{
+		{
+			name: "listing Namespaces fails",
+			breakClients: func(k8sClients *common.K8sClients) {
+				k8sClients.Client = interceptor.NewClient(k8sClients.Client.(client.WithWatch), interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if _, ok := list.(*corev1.NamespaceList); ok {
+							return errors.New("connection refused")
+						}
+						return c.List(ctx, list, opts...)
+					},
+				})
+			},
+			wantErr: "failed to fetch list of namespaces: connection refused",
+		},
+		{
+			name: "listing ReferenceGrants fails",
+			breakClients: func(k8sClients *common.K8sClients) {
+				fakeDC := k8sClients.DC.(*fakedynamicclient.FakeDynamicClient)
+				fakeDC.PrependReactor("list", "referencegrants", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("connection refused")
+				})
+			},
+			wantErr: "failed to fetch list of ReferenceGrants: connection refused",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClients := common.MustClientsForTest(t, objects...)
+			params := utils.MustParamsForTest(t, k8sClients)
+			tc.breakClients(k8sClients)
+			discoverer := Discoverer{
+				K8sClients:    k8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+
+			// The error of the first poll must be passed to onError rather than
+			// terminating the process. Polling stops on the second poll.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			polls := 0
+			poller := &Poller{
+				Interval: time.Millisecond,
+				Discover: func(ctx context.Context) (*ResourceModel, error) {
+					polls++
+					if polls == 2 {
+						cancel()
+					}
+					return discoverer.DiscoverResourcesForTopology(ctx, Filter{Labels: labels.Everything()})
+				},
+			}
+			var gotErrs []string
+			err := poller.Run(ctx, func(*ResourceModel) {
+				t.Errorf("Unexpected ResourceModel")
+			}, func(err error) {
+				gotErrs = append(gotErrs, err.Error())
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Run() err=%v; want %v", err, context.Canceled)
+			}
+			if len(gotErrs) != 1 || !strings.Contains(gotErrs[0], tc.wantErr) {
+				t.Errorf("Run() passed errors %q to onError; want one containing %q", gotErrs, tc.wantErr)
+			}
+		})
+	}
+}
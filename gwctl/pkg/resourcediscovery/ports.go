@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// NamedBackendPort is a backendRef of an HTTPRoute which references the port
+// of a Service by its name rather than by its number. Some setups accept named
+// ports, but the structured BackendRef type only holds port numbers, so these
+// are extracted from the unstructured HTTPRoute.
+type NamedBackendPort struct {
+	// RuleIndex is the index of the rule which contains the backendRef.
+	RuleIndex int
+	// BackendRef is the referenced backend, with its namespace and kind
+	// defaulted.
+	BackendRef common.ObjRef
+	// Name is the name of the port.
+	Name string
+	// Resolved is true once the name was found among the ports of the
+	// Service. Port and AppProtocol are only meaningful if it is true.
+	Resolved bool
+	// Port is the number of the named port of the Service.
+	Port int32
+	// AppProtocol is the application protocol of the named port of the
+	// Service, if set.
+	AppProtocol string
+}
+
+func (n NamedBackendPort) String() string {
+	if !n.Resolved {
+		return fmt.Sprintf("Rule %d, backend %v: port %q", n.RuleIndex, BackendRefString(n.BackendRef), n.Name)
+	}
+	result := fmt.Sprintf("Rule %d, backend %v: port %q resolves to %d", n.RuleIndex, BackendRefString(n.BackendRef), n.Name, n.Port)
+	if n.AppProtocol != "" {
+		result = fmt.Sprintf("%v (appProtocol %v)", result, n.AppProtocol)
+	}
+	return result
+}
+
+// UnknownBackendPortError indicates that a backendRef of an HTTPRoute
+// references a port by a name which does not exist on the Service.
+type UnknownBackendPortError struct {
+	ReferenceFromTo
+	// RuleIndex is the index of the rule which contains the backendRef.
+	RuleIndex int
+	// Port is the name of the port.
+	Port string
+}
+
+func (e UnknownBackendPortError) Error() string {
+	return fmt.Sprintf("%v %q references port %q in rule %d which does not exist on %v %q",
+		e.referringObjectKind(), e.referringObjectName(), e.Port, e.RuleIndex,
+		e.referredObjectKind(), e.referredObjectName())
+}
+
+// ServicePort is a port exposed by a Backend which is a Service.
+type ServicePort struct {
+	Name        string
+	Port        int32
+	AppProtocol string
+}
+
+// ServicePorts returns the ports listed in the spec of the Backend. It is
+// empty for Backends which are not Services.
+func (b *BackendNode) ServicePorts() []ServicePort {
+	if b.Backend.GetKind() != "Service" {
+		return nil
+	}
+	ports, _, _ := unstructured.NestedSlice(b.Backend.Object, "spec", "ports")
+	var result []ServicePort
+	for _, port := range ports {
+		portMap, ok := port.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		servicePort := ServicePort{}
+		servicePort.Name, _, _ = unstructured.NestedString(portMap, "name")
+		servicePort.AppProtocol, _, _ = unstructured.NestedString(portMap, "appProtocol")
+		number, _, _ := unstructured.NestedInt64(portMap, "port")
+		servicePort.Port = int32(number)
+		result = append(result, servicePort)
+	}
+	return result
+}
+
+// extractNamedBackendPorts returns the backendRefs of the unstructured
+// HTTPRoute which reference ports by name, along with a copy of the HTTPRoute
+// from which these ports are removed so that it can be converted to the
+// structured type.
+func extractNamedBackendPorts(httpRoute *unstructured.Unstructured) ([]NamedBackendPort, *unstructured.Unstructured) {
+	rules, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+	var result []NamedBackendPort
+	for ruleIndex, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendRefs, _, _ := unstructured.NestedSlice(ruleMap, "backendRefs")
+		for _, backendRef := range backendRefs {
+			backendRefMap, ok := backendRef.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, ok := backendRefMap["port"].(string); ok {
+				result = append(result, NamedBackendPort{
+					RuleIndex:  ruleIndex,
+					BackendRef: backendRefObjRef(backendRefMap, httpRoute.GetNamespace()),
+					Name:       name,
+				})
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil, httpRoute
+	}
+
+	stripped := httpRoute.DeepCopy()
+	rules, _, _ = unstructured.NestedSlice(stripped.Object, "spec", "rules")
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendRefs, _, _ := unstructured.NestedSlice(ruleMap, "backendRefs")
+		for _, backendRef := range backendRefs {
+			if backendRefMap, ok := backendRef.(map[string]interface{}); ok {
+				if _, ok := backendRefMap["port"].(string); ok {
+					delete(backendRefMap, "port")
+				}
+			}
+		}
+		ruleMap["backendRefs"] = backendRefs
+	}
+	_ = unstructured.SetNestedSlice(stripped.Object, rules, "spec", "rules")
+	return result, stripped
+}
+
+// resolveNamedBackendPorts translates the named ports of the backendRefs of
+// HTTPRoutes to the port numbers of the referenced Services. Named ports which
+// do not exist on the Service are recorded as errors of the HTTPRoute. Ports
+// of Backends which are not part of the resourceModel remain unresolved.
+func (rm *ResourceModel) resolveNamedBackendPorts() {
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		for i := range httpRouteNode.NamedBackendPorts {
+			namedPort := &httpRouteNode.NamedBackendPorts[i]
+			if namedPort.Resolved {
+				continue
+			}
+			ref := namedPort.BackendRef
+			backendNode, ok := httpRouteNode.Backends[BackendID(ref.Group, ref.Kind, ref.Namespace, ref.Name)]
+			if !ok || backendNode.Backend.GetKind() != "Service" {
+				continue
+			}
+
+			found := false
+			for _, servicePort := range backendNode.ServicePorts() {
+				if servicePort.Name == namedPort.Name {
+					namedPort.Resolved = true
+					namedPort.Port = servicePort.Port
+					namedPort.AppProtocol = servicePort.AppProtocol
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+			err := UnknownBackendPortError{
+				ReferenceFromTo: ReferenceFromTo{
+					ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()},
+					ReferredObject:  common.ObjRef{Kind: "Service", Name: ref.Name, Namespace: ref.Namespace},
+				},
+				RuleIndex: namedPort.RuleIndex,
+				Port:      namedPort.Name,
+			}
+			if !hasError(httpRouteNode.Errors, err) {
+				httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+				klog.V(1).Info(err)
+			}
+		}
+	}
+}
+
+func hasError(errs []error, target error) bool {
+	for _, err := range errs {
+		if err == target {
+			return true
+		}
+	}
+	return false
+}
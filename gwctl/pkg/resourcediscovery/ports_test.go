@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamicclient "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResolveNamedBackendPorts(t *testing.T) {
+	backendRef := func(port interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": "foo-svc", "port": port}
+	}
+	httpRoute, err := newFetchedHTTPRoute(&unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      "foo-httproute",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"backendRefs": []interface{}{backendRef("http")}},
+					map[string]interface{}{"backendRefs": []interface{}{backendRef("grpc")}},
+					map[string]interface{}{"backendRefs": []interface{}{backendRef(int64(9090))}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newFetchedHTTPRoute() failed: %v", err)
+	}
+	// Numeric ports are converted to the structured type as usual.
+	if got, want := httpRoute.Spec.Rules[2].BackendRefs[0].Port, common.PtrTo(gatewayv1.PortNumber(9090)); !cmp.Equal(got, want) {
+		t.Errorf("Port of rule 2=%v; want %v", got, want)
+	}
+	if got := httpRoute.Spec.Rules[0].BackendRefs[0].Port; got != nil {
+		t.Errorf("Port of rule 0=%v; want nil", *got)
+	}
+
+	service := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":      "foo-svc",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"name": "http", "port": int64(8080), "appProtocol": "kubernetes.io/h2c"},
+					map[string]interface{}{"name": "metrics", "port": int64(9090)},
+				},
+			},
+		},
+	}
+
+	resourceModel := &ResourceModel{}
+	resourceModel.addHTTPRoutes(httpRoute)
+	resourceModel.addBackends(service)
+	httpRouteID := HTTPRouteID("default", "foo-httproute")
+	resourceModel.connectHTTPRouteWithBackend(httpRouteID, BackendIDForService("default", "foo-svc"))
+	resourceModel.resolveNamedBackendPorts()
+	// Resolving again must not record the errors twice.
+	resourceModel.resolveNamedBackendPorts()
+
+	httpRouteNode := resourceModel.HTTPRoutes[httpRouteID]
+	serviceRef := common.ObjRef{Kind: "Service", Name: "foo-svc", Namespace: "default"}
+	wantPorts := []NamedBackendPort{
+		{RuleIndex: 0, BackendRef: serviceRef, Name: "http", Resolved: true, Port: 8080, AppProtocol: "kubernetes.io/h2c"},
+		{RuleIndex: 1, BackendRef: serviceRef, Name: "grpc"},
+	}
+	if diff := cmp.Diff(wantPorts, httpRouteNode.NamedBackendPorts); diff != "" {
+		t.Errorf("Unexpected diff in NamedBackendPorts; got=%v, want=%v;\ndiff (-want +got)=\n%v", httpRouteNode.NamedBackendPorts, wantPorts, diff)
+	}
+
+	wantErrors := []error{
+		UnknownBackendPortError{
+			ReferenceFromTo: ReferenceFromTo{
+				ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: "foo-httproute", Namespace: "default"},
+				ReferredObject:  serviceRef,
+			},
+			RuleIndex: 1,
+			Port:      "grpc",
+		},
+	}
+	if diff := cmp.Diff(wantErrors, httpRouteNode.Errors, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Unexpected diff in Errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", httpRouteNode.Errors, wantErrors, diff)
+	}
+	for i, want := range []string{
+		`Rule 0, backend Service default/foo-svc: port "http" resolves to 8080 (appProtocol kubernetes.io/h2c)`,
+		`Rule 1, backend Service default/foo-svc: port "grpc"`,
+	} {
+		if got := httpRouteNode.NamedBackendPorts[i].String(); got != want {
+			t.Errorf("NamedBackendPorts[%d].String()=%q; want %q", i, got, want)
+		}
+	}
+	if got, want := httpRouteNode.Errors[0].Error(), `HTTPRoute "default/foo-httproute" references port "grpc" in rule 1 which does not exist on Service "default/foo-svc"`; got != want {
+		t.Errorf("Error()=%q; want %q", got, want)
+	}
+}
+
+// TestResolveNamedBackendPorts_Pipelines tests that the named ports of
+// backendRefs are resolved by every pipeline discovering the backends of
+// HTTPRoutes.
+func TestResolveNamedBackendPorts_Pipelines(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		&corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "http", Port: 8080}},
+			},
+		},
+	}
+
+	k8sClients := common.MustClientsForTest(t, objects...)
+	// Named ports can not be represented by the structured HTTPRoute which the
+	// fake clients convert to, so the HTTPRoute is listed through a reactor.
+	namedPortHTTPRoute := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      "foo-httproute",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"parentRefs": []interface{}{
+					map[string]interface{}{"name": "foo-gateway"},
+				},
+				"rules": []interface{}{
+					map[string]interface{}{"backendRefs": []interface{}{
+						map[string]interface{}{"name": "foo-svc", "port": "http"},
+					}},
+				},
+			},
+		},
+	}
+	fakeDC := k8sClients.DC.(*fakedynamicclient.FakeDynamicClient)
+	fakeDC.PrependReactor("list", "httproutes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*namedPortHTTPRoute}}, nil
+	})
+	params := utils.MustParamsForTest(t, k8sClients)
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	testcases := []struct {
+		name     string
+		discover func(context.Context, Filter) (*ResourceModel, error)
+		filter   Filter
+	}{
+		{
+			name:     "HTTPRoute",
+			discover: discoverer.DiscoverResourcesForHTTPRoute,
+			filter:   Filter{Namespace: "default", Labels: labels.Everything()},
+		},
+		{
+			name:     "Backend",
+			discover: discoverer.DiscoverResourcesForBackend,
+			filter:   Filter{Namespace: "default", Labels: labels.Everything()},
+		},
+		{
+			name:     "Topology",
+			discover: discoverer.DiscoverResourcesForTopology,
+			filter:   Filter{Namespace: "default", Labels: labels.Everything()},
+		},
+		{
+			name:     "NamespaceContents",
+			discover: discoverer.DiscoverResourcesForNamespaceContents,
+			filter:   Filter{Name: "default", Labels: labels.Everything()},
+		},
+	}
+	for _, tc := range testcases {
+		resourceModel, err := tc.discover(context.Background(), tc.filter)
+		if err != nil {
+			t.Fatalf("%v: Failed to construct resourceModel: %v", tc.name, err)
+		}
+		httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+		if !ok {
+			t.Errorf("%v: HTTPRoute default/foo-httproute not found in resourceModel", tc.name)
+			continue
+		}
+		if len(httpRouteNode.NamedBackendPorts) != 1 || !httpRouteNode.NamedBackendPorts[0].Resolved {
+			t.Errorf("%v: NamedBackendPorts=%v; want port \"http\" resolved", tc.name, httpRouteNode.NamedBackendPorts)
+		}
+	}
+}
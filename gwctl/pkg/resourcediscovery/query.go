@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"slices"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// EffectivePolicyQuery selects effective policies of a ResourceModel, as rows
+// of the resource they apply to, along with the Gateway and rule they apply
+// through. Queries are immutable: each method returns a new query, so a query
+// can be refined in different ways, e.g.
+//
+//	rm.EffectivePolicies().ForKind(crdID).OnGateways().Where(isPublic).Collect()
+type EffectivePolicyQuery struct {
+	rm *ResourceModel
+	// kinds restricts the kinds of policies. All kinds are selected if empty.
+	kinds []policymanager.PolicyCrdID
+}
+
+// EffectivePolicies returns a query selecting the effective policies of all
+// kinds on all Gateways, HTTPRoutes and Backends of the ResourceModel.
+func (rm *ResourceModel) EffectivePolicies() EffectivePolicyQuery {
+	return EffectivePolicyQuery{rm: rm}
+}
+
+// ForKind restricts the query to effective policies of the given kind. Calling
+// it multiple times selects the effective policies of any of the kinds.
+func (q EffectivePolicyQuery) ForKind(policyCrdID policymanager.PolicyCrdID) EffectivePolicyQuery {
+	q.kinds = append(slices.Clone(q.kinds), policyCrdID)
+	return q
+}
+
+// OnGateways restricts the query to the effective policies of Gateways.
+func (q EffectivePolicyQuery) OnGateways() NodeEffectivePolicyQuery[*GatewayNode] {
+	return NodeEffectivePolicyQuery[*GatewayNode]{
+		query:             q,
+		nodes:             func() []*GatewayNode { return common.MapToValues(q.rm.Gateways) },
+		effectivePolicies: gatewayEffectivePolicies,
+	}
+}
+
+// OnHTTPRoutes restricts the query to the effective policies of HTTPRoutes,
+// including those of their named rules.
+func (q EffectivePolicyQuery) OnHTTPRoutes() NodeEffectivePolicyQuery[*HTTPRouteNode] {
+	return NodeEffectivePolicyQuery[*HTTPRouteNode]{
+		query:             q,
+		nodes:             func() []*HTTPRouteNode { return common.MapToValues(q.rm.HTTPRoutes) },
+		effectivePolicies: httpRouteEffectivePolicies,
+	}
+}
+
+// OnBackends restricts the query to the effective policies of Backends.
+func (q EffectivePolicyQuery) OnBackends() NodeEffectivePolicyQuery[*BackendNode] {
+	return NodeEffectivePolicyQuery[*BackendNode]{
+		query:             q,
+		nodes:             func() []*BackendNode { return common.MapToValues(q.rm.Backends) },
+		effectivePolicies: backendEffectivePolicies,
+	}
+}
+
+// Collect returns the effective policies selected by the query, on all kinds of
+// resources, sorted by resource, Gateway, listener, rule and kind of policy.
+func (q EffectivePolicyQuery) Collect() []ResourceEffectivePolicy {
+	var result []ResourceEffectivePolicy
+	result = append(result, q.OnGateways().collect()...)
+	result = append(result, q.OnHTTPRoutes().collect()...)
+	result = append(result, q.OnBackends().collect()...)
+	sortResourceEffectivePolicies(result)
+	return result
+}
+
+// selects returns true if the query selects effective policies of the kind.
+func (q EffectivePolicyQuery) selects(policyCrdID policymanager.PolicyCrdID) bool {
+	return len(q.kinds) == 0 || slices.Contains(q.kinds, policyCrdID)
+}
+
+// NodeEffectivePolicyQuery is an EffectivePolicyQuery restricted to one kind of
+// resource, whose nodes of type N can be filtered with predicates.
+type NodeEffectivePolicyQuery[N any] struct {
+	query             EffectivePolicyQuery
+	predicates        []func(N) bool
+	nodes             func() []N
+	effectivePolicies func(N) []ResourceEffectivePolicy
+}
+
+// ForKind restricts the query to effective policies of the given kind, like
+// EffectivePolicyQuery.ForKind.
+func (q NodeEffectivePolicyQuery[N]) ForKind(policyCrdID policymanager.PolicyCrdID) NodeEffectivePolicyQuery[N] {
+	q.query = q.query.ForKind(policyCrdID)
+	return q
+}
+
+// Where restricts the query to the effective policies of the nodes for which
+// the predicate returns true. Calling it multiple times selects the nodes
+// satisfying all predicates.
+func (q NodeEffectivePolicyQuery[N]) Where(predicate func(N) bool) NodeEffectivePolicyQuery[N] {
+	q.predicates = append(slices.Clone(q.predicates), predicate)
+	return q
+}
+
+// Collect returns the effective policies selected by the query, sorted by
+// resource, Gateway, listener, rule and kind of policy.
+func (q NodeEffectivePolicyQuery[N]) Collect() []ResourceEffectivePolicy {
+	result := q.collect()
+	sortResourceEffectivePolicies(result)
+	return result
+}
+
+func (q NodeEffectivePolicyQuery[N]) collect() []ResourceEffectivePolicy {
+	var result []ResourceEffectivePolicy
+	for _, node := range q.nodes() {
+		if !q.matches(node) {
+			continue
+		}
+		for _, effectivePolicy := range q.effectivePolicies(node) {
+			if q.query.selects(effectivePolicy.Policy.PolicyCrdID()) {
+				result = append(result, effectivePolicy)
+			}
+		}
+	}
+	return result
+}
+
+func (q NodeEffectivePolicyQuery[N]) matches(node N) bool {
+	for _, predicate := range q.predicates {
+		if !predicate(node) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestEffectivePolicyQuery(t *testing.T) {
+	policyCRD := func(plural, kind string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: plural + ".foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	policy := func(kind, name, targetKind, targetName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"enabled": true,
+					},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+				},
+			},
+		}
+	}
+	gateway := func(name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("foo-gateway"),
+		gateway("bar-gateway"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: "foo-svc",
+							Port: common.PtrTo(gatewayv1.PortNumber(80)),
+						},
+					}}},
+				}},
+			},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"}},
+
+		policyCRD("timeoutpolicies", "TimeoutPolicy"),
+		policyCRD("retrypolicies", "RetryPolicy"),
+		policy("TimeoutPolicy", "timeout-policy-foo-gateway", "Gateway", "foo-gateway"),
+		policy("TimeoutPolicy", "timeout-policy-bar-gateway", "Gateway", "bar-gateway"),
+		policy("RetryPolicy", "retry-policy-foo-httproute", "HTTPRoute", "foo-httproute"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	fooGateway := common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "default", Name: "foo-gateway"}
+	barGateway := common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "default", Name: "bar-gateway"}
+	fooHTTPRoute := common.ObjRef{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default", Name: "foo-httproute"}
+	fooSvc := common.ObjRef{Kind: "Service", Namespace: "default", Name: "foo-svc"}
+
+	type row struct {
+		Resource common.ObjRef
+		Gateway  common.ObjRef
+		Kind     policymanager.PolicyCrdID
+	}
+	testCases := []struct {
+		name  string
+		query func() []ResourceEffectivePolicy
+		want  []row
+	}{
+		{
+			name:  "all kinds on all resources",
+			query: resourceModel.EffectivePolicies().Collect,
+			want: []row{
+				{Resource: barGateway, Kind: "TimeoutPolicy.foo.com"},
+				{Resource: fooGateway, Kind: "TimeoutPolicy.foo.com"},
+				{Resource: fooHTTPRoute, Gateway: fooGateway, Kind: "RetryPolicy.foo.com"},
+				{Resource: fooHTTPRoute, Gateway: fooGateway, Kind: "TimeoutPolicy.foo.com"},
+				{Resource: fooSvc, Gateway: fooGateway, Kind: "RetryPolicy.foo.com"},
+				{Resource: fooSvc, Gateway: fooGateway, Kind: "TimeoutPolicy.foo.com"},
+			},
+		},
+		{
+			name:  "filtered by kind",
+			query: resourceModel.EffectivePolicies().ForKind("RetryPolicy.foo.com").Collect,
+			want: []row{
+				{Resource: fooHTTPRoute, Gateway: fooGateway, Kind: "RetryPolicy.foo.com"},
+				{Resource: fooSvc, Gateway: fooGateway, Kind: "RetryPolicy.foo.com"},
+			},
+		},
+		{
+			name:  "filtered by unknown kind",
+			query: resourceModel.EffectivePolicies().ForKind("UnknownPolicy.foo.com").Collect,
+		},
+		{
+			name:  "scoped to Gateways",
+			query: resourceModel.EffectivePolicies().OnGateways().Collect,
+			want: []row{
+				{Resource: barGateway, Kind: "TimeoutPolicy.foo.com"},
+				{Resource: fooGateway, Kind: "TimeoutPolicy.foo.com"},
+			},
+		},
+		{
+			name:  "scoped to HTTPRoutes and filtered by kind",
+			query: resourceModel.EffectivePolicies().OnHTTPRoutes().ForKind("TimeoutPolicy.foo.com").Collect,
+			want: []row{
+				{Resource: fooHTTPRoute, Gateway: fooGateway, Kind: "TimeoutPolicy.foo.com"},
+			},
+		},
+		{
+			name:  "scoped to Backends",
+			query: resourceModel.EffectivePolicies().ForKind("RetryPolicy.foo.com").OnBackends().Collect,
+			want: []row{
+				{Resource: fooSvc, Gateway: fooGateway, Kind: "RetryPolicy.foo.com"},
+			},
+		},
+		{
+			name: "filtered by predicate",
+			query: resourceModel.EffectivePolicies().ForKind("TimeoutPolicy.foo.com").OnGateways().Where(func(gatewayNode *GatewayNode) bool {
+				return gatewayNode.Gateway.GetName() == "foo-gateway"
+			}).Collect,
+			want: []row{
+				{Resource: fooGateway, Kind: "TimeoutPolicy.foo.com"},
+			},
+		},
+		{
+			name: "filtered by all predicates",
+			query: resourceModel.EffectivePolicies().OnGateways().Where(func(gatewayNode *GatewayNode) bool {
+				return gatewayNode.Gateway.GetName() == "foo-gateway"
+			}).Where(func(gatewayNode *GatewayNode) bool {
+				return gatewayNode.Gateway.GetName() == "bar-gateway"
+			}).Collect,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []row
+			for _, effectivePolicy := range tc.query() {
+				got = append(got, row{Resource: effectivePolicy.Resource, Gateway: effectivePolicy.Gateway, Kind: effectivePolicy.Policy.PolicyCrdID()})
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff in query results; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,481 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
+)
+
+// RequestTrace records how a request is routed through the ResourceModel, as
+// determined by TraceRequest.
+type RequestTrace struct {
+	// Host, Port, Path, Method, Headers and QueryParams describe the request.
+	// Port is 0 if the request does not specify one.
+	Host        string
+	Port        gatewayv1.PortNumber
+	Path        string
+	Method      string
+	Headers     http.Header
+	QueryParams url.Values
+
+	// Gateway is the Gateway whose listener accepts the request.
+	Gateway *GatewayNode
+	// Listener is the listener of the Gateway which accepts the request.
+	Listener gatewayv1.SectionName
+	// HTTPRoute is the HTTPRoute containing the winning match.
+	HTTPRoute *HTTPRouteNode
+	// Match is the match which takes precedence over all other matches of the
+	// request.
+	Match HTTPRouteMatch
+	// BackendRef references the backend selected within the rule of the
+	// winning match. It's nil if the rule has no backends to forward to.
+	BackendRef *common.ObjRef
+	// Backend is the node of the selected backend. It's nil if the backend is
+	// not part of the ResourceModel.
+	Backend *BackendNode
+	// EffectivePolicies are the policies applied to the request.
+	EffectivePolicies map[policymanager.PolicyCrdID]policymanager.Policy
+	// Steps describes each decision made while routing the request.
+	Steps []string
+}
+
+func (t *RequestTrace) stepf(format string, args ...interface{}) {
+	t.Steps = append(t.Steps, fmt.Sprintf(format, args...))
+}
+
+// ResolveRequest simulates how a request for the host, path and method, with
+// the optional headers and query parameters, is routed through the
+// ResourceModel. It returns the Gateway accepting the request, the HTTPRoute
+// with the winning match, the selected backend and the effective policies
+// applied to the request. See TraceRequest for details.
+func (rm *ResourceModel) ResolveRequest(host, path, method string, headers http.Header, queryParams url.Values) (*GatewayNode, *HTTPRouteNode, *BackendNode, map[policymanager.PolicyCrdID]policymanager.Policy, error) {
+	trace, err := rm.TraceRequest(host, path, method, headers, queryParams)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return trace.Gateway, trace.HTTPRoute, trace.Backend, trace.EffectivePolicies, nil
+}
+
+// TraceRequest simulates how a request for the host, path and method, with the
+// optional headers and query parameters, is routed through the ResourceModel,
+// recording each decision. The host may include a port, e.g. "foo.com:8080",
+// in which case only listeners on that port are considered.
+//
+//  1. On each port of each Gateway, the HTTP or HTTPS listener with the most
+//     specific hostname matching the host accepts the request. The listeners
+//     are evaluated in the order of their hostnames' specificity, then in the
+//     order of the Gateways, until one of them routes the request.
+//  2. Among the matches of all HTTPRoutes attached to the listener whose
+//     hostnames match the host, and whose path, method, header and query
+//     parameter criteria match the request (see
+//     HTTPRouteMatch.MatchesRequest), the match with the highest precedence
+//     wins.
+//     Precedence follows the rules of HTTPRouteRule: the most specific
+//     matching hostname, then the most specific match, then the oldest
+//     HTTPRoute, then the HTTPRoute first in alphabetical order, then the
+//     order of rules and matches.
+//  3. The backendRef with the highest weight within the winning rule is
+//     selected.
+//  4. The effective policies of the rule (or the HTTPRoute) for the Gateway are
+//     merged with the policies of the backend and its namespace.
+//
+// When routing fails, the returned RequestTrace holds the decisions made up to
+// that point.
+func (rm *ResourceModel) TraceRequest(host, path, method string, headers http.Header, queryParams url.Values) (*RequestTrace, error) {
+	host, port := splitHostPort(strings.ToLower(host))
+	if path == "" {
+		path = "/"
+	}
+	trace := &RequestTrace{Host: host, Port: port, Path: path, Method: method, Headers: headers, QueryParams: queryParams, Steps: []string{}}
+
+	// Step 1: Select the listeners accepting the request.
+	listeners := rm.listenersForHost(host, port)
+	if len(listeners) == 0 {
+		if port != 0 {
+			return trace, fmt.Errorf("no HTTP or HTTPS listener on port %d of any Gateway accepts host %q", port, host)
+		}
+		return trace, fmt.Errorf("no HTTP or HTTPS listener of any Gateway accepts host %q", host)
+	}
+
+	// Step 2: Select the winning match among the HTTPRoutes attached to the
+	// first listener routing the request.
+	var winner *requestCandidate
+	for _, listener := range listeners {
+		gatewayID := listener.gatewayNode.ID()
+		trace.Gateway, trace.Listener = listener.gatewayNode, listener.name
+		trace.stepf("Listener %v (port %d) of Gateway %v/%v accepts host %q (hostname %q)",
+			listener.name, listener.port, gatewayID.Namespace, gatewayID.Name, host, listener.hostname)
+		if winner = listener.winningMatch(host, path, method, headers, queryParams); winner != nil {
+			break
+		}
+		trace.stepf("No HTTPRoute attached to listener %v of Gateway %v/%v matches %v %v", listener.name, gatewayID.Namespace, gatewayID.Name, method, path)
+	}
+	if winner == nil {
+		// The most specific listener is reported as the one accepting the
+		// request.
+		trace.Gateway, trace.Listener = listeners[0].gatewayNode, listeners[0].name
+		if len(listeners) == 1 {
+			gatewayID := trace.Gateway.ID()
+			return trace, fmt.Errorf("no HTTPRoute attached to listener %v of Gateway %v/%v matches %v %v", trace.Listener, gatewayID.Namespace, gatewayID.Name, method, path)
+		}
+		return trace, fmt.Errorf("no HTTPRoute attached to any of the %d listeners accepting host %q matches %v %v", len(listeners), host, method, path)
+	}
+	gatewayID := trace.Gateway.ID()
+	trace.HTTPRoute, trace.Match = winner.httpRouteNode, winner.match
+	trace.stepf("Match %d of rule %d (%v) of HTTPRoute %v/%v wins for %v %v",
+		winner.match.MatchIndex, winner.match.RuleIndex, winner.match,
+		winner.httpRouteNode.HTTPRoute.GetNamespace(), winner.httpRouteNode.HTTPRoute.GetName(), method, path)
+
+	// Step 3: Select the backend.
+	rule := trace.HTTPRoute.HTTPRoute.Spec.Rules[trace.Match.RuleIndex]
+	trace.BackendRef = selectBackendRef(trace.HTTPRoute.HTTPRoute, rule)
+	if trace.BackendRef == nil {
+		trace.stepf("Rule %d has no backendRef with a non-zero weight", trace.Match.RuleIndex)
+	} else {
+		backendID := BackendID(trace.BackendRef.Group, trace.BackendRef.Kind, trace.BackendRef.Namespace, trace.BackendRef.Name)
+		trace.Backend = rm.Backends[backendID]
+		if trace.Backend == nil {
+			trace.stepf("Rule %d forwards to %v %v/%v, which is not part of the model",
+				trace.Match.RuleIndex, trace.BackendRef.Kind, trace.BackendRef.Namespace, trace.BackendRef.Name)
+		} else {
+			trace.stepf("Rule %d forwards to %v %v/%v",
+				trace.Match.RuleIndex, trace.BackendRef.Kind, trace.BackendRef.Namespace, trace.BackendRef.Name)
+		}
+	}
+
+	// Step 4: Calculate the effective policies.
+	policies, err := rm.requestEffectivePolicies(trace, gatewayID)
+	if err != nil {
+		return trace, err
+	}
+	trace.EffectivePolicies = policies
+	return trace, nil
+}
+
+// splitHostPort splits the port off the host, if the host has one.
+func splitHostPort(host string) (string, gatewayv1.PortNumber) {
+	hostname, rawPort, err := net.SplitHostPort(host)
+	if err != nil {
+		return host, 0
+	}
+	port, err := strconv.ParseInt(rawPort, 10, 32)
+	if err != nil {
+		return host, 0
+	}
+	return hostname, gatewayv1.PortNumber(port)
+}
+
+// requestListener is a listener of a Gateway which accepts a request.
+type requestListener struct {
+	gatewayNode *GatewayNode
+	name        gatewayv1.SectionName
+	port        gatewayv1.PortNumber
+	hostname    string
+}
+
+// requestCandidate is a match of an HTTPRoute which matches a request.
+type requestCandidate struct {
+	httpRouteNode *HTTPRouteNode
+	hostname      string
+	match         HTTPRouteMatch
+}
+
+// listenersForHost returns, for each port of each Gateway, the HTTP or HTTPS
+// listener with the most specific hostname matching the host, sorted by the
+// specificity of their hostnames and then by Gateway. If port is not 0, only
+// listeners on that port are considered.
+func (rm *ResourceModel) listenersForHost(host string, port gatewayv1.PortNumber) []requestListener {
+	var result []requestListener
+	for _, gatewayID := range sortedGatewayIDs(rm.Gateways) {
+		gatewayNode := rm.Gateways[gatewayID]
+		var ports []gatewayv1.PortNumber
+		byPort := make(map[gatewayv1.PortNumber]requestListener)
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if !acceptsHTTPRoutes(listener) || (port != 0 && listener.Port != port) {
+				continue
+			}
+			hostname := ""
+			if listener.Hostname != nil {
+				hostname = string(*listener.Hostname)
+			}
+			if !hostnameMatches(hostname, host) {
+				continue
+			}
+			current, ok := byPort[listener.Port]
+			if !ok {
+				ports = append(ports, listener.Port)
+			}
+			if !ok || compareHostnames(hostname, current.hostname) > 0 {
+				byPort[listener.Port] = requestListener{gatewayNode: gatewayNode, name: listener.Name, port: listener.Port, hostname: hostname}
+			}
+		}
+		for _, p := range ports {
+			result = append(result, byPort[p])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return compareHostnames(result[i].hostname, result[j].hostname) > 0
+	})
+	return result
+}
+
+// winningMatch returns the match with the highest precedence among the
+// matches of the HTTPRoutes attached to the listener which match the request,
+// or nil if none matches.
+func (l requestListener) winningMatch(host, path, method string, headers http.Header, queryParams url.Values) *requestCandidate {
+	gatewayID := l.gatewayNode.ID()
+	var winner *requestCandidate
+	for _, httpRouteNode := range l.gatewayNode.HTTPRoutes {
+		if !attachedToListener(httpRouteNode.HTTPRoute, gatewayID, l.name, l.port) {
+			continue
+		}
+		hostname, ok := matchingRouteHostname(httpRouteNode.HTTPRoute, l.hostname, host)
+		if !ok {
+			continue
+		}
+		for _, match := range HTTPRouteMatches(httpRouteNode.HTTPRoute) {
+			if !match.MatchesRequest(path, method, headers, queryParams) {
+				continue
+			}
+			c := &requestCandidate{httpRouteNode: httpRouteNode, hostname: hostname, match: match}
+			if winner == nil || hasHigherPrecedence(c.httpRouteNode, c.hostname, c.match, winner.httpRouteNode, winner.hostname, winner.match) {
+				winner = c
+			}
+		}
+	}
+	return winner
+}
+
+// requestEffectivePolicies returns the effective policies of the winning rule
+// of the trace through the selected listener, merged with the policies of the
+// selected backend.
+func (rm *ResourceModel) requestEffectivePolicies(trace *RequestTrace, gatewayID gatewayID) (map[policymanager.PolicyCrdID]policymanager.Policy, error) {
+	result := trace.HTTPRoute.EffectivePolicies[gatewayID]
+	listenerPolicies, hasListenerPolicies := trace.HTTPRoute.ListenerEffectivePolicies[gatewayID][trace.Listener]
+	if hasListenerPolicies {
+		result = listenerPolicies
+	}
+	if trace.Match.RuleIndex < len(trace.HTTPRoute.RuleNames) {
+		ruleName := trace.HTTPRoute.RuleNames[trace.Match.RuleIndex]
+		if policies, ok := trace.HTTPRoute.RuleEffectivePolicies[gatewayID][ruleName]; ok && ruleName != "" {
+			if !hasListenerPolicies {
+				result = policies
+			} else {
+				// The rule-scoped policies are merged on top of the result for
+				// the listener instead.
+				var rulePolicies []policymanager.Policy
+				for _, policy := range convertPoliciesMapToSlice(trace.HTTPRoute.Policies) {
+					if policy.SectionName() == ruleName {
+						rulePolicies = append(rulePolicies, policy)
+					}
+				}
+				policiesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(rulePolicies)
+				if err != nil {
+					return nil, err
+				}
+				result, err = rm.mergeRules.MergePoliciesOfDifferentHierarchy(result, policiesByKind)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if trace.Backend == nil {
+		return result, nil
+	}
+	result = filterCrossNamespacePolicies(result, trace.HTTPRoute.HTTPRoute.GetNamespace(), trace.Backend.Backend.GetNamespace())
+
+	var backendPolicies []map[policyID]*PolicyNode
+	if trace.Backend.Namespace != nil {
+		backendPolicies = append(backendPolicies, trace.Backend.Namespace.Policies)
+	}
+	backendPolicies = append(backendPolicies, trace.Backend.Policies)
+	for _, policies := range backendPolicies {
+		policiesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(convertPoliciesMapToSlice(policies))
+		if err != nil {
+			return nil, err
+		}
+		result, err = rm.mergeRules.MergePoliciesOfDifferentHierarchy(result, policiesByKind)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// acceptsHTTPRoutes returns true if HTTPRoutes can attach to the listener, which
+// is the case for HTTP listeners and HTTPS listeners terminating TLS.
+func acceptsHTTPRoutes(listener gatewayv1.Listener) bool {
+	switch listener.Protocol {
+	case gatewayv1.HTTPProtocolType:
+		return true
+	case gatewayv1.HTTPSProtocolType:
+		return listener.TLS == nil || listener.TLS.Mode == nil || *listener.TLS.Mode == gatewayv1.TLSModeTerminate
+	}
+	return false
+}
+
+// attachedToListener returns true if one of the parentRefs of the HTTPRoute
+// references the Gateway without restricting the attachment to some other
+// listener.
+func attachedToListener(httpRoute *gatewayv1.HTTPRoute, gatewayID gatewayID, listener gatewayv1.SectionName, port gatewayv1.PortNumber) bool {
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if !referencesGateway(httpRoute, parentRef, gatewayID) {
+			continue
+		}
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener {
+			continue
+		}
+		if parentRef.Port != nil && *parentRef.Port != port {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// referencesGateway returns true if the parentRef of the HTTPRoute references
+// the Gateway.
+func referencesGateway(httpRoute *gatewayv1.HTTPRoute, parentRef gatewayv1.ParentReference, gatewayID gatewayID) bool {
+	if parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName {
+		return false
+	}
+	if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+		return false
+	}
+	namespace := httpRoute.GetNamespace()
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return GatewayID(namespace, string(parentRef.Name)) == gatewayID
+}
+
+// matchingRouteHostname returns the most specific hostname of the HTTPRoute
+// matching the host. An HTTPRoute without hostnames inherits the hostname of
+// the listener.
+func matchingRouteHostname(httpRoute *gatewayv1.HTTPRoute, listenerHostname, host string) (string, bool) {
+	if len(httpRoute.Spec.Hostnames) == 0 {
+		return listenerHostname, true
+	}
+	var result string
+	var found bool
+	for _, hostname := range httpRoute.Spec.Hostnames {
+		if !hostnameMatches(string(hostname), host) {
+			continue
+		}
+		if !found || compareHostnames(string(hostname), result) > 0 {
+			result, found = string(hostname), true
+		}
+	}
+	return result, found
+}
+
+// hostnameMatches returns true if the hostname of a listener or an HTTPRoute
+// matches the host. An empty hostname matches all hosts, and a wildcard
+// hostname like "*.example.com" matches all hosts with the same suffix, but not
+// "example.com" itself.
+func hostnameMatches(hostname, host string) bool {
+	hostname = strings.ToLower(hostname)
+	switch {
+	case hostname == "":
+		return true
+	case strings.HasPrefix(hostname, "*."):
+		suffix := hostname[1:]
+		return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+	default:
+		return hostname == host
+	}
+}
+
+// compareHostnames compares the specificity of two hostnames matching the same
+// host. Non-wildcard hostnames are more specific than wildcard hostnames, and
+// otherwise longer hostnames are more specific. It returns a positive number if
+// a is more specific, a negative number if b is more specific, and zero on a
+// tie.
+func compareHostnames(a, b string) int {
+	nonWildcardLength := func(hostname string) int {
+		if hostname == "" || strings.HasPrefix(hostname, "*") {
+			return 0
+		}
+		return len(hostname)
+	}
+	if c := nonWildcardLength(a) - nonWildcardLength(b); c != 0 {
+		return c
+	}
+	return len(a) - len(b)
+}
+
+// hasHigherPrecedence returns true if match a of HTTPRoute routeA, which
+// matched the request through hostname hostnameA, takes precedence over match
+// b of HTTPRoute routeB.
+func hasHigherPrecedence(routeA *HTTPRouteNode, hostnameA string, a HTTPRouteMatch, routeB *HTTPRouteNode, hostnameB string, b HTTPRouteMatch) bool {
+	if c := compareHostnames(hostnameA, hostnameB); c != 0 {
+		return c > 0
+	}
+	if c := a.compareSpecificity(b); c != 0 {
+		return c > 0
+	}
+	if routeA != routeB {
+		return httpRouteHasPrecedence(routeA, routeB)
+	}
+	return a.HasHigherPrecedenceThan(b)
+}
+
+// httpRouteHasPrecedence returns true if HTTPRoute a takes precedence over
+// HTTPRoute b for equally specific matches, which is the case if a is older or,
+// when both were created at the same time, comes first in alphabetical order.
+func httpRouteHasPrecedence(a, b *HTTPRouteNode) bool {
+	timeA, timeB := a.HTTPRoute.GetCreationTimestamp(), b.HTTPRoute.GetCreationTimestamp()
+	if !timeA.Equal(&timeB) {
+		return timeA.Before(&timeB)
+	}
+	return a.HTTPRoute.GetNamespace()+"/"+a.HTTPRoute.GetName() < b.HTTPRoute.GetNamespace()+"/"+b.HTTPRoute.GetName()
+}
+
+// selectBackendRef returns the backendRef of the rule with the highest weight,
+// or nil if the rule has no backendRef with a non-zero weight. Ties are broken
+// by the order of the backendRefs.
+func selectBackendRef(httpRoute *gatewayv1.HTTPRoute, rule gatewayv1.HTTPRouteRule) *common.ObjRef {
+	var result *common.ObjRef
+	var resultWeight int32
+	for _, backendRef := range rule.BackendRefs {
+		weight := backendRefWeight(backendRef)
+		if weight <= resultWeight {
+			continue
+		}
+		objRef := httpBackendRefObjRef(httpRoute, backendRef)
+		result, resultWeight = &objRef, weight
+	}
+	return result
+}
+
+// httpBackendRefObjRef returns the backend referenced by the backendRef of the
+// HTTPRoute, with its namespace and kind defaulted.
+func httpBackendRefObjRef(httpRoute *gatewayv1.HTTPRoute, backendRef gatewayv1.HTTPBackendRef) common.ObjRef {
+	return relations.BackendObjRef(httpRoute.GetNamespace(), backendRef.BackendObjectReference)
+}
@@ -0,0 +1,565 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_TraceRequest(t *testing.T) {
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+	rule := func(pathType gatewayv1.PathMatchType, path string, method *gatewayv1.HTTPMethod, backend string) gatewayv1.HTTPRouteRule {
+		return gatewayv1.HTTPRouteRule{
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path:   &gatewayv1.HTTPPathMatch{Type: common.PtrTo(pathType), Value: common.PtrTo(path)},
+				Method: method,
+			}},
+			BackendRefs: []gatewayv1.HTTPBackendRef{{
+				BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{
+						Kind: common.PtrTo(gatewayv1.Kind("Service")),
+						Name: gatewayv1.ObjectName(backend),
+						Port: common.PtrTo(gatewayv1.PortNumber(80)),
+					},
+				},
+			}},
+		}
+	}
+	httpRoute := func(name string, created time.Time, hostnames []gatewayv1.Hostname, rules ...gatewayv1.HTTPRouteRule) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Hostnames: hostnames,
+				Rules:     rules,
+			},
+		}
+	}
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "wildcard", Protocol: gatewayv1.HTTPProtocolType, Port: 80, Hostname: common.PtrTo(gatewayv1.Hostname("*.foo.com"))},
+					{Name: "api", Protocol: gatewayv1.HTTPProtocolType, Port: 80, Hostname: common.PtrTo(gatewayv1.Hostname("api.foo.com"))},
+				},
+			},
+		},
+		// users-httproute is older than v1-httproute, so it wins ties between
+		// equally specific matches.
+		httpRoute("users-httproute", older, []gatewayv1.Hostname{"api.foo.com"},
+			rule(gatewayv1.PathMatchPathPrefix, "/v1", nil, "v1-svc"),
+			rule(gatewayv1.PathMatchExact, "/v1/users", nil, "users-svc"),
+		),
+		httpRoute("v1-httproute", newer, nil,
+			rule(gatewayv1.PathMatchPathPrefix, "/v1", nil, "other-v1-svc"),
+		),
+		httpRoute("get-users-httproute", newer, nil,
+			rule(gatewayv1.PathMatchPathPrefix, "/v1/users", common.PtrTo(gatewayv1.HTTPMethodGet), "get-users-svc"),
+		),
+		service("v1-svc"),
+		service("users-svc"),
+		service("other-v1-svc"),
+		service("get-users-svc"),
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-users",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": int64(30),
+					},
+					"targetRef": map[string]interface{}{
+						"group":     "gateway.networking.k8s.io",
+						"kind":      "HTTPRoute",
+						"name":      "users-httproute",
+						"namespace": "default",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	testcases := []struct {
+		name        string
+		host        string
+		path        string
+		method      string
+		headers     http.Header
+		queryParams url.Values
+
+		wantListener  gatewayv1.SectionName
+		wantHTTPRoute string
+		wantRuleIndex int
+		wantBackend   string
+		wantPolicies  []string
+		wantErr       bool
+	}{
+		{
+			name:          "exact match wins over longer prefix match",
+			host:          "api.foo.com",
+			path:          "/v1/users",
+			method:        "GET",
+			wantListener:  "api",
+			wantHTTPRoute: "users-httproute",
+			wantRuleIndex: 1,
+			wantBackend:   "users-svc",
+			wantPolicies:  []string{"HealthCheckPolicy.foo.com"},
+		},
+		{
+			name:          "longest prefix match wins",
+			host:          "api.foo.com",
+			path:          "/v1/users/1",
+			method:        "GET",
+			wantListener:  "api",
+			wantHTTPRoute: "get-users-httproute",
+			wantRuleIndex: 0,
+			wantBackend:   "get-users-svc",
+		},
+		{
+			name:          "oldest HTTPRoute wins between equal matches",
+			host:          "api.foo.com",
+			path:          "/v1/users/1",
+			method:        "POST",
+			wantListener:  "api",
+			wantHTTPRoute: "users-httproute",
+			wantRuleIndex: 0,
+			wantBackend:   "v1-svc",
+			wantPolicies:  []string{"HealthCheckPolicy.foo.com"},
+		},
+		{
+			name:          "wildcard listener for other hosts",
+			host:          "www.foo.com",
+			path:          "/v1/orders",
+			method:        "GET",
+			wantListener:  "wildcard",
+			wantHTTPRoute: "v1-httproute",
+			wantRuleIndex: 0,
+			wantBackend:   "other-v1-svc",
+		},
+		{
+			name:    "no matching listener",
+			host:    "example.com",
+			path:    "/v1",
+			method:  "GET",
+			wantErr: true,
+		},
+		{
+			name:         "no matching HTTPRoute",
+			host:         "www.foo.com",
+			path:         "/v2",
+			method:       "GET",
+			wantListener: "wildcard",
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			trace, err := resourceModel.TraceRequest(tc.host, tc.path, tc.method, tc.headers, tc.queryParams)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("TraceRequest() err=%v, wantErr=%v; trace=%v", err, tc.wantErr, trace.Steps)
+			}
+			if trace.Listener != tc.wantListener {
+				t.Errorf("Unexpected listener; got=%q, want=%q", trace.Listener, tc.wantListener)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if got := trace.HTTPRoute.HTTPRoute.GetName(); got != tc.wantHTTPRoute {
+				t.Errorf("Unexpected HTTPRoute; got=%q, want=%q; trace=%v", got, tc.wantHTTPRoute, trace.Steps)
+			}
+			if trace.Match.RuleIndex != tc.wantRuleIndex {
+				t.Errorf("Unexpected rule; got=%d, want=%d", trace.Match.RuleIndex, tc.wantRuleIndex)
+			}
+			if trace.Backend == nil {
+				t.Fatalf("Backend %q is missing from trace; trace=%v", tc.wantBackend, trace.Steps)
+			}
+			if got := trace.Backend.Backend.GetName(); got != tc.wantBackend {
+				t.Errorf("Unexpected Backend; got=%q, want=%q", got, tc.wantBackend)
+			}
+			var gotPolicies []string
+			for policyCrdID := range trace.EffectivePolicies {
+				gotPolicies = append(gotPolicies, string(policyCrdID))
+			}
+			sort.Strings(gotPolicies)
+			if diff := cmp.Diff(tc.wantPolicies, gotPolicies); diff != "" {
+				t.Errorf("Unexpected diff in EffectivePolicies; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotPolicies, tc.wantPolicies, diff)
+			}
+
+			gateway, httpRoute, backend, policies, err := resourceModel.ResolveRequest(tc.host, tc.path, tc.method, tc.headers, tc.queryParams)
+			if err != nil || gateway != trace.Gateway || httpRoute != trace.HTTPRoute || backend != trace.Backend || len(policies) != len(trace.EffectivePolicies) {
+				t.Errorf("ResolveRequest() is inconsistent with TraceRequest(); err=%v", err)
+			}
+		})
+	}
+}
+
+// TestResourceModel_TraceRequest_HeaderAndQueryParamMatches tests that the
+// headers and query parameters of a request decide between HTTPRoutes which
+// are otherwise equal.
+func TestResourceModel_TraceRequest_HeaderAndQueryParamMatches(t *testing.T) {
+	httpRoute := func(name string, match gatewayv1.HTTPRouteMatch, backend string) *gatewayv1.HTTPRoute {
+		match.Path = &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/app")}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{match},
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: gatewayv1.ObjectName(backend),
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+	headerMatch := func(matchType gatewayv1.HeaderMatchType, value string) gatewayv1.HTTPRouteMatch {
+		return gatewayv1.HTTPRouteMatch{
+			Headers: []gatewayv1.HTTPHeaderMatch{{Type: common.PtrTo(matchType), Name: "X-Track", Value: value}},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80}},
+			},
+		},
+		// Both HTTPRoutes match the same path with one header each, so only the
+		// value of the header decides between them.
+		httpRoute("stable-httproute", headerMatch(gatewayv1.HeaderMatchExact, "stable"), "stable-svc"),
+		httpRoute("canary-httproute", headerMatch(gatewayv1.HeaderMatchRegularExpression, "canary-[0-9]+"), "canary-svc"),
+		httpRoute("beta-httproute", gatewayv1.HTTPRouteMatch{
+			QueryParams: []gatewayv1.HTTPQueryParamMatch{{Name: "version", Value: "beta"}},
+		}, "beta-svc"),
+		httpRoute("default-httproute", gatewayv1.HTTPRouteMatch{}, "default-svc"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	testcases := []struct {
+		name        string
+		headers     http.Header
+		queryParams url.Values
+
+		wantHTTPRoute string
+	}{
+		{
+			name:          "exact header match",
+			headers:       http.Header{"X-Track": {"stable"}},
+			wantHTTPRoute: "stable-httproute",
+		},
+		{
+			name:          "regular expression header match",
+			headers:       http.Header{"X-Track": {"canary-2"}},
+			wantHTTPRoute: "canary-httproute",
+		},
+		{
+			name: "header names are case-insensitive",
+			headers: func() http.Header {
+				headers := make(http.Header)
+				headers.Add("x-track", "stable")
+				return headers
+			}(),
+			wantHTTPRoute: "stable-httproute",
+		},
+		{
+			name:          "unmatched header value",
+			headers:       http.Header{"X-Track": {"canary"}},
+			wantHTTPRoute: "default-httproute",
+		},
+		{
+			name:          "query parameter match",
+			queryParams:   url.Values{"version": {"beta"}},
+			wantHTTPRoute: "beta-httproute",
+		},
+		{
+			name:          "query parameter names are case-sensitive",
+			queryParams:   url.Values{"Version": {"beta"}},
+			wantHTTPRoute: "default-httproute",
+		},
+		{
+			name:          "no headers or query parameters",
+			wantHTTPRoute: "default-httproute",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, httpRoute, _, _, err := resourceModel.ResolveRequest("foo.com", "/app/items", "GET", tc.headers, tc.queryParams)
+			if err != nil {
+				t.Fatalf("ResolveRequest() failed: %v", err)
+			}
+			if got := httpRoute.HTTPRoute.GetName(); got != tc.wantHTTPRoute {
+				t.Errorf("Unexpected HTTPRoute; got=%q, want=%q", got, tc.wantHTTPRoute)
+			}
+		})
+	}
+}
+
+// TestResourceModel_TraceRequest_Listeners tests that requests are routed by
+// the first listener, across all Gateways and ports, with an HTTPRoute
+// matching the request, and that the port of the host restricts the
+// listeners.
+func TestResourceModel_TraceRequest_Listeners(t *testing.T) {
+	gateway := func(name string, listeners ...gatewayv1.Listener) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners:        listeners,
+			},
+		}
+	}
+	listener := func(name string, port gatewayv1.PortNumber, hostname *gatewayv1.Hostname) gatewayv1.Listener {
+		return gatewayv1.Listener{Name: gatewayv1.SectionName(name), Protocol: gatewayv1.HTTPProtocolType, Port: port, Hostname: hostname}
+	}
+	httpRoute := func(name, gateway string, sectionName *gatewayv1.SectionName, path string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway), SectionName: sectionName}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Path: &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo(path)},
+					}},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		gateway("foo-gateway",
+			listener("http", 80, common.PtrTo(gatewayv1.Hostname("foo.com"))),
+			listener("alt", 8080, common.PtrTo(gatewayv1.Hostname("foo.com"))),
+		),
+		gateway("bar-gateway", listener("http", 80, nil)),
+		httpRoute("a-httproute", "foo-gateway", common.PtrTo(gatewayv1.SectionName("http")), "/a"),
+		httpRoute("alt-httproute", "foo-gateway", common.PtrTo(gatewayv1.SectionName("alt")), "/alt"),
+		httpRoute("b-httproute", "bar-gateway", nil, "/b"),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	testcases := []struct {
+		name          string
+		host          string
+		path          string
+		wantGateway   string
+		wantListener  gatewayv1.SectionName
+		wantHTTPRoute string
+		wantErr       bool
+	}{
+		{
+			name:          "most specific listener",
+			host:          "foo.com",
+			path:          "/a",
+			wantGateway:   "foo-gateway",
+			wantListener:  "http",
+			wantHTTPRoute: "a-httproute",
+		},
+		{
+			name:          "less specific listener of another Gateway",
+			host:          "foo.com",
+			path:          "/b",
+			wantGateway:   "bar-gateway",
+			wantListener:  "http",
+			wantHTTPRoute: "b-httproute",
+		},
+		{
+			name:          "listener on another port",
+			host:          "foo.com",
+			path:          "/alt",
+			wantGateway:   "foo-gateway",
+			wantListener:  "alt",
+			wantHTTPRoute: "alt-httproute",
+		},
+		{
+			name:          "port of the host",
+			host:          "foo.com:8080",
+			path:          "/alt",
+			wantGateway:   "foo-gateway",
+			wantListener:  "alt",
+			wantHTTPRoute: "alt-httproute",
+		},
+		{
+			name:    "port of the host excludes other listeners",
+			host:    "foo.com:80",
+			path:    "/alt",
+			wantErr: true,
+		},
+		{
+			name:    "no listener on the port of the host",
+			host:    "foo.com:9090",
+			path:    "/a",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			trace, err := resourceModel.TraceRequest(tc.host, tc.path, "GET", nil, nil)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("TraceRequest() err=%v, wantErr=%v; trace=%v", err, tc.wantErr, trace.Steps)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := trace.Gateway.Gateway.GetName(); got != tc.wantGateway {
+				t.Errorf("Unexpected Gateway; got=%q, want=%q; trace=%v", got, tc.wantGateway, trace.Steps)
+			}
+			if trace.Listener != tc.wantListener {
+				t.Errorf("Unexpected listener; got=%q, want=%q; trace=%v", trace.Listener, tc.wantListener, trace.Steps)
+			}
+			if got := trace.HTTPRoute.HTTPRoute.GetName(); got != tc.wantHTTPRoute {
+				t.Errorf("Unexpected HTTPRoute; got=%q, want=%q; trace=%v", got, tc.wantHTTPRoute, trace.Steps)
+			}
+		})
+	}
+}
@@ -17,14 +17,19 @@ limitations under the License.
 package resourcediscovery
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
 	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/klog/v2"
 )
@@ -46,6 +51,47 @@ type ResourceModel struct {
 	Backends        map[backendID]*BackendNode
 	ReferenceGrants map[referenceGrantID]*ReferenceGrantNode
 	Policies        map[policyID]*PolicyNode
+	ExtensionRefs   map[extensionRefID]*ExtensionRefNode
+	Secrets         map[secretID]*SecretNode
+
+	// IgnoredNamespaces lists the namespaces which were ignored while
+	// discovering the ResourceModel. Nodes within these namespaces may still be
+	// part of the ResourceModel when referenced by other resources, but should
+	// not be reported on.
+	IgnoredNamespaces NamespaceIgnoreList
+	// NamespaceLabels indexes the labels of all Namespaces in the cluster, not
+	// only of those which are part of the ResourceModel. It is nil if
+	// Namespaces were not discovered.
+	NamespaceLabels NamespaceLabelIndex
+	// SkippedKinds maps the kinds of resources which could not be fetched due
+	// to missing permissions to the error returned when fetching them. These
+	// kinds are only skipped if Discoverer.SkipForbidden is set, and the
+	// ResourceModel lacks any resources of them.
+	SkippedKinds map[string]error
+	// DeletingPolicies lists the policies which are being deleted and were
+	// therefore not added to the ResourceModel. Policies are only skipped if
+	// Discoverer.SkipDeletingPolicies is set.
+	DeletingPolicies []policymanager.Policy
+
+	// hypothetical holds the NodeIDs of nodes inserted through AddHypothetical.
+	hypothetical map[string]bool
+	// dirty holds the NodeIDs of the nodes whose effective policies have to be
+	// recalculated after a policy was updated (see UpdatePolicy). If it is nil,
+	// the effective policies of all nodes are calculated.
+	dirty map[string]bool
+	// mergeRules are the behavior rules enabled while calculating effective
+	// policies.
+	mergeRules policymanager.MergeRules
+	// requireParentReferenceGrants is true if HTTPRoutes may only attach to
+	// Gateways in other namespaces when permitted by a ReferenceGrant.
+	requireParentReferenceGrants bool
+	// skipDeletingPolicies is true if policies which are being deleted are
+	// recorded in DeletingPolicies instead of being added.
+	skipDeletingPolicies bool
+	// auditEnabled is true if decisions taken while building the ResourceModel
+	// are recorded in auditLog.
+	auditEnabled bool
+	auditLog     []AuditEvent
 }
 
 // addGatewayClasses adds nodes for GatewayClases.
@@ -58,6 +104,7 @@ func (rm *ResourceModel) addGatewayClasses(gatewayClasses ...gatewayv1.GatewayCl
 		gatewayClassNode := NewGatewayClassNode(&gatewayClass)
 		if _, ok := rm.GatewayClasses[gatewayClassNode.ID()]; !ok {
 			rm.GatewayClasses[gatewayClassNode.ID()] = gatewayClassNode
+			rm.audit(AuditActionAdd, gatewayClassNode.ID(), nil, "added")
 		}
 	}
 }
@@ -71,34 +118,41 @@ func (rm *ResourceModel) addNamespace(namespaces ...corev1.Namespace) {
 		namespaceNode := NewNamespaceNode(namespace)
 		if _, ok := rm.Namespaces[namespaceNode.ID()]; !ok {
 			rm.Namespaces[namespaceNode.ID()] = namespaceNode
+			rm.audit(AuditActionAdd, namespaceNode.ID(), nil, "added")
 		}
 	}
 }
 
 // addGateways adds nodes for Gateways.
-func (rm *ResourceModel) addGateways(gateways ...gatewayv1.Gateway) {
+func (rm *ResourceModel) addGateways(gateways ...fetchedGateway) {
 	if rm.Gateways == nil {
 		rm.Gateways = make(map[gatewayID]*GatewayNode)
 	}
 	for _, gateway := range gateways {
 		gateway := gateway
-		gatewayNode := NewGatewayNode(&gateway)
+		gatewayNode := NewGatewayNode(&gateway.Gateway)
+		gatewayNode.DefaultBackendRefs = gateway.defaultBackendRefs
 		if _, ok := rm.Gateways[gatewayNode.ID()]; !ok {
 			rm.Gateways[gatewayNode.ID()] = gatewayNode
+			rm.audit(AuditActionAdd, gatewayNode.ID(), nil, "added")
 		}
 	}
 }
 
 // addHTTPRoutes adds nodes for HTTPRoutes.
-func (rm *ResourceModel) addHTTPRoutes(httpRoutes ...gatewayv1.HTTPRoute) {
+func (rm *ResourceModel) addHTTPRoutes(httpRoutes ...fetchedHTTPRoute) {
 	if rm.HTTPRoutes == nil {
 		rm.HTTPRoutes = make(map[httpRouteID]*HTTPRouteNode)
 	}
 	for _, httpRoute := range httpRoutes {
 		httpRoute := httpRoute
-		httpRouteNode := NewHTTPRouteNode(&httpRoute)
+		httpRouteNode := NewHTTPRouteNode(&httpRoute.HTTPRoute)
+		httpRouteNode.Filters = httpRoute.filters
+		httpRouteNode.RuleNames = httpRoute.ruleNames
+		httpRouteNode.NamedBackendPorts = httpRoute.namedBackendPorts
 		if _, ok := rm.HTTPRoutes[httpRouteNode.ID()]; !ok {
 			rm.HTTPRoutes[httpRouteNode.ID()] = httpRouteNode
+			rm.audit(AuditActionAdd, httpRouteNode.ID(), nil, "added")
 		}
 	}
 }
@@ -113,6 +167,7 @@ func (rm *ResourceModel) addBackends(backends ...unstructured.Unstructured) {
 		backendNode := NewBackendNode(&backend)
 		if _, ok := rm.Backends[backendNode.ID()]; !ok {
 			rm.Backends[backendNode.ID()] = backendNode
+			rm.audit(AuditActionAdd, backendNode.ID(), nil, "added")
 		}
 	}
 }
@@ -127,13 +182,74 @@ func (rm *ResourceModel) addReferenceGrants(referenceGrants ...gatewayv1beta1.Re
 		referenceGrantNode := NewReferenceGrantNode(&referenceGrant)
 		if _, ok := rm.ReferenceGrants[referenceGrantNode.ID()]; !ok {
 			rm.ReferenceGrants[referenceGrantNode.ID()] = referenceGrantNode
+			rm.audit(AuditActionAdd, referenceGrantNode.ID(), nil, "added")
 		}
 	}
 }
 
+// addExtensionRefs adds nodes for objects referenced by ExtensionRef filters.
+func (rm *ResourceModel) addExtensionRefs(objects ...unstructured.Unstructured) {
+	if rm.ExtensionRefs == nil {
+		rm.ExtensionRefs = make(map[extensionRefID]*ExtensionRefNode)
+	}
+	for _, object := range objects {
+		object := object
+		extensionRefNode := NewExtensionRefNode(&object)
+		if _, ok := rm.ExtensionRefs[extensionRefNode.ID()]; !ok {
+			rm.ExtensionRefs[extensionRefNode.ID()] = extensionRefNode
+			rm.audit(AuditActionAdd, extensionRefNode.ID(), nil, "added")
+		}
+	}
+}
+
+// addSecrets adds nodes for Secrets referenced by HTTPRoutes. Only the
+// identifying metadata of the Secrets is kept: their data is dropped, as are
+// their annotations and managedFields, which may hold a copy of the data (e.g.
+// the kubectl.kubernetes.io/last-applied-configuration annotation).
+func (rm *ResourceModel) addSecrets(secrets ...corev1.Secret) {
+	if rm.Secrets == nil {
+		rm.Secrets = make(map[secretID]*SecretNode)
+	}
+	for _, secret := range secrets {
+		secret := corev1.Secret{
+			TypeMeta: secret.TypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              secret.GetName(),
+				Namespace:         secret.GetNamespace(),
+				UID:               secret.GetUID(),
+				ResourceVersion:   secret.GetResourceVersion(),
+				CreationTimestamp: secret.GetCreationTimestamp(),
+				Labels:            secret.GetLabels(),
+				OwnerReferences:   secret.GetOwnerReferences(),
+			},
+			Type: secret.Type,
+		}
+		secretNode := NewSecretNode(&secret)
+		if _, ok := rm.Secrets[secretNode.ID()]; !ok {
+			rm.Secrets[secretNode.ID()] = secretNode
+			rm.audit(AuditActionAdd, secretNode.ID(), nil, "added")
+		}
+	}
+}
+
+// addMissingSecret adds a placeholder node for a Secret referenced by an
+// HTTPRoute which does not exist.
+func (rm *ResourceModel) addMissingSecret(namespace, name string) {
+	if rm.Secrets == nil {
+		rm.Secrets = make(map[secretID]*SecretNode)
+	}
+	secretNode := NewSecretNode(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+	secretNode.Missing = true
+	if _, ok := rm.Secrets[secretNode.ID()]; !ok {
+		rm.Secrets[secretNode.ID()] = secretNode
+		rm.audit(AuditActionAdd, secretNode.ID(), nil, "added missing Secret")
+	}
+}
+
 // addPolicyIfTargetExists adds a node for Policy only if the target for the
 // Policy exists in the ResourceModel. In addition to adding the Node, it also
-// makes the connections with the targetRefs.
+// makes the connections with the targetRefs. Policies which are being deleted
+// are recorded in DeletingPolicies instead if rm.skipDeletingPolicies is set.
 func (rm *ResourceModel) addPolicyIfTargetExists(policies ...policymanager.Policy) {
 	if rm.Policies == nil {
 		rm.Policies = make(map[policyID]*PolicyNode)
@@ -141,6 +257,17 @@ func (rm *ResourceModel) addPolicyIfTargetExists(policies ...policymanager.Polic
 	for _, policy := range policies {
 		policy := policy
 		policyNode := NewPolicyNode(&policy)
+		if rm.skipDeletingPolicies && policy.Unstructured().GetDeletionTimestamp() != nil {
+			klog.V(1).InfoS("Skipping policy since it is being deleted", "policy", policy.Name())
+			rm.audit(AuditActionSkip, policyNode.ID(), nil, "policy skipped: being deleted")
+			rm.DeletingPolicies = append(rm.DeletingPolicies, policy)
+			continue
+		}
+
+		if policy.TargetSelector() != nil {
+			rm.addPolicyIfSelectedTargetsExist(policyNode)
+			continue
+		}
 
 		switch {
 		case policy.TargetRef().Group == gatewayv1.GroupName:
@@ -150,33 +277,39 @@ func (rm *ResourceModel) addPolicyIfTargetExists(policies ...policymanager.Polic
 				gatewayClassNode, ok := rm.GatewayClasses[gwcID]
 				if !ok {
 					klog.V(1).ErrorS(nil, "Skipping policy since targetRef GatewayClass does not exist in ResourceModel", "policy", policy.Name(), "gatewayClassID", gwcID)
+					rm.audit(AuditActionSkip, policyNode.ID(), gwcID, "policy skipped: target not found")
 					continue
 				}
 				rm.Policies[policyNode.ID()] = policyNode
 				policyNode.GatewayClass = gatewayClassNode
 				gatewayClassNode.Policies[policyNode.ID()] = policyNode
+				rm.audit(AuditActionConnect, policyNode.ID(), gwcID, "policy attached")
 
 			case "Gateway":
-				gwID := GatewayID(policy.TargetRef().Namespace, policy.TargetRef().Name)
+				gwID := GatewayID(policyTargetNamespace(policy), policy.TargetRef().Name)
 				gatewayNode, ok := rm.Gateways[gwID]
 				if !ok {
 					klog.V(1).ErrorS(nil, "Skipping policy since targetRef Gateway does not exist in ResourceModel", "policy", policy.Name(), "gatewayID", gwID)
+					rm.audit(AuditActionSkip, policyNode.ID(), gwID, "policy skipped: target not found")
 					continue
 				}
 				rm.Policies[policyNode.ID()] = policyNode
 				policyNode.Gateway = gatewayNode
 				gatewayNode.Policies[policyNode.ID()] = policyNode
+				rm.audit(AuditActionConnect, policyNode.ID(), gwID, "policy attached")
 
 			case "HTTPRoute":
-				hrID := HTTPRouteID(policy.TargetRef().Namespace, policy.TargetRef().Name)
+				hrID := HTTPRouteID(policyTargetNamespace(policy), policy.TargetRef().Name)
 				httpRouteNode, ok := rm.HTTPRoutes[hrID]
 				if !ok {
 					klog.V(1).ErrorS(nil, "Skipping policy since targetRef HTTPRoute does not exist in ResourceModel", "policy", policy.Name(), "httpRouteID", hrID)
+					rm.audit(AuditActionSkip, policyNode.ID(), hrID, "policy skipped: target not found")
 					continue
 				}
 				rm.Policies[policyNode.ID()] = policyNode
 				policyNode.HTTPRoute = httpRouteNode
 				httpRouteNode.Policies[policyNode.ID()] = policyNode
+				rm.audit(AuditActionConnect, policyNode.ID(), hrID, "policy attached")
 			}
 
 		case policy.TargetRef().Group == corev1.GroupName && policy.TargetRef().Kind == "Namespace":
@@ -184,24 +317,102 @@ func (rm *ResourceModel) addPolicyIfTargetExists(policies ...policymanager.Polic
 			namespaceNode, ok := rm.Namespaces[nsID]
 			if !ok {
 				klog.V(1).ErrorS(nil, "Skipping policy since targetRef Namespace does not exist in ResourceModel", "policy", policy.Name(), "namespaceID", nsID)
+				rm.audit(AuditActionSkip, policyNode.ID(), nsID, "policy skipped: target not found")
 				continue
 			}
 			rm.Policies[policyNode.ID()] = policyNode
 			policyNode.Namespace = namespaceNode
 			namespaceNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), nsID, "policy attached")
 
 		default: // Assume attached to backend and evaluate further.
-			bID := BackendID(policy.TargetRef().Group, policy.TargetRef().Kind, policy.TargetRef().Namespace, policy.TargetRef().Name)
+			bID := BackendID(policy.TargetRef().Group, policy.TargetRef().Kind, policyTargetNamespace(policy), policy.TargetRef().Name)
 			backendNode, ok := rm.Backends[bID]
 			if !ok {
 				klog.V(1).ErrorS(nil, "Skipping policy since targetRef Backend does not exist in ResourceModel", "policy", policy.Name(), "backendID", bID)
+				rm.audit(AuditActionSkip, policyNode.ID(), bID, "policy skipped: target not found")
 				continue
 			}
 			rm.Policies[policyNode.ID()] = policyNode
 			policyNode.Backend = backendNode
 			backendNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), bID, "policy attached")
+		}
+	}
+}
+
+// addPolicyIfSelectedTargetsExist attaches a policy with a targetSelector to
+// every node in the ResourceModel matching the selector. For namespaced kinds,
+// only nodes within the namespace of the policy are considered.
+func (rm *ResourceModel) addPolicyIfSelectedTargetsExist(policyNode *PolicyNode) {
+	selector := policyNode.Policy.TargetSelector()
+	policyNamespace := policyNode.Policy.Unstructured().GetNamespace()
+	inPolicyNamespace := func(namespace string) bool {
+		return namespaceOrDefault(namespace) == namespaceOrDefault(policyNamespace)
+	}
+
+	for gatewayClassID, gatewayClassNode := range rm.GatewayClasses {
+		if selector.Matches(gatewayv1.GroupName, "GatewayClass", gatewayClassNode.GatewayClass.GetLabels()) {
+			policyNode.SelectedGatewayClasses[gatewayClassID] = gatewayClassNode
+			gatewayClassNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), gatewayClassID, "policy attached through targetSelector")
+		}
+	}
+	for namespaceID, namespaceNode := range rm.Namespaces {
+		if selector.Matches(corev1.GroupName, "Namespace", namespaceNode.Labels) {
+			policyNode.SelectedNamespaces[namespaceID] = namespaceNode
+			namespaceNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), namespaceID, "policy attached through targetSelector")
+		}
+	}
+	for gatewayID, gatewayNode := range rm.Gateways {
+		if inPolicyNamespace(gatewayNode.Gateway.GetNamespace()) && selector.Matches(gatewayv1.GroupName, "Gateway", gatewayNode.Gateway.GetLabels()) {
+			policyNode.SelectedGateways[gatewayID] = gatewayNode
+			gatewayNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), gatewayID, "policy attached through targetSelector")
+		}
+	}
+	for httpRouteID, httpRouteNode := range rm.HTTPRoutes {
+		if inPolicyNamespace(httpRouteNode.HTTPRoute.GetNamespace()) && selector.Matches(gatewayv1.GroupName, "HTTPRoute", httpRouteNode.HTTPRoute.GetLabels()) {
+			policyNode.SelectedHTTPRoutes[httpRouteID] = httpRouteNode
+			httpRouteNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), httpRouteID, "policy attached through targetSelector")
+		}
+	}
+	for backendID, backendNode := range rm.Backends {
+		backend := backendNode.Backend
+		if inPolicyNamespace(backend.GetNamespace()) && selector.Matches(backend.GroupVersionKind().Group, backend.GetKind(), backend.GetLabels()) {
+			policyNode.SelectedBackends[backendID] = backendNode
+			backendNode.Policies[policyNode.ID()] = policyNode
+			rm.audit(AuditActionConnect, policyNode.ID(), backendID, "policy attached through targetSelector")
 		}
 	}
+
+	selectedCount := len(policyNode.SelectedGatewayClasses) + len(policyNode.SelectedNamespaces) +
+		len(policyNode.SelectedGateways) + len(policyNode.SelectedHTTPRoutes) + len(policyNode.SelectedBackends)
+	if selectedCount == 0 {
+		klog.V(1).ErrorS(nil, "Skipping policy since targetSelector does not match any resource in ResourceModel", "policy", policyNode.Policy.Name())
+		rm.audit(AuditActionSkip, policyNode.ID(), nil, "policy skipped: targetSelector matches no resource")
+		return
+	}
+	rm.Policies[policyNode.ID()] = policyNode
+}
+
+// policyTargetNamespace returns the namespace of the resource targeted by the
+// targetRef of a policy. A targetRef without a namespace references a resource
+// in the namespace of the policy.
+func policyTargetNamespace(policy policymanager.Policy) string {
+	if namespace := policy.TargetRef().Namespace; namespace != "" {
+		return namespace
+	}
+	return policy.Unstructured().GetNamespace()
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return namespace
 }
 
 // connectGatewayWithGatewayClass establishes a connection between a Gateway and
@@ -210,16 +421,19 @@ func (rm *ResourceModel) connectGatewayWithGatewayClass(gatewayID gatewayID, gat
 	gatewayNode, ok := rm.Gateways[gatewayID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Gateway does not exist in ResourceModel", "gatewayID", gatewayID)
+		rm.audit(AuditActionSkip, gatewayID, gatewayClassID, "edge skipped: Gateway not found")
 		return
 	}
 	gatewayClassNode, ok := rm.GatewayClasses[gatewayClassID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "GatewayClass does not exist in ResourceModel", "gatewayClassID", gatewayClassID)
+		rm.audit(AuditActionSkip, gatewayID, gatewayClassID, "edge skipped: GatewayClass not found")
 		return
 	}
 
 	gatewayNode.GatewayClass = gatewayClassNode
 	gatewayClassNode.Gateways[gatewayID] = gatewayNode
+	rm.audit(AuditActionConnect, gatewayID, gatewayClassID, "connected")
 }
 
 // connectHTTPRouteWithGateway establishes a connection between an HTTPRoute and
@@ -228,16 +442,80 @@ func (rm *ResourceModel) connectHTTPRouteWithGateway(httpRouteID httpRouteID, ga
 	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "HTTPRoute does not exist in ResourceModel", "httpRouteID", httpRouteID)
+		rm.audit(AuditActionSkip, httpRouteID, gatewayID, "edge skipped: HTTPRoute not found")
 		return
 	}
 	gatewayNode, ok := rm.Gateways[gatewayID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Gateway does not exist in ResourceModel", "gatewayID", gatewayID)
+		rm.audit(AuditActionSkip, httpRouteID, gatewayID, "edge skipped: Gateway not found")
+		return
+	}
+
+	if rm.requireParentReferenceGrants && !parentReferenceAccepted(httpRouteNode, gatewayNode) {
+		err := ReferenceNotPermittedError{ReferenceFromTo: ReferenceFromTo{
+			ReferringObject: common.ObjRef{Kind: "HTTPRoute", Name: httpRouteNode.HTTPRoute.GetName(), Namespace: httpRouteNode.HTTPRoute.GetNamespace()},
+			ReferredObject:  common.ObjRef{Kind: "Gateway", Name: gatewayNode.Gateway.GetName(), Namespace: gatewayNode.Gateway.GetNamespace()},
+		}}
+		// The HTTPRoute is connected once per parentRef, so the error is only
+		// recorded for the first parentRef to the Gateway.
+		if !slices.Contains(httpRouteNode.Errors, error(err)) {
+			httpRouteNode.Errors = append(httpRouteNode.Errors, err)
+			klog.V(1).Info(err)
+		}
+		rm.audit(AuditActionSkip, httpRouteID, gatewayID, "edge skipped: reference not permitted")
 		return
 	}
 
 	httpRouteNode.Gateways[gatewayID] = gatewayNode
+	if sectionNames := gatewaySectionNames(httpRouteNode.HTTPRoute, gatewayID); len(sectionNames) != 0 {
+		httpRouteNode.GatewaySections[gatewayID] = sectionNames
+	}
 	gatewayNode.HTTPRoutes[httpRouteID] = httpRouteNode
+	rm.audit(AuditActionConnect, httpRouteID, gatewayID, "connected")
+}
+
+// gatewaySectionNames returns the sorted names of the listeners of the Gateway
+// which the parentRefs of the HTTPRoute attach to. A parentRef may be repeated
+// with different sectionNames to attach to multiple listeners. Nil is returned
+// if any parentRef attaches to the whole Gateway.
+func gatewaySectionNames(httpRoute *gatewayv1.HTTPRoute, gatewayID gatewayID) []gatewayv1.SectionName {
+	seen := make(map[gatewayv1.SectionName]bool)
+	var result []gatewayv1.SectionName
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if !referencesGateway(httpRoute, parentRef, gatewayID) {
+			continue
+		}
+		if parentRef.SectionName == nil || *parentRef.SectionName == "" {
+			return nil
+		}
+		if !seen[*parentRef.SectionName] {
+			seen[*parentRef.SectionName] = true
+			result = append(result, *parentRef.SectionName)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// parentReferenceAccepted returns true if the HTTPRoute is allowed to attach to
+// the Gateway, which is the case for Gateways within the same namespace and for
+// Gateways exposed to the HTTPRoute by some ReferenceGrant.
+func parentReferenceAccepted(httpRouteNode *HTTPRouteNode, gatewayNode *GatewayNode) bool {
+	if httpRouteNode.HTTPRoute.GetNamespace() == gatewayNode.Gateway.GetNamespace() {
+		return true
+	}
+	from := common.ObjRef{
+		Group:     gatewayv1.GroupName,
+		Kind:      "HTTPRoute",
+		Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+	}
+	for _, referenceGrantNode := range gatewayNode.ReferenceGrants {
+		if relations.ReferenceGrantAccepts(*referenceGrantNode.ReferenceGrant, from) {
+			return true
+		}
+	}
+	return false
 }
 
 // connectHTTPRouteWithBackend establishes a connection between an HTTPRoute and
@@ -246,16 +524,103 @@ func (rm *ResourceModel) connectHTTPRouteWithBackend(httpRouteID httpRouteID, ba
 	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "HTTPRoute does not exist in ResourceModel", "httpRouteID", httpRouteID)
+		rm.audit(AuditActionSkip, httpRouteID, backendID, "edge skipped: HTTPRoute not found")
 		return
 	}
 	backendNode, ok := rm.Backends[backendID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Backend does not exist in ResourceModel", "backendID", backendID)
+		rm.audit(AuditActionSkip, httpRouteID, backendID, "edge skipped: Backend not found")
 		return
 	}
 
 	httpRouteNode.Backends[backendID] = backendNode
 	backendNode.HTTPRoutes[httpRouteID] = httpRouteNode
+	rm.audit(AuditActionConnect, httpRouteID, backendID, "connected")
+}
+
+// connectGatewayWithDefaultBackend establishes a connection between a Gateway
+// and a Backend which it uses as a default backend.
+func (rm *ResourceModel) connectGatewayWithDefaultBackend(gatewayID gatewayID, backendID backendID) {
+	gatewayNode, ok := rm.Gateways[gatewayID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "Gateway does not exist in ResourceModel", "gatewayID", gatewayID)
+		rm.audit(AuditActionSkip, gatewayID, backendID, "edge skipped: Gateway not found")
+		return
+	}
+	backendNode, ok := rm.Backends[backendID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "Backend does not exist in ResourceModel", "backendID", backendID)
+		rm.audit(AuditActionSkip, gatewayID, backendID, "edge skipped: Backend not found")
+		return
+	}
+
+	gatewayNode.DefaultBackends[backendID] = backendNode
+	backendNode.DefaultBackendOf[gatewayID] = gatewayNode
+	rm.audit(AuditActionConnect, gatewayID, backendID, "connected")
+}
+
+// connectHTTPRouteWithExtensionRef establishes a connection between an
+// HTTPRoute and an object referenced by one of its ExtensionRef filters.
+func (rm *ResourceModel) connectHTTPRouteWithExtensionRef(httpRouteID httpRouteID, extensionRefID extensionRefID) {
+	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "HTTPRoute does not exist in ResourceModel", "httpRouteID", httpRouteID)
+		rm.audit(AuditActionSkip, httpRouteID, extensionRefID, "edge skipped: HTTPRoute not found")
+		return
+	}
+	extensionRefNode, ok := rm.ExtensionRefs[extensionRefID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "ExtensionRef does not exist in ResourceModel", "extensionRefID", extensionRefID)
+		rm.audit(AuditActionSkip, httpRouteID, extensionRefID, "edge skipped: ExtensionRef not found")
+		return
+	}
+
+	httpRouteNode.ExtensionRefs[extensionRefID] = extensionRefNode
+	extensionRefNode.HTTPRoutes[httpRouteID] = httpRouteNode
+	rm.audit(AuditActionConnect, httpRouteID, extensionRefID, "connected")
+}
+
+// connectHTTPRouteWithSecret establishes a connection between an HTTPRoute and
+// a Secret referenced through its filters.
+func (rm *ResourceModel) connectHTTPRouteWithSecret(httpRouteID httpRouteID, secretID secretID) {
+	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "HTTPRoute does not exist in ResourceModel", "httpRouteID", httpRouteID)
+		rm.audit(AuditActionSkip, httpRouteID, secretID, "edge skipped: HTTPRoute not found")
+		return
+	}
+	secretNode, ok := rm.Secrets[secretID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "Secret does not exist in ResourceModel", "secretID", secretID)
+		rm.audit(AuditActionSkip, httpRouteID, secretID, "edge skipped: Secret not found")
+		return
+	}
+
+	httpRouteNode.Secrets[secretID] = secretNode
+	secretNode.HTTPRoutes[httpRouteID] = httpRouteNode
+	rm.audit(AuditActionConnect, httpRouteID, secretID, "connected")
+}
+
+// connectHTTPRouteWithParentService establishes a connection between a mesh
+// HTTPRoute and the Service which it is attached to as its parent.
+func (rm *ResourceModel) connectHTTPRouteWithParentService(httpRouteID httpRouteID, backendID backendID) {
+	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "HTTPRoute does not exist in ResourceModel", "httpRouteID", httpRouteID)
+		rm.audit(AuditActionSkip, httpRouteID, backendID, "edge skipped: HTTPRoute not found")
+		return
+	}
+	backendNode, ok := rm.Backends[backendID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "Backend does not exist in ResourceModel", "backendID", backendID)
+		rm.audit(AuditActionSkip, httpRouteID, backendID, "edge skipped: Backend not found")
+		return
+	}
+
+	httpRouteNode.ParentServices[backendID] = backendNode
+	backendNode.MeshHTTPRoutes[httpRouteID] = httpRouteNode
+	rm.audit(AuditActionConnect, httpRouteID, backendID, "connected")
 }
 
 // connectGatewayWithNamespace establishes a connection between a Gateway and
@@ -264,16 +629,19 @@ func (rm *ResourceModel) connectGatewayWithNamespace(gatewayID gatewayID, namesp
 	gatewayNode, ok := rm.Gateways[gatewayID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Gateway does not exist in ResourceModel", "gatewayID", gatewayID)
+		rm.audit(AuditActionSkip, gatewayID, namespaceID, "edge skipped: Gateway not found")
 		return
 	}
 	namespaceNode, ok := rm.Namespaces[namespaceID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Namespace does not exist in ResourceModel", "namespaceID", namespaceID)
+		rm.audit(AuditActionSkip, gatewayID, namespaceID, "edge skipped: Namespace not found")
 		return
 	}
 
 	gatewayNode.Namespace = namespaceNode
 	namespaceNode.Gateways[gatewayID] = gatewayNode
+	rm.audit(AuditActionConnect, gatewayID, namespaceID, "connected")
 }
 
 // connectHTTPRouteWithNamespace establishes a connection between an HTTPRoute
@@ -282,16 +650,19 @@ func (rm *ResourceModel) connectHTTPRouteWithNamespace(httpRouteID httpRouteID,
 	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "HTTPRoute does not exist in ResourceModel", "httpRouteID", httpRouteID)
+		rm.audit(AuditActionSkip, httpRouteID, namespaceID, "edge skipped: HTTPRoute not found")
 		return
 	}
 	namespaceNode, ok := rm.Namespaces[namespaceID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Namespace does not exist in ResourceModel", "namespaceID", namespaceID)
+		rm.audit(AuditActionSkip, httpRouteID, namespaceID, "edge skipped: Namespace not found")
 		return
 	}
 
 	httpRouteNode.Namespace = namespaceNode
 	namespaceNode.HTTPRoutes[httpRouteID] = httpRouteNode
+	rm.audit(AuditActionConnect, httpRouteID, namespaceID, "connected")
 }
 
 // connectBackendWithNamespace establishes a connection between a Backend and
@@ -300,16 +671,19 @@ func (rm *ResourceModel) connectBackendWithNamespace(backendID backendID, namesp
 	backendNode, ok := rm.Backends[backendID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Backend does not exist in ResourceModel", "backendID", backendID)
+		rm.audit(AuditActionSkip, backendID, namespaceID, "edge skipped: Backend not found")
 		return
 	}
 	namespaceNode, ok := rm.Namespaces[namespaceID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Namespace does not exist in ResourceModel", "namespaceID", namespaceID)
+		rm.audit(AuditActionSkip, backendID, namespaceID, "edge skipped: Namespace not found")
 		return
 	}
 
 	backendNode.Namespace = namespaceNode
 	namespaceNode.Backends[backendID] = backendNode
+	rm.audit(AuditActionConnect, backendID, namespaceID, "connected")
 }
 
 // connectReferenceGrantWithBackend establishes a connection between a ReferenceGrant and
@@ -318,27 +692,57 @@ func (rm *ResourceModel) connectReferenceGrantWithBackend(referenceGrantID refer
 	referenceGrantNode, ok := rm.ReferenceGrants[referenceGrantID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "ReferenceGrant does not exist in ResourceModel", "referenceGrantID", referenceGrantID)
+		rm.audit(AuditActionSkip, referenceGrantID, backendID, "edge skipped: ReferenceGrant not found")
 		return
 	}
 	backendNode, ok := rm.Backends[backendID]
 	if !ok {
 		klog.V(1).ErrorS(nil, "Backend does not exist in ResourceModel", "backendID", backendID)
+		rm.audit(AuditActionSkip, referenceGrantID, backendID, "edge skipped: Backend not found")
 		return
 	}
 
 	referenceGrantNode.Backends[backendID] = backendNode
 	backendNode.ReferenceGrants[referenceGrantID] = referenceGrantNode
+	rm.audit(AuditActionConnect, referenceGrantID, backendID, "connected")
+}
+
+// connectReferenceGrantWithGateway establishes a connection between a
+// ReferenceGrant and the Gateway it exposes.
+func (rm *ResourceModel) connectReferenceGrantWithGateway(referenceGrantID referenceGrantID, gatewayID gatewayID) {
+	referenceGrantNode, ok := rm.ReferenceGrants[referenceGrantID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "ReferenceGrant does not exist in ResourceModel", "referenceGrantID", referenceGrantID)
+		rm.audit(AuditActionSkip, referenceGrantID, gatewayID, "edge skipped: ReferenceGrant not found")
+		return
+	}
+	gatewayNode, ok := rm.Gateways[gatewayID]
+	if !ok {
+		klog.V(1).ErrorS(nil, "Gateway does not exist in ResourceModel", "gatewayID", gatewayID)
+		rm.audit(AuditActionSkip, referenceGrantID, gatewayID, "edge skipped: Gateway not found")
+		return
+	}
+
+	referenceGrantNode.Gateways[gatewayID] = gatewayNode
+	gatewayNode.ReferenceGrants[referenceGrantID] = referenceGrantNode
+	rm.audit(AuditActionConnect, referenceGrantID, gatewayID, "connected")
 }
 
 // calculateEffectivePolicies calculates the effective policies for all
-// Gateways, HTTPRoutes, and Backends in the ResourceModel.
+// Gateways, HTTPRoutes (including mesh routes), and Backends in the ResourceModel.
 func (rm *ResourceModel) calculateEffectivePolicies() error {
+	// Only the nodes marked dirty are recalculated, once.
+	defer func() { rm.dirty = nil }()
+
 	if err := rm.calculateEffectivePoliciesForGateways(); err != nil {
 		return err
 	}
 	if err := rm.calculateEffectivePoliciesForHTTPRoutes(); err != nil {
 		return err
 	}
+	if err := rm.calculateEffectivePoliciesForMeshRoutes(); err != nil {
+		return err
+	}
 	if err := rm.calculateEffectivePoliciesForBackends(); err != nil {
 		return err
 	}
@@ -350,44 +754,80 @@ func (rm *ResourceModel) calculateEffectivePolicies() error {
 // Namespace, and Gateway).
 func (rm *ResourceModel) calculateEffectivePoliciesForGateways() error {
 	for _, gatewayNode := range rm.Gateways {
+		if !rm.needsRecalculation(gatewayNode) {
+			continue
+		}
+
 		// Do not calculate effective policy for the Gateway if the referenced
 		// GatewayClass does not exist. For now, we only calculate effective policy
-		// once the references are corrected.
-		if gatewayNode.GatewayClass == nil {
+		// once the references are corrected. If GatewayClasses could not be
+		// fetched at all, the effective policy is calculated without them.
+		_, gatewayClassesSkipped := rm.SkippedKinds["GatewayClasses"]
+		if gatewayNode.GatewayClass == nil && !gatewayClassesSkipped {
+			rm.audit(AuditActionSkip, gatewayNode.ID(), nil, "effective policies skipped: GatewayClass not found")
 			continue
 		}
 
 		// Fetch all policies.
-		gatewayClassPolicies := convertPoliciesMapToSlice(gatewayNode.GatewayClass.Policies)
+		var gatewayClassPolicies []policymanager.Policy
+		if gatewayNode.GatewayClass != nil {
+			gatewayClassPolicies = convertPoliciesMapToSlice(gatewayNode.GatewayClass.Policies)
+		}
 		gatewayNamespacePolicies := convertPoliciesMapToSlice(gatewayNode.Namespace.Policies)
-		gatewayPolicies := convertPoliciesMapToSlice(gatewayNode.Policies)
+		// Policies scoped to a listener of the Gateway only apply to that
+		// listener.
+		var gatewayPolicies []policymanager.Policy
+		listenerPolicies := make(map[gatewayv1.SectionName][]policymanager.Policy)
+		for _, policy := range convertPoliciesMapToSlice(gatewayNode.Policies) {
+			if policy.SectionName() != "" {
+				sectionName := gatewayv1.SectionName(policy.SectionName())
+				listenerPolicies[sectionName] = append(listenerPolicies[sectionName], policy)
+				continue
+			}
+			gatewayPolicies = append(gatewayPolicies, policy)
+		}
 
 		// Merge policies by their kind.
-		gatewayClassPoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(gatewayClassPolicies)
+		gatewayClassPoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(gatewayClassPolicies)
 		if err != nil {
 			return err
 		}
-		gatewayNamespacePoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(gatewayNamespacePolicies)
+		gatewayNamespacePoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(gatewayNamespacePolicies)
 		if err != nil {
 			return err
 		}
-		gatewayPoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(gatewayPolicies)
+		gatewayPoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(gatewayPolicies)
 		if err != nil {
 			return err
 		}
 
 		// Merge all hierarchial policies.
-		result, err := policymanager.MergePoliciesOfDifferentHierarchy(gatewayClassPoliciesByKind, gatewayNamespacePoliciesByKind)
+		result, err := rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(gatewayClassPoliciesByKind), gatewayNamespacePoliciesByKind)
 		if err != nil {
 			return err
 		}
 
-		result, err = policymanager.MergePoliciesOfDifferentHierarchy(result, gatewayPoliciesByKind)
+		result, err = rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(result), gatewayPoliciesByKind)
 		if err != nil {
 			return err
 		}
 
 		gatewayNode.EffectivePolicies = result
+		rm.audit(AuditActionResolve, gatewayNode.ID(), nil, effectivePoliciesOutcome(result))
+
+		// Listener-scoped policies are merged on top of the result for the
+		// Gateway.
+		gatewayNode.ListenerEffectivePolicies = make(map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
+		for sectionName, policies := range listenerPolicies {
+			policiesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(policies)
+			if err != nil {
+				return err
+			}
+			gatewayNode.ListenerEffectivePolicies[sectionName], err = rm.mergeRules.MergePoliciesOfDifferentHierarchy(result, policiesByKind)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -397,52 +837,160 @@ func (rm *ResourceModel) calculateEffectivePoliciesForGateways() error {
 // (GatewayClass, Namespace, Gateway, and HTTPRoute).
 func (rm *ResourceModel) calculateEffectivePoliciesForHTTPRoutes() error {
 	for _, httpRouteNode := range rm.HTTPRoutes {
+		if !rm.needsRecalculation(httpRouteNode) {
+			continue
+		}
+
 		result := make(map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy)
+		ruleResult := make(map[gatewayID]map[string]map[policymanager.PolicyCrdID]policymanager.Policy)
+		listenerResult := make(map[gatewayID]map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
 
 		// Step 1: Aggregate all policies of the HTTPRoute and the
-		// HTTPRoute-namespace.
-		httpRoutePolicies := convertPoliciesMapToSlice(httpRouteNode.Policies)
+		// HTTPRoute-namespace. Policies scoped to a rule of the HTTPRoute only
+		// apply to that rule.
+		var httpRoutePolicies []policymanager.Policy
+		rulePolicies := make(map[string][]policymanager.Policy)
+		for _, policy := range convertPoliciesMapToSlice(httpRouteNode.Policies) {
+			if policy.SectionName() != "" {
+				rulePolicies[policy.SectionName()] = append(rulePolicies[policy.SectionName()], policy)
+				continue
+			}
+			httpRoutePolicies = append(httpRoutePolicies, policy)
+		}
 		httpRouteNamespacePolicies := convertPoliciesMapToSlice(httpRouteNode.Namespace.Policies)
 
 		// Step 2: Merge HTTPRoute and HTTPRoute-namespace policies by their kind.
-		httpRoutePoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(httpRoutePolicies)
+		httpRoutePoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(httpRoutePolicies)
 		if err != nil {
 			return err
 		}
-		httpRouteNamespacePoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(httpRouteNamespacePolicies)
+		httpRouteNamespacePoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(httpRouteNamespacePolicies)
 		if err != nil {
 			return err
 		}
+		rulePoliciesByKind := make(map[string]map[policymanager.PolicyCrdID]policymanager.Policy)
+		for ruleName, policies := range rulePolicies {
+			rulePoliciesByKind[ruleName], err = rm.mergeRules.MergePoliciesOfSimilarKind(policies)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Step 3: Loop through all Gateways and merge policies for each Gateway.
-		// End result is we get policies partitioned by each Gateway.
+		// End result is we get policies partitioned by each Gateway. The
+		// HTTPRoute-namespace policies merged in step 2 are shared by all
+		// partitions, so only the contributions of the Gateway hierarchy differ.
 		for gatewayID, gatewayNode := range httpRouteNode.Gateways {
-			gatewayPoliciesByKind := gatewayNode.EffectivePolicies
-
-			// Merge all hierarchial policies.
-			mergedPolicies, err := policymanager.MergePoliciesOfDifferentHierarchy(gatewayPoliciesByKind, httpRouteNamespacePoliciesByKind)
+			gatewayPolicies := filterCrossNamespacePolicies(gatewayNode.EffectivePolicies, gatewayNode.Gateway.GetNamespace(), httpRouteNode.HTTPRoute.GetNamespace())
+			mergedPolicies, err := rm.mergeHTTPRoutePolicies(gatewayPolicies, httpRouteNamespacePoliciesByKind, httpRoutePoliciesByKind)
 			if err != nil {
 				return err
 			}
+			result[gatewayID] = mergedPolicies
+			rm.audit(AuditActionResolve, httpRouteNode.ID(), gatewayID, effectivePoliciesOutcome(mergedPolicies))
 
-			mergedPolicies, err = policymanager.MergePoliciesOfDifferentHierarchy(mergedPolicies, httpRoutePoliciesByKind)
-			if err != nil {
-				return err
+			// Each listener the HTTPRoute attaches to with policies scoped to it
+			// yields its own result. HTTPRoutes attaching to the Gateway as a
+			// whole attach to all of its listeners.
+			listenerResult[gatewayID] = make(map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
+			for _, sectionName := range sortedSectionNames(gatewayNode.ListenerEffectivePolicies) {
+				if !httpRouteNode.AttachesToListener(gatewayID, sectionName) {
+					continue
+				}
+				listenerPolicies := filterCrossNamespacePolicies(gatewayNode.ListenerEffectivePolicies[sectionName], gatewayNode.Gateway.GetNamespace(), httpRouteNode.HTTPRoute.GetNamespace())
+				listenerResult[gatewayID][sectionName], err = rm.mergeHTTPRoutePolicies(listenerPolicies, httpRouteNamespacePoliciesByKind, httpRoutePoliciesByKind)
+				if err != nil {
+					return err
+				}
 			}
 
-			result[gatewayID] = mergedPolicies
+			// Rules without rule-scoped policies share the result of the
+			// HTTPRoute, while rule-scoped policies are merged on top of it. Rules
+			// are part of the HTTPRoute, so they also share its direct policies.
+			ruleResult[gatewayID] = make(map[string]map[policymanager.PolicyCrdID]policymanager.Policy)
+			for _, ruleName := range httpRouteNode.RuleNames {
+				if ruleName == "" {
+					continue
+				}
+				policiesByKind, ok := rulePoliciesByKind[ruleName]
+				if !ok {
+					ruleResult[gatewayID][ruleName] = mergedPolicies
+					continue
+				}
+				ruleResult[gatewayID][ruleName], err = rm.mergeRules.MergePoliciesOfDifferentHierarchy(mergedPolicies, policiesByKind)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		httpRouteNode.EffectivePolicies = result
+		httpRouteNode.RuleEffectivePolicies = ruleResult
+		httpRouteNode.ListenerEffectivePolicies = listenerResult
+	}
+	return nil
+}
+
+// calculateEffectivePoliciesForMeshRoutes calculates the effective policies
+// for each HTTPRoute with a Service as its parent. Mesh routes have no Gateway
+// or GatewayClass hierarchy, so only the HTTPRoute-namespace and HTTPRoute
+// policies are merged, and the result is not partitioned by Gateway.
+func (rm *ResourceModel) calculateEffectivePoliciesForMeshRoutes() error {
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		if !httpRouteNode.IsMeshRoute() || !rm.needsRecalculation(httpRouteNode) {
+			continue
+		}
+
+		// Policies scoped to a rule of the HTTPRoute do not apply to the
+		// HTTPRoute as a whole.
+		var httpRoutePolicies []policymanager.Policy
+		for _, policy := range convertPoliciesMapToSlice(httpRouteNode.Policies) {
+			if policy.SectionName() == "" {
+				httpRoutePolicies = append(httpRoutePolicies, policy)
+			}
+		}
+		httpRouteNamespacePolicies := convertPoliciesMapToSlice(httpRouteNode.Namespace.Policies)
+
+		httpRoutePoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(httpRoutePolicies)
+		if err != nil {
+			return err
+		}
+		httpRouteNamespacePoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(httpRouteNamespacePolicies)
+		if err != nil {
+			return err
+		}
+
+		result, err := rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(httpRouteNamespacePoliciesByKind), httpRoutePoliciesByKind)
+		if err != nil {
+			return err
+		}
+		httpRouteNode.MeshEffectivePolicies = result
+		rm.audit(AuditActionResolve, httpRouteNode.ID(), nil, effectivePoliciesOutcome(result))
 	}
 	return nil
 }
 
+// mergeHTTPRoutePolicies merges the inheritable effective policies of a
+// Gateway, or of one of its listeners, with the policies of the
+// HTTPRoute-namespace and the HTTPRoute.
+func (rm *ResourceModel) mergeHTTPRoutePolicies(gatewayPoliciesByKind, httpRouteNamespacePoliciesByKind, httpRoutePoliciesByKind map[policymanager.PolicyCrdID]policymanager.Policy) (map[policymanager.PolicyCrdID]policymanager.Policy, error) {
+	result, err := rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(gatewayPoliciesByKind), httpRouteNamespacePoliciesByKind)
+	if err != nil {
+		return nil, err
+	}
+	return rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(result), httpRoutePoliciesByKind)
+}
+
 // calculateEffectivePoliciesForBackends calculates the effective policies for
 // each Backend, considering policies from different hierarchies (GatewayClass,
-// Namespace, Gateway, HTTPRoute, and Backend).
+// Namespace, Gateway, HTTPRoute, and Backend). Default backends of a Gateway
+// inherit the policies of the Gateway without any HTTPRoute in between.
 func (rm *ResourceModel) calculateEffectivePoliciesForBackends() error {
 	for _, backendNode := range rm.Backends {
+		if !rm.needsRecalculation(backendNode) {
+			continue
+		}
+
 		result := make(map[gatewayID]map[policymanager.PolicyCrdID]policymanager.Policy)
 
 		// Step 1: Aggregate all policies of the Backend and the Backend-namespace.
@@ -450,52 +998,395 @@ func (rm *ResourceModel) calculateEffectivePoliciesForBackends() error {
 		backendNamespacePolicies := convertPoliciesMapToSlice(backendNode.Namespace.Policies)
 
 		// Step 2: Merge Backend and Backend-namespace policies by their kind.
-		backendPoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(backendPolicies)
+		backendPoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(backendPolicies)
 		if err != nil {
 			return err
 		}
-		backendNamespacePoliciesByKind, err := policymanager.MergePoliciesOfSimilarKind(backendNamespacePolicies)
+		backendNamespacePoliciesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(backendNamespacePolicies)
 		if err != nil {
 			return err
 		}
 
 		// Step 3: Loop through all HTTPRoutes and get their effective policies. Merge
 		// effective policies such that we get policies partitioned by Gateway.
+		// Listeners of a Gateway with policies scoped to them are partitioned
+		// separately.
+		listenerResult := make(map[gatewayID]map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
 		for _, httpRouteNode := range backendNode.HTTPRoutes {
 			httpRoutePoliciesByGateway := httpRouteNode.EffectivePolicies
 
 			for gatewayID, policies := range httpRoutePoliciesByGateway {
-				result[gatewayID], err = policymanager.MergePoliciesOfSameHierarchy(result[gatewayID], policies)
+				policies = filterCrossNamespacePolicies(policies, httpRouteNode.HTTPRoute.GetNamespace(), backendNode.Backend.GetNamespace())
+				result[gatewayID], err = rm.mergeRules.MergePoliciesOfSameHierarchy(result[gatewayID], policies)
 				if err != nil {
 					return err
 				}
+				for sectionName := range httpRouteNode.ListenerEffectivePolicies[gatewayID] {
+					if listenerResult[gatewayID] == nil {
+						listenerResult[gatewayID] = make(map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
+					}
+					listenerResult[gatewayID][sectionName] = nil
+				}
+			}
+		}
+		// The HTTPRoutes reaching the Backend through a listener contribute
+		// their effective policies for that listener, or those for the Gateway
+		// if the listener has no policies scoped to it for them.
+		for gatewayID, policiesByListener := range listenerResult {
+			for sectionName := range policiesByListener {
+				for _, httpRouteNode := range backendNode.HTTPRoutes {
+					policies, ok := httpRouteNode.EffectivePolicies[gatewayID]
+					if !ok || !httpRouteNode.AttachesToListener(gatewayID, sectionName) {
+						continue
+					}
+					if listenerPolicies, ok := httpRouteNode.ListenerEffectivePolicies[gatewayID][sectionName]; ok {
+						policies = listenerPolicies
+					}
+					policies = filterCrossNamespacePolicies(policies, httpRouteNode.HTTPRoute.GetNamespace(), backendNode.Backend.GetNamespace())
+					policiesByListener[sectionName], err = rm.mergeRules.MergePoliciesOfSameHierarchy(policiesByListener[sectionName], policies)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// Step 3b: Default backends are reached without any HTTPRoute, so they
+		// inherit directly from the effective policies of the Gateway, which
+		// include the GatewayClass and Gateway-namespace policies. If the Backend
+		// is also referenced by HTTPRoutes of the Gateway, their effective
+		// policies already contain those of the Gateway.
+		for gatewayID, gatewayNode := range backendNode.DefaultBackendOf {
+			if _, ok := result[gatewayID]; ok {
+				continue
+			}
+			result[gatewayID] = filterCrossNamespacePolicies(gatewayNode.EffectivePolicies, gatewayNode.Gateway.GetNamespace(), backendNode.Backend.GetNamespace())
+			for sectionName, policies := range gatewayNode.ListenerEffectivePolicies {
+				if listenerResult[gatewayID] == nil {
+					listenerResult[gatewayID] = make(map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
+				}
+				listenerResult[gatewayID][sectionName] = filterCrossNamespacePolicies(policies, gatewayNode.Gateway.GetNamespace(), backendNode.Backend.GetNamespace())
 			}
 		}
 
 		// Step 4: Loop through all Gateways and merge the Backend and
 		// Backend-namespace specific policies. Note that this needs to be done
-		// separately from Step 4 i.e. we can't have this loop within Step 4 itself.
+		// separately from Step 3 i.e. we can't have this loop within Step 3 itself.
 		// This is because we first want to merge all policies of the same-hierarchy
 		// together and then move to the next hierarchy of Backend and
 		// Backend-namespace.
 		for gatewayID := range result {
-			// Merge all hierarchial policies.
-			result[gatewayID], err = policymanager.MergePoliciesOfDifferentHierarchy(result[gatewayID], backendNamespacePoliciesByKind)
+			result[gatewayID], err = rm.mergeBackendPolicies(result[gatewayID], backendNamespacePoliciesByKind, backendPoliciesByKind)
 			if err != nil {
 				return err
 			}
-
-			result[gatewayID], err = policymanager.MergePoliciesOfDifferentHierarchy(result[gatewayID], backendPoliciesByKind)
-			if err != nil {
-				return err
+			rm.audit(AuditActionResolve, backendNode.ID(), gatewayID, effectivePoliciesOutcome(result[gatewayID]))
+		}
+		for _, policiesByListener := range listenerResult {
+			for sectionName := range policiesByListener {
+				policiesByListener[sectionName], err = rm.mergeBackendPolicies(policiesByListener[sectionName], backendNamespacePoliciesByKind, backendPoliciesByKind)
+				if err != nil {
+					return err
+				}
 			}
 		}
 
+		backendNode.ListenerEffectivePolicies = listenerResult
 		backendNode.EffectivePolicies = result
 	}
 	return nil
 }
 
+// mergeBackendPolicies merges the inheritable policies reaching a Backend
+// through a Gateway, or one of its listeners, with the policies of the
+// Backend-namespace and the Backend.
+func (rm *ResourceModel) mergeBackendPolicies(inheritedPolicies, backendNamespacePoliciesByKind, backendPoliciesByKind map[policymanager.PolicyCrdID]policymanager.Policy) (map[policymanager.PolicyCrdID]policymanager.Policy, error) {
+	result, err := rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(inheritedPolicies), backendNamespacePoliciesByKind)
+	if err != nil {
+		return nil, err
+	}
+	return rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(result), backendPoliciesByKind)
+}
+
+// validateEffectivePolicies validates the effective policies of all resources
+// against the schema of their CRD, and records an InvalidEffectivePolicyError
+// on the resource for every effective policy which does not conform to it.
+// The effective policies of listeners and rules are validated as well, unless
+// the effective policy of the same kind for the whole resource is already
+// invalid.
+func (rm *ResourceModel) validateEffectivePolicies(policyManager *policymanager.PolicyManager) {
+	for _, gatewayNode := range rm.Gateways {
+		errs := invalidEffectivePolicies(policyManager, gatewayNode.EffectivePolicies, common.ObjRef{}, "", nil)
+		invalid := invalidPolicyCrdIDs(errs)
+		for _, sectionName := range sortedSectionNames(gatewayNode.ListenerEffectivePolicies) {
+			errs = append(errs, invalidEffectivePolicies(policyManager, gatewayNode.ListenerEffectivePolicies[sectionName], common.ObjRef{}, string(sectionName), invalid)...)
+		}
+		gatewayNode.Errors = append(gatewayNode.Errors, errs...)
+	}
+	for _, httpRouteNode := range rm.HTTPRoutes {
+		httpRouteNode.Errors = append(httpRouteNode.Errors, invalidEffectivePolicies(policyManager, httpRouteNode.MeshEffectivePolicies, common.ObjRef{}, "", nil)...)
+		for _, gatewayID := range sortedGatewayIDs(httpRouteNode.EffectivePolicies) {
+			errs := invalidEffectivePolicies(policyManager, httpRouteNode.EffectivePolicies[gatewayID], gatewayObjRef(gatewayID), "", nil)
+			invalid := invalidPolicyCrdIDs(errs)
+			listenerPolicies := httpRouteNode.ListenerEffectivePolicies[gatewayID]
+			for _, sectionName := range sortedSectionNames(listenerPolicies) {
+				errs = append(errs, invalidEffectivePolicies(policyManager, listenerPolicies[sectionName], gatewayObjRef(gatewayID), string(sectionName), invalid)...)
+			}
+			rulePolicies := httpRouteNode.RuleEffectivePolicies[gatewayID]
+			for _, ruleName := range sortedRuleNames(rulePolicies) {
+				errs = append(errs, invalidEffectivePolicies(policyManager, rulePolicies[ruleName], gatewayObjRef(gatewayID), ruleName, invalid)...)
+			}
+			httpRouteNode.Errors = append(httpRouteNode.Errors, errs...)
+		}
+	}
+	for _, backendNode := range rm.Backends {
+		for _, gatewayID := range sortedGatewayIDs(backendNode.EffectivePolicies) {
+			errs := invalidEffectivePolicies(policyManager, backendNode.EffectivePolicies[gatewayID], gatewayObjRef(gatewayID), "", nil)
+			invalid := invalidPolicyCrdIDs(errs)
+			listenerPolicies := backendNode.ListenerEffectivePolicies[gatewayID]
+			for _, sectionName := range sortedSectionNames(listenerPolicies) {
+				errs = append(errs, invalidEffectivePolicies(policyManager, listenerPolicies[sectionName], gatewayObjRef(gatewayID), string(sectionName), invalid)...)
+			}
+			backendNode.Errors = append(backendNode.Errors, errs...)
+		}
+	}
+}
+
+// invalidEffectivePolicies validates the effective policies, skipping the
+// kinds in skip, and returns an InvalidEffectivePolicyError for every one
+// which does not conform to the schema of its CRD.
+func invalidEffectivePolicies(policyManager *policymanager.PolicyManager, policies map[policymanager.PolicyCrdID]policymanager.Policy, gateway common.ObjRef, sectionName string, skip map[policymanager.PolicyCrdID]bool) []error {
+	var policyCrdIDs []policymanager.PolicyCrdID
+	for policyCrdID := range policies {
+		if !skip[policyCrdID] {
+			policyCrdIDs = append(policyCrdIDs, policyCrdID)
+		}
+	}
+	sort.Slice(policyCrdIDs, func(i, j int) bool { return policyCrdIDs[i] < policyCrdIDs[j] })
+
+	var result []error
+	for _, policyCrdID := range policyCrdIDs {
+		if err := policyManager.ValidateMergedPolicy(policies[policyCrdID]); err != nil {
+			result = append(result, InvalidEffectivePolicyError{PolicyCrdID: policyCrdID, Gateway: gateway, SectionName: sectionName, Err: err})
+		}
+	}
+	return result
+}
+
+// invalidPolicyCrdIDs returns the kinds of the effective policies reported as
+// invalid by errs.
+func invalidPolicyCrdIDs(errs []error) map[policymanager.PolicyCrdID]bool {
+	result := make(map[policymanager.PolicyCrdID]bool)
+	for _, err := range errs {
+		var invalidEffectivePolicyErr InvalidEffectivePolicyError
+		if errors.As(err, &invalidEffectivePolicyErr) {
+			result[invalidEffectivePolicyErr.PolicyCrdID] = true
+		}
+	}
+	return result
+}
+
+func gatewayObjRef(gatewayID gatewayID) common.ObjRef {
+	return common.ObjRef{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: gatewayID.Namespace, Name: gatewayID.Name}
+}
+
+func sortedSectionNames[T any](m map[gatewayv1.SectionName]T) []gatewayv1.SectionName {
+	var result []gatewayv1.SectionName
+	for sectionName := range m {
+		result = append(result, sectionName)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func sortedRuleNames[T any](m map[string]T) []string {
+	var result []string
+	for ruleName := range m {
+		result = append(result, ruleName)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedGatewayIDs[T any](m map[gatewayID]T) []gatewayID {
+	var result []gatewayID
+	for gatewayID := range m {
+		result = append(result, gatewayID)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return fmt.Sprintf("%v/%v", result[i].Namespace, result[i].Name) < fmt.Sprintf("%v/%v", result[j].Namespace, result[j].Name)
+	})
+	return result
+}
+
+// PolicyLayersForGateway returns, for each policy kind, the contribution of
+// every hierarchy level (GatewayClass, Namespace, and Gateway) to the effective
+// policy of the Gateway. Levels without policies of a kind are omitted.
+func (rm *ResourceModel) PolicyLayersForGateway(gatewayID gatewayID) (map[policymanager.PolicyCrdID][]policymanager.LevelDelta, error) {
+	gatewayNode, ok := rm.Gateways[gatewayID]
+	if !ok {
+		return nil, fmt.Errorf("failed to find Gateway %v/%v in ResourceModel", gatewayID.Namespace, gatewayID.Name)
+	}
+	return rm.computePolicyLayers(gatewayHierarchyLevels(gatewayNode))
+}
+
+// PolicyLayersForHTTPRoute returns, for each policy kind, the contribution of
+// every hierarchy level (GatewayClass, Namespace, Gateway, Namespace, and
+// HTTPRoute) to the effective policy of the HTTPRoute in the context of the
+// given Gateway. Levels without policies of a kind are omitted.
+func (rm *ResourceModel) PolicyLayersForHTTPRoute(httpRouteID httpRouteID, gatewayID gatewayID) (map[policymanager.PolicyCrdID][]policymanager.LevelDelta, error) {
+	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
+	if !ok {
+		return nil, fmt.Errorf("failed to find HTTPRoute %v/%v in ResourceModel", httpRouteID.Namespace, httpRouteID.Name)
+	}
+	gatewayNode, ok := httpRouteNode.Gateways[gatewayID]
+	if !ok {
+		return nil, fmt.Errorf("HTTPRoute %v/%v is not attached to Gateway %v/%v", httpRouteID.Namespace, httpRouteID.Name, gatewayID.Namespace, gatewayID.Name)
+	}
+
+	levels := gatewayHierarchyLevels(gatewayNode)
+	if httpRouteNode.Namespace != nil {
+		levels = append(levels, policyHierarchyLevel{
+			name:      fmt.Sprintf("Namespace %v", httpRouteNode.Namespace.Namespace.GetName()),
+			namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+			policies:  httpRouteNode.Namespace.Policies,
+		})
+	}
+	levels = append(levels, policyHierarchyLevel{
+		name:      fmt.Sprintf("HTTPRoute %v/%v", httpRouteID.Namespace, httpRouteID.Name),
+		namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+		policies:  httpRouteNode.Policies,
+	})
+	return rm.computePolicyLayers(levels)
+}
+
+// policyHierarchyLevel is a single level of the policy hierarchy along with
+// the policies directly attached at that level.
+type policyHierarchyLevel struct {
+	name string
+	// namespace is the namespace in which the policies of the level are
+	// inherited, i.e. the namespace of the Gateway for the levels of its
+	// hierarchy and the namespace of the HTTPRoute for the levels of its own.
+	namespace string
+	policies  map[policyID]*PolicyNode
+}
+
+// gatewayHierarchyLevels returns the hierarchy levels which contribute to the
+// effective policies of the Gateway, ordered from the lowest to the highest
+// precedence.
+func gatewayHierarchyLevels(gatewayNode *GatewayNode) []policyHierarchyLevel {
+	// The GatewayClass is cluster-scoped, so its policies are inherited by the
+	// Gateway without crossing namespaces.
+	namespace := gatewayNode.Gateway.GetNamespace()
+	var levels []policyHierarchyLevel
+	if gatewayNode.GatewayClass != nil {
+		levels = append(levels, policyHierarchyLevel{
+			name:      fmt.Sprintf("GatewayClass %v", gatewayNode.GatewayClass.GatewayClass.GetName()),
+			namespace: namespace,
+			policies:  gatewayNode.GatewayClass.Policies,
+		})
+	}
+	if gatewayNode.Namespace != nil {
+		levels = append(levels, policyHierarchyLevel{
+			name:      fmt.Sprintf("Namespace %v", gatewayNode.Namespace.Namespace.GetName()),
+			namespace: namespace,
+			policies:  gatewayNode.Namespace.Policies,
+		})
+	}
+	levels = append(levels, policyHierarchyLevel{
+		name:      fmt.Sprintf("Gateway %v/%v", gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName()),
+		namespace: namespace,
+		policies:  gatewayNode.Policies,
+	})
+	return levels
+}
+
+// computePolicyLayers merges the policies of the hierarchy levels in order, the
+// same way effective policies are calculated, and records the fields each
+// level contributed to the result. Policies scoped to a section of their
+// target, e.g. a listener or a rule, do not apply to the whole resource and
+// are left out.
+func (rm *ResourceModel) computePolicyLayers(levels []policyHierarchyLevel) (map[policymanager.PolicyCrdID][]policymanager.LevelDelta, error) {
+	result := make(map[policymanager.PolicyCrdID][]policymanager.LevelDelta)
+	current := make(map[policymanager.PolicyCrdID]policymanager.Policy)
+
+	for i, level := range levels {
+		if i > 0 {
+			current = filterCrossNamespacePolicies(current, levels[i-1].namespace, level.namespace)
+		}
+
+		var policies []policymanager.Policy
+		for _, policy := range convertPoliciesMapToSlice(level.policies) {
+			if policy.SectionName() == "" {
+				policies = append(policies, policy)
+			}
+		}
+		policiesByKind, err := rm.mergeRules.MergePoliciesOfSimilarKind(policies)
+		if err != nil {
+			return nil, err
+		}
+		merged, err := rm.mergeRules.MergePoliciesOfDifferentHierarchy(filterInheritablePolicies(current), policiesByKind)
+		if err != nil {
+			return nil, err
+		}
+
+		for policyCrdID := range policiesByKind {
+			var before *policymanager.Policy
+			if policy, ok := current[policyCrdID]; ok {
+				before = &policy
+			}
+			changes, err := policymanager.ComputeSpecDelta(before, merged[policyCrdID])
+			if err != nil {
+				return nil, err
+			}
+
+			var levelPolicies []policymanager.Policy
+			for _, policy := range policies {
+				if policy.PolicyCrdID() == policyCrdID {
+					levelPolicies = append(levelPolicies, policy)
+				}
+			}
+
+			result[policyCrdID] = append(result[policyCrdID], policymanager.LevelDelta{
+				Level:    level.name,
+				Policies: policymanager.ToPolicyRefs(levelPolicies),
+				Changes:  changes,
+			})
+		}
+		current = merged
+	}
+	return result, nil
+}
+
+// filterInheritablePolicies returns the parts of the policies which are
+// inherited by the descendants of the resource they apply to. Inherited
+// policies which also declare fields outside of spec.default and spec.override
+// only pass down their default and override sections.
+func filterInheritablePolicies(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[policymanager.PolicyCrdID]policymanager.Policy {
+	result := make(map[policymanager.PolicyCrdID]policymanager.Policy)
+	for policyCrdID, policy := range policies {
+		result[policyCrdID] = policy.InheritablePart()
+	}
+	return result
+}
+
+// filterCrossNamespacePolicies returns the policies which are inherited by a
+// resource in namespace toNamespace from a resource in namespace
+// fromNamespace. If the namespaces differ, the kinds of policies whose
+// MergeRules disable cross namespace propagation are dropped.
+func filterCrossNamespacePolicies(policies map[policymanager.PolicyCrdID]policymanager.Policy, fromNamespace, toNamespace string) map[policymanager.PolicyCrdID]policymanager.Policy {
+	if fromNamespace == toNamespace {
+		return policies
+	}
+	result := make(map[policymanager.PolicyCrdID]policymanager.Policy)
+	for policyCrdID, policy := range policies {
+		if policy.MergeRules().PropagatesAcrossNamespaces() {
+			result[policyCrdID] = policy
+		}
+	}
+	return result
+}
+
 func convertPoliciesMapToSlice(policies map[policyID]*PolicyNode) []policymanager.Policy {
 	var result []policymanager.Policy
 	for _, policyNode := range policies {
@@ -0,0 +1,1821 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_PolicyLayersForGateway(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-gatewayclass",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": int64(30),
+						"retries": int64(2),
+					},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "GatewayClass",
+						"name":  "foo-gatewayclass",
+					},
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-gateway",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout":        int64(60),
+						"maxConnections": int64(100),
+					},
+					"targetRef": map[string]interface{}{
+						"group":     "gateway.networking.k8s.io",
+						"kind":      "Gateway",
+						"name":      "foo-gateway",
+						"namespace": "default",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	layers, err := resourceModel.PolicyLayersForGateway(GatewayID("default", "foo-gateway"))
+	if err != nil {
+		t.Fatalf("PolicyLayersForGateway() failed: %v", err)
+	}
+
+	want := []policymanager.LevelDelta{
+		{
+			Level: "GatewayClass foo-gatewayclass",
+			Policies: []policymanager.ObjRef{
+				{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gatewayclass"},
+			},
+			Changes: []policymanager.FieldChange{
+				{Path: "retries", Value: float64(2)},
+				{Path: "timeout", Value: float64(30)},
+			},
+		},
+		{
+			Level: "Gateway default/foo-gateway",
+			Policies: []policymanager.ObjRef{
+				{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gateway"},
+			},
+			Changes: []policymanager.FieldChange{
+				{Path: "maxConnections", Value: float64(100)},
+				{Path: "timeout", Value: float64(60), PreviousValue: float64(30)},
+			},
+		},
+	}
+	got := layers["HealthCheckPolicy.foo.com"]
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in PolicyLayersForGateway(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+}
+
+func TestResourceModel_RuleEffectivePolicies(t *testing.T) {
+	timeoutPolicy := func(name, sectionName string, seconds int64) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "HTTPRoute",
+			"name":  "foo-httproute",
+		}
+		if sectionName != "" {
+			targetRef["sectionName"] = sectionName
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"seconds":   seconds,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{}, {}, {}, {}},
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		timeoutPolicy("timeout-policy-httproute", "", 30),
+		timeoutPolicy("timeout-policy-read", "read", 5),
+		timeoutPolicy("timeout-policy-write", "write", 60),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-httproute not found in resourceModel")
+	}
+
+	// Rule names are not part of the structured HTTPRouteRule type, which the
+	// fake clients convert HTTPRoutes to, so they are read from an unstructured
+	// HTTPRoute and the effective policies are recalculated.
+	httpRouteNode.RuleNames = httpRouteRuleNames(&unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"name": "read"},
+					map[string]interface{}{"name": "write"},
+					map[string]interface{}{"name": "other"},
+					map[string]interface{}{},
+				},
+			},
+		},
+	})
+	if diff := cmp.Diff([]string{"read", "write", "other", ""}, httpRouteNode.RuleNames); diff != "" {
+		t.Fatalf("Unexpected diff in RuleNames; diff (-want +got)=\n%v", diff)
+	}
+	if err := resourceModel.calculateEffectivePolicies(); err != nil {
+		t.Fatalf("Failed to calculate effective policies: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"read", "write"}, httpRouteNode.RulesWithScopedPolicies()); diff != "" {
+		t.Errorf("Unexpected diff in RulesWithScopedPolicies(); diff (-want +got)=\n%v", diff)
+	}
+
+	effectiveSeconds := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) interface{} {
+		policy, ok := policies["TimeoutPolicy.bar.com"]
+		if !ok {
+			return nil
+		}
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		return spec["seconds"]
+	}
+
+	gwID := GatewayID("default", "foo-gateway")
+	// Rule-scoped policies don't apply to the HTTPRoute as a whole.
+	if got, want := effectiveSeconds(httpRouteNode.EffectivePolicies[gwID]), int64(30); got != want {
+		t.Errorf("Effective seconds of HTTPRoute = %v; want %v", got, want)
+	}
+
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	wantRuleSeconds := map[string]interface{}{
+		"read":  float64(5),
+		"write": float64(60),
+		"other": int64(30),
+	}
+	gotRuleSeconds := make(map[string]interface{})
+	for ruleName, policies := range httpRouteNode.RuleEffectivePolicies[gwID] {
+		gotRuleSeconds[ruleName] = effectiveSeconds(policies)
+	}
+	if diff := cmp.Diff(wantRuleSeconds, gotRuleSeconds); diff != "" {
+		t.Errorf("Unexpected diff in effective seconds of rules; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotRuleSeconds, wantRuleSeconds, diff)
+	}
+}
+
+func TestResourceModel_ListenerSections(t *testing.T) {
+	healthCheckPolicy := func(name, sectionName string, timeout int64) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "Gateway",
+			"name":  "foo-gateway",
+		}
+		if sectionName != "" {
+			targetRef["sectionName"] = sectionName
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": timeout,
+					},
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+					{Name: "admin", Protocol: gatewayv1.HTTPProtocolType, Port: 8080},
+				},
+			},
+		},
+		// foo-httproute attaches to two named listeners of the same Gateway.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "foo-gateway", SectionName: common.PtrTo[gatewayv1.SectionName]("https")},
+						{Name: "foo-gateway", SectionName: common.PtrTo[gatewayv1.SectionName]("http")},
+					},
+				},
+			},
+		},
+		// bar-httproute attaches to the whole Gateway, besides a named listener.
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "foo-gateway", SectionName: common.PtrTo[gatewayv1.SectionName]("admin")},
+						{Name: "foo-gateway"},
+					},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: "bar-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		healthCheckPolicy("health-check-gateway", "", 30),
+		healthCheckPolicy("health-check-https", "https", 5),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	gwID := GatewayID("default", "foo-gateway")
+	fooHTTPRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-httproute not found in resourceModel")
+	}
+	barHTTPRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "bar-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/bar-httproute not found in resourceModel")
+	}
+
+	// Both sections of foo-httproute are part of its edge to the Gateway.
+	wantSections := map[gatewayID][]gatewayv1.SectionName{gwID: {"http", "https"}}
+	if diff := cmp.Diff(wantSections, fooHTTPRouteNode.GatewaySections); diff != "" {
+		t.Errorf("Unexpected diff in GatewaySections of foo-httproute; diff (-want +got)=\n%v", diff)
+	}
+	// Attaching to the whole Gateway includes all listeners.
+	if diff := cmp.Diff(map[gatewayID][]gatewayv1.SectionName{}, barHTTPRouteNode.GatewaySections); diff != "" {
+		t.Errorf("Unexpected diff in GatewaySections of bar-httproute; diff (-want +got)=\n%v", diff)
+	}
+
+	effectiveTimeout := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) interface{} {
+		policy, ok := policies["HealthCheckPolicy.foo.com"]
+		if !ok {
+			return nil
+		}
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		return spec["timeout"]
+	}
+
+	// Listener-scoped policies don't apply to the Gateway as a whole. Merged
+	// policies are round-tripped through JSON, hence numbers are float64.
+	gatewayNode := resourceModel.Gateways[gwID]
+	if got, want := effectiveTimeout(gatewayNode.EffectivePolicies), float64(30); got != want {
+		t.Errorf("Effective timeout of Gateway = %v; want %v", got, want)
+	}
+	if got, want := effectiveTimeout(fooHTTPRouteNode.EffectivePolicies[gwID]), float64(30); got != want {
+		t.Errorf("Effective timeout of foo-httproute = %v; want %v", got, want)
+	}
+
+	wantListenerTimeouts := map[gatewayv1.SectionName]interface{}{"https": float64(5)}
+	gotListenerTimeouts := make(map[gatewayv1.SectionName]interface{})
+	for sectionName, policies := range fooHTTPRouteNode.ListenerEffectivePolicies[gwID] {
+		gotListenerTimeouts[sectionName] = effectiveTimeout(policies)
+	}
+	if diff := cmp.Diff(wantListenerTimeouts, gotListenerTimeouts); diff != "" {
+		t.Errorf("Unexpected diff in effective timeout of listeners of foo-httproute; diff (-want +got)=\n%v", diff)
+	}
+	if got := fooHTTPRouteNode.ListenerEffectivePolicies[gwID]["http"]; got != nil {
+		t.Errorf("ListenerEffectivePolicies of foo-httproute for listener http = %v; want none", got)
+	}
+
+	// bar-httproute attaches to the whole Gateway, so it also attaches to the
+	// https listener and inherits the policies scoped to it, as does its
+	// backend.
+	gotListenerTimeouts = make(map[gatewayv1.SectionName]interface{})
+	for sectionName, policies := range barHTTPRouteNode.ListenerEffectivePolicies[gwID] {
+		gotListenerTimeouts[sectionName] = effectiveTimeout(policies)
+	}
+	if diff := cmp.Diff(wantListenerTimeouts, gotListenerTimeouts); diff != "" {
+		t.Errorf("Unexpected diff in effective timeout of listeners of bar-httproute; diff (-want +got)=\n%v", diff)
+	}
+	backendNode, ok := resourceModel.Backends[BackendIDForService("default", "bar-svc")]
+	if !ok {
+		t.Fatalf("Backend default/bar-svc not found in resourceModel")
+	}
+	if got, want := effectiveTimeout(backendNode.EffectivePolicies[gwID]), float64(30); got != want {
+		t.Errorf("Effective timeout of bar-svc = %v; want %v", got, want)
+	}
+	gotListenerTimeouts = make(map[gatewayv1.SectionName]interface{})
+	for sectionName, policies := range backendNode.ListenerEffectivePolicies[gwID] {
+		gotListenerTimeouts[sectionName] = effectiveTimeout(policies)
+	}
+	if diff := cmp.Diff(wantListenerTimeouts, gotListenerTimeouts); diff != "" {
+		t.Errorf("Unexpected diff in effective timeout of listeners of bar-svc; diff (-want +got)=\n%v", diff)
+	}
+}
+
+// TestResourceModel_SplitPolicyEffectivePolicies tests Inherited policies
+// which, besides their default and override sections, declare fields that only
+// apply to their target.
+func TestResourceModel_SplitPolicyEffectivePolicies(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "trafficpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "trafficpolicies",
+					Kind:   "TrafficPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TrafficPolicy",
+				"metadata": map[string]interface{}{
+					"name":      "traffic-policy-gateway",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": int64(30),
+					},
+					"override": map[string]interface{}{
+						"retries": int64(3),
+					},
+					// drainTimeout is a direct field which only applies to the Gateway.
+					"drainTimeout": int64(10),
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TrafficPolicy",
+				"metadata": map[string]interface{}{
+					"name":      "traffic-policy-httproute",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": int64(60),
+						"retries": int64(5),
+					},
+					// bufferSize is a direct field which only applies to the HTTPRoute.
+					"bufferSize": int64(1024),
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "HTTPRoute",
+						"name":  "foo-httproute",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	effectiveSpec := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[string]interface{} {
+		policy, ok := policies["TrafficPolicy.foo.com"]
+		if !ok {
+			t.Fatalf("TrafficPolicy.foo.com not found in effective policies")
+		}
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		return spec
+	}
+
+	// The Gateway gets both the inheritable and the direct part of its policy.
+	gwID := GatewayID("default", "foo-gateway")
+	wantGateway := map[string]interface{}{
+		"timeout":      float64(30),
+		"retries":      float64(3),
+		"drainTimeout": float64(10),
+	}
+	gotGateway := effectiveSpec(resourceModel.Gateways[gwID].EffectivePolicies)
+	if diff := cmp.Diff(wantGateway, gotGateway); diff != "" {
+		t.Errorf("Unexpected diff in effective policy of Gateway; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotGateway, wantGateway, diff)
+	}
+
+	// The HTTPRoute only inherits the default and override sections of the
+	// Gateway policy. Its own default for retries loses to the override of the
+	// Gateway, while its direct field applies.
+	wantHTTPRoute := map[string]interface{}{
+		"timeout":    float64(60),
+		"retries":    float64(3),
+		"bufferSize": float64(1024),
+	}
+	gotHTTPRoute := effectiveSpec(resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")].EffectivePolicies[gwID])
+	if diff := cmp.Diff(wantHTTPRoute, gotHTTPRoute); diff != "" {
+		t.Errorf("Unexpected diff in effective policy of HTTPRoute; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotHTTPRoute, wantHTTPRoute, diff)
+	}
+
+	// The policy layers of the HTTPRoute split the Gateway policy the same way.
+	layers, err := resourceModel.PolicyLayersForHTTPRoute(HTTPRouteID("default", "foo-httproute"), gwID)
+	if err != nil {
+		t.Fatalf("PolicyLayersForHTTPRoute() failed: %v", err)
+	}
+	var gotChanges []policymanager.FieldChange
+	for _, layer := range layers["TrafficPolicy.foo.com"] {
+		if layer.Level == "HTTPRoute default/foo-httproute" {
+			gotChanges = layer.Changes
+		}
+	}
+	wantChanges := []policymanager.FieldChange{
+		{Path: "bufferSize", Value: float64(1024)},
+		{Path: "timeout", Value: float64(60), PreviousValue: float64(30)},
+	}
+	if diff := cmp.Diff(wantChanges, gotChanges); diff != "" {
+		t.Errorf("Unexpected diff in changes of the HTTPRoute layer; got=%v, want=%v;\ndiff (-want +got)=\n%v", gotChanges, wantChanges, diff)
+	}
+}
+
+func TestResourceModel_GatewayClassPoliciesReachBackends(t *testing.T) {
+	// The Gateway forwards to foo-svc through foo-httproute, and to
+	// fallback-svc as the default backend of its https listener.
+	gateway, err := newFetchedGateway(gatewayWithDefaultBackends())
+	if err != nil {
+		t.Fatalf("newFetchedGateway() failed: %v", err)
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("fallback"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fallback-svc",
+				Namespace: "fallback",
+			},
+		},
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-default-gateways",
+				Namespace: "fallback",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{
+					Group:     gatewayv1.GroupName,
+					Kind:      "Gateway",
+					Namespace: "default",
+				}},
+				To: []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.ClusterScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata": map[string]interface{}{
+					"name": "health-check-gatewayclass",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{
+						"timeout": int64(30),
+					},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "GatewayClass",
+						"name":  "foo-gatewayclass",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+
+	// The fake clients drop the default backends of the Gateway, so it's added
+	// to the resourceModel directly.
+	ctx := context.Background()
+	resourceModel := &ResourceModel{}
+	resourceModel.addGateways(gateway)
+	discoverer.discoverHTTPRoutesFromGateways(ctx, resourceModel)
+	discoverer.discoverBackendsFromHTTPRoutes(ctx, resourceModel)
+	discoverer.discoverDefaultBackendsFromGateways(ctx, resourceModel)
+	discoverer.discoverGatewayClassesFromGateways(ctx, resourceModel)
+	discoverer.discoverNamespaces(ctx, resourceModel)
+	discoverer.discoverPolicies(resourceModel)
+	if err := resourceModel.calculateEffectivePolicies(); err != nil {
+		t.Fatalf("calculateEffectivePolicies() failed: %v", err)
+	}
+
+	gwID := GatewayID("default", "foo-gateway")
+	want := map[string]interface{}{"timeout": float64(30)}
+	for _, backendID := range []backendID{
+		BackendIDForService("default", "foo-svc"),
+		BackendIDForService("fallback", "fallback-svc"),
+	} {
+		backendNode, ok := resourceModel.Backends[backendID]
+		if !ok {
+			t.Fatalf("Backend %v not found in resourceModel", backendID)
+		}
+		policy, ok := backendNode.EffectivePolicies[gwID]["HealthCheckPolicy.foo.com"]
+		if !ok {
+			t.Errorf("HealthCheckPolicy.foo.com not found in effective policies of Backend %v for Gateway %v: %v", backendID, gwID, backendNode.EffectivePolicies)
+			continue
+		}
+		got, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Unexpected diff in effective policy of Backend %v; got=%v, want=%v;\ndiff (-want +got)=\n%v", backendID, got, want, diff)
+		}
+	}
+}
+
+// TestResourceModel_EffectivePoliciesPerGatewayClass tests that an HTTPRoute
+// attached to Gateways of different GatewayClasses keeps a separate inheritance
+// chain for each Gateway, even though the HTTPRoute-namespace and HTTPRoute
+// policies merged into them are shared.
+func TestResourceModel_EffectivePoliciesPerGatewayClass(t *testing.T) {
+	healthCheckPolicy := func(name, namespace, targetKind, targetName string, defaults map[string]interface{}) *unstructured.Unstructured {
+		metadata := map[string]interface{}{"name": name}
+		if namespace != "" {
+			metadata["namespace"] = namespace
+		}
+		targetRef := map[string]interface{}{
+			"kind": targetKind,
+			"name": targetName,
+		}
+		if targetKind == "GatewayClass" || targetKind == "HTTPRoute" {
+			targetRef["group"] = "gateway.networking.k8s.io"
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "HealthCheckPolicy",
+				"metadata":   metadata,
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "internal-gatewayclass",
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "external-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "internal-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "internal-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "external-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "external-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "internal-gateway"},
+						{Name: "external-gateway"},
+					},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "healthcheckpolicies.foo.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "inherited",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "healthcheckpolicies",
+					Kind:   "HealthCheckPolicy",
+				},
+			},
+		},
+		healthCheckPolicy("health-check-internal", "", "GatewayClass", "internal-gatewayclass", map[string]interface{}{
+			"timeout": int64(5),
+			"retries": int64(1),
+		}),
+		healthCheckPolicy("health-check-external", "", "GatewayClass", "external-gatewayclass", map[string]interface{}{
+			"timeout": int64(30),
+			"tls":     true,
+		}),
+		healthCheckPolicy("health-check-namespace", "default", "Namespace", "default", map[string]interface{}{
+			"interval": int64(10),
+		}),
+		healthCheckPolicy("health-check-httproute", "default", "HTTPRoute", "shared-httproute", map[string]interface{}{
+			"path": "/healthz",
+		}),
+		healthCheckPolicy("health-check-service", "default", "Service", "foo-svc", map[string]interface{}{
+			"port": int64(8080),
+		}),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "shared-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/shared-httproute not found in resourceModel")
+	}
+	backendNode, ok := resourceModel.Backends[BackendIDForService("default", "foo-svc")]
+	if !ok {
+		t.Fatalf("Backend default/foo-svc not found in resourceModel")
+	}
+
+	effectiveSpec := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[string]interface{} {
+		policy, ok := policies["HealthCheckPolicy.foo.com"]
+		if !ok {
+			return nil
+		}
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		return spec
+	}
+
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	internalID := GatewayID("default", "internal-gateway")
+	externalID := GatewayID("default", "external-gateway")
+	wantHTTPRoute := map[gatewayID]map[string]interface{}{
+		internalID: {"timeout": float64(5), "retries": float64(1), "interval": float64(10), "path": "/healthz"},
+		externalID: {"timeout": float64(30), "tls": true, "interval": float64(10), "path": "/healthz"},
+	}
+	gotHTTPRoute := make(map[gatewayID]map[string]interface{})
+	for gatewayID, policies := range httpRouteNode.EffectivePolicies {
+		gotHTTPRoute[gatewayID] = effectiveSpec(policies)
+	}
+	if diff := cmp.Diff(wantHTTPRoute, gotHTTPRoute); diff != "" {
+		t.Errorf("Unexpected diff in effective policies of HTTPRoute per Gateway; diff (-want +got)=\n%v", diff)
+	}
+
+	wantBackend := map[gatewayID]map[string]interface{}{
+		internalID: {"timeout": float64(5), "retries": float64(1), "interval": float64(10), "path": "/healthz", "port": float64(8080)},
+		externalID: {"timeout": float64(30), "tls": true, "interval": float64(10), "path": "/healthz", "port": float64(8080)},
+	}
+	gotBackend := make(map[gatewayID]map[string]interface{})
+	for gatewayID, policies := range backendNode.EffectivePolicies {
+		gotBackend[gatewayID] = effectiveSpec(policies)
+	}
+	if diff := cmp.Diff(wantBackend, gotBackend); diff != "" {
+		t.Errorf("Unexpected diff in effective policies of Backend per Gateway; diff (-want +got)=\n%v", diff)
+	}
+}
+
+// TestResourceModel_NamespacePoliciesStableAcrossGateways tests that the
+// policies of the namespace of an HTTPRoute contribute identically to its
+// effective policies for each Gateway it attaches to, while the contributions
+// of the hierarchy of each Gateway differ.
+func TestResourceModel_NamespacePoliciesStableAcrossGateways(t *testing.T) {
+	policy := func(kind, name, namespace, targetKind, targetName string, defaults map[string]interface{}) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"kind": targetKind,
+			"name": targetName,
+		}
+		if targetKind == "Gateway" {
+			targetRef["group"] = "gateway.networking.k8s.io"
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       kind,
+				"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+	policyCRD := func(plural, kind string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   plural + ".foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	gateway := func(name, namespace string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		}
+	}
+
+	// gateway-a shares the namespace of the HTTPRoute, so the policies of that
+	// namespace are also part of the hierarchy of gateway-a.
+	objects := []runtime.Object{
+		common.NamespaceForTest("team"),
+		common.NamespaceForTest("infra"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		gateway("gateway-a", "team"),
+		gateway("gateway-b", "infra"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "team"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "gateway-a"},
+						{Name: "gateway-b", Namespace: common.PtrTo(gatewayv1.Namespace("infra"))},
+					},
+				},
+			},
+		},
+
+		policyCRD("healthcheckpolicies", "HealthCheckPolicy"),
+		policyCRD("timeoutpolicies", "TimeoutPolicy"),
+		policy("HealthCheckPolicy", "health-check-gateway-a", "team", "Gateway", "gateway-a", map[string]interface{}{"timeout": int64(5)}),
+		policy("HealthCheckPolicy", "health-check-gateway-b", "infra", "Gateway", "gateway-b", map[string]interface{}{"timeout": int64(30)}),
+		policy("HealthCheckPolicy", "health-check-infra", "infra", "Namespace", "infra", map[string]interface{}{"retries": int64(3)}),
+		policy("HealthCheckPolicy", "health-check-team", "team", "Namespace", "team", map[string]interface{}{"interval": int64(10)}),
+		policy("TimeoutPolicy", "timeout-team", "team", "Namespace", "team", map[string]interface{}{"seconds": int64(60)}),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("team", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute team/foo-httproute not found in resourceModel")
+	}
+
+	effectiveSpecs := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[policymanager.PolicyCrdID]map[string]interface{} {
+		result := make(map[policymanager.PolicyCrdID]map[string]interface{})
+		for policyCrdID, policy := range policies {
+			spec, err := policy.EffectiveSpec()
+			if err != nil {
+				t.Fatalf("Failed to get EffectiveSpec: %v", err)
+			}
+			result[policyCrdID] = spec
+		}
+		return result
+	}
+
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	gatewayAID := GatewayID("team", "gateway-a")
+	gatewayBID := GatewayID("infra", "gateway-b")
+	want := map[gatewayID]map[policymanager.PolicyCrdID]map[string]interface{}{
+		gatewayAID: {
+			"HealthCheckPolicy.foo.com": {"timeout": float64(5), "interval": float64(10)},
+			"TimeoutPolicy.foo.com":     {"seconds": float64(60)},
+		},
+		gatewayBID: {
+			"HealthCheckPolicy.foo.com": {"timeout": float64(30), "retries": float64(3), "interval": float64(10)},
+			"TimeoutPolicy.foo.com":     {"seconds": float64(60)},
+		},
+	}
+	got := make(map[gatewayID]map[policymanager.PolicyCrdID]map[string]interface{})
+	for gatewayID, policies := range httpRouteNode.EffectivePolicies {
+		got[gatewayID] = effectiveSpecs(policies)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in effective policies of HTTPRoute per Gateway; diff (-want +got)=\n%v", diff)
+	}
+
+	// The fields contributed by the namespace of the HTTPRoute are the same for
+	// both Gateways.
+	for _, field := range []struct {
+		policyCrdID policymanager.PolicyCrdID
+		name        string
+	}{
+		{"HealthCheckPolicy.foo.com", "interval"},
+		{"TimeoutPolicy.foo.com", "seconds"},
+	} {
+		a := got[gatewayAID][field.policyCrdID][field.name]
+		b := got[gatewayBID][field.policyCrdID][field.name]
+		if !cmp.Equal(a, b) {
+			t.Errorf("Field %v of %v contributed by the namespace of the HTTPRoute differs across Gateways; %v=%v, %v=%v", field.name, field.policyCrdID, gatewayAID, a, gatewayBID, b)
+		}
+	}
+}
+
+// TestResourceModel_PolicyTargetRefDefaultsToPolicyNamespace tests that a
+// policy whose targetRef omits the namespace, or sets it to the empty string,
+// attaches to the target within the namespace of the policy.
+func TestResourceModel_PolicyTargetRefDefaultsToPolicyNamespace(t *testing.T) {
+	gateway := func(namespace string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: namespace},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		}
+	}
+	timeoutPolicy := func(name string, targetRef map[string]interface{}) *unstructured.Unstructured {
+		targetRef["group"] = "gateway.networking.k8s.io"
+		targetRef["kind"] = "Gateway"
+		targetRef["name"] = "foo-gateway"
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "foo",
+				},
+				"spec": map[string]interface{}{
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.bar.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "direct"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		gateway("foo"),
+		gateway("default"),
+		timeoutPolicy("timeout-policy", map[string]interface{}{}),
+		timeoutPolicy("timeout-policy-empty-namespace", map[string]interface{}{"namespace": ""}),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Namespace: metav1.NamespaceAll, Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	for _, name := range []string{"timeout-policy", "timeout-policy-empty-namespace"} {
+		policyID := PolicyID("bar.com", "TimeoutPolicy", "foo", name)
+		policyNode, ok := resourceModel.Policies[policyID]
+		if !ok {
+			t.Errorf("Policy %v not found in resourceModel", policyID)
+			continue
+		}
+		if policyNode.Gateway == nil {
+			t.Errorf("Policy %v is not attached to a Gateway", policyID)
+			continue
+		}
+		if got, want := policyNode.Gateway.ID(), GatewayID("foo", "foo-gateway"); got != want {
+			t.Errorf("Policy %v is attached to Gateway %v; want %v", policyID, got, want)
+		}
+		if _, ok := resourceModel.Gateways[GatewayID("default", "foo-gateway")].Policies[policyID]; ok {
+			t.Errorf("Policy %v is attached to Gateway default/foo-gateway; want it attached only to foo/foo-gateway", policyID)
+		}
+	}
+}
+
+func TestResourceModel_SkipDeletingPolicies(t *testing.T) {
+	timeoutPolicy := func(name string, metadata map[string]interface{}) *unstructured.Unstructured {
+		metadata["name"] = name
+		metadata["namespace"] = "default"
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata":   metadata,
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		}
+	}
+	objects := []runtime.Object{
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.bar.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "direct"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		timeoutPolicy("timeout-policy", map[string]interface{}{}),
+		// The policy is only kept around by its finalizer.
+		timeoutPolicy("deleting-timeout-policy", map[string]interface{}{
+			"deletionTimestamp": "2024-01-01T00:00:00Z",
+			"finalizers":        []interface{}{"bar.com/cleanup"},
+		}),
+	}
+	deletingPolicyID := PolicyID("bar.com", "TimeoutPolicy", "default", "deleting-timeout-policy")
+	gatewayID := GatewayID("default", "foo-gateway")
+
+	for _, skip := range []bool{false, true} {
+		params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+		discoverer := Discoverer{
+			K8sClients:           params.K8sClients,
+			PolicyManager:        params.PolicyManager,
+			SkipDeletingPolicies: skip,
+		}
+		resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Namespace: "default", Labels: labels.Everything()})
+		if err != nil {
+			t.Fatalf("Failed to construct resourceModel: %v", err)
+		}
+
+		if _, ok := resourceModel.Policies[PolicyID("bar.com", "TimeoutPolicy", "default", "timeout-policy")]; !ok {
+			t.Errorf("SkipDeletingPolicies=%v: policy default/timeout-policy not found in resourceModel", skip)
+		}
+		_, inModel := resourceModel.Policies[deletingPolicyID]
+		_, attached := resourceModel.Gateways[gatewayID].Policies[deletingPolicyID]
+		if inModel != !skip || attached != !skip {
+			t.Errorf("SkipDeletingPolicies=%v: policy default/deleting-timeout-policy in resourceModel=%v, attached to Gateway=%v; want %v", skip, inModel, attached, !skip)
+		}
+
+		var gotDeleting []string
+		for _, policy := range resourceModel.DeletingPolicies {
+			gotDeleting = append(gotDeleting, policy.Name())
+		}
+		var wantDeleting []string
+		if skip {
+			wantDeleting = []string{"TimeoutPolicy.bar.com/default/deleting-timeout-policy"}
+		}
+		if diff := cmp.Diff(wantDeleting, gotDeleting); diff != "" {
+			t.Errorf("SkipDeletingPolicies=%v: unexpected diff in DeletingPolicies (-want +got):\n%v", skip, diff)
+		}
+	}
+}
+
+func TestResourceModel_PolicyKinds(t *testing.T) {
+	policyCRD := func(plural, kind string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   plural + ".bar.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	policy := func(kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"default": map[string]interface{}{"enabled": true},
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "Gateway",
+						"name":  "foo-gateway",
+					},
+				},
+			},
+		}
+	}
+	objects := []runtime.Object{
+		policyCRD("timeoutpolicies", "TimeoutPolicy"),
+		policyCRD("retrypolicies", "RetryPolicy"),
+		policyCRD("healthcheckpolicies", "HealthCheckPolicy"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		common.NamespaceForTest("default"),
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		policy("TimeoutPolicy", "timeout-policy"),
+		policy("RetryPolicy", "retry-policy"),
+		policy("HealthCheckPolicy", "health-check-policy"),
+	}
+
+	k8sClients := common.MustClientsForTest(t, objects...)
+	policyManager := policymanager.New(k8sClients.DC)
+	policyManager.SetPolicyKinds("TimeoutPolicy.bar.com")
+	if err := policyManager.Init(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize PolicyManager: %v", err)
+	}
+	if got := len(policyManager.GetCRDs()); got != 1 {
+		t.Errorf("len(GetCRDs()) = %v, want 1", got)
+	}
+	discoverer := Discoverer{
+		K8sClients:    k8sClients,
+		PolicyManager: policyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForGateway(context.Background(), Filter{Namespace: "default", Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	var gotPolicies []string
+	for _, policyNode := range resourceModel.Policies {
+		gotPolicies = append(gotPolicies, policyNode.Policy.Name())
+	}
+	wantPolicies := []string{"TimeoutPolicy.bar.com/default/timeout-policy"}
+	if diff := cmp.Diff(wantPolicies, gotPolicies); diff != "" {
+		t.Errorf("Unexpected diff in policies of resourceModel (-want +got):\n%v", diff)
+	}
+
+	gatewayNode, ok := resourceModel.Gateways[GatewayID("default", "foo-gateway")]
+	if !ok {
+		t.Fatalf("Gateway default/foo-gateway not found in resourceModel")
+	}
+	var gotEffective []policymanager.PolicyCrdID
+	for policyCrdID := range gatewayNode.EffectivePolicies {
+		gotEffective = append(gotEffective, policyCrdID)
+	}
+	wantEffective := []policymanager.PolicyCrdID{"TimeoutPolicy.bar.com"}
+	if diff := cmp.Diff(wantEffective, gotEffective); diff != "" {
+		t.Errorf("Unexpected diff in kinds of effective policies of Gateway (-want +got):\n%v", diff)
+	}
+
+	policyManager = policymanager.New(k8sClients.DC)
+	policyManager.SetPolicyKinds("UnknownPolicy.bar.com")
+	if err := policyManager.Init(context.Background()); err == nil {
+		t.Errorf("Init() with unknown policy kind returned no error")
+	}
+}
+
+// TestResourceModel_MeshRouteEffectivePolicies tests that the effective
+// policies of a mesh route only merge the policies of the HTTPRoute-namespace
+// and the HTTPRoute, even when the HTTPRoute is also attached to a Gateway.
+func TestResourceModel_MeshRouteEffectivePolicies(t *testing.T) {
+	policy := func(name, targetKind, targetName string, defaults map[string]interface{}) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"kind": targetKind,
+			"name": targetName,
+		}
+		if targetKind != "Namespace" {
+			targetRef["group"] = "gateway.networking.k8s.io"
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "foo-gatewayclass"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "foo-gateway"},
+						{
+							Group: common.PtrTo(gatewayv1.Group("")),
+							Kind:  common.PtrTo(gatewayv1.Kind("Service")),
+							Name:  "foo-svc",
+						},
+					},
+				},
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		policy("timeout-gateway", "Gateway", "foo-gateway", map[string]interface{}{"idle": int64(300)}),
+		policy("timeout-namespace", "Namespace", "default", map[string]interface{}{"condition": "path=/abc", "seconds": int64(30)}),
+		policy("timeout-httproute", "HTTPRoute", "foo-httproute", map[string]interface{}{"seconds": int64(60)}),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("default", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute default/foo-httproute not found in resourceModel")
+	}
+
+	effectiveSpec := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[string]interface{} {
+		policy, ok := policies["TimeoutPolicy.foo.com"]
+		if !ok {
+			t.Fatalf("TimeoutPolicy.foo.com not found in effective policies %v", policies)
+		}
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		return spec
+	}
+
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	wantMesh := map[string]interface{}{"condition": "path=/abc", "seconds": float64(60)}
+	if diff := cmp.Diff(wantMesh, effectiveSpec(httpRouteNode.MeshEffectivePolicies)); diff != "" {
+		t.Errorf("Unexpected diff in MeshEffectivePolicies; diff (-want +got)=\n%v", diff)
+	}
+
+	// The Gateway hierarchy only contributes to the result keyed by the
+	// Gateway.
+	gatewayID := GatewayID("default", "foo-gateway")
+	if len(httpRouteNode.EffectivePolicies) != 1 {
+		t.Fatalf("len(EffectivePolicies)=%v; want 1", len(httpRouteNode.EffectivePolicies))
+	}
+	wantGateway := map[string]interface{}{"condition": "path=/abc", "seconds": float64(60), "idle": float64(300)}
+	if diff := cmp.Diff(wantGateway, effectiveSpec(httpRouteNode.EffectivePolicies[gatewayID])); diff != "" {
+		t.Errorf("Unexpected diff in EffectivePolicies for %v; diff (-want +got)=\n%v", gatewayID, diff)
+	}
+}
+
+// TestResourceModel_CrossNamespacePolicyRules tests that policies of a kind
+// whose MergeRules disable cross namespace propagation do not reach an
+// HTTPRoute in another namespace than its Gateway, even after being merged
+// along the GatewayClass and Gateway levels.
+func TestResourceModel_CrossNamespacePolicyRules(t *testing.T) {
+	policyCRD := func(kind, plural string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   plural + ".foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	policy := func(kind, name, targetKind, targetName string, defaults map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       kind,
+				"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+				"spec": map[string]interface{}{
+					"default": defaults,
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("other"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+					AllowedRoutes: &gatewayv1.AllowedRoutes{
+						Namespaces: &gatewayv1.RouteNamespaces{From: common.PtrTo(gatewayv1.NamespacesFromAll)},
+					},
+				}},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "other"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Name:      "foo-gateway",
+						Namespace: common.PtrTo(gatewayv1.Namespace("default")),
+					}},
+				},
+			},
+		},
+
+		policyCRD("TimeoutPolicy", "timeoutpolicies"),
+		policyCRD("HealthCheckPolicy", "healthcheckpolicies"),
+		policy("TimeoutPolicy", "timeout-gatewayclass", "GatewayClass", "foo-gatewayclass", map[string]interface{}{"connect": int64(5)}),
+		policy("TimeoutPolicy", "timeout-gateway", "Gateway", "foo-gateway", map[string]interface{}{"idle": int64(300)}),
+		policy("HealthCheckPolicy", "health-check-gatewayclass", "GatewayClass", "foo-gatewayclass", map[string]interface{}{"interval": int64(10)}),
+	}
+
+	k8sClients := common.MustClientsForTest(t, objects...)
+	policyManager := policymanager.New(k8sClients.DC)
+	policyManager.SetPolicyRules(policymanager.PolicyRules{
+		"TimeoutPolicy.foo.com": {CrossNamespace: common.PtrTo(false)},
+	})
+	if err := policyManager.Init(context.Background()); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	discoverer := Discoverer{
+		K8sClients:    k8sClients,
+		PolicyManager: policyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	kinds := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) []policymanager.PolicyCrdID {
+		var result []policymanager.PolicyCrdID
+		for policyCrdID := range policies {
+			result = append(result, policyCrdID)
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	gwID := GatewayID("default", "foo-gateway")
+	gatewayNode, ok := resourceModel.Gateways[gwID]
+	if !ok {
+		t.Fatalf("Gateway default/foo-gateway not found in resourceModel")
+	}
+	if diff := cmp.Diff([]policymanager.PolicyCrdID{"HealthCheckPolicy.foo.com", "TimeoutPolicy.foo.com"}, kinds(gatewayNode.EffectivePolicies)); diff != "" {
+		t.Errorf("Unexpected diff in kinds of effective policies of the Gateway; diff (-want +got)=\n%v", diff)
+	}
+
+	httpRouteNode, ok := resourceModel.HTTPRoutes[HTTPRouteID("other", "foo-httproute")]
+	if !ok {
+		t.Fatalf("HTTPRoute other/foo-httproute not found in resourceModel")
+	}
+	// The merged TimeoutPolicy keeps the MergeRules of its kind, so it is not
+	// inherited across namespaces.
+	if diff := cmp.Diff([]policymanager.PolicyCrdID{"HealthCheckPolicy.foo.com"}, kinds(httpRouteNode.EffectivePolicies[gwID])); diff != "" {
+		t.Errorf("Unexpected diff in kinds of effective policies of the HTTPRoute; diff (-want +got)=\n%v", diff)
+	}
+}
+
+func TestResourceModel_PolicyLayersForHTTPRoute(t *testing.T) {
+	policyCRD := func(kind, plural string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   plural + ".foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   kind,
+				},
+			},
+		}
+	}
+	policy := func(kind, namespace, name, targetKind, targetName, sectionName string, defaults map[string]interface{}) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  targetKind,
+			"name":  targetName,
+		}
+		if sectionName != "" {
+			targetRef["sectionName"] = sectionName
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       kind,
+				"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+				"spec": map[string]interface{}{
+					"default":   defaults,
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		common.NamespaceForTest("other"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+					AllowedRoutes: &gatewayv1.AllowedRoutes{
+						Namespaces: &gatewayv1.RouteNamespaces{From: common.PtrTo(gatewayv1.NamespacesFromAll)},
+					},
+				}},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "other"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Name:      "foo-gateway",
+						Namespace: common.PtrTo(gatewayv1.Namespace("default")),
+					}},
+				},
+			},
+		},
+
+		policyCRD("TimeoutPolicy", "timeoutpolicies"),
+		policyCRD("HealthCheckPolicy", "healthcheckpolicies"),
+		policy("HealthCheckPolicy", "default", "health-check-gatewayclass", "GatewayClass", "foo-gatewayclass", "", map[string]interface{}{"interval": int64(10)}),
+		// Scoped to a listener, so it does not contribute to the Gateway as a
+		// whole.
+		policy("HealthCheckPolicy", "default", "health-check-listener", "Gateway", "foo-gateway", "http", map[string]interface{}{"interval": int64(5)}),
+		// Not inherited across namespaces, so the HTTPRoute does not see it.
+		policy("TimeoutPolicy", "default", "timeout-gateway", "Gateway", "foo-gateway", "", map[string]interface{}{"idle": int64(300)}),
+		policy("TimeoutPolicy", "other", "timeout-httproute", "HTTPRoute", "foo-httproute", "", map[string]interface{}{"idle": int64(60)}),
+	}
+
+	k8sClients := common.MustClientsForTest(t, objects...)
+	policyManager := policymanager.New(k8sClients.DC)
+	policyManager.SetPolicyRules(policymanager.PolicyRules{
+		"TimeoutPolicy.foo.com": {CrossNamespace: common.PtrTo(false)},
+	})
+	if err := policyManager.Init(context.Background()); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	discoverer := Discoverer{
+		K8sClients:    k8sClients,
+		PolicyManager: policyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForHTTPRoute(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	layers, err := resourceModel.PolicyLayersForHTTPRoute(HTTPRouteID("other", "foo-httproute"), GatewayID("default", "foo-gateway"))
+	if err != nil {
+		t.Fatalf("PolicyLayersForHTTPRoute() failed: %v", err)
+	}
+
+	want := map[policymanager.PolicyCrdID][]policymanager.LevelDelta{
+		"HealthCheckPolicy.foo.com": {
+			{
+				Level: "GatewayClass foo-gatewayclass",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "HealthCheckPolicy", Name: "health-check-gatewayclass", Namespace: "default"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "interval", Value: float64(10)},
+				},
+			},
+		},
+		"TimeoutPolicy.foo.com": {
+			{
+				Level: "Gateway default/foo-gateway",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "TimeoutPolicy", Name: "timeout-gateway", Namespace: "default"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "idle", Value: float64(300)},
+				},
+			},
+			{
+				Level: "HTTPRoute other/foo-httproute",
+				Policies: []policymanager.ObjRef{
+					{Group: "foo.com", Kind: "TimeoutPolicy", Name: "timeout-httproute", Namespace: "other"},
+				},
+				Changes: []policymanager.FieldChange{
+					{Path: "idle", Value: float64(60)},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, layers); diff != "" {
+		t.Errorf("Unexpected diff in PolicyLayersForHTTPRoute(); got=%v, want=%v;\ndiff (-want +got)=\n%v", layers, want, diff)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"sort"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+)
+
+// RouteTrace is the complete path of an HTTPRoute through one of the Gateways
+// it is attached to: from the GatewayClass of the Gateway down to the Backends
+// of the HTTPRoute, along with the effective policies at each level.
+type RouteTrace struct {
+	// GatewayClass is the GatewayClass of the Gateway. It is nil if the
+	// GatewayClass was not found.
+	GatewayClass *GatewayClassNode
+	// GatewayClassEffectivePolicies are the policies of the GatewayClass,
+	// merged by their kind.
+	GatewayClassEffectivePolicies map[policymanager.PolicyCrdID]policymanager.Policy
+
+	Gateway *GatewayNode
+	// Listeners are the listeners of the Gateway which the HTTPRoute attaches
+	// to.
+	Listeners []gatewayv1.Listener
+	// ListenerEffectivePolicies are the effective policies of those Listeners
+	// which policies target through their sectionName.
+	ListenerEffectivePolicies map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy
+
+	HTTPRoute *HTTPRouteNode
+	// HTTPRouteEffectivePolicies are the effective policies of the HTTPRoute in
+	// the context of the Gateway.
+	HTTPRouteEffectivePolicies map[policymanager.PolicyCrdID]policymanager.Policy
+	// HTTPRouteListenerEffectivePolicies are the effective policies of the
+	// HTTPRoute through each Listener of the Gateway targeted by policies.
+	HTTPRouteListenerEffectivePolicies map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy
+
+	// Backends are the Backends of the HTTPRoute, sorted by their NodeID.
+	Backends []BackendTrace
+}
+
+// BackendTrace is a Backend within a RouteTrace.
+type BackendTrace struct {
+	Backend *BackendNode
+	// EffectivePolicies are the effective policies of the Backend in the
+	// context of the Gateway of the RouteTrace.
+	EffectivePolicies map[policymanager.PolicyCrdID]policymanager.Policy
+	// ListenerEffectivePolicies are the effective policies of the Backend
+	// through each Listener of the Gateway targeted by policies.
+	ListenerEffectivePolicies map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy
+}
+
+// RouteTraces returns a RouteTrace for each Gateway the HTTPRoute is attached
+// to, sorted by the namespace/name of the Gateway.
+func (rm *ResourceModel) RouteTraces(httpRouteID httpRouteID) ([]RouteTrace, error) {
+	httpRouteNode, ok := rm.HTTPRoutes[httpRouteID]
+	if !ok {
+		return nil, fmt.Errorf("failed to find HTTPRoute %v/%v in ResourceModel", httpRouteID.Namespace, httpRouteID.Name)
+	}
+
+	var backendNodes []*BackendNode
+	for _, backendNode := range httpRouteNode.Backends {
+		backendNodes = append(backendNodes, backendNode)
+	}
+	sort.Slice(backendNodes, func(i, j int) bool {
+		return backendNodes[i].NodeID() < backendNodes[j].NodeID()
+	})
+
+	var result []RouteTrace
+	for _, gatewayID := range sortedGatewayIDs(httpRouteNode.Gateways) {
+		gatewayNode := httpRouteNode.Gateways[gatewayID]
+		trace := RouteTrace{
+			GatewayClass:                       gatewayNode.GatewayClass,
+			Gateway:                            gatewayNode,
+			HTTPRoute:                          httpRouteNode,
+			HTTPRouteEffectivePolicies:         httpRouteNode.EffectivePolicies[gatewayID],
+			HTTPRouteListenerEffectivePolicies: httpRouteNode.ListenerEffectivePolicies[gatewayID],
+		}
+		if gatewayNode.GatewayClass != nil {
+			var err error
+			trace.GatewayClassEffectivePolicies, err = rm.mergeRules.MergePoliciesOfSimilarKind(convertPoliciesMapToSlice(gatewayNode.GatewayClass.Policies))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, listener := range gatewayNode.Gateway.Spec.Listeners {
+			if !httpRouteNode.AttachesToListener(gatewayID, listener.Name) {
+				continue
+			}
+			trace.Listeners = append(trace.Listeners, listener)
+			if policies, ok := gatewayNode.ListenerEffectivePolicies[listener.Name]; ok {
+				if trace.ListenerEffectivePolicies == nil {
+					trace.ListenerEffectivePolicies = make(map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy)
+				}
+				trace.ListenerEffectivePolicies[listener.Name] = policies
+			}
+		}
+		for _, backendNode := range backendNodes {
+			trace.Backends = append(trace.Backends, BackendTrace{
+				Backend:                   backendNode,
+				EffectivePolicies:         backendNode.EffectivePolicies[gatewayID],
+				ListenerEffectivePolicies: backendNode.ListenerEffectivePolicies[gatewayID],
+			})
+		}
+		result = append(result, trace)
+	}
+	return result, nil
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_RouteTraces(t *testing.T) {
+	policy := func(name, targetGroup, targetKind, targetName string, defaults map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+				"spec": map[string]interface{}{
+					"default": defaults,
+					"targetRef": map[string]interface{}{
+						"group": targetGroup,
+						"kind":  targetKind,
+						"name":  targetName,
+					},
+				},
+			},
+		}
+	}
+	gateway := func(name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.net/gateway-controller"},
+		},
+		gateway("foo-gateway"),
+		gateway("bar-gateway"),
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "foo-gateway"},
+						{Name: "bar-gateway", SectionName: common.PtrTo(gatewayv1.SectionName("https"))},
+					},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Kind: common.PtrTo(gatewayv1.Kind("Service")),
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		policy("timeout-gatewayclass", "gateway.networking.k8s.io", "GatewayClass", "foo-gatewayclass", map[string]interface{}{"connect": int64(5)}),
+		policy("timeout-gateway", "gateway.networking.k8s.io", "Gateway", "foo-gateway", map[string]interface{}{"idle": int64(300)}),
+		policy("timeout-httproute", "gateway.networking.k8s.io", "HTTPRoute", "foo-httproute", map[string]interface{}{"request": int64(30)}),
+		policy("timeout-backend", "", "Service", "foo-svc", map[string]interface{}{"backend": int64(10)}),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	traces, err := resourceModel.RouteTraces(HTTPRouteID("default", "foo-httproute"))
+	if err != nil {
+		t.Fatalf("RouteTraces() returned err=%v", err)
+	}
+
+	effectiveSpec := func(policies map[policymanager.PolicyCrdID]policymanager.Policy) map[string]interface{} {
+		policy, ok := policies["TimeoutPolicy.foo.com"]
+		if !ok {
+			t.Fatalf("TimeoutPolicy.foo.com not found in effective policies %v", policies)
+		}
+		spec, err := policy.EffectiveSpec()
+		if err != nil {
+			t.Fatalf("Failed to get EffectiveSpec: %v", err)
+		}
+		return spec
+	}
+
+	// Traces are sorted by Gateway. Only foo-gateway has a policy of its own.
+	// Merged policies are round-tripped through JSON, hence numbers are float64.
+	wantTraces := []struct {
+		gateway                                 string
+		listeners                               []gatewayv1.SectionName
+		gatewaySpec, httpRouteSpec, backendSpec map[string]interface{}
+	}{
+		{
+			gateway:       "bar-gateway",
+			listeners:     []gatewayv1.SectionName{"https"},
+			gatewaySpec:   map[string]interface{}{"connect": float64(5)},
+			httpRouteSpec: map[string]interface{}{"connect": float64(5), "request": float64(30)},
+			backendSpec:   map[string]interface{}{"connect": float64(5), "request": float64(30), "backend": float64(10)},
+		},
+		{
+			gateway:       "foo-gateway",
+			listeners:     []gatewayv1.SectionName{"http", "https"},
+			gatewaySpec:   map[string]interface{}{"connect": float64(5), "idle": float64(300)},
+			httpRouteSpec: map[string]interface{}{"connect": float64(5), "idle": float64(300), "request": float64(30)},
+			backendSpec:   map[string]interface{}{"connect": float64(5), "idle": float64(300), "request": float64(30), "backend": float64(10)},
+		},
+	}
+	if len(traces) != len(wantTraces) {
+		t.Fatalf("len(RouteTraces())=%v; want %v", len(traces), len(wantTraces))
+	}
+	for i, want := range wantTraces {
+		trace := traces[i]
+		if got := trace.Gateway.Gateway.GetName(); got != want.gateway {
+			t.Fatalf("traces[%d].Gateway=%v; want %v", i, got, want.gateway)
+		}
+
+		// GatewayClass
+		if trace.GatewayClass == nil || trace.GatewayClass.GatewayClass.GetName() != "foo-gatewayclass" {
+			t.Errorf("traces[%d].GatewayClass=%v; want foo-gatewayclass", i, trace.GatewayClass)
+		}
+		if diff := cmp.Diff(map[string]interface{}{"connect": float64(5)}, effectiveSpec(trace.GatewayClassEffectivePolicies)); diff != "" {
+			t.Errorf("Unexpected diff in traces[%d].GatewayClassEffectivePolicies; diff (-want +got)=\n%v", i, diff)
+		}
+
+		// Gateway
+		var gotListeners []gatewayv1.SectionName
+		for _, listener := range trace.Listeners {
+			gotListeners = append(gotListeners, listener.Name)
+		}
+		if diff := cmp.Diff(want.listeners, gotListeners); diff != "" {
+			t.Errorf("Unexpected diff in traces[%d].Listeners; diff (-want +got)=\n%v", i, diff)
+		}
+		if diff := cmp.Diff(want.gatewaySpec, effectiveSpec(trace.Gateway.EffectivePolicies)); diff != "" {
+			t.Errorf("Unexpected diff in effective policies of traces[%d].Gateway; diff (-want +got)=\n%v", i, diff)
+		}
+
+		// HTTPRoute
+		if trace.HTTPRoute.ID() != HTTPRouteID("default", "foo-httproute") {
+			t.Errorf("traces[%d].HTTPRoute=%v; want default/foo-httproute", i, trace.HTTPRoute.ID())
+		}
+		if diff := cmp.Diff(want.httpRouteSpec, effectiveSpec(trace.HTTPRouteEffectivePolicies)); diff != "" {
+			t.Errorf("Unexpected diff in traces[%d].HTTPRouteEffectivePolicies; diff (-want +got)=\n%v", i, diff)
+		}
+
+		// Backends
+		if len(trace.Backends) != 1 || trace.Backends[0].Backend.ID() != BackendIDForService("default", "foo-svc") {
+			t.Fatalf("traces[%d].Backends=%v; want default/foo-svc", i, trace.Backends)
+		}
+		if diff := cmp.Diff(want.backendSpec, effectiveSpec(trace.Backends[0].EffectivePolicies)); diff != "" {
+			t.Errorf("Unexpected diff in effective policies of traces[%d].Backends[0]; diff (-want +got)=\n%v", i, diff)
+		}
+	}
+
+	if _, err := resourceModel.RouteTraces(HTTPRouteID("default", "missing-httproute")); err == nil {
+		t.Errorf("RouteTraces() for a missing HTTPRoute returned err=nil; want error")
+	}
+}
+
+func TestResourceModel_RouteTraces_ListenerPolicies(t *testing.T) {
+	policy := func(name, sectionName string, idle int64) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "Gateway",
+			"name":  "foo-gateway",
+		}
+		if sectionName != "" {
+			targetRef["sectionName"] = sectionName
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+				"spec": map[string]interface{}{
+					"default":   map[string]interface{}{"idle": idle},
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gatewayclass"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.net/gateway-controller"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-gateway", Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-httproute", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: "foo-svc",
+								Port: common.PtrTo(gatewayv1.PortNumber(80)),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "timeoutpolicies.foo.com",
+				Labels: map[string]string{gatewayv1alpha2.PolicyLabelKey: "inherited"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "foo.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		policy("timeout-gateway", "", 300),
+		policy("timeout-https", "https", 60),
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForRequests(context.Background(), Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	traces, err := resourceModel.RouteTraces(HTTPRouteID("default", "foo-httproute"))
+	if err != nil {
+		t.Fatalf("RouteTraces() returned err=%v", err)
+	}
+	if len(traces) != 1 || len(traces[0].Backends) != 1 {
+		t.Fatalf("RouteTraces()=%v; want a single trace with a single Backend", traces)
+	}
+	trace := traces[0]
+
+	idleByListener := func(policiesByListener map[gatewayv1.SectionName]map[policymanager.PolicyCrdID]policymanager.Policy) map[gatewayv1.SectionName]interface{} {
+		result := make(map[gatewayv1.SectionName]interface{})
+		for sectionName, policies := range policiesByListener {
+			spec, err := policies["TimeoutPolicy.foo.com"].EffectiveSpec()
+			if err != nil {
+				t.Fatalf("Failed to get EffectiveSpec: %v", err)
+			}
+			result[sectionName] = spec["idle"]
+		}
+		return result
+	}
+
+	// Only the https listener is targeted by a policy, which applies to the
+	// Gateway, the HTTPRoute and the Backend through that listener.
+	want := map[gatewayv1.SectionName]interface{}{"https": float64(60)}
+	if diff := cmp.Diff(want, idleByListener(trace.ListenerEffectivePolicies)); diff != "" {
+		t.Errorf("Unexpected diff in ListenerEffectivePolicies; diff (-want +got)=\n%v", diff)
+	}
+	if diff := cmp.Diff(want, idleByListener(trace.HTTPRouteListenerEffectivePolicies)); diff != "" {
+		t.Errorf("Unexpected diff in HTTPRouteListenerEffectivePolicies; diff (-want +got)=\n%v", diff)
+	}
+	if diff := cmp.Diff(want, idleByListener(trace.Backends[0].ListenerEffectivePolicies)); diff != "" {
+		t.Errorf("Unexpected diff in Backends[0].ListenerEffectivePolicies; diff (-want +got)=\n%v", diff)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// secretRef identifies a Secret referenced by an HTTPRoute.
+type secretRef struct {
+	Namespace string
+	Name      string
+	// From references the object holding the reference: the HTTPRoute for
+	// ExtensionRef filters referencing a Secret directly, or the object
+	// referenced by an ExtensionRef filter otherwise. References to Secrets in
+	// another namespace need a ReferenceGrant accepting From.
+	From common.ObjRef
+}
+
+// isSecretReference returns true if the ExtensionRef filter references a
+// Secret directly, rather than an implementation specific custom resource.
+func isSecretReference(ref gatewayv1.LocalObjectReference) bool {
+	return ref.Group == "" && ref.Kind == "Secret"
+}
+
+// secretRefsOfHTTPRoute returns the Secrets which the HTTPRoute references
+// through its filters, sorted by namespace/name. A Secret referenced by
+// several objects is returned once per referencing object. Secrets are referenced either
+// directly by an ExtensionRef filter, or by the object referenced by an
+// ExtensionRef filter. In the latter case, references are recognized by the
+// convention used by the Gateway API for SecretObjectReferences: fields named
+// secretRef or ending in SecretRef (or lists of those, named secretRefs or
+// ending in SecretRefs) within the spec, holding an object with a name, and
+// optionally a namespace, a group and a kind.
+func secretRefsOfHTTPRoute(httpRouteNode *HTTPRouteNode) []secretRef {
+	seen := make(map[secretRef]bool)
+	var result []secretRef
+	add := func(ref secretRef) {
+		if ref.Namespace == "" {
+			ref.Namespace = namespaceOrDefault(httpRouteNode.HTTPRoute.GetNamespace())
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			result = append(result, ref)
+		}
+	}
+
+	httpRouteRef := common.ObjRef{
+		Group:     gatewayv1.GroupName,
+		Kind:      "HTTPRoute",
+		Name:      httpRouteNode.HTTPRoute.GetName(),
+		Namespace: httpRouteNode.HTTPRoute.GetNamespace(),
+	}
+	for _, filter := range httpRouteNode.Filters {
+		if filter.ExtensionRef != nil && isSecretReference(*filter.ExtensionRef) {
+			add(secretRef{Namespace: httpRouteNode.HTTPRoute.GetNamespace(), Name: string(filter.ExtensionRef.Name), From: httpRouteRef})
+		}
+	}
+	for _, extensionRefNode := range httpRouteNode.ExtensionRefs {
+		spec, ok := extensionRefNode.Object.Object["spec"]
+		if !ok {
+			continue
+		}
+		from := common.ObjRef{
+			Group:     extensionRefNode.Object.GroupVersionKind().Group,
+			Kind:      extensionRefNode.Object.GetKind(),
+			Name:      extensionRefNode.Object.GetName(),
+			Namespace: extensionRefNode.Object.GetNamespace(),
+		}
+		for _, ref := range findSecretRefs(spec, extensionRefNode.Object.GetNamespace()) {
+			ref.From = from
+			add(ref)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return fmt.Sprint(result[i].From) < fmt.Sprint(result[j].From)
+	})
+	return result
+}
+
+// findSecretRefs walks value, which is part of an unstructured object, and
+// returns the Secrets it references. References without a namespace default
+// to namespace.
+func findSecretRefs(value interface{}, namespace string) []secretRef {
+	var result []secretRef
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			switch {
+			case key == "secretRef" || strings.HasSuffix(key, "SecretRef"):
+				if ref, ok := parseSecretRef(field, namespace); ok {
+					result = append(result, ref)
+				}
+			case key == "secretRefs" || strings.HasSuffix(key, "SecretRefs"):
+				items, _ := field.([]interface{})
+				for _, item := range items {
+					if ref, ok := parseSecretRef(item, namespace); ok {
+						result = append(result, ref)
+					}
+				}
+			default:
+				result = append(result, findSecretRefs(field, namespace)...)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, findSecretRefs(item, namespace)...)
+		}
+	}
+	return result
+}
+
+// parseSecretRef parses a reference to a Secret. The returned bool is false if
+// value is not a reference to a Secret, e.g. because it has no name or
+// references another kind.
+func parseSecretRef(value interface{}, namespace string) (secretRef, bool) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return secretRef{}, false
+	}
+	name, _ := fields["name"].(string)
+	if name == "" {
+		return secretRef{}, false
+	}
+	if group, ok := fields["group"].(string); ok && group != "" {
+		return secretRef{}, false
+	}
+	if kind, ok := fields["kind"].(string); ok && kind != "Secret" {
+		return secretRef{}, false
+	}
+	if ns, ok := fields["namespace"].(string); ok && ns != "" {
+		namespace = ns
+	}
+	return secretRef{Namespace: namespace, Name: name}, true
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// StatusSummary rolls up the status conditions of a resource into a single
+// verdict.
+type StatusSummary struct {
+	// Problems describes every condition which does not have its expected
+	// status, e.g. "listener web ResolvedRefs=False".
+	Problems []string
+}
+
+// OK returns true if all conditions of the resource have their expected
+// status.
+func (s StatusSummary) OK() bool {
+	return len(s.Problems) == 0
+}
+
+// String returns "OK" or "DEGRADED: " followed by the problems.
+func (s StatusSummary) String() string {
+	if s.OK() {
+		return "OK"
+	}
+	return "DEGRADED: " + strings.Join(s.Problems, ", ")
+}
+
+// expectedConditionStatus maps condition types to the status they are expected
+// to have on a healthy resource. Conditions of any other type are ignored.
+var expectedConditionStatus = map[string]metav1.ConditionStatus{
+	string(gatewayv1.GatewayConditionAccepted):      metav1.ConditionTrue,
+	string(gatewayv1.GatewayConditionProgrammed):    metav1.ConditionTrue,
+	string(gatewayv1.ListenerConditionResolvedRefs): metav1.ConditionTrue,
+	string(gatewayv1.ListenerConditionConflicted):   metav1.ConditionFalse,
+}
+
+// conditionProblems returns a problem, prefixed with prefix, for each condition
+// which does not have its expected status.
+func conditionProblems(prefix string, conditions []metav1.Condition) []string {
+	var result []string
+	for _, condition := range conditions {
+		want, ok := expectedConditionStatus[condition.Type]
+		if !ok || condition.Status == want {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%v%v=%v", prefix, condition.Type, condition.Status))
+	}
+	return result
+}
+
+// StatusSummary rolls up the conditions of the Gateway and of its listeners.
+// Resources without any conditions, e.g. ones not yet reconciled by a
+// controller, are considered OK.
+func (g *GatewayNode) StatusSummary() StatusSummary {
+	problems := conditionProblems("", g.Gateway.Status.Conditions)
+	for _, listener := range g.Gateway.Status.Listeners {
+		problems = append(problems, conditionProblems(fmt.Sprintf("listener %v ", listener.Name), listener.Conditions)...)
+	}
+	return StatusSummary{Problems: problems}
+}
+
+// StatusSummary rolls up the Accepted and ResolvedRefs conditions of the
+// HTTPRoute across all of its parents.
+func (h *HTTPRouteNode) StatusSummary() StatusSummary {
+	var problems []string
+	for _, parent := range h.HTTPRoute.Status.Parents {
+		parentName := string(parent.ParentRef.Name)
+		if parent.ParentRef.Namespace != nil {
+			parentName = fmt.Sprintf("%v/%v", *parent.ParentRef.Namespace, parentName)
+		}
+		problems = append(problems, conditionProblems(fmt.Sprintf("parent %v ", parentName), parent.Conditions)...)
+	}
+	return StatusSummary{Problems: problems}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestStatusSummary(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+
+	testcases := []struct {
+		name string
+		node interface{ StatusSummary() StatusSummary }
+		want string
+	}{
+		{
+			name: "Gateway without conditions is OK",
+			node: NewGatewayNode(&gatewayv1.Gateway{}),
+			want: "OK",
+		},
+		{
+			name: "healthy Gateway is OK",
+			node: NewGatewayNode(&gatewayv1.Gateway{
+				Status: gatewayv1.GatewayStatus{
+					Conditions: []metav1.Condition{
+						condition("Accepted", metav1.ConditionTrue),
+						condition("Programmed", metav1.ConditionTrue),
+					},
+					Listeners: []gatewayv1.ListenerStatus{{
+						Name: "web",
+						Conditions: []metav1.Condition{
+							condition("ResolvedRefs", metav1.ConditionTrue),
+							condition("Conflicted", metav1.ConditionFalse),
+						},
+					}},
+				},
+			}),
+			want: "OK",
+		},
+		{
+			name: "Gateway with unresolved listener refs is degraded",
+			node: NewGatewayNode(&gatewayv1.Gateway{
+				Status: gatewayv1.GatewayStatus{
+					Conditions: []metav1.Condition{
+						condition("Accepted", metav1.ConditionTrue),
+					},
+					Listeners: []gatewayv1.ListenerStatus{{
+						Name: "web",
+						Conditions: []metav1.Condition{
+							condition("ResolvedRefs", metav1.ConditionFalse),
+							condition("Conflicted", metav1.ConditionTrue),
+						},
+					}},
+				},
+			}),
+			want: "DEGRADED: listener web ResolvedRefs=False, listener web Conflicted=True",
+		},
+		{
+			name: "HTTPRoute not accepted by one of its parents is degraded",
+			node: NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+				Status: gatewayv1.HTTPRouteStatus{
+					RouteStatus: gatewayv1.RouteStatus{
+						Parents: []gatewayv1.RouteParentStatus{
+							{
+								ParentRef:  gatewayv1.ParentReference{Name: "foo-gateway"},
+								Conditions: []metav1.Condition{condition("Accepted", metav1.ConditionTrue)},
+							},
+							{
+								ParentRef: gatewayv1.ParentReference{Name: "bar-gateway", Namespace: common.PtrTo(gatewayv1.Namespace("bar"))},
+								Conditions: []metav1.Condition{
+									condition("Accepted", metav1.ConditionFalse),
+									condition("ResolvedRefs", metav1.ConditionUnknown),
+								},
+							},
+						},
+					},
+				},
+			}),
+			want: "DEGRADED: parent bar/bar-gateway Accepted=False, parent bar/bar-gateway ResolvedRefs=Unknown",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.node.StatusSummary()
+			if got.String() != tc.want {
+				t.Errorf("StatusSummary() = %q; want %q", got.String(), tc.want)
+			}
+			if got.OK() != (tc.want == "OK") {
+				t.Errorf("StatusSummary().OK() = %v; want %v", got.OK(), tc.want == "OK")
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"fmt"
+)
+
+// Validate checks the internal consistency of the ResourceModel. It asserts
+// that:
+//   - Every edge between two nodes has a corresponding reverse edge.
+//   - Every node referenced through an edge exists in the ResourceModel.
+//   - Effective policies are only computed for Gateways which exist in the
+//     ResourceModel.
+//
+// Validate is meant to be used as a safety net while debugging and testing; a
+// ResourceModel constructed through the Discoverer should never return any
+// errors.
+func (rm *ResourceModel) Validate() []error {
+	v := &modelValidator{rm: rm}
+
+	for gatewayClassID, gatewayClassNode := range rm.GatewayClasses {
+		from := describeID(gatewayClassID)
+		validateEdges(v, from, gatewayClassNode.Gateways, rm.Gateways, func(gatewayNode *GatewayNode) bool {
+			return gatewayNode.GatewayClass == gatewayClassNode
+		})
+		validateEdges(v, from, gatewayClassNode.Policies, rm.Policies, func(policyNode *PolicyNode) bool {
+			return policyNode.GatewayClass == gatewayClassNode || policyNode.SelectedGatewayClasses[gatewayClassID] == gatewayClassNode
+		})
+	}
+
+	for namespaceID, namespaceNode := range rm.Namespaces {
+		from := describeID(namespaceID)
+		validateEdges(v, from, namespaceNode.Gateways, rm.Gateways, func(gatewayNode *GatewayNode) bool {
+			return gatewayNode.Namespace == namespaceNode
+		})
+		validateEdges(v, from, namespaceNode.HTTPRoutes, rm.HTTPRoutes, func(httpRouteNode *HTTPRouteNode) bool {
+			return httpRouteNode.Namespace == namespaceNode
+		})
+		validateEdges(v, from, namespaceNode.Backends, rm.Backends, func(backendNode *BackendNode) bool {
+			return backendNode.Namespace == namespaceNode
+		})
+		validateEdges(v, from, namespaceNode.Policies, rm.Policies, func(policyNode *PolicyNode) bool {
+			return policyNode.Namespace == namespaceNode || policyNode.SelectedNamespaces[namespaceID] == namespaceNode
+		})
+	}
+
+	for gatewayID, gatewayNode := range rm.Gateways {
+		from := describeID(gatewayID)
+		if gatewayNode.GatewayClass != nil {
+			validateEdge(v, from, gatewayNode.GatewayClass.ID(), gatewayNode.GatewayClass, rm.GatewayClasses,
+				gatewayNode.GatewayClass.Gateways[gatewayID] == gatewayNode)
+		}
+		if gatewayNode.Namespace != nil {
+			validateEdge(v, from, gatewayNode.Namespace.ID(), gatewayNode.Namespace, rm.Namespaces,
+				gatewayNode.Namespace.Gateways[gatewayID] == gatewayNode)
+		}
+		validateEdges(v, from, gatewayNode.HTTPRoutes, rm.HTTPRoutes, func(httpRouteNode *HTTPRouteNode) bool {
+			return httpRouteNode.Gateways[gatewayID] == gatewayNode
+		})
+		validateEdges(v, from, gatewayNode.Policies, rm.Policies, func(policyNode *PolicyNode) bool {
+			return policyNode.Gateway == gatewayNode || policyNode.SelectedGateways[gatewayID] == gatewayNode
+		})
+	}
+
+	for httpRouteID, httpRouteNode := range rm.HTTPRoutes {
+		from := describeID(httpRouteID)
+		if httpRouteNode.Namespace != nil {
+			validateEdge(v, from, httpRouteNode.Namespace.ID(), httpRouteNode.Namespace, rm.Namespaces,
+				httpRouteNode.Namespace.HTTPRoutes[httpRouteID] == httpRouteNode)
+		}
+		validateEdges(v, from, httpRouteNode.Gateways, rm.Gateways, func(gatewayNode *GatewayNode) bool {
+			return gatewayNode.HTTPRoutes[httpRouteID] == httpRouteNode
+		})
+		validateEdges(v, from, httpRouteNode.ParentServices, rm.Backends, func(backendNode *BackendNode) bool {
+			return backendNode.MeshHTTPRoutes[httpRouteID] == httpRouteNode
+		})
+		validateEdges(v, from, httpRouteNode.Backends, rm.Backends, func(backendNode *BackendNode) bool {
+			return backendNode.HTTPRoutes[httpRouteID] == httpRouteNode
+		})
+		validateEdges(v, from, httpRouteNode.Policies, rm.Policies, func(policyNode *PolicyNode) bool {
+			return policyNode.HTTPRoute == httpRouteNode || policyNode.SelectedHTTPRoutes[httpRouteID] == httpRouteNode
+		})
+		for gatewayID := range httpRouteNode.EffectivePolicies {
+			v.validateEffectivePoliciesGateway(from, gatewayID)
+		}
+	}
+
+	for backendID, backendNode := range rm.Backends {
+		from := describeID(backendID)
+		if backendNode.Namespace != nil {
+			validateEdge(v, from, backendNode.Namespace.ID(), backendNode.Namespace, rm.Namespaces,
+				backendNode.Namespace.Backends[backendID] == backendNode)
+		}
+		validateEdges(v, from, backendNode.HTTPRoutes, rm.HTTPRoutes, func(httpRouteNode *HTTPRouteNode) bool {
+			return httpRouteNode.Backends[backendID] == backendNode
+		})
+		validateEdges(v, from, backendNode.MeshHTTPRoutes, rm.HTTPRoutes, func(httpRouteNode *HTTPRouteNode) bool {
+			return httpRouteNode.ParentServices[backendID] == backendNode
+		})
+		validateEdges(v, from, backendNode.ReferenceGrants, rm.ReferenceGrants, func(referenceGrantNode *ReferenceGrantNode) bool {
+			return referenceGrantNode.Backends[backendID] == backendNode
+		})
+		validateEdges(v, from, backendNode.Policies, rm.Policies, func(policyNode *PolicyNode) bool {
+			return policyNode.Backend == backendNode || policyNode.SelectedBackends[backendID] == backendNode
+		})
+		for gatewayID := range backendNode.EffectivePolicies {
+			v.validateEffectivePoliciesGateway(from, gatewayID)
+		}
+	}
+
+	for referenceGrantID, referenceGrantNode := range rm.ReferenceGrants {
+		validateEdges(v, describeID(referenceGrantID), referenceGrantNode.Backends, rm.Backends, func(backendNode *BackendNode) bool {
+			return backendNode.ReferenceGrants[referenceGrantID] == referenceGrantNode
+		})
+	}
+
+	for policyID, policyNode := range rm.Policies {
+		from := describeID(policyID)
+		if policyNode.GatewayClass != nil {
+			validateEdge(v, from, policyNode.GatewayClass.ID(), policyNode.GatewayClass, rm.GatewayClasses,
+				policyNode.GatewayClass.Policies[policyID] == policyNode)
+		}
+		if policyNode.Namespace != nil {
+			validateEdge(v, from, policyNode.Namespace.ID(), policyNode.Namespace, rm.Namespaces,
+				policyNode.Namespace.Policies[policyID] == policyNode)
+		}
+		if policyNode.Gateway != nil {
+			validateEdge(v, from, policyNode.Gateway.ID(), policyNode.Gateway, rm.Gateways,
+				policyNode.Gateway.Policies[policyID] == policyNode)
+		}
+		if policyNode.HTTPRoute != nil {
+			validateEdge(v, from, policyNode.HTTPRoute.ID(), policyNode.HTTPRoute, rm.HTTPRoutes,
+				policyNode.HTTPRoute.Policies[policyID] == policyNode)
+		}
+		if policyNode.Backend != nil {
+			validateEdge(v, from, policyNode.Backend.ID(), policyNode.Backend, rm.Backends,
+				policyNode.Backend.Policies[policyID] == policyNode)
+		}
+		validateEdges(v, from, policyNode.SelectedGatewayClasses, rm.GatewayClasses, func(gatewayClassNode *GatewayClassNode) bool {
+			return gatewayClassNode.Policies[policyID] == policyNode
+		})
+		validateEdges(v, from, policyNode.SelectedNamespaces, rm.Namespaces, func(namespaceNode *NamespaceNode) bool {
+			return namespaceNode.Policies[policyID] == policyNode
+		})
+		validateEdges(v, from, policyNode.SelectedGateways, rm.Gateways, func(gatewayNode *GatewayNode) bool {
+			return gatewayNode.Policies[policyID] == policyNode
+		})
+		validateEdges(v, from, policyNode.SelectedHTTPRoutes, rm.HTTPRoutes, func(httpRouteNode *HTTPRouteNode) bool {
+			return httpRouteNode.Policies[policyID] == policyNode
+		})
+		validateEdges(v, from, policyNode.SelectedBackends, rm.Backends, func(backendNode *BackendNode) bool {
+			return backendNode.Policies[policyID] == policyNode
+		})
+	}
+
+	return v.errors
+}
+
+// modelValidator accumulates the errors found while validating a
+// ResourceModel.
+type modelValidator struct {
+	rm     *ResourceModel
+	errors []error
+}
+
+func (v *modelValidator) errorf(format string, args ...interface{}) {
+	v.errors = append(v.errors, fmt.Errorf(format, args...))
+}
+
+// validateEffectivePoliciesGateway checks that effective policies computed
+// for the Gateway identified by gatewayID reference a known Gateway.
+func (v *modelValidator) validateEffectivePoliciesGateway(from string, gatewayID gatewayID) {
+	if _, ok := v.rm.Gateways[gatewayID]; !ok {
+		v.errorf("%v has EffectivePolicies for %v which does not exist in the ResourceModel", from, describeID(gatewayID))
+	}
+}
+
+// validateEdges checks every edge from the node described by "from" to the
+// nodes in "edges". Each referenced node must be the same node which is stored
+// in the ResourceModel ("model"), and hasReverseEdge must report that the
+// referenced node links back to the "from" node.
+func validateEdges[ID comparable, Node any](v *modelValidator, from string, edges, model map[ID]*Node, hasReverseEdge func(*Node) bool) {
+	for id, node := range edges {
+		validateEdge(v, from, id, node, model, hasReverseEdge(node))
+	}
+}
+
+// validateEdge checks a single edge from the node described by "from" to the
+// node identified by id.
+func validateEdge[ID comparable, Node any](v *modelValidator, from string, id ID, node *Node, model map[ID]*Node, hasReverseEdge bool) {
+	if model[id] != node {
+		v.errorf("%v references %v which does not exist in the ResourceModel", from, describeID(id))
+		return
+	}
+	if !hasReverseEdge {
+		v.errorf("%v references %v but the reverse edge is missing", from, describeID(id))
+	}
+}
+
+// describeID returns a human readable representation of a node ID.
+func describeID(id interface{}) string {
+	var kind string
+	var r resourceID
+	switch id := id.(type) {
+	case gatewayClassID:
+		kind, r = "GatewayClass", resourceID(id)
+	case namespaceID:
+		kind, r = "Namespace", resourceID(id)
+	case gatewayID:
+		kind, r = "Gateway", resourceID(id)
+	case httpRouteID:
+		kind, r = "HTTPRoute", resourceID(id)
+	case backendID:
+		kind, r = "Backend", resourceID(id)
+	case referenceGrantID:
+		kind, r = "ReferenceGrant", resourceID(id)
+	case policyID:
+		kind, r = "Policy", resourceID(id)
+	case extensionRefID:
+		kind, r = "ExtensionRef", resourceID(id)
+	default:
+		return fmt.Sprintf("%v", id)
+	}
+	if r.Namespace == "" {
+		return fmt.Sprintf("%v %q", kind, r.Name)
+	}
+	return fmt.Sprintf("%v %q", kind, r.Namespace+"/"+r.Name)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestResourceModel_Validate(t *testing.T) {
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{
+						BackendRefs: []gatewayv1.HTTPBackendRef{
+							{
+								BackendRef: gatewayv1.BackendRef{
+									BackendObjectReference: gatewayv1.BackendObjectReference{
+										Kind: common.PtrTo(gatewayv1.Kind("Service")),
+										Name: "foo-svc",
+										Port: common.PtrTo(gatewayv1.PortNumber(80)),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+	}
+
+	gwID := GatewayID("default", "foo-gateway")
+	hrID := HTTPRouteID("default", "foo-httproute")
+	bID := BackendIDForService("default", "foo-svc")
+
+	testcases := []struct {
+		name    string
+		corrupt func(rm *ResourceModel)
+		want    []string
+	}{
+		{
+			name:    "consistent model",
+			corrupt: func(*ResourceModel) {},
+			want:    nil,
+		},
+		{
+			name: "missing reverse edge from HTTPRoute to Gateway",
+			corrupt: func(rm *ResourceModel) {
+				delete(rm.HTTPRoutes[hrID].Gateways, gwID)
+			},
+			want: []string{
+				`Gateway "default/foo-gateway" references HTTPRoute "default/foo-httproute" but the reverse edge is missing`,
+			},
+		},
+		{
+			name: "edge to node which does not exist in model",
+			corrupt: func(rm *ResourceModel) {
+				rm.Backends[bID].HTTPRoutes[HTTPRouteID("default", "bar-httproute")] = NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Name: "bar-httproute", Namespace: "default"},
+				})
+			},
+			want: []string{
+				`Backend "default/foo-svc" references HTTPRoute "default/bar-httproute" which does not exist in the ResourceModel`,
+			},
+		},
+		{
+			name: "effective policies for unknown Gateway",
+			corrupt: func(rm *ResourceModel) {
+				rm.HTTPRoutes[hrID].EffectivePolicies[GatewayID("default", "bar-gateway")] = map[policymanager.PolicyCrdID]policymanager.Policy{}
+			},
+			want: []string{
+				`HTTPRoute "default/foo-httproute" has EffectivePolicies for Gateway "default/bar-gateway" which does not exist in the ResourceModel`,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+			discoverer := Discoverer{
+				K8sClients:    params.K8sClients,
+				PolicyManager: params.PolicyManager,
+			}
+
+			resourceModel, err := discoverer.DiscoverResourcesForBackend(context.Background(), Filter{Labels: labels.Everything()})
+			if err != nil {
+				t.Fatalf("Failed to construct resourceModel: %v", err)
+			}
+			if len(resourceModel.Gateways) != 1 || len(resourceModel.HTTPRoutes) != 1 {
+				t.Fatalf("Unexpected resourceModel; got %v Gateways and %v HTTPRoutes, want 1 of each", len(resourceModel.Gateways), len(resourceModel.HTTPRoutes))
+			}
+
+			tc.corrupt(resourceModel)
+
+			var got []string
+			for _, err := range resourceModel.Validate() {
+				got = append(got, err.Error())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff in Validate() errors; got=%v, want=%v;\ndiff (-want +got)=\n%v", got, tc.want, diff)
+			}
+		})
+	}
+}
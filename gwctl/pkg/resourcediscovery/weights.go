@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+// BackendWeight is the share of the traffic matched by a rule of an HTTPRoute
+// which a backendRef of the rule receives.
+type BackendWeight struct {
+	RuleIndex int
+	// BackendRef is the referenced backend, with its namespace and kind
+	// defaulted.
+	BackendRef common.ObjRef
+	// Weight is the weight of the backendRef, which defaults to 1 when omitted.
+	Weight int32
+	// WeightOmitted is true if the backendRef does not specify a weight.
+	WeightOmitted bool
+	// Percentage is the Weight normalized against the sum of the weights of
+	// all backendRefs of the rule. It is 0 if all weights of the rule are 0.
+	Percentage float64
+}
+
+// BackendWeights returns the weights of the backendRefs of all rules of the
+// HTTPRoute, in declared order, along with their normalized percentages.
+func (h *HTTPRouteNode) BackendWeights() []BackendWeight {
+	var result []BackendWeight
+	for ruleIndex, rule := range h.HTTPRoute.Spec.Rules {
+		first := len(result)
+		var total int64
+		for _, backendRef := range rule.BackendRefs {
+			weight := BackendWeight{
+				RuleIndex:     ruleIndex,
+				BackendRef:    httpBackendRefObjRef(h.HTTPRoute, backendRef),
+				Weight:        backendRefWeight(backendRef),
+				WeightOmitted: backendRef.Weight == nil,
+			}
+			total += int64(weight.Weight)
+			result = append(result, weight)
+		}
+		if total == 0 {
+			continue
+		}
+		for i := first; i < len(result); i++ {
+			result[i].Percentage = float64(result[i].Weight) * 100 / float64(total)
+		}
+	}
+	return result
+}
+
+// backendRefWeight returns the weight of the backendRef, which defaults to 1
+// when omitted.
+func backendRefWeight(backendRef gatewayv1.HTTPBackendRef) int32 {
+	if backendRef.Weight == nil {
+		return 1
+	}
+	return *backendRef.Weight
+}
+
+// RulesWithAmbiguousWeights returns the indexes of the rules of the HTTPRoute
+// in which some backendRefs specify a weight and others do not. The omitted
+// weights default to 1, which is rarely what was intended when the other
+// weights are e.g. percentages.
+func (h *HTTPRouteNode) RulesWithAmbiguousWeights() []int {
+	var result []int
+	for ruleIndex, rule := range h.HTTPRoute.Spec.Rules {
+		var specified, omitted bool
+		for _, backendRef := range rule.BackendRefs {
+			if backendRef.Weight == nil {
+				omitted = true
+			} else {
+				specified = true
+			}
+		}
+		if specified && omitted {
+			result = append(result, ruleIndex)
+		}
+	}
+	return result
+}
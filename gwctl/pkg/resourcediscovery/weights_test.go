@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcediscovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+)
+
+func TestHTTPRouteNode_BackendWeights(t *testing.T) {
+	backendRef := func(name string, weight *int32) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+				Weight: weight,
+			},
+		}
+	}
+	serviceRef := func(name string) common.ObjRef {
+		return common.ObjRef{Kind: "Service", Name: name, Namespace: "default"}
+	}
+
+	httpRouteNode := NewHTTPRouteNode(&gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-httproute",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				// The omitted weight of bar-svc defaults to 1, so it receives
+				// 25% of the traffic rather than the intended 0% or 75%.
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc", common.PtrTo(int32(3))),
+					backendRef("bar-svc", nil),
+				}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc", common.PtrTo(int32(1))),
+					backendRef("bar-svc", common.PtrTo(int32(2))),
+				}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("foo-svc", common.PtrTo(int32(0))),
+				}},
+				{BackendRefs: []gatewayv1.HTTPBackendRef{
+					backendRef("baz-svc", nil),
+				}},
+			},
+		},
+	})
+
+	wantWeights := []BackendWeight{
+		{RuleIndex: 0, BackendRef: serviceRef("foo-svc"), Weight: 3, Percentage: 75},
+		{RuleIndex: 0, BackendRef: serviceRef("bar-svc"), Weight: 1, WeightOmitted: true, Percentage: 25},
+		{RuleIndex: 1, BackendRef: serviceRef("foo-svc"), Weight: 1, Percentage: 100.0 / 3},
+		{RuleIndex: 1, BackendRef: serviceRef("bar-svc"), Weight: 2, Percentage: 200.0 / 3},
+		{RuleIndex: 2, BackendRef: serviceRef("foo-svc"), Weight: 0, Percentage: 0},
+		{RuleIndex: 3, BackendRef: serviceRef("baz-svc"), Weight: 1, WeightOmitted: true, Percentage: 100},
+	}
+	if diff := cmp.Diff(wantWeights, httpRouteNode.BackendWeights()); diff != "" {
+		t.Errorf("Unexpected diff in BackendWeights(); diff (-want +got)=\n%v", diff)
+	}
+
+	wantRules := []int{0}
+	if diff := cmp.Diff(wantRules, httpRouteNode.RulesWithAmbiguousWeights()); diff != "" {
+		t.Errorf("Unexpected diff in RulesWithAmbiguousWeights(); diff (-want +got)=\n%v", diff)
+	}
+}
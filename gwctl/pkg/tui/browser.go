@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tui
+
+import (
+	"strings"
+)
+
+// KeyType identifies a key press handled by the Browser.
+type KeyType int
+
+const (
+	// KeyRune is a printable character.
+	KeyRune KeyType = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyBackspace
+	KeyEscape
+	// KeyInterrupt is Ctrl-C, which always quits.
+	KeyInterrupt
+)
+
+// Key is a single key press.
+type Key struct {
+	Type KeyType
+	// Rune is the typed character if Type is KeyRune.
+	Rune rune
+}
+
+const helpLine = "↑/↓ move  enter/→ open  ←/backspace back  / search  esc clear  q quit"
+
+// Browser holds the state of the terminal UI: the Nodes entered so far, the
+// selected Node of each level, and the search query filtering the current
+// level. It is independent of the terminal, which allows rendering it with
+// View and driving it with HandleKey.
+type Browser struct {
+	roots []*Node
+	// path lists the entered Nodes, starting at the top level.
+	path []*Node
+	// cursors holds the index of the selected entry for the top level and for
+	// each entered Node.
+	cursors []int
+	// query filters the entries of the current level by their title.
+	query string
+	// searching is true while the search box has the focus.
+	searching bool
+}
+
+// NewBrowser returns a Browser showing the roots at the top level.
+func NewBrowser(roots []*Node) *Browser {
+	return &Browser{roots: roots, cursors: []int{0}}
+}
+
+// Entries returns the Nodes listed at the current level, filtered by the
+// search query.
+func (b *Browser) Entries() []*Node {
+	nodes := b.roots
+	if len(b.path) != 0 {
+		nodes = b.path[len(b.path)-1].Children
+	}
+	if b.query == "" {
+		return nodes
+	}
+	query := strings.ToLower(b.query)
+	var result []*Node
+	for _, node := range nodes {
+		if strings.Contains(strings.ToLower(node.Title), query) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// Selected returns the selected Node, or nil if the current level has no
+// entries.
+func (b *Browser) Selected() *Node {
+	entries := b.Entries()
+	cursor := b.cursors[len(b.cursors)-1]
+	if cursor >= len(entries) {
+		return nil
+	}
+	return entries[cursor]
+}
+
+// Path returns the titles of the entered Nodes.
+func (b *Browser) Path() []string {
+	var result []string
+	for _, node := range b.path {
+		result = append(result, node.Title)
+	}
+	return result
+}
+
+// HandleKey updates the state for the key press. It returns false if the user
+// asked to quit.
+func (b *Browser) HandleKey(key Key) bool {
+	if key.Type == KeyInterrupt {
+		return false
+	}
+	if b.searching {
+		switch key.Type {
+		case KeyRune:
+			b.setQuery(b.query + string(key.Rune))
+			return true
+		case KeyBackspace:
+			if b.query != "" {
+				b.setQuery(string([]rune(b.query)[:len([]rune(b.query))-1]))
+			}
+			return true
+		case KeyEnter:
+			b.searching = false
+			return true
+		case KeyEscape:
+			b.searching = false
+			b.setQuery("")
+			return true
+		}
+	}
+
+	switch key.Type {
+	case KeyUp:
+		b.moveCursor(-1)
+	case KeyDown:
+		b.moveCursor(1)
+	case KeyEnter, KeyRight:
+		b.enter()
+	case KeyLeft, KeyBackspace:
+		b.back()
+	case KeyEscape:
+		b.setQuery("")
+	case KeyRune:
+		switch key.Rune {
+		case 'q':
+			return false
+		case 'k':
+			b.moveCursor(-1)
+		case 'j':
+			b.moveCursor(1)
+		case 'l':
+			b.enter()
+		case 'h':
+			b.back()
+		case '/':
+			b.searching = true
+		}
+	}
+	return true
+}
+
+func (b *Browser) moveCursor(delta int) {
+	cursor := b.cursors[len(b.cursors)-1] + delta
+	if cursor >= len(b.Entries()) {
+		cursor = len(b.Entries()) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	b.cursors[len(b.cursors)-1] = cursor
+}
+
+// enter drills into the selected Node, unless it has no children.
+func (b *Browser) enter() {
+	selected := b.Selected()
+	if selected == nil || len(selected.Children) == 0 {
+		return
+	}
+	b.path = append(b.path, selected)
+	b.cursors = append(b.cursors, 0)
+	b.query = ""
+}
+
+// back returns to the parent level, with its previously selected entry.
+func (b *Browser) back() {
+	if len(b.path) == 0 {
+		return
+	}
+	b.path = b.path[:len(b.path)-1]
+	b.cursors = b.cursors[:len(b.cursors)-1]
+	b.query = ""
+}
+
+func (b *Browser) setQuery(query string) {
+	b.query = query
+	b.cursors[len(b.cursors)-1] = 0
+}
+
+// View renders the Browser into a screen of the given size: a header with the
+// path of entered Nodes, the entries of the current level next to the detail
+// of the selected entry, and a footer with the search box or the key bindings.
+func (b *Browser) View(width, height int) string {
+	if width < 20 {
+		width = 20
+	}
+	if height < 4 {
+		height = 4
+	}
+	lines := []string{truncate(strings.Join(append([]string{"gwctl"}, b.Path()...), " > "), width)}
+
+	entries := b.Entries()
+	cursor := b.cursors[len(b.cursors)-1]
+	bodyHeight := height - 2
+	listWidth := width * 2 / 5
+	detailWidth := width - listWidth - 3
+
+	// Scroll the list such that the selected entry is visible.
+	offset := 0
+	if cursor >= bodyHeight {
+		offset = cursor - bodyHeight + 1
+	}
+	var detail []string
+	if selected := b.Selected(); selected != nil {
+		detail = selected.Detail
+	}
+	for row := 0; row < bodyHeight; row++ {
+		var entry string
+		switch i := offset + row; {
+		case i < len(entries) && i == cursor:
+			entry = "> " + entries[i].Title
+		case i < len(entries):
+			entry = "  " + entries[i].Title
+		case row == 0 && len(entries) == 0:
+			entry = "  <no entries>"
+		}
+		if i := offset + row; i < len(entries) && len(entries[i].Children) != 0 {
+			entry += " ›"
+		}
+		var detailLine string
+		if row < len(detail) {
+			detailLine = detail[row]
+		}
+		lines = append(lines, pad(truncate(entry, listWidth), listWidth)+" │ "+truncate(detailLine, detailWidth))
+	}
+
+	footer := helpLine
+	if b.searching || b.query != "" {
+		footer = "/" + b.query
+		if b.searching {
+			footer += "_"
+		}
+	}
+	lines = append(lines, truncate(footer, width))
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens s to at most width runes.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// pad appends spaces to s until it is width runes long.
+func pad(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testTree() []*Node {
+	return []*Node{
+		{
+			Title:  "Gateway default/bar-gateway",
+			Detail: []string{"Gateway default/bar-gateway"},
+		},
+		{
+			Title:  "Gateway default/foo-gateway",
+			Detail: []string{"Gateway default/foo-gateway", "GatewayClass: foo-gatewayclass"},
+			Children: []*Node{
+				{
+					Title: "HTTPRoute default/foo-httproute",
+					Children: []*Node{
+						{Title: "Service default/foo-svc"},
+					},
+				},
+				{Title: "HTTPRoute default/qux-httproute"},
+			},
+		},
+	}
+}
+
+func runes(s string) []Key {
+	var result []Key
+	for _, r := range s {
+		result = append(result, Key{Type: KeyRune, Rune: r})
+	}
+	return result
+}
+
+func TestBrowser_HandleKey(t *testing.T) {
+	testcases := []struct {
+		name         string
+		keys         []Key
+		wantPath     []string
+		wantSelected string
+		wantQuit     bool
+	}{
+		{
+			name:         "initial state selects the first root",
+			wantSelected: "Gateway default/bar-gateway",
+		},
+		{
+			name:         "down moves the selection",
+			keys:         []Key{{Type: KeyDown}},
+			wantSelected: "Gateway default/foo-gateway",
+		},
+		{
+			name:         "selection stays within the entries",
+			keys:         []Key{{Type: KeyDown}, {Type: KeyDown}, {Type: KeyDown}, {Type: KeyUp}},
+			wantSelected: "Gateway default/bar-gateway",
+		},
+		{
+			name:         "enter opens the selected node",
+			keys:         append(runes("j"), Key{Type: KeyEnter}, Key{Type: KeyRight}),
+			wantPath:     []string{"Gateway default/foo-gateway", "HTTPRoute default/foo-httproute"},
+			wantSelected: "Service default/foo-svc",
+		},
+		{
+			name:         "nodes without children are not opened",
+			keys:         []Key{{Type: KeyEnter}},
+			wantSelected: "Gateway default/bar-gateway",
+		},
+		{
+			name:         "back restores the previous selection",
+			keys:         []Key{{Type: KeyDown}, {Type: KeyEnter}, {Type: KeyDown}, {Type: KeyLeft}},
+			wantSelected: "Gateway default/foo-gateway",
+		},
+		{
+			name: "search without matches selects nothing",
+			keys: append(runes("/QUX"), Key{Type: KeyEnter}),
+		},
+		{
+			name:         "search filters the entries of the current level",
+			keys:         append(append(runes("jl/qux"), Key{Type: KeyEnter}), runes("j")...),
+			wantPath:     []string{"Gateway default/foo-gateway"},
+			wantSelected: "HTTPRoute default/qux-httproute",
+		},
+		{
+			name:         "escape clears the search",
+			keys:         append(runes("jl/qux"), Key{Type: KeyEscape}),
+			wantPath:     []string{"Gateway default/foo-gateway"},
+			wantSelected: "HTTPRoute default/foo-httproute",
+		},
+		{
+			name:         "q is part of the search query",
+			keys:         append(runes("/q"), Key{Type: KeyBackspace}, Key{Type: KeyEnter}),
+			wantSelected: "Gateway default/bar-gateway",
+		},
+		{
+			name:     "q quits",
+			keys:     runes("q"),
+			wantQuit: true,
+		},
+		{
+			name:     "ctrl-c quits while searching",
+			keys:     append(runes("/"), Key{Type: KeyInterrupt}),
+			wantQuit: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser := NewBrowser(testTree())
+			quit := false
+			for _, key := range tc.keys {
+				if !browser.HandleKey(key) {
+					quit = true
+					break
+				}
+			}
+			if quit != tc.wantQuit {
+				t.Fatalf("quit = %v, want %v", quit, tc.wantQuit)
+			}
+			if quit {
+				return
+			}
+			if diff := cmp.Diff(tc.wantPath, browser.Path()); diff != "" {
+				t.Errorf("Unexpected diff in Path (-want +got):\n%v", diff)
+			}
+			var selected string
+			if node := browser.Selected(); node != nil {
+				selected = node.Title
+			}
+			if selected != tc.wantSelected {
+				t.Errorf("Selected() = %q, want %q", selected, tc.wantSelected)
+			}
+		})
+	}
+}
+
+func TestBrowser_View(t *testing.T) {
+	browser := NewBrowser(testTree())
+	browser.HandleKey(Key{Type: KeyDown})
+
+	got := browser.View(80, 5)
+	want := strings.Join([]string{
+		"gwctl",
+		"  Gateway default/bar-gateway    │ Gateway default/foo-gateway",
+		"> Gateway default/foo-gateway ›  │ GatewayClass: foo-gatewayclass",
+		"                                 │ ",
+		helpLine,
+	}, "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in View (-want +got):\n%v", diff)
+	}
+
+	for _, key := range runes("/nothing") {
+		browser.HandleKey(key)
+	}
+	got = browser.View(40, 4)
+	want = strings.Join([]string{
+		"gwctl",
+		"  <no entries>   │ ",
+		"                 │ ",
+		"/nothing_",
+	}, "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in View while searching (-want +got):\n%v", diff)
+	}
+}
+
+func TestDecodeKeys(t *testing.T) {
+	got := decodeKeys([]byte("\x1b[A\x1b[B\x1bOC\x1b[Dx\r\x7f\x1b\x03é"))
+	want := []Key{
+		{Type: KeyUp},
+		{Type: KeyDown},
+		{Type: KeyRight},
+		{Type: KeyLeft},
+		{Type: KeyRune, Rune: 'x'},
+		{Type: KeyEnter},
+		{Type: KeyBackspace},
+		{Type: KeyEscape},
+		{Type: KeyInterrupt},
+		{Type: KeyRune, Rune: 'é'},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in decodeKeys (-want +got):\n%v", diff)
+	}
+}
+
+func TestDecodeKeys_EscapeSequences(t *testing.T) {
+	testcases := []struct {
+		name  string
+		input string
+		want  []Key
+	}{
+		{
+			name:  "arrow key with modifier parameters",
+			input: "\x1b[1;5Ax",
+			want:  []Key{{Type: KeyUp}, {Type: KeyRune, Rune: 'x'}},
+		},
+		{
+			name:  "unknown sequence with parameters is dropped",
+			input: "\x1b[3~x",
+			want:  []Key{{Type: KeyRune, Rune: 'x'}},
+		},
+		{
+			name:  "unknown SS3 sequence is dropped",
+			input: "\x1bOPx",
+			want:  []Key{{Type: KeyRune, Rune: 'x'}},
+		},
+		{
+			name:  "incomplete sequence is dropped",
+			input: "\x1b[12",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := decodeKeys([]byte(tc.input))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected diff in decodeKeys (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
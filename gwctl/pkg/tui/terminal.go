@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	enterAlternateScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAlternateScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen          = "\x1b[H\x1b[2J"
+)
+
+// Run shows a Browser of the roots in the terminal until the user quits. The
+// terminal is switched to raw mode and to the alternate screen, both of which
+// are restored when Run returns.
+func Run(in *os.File, out io.Writer, roots []*Node) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the terminal UI requires an interactive terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %v", err)
+	}
+	defer term.Restore(fd, state) //nolint:errcheck
+
+	fmt.Fprint(out, enterAlternateScreen)
+	defer fmt.Fprint(out, leaveAlternateScreen)
+
+	browser := NewBrowser(roots)
+	buf := make([]byte, 64)
+	for {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		// Raw mode disables the translation of newlines, so lines need an
+		// explicit carriage return.
+		fmt.Fprint(out, clearScreen+strings.ReplaceAll(browser.View(width, height), "\n", "\r\n"))
+
+		n, err := in.Read(buf)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		for _, key := range decodeKeys(buf[:n]) {
+			if !browser.HandleKey(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// decodeKeys translates the bytes read from a terminal in raw mode into key
+// presses. Unknown escape sequences are dropped.
+func decodeKeys(input []byte) []Key {
+	var result []Key
+	for len(input) != 0 {
+		switch {
+		case input[0] == 0x1b && len(input) >= 2 && (input[1] == '[' || input[1] == 'O'):
+			var final byte
+			final, input = decodeEscapeSequence(input)
+			if key, ok := arrowKeys[final]; ok {
+				result = append(result, Key{Type: key})
+			}
+		case input[0] == 0x1b:
+			result = append(result, Key{Type: KeyEscape})
+			input = input[1:]
+		case input[0] == '\r' || input[0] == '\n':
+			result = append(result, Key{Type: KeyEnter})
+			input = input[1:]
+		case input[0] == 0x7f || input[0] == 0x08:
+			result = append(result, Key{Type: KeyBackspace})
+			input = input[1:]
+		case input[0] == 0x03:
+			result = append(result, Key{Type: KeyInterrupt})
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError && unicode.IsPrint(r) {
+				result = append(result, Key{Type: KeyRune, Rune: r})
+			}
+			input = input[size:]
+		}
+	}
+	return result
+}
+
+// arrowKeys maps the final bytes of the escape sequences sent for the arrow
+// keys to the keys.
+var arrowKeys = map[byte]KeyType{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+}
+
+// decodeEscapeSequence consumes the CSI (ESC [) or SS3 (ESC O) sequence at the
+// start of the input, returning its final byte and the remaining input. A CSI
+// sequence consists of any number of parameter bytes (0x30-0x3f) and
+// intermediate bytes (0x20-0x2f) followed by a final byte (0x40-0x7e), e.g.
+// "ESC [ 1 ; 5 A" for Ctrl+Up. An SS3 sequence consists of a single final
+// byte. The final byte is 0 if the sequence is incomplete or malformed, in
+// which case the sequence is consumed up to the offending byte.
+func decodeEscapeSequence(input []byte) (byte, []byte) {
+	if input[1] == 'O' {
+		if len(input) < 3 {
+			return 0, input[2:]
+		}
+		return input[2], input[3:]
+	}
+	i := 2
+	for i < len(input) && input[i] >= 0x30 && input[i] <= 0x3f {
+		i++
+	}
+	for i < len(input) && input[i] >= 0x20 && input[i] <= 0x2f {
+		i++
+	}
+	if i < len(input) && input[i] >= 0x40 && input[i] <= 0x7e {
+		return input[i], input[i+1:]
+	}
+	return 0, input[i:]
+}
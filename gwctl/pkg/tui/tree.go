@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tui implements a read-only terminal UI for browsing a
+// ResourceModel.
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/policymanager"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/relations"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+)
+
+// Node is an entry of the navigation tree. While a Node is selected, its
+// Detail is shown next to the tree, and entering it lists its Children.
+type Node struct {
+	// Title is the label of the Node in the navigation tree.
+	Title string
+	// Detail lists the lines shown in the detail pane.
+	Detail []string
+	// Children are the Nodes listed when entering the Node.
+	Children []*Node
+}
+
+// BuildTree maps the ResourceModel to the navigation tree. The top level lists
+// the Gateways, each of which contains its HTTPRoutes and default backends.
+// HTTPRoutes in turn contain the backends they reference. Effective policies
+// of HTTPRoutes and backends are shown in the context of the Gateway they are
+// reached through.
+func BuildTree(resourceModel *resourcediscovery.ResourceModel) []*Node {
+	gatewayNodes := common.MapToValues(resourceModel.Gateways)
+	sort.Slice(gatewayNodes, func(i, j int) bool {
+		return namespacedName(gatewayNodes[i].Gateway.GetNamespace(), gatewayNodes[i].Gateway.GetName()) <
+			namespacedName(gatewayNodes[j].Gateway.GetNamespace(), gatewayNodes[j].Gateway.GetName())
+	})
+
+	var result []*Node
+	for _, gatewayNode := range gatewayNodes {
+		result = append(result, gatewayTreeNode(resourceModel, gatewayNode))
+	}
+	return result
+}
+
+func gatewayTreeNode(resourceModel *resourcediscovery.ResourceModel, gatewayNode *resourcediscovery.GatewayNode) *Node {
+	gateway := gatewayNode.Gateway
+	node := &Node{Title: gatewayTitle(gatewayNode)}
+
+	node.Detail = append(node.Detail,
+		node.Title,
+		fmt.Sprintf("GatewayClass: %v", gateway.Spec.GatewayClassName),
+		fmt.Sprintf("Status: %v", gatewayNode.StatusSummary()),
+	)
+	var addresses []string
+	for _, address := range gateway.Status.Addresses {
+		addresses = append(addresses, address.Value)
+	}
+	if len(addresses) != 0 {
+		node.Detail = append(node.Detail, fmt.Sprintf("Addresses: %v", strings.Join(addresses, ", ")))
+	}
+	node.Detail = append(node.Detail, "Listeners:")
+	for _, listener := range gateway.Spec.Listeners {
+		hostname := "*"
+		if listener.Hostname != nil {
+			hostname = string(*listener.Hostname)
+		}
+		node.Detail = append(node.Detail, fmt.Sprintf("  %v: %v/%d %v", listener.Name, listener.Protocol, listener.Port, hostname))
+	}
+	if len(gateway.Spec.Listeners) == 0 {
+		node.Detail = append(node.Detail, "  <none>")
+	}
+	node.Detail = append(node.Detail, effectivePolicyLines("EffectivePolicies:", gatewayNode.EffectivePolicies)...)
+
+	httpRouteNodes := common.MapToValues(gatewayNode.HTTPRoutes)
+	sort.Slice(httpRouteNodes, func(i, j int) bool {
+		return namespacedName(httpRouteNodes[i].HTTPRoute.GetNamespace(), httpRouteNodes[i].HTTPRoute.GetName()) <
+			namespacedName(httpRouteNodes[j].HTTPRoute.GetNamespace(), httpRouteNodes[j].HTTPRoute.GetName())
+	})
+	for _, httpRouteNode := range httpRouteNodes {
+		node.Children = append(node.Children, httpRouteTreeNode(resourceModel, gatewayNode, httpRouteNode))
+	}
+	for _, defaultBackendRef := range gatewayNode.DefaultBackendRefs {
+		child := backendTreeNode(resourceModel, gatewayNode, defaultBackendRef.BackendRef)
+		child.Title = "Default backend " + defaultBackendRef.String()
+		node.Children = append(node.Children, child)
+	}
+	return node
+}
+
+func httpRouteTreeNode(resourceModel *resourcediscovery.ResourceModel, gatewayNode *resourcediscovery.GatewayNode, httpRouteNode *resourcediscovery.HTTPRouteNode) *Node {
+	httpRoute := httpRouteNode.HTTPRoute
+	node := &Node{Title: "HTTPRoute " + namespacedName(httpRoute.GetNamespace(), httpRoute.GetName())}
+
+	hostnames := []string{"*"}
+	if len(httpRoute.Spec.Hostnames) != 0 {
+		hostnames = nil
+		for _, hostname := range httpRoute.Spec.Hostnames {
+			hostnames = append(hostnames, string(hostname))
+		}
+	}
+	var parents []string
+	for _, gatewayRef := range relations.FindGatewayRefsForHTTPRoute(*httpRoute) {
+		parents = append(parents, "Gateway "+gatewayRef.String())
+	}
+	node.Detail = append(node.Detail,
+		node.Title,
+		fmt.Sprintf("Hostnames: %v", strings.Join(hostnames, ", ")),
+		fmt.Sprintf("Parents: %v", strings.Join(parents, ", ")),
+		fmt.Sprintf("Rules: %d", len(httpRoute.Spec.Rules)),
+		fmt.Sprintf("Status: %v", httpRouteNode.StatusSummary()),
+	)
+	for _, filter := range httpRouteNode.Filters {
+		node.Detail = append(node.Detail, fmt.Sprintf("Filter: %v", filter))
+	}
+	node.Detail = append(node.Detail, effectivePolicyLines(
+		fmt.Sprintf("EffectivePolicies (through %v):", gatewayTitle(gatewayNode)),
+		httpRouteNode.EffectivePolicies[gatewayNode.ID()])...)
+
+	seen := make(map[common.ObjRef]bool)
+	var backendRefs []common.ObjRef
+	for _, backendRef := range relations.FindBackendRefsForHTTPRoute(*httpRoute) {
+		if seen[backendRef] {
+			continue
+		}
+		seen[backendRef] = true
+		backendRefs = append(backendRefs, backendRef)
+	}
+	sort.Slice(backendRefs, func(i, j int) bool {
+		return resourcediscovery.BackendRefString(backendRefs[i]) < resourcediscovery.BackendRefString(backendRefs[j])
+	})
+	for _, backendRef := range backendRefs {
+		node.Children = append(node.Children, backendTreeNode(resourceModel, gatewayNode, backendRef))
+	}
+	return node
+}
+
+func backendTreeNode(resourceModel *resourcediscovery.ResourceModel, gatewayNode *resourcediscovery.GatewayNode, backendRef common.ObjRef) *Node {
+	node := &Node{Title: resourcediscovery.BackendRefString(backendRef)}
+	node.Detail = append(node.Detail, node.Title)
+
+	backendNode, ok := resourceModel.Backends[resourcediscovery.BackendID(backendRef.Group, backendRef.Kind, backendRef.Namespace, backendRef.Name)]
+	if !ok {
+		node.Detail = append(node.Detail, "Status: not found")
+		return node
+	}
+	if backendNode.EndpointsDiscovered {
+		node.Detail = append(node.Detail, fmt.Sprintf("ReadyEndpoints: %d", backendNode.ReadyEndpoints))
+	}
+	for _, servicePort := range backendNode.ServicePorts() {
+		port := fmt.Sprintf("Port: %d", servicePort.Port)
+		if servicePort.Name != "" {
+			port += fmt.Sprintf(" (%v)", servicePort.Name)
+		}
+		node.Detail = append(node.Detail, port)
+	}
+	node.Detail = append(node.Detail, effectivePolicyLines(
+		fmt.Sprintf("EffectivePolicies (through %v):", gatewayTitle(gatewayNode)),
+		backendNode.EffectivePolicies[gatewayNode.ID()])...)
+	return node
+}
+
+// effectivePolicyLines returns the header followed by the effective spec of
+// each policy, sorted by kind. Nothing is returned if there are no policies.
+func effectivePolicyLines(header string, policies map[policymanager.PolicyCrdID]policymanager.Policy) []string {
+	if len(policies) == 0 {
+		return nil
+	}
+	var policyCrdIDs []policymanager.PolicyCrdID
+	for policyCrdID := range policies {
+		policyCrdIDs = append(policyCrdIDs, policyCrdID)
+	}
+	sort.Slice(policyCrdIDs, func(i, j int) bool { return policyCrdIDs[i] < policyCrdIDs[j] })
+
+	result := []string{header}
+	for _, policyCrdID := range policyCrdIDs {
+		result = append(result, fmt.Sprintf("  %v:", policyCrdID))
+		spec, err := yaml.Marshal(policies[policyCrdID])
+		if err != nil {
+			result = append(result, fmt.Sprintf("    <invalid: %v>", err))
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(spec), "\n"), "\n") {
+			result = append(result, "    "+line)
+		}
+	}
+	return result
+}
+
+func gatewayTitle(gatewayNode *resourcediscovery.GatewayNode) string {
+	return "Gateway " + namespacedName(gatewayNode.Gateway.GetNamespace(), gatewayNode.Gateway.GetName())
+}
+
+func namespacedName(namespace, name string) string {
+	return namespace + "/" + name
+}
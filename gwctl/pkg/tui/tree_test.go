@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/common"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/resourcediscovery"
+	"sigs.k8s.io/gateway-api/gwctl/pkg/utils"
+)
+
+func TestBuildTree(t *testing.T) {
+	backendRef := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: common.PtrTo(gatewayv1.PortNumber(80)),
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		common.NamespaceForTest("default"),
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+				Listeners: []gatewayv1.Listener{{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+					Hostname: common.PtrTo(gatewayv1.Hostname("*.foo.com")),
+				}},
+			},
+		},
+		// Gateways without HTTPRoutes are listed as well.
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "foo-gatewayclass",
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-httproute",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "foo-gateway"}},
+				},
+				Hostnames: []gatewayv1.Hostname{"api.foo.com"},
+				Rules: []gatewayv1.HTTPRouteRule{
+					{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("foo-svc")}},
+					{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef("foo-svc"), backendRef("missing-svc")}},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "timeoutpolicies.bar.com",
+				Labels: map[string]string{
+					gatewayv1alpha2.PolicyLabelKey: "direct",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Scope:    apiextensionsv1.NamespaceScoped,
+				Group:    "bar.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: "timeoutpolicies",
+					Kind:   "TimeoutPolicy",
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "bar.com/v1",
+				"kind":       "TimeoutPolicy",
+				"metadata": map[string]interface{}{
+					"name":      "timeout-policy-httproute",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"seconds": int64(30),
+					"targetRef": map[string]interface{}{
+						"group": "gateway.networking.k8s.io",
+						"kind":  "HTTPRoute",
+						"name":  "foo-httproute",
+					},
+				},
+			},
+		},
+	}
+
+	params := utils.MustParamsForTest(t, common.MustClientsForTest(t, objects...))
+	discoverer := resourcediscovery.Discoverer{
+		K8sClients:    params.K8sClients,
+		PolicyManager: params.PolicyManager,
+	}
+	resourceModel, err := discoverer.DiscoverResourcesForTopology(context.Background(), resourcediscovery.Filter{Labels: labels.Everything()})
+	if err != nil {
+		t.Fatalf("Failed to construct resourceModel: %v", err)
+	}
+
+	tree := BuildTree(resourceModel)
+
+	// titles returns the titles of the tree, indented by their depth.
+	var titles func(nodes []*Node, indent string) []string
+	titles = func(nodes []*Node, indent string) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, indent+node.Title)
+			result = append(result, titles(node.Children, indent+"  ")...)
+		}
+		return result
+	}
+	wantTitles := []string{
+		"Gateway default/bar-gateway",
+		"Gateway default/foo-gateway",
+		"  HTTPRoute default/foo-httproute",
+		"    Service default/foo-svc",
+		"    Service default/missing-svc",
+	}
+	if diff := cmp.Diff(wantTitles, titles(tree, "")); diff != "" {
+		t.Errorf("Unexpected diff in titles (-want +got):\n%v", diff)
+	}
+
+	wantGatewayDetail := []string{
+		"Gateway default/foo-gateway",
+		"GatewayClass: foo-gatewayclass",
+		"Status: OK",
+		"Listeners:",
+		"  http: HTTP/80 *.foo.com",
+	}
+	if diff := cmp.Diff(wantGatewayDetail, tree[1].Detail); diff != "" {
+		t.Errorf("Unexpected diff in Detail of Gateway (-want +got):\n%v", diff)
+	}
+
+	wantHTTPRouteDetail := []string{
+		"HTTPRoute default/foo-httproute",
+		"Hostnames: api.foo.com",
+		"Parents: Gateway default/foo-gateway",
+		"Rules: 2",
+		"Status: OK",
+		"EffectivePolicies (through Gateway default/foo-gateway):",
+		"  TimeoutPolicy.bar.com:",
+		"    seconds: 30",
+	}
+	if diff := cmp.Diff(wantHTTPRouteDetail, tree[1].Children[0].Detail); diff != "" {
+		t.Errorf("Unexpected diff in Detail of HTTPRoute (-want +got):\n%v", diff)
+	}
+
+	// The direct policy of the HTTPRoute is part of the effective policies of
+	// its backends.
+	wantBackendDetails := [][]string{
+		{
+			"Service default/foo-svc",
+			"EffectivePolicies (through Gateway default/foo-gateway):",
+			"  TimeoutPolicy.bar.com:",
+			"    seconds: 30",
+		},
+		{
+			"Service default/missing-svc",
+			"Status: not found",
+		},
+	}
+	for i, want := range wantBackendDetails {
+		if diff := cmp.Diff(want, tree[1].Children[0].Children[i].Detail); diff != "" {
+			t.Errorf("Unexpected diff in Detail of backend %d (-want +got):\n%v", i, diff)
+		}
+	}
+}
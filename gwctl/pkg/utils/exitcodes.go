@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+// ExitCode is the code with which a command exits. Commands reporting
+// findings, like analyze, verify-grants, match-test and check-baseline, honor
+// the following contract, so that they can be used as checks in CI.
+type ExitCode int
+
+const (
+	// ExitCodeSuccess is used when the command succeeded and reported no
+	// findings which fail it.
+	ExitCodeSuccess ExitCode = 0
+	// ExitCodeRuntimeError is used when the command failed to run, e.g.
+	// because of invalid flags or an unreachable API server.
+	ExitCodeRuntimeError ExitCode = 1
+	// ExitCodeErrorFindings is used when the command reported findings with
+	// the Error severity.
+	ExitCodeErrorFindings ExitCode = 2
+	// ExitCodeWarningFindings is used when the command reported findings with
+	// the Warning severity, and no Error findings. It is only used in strict
+	// mode, which check-baseline uses unless --warn-only is set; otherwise
+	// warnings do not fail the command.
+	ExitCodeWarningFindings ExitCode = 3
+)
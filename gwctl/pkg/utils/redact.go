@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted fields.
+const RedactedValue = "***REDACTED***"
+
+// DefaultRedactionPatterns cover field names which commonly hold secrets.
+var DefaultRedactionPatterns = []string{
+	"**.password",
+	"**.*secret",
+	"**.*token",
+	"**.*apikey",
+	"**.*privatekey",
+	"**.credentials",
+}
+
+// redactor is used by MarshalWithFormat and Redact to redact all exported
+// content.
+var redactor = MustRedactor(DefaultRedactionPatterns)
+
+// Redact returns content with the values of the fields matching the patterns
+// configured by SetRedactionPatterns replaced by RedactedValue. It is applied
+// by MarshalWithFormat, and must be applied to content which is exported
+// without it.
+func Redact(content any) (any, error) {
+	return redactor.Redact(content)
+}
+
+// SetRedactionPatterns configures the patterns of the fields which are redacted
+// by MarshalWithFormat. See NewRedactor for the syntax of patterns.
+func SetRedactionPatterns(patterns []string) error {
+	r, err := NewRedactor(patterns)
+	if err != nil {
+		return err
+	}
+	redactor = r
+	return nil
+}
+
+// Redactor replaces the values of fields matching any of its patterns with
+// RedactedValue.
+type Redactor struct {
+	patterns [][]string
+}
+
+// NewRedactor returns a Redactor for the given JSON path patterns. A pattern is
+// a dot separated list of segments, e.g. "spec.auth.token", where each segment
+// matches a field name or a list index. Segments are matched case-insensitively
+// and may contain shell wildcards, e.g. "*token". A "*" segment matches exactly
+// one field and a "**" segment matches any number of fields, including none.
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		segments := strings.Split(strings.ToLower(pattern), ".")
+		for _, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("invalid redaction pattern %q: empty segment", pattern)
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid redaction pattern %q: %v", pattern, err)
+			}
+		}
+		r.patterns = append(r.patterns, segments)
+	}
+	return r, nil
+}
+
+// MustRedactor is like NewRedactor but panics if any pattern is invalid.
+func MustRedactor(patterns []string) *Redactor {
+	r, err := NewRedactor(patterns)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Redact returns a copy of content, in its generic JSON representation, with
+// the values of matching fields replaced by RedactedValue. content is returned
+// as is if there is nothing to redact.
+func (r *Redactor) Redact(content any) (any, error) {
+	if r == nil || len(r.patterns) == 0 {
+		return content, nil
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	// Numbers are kept as they are, instead of being converted to float64.
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	redacted, changed := r.redact(generic, nil)
+	if !changed {
+		return content, nil
+	}
+	return redacted, nil
+}
+
+func (r *Redactor) redact(value any, fieldPath []string) (any, bool) {
+	if len(fieldPath) != 0 && r.matches(fieldPath) {
+		return RedactedValue, true
+	}
+
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if redacted, ok := r.redact(child, append(fieldPath, strings.ToLower(key))); ok {
+				v[key] = redacted
+				changed = true
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if redacted, ok := r.redact(child, append(fieldPath, strconv.Itoa(i))); ok {
+				v[i] = redacted
+				changed = true
+			}
+		}
+	}
+	return value, changed
+}
+
+func (r *Redactor) matches(fieldPath []string) bool {
+	for _, pattern := range r.patterns {
+		if matchSegments(pattern, fieldPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments returns true if the pattern segments match all of fieldPath.
+func matchSegments(pattern, fieldPath []string) bool {
+	if len(pattern) == 0 {
+		return len(fieldPath) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(fieldPath); i++ {
+			if matchSegments(pattern[1:], fieldPath[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(fieldPath) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], fieldPath[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], fieldPath[1:])
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMarshalWithFormat_Redaction(t *testing.T) {
+	t.Cleanup(func() {
+		if err := SetRedactionPatterns(DefaultRedactionPatterns); err != nil {
+			t.Fatalf("SetRedactionPatterns() failed: %v", err)
+		}
+	})
+	if err := SetRedactionPatterns([]string{"spec.auth.header", "**.*token"}); err != nil {
+		t.Fatalf("SetRedactionPatterns() failed: %v", err)
+	}
+
+	policy := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "foo.com/v1",
+			"kind":       "AuthPolicy",
+			"metadata": map[string]interface{}{
+				"name":      "auth-policy",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"auth": map[string]interface{}{
+					"header":  "Authorization: Bearer abc",
+					"timeout": int64(30),
+					"backends": []interface{}{
+						map[string]interface{}{"name": "foo", "accessToken": "xyz"},
+					},
+				},
+				"header": "X-Not-Redacted",
+			},
+		},
+	}
+
+	b, err := MarshalWithFormat(policy, OutputFormatJSON)
+	if err != nil {
+		t.Fatalf("MarshalWithFormat() failed: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Failed to unmarshal JSON output: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"apiVersion": "foo.com/v1",
+		"kind":       "AuthPolicy",
+		"metadata": map[string]interface{}{
+			"name":      "auth-policy",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"auth": map[string]interface{}{
+				"header":  RedactedValue,
+				"timeout": float64(30),
+				"backends": []interface{}{
+					map[string]interface{}{"name": "foo", "accessToken": RedactedValue},
+				},
+			},
+			"header": "X-Not-Redacted",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected diff in MarshalWithFormat(); got=%v, want=%v;\ndiff (-want +got)=\n%v", got, want, diff)
+	}
+
+	// The original object must not be modified.
+	if header, _, _ := unstructured.NestedString(policy.Object, "spec", "auth", "header"); header != "Authorization: Bearer abc" {
+		t.Errorf("MarshalWithFormat() modified the original object; spec.auth.header = %q", header)
+	}
+}
+
+func TestNewRedactor_InvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"spec..token", "spec.[token"} {
+		if _, err := NewRedactor([]string{pattern}); err == nil {
+			t.Errorf("NewRedactor(%q) returned no error; want error", pattern)
+		}
+	}
+}
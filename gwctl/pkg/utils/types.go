@@ -22,6 +22,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/yaml"
@@ -54,23 +56,87 @@ const (
 	OutputFormatJSON  OutputFormat = "json"
 	OutputFormatYAML  OutputFormat = "yaml"
 	OutputFormatTable OutputFormat = ""
+	// OutputFormatStatus prints a single line per resource with a rollup of its
+	// status, e.g. "default/foo-gateway OK".
+	OutputFormatStatus OutputFormat = "status"
+	// OutputFormatSARIF prints analyzer findings as a SARIF 2.1.0 log, e.g. for
+	// GitHub code scanning.
+	OutputFormatSARIF OutputFormat = "sarif"
+	// OutputFormatRegoInput prints effective policies as a JSON document shaped
+	// for use as the input of OPA Rego policies.
+	OutputFormatRegoInput OutputFormat = "rego-input"
+	// OutputFormatGoTemplate renders resources with a Go template given as
+	// "go-template=TEMPLATE".
+	OutputFormatGoTemplate OutputFormat = "go-template"
 )
 
-func ValidateAndReturnOutputFormat(format string) (OutputFormat, error) {
+const goTemplatePrefix = string(OutputFormatGoTemplate) + "="
+
+// commandOnlyFormats are the formats which only some commands support.
+var commandOnlyFormats = []OutputFormat{
+	OutputFormatStatus,
+	OutputFormatSARIF,
+	OutputFormatRegoInput,
+	OutputFormatGoTemplate,
+}
+
+// ValidateAndReturnOutputFormat returns the OutputFormat for format. Formats
+// which only some commands support, like sarif, are only accepted if they are
+// listed in commandFormats.
+func ValidateAndReturnOutputFormat(format string, commandFormats ...OutputFormat) (OutputFormat, error) {
+	outputFormat, err := parseOutputFormat(format)
+	if err != nil {
+		return outputFormat, err
+	}
+	if slices.Contains(commandOnlyFormats, outputFormat) && !slices.Contains(commandFormats, outputFormat) {
+		var zero OutputFormat
+		return zero, fmt.Errorf("format %s is not supported by this command", outputFormat)
+	}
+	return outputFormat, nil
+}
+
+func parseOutputFormat(format string) (OutputFormat, error) {
 	switch format {
 	case "json":
 		return OutputFormatJSON, nil
 	case "yaml":
 		return OutputFormatYAML, nil
+	case "status":
+		return OutputFormatStatus, nil
+	case "sarif":
+		return OutputFormatSARIF, nil
+	case "rego-input":
+		return OutputFormatRegoInput, nil
 	case "":
 		return OutputFormatTable, nil
+	case string(OutputFormatGoTemplate):
+		var zero OutputFormat
+		return zero, fmt.Errorf("format %s requires a template, e.g. %s'{{range .Gateways}}{{.Name}}{{end}}'", format, goTemplatePrefix)
 	default:
+		if strings.HasPrefix(format, goTemplatePrefix) {
+			return OutputFormatGoTemplate, nil
+		}
 		var zero OutputFormat
 		return zero, fmt.Errorf("unknown format %s provided", format)
 	}
 }
 
+// TemplateFromOutputFormat returns the template of an output format of the form
+// "go-template=TEMPLATE", or an empty string for other formats.
+func TemplateFromOutputFormat(format string) string {
+	if !strings.HasPrefix(format, goTemplatePrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(format, goTemplatePrefix)
+}
+
+// MarshalWithFormat marshals content to the given format, after redacting the
+// fields configured through SetRedactionPatterns.
 func MarshalWithFormat(content any, format OutputFormat) ([]byte, error) {
+	content, err := redactor.Redact(content)
+	if err != nil {
+		return nil, err
+	}
 	if format == OutputFormatJSON {
 		return json.MarshalIndent(content, "", "  ")
 	}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestValidateAndReturnOutputFormat(t *testing.T) {
+	testcases := []struct {
+		name           string
+		format         string
+		commandFormats []OutputFormat
+		want           OutputFormat
+		wantErr        bool
+	}{
+		{name: "table", format: "", want: OutputFormatTable},
+		{name: "json", format: "json", want: OutputFormatJSON},
+		{name: "sarif is rejected by default", format: "sarif", wantErr: true},
+		{name: "sarif supported by the command", format: "sarif", commandFormats: []OutputFormat{OutputFormatSARIF}, want: OutputFormatSARIF},
+		{name: "rego-input is rejected by default", format: "rego-input", wantErr: true},
+		{name: "rego-input supported by the command", format: "rego-input", commandFormats: []OutputFormat{OutputFormatRegoInput}, want: OutputFormatRegoInput},
+		{name: "go-template is rejected by default", format: "go-template={{.}}", wantErr: true},
+		{name: "go-template supported by the command", format: "go-template={{.}}", commandFormats: []OutputFormat{OutputFormatGoTemplate}, want: OutputFormatGoTemplate},
+		{name: "go-template without template", format: "go-template", commandFormats: []OutputFormat{OutputFormatGoTemplate}, wantErr: true},
+		{name: "status is rejected by default", format: "status", wantErr: true},
+		{name: "unknown format", format: "xml", wantErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ValidateAndReturnOutputFormat(tc.format, tc.commandFormats...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ValidateAndReturnOutputFormat(%q) returned err=%v; wantErr=%v", tc.format, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ValidateAndReturnOutputFormat(%q)=%q; want %q", tc.format, got, tc.want)
+			}
+		})
+	}
+}